@@ -70,7 +70,7 @@ check_service() {
         log_info "Asegúrate de que el servicio esté ejecutándose:"
         log_info "  docker-compose up -d"
         log_info "  o"
-        log_info "  go run cmd/api/main.go"
+        log_info "  go run ./cmd/api"
         exit 1
     fi
 }
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"btc-ltp-service/internal/app"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
+	"btc-ltp-service/internal/infrastructure/leader"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Códigos de salida del binario
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// defaultCommand se ejecuta cuando el binario se invoca sin argumentos
+const defaultCommand = "serve"
+
+// command es un subcomando del binario (serve, exporter, validate-config, warmup, version)
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, stdout io.Writer) error
+}
+
+// commands retorna los subcomandos disponibles en el orden en que se muestran en la ayuda
+func commands() []command {
+	return []command{
+		{name: "serve", summary: "Run the HTTP price API (default)", run: runServe},
+		{name: "exporter", summary: "Run only the Kraken feed and the Prometheus metrics exporter", run: runExporter},
+		{name: "validate-config", summary: "Load and validate the configuration, then exit", run: runValidateConfig},
+		{name: "dump-config", summary: "Print the effective configuration (secrets redacted) without validating it", run: runDumpConfig},
+		{name: "warmup", summary: "Pre-populate the shared cache with the supported pairs, then exit", run: runWarmup},
+		{name: "version", summary: "Print the service version", run: runVersion},
+	}
+}
+
+// runCommand despacha args al subcomando correspondiente y retorna el código de salida.
+// Sin argumentos ejecuta serve, para mantener compatible el entrypoint del contenedor.
+func runCommand(ctx context.Context, args []string, cmds []command, stdout, stderr io.Writer) int {
+	name := defaultCommand
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	switch name {
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout, cmds)
+		return exitOK
+	}
+
+	if len(args) > 1 {
+		fmt.Fprintf(stderr, "%s: unexpected arguments: %v\n\n", name, args[1:])
+		printUsage(stderr, cmds)
+		return exitUsage
+	}
+
+	for _, cmd := range cmds {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(ctx, stdout); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+	printUsage(stderr, cmds)
+	return exitUsage
+}
+
+func printUsage(w io.Writer, cmds []command) {
+	fmt.Fprintln(w, "Usage: btc-ltp-service [command]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range cmds {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// runValidateConfig carga y valida la configuración del entorno actual; útil como
+// gate de CI antes de desplegar
+func runValidateConfig(ctx context.Context, stdout io.Writer) error {
+	cfg, err := loadConfiguration(ctx)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "configuration is valid (environment=%s, cache_backend=%s, supported_pairs=%d)\n",
+		config.GetEnvironment(), cfg.Cache.Backend, len(cfg.Business.SupportedPairs))
+	return nil
+}
+
+// runDumpConfig imprime la configuración efectiva tras aplicar todas las capas, para
+// depurar la precedencia; no valida, así que también sirve con configuraciones inválidas
+func runDumpConfig(_ context.Context, stdout io.Writer) error {
+	loader := config.NewLoader()
+	environment := config.GetEnvironment()
+	cfg, err := loader.LoadForEnvironment(environment)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "# environment: %s\n", environment)
+	for _, source := range loader.Sources() {
+		fmt.Fprintf(stdout, "# source: %s\n", source)
+	}
+	return config.DumpYAML(stdout, cfg)
+}
+
+// runWarmup precarga la caché compartida con los pares soportados vía REST y termina;
+// pensado para init containers. Falla si algún par requerido no pudo precargarse.
+func runWarmup(ctx context.Context, stdout io.Writer) error {
+	cfg, err := loadConfiguration(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	initializeLogging(ctx, cfg.Logging)
+
+	// Una caché en memoria desaparece al terminar el proceso
+	if cache.CacheType(cfg.Cache.Backend) != cache.CacheTypeRedis {
+		return fmt.Errorf("warmup requires a shared cache backend (%s), got %q", cache.CacheTypeRedis, cfg.Cache.Backend)
+	}
+
+	// Sólo REST y sin leader election: el proceso termina tras el warm-up
+	application, err := app.New(ctx, cfg,
+		app.WithExchange(newWarmupExchange(ctx, cfg)),
+		app.WithStateRepository(state.NewMemoryStateRepository()),
+		app.WithLeaderElector(leader.NewStaticElector()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize dependencies: %w", err)
+	}
+
+	result, err := application.Warmer.Warmup(ctx, cfg.Business.SupportedPairs)
+
+	fmt.Fprintf(stdout, "warmed %d/%d pairs in %s\n", len(result.Succeeded), len(cfg.Business.SupportedPairs), result.Duration)
+	failed := make([]string, 0, len(result.Failed))
+	for pair := range result.Failed {
+		failed = append(failed, pair)
+	}
+	sort.Strings(failed)
+	for _, pair := range failed {
+		fmt.Fprintf(stdout, "  failed %s: %s\n", pair, result.Failed[pair])
+	}
+
+	return err
+}
+
+// newWarmupExchange retorna el origen de precios del comando warmup: sólo REST, sin
+// abrir el WebSocket, o el mock en modo desarrollo
+func newWarmupExchange(ctx context.Context, cfg *config.Config) interfaces.Exchange {
+	if cfg.Development.MockMode || cfg.Development.DevMode {
+		return exchange.NewMockExchange()
+	}
+
+	restClient := kraken.NewRestClientWithConfig(cfg.Exchange.Kraken)
+	if cfg.Exchange.Kraken.DynamicPairs {
+		mapper := kraken.NewPairMapper(cfg.Exchange.Kraken.RestURL, cfg.Exchange.Kraken.Timeout)
+		if err := mapper.Load(ctx); err != nil {
+			logging.Warn(ctx, "Failed to load Kraken AssetPairs, using static pair mapping", logging.Fields{
+				"error": err.Error(),
+			})
+		}
+		restClient.SetPairMapper(mapper)
+	}
+	return restClient
+}
+
+// runVersion imprime la versión del servicio
+func runVersion(_ context.Context, stdout io.Writer) error {
+	fmt.Fprintf(stdout, "btc-ltp-service %s\n", AppVersion)
+	return nil
+}
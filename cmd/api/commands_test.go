@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCommand_Dispatch(t *testing.T) {
+	var ran []string
+	cmds := []command{
+		{name: "serve", summary: "serve", run: func(context.Context, io.Writer) error {
+			ran = append(ran, "serve")
+			return nil
+		}},
+		{name: "fail", summary: "always fails", run: func(context.Context, io.Writer) error {
+			return errors.New("boom")
+		}},
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, runCommand(context.Background(), nil, cmds, &stdout, &stderr), "no args runs serve")
+	assert.Equal(t, []string{"serve"}, ran)
+
+	assert.Equal(t, exitFailure, runCommand(context.Background(), []string{"fail"}, cmds, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "fail: boom")
+
+	stderr.Reset()
+	assert.Equal(t, exitUsage, runCommand(context.Background(), []string{"deploy"}, cmds, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "deploy"`)
+
+	assert.Equal(t, exitUsage, runCommand(context.Background(), []string{"serve", "extra"}, cmds, &stdout, &stderr))
+	assert.Len(t, ran, 1, "extra arguments are rejected before running")
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, runCommand(context.Background(), []string{"--help"}, cmds, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "always fails")
+}
+
+func TestRunVersion(t *testing.T) {
+	var stdout bytes.Buffer
+	assert.NoError(t, runVersion(context.Background(), &stdout))
+	assert.Equal(t, "btc-ltp-service "+AppVersion+"\n", stdout.String())
+}
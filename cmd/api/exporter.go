@@ -0,0 +1,75 @@
+package main
+
+import (
+	"btc-ltp-service/internal/app"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange"
+	"btc-ltp-service/internal/infrastructure/leader"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"btc-ltp-service/internal/infrastructure/web/handlers"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/gorilla/mux"
+)
+
+// runExporter ejecuta sólo el feed de Kraken y expone las métricas Prometheus
+// (btc_ltp_current_prices incluido), sin la API HTTP de precios. Cada réplica
+// exporta su propio feed, por lo que no usa leader election ni caché compartida.
+func runExporter(ctx context.Context, _ io.Writer) error {
+	cfg, err := loadConfiguration(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	initializeLogging(ctx, cfg.Logging)
+	app.ApplyRuntimeLimits(ctx, cfg.Runtime)
+
+	logging.Info(ctx, "Starting BTC LTP price exporter", logging.Fields{
+		"version":     AppVersion,
+		"environment": config.GetEnvironment(),
+		"port":        cfg.Server.Port,
+	})
+
+	// Cada réplica mantiene su propio feed: caché en memoria y sin leader election
+	application, err := app.New(ctx, cfg,
+		app.WithCache(cache.NewMemoryCache()),
+		app.WithStateRepository(state.NewMemoryStateRepository()),
+		app.WithLeaderElector(leader.NewStaticElector()),
+		app.WithHandlerFactory(exporterHandler),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize dependencies: %w", err)
+	}
+
+	// Los ticks del WebSocket actualizan el gauge en tiempo real; el refresco
+	// periódico lo mantiene al día cuando el feed cae a REST
+	if fallbackExchange, ok := application.Exchange.(*exchange.FallbackExchange); ok {
+		fallbackExchange.OnPrice(func(price *entities.Price) {
+			metrics.UpdateCurrentPrice(price.Pair, price.Amount)
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return application.Run(ctx)
+}
+
+// exporterHandler expone sólo métricas y health checks
+func exporterHandler(a *app.Application) http.Handler {
+	healthHandler := handlers.NewHealthHandler(a.PriceService)
+	healthHandler.AddReadinessCheck("warmup", a.Warmer.Ready)
+
+	mainRouter := mux.NewRouter()
+	mainRouter.Handle("/metrics", metrics.Handler()).Methods("GET")
+	mainRouter.HandleFunc("/health", healthHandler.Health).Methods("GET")
+	mainRouter.HandleFunc("/ready", healthHandler.Ready).Methods("GET")
+	return mainRouter
+}
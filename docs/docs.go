@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
-            "get": {
-                "description": "Verifies that the service is running correctly. Responds quickly without checking external dependencies.",
+        "/admin/cache": {
+            "delete": {
+                "description": "Purges cached prices for the given pairs, or every cached price with all=true, without restarting the service. Exactly one of pair or all must be provided.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,73 +25,224 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
+                ],
+                "summary": "Invalidate cached prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trading pairs separated by commas to invalidate (e.g., BTC/USD,ETH/USD)",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Flush every cached price",
+                        "name": "all",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Basic health check",
                 "responses": {
                     "200": {
-                        "description": "Service is running correctly",
+                        "description": "Cached prices invalidated",
                         "schema": {
-                            "$ref": "#/definitions/dto.HealthResponse"
+                            "$ref": "#/definitions/dto.CacheInvalidationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to invalidate cache",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/ltp": {
+        "/admin/config": {
             "get": {
-                "description": "Get the latest traded prices for specified cryptocurrency pairs. If no pairs are specified, returns all supported pairs.",
-                "consumes": [
+                "description": "Effective runtime configuration with secrets redacted, plus where every value came from (default, config file or environment variable) to debug environment drift.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Effective configuration",
+                "responses": {
+                    "200": {
+                        "description": "Effective configuration",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminConfigResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to encode configuration",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Configuration viewer is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drain": {
+            "post": {
+                "description": "Marks the instance not ready (/ready answers 503), rejects new SSE and WebSocket subscriptions, tells the open ones to reconnect elsewhere and stops keeping connections alive. Meant to be called from a Kubernetes preStop hook before SIGTERM; regular requests are still served. Calling it again only reports the streams still open.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Start draining before shutdown",
+                "responses": {
+                    "200": {
+                        "description": "Instance draining",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DrainResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Draining is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events": {
+            "get": {
+                "description": "Lists the REST fallback activations and WebSocket reconnects persisted in the state repository by every replica, newest first, for postmortems that outlive log retention. Events of other replicas are included once they flush (audit.flush_interval).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
+                    "admin"
                 ],
-                "summary": "Get Last Traded Prices",
+                "summary": "Fallback and reconnect audit trail",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Trading pairs separated by commas (e.g., BTC/USD,ETH/USD). If empty, returns all supported pairs",
+                        "description": "Only this event type",
+                        "name": "type",
+                        "in": "query",
+                        "enum": [
+                            "fallback",
+                            "reconnect"
+                        ]
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this reason (e.g., timeout, connection_closed, forced)",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events that affected this pair (e.g., BTC/USD)",
                         "name": "pair",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Events at or after this time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Events at or before this time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum events returned, newest first (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Prices retrieved successfully",
+                        "description": "Matching events",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetLTPResponse"
+                            "$ref": "#/definitions/dto.AuditEventsResponse"
                         }
                     },
-                    "206": {
-                        "description": "Partial success - some pairs failed",
+                    "400": {
+                        "description": "Invalid filter",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetLTPResponse"
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid parameters",
+                    "401": {
+                        "description": "API key missing or invalid",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Pairs not found",
+                    "429": {
+                        "description": "Rate limit exceeded",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to read events",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Service unavailable",
+                    "501": {
+                        "description": "Audit trail is not enabled",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -99,28 +250,119 @@ const docTemplate = `{
                 }
             }
         },
-        "/ltp/cached": {
+        "/admin/exchange/reconnect": {
+            "post": {
+                "description": "Closes and re-establishes the exchange streaming connection. Requests fall back to REST while reconnecting.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Force exchange reconnection",
+                "responses": {
+                    "200": {
+                        "description": "Exchange reconnected",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReconnectResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Exchange does not support reconnection",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Reconnection failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReconnectResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/feed-quality": {
             "get": {
-                "description": "Get all prices currently stored in cache. Useful for debugging and monitoring cache state.",
-                "consumes": [
+                "description": "Per supported pair, rolling in-memory statistics of the WebSocket feed: received ticks per minute, drop rate, average interval between ticks, last REST fallback and last measured divergence vs REST.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Price feed quality",
+                "responses": {
+                    "200": {
+                        "description": "Feed quality per pair",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminFeedQualityResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Feed quality is not available for the exchange",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/flags": {
+            "get": {
+                "description": "Lists the declared feature flags with their default and effective state.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
+                    "admin"
                 ],
-                "summary": "Get cached prices",
+                "summary": "List feature flags",
                 "responses": {
                     "200": {
-                        "description": "Cached prices retrieved successfully",
+                        "description": "Declared feature flags",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetLTPResponse"
+                            "$ref": "#/definitions/dto.FeatureFlagsResponse"
                         }
                     },
-                    "500": {
-                        "description": "Error retrieving prices from cache",
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Feature flags are not configured",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -128,9 +370,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/ltp/refresh": {
-            "post": {
-                "description": "Manually refresh cached prices for specified pairs. Administrative endpoint to force data update.",
+        "/admin/flags/{name}": {
+            "put": {
+                "description": "Overrides a declared feature flag at runtime. The override is stored in the state repository and picked up by every replica within feature_flags.refresh_interval.",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,93 +380,1706 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
+                    "admin"
                 ],
-                "summary": "Refresh cached prices",
+                "summary": "Override a feature flag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Trading pairs separated by commas to refresh (e.g., BTC/USD,ETH/USD)",
-                        "name": "pairs",
-                        "in": "query",
+                        "description": "Flag name",
+                        "name": "name",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New flag state",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FeatureFlagOverrideRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Prices refreshed successfully",
+                        "description": "Updated feature flags",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/dto.FeatureFlagsResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid or missing parameters",
+                        "description": "Invalid body",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to refresh prices",
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown feature flag",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Runtime overrides are disabled",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/ready": {
-            "get": {
-                "description": "Verifies that the service is ready to receive traffic, including validation of dependencies like cache and external services.",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Removes the runtime override of a feature flag, restoring its configured default.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
+                ],
+                "summary": "Clear a feature flag override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Flag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Complete readiness check",
                 "responses": {
                     "200": {
-                        "description": "Service is ready to receive traffic",
+                        "description": "Updated feature flags",
                         "schema": {
-                            "$ref": "#/definitions/dto.HealthResponse"
+                            "$ref": "#/definitions/dto.FeatureFlagsResponse"
                         }
                     },
-                    "503": {
-                        "description": "Service is not ready - dependencies are failing",
+                    "401": {
+                        "description": "API key missing or invalid",
                         "schema": {
-                            "$ref": "#/definitions/dto.HealthResponse"
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "dto.ErrorResponse": {
-            "description": "Standard error response for endpoints",
+                    },
+                    "404": {
+                        "description": "Unknown feature flag",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Runtime overrides are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/pairs": {
+            "get": {
+                "description": "Per supported pair, the state of the WebSocket ticker subscription. Pairs the exchange rejects are retried with exponential backoff; after repeated errors they are excluded and served from REST until the exchange is reconnected (POST /admin/exchange/reconnect).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "WebSocket subscription state per pair",
+                "responses": {
+                    "200": {
+                        "description": "Subscription state per pair",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminPairsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Subscription state is not available for the exchange",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/startup-report": {
+            "get": {
+                "description": "How the service started: duration of each component's initialization and start, cache warm-up results and configuration warnings, to diagnose slow starts. ready_at is omitted while the service is still starting.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Startup report",
+                "responses": {
+                    "200": {
+                        "description": "Startup report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminStartupReportResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Startup report is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/status": {
+            "get": {
+                "description": "Exchange connection state, cached prices summary and the latest REST fallbacks. Used by the admin dashboard at /admin/.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Service status",
+                "responses": {
+                    "200": {
+                        "description": "Service status",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read cached prices",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Status is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants": {
+            "get": {
+                "description": "Lists the tenants with their allowed pairs and rate limit. API keys are never returned, only how many each tenant has.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List tenants",
+                "responses": {
+                    "200": {
+                        "description": "Tenants",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TenantsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Tenant API keys cannot use admin endpoints",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Multi-tenancy is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{name}": {
+            "put": {
+                "description": "Creates or replaces a runtime-managed tenant. It is stored in the state repository (API keys hashed) and picked up by every replica within tenancy.refresh_interval. Tenants declared in configuration cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create or replace a tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant definition",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated tenants",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TenantsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Tenant API keys cannot use admin endpoints",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tenant is read-only or an API key is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Runtime tenant management is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a runtime-managed tenant; its API keys stop working on every replica within tenancy.refresh_interval.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated tenants",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TenantsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Tenant API keys cannot use admin endpoints",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown tenant",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tenant is declared in configuration",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Runtime tenant management is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/usage": {
+            "get": {
+                "description": "Reports requests and streamed price events per API key and UTC day, aggregated across replicas, for billing and chargeback. Keys are identified by the first 12 hex digits of their SHA-256. Counters of other replicas are included once they flush (usage.flush_interval).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "API usage per key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First UTC day, YYYY-MM-DD (default: to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last UTC day, YYYY-MM-DD (default: today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only report this tenant",
+                        "name": "tenant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage per key and day",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Tenant API keys cannot use admin endpoints",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read usage",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Usage accounting is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Verifies that the service is running correctly. Responds quickly without checking external dependencies.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Basic health check",
+                "responses": {
+                    "200": {
+                        "description": "Service is running correctly",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ltp": {
+            "get": {
+                "description": "Get the latest traded prices for specified cryptocurrency pairs. If no pairs are specified (or pair=*), returns all supported pairs currently in cache without fetching from the exchange.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get Last Traded Prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trading pairs separated by commas (e.g., BTC/USD,ETH/USD). Use * or leave empty for all cached supported pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of pairs to return (1-1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pairs to skip after filtering",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return pairs quoted in this currency (e.g., USD)",
+                        "name": "quote",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve amounts without the per-quote rounding rules (business.price_format)",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prices retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial success - some pairs failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pairs not found, or every requested pair failed with UNSUPPORTED_PAIR",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded, or every requested pair failed with UPSTREAM_RATE_LIMITED",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable - every requested pair failed (UPSTREAM_UNAVAILABLE, STALE_DATA, PRICE_DEVIATION, PRICE_FETCH_ERROR)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Get the latest traded prices for a list of pairs sent in the JSON body. Use it when the pair list does not fit in a query string; the number of pairs is limited by server.max_bulk_pairs. The optional max_age sets the maximum accepted price age per pair; only the pairs older than their max_age are refreshed from the exchange before responding.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get Last Traded Prices (bulk)",
+                "parameters": [
+                    {
+                        "description": "Pairs to query",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostLTPRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve amounts without the per-quote rounding rules (business.price_format)",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prices retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial success - some pairs failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body, unsupported pairs, invalid max_age or too many pairs",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded, or every requested pair failed with UPSTREAM_RATE_LIMITED",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable - every requested pair failed (UPSTREAM_UNAVAILABLE, STALE_DATA, PRICE_DEVIATION, PRICE_FETCH_ERROR)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pairs not found, or every requested pair failed with UNSUPPORTED_PAIR",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ltp/cached": {
+            "get": {
+                "description": "Get all prices currently stored in cache. Useful for debugging and monitoring cache state.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get cached prices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of pairs to return (1-1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pairs to skip after filtering",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return pairs quoted in this currency (e.g., USD)",
+                        "name": "quote",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve amounts without the per-quote rounding rules (business.price_format)",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cached prices retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLTPResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving prices from cache",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ltp/refresh": {
+            "post": {
+                "description": "Manually refresh cached prices for specified pairs. Administrative endpoint to force data update.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Refresh cached prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trading pairs separated by commas to refresh (e.g., BTC/USD,ETH/USD)",
+                        "name": "pairs",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prices refreshed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to refresh prices",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ltp/stream": {
+            "get": {
+                "description": "Server-Sent Events stream of cached prices: every cached price first, then each price that changes, as \"price\" events with a PriceData payload. The stream ends after 30 minutes, or on server shutdown after a randomized retry hint; EventSource clients reconnect automatically. New streams are refused with 503 while the server drains.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "ltp"
+                ],
+                "summary": "Stream prices (SSE)",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Serve amounts without the per-quote rounding rules (business.price_format)",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "price events",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PriceData"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid raw parameter",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Streaming not supported or cache unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Server shutting down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pairs": {
+            "get": {
+                "description": "Lists the supported pairs (for a tenant API key, the pairs allowed for the tenant) with their metadata: base and quote assets, price and volume decimals (when the exchange reports them), source exchange, whether the WebSocket ticker subscription is confirmed and when the cached price was last updated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pairs"
+                ],
+                "summary": "Supported pairs",
+                "responses": {
+                    "200": {
+                        "description": "Supported pairs",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PairsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read cached prices",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "Verifies that the service is ready to receive traffic, including validation of dependencies like cache and external services.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Complete readiness check",
+                "responses": {
+                    "200": {
+                        "description": "Service is ready to receive traffic",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is not ready - dependencies are failing",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "WebSocket feed of cached prices. The first message is a \"snapshot\" with every subscribed pair in cache; then, every second, a \"diff\" with only the pairs that changed. Snapshots and diffs carry a per-connection \"seq\" that grows by one; on a gap the client sends {\"type\":\"resync\"} and gets a fresh snapshot. Unknown client messages get an \"error\" message. The connection is closed with \"going away\" on server shutdown and after 30 minutes; new connections are refused with 503 while the server drains.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ltp"
+                ],
+                "summary": "Price feed (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated pairs to subscribe to (default: every supported pair)",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve amounts without the per-quote rounding rules (business.price_format)",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "snapshot, diff and error messages",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StreamMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pair, raw parameter or WebSocket handshake",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "API key missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read cached prices",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Server shutting down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apierror.Problem": {
+            "description": "RFC 7807 problem document, returned instead of dto.ErrorResponse when the client sends Accept: application/problem+json",
+            "type": "object",
+            "required": [
+                "type",
+                "title",
+                "status",
+                "code"
+            ],
+            "properties": {
+                "type": {
+                    "description": "Problem type URI (server.problem_type_base_uri + code in kebab case)",
+                    "type": "string",
+                    "example": "/problems/rate-limit-exceeded"
+                },
+                "title": {
+                    "description": "HTTP status text",
+                    "type": "string",
+                    "example": "Too Many Requests"
+                },
+                "status": {
+                    "description": "HTTP status code",
+                    "type": "integer",
+                    "example": 429
+                },
+                "detail": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "Rate limit exceeded. Please slow down your requests."
+                },
+                "instance": {
+                    "description": "Request path",
+                    "type": "string",
+                    "example": "/api/v1/ltp"
+                },
+                "code": {
+                    "description": "Machine-readable error code",
+                    "type": "string",
+                    "example": "RATE_LIMIT_EXCEEDED"
+                },
+                "request_id": {
+                    "description": "X-Request-ID of the failed request",
+                    "type": "string",
+                    "example": "req_1704067200123456_a1b2c3d4"
+                },
+                "details": {
+                    "description": "Error-specific context",
+                    "type": "object"
+                }
+            }
+        },
+        "dto.AdminConfigResponse": {
+            "description": "Effective runtime configuration with secrets redacted and the origin of each value",
+            "type": "object",
+            "properties": {
+                "environment": {
+                    "description": "Environment overlay applied (config.{env}.yaml)",
+                    "type": "string",
+                    "example": "production"
+                },
+                "files": {
+                    "description": "Config files applied, lowest precedence first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "configs/config.yaml"
+                    ]
+                },
+                "config": {
+                    "description": "Effective configuration, same keys as config.yaml",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "sources": {
+                    "description": "Origin of every configuration key",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.ConfigSourceData"
+                    }
+                }
+            }
+        },
+        "dto.AdminFeedQualityResponse": {
+            "description": "Rolling per-pair feed statistics: tick rate, drops, intervals, fallbacks and divergence vs REST",
+            "type": "object",
+            "properties": {
+                "pairs": {
+                    "description": "Supported pairs, in configuration order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FeedQualityData"
+                    }
+                },
+                "timestamp": {
+                    "description": "When the report was computed",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                },
+                "window_seconds": {
+                    "description": "Rolling window the tick statistics cover",
+                    "type": "integer",
+                    "example": 300
+                }
+            }
+        },
+        "dto.AdminPairsResponse": {
+            "description": "WebSocket ticker subscription state of the supported pairs, with the pairs excluded after repeated subscription errors",
+            "type": "object",
+            "properties": {
+                "excluded": {
+                    "description": "Pairs served only from REST after repeated subscription errors",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "FOO/USD"
+                    ]
+                },
+                "pairs": {
+                    "description": "Supported pairs, in configuration order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PairSubscriptionData"
+                    }
+                },
+                "timestamp": {
+                    "description": "When the report was computed",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                }
+            }
+        },
+        "dto.AdminStartupReportResponse": {
+            "description": "Startup steps, cache warm-up and configuration warnings",
+            "type": "object",
+            "properties": {
+                "started_at": {
+                    "description": "When initialization began",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                },
+                "ready_at": {
+                    "description": "When every component had started, omitted while starting",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:02Z"
+                },
+                "duration_ms": {
+                    "description": "Time from started_at to ready_at",
+                    "type": "integer",
+                    "example": 2150
+                },
+                "steps": {
+                    "description": "Initialization and start steps, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.StartupStepData"
+                    }
+                },
+                "warmup": {
+                    "description": "Cache warm-up, omitted when it did not run",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.StartupWarmupData"
+                        }
+                    ]
+                },
+                "config_warnings": {
+                    "description": "Valid but probably unintended settings",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "auth is disabled: /api/v1/admin endpoints accept unauthenticated requests"
+                    ]
+                }
+            }
+        },
+        "dto.AdminStatusResponse": {
+            "description": "Exchange connection, cache contents and recent REST fallbacks",
+            "type": "object",
+            "properties": {
+                "timestamp": {
+                    "description": "When the status was taken",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                },
+                "exchange": {
+                    "description": "Upstream connection",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ExchangeStatusData"
+                        }
+                    ]
+                },
+                "cache": {
+                    "description": "Cached prices",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.CacheStatsData"
+                        }
+                    ]
+                },
+                "recent_fallbacks": {
+                    "description": "Latest REST fallbacks, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FallbackEventData"
+                    }
+                }
+            }
+        },
+        "dto.AuditEventData": {
+            "description": "REST fallback activation or WebSocket reconnect",
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "description": "Reconnection attempt (reconnects)",
+                    "type": "integer",
+                    "example": 1
+                },
+                "duration_ms": {
+                    "description": "REST query for fallbacks; time until reconnected (or failed) for reconnects",
+                    "type": "integer",
+                    "example": 120
+                },
+                "error": {
+                    "description": "Why it failed",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Event identifier",
+                    "type": "string",
+                    "example": "20231201T103000.000000000Z/api-1/42"
+                },
+                "instance": {
+                    "description": "Replica that recorded the event",
+                    "type": "string",
+                    "example": "api-1"
+                },
+                "pairs": {
+                    "description": "Affected pairs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD"
+                    ]
+                },
+                "reason": {
+                    "description": "Why the fallback was activated or the connection was lost",
+                    "type": "string",
+                    "example": "timeout"
+                },
+                "request_id": {
+                    "description": "Request that triggered the fallback",
+                    "type": "string",
+                    "example": "req_1704067200123456_a1b2c3d4"
+                },
+                "shard": {
+                    "description": "WebSocket connection (reconnects)",
+                    "type": "string",
+                    "example": "0"
+                },
+                "success": {
+                    "description": "Whether REST answered or the connection was restored",
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "description": "When the fallback started or the connection was lost",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                },
+                "type": {
+                    "description": "fallback or reconnect",
+                    "type": "string",
+                    "enum": [
+                        "fallback",
+                        "reconnect"
+                    ],
+                    "example": "fallback"
+                }
+            }
+        },
+        "dto.AuditEventsResponse": {
+            "description": "Audit trail of REST fallback activations and WebSocket reconnects, newest first",
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Matching events, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditEventData"
+                    }
+                },
+                "retention_days": {
+                    "description": "Days of events kept",
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "dto.CacheInvalidationResponse": {
+            "description": "Result of an administrative cache invalidation",
+            "type": "object",
+            "required": [
+                "scope"
+            ],
+            "properties": {
+                "message": {
+                    "description": "Human readable summary",
+                    "type": "string",
+                    "example": "Cached prices invalidated"
+                },
+                "pairs": {
+                    "description": "Invalidated pairs (scope \"pairs\")",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD",
+                        "ETH/USD"
+                    ]
+                },
+                "removed": {
+                    "description": "Removed cache entries (scope \"all\")",
+                    "type": "integer",
+                    "example": 3
+                },
+                "scope": {
+                    "description": "What was invalidated",
+                    "type": "string",
+                    "enum": [
+                        "pairs",
+                        "all"
+                    ],
+                    "example": "pairs"
+                }
+            }
+        },
+        "dto.CacheStatsData": {
+            "description": "Cached prices summary",
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Cached prices",
+                    "type": "integer",
+                    "example": 3
+                },
+                "supported_pairs": {
+                    "description": "Configured supported pairs",
+                    "type": "integer",
+                    "example": 4
+                },
+                "missing_pairs": {
+                    "description": "Supported pairs without a cached price",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ETH/EUR"
+                    ]
+                },
+                "oldest_age_seconds": {
+                    "description": "Age of the oldest cached price",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "newest_age_seconds": {
+                    "description": "Age of the newest cached price",
+                    "type": "number",
+                    "example": 0.3
+                }
+            }
+        },
+        "dto.ConfigSourceData": {
+            "description": "Origin of a configuration value",
+            "type": "object",
+            "properties": {
+                "source": {
+                    "description": "default, file or env",
+                    "type": "string",
+                    "enum": [
+                        "default",
+                        "file",
+                        "env"
+                    ],
+                    "example": "env"
+                },
+                "from": {
+                    "description": "File path or environment variable",
+                    "type": "string",
+                    "example": "PORT"
+                }
+            }
+        },
+        "dto.ConnectionStatusData": {
+            "description": "Streaming connection (shard) state",
+            "type": "object",
+            "properties": {
+                "confirmed": {
+                    "description": "Subscriptions confirmed by the exchange",
+                    "type": "integer",
+                    "example": 100
+                },
+                "connected": {
+                    "description": "Whether the connection is up",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "description": "Shard identifier",
+                    "type": "string",
+                    "example": "0"
+                },
+                "pairs": {
+                    "description": "Pairs assigned to the connection",
+                    "type": "integer",
+                    "example": 100
+                },
+                "pending": {
+                    "description": "Subscriptions sent and not yet confirmed",
+                    "type": "integer",
+                    "example": 0
+                },
+                "reconnect_attempts": {
+                    "description": "Reconnection attempts since the connection was lost",
+                    "type": "integer",
+                    "example": 0
+                },
+                "reconnecting": {
+                    "description": "Whether a reconnection is scheduled",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "dto.DrainResponse": {
+            "description": "Draining state after POST /admin/drain",
+            "type": "object",
+            "properties": {
+                "draining": {
+                    "description": "Always true once the endpoint was called",
+                    "type": "boolean",
+                    "example": true
+                },
+                "open_streams": {
+                    "description": "SSE and WebSocket streams still open",
+                    "type": "integer",
+                    "example": 3
+                },
+                "message": {
+                    "description": "Human readable summary",
+                    "type": "string",
+                    "example": "Instance draining, not ready for new traffic"
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "description": "Standard error response for every 4xx/5xx API response (see apierror.Problem for application/problem+json)",
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code",
+                    "type": "string",
+                    "example": "INVALID_PARAMETER"
+                },
+                "message": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "The provided trading pair is not supported"
+                },
+                "details": {
+                    "description": "Error-specific context (parameter, limits, ...)",
+                    "type": "object"
+                },
+                "request_id": {
+                    "description": "X-Request-ID of the failed request",
+                    "type": "string",
+                    "example": "req_1704067200123456_a1b2c3d4"
+                },
+                "error": {
+                    "description": "Deprecated: same as code",
+                    "type": "string",
+                    "example": "INVALID_PARAMETER"
+                }
+            }
+        },
+        "dto.ExchangeData": {
+            "type": "object",
+            "properties": {
+                "ask": {
+                    "description": "Best ask in the ticker, when known",
+                    "type": "number",
+                    "example": 45123.5
+                },
+                "bid": {
+                    "description": "Best bid in the ticker, when known",
+                    "type": "number",
+                    "example": 45123.4
+                },
+                "channel": {
+                    "description": "WebSocket channel or REST endpoint",
+                    "type": "string",
+                    "example": "ticker"
+                },
+                "name": {
+                    "description": "Exchange that reported the price",
+                    "type": "string",
+                    "example": "kraken"
+                },
+                "source": {
+                    "description": "websocket, rest or mock",
+                    "type": "string",
+                    "example": "websocket"
+                }
+            }
+        },
+        "dto.ExchangeStatusData": {
+            "description": "Upstream exchange connection state",
+            "type": "object",
+            "properties": {
+                "streaming": {
+                    "description": "Whether the exchange keeps a streaming (WebSocket) connection",
+                    "type": "boolean",
+                    "example": true
+                },
+                "connected": {
+                    "description": "Whether every streaming connection is up",
+                    "type": "boolean",
+                    "example": true
+                },
+                "connections": {
+                    "description": "Streaming connections the subscriptions are sharded across",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ConnectionStatusData"
+                    }
+                }
+            }
+        },
+        "dto.FallbackEventData": {
+            "description": "REST fallback activation",
+            "type": "object",
+            "properties": {
+                "time": {
+                    "description": "When the fallback started",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                },
+                "pairs": {
+                    "description": "Pairs requested via REST",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD"
+                    ]
+                },
+                "reason": {
+                    "description": "Why the WebSocket was not used",
+                    "type": "string",
+                    "example": "timeout"
+                },
+                "success": {
+                    "description": "Whether REST returned the prices",
+                    "type": "boolean",
+                    "example": true
+                },
+                "duration_ms": {
+                    "description": "REST request duration",
+                    "type": "integer",
+                    "example": 180
+                },
+                "request_id": {
+                    "description": "request_id of the logs of the request or refresh that triggered it",
+                    "type": "string",
+                    "example": "req_1704067200123456_a1b2c3d4"
+                }
+            }
+        },
+        "dto.FeatureFlagData": {
+            "description": "Feature flag state",
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "State declared in configuration",
+                    "type": "boolean",
+                    "example": false
+                },
+                "enabled": {
+                    "description": "Effective state",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "Flag name",
+                    "type": "string",
+                    "example": "hedged_requests"
+                },
+                "overridden": {
+                    "description": "Whether a runtime override is active",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.FeatureFlagOverrideRequest": {
+            "description": "Feature flag override",
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "New state for the flag",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.FeatureFlagsResponse": {
+            "description": "Declared feature flags and their effective state",
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "description": "Flags sorted by name",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FeatureFlagData"
+                    }
+                },
+                "overrides_enabled": {
+                    "description": "Whether flags can be overridden at runtime",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.FeedQualityData": {
+            "description": "WebSocket feed quality of a pair",
             "type": "object",
-            "required": [
-                "error"
-            ],
             "properties": {
-                "code": {
-                    "description": "HTTP error code or internal code",
+                "avg_tick_interval_ms": {
+                    "description": "Average time between received ticks in the window",
+                    "type": "integer",
+                    "example": 1410
+                },
+                "divergence_at": {
+                    "description": "When the divergence was measured",
                     "type": "string",
-                    "example": "400"
+                    "example": "2023-12-01T10:25:00Z"
                 },
-                "error": {
-                    "description": "Main error message",
+                "divergence_percent": {
+                    "description": "Last measured WebSocket vs REST divergence, omitted if never compared",
+                    "type": "number",
+                    "example": 0.08
+                },
+                "drop_rate": {
+                    "description": "Dropped ticks over received ticks in the window (0-1)",
+                    "type": "number",
+                    "example": 0.02
+                },
+                "last_fallback_at": {
+                    "description": "Last REST fallback for the pair, omitted if none",
                     "type": "string",
-                    "example": "INVALID_PARAMETER"
+                    "example": "2023-12-01T10:12:00Z"
                 },
-                "message": {
-                    "description": "Detailed error description",
+                "last_tick_at": {
+                    "description": "Last received tick, omitted if none was received",
                     "type": "string",
-                    "example": "The provided trading pair is not supported"
+                    "example": "2023-12-01T10:29:59Z"
+                },
+                "pair": {
+                    "description": "Pair in BASE/QUOTE format",
+                    "type": "string",
+                    "example": "BTC/USD"
+                },
+                "ticks_per_minute": {
+                    "description": "WebSocket ticks received per minute in the window",
+                    "type": "number",
+                    "example": 42.5
                 }
             }
         },
@@ -248,6 +2103,14 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/dto.PriceData"
                     }
+                },
+                "pagination": {
+                    "description": "Present when limit, offset or quote are used",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.Pagination"
+                        }
+                    ]
                 }
             }
         },
@@ -287,6 +2150,189 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.Pagination": {
+            "description": "Pagination metadata for multi-pair responses",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Page size (omitted when unlimited)",
+                    "type": "integer",
+                    "example": 50
+                },
+                "next_offset": {
+                    "description": "Offset of the next page, if any",
+                    "type": "integer",
+                    "example": 50
+                },
+                "offset": {
+                    "description": "Pairs skipped",
+                    "type": "integer",
+                    "example": 0
+                },
+                "quote": {
+                    "description": "Quote currency filter applied",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "total": {
+                    "description": "Pairs matching the filter",
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "dto.PairData": {
+            "description": "Supported pair metadata",
+            "type": "object",
+            "properties": {
+                "pair": {
+                    "description": "Pair in BASE/QUOTE format",
+                    "type": "string",
+                    "example": "BTC/USD"
+                },
+                "base": {
+                    "description": "Base asset",
+                    "type": "string",
+                    "example": "BTC"
+                },
+                "quote": {
+                    "description": "Quote asset",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "precision": {
+                    "description": "Decimals reported by the exchange, omitted when unknown",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PairPrecisionData"
+                        }
+                    ]
+                },
+                "exchange": {
+                    "description": "Source exchange",
+                    "type": "string",
+                    "example": "kraken"
+                },
+                "status": {
+                    "description": "Pair status on the exchange, omitted when unknown",
+                    "type": "string",
+                    "example": "online"
+                },
+                "ws_subscribed": {
+                    "description": "Whether the WebSocket ticker subscription is confirmed",
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_update": {
+                    "description": "Timestamp of the cached price, omitted when not cached",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                }
+            }
+        },
+        "dto.PairPrecisionData": {
+            "description": "Price and volume decimals",
+            "type": "object",
+            "properties": {
+                "price": {
+                    "description": "Price decimals",
+                    "type": "integer",
+                    "example": 1
+                },
+                "volume": {
+                    "description": "Volume (lot) decimals",
+                    "type": "integer",
+                    "example": 8
+                }
+            }
+        },
+        "dto.PairSubscriptionData": {
+            "description": "WebSocket ticker subscription of a pair",
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "description": "Subscription errors since the last confirmation",
+                    "type": "integer",
+                    "example": 2
+                },
+                "last_error": {
+                    "description": "Last error reported by the exchange",
+                    "type": "string",
+                    "example": "Currency pair not supported"
+                },
+                "last_failure_at": {
+                    "description": "When the last error was reported",
+                    "type": "string",
+                    "example": "2023-12-01T10:29:50Z"
+                },
+                "next_retry_at": {
+                    "description": "Next subscription attempt, only while retrying",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:10Z"
+                },
+                "pair": {
+                    "description": "Pair in BASE/QUOTE format",
+                    "type": "string",
+                    "example": "BTC/USD"
+                },
+                "status": {
+                    "description": "Subscription state",
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "pending",
+                        "retrying",
+                        "excluded",
+                        "unsubscribed"
+                    ],
+                    "example": "retrying"
+                }
+            }
+        },
+        "dto.PairsResponse": {
+            "description": "Supported pairs with exchange metadata",
+            "type": "object",
+            "properties": {
+                "pairs": {
+                    "description": "Supported pairs, in configuration order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PairData"
+                    }
+                }
+            }
+        },
+        "dto.PostLTPRequest": {
+            "description": "Bulk price query body",
+            "type": "object",
+            "required": [
+                "pairs"
+            ],
+            "properties": {
+                "max_age": {
+                    "description": "Maximum price age per pair (\"1s\", \"60s\", at least 1s); \"*\" applies to the other pairs. Older or missing prices are refreshed in one batch; pairs still older fail with STALE_DATA",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "*": "60s",
+                        "BTC/USD": "1s"
+                    }
+                },
+                "pairs": {
+                    "description": "Trading pairs to query",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD",
+                        "ETH/USD"
+                    ]
+                }
+            }
+        },
         "dto.PriceData": {
             "description": "Last traded price data for a cryptocurrency pair",
             "type": "object",
@@ -301,10 +2347,28 @@ const docTemplate = `{
                     "minimum": 0,
                     "example": 45123.45
                 },
+                "exchange": {
+                    "description": "Where the price came from, when known",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ExchangeData"
+                        }
+                    ]
+                },
+                "exchange_time": {
+                    "description": "Time reported by the exchange, when available",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00Z"
+                },
                 "pair": {
                     "description": "Trading pair (e.g., BTC/USD)",
                     "type": "string",
                     "example": "BTC/USD"
+                },
+                "received_time": {
+                    "description": "Time the service received the price",
+                    "type": "string",
+                    "example": "2023-12-01T10:30:00.250Z"
                 }
             }
         },
@@ -337,6 +2401,310 @@ const docTemplate = `{
                     "example": "ETH/USD"
                 }
             }
+        },
+        "dto.ReconnectResponse": {
+            "description": "Result of a forced exchange reconnection",
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "description": "Whether the streaming connection is up after the attempt",
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "description": "Human readable summary",
+                    "type": "string",
+                    "example": "Exchange reconnected"
+                }
+            }
+        },
+        "dto.StartupStepData": {
+            "description": "Initialization or start of a component",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Component",
+                    "type": "string",
+                    "example": "cache"
+                },
+                "phase": {
+                    "description": "init (creation) or start (lifecycle)",
+                    "type": "string",
+                    "enum": [
+                        "init",
+                        "start"
+                    ],
+                    "example": "init"
+                },
+                "duration_ms": {
+                    "description": "Step duration",
+                    "type": "integer",
+                    "example": 12
+                },
+                "error": {
+                    "description": "Why the step failed",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StartupWarmupData": {
+            "description": "Startup cache warm-up result",
+            "type": "object",
+            "properties": {
+                "succeeded": {
+                    "description": "Pairs loaded into the cache",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD"
+                    ]
+                },
+                "failed": {
+                    "description": "Pairs that failed, with the reason",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "duration_ms": {
+                    "description": "Warm-up duration",
+                    "type": "integer",
+                    "example": 850
+                },
+                "error": {
+                    "description": "Set when required pairs were not loaded",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StreamMessage": {
+            "description": "WebSocket price feed message",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Error code (error messages only)",
+                    "type": "string",
+                    "example": "INVALID_BODY"
+                },
+                "message": {
+                    "description": "Error description (error messages only)",
+                    "type": "string",
+                    "example": "unknown message type: x"
+                },
+                "prices": {
+                    "description": "Snapshot: every cached subscribed pair; diff: the pairs that changed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PriceData"
+                    }
+                },
+                "seq": {
+                    "description": "Per-connection sequence number of snapshots and diffs; a gap means a message was lost",
+                    "type": "integer",
+                    "example": 42
+                },
+                "type": {
+                    "description": "snapshot, diff or error",
+                    "type": "string",
+                    "example": "diff"
+                }
+            }
+        },
+        "dto.TenantData": {
+            "description": "Tenant state",
+            "type": "object",
+            "properties": {
+                "api_key_count": {
+                    "description": "Number of API keys",
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "description": "Tenant name",
+                    "type": "string",
+                    "example": "team-a"
+                },
+                "pairs": {
+                    "description": "Allowed pairs; empty allows every supported pair",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD",
+                        "ETH/USD"
+                    ]
+                },
+                "rate_limit": {
+                    "description": "Tenant-wide rate limit, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TenantRateLimitData"
+                        }
+                    ]
+                },
+                "source": {
+                    "description": "config (read-only) or admin (managed at runtime)",
+                    "type": "string",
+                    "example": "config"
+                }
+            }
+        },
+        "dto.TenantRateLimitData": {
+            "description": "Tenant rate limit",
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Maximum burst",
+                    "type": "integer",
+                    "example": 50
+                },
+                "refill_rate": {
+                    "description": "Tokens added per second",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "dto.TenantRequest": {
+            "description": "Tenant definition",
+            "type": "object",
+            "required": [
+                "api_keys"
+            ],
+            "properties": {
+                "api_keys": {
+                    "description": "API keys of the tenant (stored hashed, never returned)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "team-a-key"
+                    ]
+                },
+                "pairs": {
+                    "description": "Allowed pairs; empty allows every supported pair",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC/USD",
+                        "ETH/USD"
+                    ]
+                },
+                "rate_limit": {
+                    "description": "Tenant-wide token bucket; omitted = no tenant limit",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TenantRateLimitData"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.TenantsResponse": {
+            "description": "Tenants and whether they can be managed at runtime",
+            "type": "object",
+            "properties": {
+                "management_enabled": {
+                    "description": "Whether tenants can be created or deleted via the API",
+                    "type": "boolean",
+                    "example": true
+                },
+                "tenants": {
+                    "description": "Tenants sorted by name",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TenantData"
+                    }
+                }
+            }
+        },
+        "dto.UsageRecordData": {
+            "description": "Daily usage of an API key",
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "UTC day",
+                    "type": "string",
+                    "example": "2026-10-14"
+                },
+                "key_id": {
+                    "description": "First 12 hex digits of the SHA-256 of the API key",
+                    "type": "string",
+                    "example": "3f2a9c1b7d4e"
+                },
+                "requests": {
+                    "description": "API requests",
+                    "type": "integer",
+                    "example": 1520
+                },
+                "stream_messages": {
+                    "description": "Price events sent by /ltp/stream",
+                    "type": "integer",
+                    "example": 86400
+                },
+                "tenant": {
+                    "description": "Tenant of the key; omitted for the operator key",
+                    "type": "string",
+                    "example": "team-a"
+                }
+            }
+        },
+        "dto.UsageResponse": {
+            "description": "Usage per API key and UTC day, aggregated across replicas",
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "First UTC day (inclusive)",
+                    "type": "string",
+                    "example": "2026-10-08"
+                },
+                "records": {
+                    "description": "Sorted by date, tenant and key",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UsageRecordData"
+                    }
+                },
+                "to": {
+                    "description": "Last UTC day (inclusive)",
+                    "type": "string",
+                    "example": "2026-10-14"
+                },
+                "totals": {
+                    "description": "Per tenant, sorted by tenant",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UsageTotalData"
+                    }
+                }
+            }
+        },
+        "dto.UsageTotalData": {
+            "description": "Usage totals of a tenant",
+            "type": "object",
+            "properties": {
+                "requests": {
+                    "description": "API requests",
+                    "type": "integer",
+                    "example": 10640
+                },
+                "stream_messages": {
+                    "description": "Price events sent by /ltp/stream",
+                    "type": "integer",
+                    "example": 604800
+                },
+                "tenant": {
+                    "description": "Tenant; omitted for the operator key",
+                    "type": "string",
+                    "example": "team-a"
+                }
+            }
         }
     }
 }`
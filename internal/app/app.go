@@ -0,0 +1,520 @@
+// Package app ensambla los componentes del servicio (caché, exchange, servicios,
+// schedulers y servidor HTTP) y controla su ciclo de vida de forma uniforme.
+package app
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/application/services"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"btc-ltp-service/internal/infrastructure/slo"
+	"btc-ltp-service/internal/infrastructure/web/router"
+	"btc-ltp-service/internal/infrastructure/web/server"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// HandlerFactory construye el handler HTTP a partir de la aplicación ensamblada
+type HandlerFactory func(a *Application) http.Handler
+
+// Application contiene los componentes ensamblados del servicio. Los campos son
+// de sólo lectura una vez creada con New.
+type Application struct {
+	Config       *config.Config
+	Exchange     interfaces.Exchange
+	Cache        interfaces.Cache
+	State        interfaces.StateRepository
+	Leader       interfaces.LeaderElector
+	Flags        *services.FeatureFlags
+	Tenants      *services.TenantRegistry      // nil si tenancy está deshabilitado
+	Usage        *services.UsageMeter          // nil si usage está deshabilitado
+	Audit        *services.AuditTrail          // nil si audit está deshabilitado
+	SLO          *slo.Tracker                  // nil si slo.enabled es false
+	Guard        *services.DeviationGuard      // nil si price_validation.deviation_guard está deshabilitado
+	Anomalies    *services.AnomalyDetector     // nil si anomaly_detection está deshabilitado
+	Maintenance  *services.MaintenanceCalendar // nil si maintenance está deshabilitado
+	Activity     *services.PairActivity        // nil si business.pair_pruning está deshabilitado
+	OnDemand     *services.OnDemandPairs       // nil si business.on_demand_pairs está deshabilitado
+	PriceService interfaces.PriceService
+	Warmer       *services.CacheWarmer
+	Server       *server.Server
+
+	handlerFactory HandlerFactory
+	lifecycle      lifecycle
+	serverErr      chan error
+	startup        *startupReport
+}
+
+// Option sustituye un componente antes de ensamblar la aplicación (útil en tests
+// y en modos como exporter o warmup)
+type Option func(*Application)
+
+// WithExchange usa exchange en lugar del creado desde la configuración
+func WithExchange(exchange interfaces.Exchange) Option {
+	return func(a *Application) {
+		a.Exchange = exchange
+	}
+}
+
+// WithCache usa cache en lugar del backend configurado
+func WithCache(cache interfaces.Cache) Option {
+	return func(a *Application) {
+		a.Cache = cache
+	}
+}
+
+// WithStateRepository usa repo en lugar del backend configurado
+func WithStateRepository(repo interfaces.StateRepository) Option {
+	return func(a *Application) {
+		a.State = repo
+	}
+}
+
+// WithLeaderElector usa elector en lugar del configurado
+func WithLeaderElector(elector interfaces.LeaderElector) Option {
+	return func(a *Application) {
+		a.Leader = elector
+	}
+}
+
+// WithHandlerFactory reemplaza el router de la API de precios por otro handler
+func WithHandlerFactory(factory HandlerFactory) Option {
+	return func(a *Application) {
+		a.handlerFactory = factory
+	}
+}
+
+// New ensambla la aplicación: crea los componentes no sustituidos por opts y
+// registra su arranque y apagado. No inicia nada; ver Start y Run.
+func New(ctx context.Context, cfg *config.Config, opts ...Option) (*Application, error) {
+	logging.Info(ctx, "Initializing application dependencies", nil)
+
+	a := &Application{Config: cfg, handlerFactory: DefaultHandler, serverErr: make(chan error, 1)}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.startup = newStartupReport(time.Now(), config.Warnings(cfg))
+	a.lifecycle.observe = func(name string, begin time.Time, err error) {
+		a.startup.step("start", name, begin, err)
+	}
+
+	// 0. Metric groups disabled by configuration (shared default registry)
+	if err := disableMetricGroups(cfg.Metrics); err != nil {
+		return nil, err
+	}
+
+	// 1. Exchange client - usar Mock en development mode, sino Fallback real
+	if a.Exchange == nil {
+		begin := time.Now()
+		a.Exchange = NewExchange(ctx, cfg)
+		a.startup.step("init", "exchange", begin, nil)
+	}
+
+	// 2. Cache with configuration
+	if a.Cache == nil {
+		begin := time.Now()
+		appCache, err := NewCache(ctx, cfg.Cache)
+		if err != nil {
+			return nil, err
+		}
+		a.Cache = appCache
+		a.startup.step("init", "cache", begin, nil)
+	}
+
+	// 3. State repository for runtime-mutable state (pairs, alerts, API keys)
+	if a.State == nil {
+		begin := time.Now()
+		stateRepo, err := NewStateRepository(ctx, cfg.State, cfg.Cache.Redis)
+		if err != nil {
+			return nil, err
+		}
+		a.State = stateRepo
+		a.startup.step("init", "state", begin, nil)
+	}
+
+	// 3b. Feature flags; runtime overrides live in the state repository
+	var flagStore interfaces.StateRepository
+	if cfg.Features.OverridesEnabled {
+		flagStore = a.State
+	}
+	a.Flags = services.NewFeatureFlags(cfg.Features.Flags, flagStore, cfg.Features.RefreshInterval)
+
+	// 3c. Tenants; the runtime-managed ones also live in the state repository
+	if cfg.Tenancy.Enabled {
+		a.Tenants = NewTenantRegistry(cfg.Tenancy, cfg.Auth.APIKey, a.State)
+	}
+
+	// 3d. Usage accounting per API key; daily aggregates live in the state repository
+	if cfg.Usage.Enabled {
+		a.Usage = NewUsageMeter(cfg.Usage, cfg.Leader, a.State)
+	}
+
+	// 3e. Audit trail of fallbacks and reconnects, also in the state repository
+	if cfg.Audit.Enabled {
+		a.Audit = NewAuditTrail(cfg.Audit, cfg.Leader, a.State)
+	}
+
+	// 4. Leader election for background jobs
+	if a.Leader == nil {
+		begin := time.Now()
+		a.Leader = NewLeaderElector(ctx, cfg.Leader, cfg.Cache.Redis)
+		a.startup.step("init", "leader", begin, nil)
+	}
+	fallbackExchange, isFallback := a.Exchange.(*exchange.FallbackExchange)
+	if isFallback {
+		fallbackExchange.SetLeaderElector(a.Leader)
+		if a.Audit != nil {
+			fallbackExchange.SetAuditRecorder(a.Audit)
+		}
+	}
+
+	// 4b. Maintenance calendar: background REST refreshes slow down during known windows
+	if cfg.Maintenance.Enabled {
+		a.Maintenance = NewMaintenanceCalendar(cfg.Maintenance)
+		if isFallback {
+			fallbackExchange.SetMaintenanceCheck(a.Maintenance.InMaintenance, cfg.Maintenance.RefreshInterval)
+		}
+	}
+
+	// 5. Price service with configuration
+	priceCodec, err := cache.NewPriceCodec(cfg.Cache.Codec)
+	if err != nil {
+		return nil, err
+	}
+	serviceOpts := []services.PriceServiceOption{services.WithPriceCodec(priceCodec)}
+	if cfg.Validation.Enabled {
+		priceValidator := NewPriceValidator(cfg.Validation)
+		serviceOpts = append(serviceOpts, services.WithPriceValidator(priceValidator))
+		if isFallback {
+			fallbackExchange.SetPriceValidator(priceValidator)
+		}
+	}
+	guardOpts := serviceOpts
+	if cfg.Validation.DeviationGuard.Enabled {
+		// El guard lee la caché con un servicio propio para seguir viendo los precios que retiene
+		if reference, ok := a.Exchange.(interfaces.WarmupExchange); ok {
+			unguarded := services.NewPriceServiceWithTTL(a.Exchange, a.Cache, cfg.Cache.TTL, cfg.Business.SupportedPairs, serviceOpts...)
+			a.Guard = NewDeviationGuard(cfg.Validation.DeviationGuard, unguarded, reference)
+			guardOpts = append(slices.Clip(serviceOpts), services.WithPriceGuard(a.Guard))
+		} else {
+			a.warn(ctx, "Deviation guard disabled: exchange has no REST reference source")
+		}
+	}
+	if cfg.Business.PairPruning.Enabled {
+		// Sólo el servicio que atiende las requests registra actividad
+		a.Activity = services.NewPairActivity(cfg.Business.PairPruning.IdleAfter)
+		guardOpts = append(slices.Clip(guardOpts), services.WithPairActivity(a.Activity))
+	}
+	if onDemand := cfg.Business.OnDemandPairs; onDemand.Enabled {
+		if resolver, ok := a.Exchange.(interfaces.PairResolver); ok {
+			if isFallback && fallbackExchange.PairMapper() == nil {
+				a.warn(ctx, "On-demand pairs need kraken.dynamic_pairs to resolve pairs")
+			}
+			a.OnDemand = services.NewOnDemandPairs(cfg.Business.SupportedPairs, resolver, onDemand.MaxPairs)
+			guardOpts = append(slices.Clip(guardOpts), services.WithOnDemandPairs(a.OnDemand))
+		} else {
+			a.warn(ctx, "On-demand pairs disabled: exchange cannot resolve pairs")
+		}
+	}
+	a.PriceService = services.NewSlowRequestLogger(
+		services.NewPriceServiceWithTTL(a.Exchange, a.Cache, cfg.Cache.TTL, cfg.Business.SupportedPairs, guardOpts...),
+		cfg.Logging.SlowRequestThreshold,
+	)
+
+	// 5b. Anomaly detection over the WebSocket tick stream
+	if cfg.Anomalies.Enabled {
+		if isFallback {
+			a.Anomalies = NewAnomalyDetector(cfg.Anomalies)
+			fallbackExchange.OnPrice(a.Anomalies.Observe)
+			if a.Maintenance != nil {
+				a.Anomalies.SetMaintenanceCheck(a.Maintenance.InMaintenance)
+			}
+		} else {
+			a.warn(ctx, "Anomaly detection disabled: exchange has no tick stream")
+		}
+	}
+
+	// 6. Cache warm-up: REST-only when the exchange supports it, sharing the same cache
+	warmupService := a.PriceService
+	if wu, ok := a.Exchange.(interfaces.WarmupExchange); ok {
+		warmupService = services.NewPriceServiceWithTTL(services.NewWarmupSource(wu), a.Cache, cfg.Cache.TTL, cfg.Business.SupportedPairs, serviceOpts...)
+	}
+	a.Warmer = NewCacheWarmer(warmupService, cfg.Warmup)
+
+	// 7. SLO tracking over the HTTP and price pipeline histograms
+	a.SLO = NewSLOTracker(ctx, cfg.SLO)
+
+	// 8. HTTP server
+	a.Server = server.NewServer(a.handlerFactory(a), cfg.Server.Port).
+		WithDrainPeriod(cfg.Server.DrainPeriod).
+		WithTimeouts(cfg.Timeouts.HTTPRead, cfg.Timeouts.HTTPWrite, cfg.Timeouts.HTTPIdle).
+		WithListeners(serverListeners(cfg.Server.Listeners), cfg.Server.ReusePort)
+
+	logging.Info(ctx, "Price service initialized", logging.Fields{
+		"cache_ttl_seconds": cfg.Cache.TTL.Seconds(),
+		"cache_prefix":      cfg.Business.CachePrefix,
+		"exchange_type":     fmt.Sprintf("%T", a.Exchange),
+	})
+
+	a.registerComponents()
+
+	logging.Info(ctx, "All dependencies initialized successfully", nil)
+	return a, nil
+}
+
+// warn registra una función deshabilitada por la configuración y la suma a las
+// advertencias del informe de arranque
+func (a *Application) warn(ctx context.Context, message string) {
+	logging.Warn(ctx, message, logging.Fields{
+		"exchange_type": fmt.Sprintf("%T", a.Exchange),
+	})
+	a.startup.warn(message)
+}
+
+// StartupReport retorna el informe de arranque: duración de cada paso, resultado
+// del warm-up y advertencias de configuración
+func (a *Application) StartupReport() interfaces.StartupReport {
+	return a.startup.get()
+}
+
+// Drain empieza a drenar el servidor HTTP antes del apagado (ver server.Server.Drain)
+func (a *Application) Drain(ctx context.Context) int {
+	return a.Server.Drain(ctx)
+}
+
+// Draining indica si el servidor HTTP está drenando
+func (a *Application) Draining() bool {
+	return a.Server.Draining()
+}
+
+// DefaultHandler es el router completo de la API de precios
+func DefaultHandler(a *Application) http.Handler {
+	appRouter := router.NewRouter(a.PriceService, a.Config.Business.SupportedPairs, a.Config.RateLimit, a.Config.Auth)
+	appRouter.SetServerConfig(a.Config.Server)
+	appRouter.SetConfig(a.Config)
+	appRouter.AddReadinessCheck("warmup", a.Warmer.Ready)
+	if a.Maintenance != nil {
+		appRouter.AddReadinessDetail("maintenance", a.Maintenance.Describe)
+	}
+	if reporter, ok := a.Exchange.(interfaces.SystemStatusReporter); ok {
+		appRouter.AddReadinessDetail("exchange_status", func(context.Context) string {
+			if status := reporter.SystemStatus(); status != "" {
+				return status
+			}
+			return "unknown"
+		})
+	}
+	appRouter.SetFeatureFlags(a.Flags)
+	appRouter.SetStartupReport(a)
+	appRouter.SetDrainer(a)
+	if a.Tenants != nil {
+		appRouter.SetTenants(a.Tenants)
+	}
+	if a.Usage != nil {
+		appRouter.SetUsage(a.Usage)
+	}
+	if a.Audit != nil {
+		appRouter.SetAuditLog(a.Audit)
+	}
+	if a.Activity != nil {
+		appRouter.SetPairActivity(a.Activity)
+	}
+	if a.OnDemand != nil {
+		appRouter.SetOnDemandPairs(a.OnDemand)
+	}
+	if format := a.Config.Business.PriceFormat; format.Enabled {
+		appRouter.SetPriceFormat(dto.NewPriceFormat(format.Mode, format.QuoteDecimals))
+	}
+	if reconnector, ok := a.Exchange.(interfaces.Reconnectable); ok {
+		appRouter.SetReconnector(reconnector)
+	}
+	if history, ok := a.Exchange.(interfaces.FallbackHistory); ok {
+		appRouter.SetFallbackHistory(history)
+	}
+	if quality, ok := a.Exchange.(interfaces.FeedQualityReporter); ok {
+		appRouter.SetFeedQuality(quality)
+	}
+	if subscriptions, ok := a.Exchange.(interfaces.SubscriptionReporter); ok {
+		appRouter.SetSubscriptions(subscriptions)
+	}
+	if catalog, ok := a.Exchange.(interfaces.PairCatalog); ok {
+		appRouter.SetPairCatalog(catalog)
+	}
+	return appRouter.GetHandler()
+}
+
+// registerComponents define el orden de arranque; el apagado es el inverso:
+// servidor HTTP, SLOs, detección de anomalías, control de desvío, refresco
+// automático, liderazgo, exchange, uso, tenants, feature flags, state repository y caché
+func (a *Application) registerComponents() {
+	a.lifecycle.add(component{
+		name: "cache",
+		stop: func(ctx context.Context) error { return closeComponent(ctx, a.Cache) },
+	})
+	a.lifecycle.add(component{
+		name: "state",
+		stop: func(ctx context.Context) error { return closeComponent(ctx, a.State) },
+	})
+	a.lifecycle.add(component{
+		name:  "feature_flags",
+		start: a.Flags.Start,
+		stop:  a.Flags.Stop,
+	})
+	if a.Tenants != nil {
+		a.lifecycle.add(component{
+			name:  "tenants",
+			start: a.Tenants.Start,
+			stop:  a.Tenants.Stop,
+		})
+	}
+	if a.Usage != nil {
+		a.lifecycle.add(component{
+			name:  "usage",
+			start: a.Usage.Start,
+			stop:  a.Usage.Stop,
+		})
+	}
+	if a.Audit != nil {
+		a.lifecycle.add(component{
+			name:  "audit",
+			start: a.Audit.Start,
+			stop:  a.Audit.Stop,
+		})
+	}
+	a.lifecycle.add(component{
+		name: "exchange",
+		stop: func(ctx context.Context) error { return closeComponent(ctx, a.Exchange) },
+	})
+	a.lifecycle.add(component{
+		name:  "leader",
+		start: a.Leader.Start,
+		stop:  func(context.Context) error { return a.Leader.Stop() },
+	})
+	a.lifecycle.add(component{
+		name: "warmup",
+		start: func(ctx context.Context) error {
+			// Un warm-up fallido no impide arrancar: /ready reporta los pares requeridos
+			result, err := WarmupCache(ctx, a.Warmer, a.Config.Business.SupportedPairs)
+			a.startup.warmup(result, err)
+			if err != nil {
+				logging.Warn(ctx, "Failed to initialize cache with supported pairs", logging.Fields{
+					"error":                 err.Error(),
+					"supported_pairs_count": len(a.Config.Business.SupportedPairs),
+					"supported_pairs":       a.Config.Business.SupportedPairs,
+				})
+			}
+			return nil
+		},
+	})
+
+	// Only runs on the leader replica
+	refresher := NewCacheRefresher(a.PriceService, a.Leader, a.Config.Business.SupportedPairs, a.Config.Cache.TTL)
+	refresher.SetFailureThreshold(a.Config.Cache.RefreshFailureThreshold)
+	refresher.SetRetryBackoff(a.Config.Cache.RefreshMaxBackoff)
+	refresher.SetRoundTimeout(a.Config.Timeouts.CacheRefresh)
+	if a.Maintenance != nil {
+		refresher.SetMaintenanceCheck(a.Maintenance.InMaintenance, a.Config.Maintenance.RefreshInterval)
+	}
+	if a.Activity != nil {
+		pruner, _ := a.Exchange.(interfaces.PairPruner) // nil: sólo se deja de refrescar
+		refresher.SetPairActivity(a.Activity, pruner)
+	}
+	if a.OnDemand != nil {
+		refresher.SetOnDemandPairs(a.OnDemand)
+	}
+	a.lifecycle.add(component{
+		name:  "cache_refresh",
+		start: refresher.Start,
+		stop:  refresher.Stop,
+	})
+
+	if a.Guard != nil {
+		a.lifecycle.add(component{
+			name:  "deviation_guard",
+			start: a.Guard.Start,
+			stop:  a.Guard.Stop,
+		})
+	}
+
+	if a.Anomalies != nil {
+		a.lifecycle.add(component{
+			name:  "anomaly_detection",
+			start: a.Anomalies.Start,
+			stop:  a.Anomalies.Stop,
+		})
+	}
+
+	if a.SLO != nil {
+		a.lifecycle.add(component{
+			name:  "slo",
+			start: a.SLO.Start,
+			stop:  a.SLO.Stop,
+		})
+	}
+
+	a.lifecycle.add(component{
+		name: "http_server",
+		start: func(ctx context.Context) error {
+			logging.Info(ctx, "Starting HTTP server", logging.Fields{
+				"port":             a.Config.Server.Port,
+				"shutdown_timeout": a.Config.Server.ShutdownTimeout,
+			})
+			go func() {
+				if err := a.Server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.serverErr <- err
+				}
+			}()
+			return nil
+		},
+		stop: a.Server.Stop,
+	})
+}
+
+// Start arranca los componentes en orden; si uno falla detiene los ya iniciados
+func (a *Application) Start(ctx context.Context) error {
+	if err := a.lifecycle.start(ctx); err != nil {
+		return err
+	}
+	a.startup.ready(ctx, time.Now())
+	return nil
+}
+
+// Stop detiene los componentes iniciados en orden inverso
+func (a *Application) Stop(ctx context.Context) error {
+	return a.lifecycle.stop(ctx)
+}
+
+// Run arranca la aplicación y bloquea hasta que ctx se cancele o el servidor HTTP
+// falle; luego la detiene dentro de server.shutdown_timeout
+func (a *Application) Run(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logging.Info(ctx, "Received shutdown signal", logging.Fields{
+			"shutdown_timeout": a.Config.Server.ShutdownTimeout,
+		})
+	case runErr = <-a.serverErr:
+		logging.ErrorWithError(ctx, "HTTP server failed", runErr, nil)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.Config.Server.ShutdownTimeout)
+	defer cancel()
+	if err := a.Stop(shutdownCtx); err != nil {
+		logging.ErrorWithError(ctx, "Error during graceful shutdown", err, nil)
+		return errors.Join(runErr, err)
+	}
+
+	logging.Info(ctx, "Graceful shutdown completed successfully", nil)
+	return runErr
+}
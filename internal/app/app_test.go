@@ -0,0 +1,184 @@
+package app
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange"
+	"btc-ltp-service/internal/infrastructure/leader"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycle_StartsInOrderAndStopsInReverse(t *testing.T) {
+	var events []string
+	record := func(event string) func(context.Context) error {
+		return func(context.Context) error {
+			events = append(events, event)
+			return nil
+		}
+	}
+
+	var l lifecycle
+	l.add(component{name: "a", start: record("start a"), stop: record("stop a")})
+	l.add(component{name: "b", stop: record("stop b")})
+	l.add(component{name: "c", start: record("start c"), stop: record("stop c")})
+
+	require.NoError(t, l.start(context.Background()))
+	require.NoError(t, l.stop(context.Background()))
+	assert.Equal(t, []string{"start a", "start c", "stop c", "stop b", "stop a"}, events)
+
+	events = nil
+	require.NoError(t, l.stop(context.Background()), "stopping twice is a no-op")
+	assert.Empty(t, events)
+}
+
+func TestLifecycle_StartFailureStopsStartedComponents(t *testing.T) {
+	var stopped []string
+	stop := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			stopped = append(stopped, name)
+			return nil
+		}
+	}
+
+	var l lifecycle
+	l.add(component{name: "a", stop: stop("a")})
+	l.add(component{name: "b", start: func(context.Context) error { return errors.New("boom") }, stop: stop("b")})
+	l.add(component{name: "c", stop: stop("c")})
+
+	err := l.start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start b")
+	assert.Equal(t, []string{"a"}, stopped, "only components that started are stopped")
+}
+
+type fakeCloser struct{ closed, shutdown bool }
+
+func (f *fakeCloser) Close() error { f.closed = true; return nil }
+
+type fakeGracefulCloser struct{ fakeCloser }
+
+func (f *fakeGracefulCloser) Shutdown(context.Context) error { f.shutdown = true; return nil }
+
+func TestCloseComponent_PrefersGracefulShutdown(t *testing.T) {
+	plain := &fakeCloser{}
+	require.NoError(t, closeComponent(context.Background(), plain))
+	assert.True(t, plain.closed)
+
+	graceful := &fakeGracefulCloser{}
+	require.NoError(t, closeComponent(context.Background(), graceful))
+	assert.True(t, graceful.shutdown)
+	assert.False(t, graceful.closed, "Close is not called when Shutdown is available")
+
+	assert.NoError(t, closeComponent(context.Background(), struct{}{}), "values without Close are ignored")
+}
+
+func TestApplication_RunWithSubstitutedComponents(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Server.Port = 0
+	cfg.Server.ShutdownTimeout = time.Second
+	cfg.Business.SupportedPairs = []string{"BTC/USD", "ETH/USD"}
+
+	mockExchange := exchange.NewMockExchange()
+	application, err := New(context.Background(), cfg,
+		WithExchange(mockExchange),
+		WithCache(cache.NewMemoryCache()),
+		WithStateRepository(state.NewMemoryStateRepository()),
+		WithLeaderElector(leader.NewStaticElector()),
+		WithHandlerFactory(func(*Application) http.Handler { return http.NotFoundHandler() }),
+	)
+	require.NoError(t, err)
+	assert.Same(t, mockExchange, application.Exchange)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- application.Run(ctx) }()
+
+	// El warm-up corre durante Start y deja los pares soportados en caché
+	require.Eventually(t, func() bool {
+		price, err := application.PriceService.GetLastPrice(context.Background(), "BTC/USD")
+		return err == nil && price.Amount > 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
+
+func TestApplication_StartupReport(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Server.Port = 0
+	cfg.Business.SupportedPairs = []string{"BTC/USD", "ETH/USD"}
+
+	application, err := New(context.Background(), cfg,
+		WithExchange(exchange.NewMockExchange()),
+		WithCache(cache.NewMemoryCache()),
+		WithStateRepository(state.NewMemoryStateRepository()),
+		WithLeaderElector(leader.NewStaticElector()),
+		WithHandlerFactory(func(*Application) http.Handler { return http.NotFoundHandler() }),
+	)
+	require.NoError(t, err)
+	assert.True(t, application.StartupReport().ReadyAt.IsZero(), "not ready before Start")
+
+	require.NoError(t, application.Start(context.Background()))
+	defer func() { _ = application.Stop(context.Background()) }()
+
+	report := application.StartupReport()
+	assert.False(t, report.ReadyAt.Before(report.StartedAt))
+	var started []string
+	for _, step := range report.Steps {
+		assert.Equal(t, "start", step.Phase, "substituted components are not initialized by New")
+		started = append(started, step.Name)
+	}
+	assert.Contains(t, started, "warmup")
+	assert.Contains(t, started, "http_server")
+	require.NotNil(t, report.Warmup)
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, report.Warmup.Succeeded)
+	assert.Contains(t, report.ConfigWarnings, "auth is disabled: /api/v1/admin endpoints accept unauthenticated requests")
+}
+
+func TestApplication_DrainMarksNotReady(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Server.Port = 0
+	cfg.Business.SupportedPairs = []string{"BTC/USD"}
+
+	application, err := New(context.Background(), cfg,
+		WithExchange(exchange.NewMockExchange()),
+		WithCache(cache.NewMemoryCache()),
+		WithStateRepository(state.NewMemoryStateRepository()),
+		WithLeaderElector(leader.NewStaticElector()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, application.Start(context.Background()))
+	defer func() { _ = application.Stop(context.Background()) }()
+
+	handler := DefaultHandler(application)
+	ready := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return recorder.Code
+	}
+	assert.Equal(t, http.StatusOK, ready())
+	assert.False(t, application.Draining())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/admin/drain", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"draining":true`)
+
+	assert.True(t, application.Draining())
+	assert.Equal(t, http.StatusServiceUnavailable, ready())
+	assert.Equal(t, 0, application.Drain(context.Background()), "draining again only counts open streams")
+}
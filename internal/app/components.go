@@ -0,0 +1,323 @@
+package app
+
+import (
+	"btc-ltp-service/internal/application/services"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange"
+	"btc-ltp-service/internal/infrastructure/leader"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"btc-ltp-service/internal/infrastructure/runtimelimits"
+	"btc-ltp-service/internal/infrastructure/slo"
+	"btc-ltp-service/internal/infrastructure/web/server"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewExchange crea el exchange: Mock en development mode, sino Fallback WebSocket → REST
+func NewExchange(ctx context.Context, cfg *config.Config) interfaces.Exchange {
+	var exchangeClient interfaces.Exchange
+	if cfg.Development.MockMode || cfg.Development.DevMode {
+		exchangeClient = exchange.NewMockExchange()
+		logging.Info(ctx, "Mock exchange initialized for development", logging.Fields{
+			"type":       "MockExchange",
+			"mock_mode":  cfg.Development.MockMode,
+			"dev_mode":   cfg.Development.DevMode,
+			"debug_mode": cfg.Development.DebugMode,
+		})
+	} else {
+		krakenConfig := cfg.Exchange.Kraken
+		krakenConfig.WebSocketTimeouts = cfg.Timeouts.WebSocket
+		krakenConfig.Proxy = cfg.Exchange.Proxy.Override(cfg.Exchange.Kraken.Proxy)
+		exchangeClient = exchange.NewFallbackExchange(krakenConfig, cfg.Business.SupportedPairs)
+		logging.Info(ctx, "Fallback exchange initialized", logging.Fields{
+			"primary":          "WebSocket",
+			"secondary":        "REST",
+			"websocket_url":    cfg.Exchange.Kraken.WebSocketURL,
+			"rest_url":         cfg.Exchange.Kraken.RestURL,
+			"timeout_seconds":  cfg.Exchange.Kraken.Timeout.Seconds(),
+			"fallback_timeout": cfg.Exchange.Kraken.FallbackTimeout.Seconds(),
+			"max_retries":      cfg.Exchange.Kraken.MaxRetries,
+			"proxy":            krakenConfig.Proxy.URL != "",
+		})
+	}
+	return exchangeClient
+}
+
+// NewCache creates a cache instance based on configuration
+func NewCache(ctx context.Context, cacheConfig config.CacheConfig) (interfaces.Cache, error) {
+	cacheFactory := cache.NewFactory()
+
+	logging.Info(ctx, "Configuring cache", logging.Fields{
+		"backend":            cacheConfig.Backend,
+		"ttl_seconds":        cacheConfig.TTL.Seconds(),
+		"codec":              cacheConfig.Codec,
+		"redis_addr":         cacheConfig.Redis.Addr,
+		"redis_db":           cacheConfig.Redis.DB,
+		"redis_password_set": cacheConfig.Redis.Password != "",
+	})
+
+	return cacheFactory.CreateCacheFromEnv(
+		cacheConfig.Backend,
+		cacheConfig.Redis.Addr,
+		cacheConfig.Redis.Password,
+		cacheConfig.Redis.DB,
+	)
+}
+
+// NewStateRepository creates a state repository based on configuration
+func NewStateRepository(ctx context.Context, stateConfig config.StateConfig, redisConfig config.RedisConfig) (interfaces.StateRepository, error) {
+	logging.Info(ctx, "Configuring state repository", logging.Fields{
+		"backend":    stateConfig.Backend,
+		"sql_driver": stateConfig.SQL.Driver,
+	})
+
+	return state.NewFactory().Create(ctx, state.Config{
+		Backend:       state.BackendType(stateConfig.Backend),
+		RedisAddr:     redisConfig.Addr,
+		RedisPassword: redisConfig.Password,
+		RedisDB:       redisConfig.DB,
+		SQLDriver:     stateConfig.SQL.Driver,
+		SQLDSN:        stateConfig.SQL.DSN,
+	})
+}
+
+// NewLeaderElector creates the leader elector based on configuration; it is started
+// by the application lifecycle
+func NewLeaderElector(ctx context.Context, leaderConfig config.LeaderConfig, redisConfig config.RedisConfig) interfaces.LeaderElector {
+	var elector interfaces.LeaderElector
+	if !leaderConfig.Enabled {
+		elector = leader.NewStaticElector()
+	} else {
+		client := redis.NewClient(&redis.Options{
+			Addr:     redisConfig.Addr,
+			Password: redisConfig.Password,
+			DB:       redisConfig.DB,
+		})
+		elector = leader.NewRedisElector(client, leaderConfig.LockKey, leaderConfig.InstanceID, leaderConfig.LeaseTTL, leaderConfig.RenewInterval)
+	}
+
+	logging.Info(ctx, "Configuring leader election", logging.Fields{
+		"enabled":  leaderConfig.Enabled,
+		"backend":  leaderConfig.Backend,
+		"lock_key": leaderConfig.LockKey,
+	})
+
+	return elector
+}
+
+// NewSLOTracker crea el tracker de SLOs; devuelve nil si está deshabilitado
+func NewSLOTracker(ctx context.Context, sloConfig config.SLOConfig) *slo.Tracker {
+	if !sloConfig.Enabled {
+		return nil
+	}
+
+	objectives := make([]slo.Objective, len(sloConfig.Objectives))
+	names := make([]string, len(sloConfig.Objectives))
+	for i, objective := range sloConfig.Objectives {
+		objectives[i] = slo.Objective{
+			Name:    objective.Name,
+			Path:    objective.Path,
+			Target:  objective.Target,
+			Latency: objective.Latency,
+			MaxAge:  objective.MaxAge,
+		}
+		names[i] = objective.Name
+	}
+
+	logging.Info(ctx, "Configuring SLO tracking", logging.Fields{
+		"objectives": names,
+		"window":     sloConfig.Window.String(),
+	})
+
+	return slo.NewTracker(objectives, sloConfig.Window, sloConfig.EvaluationInterval)
+}
+
+// NewPriceValidator crea el validador de cordura de precios a partir de la configuración
+func NewPriceValidator(validationConfig config.PriceValidationConfig) *services.PriceSanityValidator {
+	return services.NewPriceSanityValidator(services.PriceValidatorConfig{
+		MaxJumpPercent: validationConfig.MaxJumpPercent,
+		MaxFutureSkew:  validationConfig.MaxFutureSkew,
+		QuarantineSize: validationConfig.QuarantineSize,
+	})
+}
+
+// NewDeviationGuard crea el control que compara los precios cacheados de prices
+// contra los precios REST de reference
+func NewDeviationGuard(guardConfig config.DeviationGuardConfig, prices interfaces.PriceService, reference interfaces.WarmupExchange) *services.DeviationGuard {
+	return services.NewDeviationGuard(prices.GetCachedPrices, services.NewWarmupSource(reference), services.DeviationGuardConfig{
+		Interval:            guardConfig.Interval,
+		MaxDeviationPercent: guardConfig.MaxDeviationPercent,
+		Action:              guardConfig.Action,
+	})
+}
+
+// NewAnomalyDetector crea el detector de flatlines y spikes a partir de la configuración
+func NewAnomalyDetector(anomalyConfig config.AnomalyDetectionConfig) *services.AnomalyDetector {
+	flatlinePairs := make([]string, len(anomalyConfig.FlatlinePairs))
+	for i, pair := range anomalyConfig.FlatlinePairs {
+		flatlinePairs[i] = entities.CanonicalPair(pair)
+	}
+	return services.NewAnomalyDetector(services.AnomalyDetectorConfig{
+		FlatlineAfter:   anomalyConfig.FlatlineAfter,
+		FlatlinePairs:   flatlinePairs,
+		SpikeZScore:     anomalyConfig.SpikeZScore,
+		SpikeWindow:     anomalyConfig.SpikeWindow,
+		SpikeMinSamples: anomalyConfig.SpikeMinSamples,
+		CheckInterval:   anomalyConfig.CheckInterval,
+	})
+}
+
+// NewMaintenanceCalendar crea el calendario de ventanas de mantenimiento; las
+// ventanas ya fueron validadas al cargar la configuración
+func NewMaintenanceCalendar(maintenanceConfig config.MaintenanceConfig) *services.MaintenanceCalendar {
+	repeats := map[string]time.Duration{"daily": 24 * time.Hour, "weekly": 7 * 24 * time.Hour}
+	windows := make([]services.MaintenanceWindow, 0, len(maintenanceConfig.Windows))
+	for _, window := range maintenanceConfig.Windows {
+		start, err := time.Parse(time.RFC3339, window.Start)
+		if err != nil {
+			continue
+		}
+		windows = append(windows, services.MaintenanceWindow{
+			Name:     window.Name,
+			Start:    start,
+			Duration: window.Duration,
+			Repeat:   repeats[window.Repeat],
+		})
+	}
+	return services.NewMaintenanceCalendar(windows)
+}
+
+// NewTenantRegistry crea el registro de tenants de la configuración (ya validada);
+// con admin_api_enabled los tenants creados vía API se guardan en store
+func NewTenantRegistry(tenancyConfig config.TenancyConfig, operatorKey string, store interfaces.StateRepository) *services.TenantRegistry {
+	tenants := make([]entities.Tenant, len(tenancyConfig.Tenants))
+	for i, tenant := range tenancyConfig.Tenants {
+		tenants[i] = entities.Tenant{
+			Name:                tenant.Name,
+			APIKeys:             tenant.APIKeys,
+			Pairs:               tenant.Pairs,
+			RateLimitCapacity:   tenant.RateLimit.Capacity,
+			RateLimitRefillRate: tenant.RateLimit.RefillRate,
+		}
+	}
+	if !tenancyConfig.AdminAPIEnabled {
+		store = nil
+	}
+	return services.NewTenantRegistry(tenants, operatorKey, store, tenancyConfig.RefreshInterval)
+}
+
+// NewUsageMeter crea la contabilidad de uso de esta réplica; los registros se
+// distinguen por leader_election.instance_id (o hostname-pid si no está definido)
+func NewUsageMeter(usageConfig config.UsageConfig, leaderConfig config.LeaderConfig, store interfaces.StateRepository) *services.UsageMeter {
+	instanceID := leaderConfig.InstanceID
+	if instanceID == "" {
+		instanceID = leader.DefaultInstanceID()
+	}
+	return services.NewUsageMeter(store, instanceID, usageConfig.FlushInterval, usageConfig.RetentionDays)
+}
+
+// NewAuditTrail crea el registro de auditoría de esta réplica; los eventos se
+// distinguen por leader_election.instance_id (o hostname-pid si no está definido)
+func NewAuditTrail(auditConfig config.AuditConfig, leaderConfig config.LeaderConfig, store interfaces.StateRepository) *services.AuditTrail {
+	instanceID := leaderConfig.InstanceID
+	if instanceID == "" {
+		instanceID = leader.DefaultInstanceID()
+	}
+	return services.NewAuditTrail(store, instanceID, auditConfig.FlushInterval, auditConfig.RetentionDays)
+}
+
+// NewCacheWarmer crea el CacheWarmer de priceService con la configuración de warm-up
+func NewCacheWarmer(priceService interfaces.PriceService, warmupConfig config.WarmupConfig) *services.CacheWarmer {
+	return services.NewCacheWarmer(priceService, services.WarmupConfig{
+		Timeout:       warmupConfig.Timeout,
+		BatchSize:     warmupConfig.BatchSize,
+		Concurrency:   warmupConfig.Concurrency,
+		RequiredPairs: warmupConfig.RequiredPairs,
+	})
+}
+
+// WarmupCache pre-loads the cache with prices for all supported pairs
+// using batched parallel REST requests; failing required pairs are reported by /ready.
+// The result is nil when there are no pairs to warm up.
+func WarmupCache(ctx context.Context, warmer *services.CacheWarmer, supportedPairs []string) (*services.WarmupResult, error) {
+	if len(supportedPairs) == 0 {
+		logging.Info(ctx, "No supported pairs configured, skipping cache initialization", nil)
+		return nil, nil
+	}
+
+	result, err := warmer.Warmup(ctx, supportedPairs)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to initialize cache with required pairs", err, logging.Fields{
+			"succeeded": result.Succeeded,
+			"failed":    result.Failed,
+		})
+		return result, err
+	}
+
+	if len(result.Failed) > 0 {
+		logging.Warn(ctx, "Cache initialized with some pairs missing", logging.Fields{
+			"succeeded_count": len(result.Succeeded),
+			"failed":          result.Failed,
+		})
+		return result, nil
+	}
+
+	logging.Info(ctx, "Successfully initialized cache with supported pairs", logging.Fields{
+		"pairs_count": len(supportedPairs),
+		"duration_ms": result.Duration.Milliseconds(),
+	})
+	return result, nil
+}
+
+// serverListeners convierte server.listeners en los listeners del servidor HTTP;
+// los permisos ya fueron validados al cargar la configuración
+func serverListeners(listeners []config.ListenerConfig) []server.Listener {
+	converted := make([]server.Listener, len(listeners))
+	for i, listener := range listeners {
+		mode, _ := listener.FileMode()
+		converted[i] = server.Listener{Network: listener.Network, Address: listener.Address, Mode: mode}
+	}
+	return converted
+}
+
+// disableMetricGroups deja de exponer en /metrics los grupos de metrics.disabled_groups
+func disableMetricGroups(metricsConfig config.MetricsConfig) error {
+	groups := make([]metrics.Group, 0, len(metricsConfig.DisabledGroups))
+	for _, name := range metricsConfig.DisabledGroups {
+		group, err := metrics.ParseGroup(name)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, group)
+	}
+	return metrics.Default().Disable(groups...)
+}
+
+// ApplyRuntimeLimits ajusta GOMAXPROCS y GOMEMLIMIT a los límites del cgroup
+// según la configuración, y registra los valores aplicados en logs y métricas
+func ApplyRuntimeLimits(ctx context.Context, runtimeConfig config.RuntimeConfig) runtimelimits.Applied {
+	applied := runtimelimits.Apply(runtimelimits.Options{
+		AutoMaxProcs:     runtimeConfig.AutoMaxProcs,
+		MaxProcs:         runtimeConfig.MaxProcs,
+		MemoryLimit:      runtimeConfig.MemoryLimit,
+		MemoryLimitRatio: runtimeConfig.MemoryLimitRatio,
+	})
+
+	logging.Info(ctx, "Runtime limits applied", logging.Fields{
+		"gomaxprocs":                applied.MaxProcs,
+		"gomaxprocs_source":         applied.MaxProcsSource,
+		"memory_limit_bytes":        applied.MemoryLimit,
+		"memory_limit_source":       applied.MemoryLimitSource,
+		"cgroup_cpu_quota":          applied.Quota.CPU,
+		"cgroup_memory_limit_bytes": applied.Quota.Memory,
+	})
+	metrics.SetRuntimeLimits(applied.MaxProcs, applied.MemoryLimit, applied.Quota.CPU, applied.Quota.Memory)
+	return applied
+}
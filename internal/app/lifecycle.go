@@ -0,0 +1,102 @@
+package app
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// component es una unidad con arranque y apagado opcionales
+type component struct {
+	name  string
+	start func(ctx context.Context) error
+	stop  func(ctx context.Context) error
+}
+
+// lifecycle arranca componentes en orden de registro y los detiene en orden inverso
+type lifecycle struct {
+	mu         sync.Mutex
+	components []component
+	started    int // cantidad de componentes iniciados (prefijo de components)
+
+	// observe, opcional, recibe el resultado del arranque de cada componente que
+	// empezó en begin
+	observe func(name string, begin time.Time, err error)
+}
+
+func (l *lifecycle) add(c component) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.components = append(l.components, c)
+}
+
+// start inicia los componentes pendientes; ante un error detiene los ya iniciados
+func (l *lifecycle) start(ctx context.Context) error {
+	l.mu.Lock()
+	for l.started < len(l.components) {
+		c := l.components[l.started]
+		if c.start != nil {
+			begin := time.Now()
+			err := c.start(ctx)
+			if l.observe != nil {
+				l.observe(c.name, begin, err)
+			}
+			if err != nil {
+				l.mu.Unlock()
+				startErr := fmt.Errorf("failed to start %s: %w", c.name, err)
+				if stopErr := l.stop(ctx); stopErr != nil {
+					return errors.Join(startErr, stopErr)
+				}
+				return startErr
+			}
+		}
+		l.started++
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// stop detiene los componentes iniciados en orden inverso; los errores no
+// interrumpen el apagado del resto
+func (l *lifecycle) stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for l.started > 0 {
+		l.started--
+		c := l.components[l.started]
+		if c.stop == nil {
+			continue
+		}
+		if err := c.stop(ctx); err != nil {
+			logging.Warn(ctx, "Error stopping component", logging.Fields{
+				"component": c.name,
+				"error":     err.Error(),
+			})
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.name, err))
+			continue
+		}
+		logging.Debug(ctx, "Component stopped", logging.Fields{
+			"component": c.name,
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// closeComponent apaga v si implementa interfaces.GracefulCloser (respetando ctx)
+// o interfaces.Closer; cualquier otro valor no requiere cierre
+func closeComponent(ctx context.Context, v any) error {
+	switch c := v.(type) {
+	case interfaces.GracefulCloser:
+		return c.Shutdown(ctx)
+	case interfaces.Closer:
+		return c.Close()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"time"
+)
+
+// DefaultRefreshFailureThreshold es la cantidad de refrescos fallidos seguidos de
+// un par a partir de la cual se registra un warning
+const DefaultRefreshFailureThreshold = 3
+
+// DefaultRefreshMaxBackoff es la espera máxima antes de reintentar un par que sigue fallando
+const DefaultRefreshMaxBackoff = 5 * time.Minute
+
+// refreshTracker lleva por par el último refresco exitoso, los fallos seguidos y
+// las rondas que faltan para reintentarlo. Sólo lo usa el loop del CacheRefresher,
+// sin concurrencia.
+type refreshTracker struct {
+	threshold   int
+	maxSkip     int // Máximo de rondas que se saltea un par que falla
+	lastSuccess map[string]time.Time
+	failures    map[string]int
+	skip        map[string]int
+}
+
+func newRefreshTracker(threshold int) *refreshTracker {
+	if threshold <= 0 {
+		threshold = DefaultRefreshFailureThreshold
+	}
+	return &refreshTracker{
+		threshold:   threshold,
+		lastSuccess: make(map[string]time.Time),
+		failures:    make(map[string]int),
+		skip:        make(map[string]int),
+	}
+}
+
+// plan reparte pairs para una ronda: healthy se refrescan juntos, retry (con fallos
+// previos y ya vencido su backoff) de a uno, y deferred esperan a otra ronda
+func (t *refreshTracker) plan(pairs []string) (healthy, retry, deferred []string) {
+	for _, pair := range pairs {
+		switch {
+		case t.failures[pair] == 0:
+			healthy = append(healthy, pair)
+		case t.skip[pair] > 0:
+			t.skip[pair]--
+			deferred = append(deferred, pair)
+		default:
+			retry = append(retry, pair)
+		}
+	}
+	return healthy, retry, deferred
+}
+
+// observe registra el resultado de una llamada al refresco terminada en now; err es
+// el error de esa llamada. Un par que llega a threshold fallos seguidos (y cada
+// threshold fallos más) se reporta con un warning; al recuperarse se registra
+// cuántos acumuló. Cada fallo duplica las rondas que el par espera (hasta maxSkip).
+func (t *refreshTracker) observe(ctx context.Context, now time.Time, report interfaces.RefreshReport, err error) {
+	for _, pair := range report.Refreshed {
+		if failures := t.failures[pair]; failures >= t.threshold {
+			fields := logging.Fields{
+				"pair":                 pair,
+				"consecutive_failures": failures,
+			}
+			if last, ok := t.lastSuccess[pair]; ok {
+				fields["seconds_since_success"] = now.Sub(last).Seconds()
+			}
+			logging.Info(ctx, "Pair refresh recovered", fields)
+		}
+		t.lastSuccess[pair] = now
+		delete(t.failures, pair)
+		delete(t.skip, pair)
+		metrics.UpdateRefreshPair(pair, now, 0)
+	}
+
+	for _, pair := range report.Failed {
+		t.failures[pair]++
+		failures := t.failures[pair]
+		t.skip[pair] = t.backoffRounds(failures)
+		metrics.UpdateRefreshPair(pair, t.lastSuccess[pair], failures)
+		if failures%t.threshold != 0 {
+			continue
+		}
+		fields := logging.Fields{
+			"pair":                 pair,
+			"consecutive_failures": failures,
+			"retry_in_rounds":      t.skip[pair] + 1,
+		}
+		if last, ok := t.lastSuccess[pair]; ok {
+			fields["last_success"] = last.Format(time.RFC3339)
+			fields["seconds_since_success"] = now.Sub(last).Seconds()
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		logging.Warn(ctx, "Pair failed consecutive cache refreshes", fields)
+	}
+}
+
+// backoffRounds retorna las rondas a saltear tras failures fallos seguidos:
+// 0, 1, 3, 7... hasta maxSkip
+func (t *refreshTracker) backoffRounds(failures int) int {
+	rounds := 0
+	for i := 1; i < failures && rounds < t.maxSkip; i++ {
+		rounds = 2*rounds + 1
+	}
+	return min(rounds, t.maxSkip)
+}
+
+// refreshLoopResult clasifica una ronda para btc_ltp_refresh_loop_duration_seconds
+func refreshLoopResult(report interfaces.RefreshReport, err error) string {
+	switch {
+	case err != nil && len(report.Refreshed) == 0:
+		return "error"
+	case err != nil || len(report.Failed) > 0:
+		return "partial"
+	}
+	return "success"
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTracker_CountsConsecutiveFailuresPerPair(t *testing.T) {
+	ctx := context.Background()
+	tracker := newRefreshTracker(2)
+	start := time.Now()
+
+	tracker.observe(ctx, start, interfaces.RefreshReport{Refreshed: []string{"BTC/USD", "ETH/USD"}}, nil)
+	assert.Equal(t, start, tracker.lastSuccess["ETH/USD"])
+
+	for i := 1; i <= 3; i++ {
+		tracker.observe(ctx, start.Add(time.Duration(i)*time.Minute),
+			interfaces.RefreshReport{Refreshed: []string{"BTC/USD"}, Failed: []string{"ETH/USD"}}, errors.New("failed to cache some prices"))
+	}
+	assert.Equal(t, 3, tracker.failures["ETH/USD"])
+	assert.Equal(t, 0, tracker.failures["BTC/USD"])
+	assert.Equal(t, start, tracker.lastSuccess["ETH/USD"], "failures keep the last success")
+
+	tracker.observe(ctx, start.Add(4*time.Minute), interfaces.RefreshReport{Refreshed: []string{"ETH/USD"}}, nil)
+	assert.Equal(t, 0, tracker.failures["ETH/USD"])
+	assert.Equal(t, start.Add(4*time.Minute), tracker.lastSuccess["ETH/USD"])
+}
+
+func TestRefreshTracker_DefaultThreshold(t *testing.T) {
+	assert.Equal(t, DefaultRefreshFailureThreshold, newRefreshTracker(0).threshold)
+}
+
+func TestRefreshTracker_BackoffDoublesUpToMax(t *testing.T) {
+	tracker := newRefreshTracker(3)
+	tracker.maxSkip = 10
+	var rounds []int
+	for failures := 1; failures <= 6; failures++ {
+		rounds = append(rounds, tracker.backoffRounds(failures))
+	}
+	assert.Equal(t, []int{0, 1, 3, 7, 10, 10}, rounds)
+
+	tracker.maxSkip = 0
+	assert.Equal(t, 0, tracker.backoffRounds(5), "without backoff a failing pair is retried every round")
+}
+
+func TestRefreshTracker_PlanDefersFailingPairs(t *testing.T) {
+	ctx := context.Background()
+	tracker := newRefreshTracker(3)
+	tracker.maxSkip = 10
+	pairs := []string{"BTC/USD", "ETH/USD"}
+	failed := interfaces.RefreshReport{Refreshed: []string{"BTC/USD"}, Failed: []string{"ETH/USD"}}
+
+	tracker.observe(ctx, time.Now(), failed, errors.New("unknown pair"))
+	healthy, retry, deferred := tracker.plan(pairs)
+	assert.Equal(t, []string{"BTC/USD"}, healthy)
+	assert.Equal(t, []string{"ETH/USD"}, retry, "after one failure the pair is retried alone next round")
+	assert.Empty(t, deferred)
+
+	tracker.observe(ctx, time.Now(), failed, errors.New("unknown pair"))
+	_, retry, deferred = tracker.plan(pairs)
+	assert.Empty(t, retry)
+	assert.Equal(t, []string{"ETH/USD"}, deferred)
+	_, retry, _ = tracker.plan(pairs)
+	assert.Equal(t, []string{"ETH/USD"}, retry)
+
+	tracker.observe(ctx, time.Now(), interfaces.RefreshReport{Refreshed: []string{"ETH/USD"}}, nil)
+	healthy, _, _ = tracker.plan(pairs)
+	assert.Equal(t, pairs, healthy, "a recovered pair rejoins the batch")
+}
+
+// batchRefresher falla cualquier refresco que incluya un par de bad
+type batchRefresher struct {
+	interfaces.PriceService
+	bad   map[string]bool
+	calls [][]string
+}
+
+func (b *batchRefresher) RefreshPrices(_ context.Context, pairs []string) error {
+	b.calls = append(b.calls, pairs)
+	for _, pair := range pairs {
+		if b.bad[pair] {
+			return errors.New("unknown pair " + pair)
+		}
+	}
+	return nil
+}
+
+func TestCacheRefresher_RefreshRoundIsolatesFailingPairs(t *testing.T) {
+	ctx := context.Background()
+	pairs := []string{"BTC/USD", "BAD/USD", "ETH/USD"}
+	service := &batchRefresher{bad: map[string]bool{"BAD/USD": true}}
+	refresher := NewCacheRefresher(service, nil, pairs, time.Minute)
+
+	report, err := refresher.refreshRound(ctx, pairs)
+	assert.Error(t, err)
+	assert.ElementsMatch(t, []string{"BTC/USD", "ETH/USD"}, report.Refreshed, "healthy pairs are refreshed despite the bad one")
+	assert.Equal(t, []string{"BAD/USD"}, report.Failed)
+	assert.Equal(t, [][]string{pairs, {"BTC/USD"}, {"BAD/USD"}, {"ETH/USD"}}, service.calls)
+
+	// En la ronda siguiente el lote sano ya no incluye al par que falla
+	service.calls = nil
+	_, err = refresher.refreshRound(ctx, pairs)
+	assert.Error(t, err)
+	assert.Equal(t, [][]string{{"BTC/USD", "ETH/USD"}, {"BAD/USD"}}, service.calls)
+
+	// Con dos fallos seguidos el par espera una ronda
+	service.calls = nil
+	report, err = refresher.refreshRound(ctx, pairs)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Failed)
+	assert.Equal(t, [][]string{{"BTC/USD", "ETH/USD"}}, service.calls)
+}
+
+type plainRefresher struct {
+	interfaces.PriceService
+	err error
+}
+
+func (p *plainRefresher) RefreshPrices(context.Context, []string) error { return p.err }
+
+func TestCacheRefresher_RefreshPricesWithoutReport(t *testing.T) {
+	pairs := []string{"BTC/USD", "ETH/USD"}
+	service := &plainRefresher{}
+	refresher := NewCacheRefresher(service, nil, pairs, time.Minute)
+
+	report, err := refresher.refreshPrices(context.Background(), pairs)
+	assert.NoError(t, err)
+	assert.Equal(t, pairs, report.Refreshed)
+
+	service.err = errors.New("exchange down")
+	report, err = refresher.refreshPrices(context.Background(), pairs)
+	assert.Error(t, err)
+	assert.Equal(t, pairs, report.Failed, "without a report an error fails every pair")
+}
@@ -0,0 +1,330 @@
+package app
+
+import (
+	"btc-ltp-service/internal/application/services"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval evita refrescos demasiado frecuentes con TTLs cortos
+const minRefreshInterval = 30 * time.Second
+
+// DefaultRefreshRoundTimeout es el deadline de cada ronda de refresco automático
+const DefaultRefreshRoundTimeout = 60 * time.Second
+
+// CacheRefresher actualiza la caché periódicamente antes de que expire el TTL;
+// sólo la réplica líder refresca para no multiplicar el tráfico a Kraken
+type CacheRefresher struct {
+	priceService   interfaces.PriceService
+	elector        interfaces.LeaderElector
+	supportedPairs []string
+	interval       time.Duration
+	roundTimeout   time.Duration
+
+	// Durante una ventana de mantenimiento los refrescos se espacian a maintenanceInterval
+	inMaintenance       func() bool
+	maintenanceInterval time.Duration
+	lastRefresh         time.Time
+
+	// Con poda, sólo se refrescan los pares pedidos recientemente
+	activity *services.PairActivity
+	pruner   interfaces.PairPruner
+	onDemand *services.OnDemandPairs // Opcional: suma los pares admitidos bajo demanda
+
+	tracker *refreshTracker // Último éxito, fallos seguidos y backoff por par
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCacheRefresher crea el scheduler; refresca cada ~TTL/2 con un mínimo de 30s.
+// Un elector nil refresca siempre.
+func NewCacheRefresher(priceService interfaces.PriceService, elector interfaces.LeaderElector, supportedPairs []string, cacheTTL time.Duration) *CacheRefresher {
+	// Refresh BEFORE TTL expires to avoid data gaps and spikes
+	interval := cacheTTL / 2
+	if interval < minRefreshInterval {
+		interval = minRefreshInterval
+	}
+	r := &CacheRefresher{
+		priceService:   priceService,
+		elector:        elector,
+		supportedPairs: supportedPairs,
+		interval:       interval,
+		roundTimeout:   DefaultRefreshRoundTimeout,
+
+		inMaintenance:       func() bool { return false },
+		maintenanceInterval: interval,
+		tracker:             newRefreshTracker(DefaultRefreshFailureThreshold),
+	}
+	r.SetRetryBackoff(DefaultRefreshMaxBackoff)
+	return r
+}
+
+// SetFailureThreshold registra un warning cuando un par acumula threshold refrescos
+// fallidos seguidos; no positivo usa DefaultRefreshFailureThreshold. Llamar antes de Start.
+func (r *CacheRefresher) SetFailureThreshold(threshold int) {
+	if threshold <= 0 {
+		threshold = DefaultRefreshFailureThreshold
+	}
+	r.tracker.threshold = threshold
+}
+
+// SetRetryBackoff limita a maxBackoff la espera antes de reintentar un par que sigue
+// fallando; la espera se cuenta en rondas del refresco y se duplica en cada fallo.
+// No positivo usa DefaultRefreshMaxBackoff. Llamar antes de Start.
+func (r *CacheRefresher) SetRetryBackoff(maxBackoff time.Duration) {
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRefreshMaxBackoff
+	}
+	r.tracker.maxSkip = int(maxBackoff / r.interval)
+}
+
+// SetRoundTimeout fija el deadline de cada ronda de refresco; no positivo usa
+// DefaultRefreshRoundTimeout. Llamar antes de Start.
+func (r *CacheRefresher) SetRoundTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultRefreshRoundTimeout
+	}
+	r.roundTimeout = timeout
+}
+
+// SetMaintenanceCheck espacia los refrescos a interval (nunca por debajo del
+// intervalo normal) mientras inMaintenance reporte una ventana activa. Llamar
+// antes de Start.
+func (r *CacheRefresher) SetMaintenanceCheck(inMaintenance func() bool, interval time.Duration) {
+	if inMaintenance != nil {
+		r.inMaintenance = inMaintenance
+	}
+	if interval > r.maintenanceInterval {
+		r.maintenanceInterval = interval
+	}
+}
+
+// SetPairActivity refresca sólo los pares activos según activity y cancela con
+// pruner (opcional) el streaming de los que se poden. Llamar antes de Start.
+func (r *CacheRefresher) SetPairActivity(activity *services.PairActivity, pruner interfaces.PairPruner) {
+	r.activity = activity
+	r.pruner = pruner
+}
+
+// SetOnDemandPairs refresca también los pares admitidos por onDemand. Con poda,
+// un par bajo demanda podado deja de estar admitido. Llamar antes de Start.
+func (r *CacheRefresher) SetOnDemandPairs(onDemand *services.OnDemandPairs) {
+	r.onDemand = onDemand
+}
+
+// Start lanza el proceso de refresco en background (no bloqueante)
+func (r *CacheRefresher) Start(ctx context.Context) error {
+	if len(r.supportedPairs) == 0 {
+		logging.Info(ctx, "No supported pairs configured, skipping automatic cache refresh", nil)
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		return nil
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	logging.Info(ctx, "Starting automatic cache refresh process", logging.Fields{
+		"refresh_interval_seconds": r.interval.Seconds(),
+		"pairs_count":              len(r.supportedPairs),
+		"pairs":                    r.supportedPairs,
+	})
+
+	go r.loop(context.WithoutCancel(ctx), r.stop, r.done)
+	return nil
+}
+
+// Stop detiene el proceso y espera a que termine el refresco en curso o venza ctx
+func (r *CacheRefresher) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		logging.Info(ctx, "Automatic cache refresh process stopped", nil)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *CacheRefresher) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			// La poda corre en todas las réplicas: cada una tiene sus suscripciones
+			pairs := r.prune(ctx)
+			if len(pairs) == 0 {
+				logging.Debug(ctx, "Skipping automatic cache refresh, all pairs are pruned", nil)
+				continue
+			}
+			if r.inMaintenance() && now.Sub(r.lastRefresh) < r.maintenanceInterval {
+				logging.Debug(ctx, "Skipping automatic cache refresh during exchange maintenance", nil)
+				continue
+			}
+			r.refresh(ctx, pairs)
+			r.lastRefresh = now
+		case <-stop:
+			logging.Info(ctx, "Stopping automatic cache refresh process", nil)
+			return
+		}
+	}
+}
+
+// pairs retorna los pares soportados más los admitidos bajo demanda
+func (r *CacheRefresher) pairs() []string {
+	if r.onDemand == nil {
+		return r.supportedPairs
+	}
+	return r.onDemand.Pairs()
+}
+
+// prune retorna los pares a refrescar y cancela el streaming de los recién podados
+func (r *CacheRefresher) prune(ctx context.Context) []string {
+	if r.activity == nil {
+		return r.pairs()
+	}
+	active, pruned := r.activity.Prune(r.pairs())
+	if len(pruned) == 0 {
+		return active
+	}
+	if r.onDemand != nil {
+		r.onDemand.Remove(pruned...)
+	}
+	logging.Info(ctx, "Pruning idle pairs", logging.Fields{
+		"pairs":        pruned,
+		"active_count": len(active),
+	})
+	if r.pruner != nil {
+		if err := r.pruner.PrunePairs(ctx, pruned); err != nil {
+			logging.Warn(ctx, "Failed to prune idle pairs", logging.Fields{
+				"error": err.Error(),
+				"pairs": pruned,
+			})
+		}
+	}
+	return active
+}
+
+// refresh ejecuta un refresco de pairs con timeout propio si esta réplica es líder
+func (r *CacheRefresher) refresh(ctx context.Context, pairs []string) {
+	// Only the leader replica refreshes to avoid multiplying Kraken traffic
+	if r.elector != nil && !r.elector.IsLeader() {
+		logging.Debug(ctx, "Skipping automatic cache refresh, instance is not leader", nil)
+		return
+	}
+
+	// Add small jitter (±10%) without external dependencies
+	jitter := time.Duration(float64(r.interval) * 0.1) // 10%
+	// pseudo-random using current nanoseconds
+	n := time.Now().UnixNano()
+	delta := time.Duration(n%int64(2*jitter)) - jitter
+	effectiveTimeout := r.roundTimeout + delta/10 // slightly adjust timeout
+
+	// Create context with timeout for each refresh
+	// Cada refresco lleva su propio request_id para seguirlo en los logs del exchange
+	refreshCtx, cancel := context.WithTimeout(logging.WithRequestID(ctx, logging.GenerateRequestID()), effectiveTimeout)
+	defer cancel()
+
+	logging.Debug(refreshCtx, "Running automatic cache refresh", logging.Fields{
+		"pairs_count": len(pairs),
+	})
+
+	start := time.Now()
+	report, err := r.refreshRound(refreshCtx, pairs)
+	metrics.RecordRefreshLoop(refreshLoopResult(report, err), time.Since(start).Seconds(), len(report.Refreshed), len(report.Failed))
+	if err != nil {
+		logging.Warn(refreshCtx, "Automatic cache refresh failed", logging.Fields{
+			"error":        err.Error(),
+			"pairs_count":  len(pairs),
+			"pairs":        pairs,
+			"failed_pairs": report.Failed,
+		})
+		return
+	}
+	logging.Debug(refreshCtx, "Automatic cache refresh completed successfully", logging.Fields{
+		"pairs_count":  len(pairs),
+		"failed_pairs": report.Failed,
+	})
+}
+
+// refreshRound refresca en un lote los pares sanos y de a uno los que vienen
+// fallando, para que un par roto no impida refrescar al resto. Si el lote falla
+// entero se reintenta par a par para aislar al culpable. Los pares en backoff
+// esperan a otra ronda y no figuran en el reporte.
+func (r *CacheRefresher) refreshRound(ctx context.Context, pairs []string) (interfaces.RefreshReport, error) {
+	healthy, retry, deferred := r.tracker.plan(pairs)
+	if len(deferred) > 0 {
+		logging.Debug(ctx, "Deferring refresh of failing pairs", logging.Fields{
+			"pairs": deferred,
+		})
+	}
+
+	var round interfaces.RefreshReport
+	var errs []error
+	observe := func(report interfaces.RefreshReport, err error) {
+		r.tracker.observe(ctx, time.Now(), report, err)
+		round.Refreshed = append(round.Refreshed, report.Refreshed...)
+		round.Failed = append(round.Failed, report.Failed...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(healthy) > 0 {
+		report, err := r.refreshPrices(ctx, healthy)
+		if err != nil && len(report.Refreshed) == 0 && len(healthy) > 1 && ctx.Err() == nil {
+			logging.Debug(ctx, "Batch refresh failed, retrying pairs individually", logging.Fields{
+				"error":       err.Error(),
+				"pairs_count": len(healthy),
+			})
+			retry = append(healthy, retry...)
+		} else {
+			observe(report, err)
+		}
+	}
+
+	for i, pair := range retry {
+		// Sin tiempo restante, los pares sin intentar quedan para la próxima ronda
+		if ctx.Err() != nil {
+			logging.Debug(ctx, "Refresh deadline exceeded, postponing remaining pairs", logging.Fields{
+				"pairs": retry[i:],
+			})
+			break
+		}
+		observe(r.refreshPrices(ctx, []string{pair}))
+	}
+	return round, errors.Join(errs...)
+}
+
+// refreshPrices refresca pairs con el detalle por par si el servicio lo informa;
+// si no, un error cuenta como fallo de todos los pares
+func (r *CacheRefresher) refreshPrices(ctx context.Context, pairs []string) (interfaces.RefreshReport, error) {
+	if reporter, ok := r.priceService.(interfaces.RefreshReporter); ok {
+		return reporter.RefreshPricesReport(ctx, pairs)
+	}
+	if err := r.priceService.RefreshPrices(ctx, pairs); err != nil {
+		return interfaces.RefreshReport{Failed: pairs}, err
+	}
+	return interfaces.RefreshReport{Refreshed: pairs}, nil
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"btc-ltp-service/internal/application/services"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// startupReport acumula el informe de arranque mientras New y Start avanzan
+type startupReport struct {
+	mu     sync.Mutex
+	report interfaces.StartupReport
+}
+
+func newStartupReport(now time.Time, configWarnings []string) *startupReport {
+	return &startupReport{report: interfaces.StartupReport{
+		StartedAt:      now,
+		ConfigWarnings: configWarnings,
+	}}
+}
+
+// step registra un paso de phase que empezó en start; err es su resultado
+func (s *startupReport) step(phase, name string, start time.Time, err error) {
+	step := interfaces.StartupStep{Name: name, Phase: phase, Duration: time.Since(start)}
+	if err != nil {
+		step.Err = err.Error()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Steps = append(s.report.Steps, step)
+}
+
+// warn suma una advertencia de configuración detectada al ensamblar la aplicación
+func (s *startupReport) warn(warning string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.ConfigWarnings = append(s.report.ConfigWarnings, warning)
+}
+
+// warmup registra el resultado del warm-up; result nil indica que no hubo pares
+func (s *startupReport) warmup(result *services.WarmupResult, err error) {
+	if result == nil {
+		return
+	}
+	warmup := &interfaces.StartupWarmup{
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+		Duration:  result.Duration,
+	}
+	if err != nil {
+		warmup.Err = err.Error()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Warmup = warmup
+}
+
+// ready marca el fin del arranque en now y registra el informe una única vez
+func (s *startupReport) ready(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if !s.report.ReadyAt.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.report.ReadyAt = now
+	report := s.snapshot()
+	s.mu.Unlock()
+
+	steps := make(logging.Fields, len(report.Steps))
+	for _, step := range report.Steps {
+		steps[step.Phase+"."+step.Name+"_ms"] = step.Duration.Milliseconds()
+	}
+	fields := logging.Fields{
+		"startup_ms":      report.ReadyAt.Sub(report.StartedAt).Milliseconds(),
+		"steps":           steps,
+		"config_warnings": report.ConfigWarnings,
+	}
+	if report.Warmup != nil {
+		fields["warmup_ms"] = report.Warmup.Duration.Milliseconds()
+		fields["warmup_succeeded"] = len(report.Warmup.Succeeded)
+		fields["warmup_failed"] = len(report.Warmup.Failed)
+	}
+	logging.Info(ctx, "Service started", fields)
+}
+
+// get retorna una copia del informe
+func (s *startupReport) get() interfaces.StartupReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+// snapshot copia el informe; requiere mu
+func (s *startupReport) snapshot() interfaces.StartupReport {
+	report := s.report
+	report.Steps = slices.Clone(report.Steps)
+	report.ConfigWarnings = slices.Clone(report.ConfigWarnings)
+	return report
+}
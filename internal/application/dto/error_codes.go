@@ -0,0 +1,35 @@
+package dto
+
+// Códigos de error de la API (campo code de ErrorResponse y de PriceError)
+const (
+	CodeInvalidParameter    = "INVALID_PARAMETER"
+	CodeMissingParameter    = "MISSING_PARAMETER"
+	CodeUnknownParameter    = "UNKNOWN_PARAMETER"
+	CodeUnsupportedPair     = "UNSUPPORTED_PAIR"
+	CodeInvalidPair         = "INVALID_PAIR"
+	CodeInvalidBody         = "INVALID_BODY"
+	CodeTooManyPairs        = "TOO_MANY_PAIRS"
+	CodeBodyTooLarge        = "BODY_TOO_LARGE"
+	CodeHeadersTooLarge     = "HEADERS_TOO_LARGE"
+	CodeAPIKeyMissing       = "API_KEY_MISSING"
+	CodeAPIKeyInvalid       = "API_KEY_INVALID"
+	CodeRateLimitExceeded   = "RATE_LIMIT_EXCEEDED"
+	CodeForbidden           = "FORBIDDEN"
+	CodePairNotAllowed      = "PAIR_NOT_ALLOWED"
+	CodeNotFound            = "NOT_FOUND"
+	CodeMethodNotAllowed    = "METHOD_NOT_ALLOWED"
+	CodeUnknownFlag         = "UNKNOWN_FLAG"
+	CodeUnknownTenant       = "UNKNOWN_TENANT"
+	CodeTenantReadOnly      = "TENANT_READ_ONLY"
+	CodeTenantConflict      = "TENANT_CONFLICT"
+	CodePriceFetchError     = "PRICE_FETCH_ERROR"
+	CodePriceDeviation      = "PRICE_DEVIATION"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamRateLimited = "UPSTREAM_RATE_LIMITED"
+	CodeStaleData           = "STALE_DATA"
+	CodeCacheError          = "CACHE_ERROR"
+	CodeEncodingError       = "ENCODING_ERROR"
+	CodeInternalError       = "INTERNAL_ERROR"
+	CodeNotSupported        = "NOT_SUPPORTED"
+	CodeShuttingDown        = "SHUTTING_DOWN"
+)
@@ -39,10 +39,7 @@ func (m *PriceMapper) ToGetLTPResponse(prices []*entities.Price) *GetLTPResponse
 
 // toPriceData convierte una entidad Price a PriceData DTO
 func (m *PriceMapper) toPriceData(price *entities.Price) PriceData {
-	return PriceData{
-		Pair:   price.Pair,
-		Amount: price.Amount,
-	}
+	return NewPriceData(price)
 }
 
 // FilterPricesByPairs filtra precios basado en los pares solicitados
@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxPageLimit es el máximo de elementos por página en endpoints multi-par
+const MaxPageLimit = 1000
+
+// ListOptions agrupa el filtro por moneda cotizada y la paginación limit/offset
+// de los endpoints multi-par. El valor cero significa "sin filtro ni paginación".
+type ListOptions struct {
+	Quote  string // Moneda cotizada (ej: USD); vacío no filtra
+	Limit  int    // Elementos por página; 0 devuelve todos desde Offset
+	Offset int    // Elementos a omitir tras aplicar el filtro
+}
+
+// Pagination describe la página devuelta dentro del conjunto filtrado
+// @Description Pagination metadata for multi-pair responses
+type Pagination struct {
+	Total      int    `json:"total" example:"250"`                // Pairs matching the filter
+	Offset     int    `json:"offset" example:"0"`                 // Pairs skipped
+	Limit      int    `json:"limit,omitempty" example:"50"`       // Page size (omitted when unlimited)
+	NextOffset *int   `json:"next_offset,omitempty" example:"50"` // Offset of the next page, if any
+	Quote      string `json:"quote,omitempty" example:"USD"`      // Quote currency filter applied
+}
+
+// NewListOptions valida los query parameters limit, offset y quote
+func NewListOptions(limitParam, offsetParam, quoteParam string) (ListOptions, error) {
+	var opts ListOptions
+
+	if limitParam = strings.TrimSpace(limitParam); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return ListOptions{}, fmt.Errorf("invalid limit: %s (expected 1-%d)", limitParam, MaxPageLimit)
+		}
+		opts.Limit = limit
+	}
+
+	if offsetParam = strings.TrimSpace(offsetParam); offsetParam != "" {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return ListOptions{}, errors.New("invalid offset: " + offsetParam + " (expected a non-negative integer)")
+		}
+		opts.Offset = offset
+	}
+
+	if quoteParam = strings.ToUpper(strings.TrimSpace(quoteParam)); quoteParam != "" {
+		// Se valida como par para reutilizar las reglas y alias de activos (XBT → BTC)
+		canonical, err := entities.NormalizePair("X/" + quoteParam)
+		if err != nil {
+			return ListOptions{}, errors.New("invalid quote currency: " + quoteParam)
+		}
+		opts.Quote = canonical[strings.Index(canonical, "/")+1:]
+	}
+
+	return opts, nil
+}
+
+// IsZero indica que no se pidió filtro ni paginación
+func (o ListOptions) IsZero() bool {
+	return o == ListOptions{}
+}
+
+// ApplyToPairs filtra pairs por moneda cotizada y devuelve la página solicitada.
+// La Pagination resultante es nil si no se pidió filtro ni paginación.
+func (o ListOptions) ApplyToPairs(pairs []string) ([]string, *Pagination) {
+	if o.IsZero() {
+		return pairs, nil
+	}
+
+	filtered := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if o.matchesQuote(pair) {
+			filtered = append(filtered, pair)
+		}
+	}
+
+	start, end, page := o.window(len(filtered))
+	return filtered[start:end], page
+}
+
+// ApplyToPrices es el equivalente de ApplyToPairs para precios ya obtenidos
+func (o ListOptions) ApplyToPrices(prices []*entities.Price) ([]*entities.Price, *Pagination) {
+	if o.IsZero() {
+		return prices, nil
+	}
+
+	filtered := make([]*entities.Price, 0, len(prices))
+	for _, price := range prices {
+		if o.matchesQuote(price.Pair) {
+			filtered = append(filtered, price)
+		}
+	}
+
+	start, end, page := o.window(len(filtered))
+	return filtered[start:end], page
+}
+
+func (o ListOptions) matchesQuote(pair string) bool {
+	if o.Quote == "" {
+		return true
+	}
+	_, quote, ok := strings.Cut(pair, "/")
+	return ok && quote == o.Quote
+}
+
+// window calcula los límites de la página dentro de total elementos filtrados
+func (o ListOptions) window(total int) (int, int, *Pagination) {
+	start := min(o.Offset, total)
+	end := total
+	if o.Limit > 0 {
+		end = min(start+o.Limit, total)
+	}
+
+	page := &Pagination{Total: total, Offset: o.Offset, Limit: o.Limit, Quote: o.Quote}
+	if end < total {
+		next := end
+		page.NextOffset = &next
+	}
+	return start, end, page
+}
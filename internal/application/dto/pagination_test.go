@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListOptions(t *testing.T) {
+	opts, err := NewListOptions("", "", "")
+	require.NoError(t, err)
+	assert.True(t, opts.IsZero())
+
+	opts, err = NewListOptions("10", "20", " usd ")
+	require.NoError(t, err)
+	assert.Equal(t, ListOptions{Quote: "USD", Limit: 10, Offset: 20}, opts)
+
+	opts, err = NewListOptions("", "", "xbt")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC", opts.Quote, "asset aliases are normalized")
+
+	for _, tc := range []struct{ limit, offset, quote string }{
+		{limit: "0"},
+		{limit: "-1"},
+		{limit: "1001"},
+		{limit: "ten"},
+		{offset: "-5"},
+		{quote: "US$"},
+	} {
+		_, err := NewListOptions(tc.limit, tc.offset, tc.quote)
+		assert.Error(t, err, "limit=%q offset=%q quote=%q", tc.limit, tc.offset, tc.quote)
+	}
+}
+
+func TestListOptions_ApplyToPairs(t *testing.T) {
+	pairs := []string{"BTC/USD", "ETH/USD", "BTC/EUR", "LTC/USD", "XRP/USD"}
+
+	all, page := ListOptions{}.ApplyToPairs(pairs)
+	assert.Equal(t, pairs, all)
+	assert.Nil(t, page, "no pagination metadata when nothing was requested")
+
+	got, page := ListOptions{Quote: "USD", Limit: 2}.ApplyToPairs(pairs)
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, got)
+	require.NotNil(t, page.NextOffset)
+	assert.Equal(t, 2, *page.NextOffset)
+	assert.Equal(t, 4, page.Total)
+
+	got, page = ListOptions{Quote: "USD", Limit: 2, Offset: 2}.ApplyToPairs(pairs)
+	assert.Equal(t, []string{"LTC/USD", "XRP/USD"}, got)
+	assert.Nil(t, page.NextOffset, "last page")
+
+	got, page = ListOptions{Offset: 10}.ApplyToPairs(pairs)
+	assert.Empty(t, got)
+	assert.Equal(t, 5, page.Total)
+}
+
+func TestListOptions_ApplyToPrices(t *testing.T) {
+	prices := []*entities.Price{{Pair: "BTC/USD"}, {Pair: "BTC/EUR"}, {Pair: "ETH/EUR"}}
+
+	got, page := ListOptions{Quote: "EUR", Offset: 1}.ApplyToPrices(prices)
+	require.Len(t, got, 1)
+	assert.Equal(t, "ETH/EUR", got[0].Pair)
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, "EUR", page.Quote)
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Modos de ajuste de decimales
+const (
+	PriceFormatRound    = "round"
+	PriceFormatTruncate = "truncate"
+)
+
+// PriceFormat aplica las reglas de presentación por moneda cotizada (p. ej. JPY 0
+// decimales, USD 2, BTC 8) al serializar precios. Las cotizaciones sin regla se
+// devuelven sin cambios; un *PriceFormat nil no modifica nada.
+type PriceFormat struct {
+	truncate bool
+	decimals map[string]int
+}
+
+// NewPriceFormat crea las reglas; mode es round o truncate y las claves de
+// quoteDecimals se normalizan a mayúsculas
+func NewPriceFormat(mode string, quoteDecimals map[string]int) *PriceFormat {
+	format := &PriceFormat{
+		truncate: strings.EqualFold(mode, PriceFormatTruncate),
+		decimals: make(map[string]int, len(quoteDecimals)),
+	}
+	for quote, decimals := range quoteDecimals {
+		format.decimals[strings.ToUpper(quote)] = decimals
+	}
+	return format
+}
+
+// Amount ajusta amount a los decimales de la moneda cotizada de pair
+func (f *PriceFormat) Amount(pair string, amount float64) float64 {
+	if f == nil {
+		return amount
+	}
+	_, quote, ok := entities.SplitPair(pair)
+	if !ok {
+		return amount
+	}
+	decimals, ok := f.decimals[quote]
+	if !ok {
+		return amount
+	}
+	return f.adjust(amount, decimals)
+}
+
+// adjust opera sobre la representación decimal más corta de amount para que 0.29
+// truncado a 2 decimales siga siendo 0.29 (y no 0.28 por el error binario de
+// 0.29*100); el redondeo es half-up, como se leen los precios
+func (f *PriceFormat) adjust(amount float64, decimals int) float64 {
+	text := strconv.FormatFloat(amount, 'f', -1, 64)
+	dot := strings.IndexByte(text, '.')
+	if dot < 0 || len(text)-dot-1 <= decimals {
+		return amount
+	}
+	roundUp := !f.truncate && text[dot+1+decimals] >= '5'
+	adjusted, _ := strconv.ParseFloat(text[:dot+1+decimals], 64)
+	if roundUp {
+		adjusted, _ = strconv.ParseFloat(strconv.FormatFloat(adjusted+math.Copysign(math.Pow10(-decimals), amount), 'f', decimals, 64), 64)
+	}
+	return adjusted
+}
+
+// Apply ajusta en el lugar el importe, el bid y el ask de cada precio
+func (f *PriceFormat) Apply(prices []PriceData) {
+	if f == nil {
+		return
+	}
+	for i := range prices {
+		f.apply(&prices[i])
+	}
+}
+
+// Data convierte price a su representación de respuesta con los importes ajustados
+func (f *PriceFormat) Data(price *entities.Price) PriceData {
+	data := NewPriceData(price)
+	if f != nil {
+		f.apply(&data)
+	}
+	return data
+}
+
+func (f *PriceFormat) apply(data *PriceData) {
+	data.Amount = f.Amount(data.Pair, data.Amount)
+	if data.Exchange != nil {
+		data.Exchange.Bid = f.Amount(data.Pair, data.Exchange.Bid)
+		data.Exchange.Ask = f.Amount(data.Pair, data.Exchange.Ask)
+	}
+}
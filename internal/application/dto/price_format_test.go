@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceFormat_Amount(t *testing.T) {
+	decimals := map[string]int{"usd": 2, "JPY": 0, "BTC": 8}
+	round := NewPriceFormat(PriceFormatRound, decimals)
+	truncate := NewPriceFormat(PriceFormatTruncate, decimals)
+
+	assert.Equal(t, 45123.46, round.Amount("BTC/USD", 45123.456))
+	assert.Equal(t, 45123.45, truncate.Amount("BTC/USD", 45123.456))
+	assert.Equal(t, 0.29, truncate.Amount("XRP/USD", 0.29), "truncation works on the decimal representation")
+	assert.Equal(t, 7000001.0, round.Amount("BTC/JPY", 7000000.5))
+	assert.Equal(t, 7000000.0, truncate.Amount("BTC/JPY", 7000000.9))
+	assert.Equal(t, 0.05123457, round.Amount("ETH/BTC", 0.051234567))
+	assert.Equal(t, 1.23456, round.Amount("ETH/EUR", 1.23456), "quotes without a rule are untouched")
+	assert.Equal(t, 1.5, round.Amount("XBTUSD", 1.5), "the quote of legacy pair names is resolved")
+}
+
+func TestPriceFormat_Apply(t *testing.T) {
+	prices := []PriceData{{Pair: "BTC/USD", Amount: 50000.129}, {Pair: "BTC/JPY", Amount: 7000000.4}}
+
+	var none *PriceFormat
+	none.Apply(prices)
+	assert.Equal(t, 50000.129, prices[0].Amount, "a nil format serves raw prices")
+
+	NewPriceFormat(PriceFormatRound, map[string]int{"USD": 2, "JPY": 0}).Apply(prices)
+	assert.Equal(t, 50000.13, prices[0].Amount)
+	assert.Equal(t, 7000000.0, prices[1].Amount)
+}
+
+func TestPriceFormat_Data(t *testing.T) {
+	price := &entities.Price{Pair: "BTC/USD", Amount: 50000.129, Exchange: entities.ExchangeKraken, Source: entities.PriceSourceWebSocket, Channel: "ticker", Bid: 50000.124, Ask: 50000.136}
+
+	data := NewPriceFormat(PriceFormatRound, map[string]int{"USD": 2}).Data(price)
+	assert.Equal(t, 50000.13, data.Amount)
+	assert.Equal(t, &ExchangeData{Name: "kraken", Source: "websocket", Channel: "ticker", Bid: 50000.12, Ask: 50000.14}, data.Exchange)
+
+	var none *PriceFormat
+	assert.Equal(t, NewPriceData(price), none.Data(price))
+	assert.Nil(t, NewPriceData(&entities.Price{Pair: "BTC/USD", Amount: 1}).Exchange, "prices without exchange metadata omit it")
+}
@@ -1,60 +1,169 @@
 package dto
 
 import (
+	"btc-ltp-service/internal/domain/entities"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 )
 
 // GetLTPRequest representa la request para obtener Last Traded Prices
 type GetLTPRequest struct {
 	// Pairs es la lista de pares solicitados (ej: "BTC/USD,ETH/USD" o "BTC/USD")
 	Pairs []string `json:"pairs"`
+	// Wildcard indica que se pidieron todos los pares (pair=* o sin parámetro)
+	Wildcard bool `json:"-"`
+	// MaxAge es la edad máxima aceptada por par (forma canónica); WildcardPair fija
+	// la de los pares no listados. Sin entrada no hay requisito de frescura.
+	MaxAge map[string]time.Duration `json:"-"`
 }
 
+// WildcardPair es el valor de pair que solicita todos los pares soportados
+const WildcardPair = "*"
+
 // NewGetLTPRequest crea una nueva request desde query parameters
-// Si pairsParam está vacío, usa defaultPairs como fallback
+// Si pairsParam está vacío o es "*", usa supportedPairs y marca la request como Wildcard
 // VALIDA que todos los pares solicitados estén en la lista de pares soportados
 func NewGetLTPRequest(pairsParam string, supportedPairs []string) (*GetLTPRequest, error) {
-	// Si no se proporciona el parámetro, usar pares soportados como fallback
-	if pairsParam == "" {
+	// Sin parámetro o con wildcard se solicitan todos los pares soportados
+	if trimmed := strings.TrimSpace(pairsParam); trimmed == "" || trimmed == WildcardPair {
 		if len(supportedPairs) == 0 {
 			return nil, errors.New("no supported pairs configured")
 		}
 		return &GetLTPRequest{
-			Pairs: supportedPairs,
+			Pairs:    supportedPairs,
+			Wildcard: true,
 		}, nil
 	}
 
-	// Crear mapa de pares soportados para búsqueda eficiente
+	return newLTPRequestFromPairs(strings.Split(pairsParam, ","), supportedPairs)
+}
+
+// PostLTPRequest es el cuerpo JSON de POST /api/v1/ltp para consultas masivas
+// @Description Bulk price query body
+type PostLTPRequest struct {
+	Pairs  []string          `json:"pairs" example:"BTC/USD,ETH/USD" validate:"required"` // Trading pairs to query
+	MaxAge map[string]string `json:"max_age,omitempty"`                                   // Maximum price age per pair ("1s", "60s"); "*" applies to the other pairs
+}
+
+// MinPairMaxAge es el menor max_age aceptado por POST /api/v1/ltp; evita que una
+// request fuerce una consulta al exchange por cada par en cada llamada
+const MinPairMaxAge = time.Second
+
+// StreamCommandResync pide un snapshot nuevo por /api/v1/ws (p. ej. tras un hueco en seq)
+const StreamCommandResync = "resync"
+
+// StreamCommand es un mensaje del cliente por /api/v1/ws
+// @Description WebSocket client message
+type StreamCommand struct {
+	Type string `json:"type" example:"resync" validate:"required"` // resync
+}
+
+// FeatureFlagOverrideRequest es el cuerpo de PUT /api/v1/admin/flags/{name}
+// @Description Feature flag override
+type FeatureFlagOverrideRequest struct {
+	Enabled *bool `json:"enabled" example:"true" validate:"required"` // New state for the flag
+}
+
+// TenantRequest es el cuerpo de PUT /api/v1/admin/tenants/{name}
+// @Description Tenant definition
+type TenantRequest struct {
+	APIKeys   []string             `json:"api_keys" example:"team-a-key" validate:"required"` // API keys of the tenant (stored hashed, never returned)
+	Pairs     []string             `json:"pairs,omitempty" example:"BTC/USD,ETH/USD"`         // Allowed pairs; empty allows every supported pair
+	RateLimit *TenantRateLimitData `json:"rate_limit,omitempty"`                              // Tenant-wide token bucket; omitted = no tenant limit
+}
+
+// ErrTooManyPairs indica que la request supera el máximo de pares configurado
+var ErrTooManyPairs = errors.New("too many pairs requested")
+
+// NewPostLTPRequest valida el cuerpo de una consulta masiva: al menos un par, como
+// máximo maxPairs y todos soportados. A diferencia de GET, no hay fallback a
+// todos los pares soportados.
+func NewPostLTPRequest(body PostLTPRequest, supportedPairs []string, maxPairs int) (*GetLTPRequest, error) {
+	if len(body.Pairs) == 0 {
+		return nil, errors.New("pairs cannot be empty")
+	}
+	if maxPairs > 0 && len(body.Pairs) > maxPairs {
+		return nil, fmt.Errorf("%w: %d, max %d", ErrTooManyPairs, len(body.Pairs), maxPairs)
+	}
+	request, err := newLTPRequestFromPairs(body.Pairs, supportedPairs)
+	if err != nil {
+		return nil, err
+	}
+	if request.MaxAge, err = parsePairMaxAges(body.MaxAge, request.Pairs); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// parsePairMaxAges valida los max_age de una consulta masiva: duraciones de al
+// menos MinPairMaxAge para pares incluidos en pairs o para WildcardPair
+func parsePairMaxAges(raw map[string]string, pairs []string) (map[string]time.Duration, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	maxAges := make(map[string]time.Duration, len(raw))
+	for pair, value := range raw {
+		key := WildcardPair
+		if strings.TrimSpace(pair) != WildcardPair {
+			canonical, err := entities.NormalizePair(strings.TrimSpace(pair))
+			if err != nil {
+				return nil, errors.New("invalid pair format in max_age: " + pair + " (expected BASE/QUOTE)")
+			}
+			if !slices.Contains(pairs, canonical) {
+				return nil, errors.New("max_age set for a pair not requested: " + canonical)
+			}
+			key = canonical
+		}
+		maxAge, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_age for %s: %q (expected a duration such as 1s or 500ms)", pair, value)
+		}
+		if maxAge < MinPairMaxAge {
+			return nil, fmt.Errorf("max_age for %s must be at least %s", pair, MinPairMaxAge)
+		}
+		maxAges[key] = maxAge
+	}
+	return maxAges, nil
+}
+
+// MaxAgeFor retorna la edad máxima aceptada para pair, o 0 si no se pidió ninguna
+func (r *GetLTPRequest) MaxAgeFor(pair string) time.Duration {
+	if maxAge, ok := r.MaxAge[entities.CanonicalPair(pair)]; ok {
+		return maxAge
+	}
+	return r.MaxAge[WildcardPair]
+}
+
+// newLTPRequestFromPairs normaliza pairsList y valida que todos estén soportados
+func newLTPRequestFromPairs(pairsList []string, supportedPairs []string) (*GetLTPRequest, error) {
+	// Crear mapa de pares soportados (forma canónica) para búsqueda eficiente
 	supportedMap := make(map[string]bool)
 	for _, supportedPair := range supportedPairs {
-		supportedMap[strings.ToUpper(supportedPair)] = true
+		supportedMap[entities.CanonicalPair(supportedPair)] = true
 	}
 
-	// Split por comas y limpiar espacios
-	pairsList := strings.Split(pairsParam, ",")
 	var cleanPairs []string
-
 	for _, pair := range pairsList {
 		pair = strings.TrimSpace(pair)
 		if pair == "" {
 			continue
 		}
 
-		// Validación básica del formato (debe tener /)
-		if !strings.Contains(pair, "/") {
+		// Normalizar alias (XBTUSD, BTC-USD, btc_usd) a la forma canónica BASE/QUOTE
+		canonical, err := entities.NormalizePair(pair)
+		if err != nil {
 			return nil, errors.New("invalid pair format: " + pair + " (expected BASE/QUOTE)")
 		}
 
-		// Normalizar a mayúsculas
-		pair = strings.ToUpper(pair)
-
 		// VALIDACIÓN CRÍTICA: Verificar que el par esté soportado
-		if !supportedMap[pair] {
-			return nil, errors.New("unsupported pair: " + pair + " (supported pairs: " + strings.Join(supportedPairs, ",") + ")")
+		if !supportedMap[canonical] {
+			return nil, errors.New("unsupported pair: " + canonical + " (supported pairs: " + strings.Join(supportedPairs, ",") + ")")
 		}
 
-		cleanPairs = append(cleanPairs, pair)
+		cleanPairs = append(cleanPairs, canonical)
 	}
 
 	if len(cleanPairs) == 0 {
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPostLTPRequest_MaxAge(t *testing.T) {
+	supported := []string{"BTC/USD", "ETH/USD", "XRP/EUR"}
+
+	request, err := NewPostLTPRequest(PostLTPRequest{Pairs: []string{"BTC/USD", "XRP/EUR"}}, supported, 0)
+	require.NoError(t, err)
+	assert.Zero(t, request.MaxAgeFor("BTC/USD"), "without max_age there is no freshness requirement")
+
+	request, err = NewPostLTPRequest(PostLTPRequest{
+		Pairs:  []string{"XBTUSD", "ETH/USD", "XRP/EUR"},
+		MaxAge: map[string]string{"btc-usd": "1s", "*": "60s"},
+	}, supported, 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, request.MaxAgeFor("BTC/USD"), "pair aliases are normalized")
+	assert.Equal(t, time.Minute, request.MaxAgeFor("XRP/EUR"), "the wildcard applies to the other pairs")
+	assert.Equal(t, time.Minute, request.MaxAgeFor("ETH/USD"))
+
+	for name, maxAge := range map[string]map[string]string{
+		"not a duration":      {"BTC/USD": "soon"},
+		"below the minimum":   {"BTC/USD": "500ms"},
+		"negative":            {"BTC/USD": "-1s"},
+		"pair not requested":  {"ETH/USD": "1s"},
+		"invalid pair format": {"BTCUSDT!": "1s"},
+	} {
+		_, err := NewPostLTPRequest(PostLTPRequest{Pairs: []string{"BTC/USD"}, MaxAge: maxAge}, supported, 0)
+		assert.Error(t, err, name)
+	}
+}
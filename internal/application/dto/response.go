@@ -2,14 +2,72 @@ package dto
 
 import (
 	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"sort"
 	"time"
 )
 
 // PriceData represents an individual price in the response
 // @Description Last traded price data for a cryptocurrency pair
 type PriceData struct {
-	Pair   string  `json:"pair" example:"BTC/USD" validate:"required"`          // Trading pair (e.g., BTC/USD)
-	Amount float64 `json:"amount" example:"45123.45" validate:"required,min=0"` // Price in the quoted currency
+	Pair         string        `json:"pair" example:"BTC/USD" validate:"required"`                 // Trading pair (e.g., BTC/USD)
+	Amount       float64       `json:"amount" example:"45123.45" validate:"required,min=0"`        // Price in the quoted currency
+	ExchangeTime *time.Time    `json:"exchange_time,omitempty" example:"2023-12-01T10:30:00Z"`     // Time reported by the exchange, when available
+	ReceivedTime *time.Time    `json:"received_time,omitempty" example:"2023-12-01T10:30:00.250Z"` // Time the service received the price
+	Exchange     *ExchangeData `json:"exchange,omitempty"`                                         // Where the price came from, when known
+}
+
+// ExchangeData describes the exchange feed a price came from
+type ExchangeData struct {
+	Name    string  `json:"name" example:"kraken"`                // Exchange that reported the price
+	Source  string  `json:"source,omitempty" example:"websocket"` // websocket, rest or mock
+	Channel string  `json:"channel,omitempty" example:"ticker"`   // WebSocket channel or REST endpoint
+	Bid     float64 `json:"bid,omitempty" example:"45123.4"`      // Best bid in the ticker, when known
+	Ask     float64 `json:"ask,omitempty" example:"45123.5"`      // Best ask in the ticker, when known
+}
+
+// NewPriceData converts a domain price into its response representation
+func NewPriceData(price *entities.Price) PriceData {
+	data := PriceData{
+		Pair:   price.Pair,
+		Amount: price.Amount,
+	}
+	if price.Exchange != "" {
+		data.Exchange = &ExchangeData{
+			Name:    price.Exchange,
+			Source:  price.Source,
+			Channel: price.Channel,
+			Bid:     price.Bid,
+			Ask:     price.Ask,
+		}
+	}
+	if !price.ExchangeTime.IsZero() {
+		exchangeTime := price.ExchangeTime.UTC()
+		data.ExchangeTime = &exchangeTime
+	}
+	if !price.ReceivedTime.IsZero() {
+		receivedTime := price.ReceivedTime.UTC()
+		data.ReceivedTime = &receivedTime
+	}
+	return data
+}
+
+// Tipos de mensaje del protocolo de /api/v1/ws
+const (
+	StreamMessageSnapshot = "snapshot"
+	StreamMessageDiff     = "diff"
+	StreamMessageError    = "error"
+)
+
+// StreamMessage is a server message of the /api/v1/ws protocol: a snapshot with
+// every subscribed pair, a diff with only the pairs that changed, or an error
+// @Description WebSocket price feed message
+type StreamMessage struct {
+	Type    string      `json:"type" example:"diff"`                                 // snapshot, diff or error
+	Seq     uint64      `json:"seq,omitempty" example:"42"`                          // Per-connection sequence number of snapshots and diffs; a gap means a message was lost
+	Prices  []PriceData `json:"prices,omitempty"`                                    // Snapshot: every cached subscribed pair; diff: the pairs that changed
+	Code    string      `json:"code,omitempty" example:"INVALID_BODY"`               // Error code (error messages only)
+	Message string      `json:"message,omitempty" example:"unknown message type: x"` // Error description (error messages only)
 }
 
 // PriceError represents an error for a specific pair
@@ -24,8 +82,9 @@ type PriceError struct {
 // GetLTPResponse represents the response from /api/v1/ltp endpoint
 // @Description Main response with last traded prices
 type GetLTPResponse struct {
-	LTP    []PriceData  `json:"ltp" validate:"required"` // List of successfully retrieved prices
-	Errors []PriceError `json:"errors,omitempty"`        // Errors for specific pairs (optional)
+	LTP        []PriceData  `json:"ltp" validate:"required"` // List of successfully retrieved prices
+	Errors     []PriceError `json:"errors,omitempty"`        // Errors for specific pairs (optional)
+	Pagination *Pagination  `json:"pagination,omitempty"`    // Present when limit, offset or quote are used
 }
 
 // GetLTPPartialResponse represents a response with partial successes and errors
@@ -42,12 +101,16 @@ type ResponseStats struct {
 	Failed    int `json:"failed"`
 }
 
-// ErrorResponse represents a standard error response for endpoints
-// @Description Standard error response for endpoints
+// ErrorResponse is the body of every 4xx/5xx API response. Code is the
+// machine-readable error (see the Code* constants); Error repeats it for clients
+// written before the standard schema.
+// @Description Standard error response for every 4xx/5xx API response (see apierror.Problem for application/problem+json)
 type ErrorResponse struct {
-	Error   string `json:"error" example:"INVALID_PARAMETER" validate:"required"`                  // Main error message
-	Message string `json:"message,omitempty" example:"The provided trading pair is not supported"` // Detailed error description
-	Code    string `json:"code,omitempty" example:"400"`                                           // HTTP error code or internal code
+	Code      string         `json:"code" example:"INVALID_PARAMETER" validate:"required"`         // Machine-readable error code
+	Message   string         `json:"message" example:"The provided trading pair is not supported"` // Human-readable description
+	Details   map[string]any `json:"details,omitempty" swaggertype:"object"`                       // Error-specific context (parameter, limits, ...)
+	RequestID string         `json:"request_id,omitempty" example:"req_1704067200123456_a1b2c3d4"` // X-Request-ID of the failed request
+	Error     string         `json:"error" example:"INVALID_PARAMETER"`                            // Deprecated: same as code
 }
 
 // HealthResponse represents the health check response with service status
@@ -58,15 +121,462 @@ type HealthResponse struct {
 	Services  map[string]string `json:"services,omitempty" example:"cache:healthy,exchange:healthy"`                     // Individual service statuses
 }
 
+// CacheInvalidationResponse reports the result of an administrative cache invalidation
+// @Description Result of an administrative cache invalidation
+type CacheInvalidationResponse struct {
+	Scope   string   `json:"scope" example:"pairs" validate:"required" enums:"pairs,all"` // What was invalidated
+	Pairs   []string `json:"pairs,omitempty" example:"BTC/USD,ETH/USD"`                   // Invalidated pairs (scope "pairs")
+	Removed *int     `json:"removed,omitempty" example:"3"`                               // Removed cache entries (scope "all")
+	Message string   `json:"message" example:"Cached prices invalidated"`                 // Human readable summary
+}
+
+// ReconnectResponse reports the result of a forced exchange reconnection
+// @Description Result of a forced exchange reconnection
+type ReconnectResponse struct {
+	Connected bool   `json:"connected" example:"true"`               // Whether the streaming connection is up after the attempt
+	Message   string `json:"message" example:"Exchange reconnected"` // Human readable summary
+}
+
+// DrainResponse reports the instance draining state
+// @Description Draining state after POST /admin/drain
+type DrainResponse struct {
+	Draining    bool   `json:"draining" example:"true"`                                        // Always true once the endpoint was called
+	OpenStreams int    `json:"open_streams" example:"3"`                                       // SSE and WebSocket streams still open
+	Message     string `json:"message" example:"Instance draining, not ready for new traffic"` // Human readable summary
+}
+
+// FeatureFlagData is the state of a single feature flag
+// @Description Feature flag state
+type FeatureFlagData struct {
+	Name       string `json:"name" example:"hedged_requests"` // Flag name
+	Enabled    bool   `json:"enabled" example:"true"`         // Effective state
+	Default    bool   `json:"default" example:"false"`        // State declared in configuration
+	Overridden bool   `json:"overridden" example:"true"`      // Whether a runtime override is active
+}
+
+// FeatureFlagsResponse lists the declared feature flags
+// @Description Declared feature flags and their effective state
+type FeatureFlagsResponse struct {
+	OverridesEnabled bool              `json:"overrides_enabled" example:"true"` // Whether flags can be overridden at runtime
+	Flags            []FeatureFlagData `json:"flags"`                            // Flags sorted by name
+}
+
+// NewFeatureFlagsResponse creates the response from a flags snapshot
+func NewFeatureFlagsResponse(states []interfaces.FeatureFlagState, overridesEnabled bool) *FeatureFlagsResponse {
+	flags := make([]FeatureFlagData, len(states))
+	for i, state := range states {
+		flags[i] = FeatureFlagData{
+			Name:       state.Name,
+			Enabled:    state.Enabled,
+			Default:    state.Default,
+			Overridden: state.Overridden,
+		}
+	}
+	return &FeatureFlagsResponse{OverridesEnabled: overridesEnabled, Flags: flags}
+}
+
+// TenantRateLimitData is the token bucket shared by every client of a tenant
+// @Description Tenant rate limit
+type TenantRateLimitData struct {
+	Capacity   int `json:"capacity" example:"50"`   // Maximum burst
+	RefillRate int `json:"refill_rate" example:"5"` // Tokens added per second
+}
+
+// TenantData describes a tenant without its API keys
+// @Description Tenant state
+type TenantData struct {
+	Name        string               `json:"name" example:"team-a"`           // Tenant name
+	Source      string               `json:"source" example:"config"`         // config (read-only) or admin (managed at runtime)
+	Pairs       []string             `json:"pairs" example:"BTC/USD,ETH/USD"` // Allowed pairs; empty allows every supported pair
+	APIKeyCount int                  `json:"api_key_count" example:"2"`       // Number of API keys
+	RateLimit   *TenantRateLimitData `json:"rate_limit,omitempty"`            // Tenant-wide rate limit, if any
+}
+
+// TenantsResponse lists the tenants of the deployment
+// @Description Tenants and whether they can be managed at runtime
+type TenantsResponse struct {
+	ManagementEnabled bool         `json:"management_enabled" example:"true"` // Whether tenants can be created or deleted via the API
+	Tenants           []TenantData `json:"tenants"`                           // Tenants sorted by name
+}
+
+// NewTenantsResponse creates the response from the tenant states
+func NewTenantsResponse(states []interfaces.TenantState, managementEnabled bool) *TenantsResponse {
+	tenants := make([]TenantData, len(states))
+	for i, state := range states {
+		pairs := state.Tenant.Pairs
+		if pairs == nil {
+			pairs = []string{}
+		}
+		tenants[i] = TenantData{
+			Name:        state.Tenant.Name,
+			Source:      state.Source,
+			Pairs:       pairs,
+			APIKeyCount: state.KeyCount,
+		}
+		if state.Tenant.RateLimitCapacity > 0 {
+			tenants[i].RateLimit = &TenantRateLimitData{
+				Capacity:   state.Tenant.RateLimitCapacity,
+				RefillRate: state.Tenant.RateLimitRefillRate,
+			}
+		}
+	}
+	return &TenantsResponse{ManagementEnabled: managementEnabled, Tenants: tenants}
+}
+
+// UsageRecordData is the usage of one API key during one UTC day
+// @Description Daily usage of an API key
+type UsageRecordData struct {
+	Date           string `json:"date" example:"2026-10-14"`         // UTC day
+	Tenant         string `json:"tenant,omitempty" example:"team-a"` // Tenant of the key; omitted for the operator key
+	KeyID          string `json:"key_id" example:"3f2a9c1b7d4e"`     // First 12 hex digits of the SHA-256 of the API key
+	Requests       int64  `json:"requests" example:"1520"`           // API requests
+	StreamMessages int64  `json:"stream_messages" example:"86400"`   // Price events sent by /ltp/stream
+}
+
+// UsageTotalData is the usage of a tenant over the whole period
+// @Description Usage totals of a tenant
+type UsageTotalData struct {
+	Tenant         string `json:"tenant,omitempty" example:"team-a"` // Tenant; omitted for the operator key
+	Requests       int64  `json:"requests" example:"10640"`          // API requests
+	StreamMessages int64  `json:"stream_messages" example:"604800"`  // Price events sent by /ltp/stream
+}
+
+// UsageResponse reports API usage per key and day for billing/chargeback
+// @Description Usage per API key and UTC day, aggregated across replicas
+type UsageResponse struct {
+	From    string            `json:"from" example:"2026-10-08"` // First UTC day (inclusive)
+	To      string            `json:"to" example:"2026-10-14"`   // Last UTC day (inclusive)
+	Records []UsageRecordData `json:"records"`                   // Sorted by date, tenant and key
+	Totals  []UsageTotalData  `json:"totals"`                    // Per tenant, sorted by tenant
+}
+
+// NewUsageResponse creates the response from the usage records of the period
+func NewUsageResponse(from, to string, records []interfaces.UsageRecord) *UsageResponse {
+	response := &UsageResponse{From: from, To: to, Records: make([]UsageRecordData, len(records)), Totals: []UsageTotalData{}}
+	totals := make(map[string]int)
+	for i, record := range records {
+		response.Records[i] = UsageRecordData{
+			Date:           record.Date,
+			Tenant:         record.Tenant,
+			KeyID:          record.KeyID,
+			Requests:       record.Requests,
+			StreamMessages: record.StreamMessages,
+		}
+		index, ok := totals[record.Tenant]
+		if !ok {
+			index = len(response.Totals)
+			totals[record.Tenant] = index
+			response.Totals = append(response.Totals, UsageTotalData{Tenant: record.Tenant})
+		}
+		response.Totals[index].Requests += record.Requests
+		response.Totals[index].StreamMessages += record.StreamMessages
+	}
+	sort.Slice(response.Totals, func(i, j int) bool { return response.Totals[i].Tenant < response.Totals[j].Tenant })
+	return response
+}
+
+// AuditEventsResponse lists persisted fallback activations and reconnects
+// @Description Audit trail of REST fallback activations and WebSocket reconnects, newest first
+type AuditEventsResponse struct {
+	RetentionDays int              `json:"retention_days" example:"30"` // Days of events kept
+	Events        []AuditEventData `json:"events"`                      // Matching events, newest first
+}
+
+// AuditEventData is one persisted fallback activation or reconnect
+// @Description REST fallback activation or WebSocket reconnect
+type AuditEventData struct {
+	ID         string    `json:"id" example:"20231201T103000.000000000Z/api-1/42"`             // Event identifier
+	Type       string    `json:"type" example:"fallback" enums:"fallback,reconnect"`           // fallback or reconnect
+	Time       time.Time `json:"time" example:"2023-12-01T10:30:00Z"`                          // When the fallback started or the connection was lost
+	Instance   string    `json:"instance" example:"api-1"`                                     // Replica that recorded the event
+	Reason     string    `json:"reason" example:"timeout"`                                     // Why the fallback was activated or the connection was lost
+	DurationMs int64     `json:"duration_ms" example:"120"`                                    // REST query for fallbacks; time until reconnected (or failed) for reconnects
+	Pairs      []string  `json:"pairs" example:"BTC/USD"`                                      // Affected pairs
+	Success    bool      `json:"success" example:"true"`                                       // Whether REST answered or the connection was restored
+	Error      string    `json:"error,omitempty"`                                              // Why it failed
+	Shard      string    `json:"shard,omitempty" example:"0"`                                  // WebSocket connection (reconnects)
+	Attempt    int       `json:"attempt,omitempty" example:"1"`                                // Reconnection attempt (reconnects)
+	RequestID  string    `json:"request_id,omitempty" example:"req_1704067200123456_a1b2c3d4"` // Request that triggered the fallback
+}
+
+// AdminStatusResponse summarizes the service state for operators (admin dashboard)
+// @Description Exchange connection, cache contents and recent REST fallbacks
+type AdminStatusResponse struct {
+	Timestamp       time.Time           `json:"timestamp" example:"2023-12-01T10:30:00Z"` // When the status was taken
+	Exchange        ExchangeStatusData  `json:"exchange"`                                 // Upstream connection
+	Cache           CacheStatsData      `json:"cache"`                                    // Cached prices
+	RecentFallbacks []FallbackEventData `json:"recent_fallbacks"`                         // Latest REST fallbacks, newest first
+}
+
+// ExchangeStatusData reports the upstream connection
+// @Description Upstream exchange connection state
+type ExchangeStatusData struct {
+	Streaming   bool                   `json:"streaming" example:"true"` // Whether the exchange keeps a streaming (WebSocket) connection
+	Connected   bool                   `json:"connected" example:"true"` // Whether every streaming connection is up
+	Connections []ConnectionStatusData `json:"connections,omitempty"`    // Streaming connections the subscriptions are sharded across
+}
+
+// ConnectionStatusData reports one streaming connection (shard)
+// @Description Streaming connection (shard) state
+type ConnectionStatusData struct {
+	ID                string `json:"id" example:"0"`                 // Shard identifier
+	Pairs             int    `json:"pairs" example:"100"`            // Pairs assigned to the connection
+	Connected         bool   `json:"connected" example:"true"`       // Whether the connection is up
+	Reconnecting      bool   `json:"reconnecting" example:"false"`   // Whether a reconnection is scheduled
+	ReconnectAttempts int    `json:"reconnect_attempts" example:"0"` // Reconnection attempts since the connection was lost
+	Confirmed         int    `json:"confirmed" example:"100"`        // Subscriptions confirmed by the exchange
+	Pending           int    `json:"pending" example:"0"`            // Subscriptions sent and not yet confirmed
+}
+
+// NewConnectionStatusData converts the connection states of a pool
+func NewConnectionStatusData(connections []interfaces.ConnectionStatus) []ConnectionStatusData {
+	data := make([]ConnectionStatusData, len(connections))
+	for i, connection := range connections {
+		data[i] = ConnectionStatusData{
+			ID:                connection.ID,
+			Pairs:             connection.Pairs,
+			Connected:         connection.Connected,
+			Reconnecting:      connection.Reconnecting,
+			ReconnectAttempts: connection.ReconnectAttempts,
+			Confirmed:         connection.Confirmed,
+			Pending:           connection.Pending,
+		}
+	}
+	return data
+}
+
+// CacheStatsData summarizes the cached prices
+// @Description Cached prices summary
+type CacheStatsData struct {
+	Entries          int      `json:"entries" example:"3"`               // Cached prices
+	SupportedPairs   int      `json:"supported_pairs" example:"4"`       // Configured supported pairs
+	MissingPairs     []string `json:"missing_pairs" example:"ETH/EUR"`   // Supported pairs without a cached price
+	OldestAgeSeconds float64  `json:"oldest_age_seconds" example:"12.5"` // Age of the oldest cached price
+	NewestAgeSeconds float64  `json:"newest_age_seconds" example:"0.3"`  // Age of the newest cached price
+}
+
+// FallbackEventData is a REST fallback activation
+// @Description REST fallback activation
+type FallbackEventData struct {
+	Time       time.Time `json:"time" example:"2023-12-01T10:30:00Z"`                          // When the fallback started
+	Pairs      []string  `json:"pairs" example:"BTC/USD"`                                      // Pairs requested via REST
+	Reason     string    `json:"reason" example:"timeout"`                                     // Why the WebSocket was not used
+	Success    bool      `json:"success" example:"true"`                                       // Whether REST returned the prices
+	DurationMs int64     `json:"duration_ms" example:"180"`                                    // REST request duration
+	RequestID  string    `json:"request_id,omitempty" example:"req_1704067200123456_a1b2c3d4"` // request_id of the logs of the request or refresh that triggered it
+}
+
+// AdminConfigResponse is the effective runtime configuration for operators
+// @Description Effective runtime configuration with secrets redacted and the origin of each value
+type AdminConfigResponse struct {
+	Environment string                      `json:"environment" example:"production"`    // Environment overlay applied (config.{env}.yaml)
+	Files       []string                    `json:"files" example:"configs/config.yaml"` // Config files applied, lowest precedence first
+	Config      map[string]any              `json:"config"`                              // Effective configuration, same keys as config.yaml
+	Sources     map[string]ConfigSourceData `json:"sources"`                             // Origin of every configuration key
+}
+
+// ConfigSourceData tells where a configuration value came from
+// @Description Origin of a configuration value
+type ConfigSourceData struct {
+	Source string `json:"source" example:"env" enums:"default,file,env"` // default, file or env
+	From   string `json:"from,omitempty" example:"PORT"`                 // File path or environment variable
+}
+
+// AdminStartupReportResponse describes how the service started
+// @Description Startup steps, cache warm-up and configuration warnings
+type AdminStartupReportResponse struct {
+	StartedAt      time.Time          `json:"started_at" example:"2023-12-01T10:30:00Z"`                                                           // When initialization began
+	ReadyAt        *time.Time         `json:"ready_at,omitempty" example:"2023-12-01T10:30:02Z"`                                                   // When every component had started, omitted while starting
+	DurationMs     int64              `json:"duration_ms,omitempty" example:"2150"`                                                                // Time from started_at to ready_at
+	Steps          []StartupStepData  `json:"steps"`                                                                                               // Initialization and start steps, in order
+	Warmup         *StartupWarmupData `json:"warmup,omitempty"`                                                                                    // Cache warm-up, omitted when it did not run
+	ConfigWarnings []string           `json:"config_warnings" example:"auth is disabled: /api/v1/admin endpoints accept unauthenticated requests"` // Valid but probably unintended settings
+}
+
+// StartupStepData is one startup step
+// @Description Initialization or start of a component
+type StartupStepData struct {
+	Name       string `json:"name" example:"cache"`                    // Component
+	Phase      string `json:"phase" example:"init" enums:"init,start"` // init (creation) or start (lifecycle)
+	DurationMs int64  `json:"duration_ms" example:"12"`                // Step duration
+	Error      string `json:"error,omitempty"`                         // Why the step failed
+}
+
+// StartupWarmupData is the result of the startup cache warm-up
+// @Description Startup cache warm-up result
+type StartupWarmupData struct {
+	Succeeded  []string          `json:"succeeded" example:"BTC/USD"` // Pairs loaded into the cache
+	Failed     map[string]string `json:"failed"`                      // Pairs that failed, with the reason
+	DurationMs int64             `json:"duration_ms" example:"850"`   // Warm-up duration
+	Error      string            `json:"error,omitempty"`             // Set when required pairs were not loaded
+}
+
+// AdminFeedQualityResponse reports the WebSocket feed quality per pair
+// @Description Rolling per-pair feed statistics: tick rate, drops, intervals, fallbacks and divergence vs REST
+type AdminFeedQualityResponse struct {
+	Timestamp     time.Time         `json:"timestamp" example:"2023-12-01T10:30:00Z"` // When the report was computed
+	WindowSeconds int64             `json:"window_seconds" example:"300"`             // Rolling window the tick statistics cover
+	Pairs         []FeedQualityData `json:"pairs"`                                    // Supported pairs, in configuration order
+}
+
+// FeedQualityData is the feed quality of one pair
+// @Description WebSocket feed quality of a pair
+type FeedQualityData struct {
+	Pair              string     `json:"pair" example:"BTC/USD"`                                    // Pair in BASE/QUOTE format
+	TicksPerMinute    float64    `json:"ticks_per_minute" example:"42.5"`                           // WebSocket ticks received per minute in the window
+	DropRate          float64    `json:"drop_rate" example:"0.02"`                                  // Dropped ticks over received ticks in the window (0-1)
+	AvgTickIntervalMs int64      `json:"avg_tick_interval_ms" example:"1410"`                       // Average time between received ticks in the window
+	LastTickAt        *time.Time `json:"last_tick_at,omitempty" example:"2023-12-01T10:29:59Z"`     // Last received tick, omitted if none was received
+	LastFallbackAt    *time.Time `json:"last_fallback_at,omitempty" example:"2023-12-01T10:12:00Z"` // Last REST fallback for the pair, omitted if none
+	DivergencePercent *float64   `json:"divergence_percent,omitempty" example:"0.08"`               // Last measured WebSocket vs REST divergence, omitted if never compared
+	DivergenceAt      *time.Time `json:"divergence_at,omitempty" example:"2023-12-01T10:25:00Z"`    // When the divergence was measured
+}
+
+// AdminPairsResponse reports the WebSocket subscription state per pair
+// @Description WebSocket ticker subscription state of the supported pairs, with the pairs excluded after repeated subscription errors
+type AdminPairsResponse struct {
+	Timestamp time.Time              `json:"timestamp" example:"2023-12-01T10:30:00Z"` // When the report was computed
+	Excluded  []string               `json:"excluded" example:"FOO/USD"`               // Pairs served only from REST after repeated subscription errors
+	Pairs     []PairSubscriptionData `json:"pairs"`                                    // Supported pairs, in configuration order
+}
+
+// PairSubscriptionData is the WebSocket subscription state of one pair
+// @Description WebSocket ticker subscription of a pair
+type PairSubscriptionData struct {
+	Pair          string     `json:"pair" example:"BTC/USD"`                                                             // Pair in BASE/QUOTE format
+	Status        string     `json:"status" example:"retrying" enums:"confirmed,pending,retrying,excluded,unsubscribed"` // Subscription state
+	Failures      int        `json:"failures,omitempty" example:"2"`                                                     // Subscription errors since the last confirmation
+	LastError     string     `json:"last_error,omitempty" example:"Currency pair not supported"`                         // Last error reported by the exchange
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty" example:"2023-12-01T10:29:50Z"`                           // When the last error was reported
+	NextRetryAt   *time.Time `json:"next_retry_at,omitempty" example:"2023-12-01T10:30:10Z"`                             // Next subscription attempt, only while retrying
+}
+
+// PairsResponse lists the supported pairs with their metadata
+// @Description Supported pairs with exchange metadata
+type PairsResponse struct {
+	Pairs []PairData `json:"pairs"` // Supported pairs, in configuration order
+}
+
+// PairData describes a supported pair
+// @Description Supported pair metadata
+type PairData struct {
+	Pair       string             `json:"pair" example:"BTC/USD"`                               // Pair in BASE/QUOTE format
+	Base       string             `json:"base" example:"BTC"`                                   // Base asset
+	Quote      string             `json:"quote" example:"USD"`                                  // Quote asset
+	Precision  *PairPrecisionData `json:"precision,omitempty"`                                  // Decimals reported by the exchange, omitted when unknown
+	Exchange   string             `json:"exchange" example:"kraken"`                            // Source exchange
+	Status     string             `json:"status,omitempty" example:"online"`                    // Pair status on the exchange, omitted when unknown
+	Subscribed bool               `json:"ws_subscribed" example:"true"`                         // Whether the WebSocket ticker subscription is confirmed
+	LastUpdate *time.Time         `json:"last_update,omitempty" example:"2023-12-01T10:30:00Z"` // Timestamp of the cached price, omitted when not cached
+}
+
+// PairPrecisionData holds the decimals used by the exchange for a pair
+// @Description Price and volume decimals
+type PairPrecisionData struct {
+	Price  int `json:"price" example:"1"`  // Price decimals
+	Volume int `json:"volume" example:"8"` // Volume (lot) decimals
+}
+
+// NewPairsResponse combines the exchange metadata with the cached price timestamps
+func NewPairsResponse(metadata []interfaces.PairMetadata, prices []*entities.Price) *PairsResponse {
+	updated := make(map[string]time.Time, len(prices))
+	for _, price := range prices {
+		updated[price.Pair] = price.Timestamp
+	}
+
+	pairs := make([]PairData, len(metadata))
+	for i, meta := range metadata {
+		pairs[i] = PairData{
+			Pair:       meta.Pair,
+			Base:       meta.Base,
+			Quote:      meta.Quote,
+			Exchange:   meta.Exchange,
+			Status:     meta.Status,
+			Subscribed: meta.Subscribed,
+		}
+		if meta.HasPrecision {
+			pairs[i].Precision = &PairPrecisionData{Price: meta.PriceDecimals, Volume: meta.LotDecimals}
+		}
+		if timestamp, ok := updated[meta.Pair]; ok {
+			timestamp = timestamp.UTC()
+			pairs[i].LastUpdate = &timestamp
+		}
+	}
+	return &PairsResponse{Pairs: pairs}
+}
+
+// NewAdminStatusResponse builds the status from the cached prices and fallback history
+func NewAdminStatusResponse(now time.Time, exchange ExchangeStatusData, prices []*entities.Price, supportedPairs []string, fallbacks []interfaces.FallbackEvent) *AdminStatusResponse {
+	cached := make(map[string]bool, len(prices))
+	stats := CacheStatsData{Entries: len(prices), SupportedPairs: len(supportedPairs), MissingPairs: []string{}}
+	for i, price := range prices {
+		cached[price.Pair] = true
+		age := price.AgeAt(now).Seconds()
+		if i == 0 || age > stats.OldestAgeSeconds {
+			stats.OldestAgeSeconds = age
+		}
+		if i == 0 || age < stats.NewestAgeSeconds {
+			stats.NewestAgeSeconds = age
+		}
+	}
+	for _, pair := range supportedPairs {
+		if !cached[pair] {
+			stats.MissingPairs = append(stats.MissingPairs, pair)
+		}
+	}
+
+	events := make([]FallbackEventData, len(fallbacks))
+	for i, event := range fallbacks {
+		events[i] = FallbackEventData{
+			Time:       event.Time.UTC(),
+			Pairs:      event.Pairs,
+			Reason:     event.Reason,
+			Success:    event.Success,
+			DurationMs: event.Duration.Milliseconds(),
+			RequestID:  event.RequestID,
+		}
+	}
+
+	return &AdminStatusResponse{
+		Timestamp:       now.UTC(),
+		Exchange:        exchange,
+		Cache:           stats,
+		RecentFallbacks: events,
+	}
+}
+
+// NewAuditEventsResponse converts the audit events into their JSON response
+func NewAuditEventsResponse(retentionDays int, events []interfaces.AuditEvent) *AuditEventsResponse {
+	response := &AuditEventsResponse{RetentionDays: retentionDays, Events: make([]AuditEventData, len(events))}
+	for i, event := range events {
+		pairs := event.Pairs
+		if pairs == nil {
+			pairs = []string{}
+		}
+		response.Events[i] = AuditEventData{
+			ID:         event.ID,
+			Type:       event.Type,
+			Time:       event.Time.UTC(),
+			Instance:   event.Instance,
+			Reason:     event.Reason,
+			DurationMs: event.Duration.Milliseconds(),
+			Pairs:      pairs,
+			Success:    event.Success,
+			Error:      event.Error,
+			Shard:      event.Shard,
+			Attempt:    event.Attempt,
+			RequestID:  event.RequestID,
+		}
+	}
+	return response
+}
+
 // NewGetLTPResponse creates a new response from a list of prices
 func NewGetLTPResponse(prices []*entities.Price) *GetLTPResponse {
 	priceData := make([]PriceData, len(prices))
 
 	for i, price := range prices {
-		priceData[i] = PriceData{
-			Pair:   price.Pair,
-			Amount: price.Amount,
-		}
+		priceData[i] = NewPriceData(price)
 	}
 
 	return &GetLTPResponse{
@@ -79,10 +589,7 @@ func NewGetLTPResponseWithErrors(successPrices []*entities.Price, errors []Price
 	priceData := make([]PriceData, len(successPrices))
 
 	for i, price := range successPrices {
-		priceData[i] = PriceData{
-			Pair:   price.Pair,
-			Amount: price.Amount,
-		}
+		priceData[i] = NewPriceData(price)
 	}
 
 	return &GetLTPResponse{
@@ -96,10 +603,7 @@ func NewGetLTPPartialResponse(successPrices []*entities.Price, errors []PriceErr
 	successData := make([]PriceData, len(successPrices))
 
 	for i, price := range successPrices {
-		successData[i] = PriceData{
-			Pair:   price.Pair,
-			Amount: price.Amount,
-		}
+		successData[i] = NewPriceData(price)
 	}
 
 	total := len(successPrices) + len(errors)
@@ -125,21 +629,25 @@ func NewPriceError(pair, error, code, message string) PriceError {
 	}
 }
 
-// NewErrorResponse creates a new error response
-func NewErrorResponse(error string, message string) *ErrorResponse {
+// NewErrorResponse creates an error response with a machine-readable code
+func NewErrorResponse(code string, message string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:   error,
+		Code:    code,
 		Message: message,
+		Error:   code,
 	}
 }
 
-// NewErrorResponseWithCode creates an error response with code
-func NewErrorResponseWithCode(error string, message string, code string) *ErrorResponse {
-	return &ErrorResponse{
-		Error:   error,
-		Message: message,
-		Code:    code,
-	}
+// WithDetails adds error-specific context to the response
+func (e *ErrorResponse) WithDetails(details map[string]any) *ErrorResponse {
+	e.Details = details
+	return e
+}
+
+// WithRequestID sets the request ID the error belongs to
+func (e *ErrorResponse) WithRequestID(requestID string) *ErrorResponse {
+	e.RequestID = requestID
+	return e
 }
 
 // NewHealthResponse creates a health check response
@@ -150,3 +658,110 @@ func NewHealthResponse(status string, services map[string]string) *HealthRespons
 		Services:  services,
 	}
 }
+
+// NewPairsInvalidationResponse creates the response for a per-pair invalidation
+func NewPairsInvalidationResponse(pairs []string) *CacheInvalidationResponse {
+	return &CacheInvalidationResponse{
+		Scope:   "pairs",
+		Pairs:   pairs,
+		Message: "Cached prices invalidated",
+	}
+}
+
+// NewFlushInvalidationResponse creates the response for a flush of all cached prices
+func NewFlushInvalidationResponse(removed int) *CacheInvalidationResponse {
+	return &CacheInvalidationResponse{
+		Scope:   "all",
+		Removed: &removed,
+		Message: "All cached prices flushed",
+	}
+}
+
+// NewAdminStartupReportResponse converts the startup report into its JSON response
+func NewAdminStartupReportResponse(report interfaces.StartupReport) *AdminStartupReportResponse {
+	response := &AdminStartupReportResponse{
+		StartedAt:      report.StartedAt.UTC(),
+		Steps:          make([]StartupStepData, len(report.Steps)),
+		ConfigWarnings: append([]string{}, report.ConfigWarnings...),
+	}
+	if !report.ReadyAt.IsZero() {
+		readyAt := report.ReadyAt.UTC()
+		response.ReadyAt = &readyAt
+		response.DurationMs = report.ReadyAt.Sub(report.StartedAt).Milliseconds()
+	}
+	for i, step := range report.Steps {
+		response.Steps[i] = StartupStepData{
+			Name:       step.Name,
+			Phase:      step.Phase,
+			DurationMs: step.Duration.Milliseconds(),
+			Error:      step.Err,
+		}
+	}
+	if warmup := report.Warmup; warmup != nil {
+		response.Warmup = &StartupWarmupData{
+			Succeeded:  append([]string{}, warmup.Succeeded...),
+			Failed:     warmup.Failed,
+			DurationMs: warmup.Duration.Milliseconds(),
+			Error:      warmup.Err,
+		}
+	}
+	return response
+}
+
+// NewAdminFeedQualityResponse converts the feed quality report into its JSON response
+func NewAdminFeedQualityResponse(now time.Time, report interfaces.FeedQualityReport) *AdminFeedQualityResponse {
+	response := &AdminFeedQualityResponse{
+		Timestamp:     now.UTC(),
+		WindowSeconds: int64(report.Window.Seconds()),
+		Pairs:         make([]FeedQualityData, len(report.Pairs)),
+	}
+	for i, pair := range report.Pairs {
+		data := FeedQualityData{
+			Pair:              pair.Pair,
+			TicksPerMinute:    pair.TicksPerMinute,
+			DropRate:          pair.DropRate,
+			AvgTickIntervalMs: pair.AvgTickInterval.Milliseconds(),
+			LastTickAt:        optionalTime(pair.LastTick),
+			LastFallbackAt:    optionalTime(pair.LastFallback),
+		}
+		if !pair.DivergenceAt.IsZero() {
+			divergence := pair.DivergencePercent
+			data.DivergencePercent = &divergence
+			data.DivergenceAt = optionalTime(pair.DivergenceAt)
+		}
+		response.Pairs[i] = data
+	}
+	return response
+}
+
+// NewAdminPairsResponse converts the subscription state of the pairs into its JSON response
+func NewAdminPairsResponse(now time.Time, subscriptions []interfaces.PairSubscription) *AdminPairsResponse {
+	response := &AdminPairsResponse{
+		Timestamp: now.UTC(),
+		Excluded:  []string{},
+		Pairs:     make([]PairSubscriptionData, len(subscriptions)),
+	}
+	for i, subscription := range subscriptions {
+		if subscription.Status == interfaces.SubscriptionExcluded {
+			response.Excluded = append(response.Excluded, subscription.Pair)
+		}
+		response.Pairs[i] = PairSubscriptionData{
+			Pair:          subscription.Pair,
+			Status:        subscription.Status,
+			Failures:      subscription.Failures,
+			LastError:     subscription.LastError,
+			LastFailureAt: optionalTime(subscription.LastFailureAt),
+			NextRetryAt:   optionalTime(subscription.RetryAt),
+		}
+	}
+	return response
+}
+
+// optionalTime returns t in UTC, or nil for the zero time
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultFlatlineAfter        = 10 * time.Minute // Sin cambios de precio durante este tiempo = flatline
+	DefaultSpikeZScore          = 6.0              // |z| del retorno a partir del cual hay un spike
+	DefaultSpikeWindow          = 120              // Retornos usados para la media y el desvío
+	DefaultSpikeMinSamples      = 30               // Retornos mínimos antes de evaluar spikes
+	DefaultAnomalyCheckInterval = 30 * time.Second // Frecuencia de búsqueda de flatlines
+)
+
+// Tipos de anomalía
+const (
+	AnomalyFlatline = "flatline"
+	AnomalySpike    = "spike"
+)
+
+// AnomalyDetectorConfig parametriza el detector
+type AnomalyDetectorConfig struct {
+	FlatlineAfter   time.Duration
+	FlatlinePairs   []string // Pares líquidos vigilados por flatline; vacío = todos los observados
+	SpikeZScore     float64
+	SpikeWindow     int
+	SpikeMinSamples int
+	CheckInterval   time.Duration
+}
+
+// Anomaly es un hallazgo del detector
+type Anomaly struct {
+	Pair   string
+	Kind   string // flatline, spike
+	Time   time.Time
+	Amount float64
+	// Previous es el precio anterior al spike o el precio congelado del flatline
+	Previous float64
+	// ZScore del retorno que disparó el spike (0 en flatlines)
+	ZScore float64
+	// Unchanged es el tiempo sin cambios del flatline (0 en spikes)
+	Unchanged time.Duration
+}
+
+// AnomalyHandler recibe cada anomalía detectada; se invoca sin locks tomados
+type AnomalyHandler func(ctx context.Context, anomaly Anomaly)
+
+// pairTicks es el estado por par: último precio, último cambio y ventana de retornos
+type pairTicks struct {
+	last       float64
+	lastChange time.Time
+	returns    []float64 // ring buffer de log-retornos
+	next       int
+	flatlined  bool
+}
+
+// AnomalyDetector vigila el stream de ticks de cada par buscando flatlines (el
+// precio de un par líquido no cambia durante FlatlineAfter) y spikes (un retorno
+// cuyo z-score respecto a la ventana reciente supera SpikeZScore). Los hallazgos
+// se registran como métricas y logs y se entregan a los handlers de OnAnomaly.
+type AnomalyDetector struct {
+	config AnomalyDetectorConfig
+	now    func() time.Time
+
+	mu            sync.Mutex
+	pairs         map[string]*pairTicks
+	handlers      []AnomalyHandler
+	inMaintenance func() bool
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewAnomalyDetector crea un detector; valores no positivos usan los defaults
+func NewAnomalyDetector(config AnomalyDetectorConfig) *AnomalyDetector {
+	if config.FlatlineAfter <= 0 {
+		config.FlatlineAfter = DefaultFlatlineAfter
+	}
+	if config.SpikeZScore <= 0 {
+		config.SpikeZScore = DefaultSpikeZScore
+	}
+	if config.SpikeWindow <= 0 {
+		config.SpikeWindow = DefaultSpikeWindow
+	}
+	if config.SpikeMinSamples <= 0 {
+		config.SpikeMinSamples = DefaultSpikeMinSamples
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = DefaultAnomalyCheckInterval
+	}
+	config.FlatlinePairs = slices.Clone(config.FlatlinePairs)
+	return &AnomalyDetector{
+		config: config,
+		now:    time.Now,
+		pairs:  make(map[string]*pairTicks),
+	}
+}
+
+// OnAnomaly registra un handler para las anomalías detectadas (p. ej. notificaciones)
+func (d *AnomalyDetector) OnAnomaly(handler AnomalyHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, handler)
+}
+
+// SetMaintenanceCheck suprime los flatlines mientras inMaintenance reporte una
+// ventana de mantenimiento del exchange (sin ticks es lo esperable)
+func (d *AnomalyDetector) SetMaintenanceCheck(inMaintenance func() bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inMaintenance = inMaintenance
+}
+
+// Observe procesa un tick. Se invoca desde la goroutine de lectura del WebSocket,
+// así que sólo actualiza estado en memoria y evalúa el spike del tick.
+func (d *AnomalyDetector) Observe(price *entities.Price) {
+	if price == nil || price.Amount <= 0 {
+		return
+	}
+	now := d.now()
+
+	d.mu.Lock()
+	state, ok := d.pairs[price.Pair]
+	if !ok {
+		d.pairs[price.Pair] = &pairTicks{last: price.Amount, lastChange: now}
+		d.mu.Unlock()
+		return
+	}
+	if price.Amount == state.last {
+		d.mu.Unlock()
+		return
+	}
+
+	previous := state.last
+	ret := math.Log(price.Amount / previous)
+	z, scored := state.zscore(ret, d.config.SpikeMinSamples)
+	state.push(ret, d.config.SpikeWindow)
+	state.last = price.Amount
+	state.lastChange = now
+	recovered := state.flatlined
+	state.flatlined = false
+	d.mu.Unlock()
+
+	ctx := context.Background()
+	if recovered {
+		metrics.UpdatePriceFlatline(price.Pair, false)
+		logging.Info(ctx, "Price is moving again after flatline", logging.Fields{
+			"pair":   price.Pair,
+			"amount": price.Amount,
+		})
+	}
+	if scored {
+		metrics.UpdatePriceReturnZScore(price.Pair, z)
+	}
+	if scored && math.Abs(z) >= d.config.SpikeZScore {
+		d.report(ctx, Anomaly{
+			Pair:     price.Pair,
+			Kind:     AnomalySpike,
+			Time:     now,
+			Amount:   price.Amount,
+			Previous: previous,
+			ZScore:   z,
+		})
+	}
+}
+
+// Check busca flatlines: pares vigilados cuyo precio no cambia hace FlatlineAfter.
+// Cada episodio se reporta una vez; el siguiente cambio de precio lo cierra.
+// Durante una ventana de mantenimiento no se reportan flatlines.
+func (d *AnomalyDetector) Check(ctx context.Context) {
+	now := d.now()
+	var found []Anomaly
+
+	d.mu.Lock()
+	if d.inMaintenance != nil && d.inMaintenance() {
+		d.mu.Unlock()
+		return
+	}
+	for pair, state := range d.pairs {
+		if state.flatlined || !d.watchesFlatline(pair) {
+			continue
+		}
+		if unchanged := now.Sub(state.lastChange); unchanged >= d.config.FlatlineAfter {
+			state.flatlined = true
+			found = append(found, Anomaly{
+				Pair:      pair,
+				Kind:      AnomalyFlatline,
+				Time:      now,
+				Amount:    state.last,
+				Previous:  state.last,
+				Unchanged: unchanged,
+			})
+		}
+	}
+	d.mu.Unlock()
+
+	for _, anomaly := range found {
+		metrics.UpdatePriceFlatline(anomaly.Pair, true)
+		d.report(ctx, anomaly)
+	}
+}
+
+func (d *AnomalyDetector) watchesFlatline(pair string) bool {
+	return len(d.config.FlatlinePairs) == 0 || slices.Contains(d.config.FlatlinePairs, pair)
+}
+
+// report registra la anomalía y la entrega a los handlers
+func (d *AnomalyDetector) report(ctx context.Context, anomaly Anomaly) {
+	metrics.RecordPriceAnomaly(anomaly.Pair, anomaly.Kind)
+
+	fields := logging.Fields{
+		"pair":   anomaly.Pair,
+		"kind":   anomaly.Kind,
+		"amount": anomaly.Amount,
+	}
+	switch anomaly.Kind {
+	case AnomalySpike:
+		fields["previous_amount"] = anomaly.Previous
+		fields["z_score"] = anomaly.ZScore
+		fields["z_score_threshold"] = d.config.SpikeZScore
+	case AnomalyFlatline:
+		fields["unchanged_for"] = anomaly.Unchanged.String()
+		fields["flatline_after"] = d.config.FlatlineAfter.String()
+	}
+	logging.Warn(ctx, "Price anomaly detected", fields)
+
+	d.mu.Lock()
+	handlers := slices.Clone(d.handlers)
+	d.mu.Unlock()
+	for _, handler := range handlers {
+		handler(ctx, anomaly)
+	}
+}
+
+// zscore calcula el z-score de ret respecto a los retornos de la ventana; false
+// si todavía no hay muestras suficientes o la ventana no tiene dispersión
+func (p *pairTicks) zscore(ret float64, minSamples int) (float64, bool) {
+	n := len(p.returns)
+	if n < minSamples {
+		return 0, false
+	}
+	var sum float64
+	for _, r := range p.returns {
+		sum += r
+	}
+	mean := sum / float64(n)
+	var variance float64
+	for _, r := range p.returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(n))
+	if std == 0 {
+		return 0, false
+	}
+	return (ret - mean) / std, true
+}
+
+func (p *pairTicks) push(ret float64, window int) {
+	if len(p.returns) < window {
+		p.returns = append(p.returns, ret)
+		return
+	}
+	p.returns[p.next] = ret
+	p.next = (p.next + 1) % window
+}
+
+// Start lanza la búsqueda periódica de flatlines en background
+func (d *AnomalyDetector) Start(ctx context.Context) error {
+	d.loopMu.Lock()
+	defer d.loopMu.Unlock()
+	if d.stop != nil {
+		return nil
+	}
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+
+	logging.Info(ctx, "Starting price anomaly detection", logging.Fields{
+		"flatline_after": d.config.FlatlineAfter.String(),
+		"flatline_pairs": d.config.FlatlinePairs,
+		"spike_z_score":  d.config.SpikeZScore,
+		"spike_window":   d.config.SpikeWindow,
+	})
+	go d.loop(context.WithoutCancel(ctx), d.stop, d.done)
+	return nil
+}
+
+// Stop detiene la búsqueda periódica
+func (d *AnomalyDetector) Stop(ctx context.Context) error {
+	d.loopMu.Lock()
+	stop, done := d.stop, d.done
+	d.stop, d.done = nil, nil
+	d.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *AnomalyDetector) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(d.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.Check(ctx)
+		case <-stop:
+			return
+		}
+	}
+}
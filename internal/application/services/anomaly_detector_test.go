@@ -0,0 +1,108 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAnomalyDetector(t *testing.T, config AnomalyDetectorConfig) (*AnomalyDetector, *time.Time, *[]Anomaly, *metrics.Metrics) {
+	t.Helper()
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewAnomalyDetector(config)
+	d.now = func() time.Time { return now }
+	var found []Anomaly
+	d.OnAnomaly(func(_ context.Context, anomaly Anomaly) { found = append(found, anomaly) })
+	return d, &now, &found, m
+}
+
+func TestAnomalyDetector_Spike(t *testing.T) {
+	d, _, found, m := newTestAnomalyDetector(t, AnomalyDetectorConfig{SpikeZScore: 5, SpikeWindow: 50, SpikeMinSamples: 20})
+
+	// Oscilación de ±0.01% alrededor de 50000
+	amount := 50000.0
+	for i := 0; i < 40; i++ {
+		if i%2 == 0 {
+			amount *= 1.0001
+		} else {
+			amount /= 1.0001
+		}
+		d.Observe(&entities.Price{Pair: "BTC/USD", Amount: amount})
+	}
+	assert.Empty(t, *found, "regular ticks are not anomalies")
+
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: amount * 1.02})
+
+	require.Len(t, *found, 1)
+	spike := (*found)[0]
+	assert.Equal(t, AnomalySpike, spike.Kind)
+	assert.Equal(t, "BTC/USD", spike.Pair)
+	assert.InDelta(t, amount, spike.Previous, 1e-9)
+	assert.Greater(t, spike.ZScore, 5.0)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.AnomaliesTotal.WithLabelValues("BTC/USD", AnomalySpike)))
+}
+
+func TestAnomalyDetector_SpikeNeedsMinSamples(t *testing.T) {
+	d, _, found, _ := newTestAnomalyDetector(t, AnomalyDetectorConfig{SpikeMinSamples: 30})
+
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 50000})
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 50001})
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 60000})
+
+	assert.Empty(t, *found)
+}
+
+func TestAnomalyDetector_FlatlineReportedOncePerEpisode(t *testing.T) {
+	d, now, found, m := newTestAnomalyDetector(t, AnomalyDetectorConfig{FlatlineAfter: 5 * time.Minute, FlatlinePairs: []string{"BTC/USD"}})
+	ctx := context.Background()
+
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 50000})
+	d.Observe(&entities.Price{Pair: "XRP/USD", Amount: 0.5})
+
+	*now = now.Add(4 * time.Minute)
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 50000}) // mismo precio: no cuenta como cambio
+	d.Check(ctx)
+	assert.Empty(t, *found)
+
+	*now = now.Add(2 * time.Minute)
+	d.Check(ctx)
+	d.Check(ctx)
+	require.Len(t, *found, 1, "only watched pairs flatline, once per episode")
+	assert.Equal(t, AnomalyFlatline, (*found)[0].Kind)
+	assert.Equal(t, "BTC/USD", (*found)[0].Pair)
+	assert.Equal(t, 6*time.Minute, (*found)[0].Unchanged)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.Flatline.WithLabelValues("BTC/USD")))
+
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 50010})
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.Prices.Flatline.WithLabelValues("BTC/USD")))
+
+	*now = now.Add(6 * time.Minute)
+	d.Check(ctx)
+	assert.Len(t, *found, 2, "a new episode is reported after the price moved")
+}
+
+func TestAnomalyDetector_FlatlineSuppressedDuringMaintenance(t *testing.T) {
+	d, now, found, _ := newTestAnomalyDetector(t, AnomalyDetectorConfig{FlatlineAfter: 5 * time.Minute})
+	maintenance := true
+	d.SetMaintenanceCheck(func() bool { return maintenance })
+
+	d.Observe(&entities.Price{Pair: "BTC/USD", Amount: 50000})
+	*now = now.Add(10 * time.Minute)
+	d.Check(context.Background())
+	assert.Empty(t, *found, "no flatline alerts during a maintenance window")
+
+	maintenance = false
+	d.Check(context.Background())
+	assert.Len(t, *found, 1, "a price still frozen after the window is reported")
+}
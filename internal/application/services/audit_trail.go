@@ -0,0 +1,344 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultAuditFlushInterval = 10 * time.Second // Frecuencia de escritura de los eventos
+	DefaultAuditRetentionDays = 30               // Días de eventos conservados
+	DefaultAuditEventsLimit   = 100              // Eventos devueltos si el filtro no fija Limit
+
+	// maxPendingAuditEvents acota los eventos en memoria mientras el state
+	// repository no responde; los más viejos se descartan
+	maxPendingAuditEvents = 1000
+)
+
+// AuditTrail registra las activaciones del fallback REST y las reconexiones del
+// WebSocket en el state repository para los postmortems. Los eventos se acumulan
+// en memoria y se guardan cada flushInterval, así registrarlos no agrega la
+// latencia del repositorio a las requests; la consulta suma todas las réplicas.
+type AuditTrail struct {
+	store         interfaces.StateRepository
+	instanceID    string
+	flushInterval time.Duration
+	retentionDays int
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending []interfaces.AuditEvent
+	seq     int64
+	dropped int
+
+	flushMu    sync.Mutex
+	lastPruned string
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewAuditTrail crea el registro de auditoría de esta réplica; valores no positivos usan los defaults
+func NewAuditTrail(store interfaces.StateRepository, instanceID string, flushInterval time.Duration, retentionDays int) *AuditTrail {
+	if flushInterval <= 0 {
+		flushInterval = DefaultAuditFlushInterval
+	}
+	if retentionDays <= 0 {
+		retentionDays = DefaultAuditRetentionDays
+	}
+	return &AuditTrail{
+		store:         store,
+		instanceID:    instanceID,
+		flushInterval: flushInterval,
+		retentionDays: retentionDays,
+		now:           time.Now,
+	}
+}
+
+// RecordEvent implementa interfaces.AuditRecorder; el evento se guarda en el próximo flush
+func (a *AuditTrail) RecordEvent(ctx context.Context, event interfaces.AuditEvent) {
+	if event.Time.IsZero() {
+		event.Time = a.now()
+	}
+	event.Time = event.Time.UTC()
+	event.Instance = a.instanceID
+	event.Pairs = slices.Clone(event.Pairs)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.seq++
+	event.ID = event.Time.Format("20060102T150405.000000000Z") + "/" + a.instanceID + "/" + strconv.FormatInt(a.seq, 10)
+	if len(a.pending) >= maxPendingAuditEvents {
+		a.pending = a.pending[1:]
+		a.dropped++
+	}
+	a.pending = append(a.pending, event)
+}
+
+// RetentionDays implementa interfaces.AuditLog
+func (a *AuditTrail) RetentionDays() int {
+	return a.retentionDays
+}
+
+// Events implementa interfaces.AuditLog. Los eventos de esta réplica todavía no
+// guardados se incluyen en la consulta.
+func (a *AuditTrail) Events(ctx context.Context, filter interfaces.AuditEventFilter) ([]interfaces.AuditEvent, error) {
+	stored, err := a.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	for _, event := range a.pending {
+		stored[event.ID] = event
+	}
+	a.mu.Unlock()
+
+	events := make([]interfaces.AuditEvent, 0, len(stored))
+	for _, event := range stored {
+		if matchesAuditFilter(event, filter) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.After(events[j].Time)
+		}
+		return events[i].ID > events[j].ID
+	})
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultAuditEventsLimit
+	}
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func matchesAuditFilter(event interfaces.AuditEvent, filter interfaces.AuditEventFilter) bool {
+	switch {
+	case filter.Type != "" && event.Type != filter.Type:
+		return false
+	case filter.Reason != "" && event.Reason != filter.Reason:
+		return false
+	case filter.Pair != "" && !slices.Contains(event.Pairs, filter.Pair):
+		return false
+	case !filter.From.IsZero() && event.Time.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && event.Time.After(filter.To):
+		return false
+	}
+	return true
+}
+
+// Flush guarda los eventos pendientes; los que fallan quedan para el próximo
+// flush. Una vez por día borra los eventos fuera de la retención.
+func (a *AuditTrail) Flush(ctx context.Context) error {
+	a.flushMu.Lock()
+	defer a.flushMu.Unlock()
+
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = nil
+	dropped := a.dropped
+	a.dropped = 0
+	a.mu.Unlock()
+
+	if dropped > 0 {
+		logging.Warn(ctx, "Audit events dropped while the state repository was unavailable", logging.Fields{
+			"dropped": dropped,
+		})
+	}
+
+	var failed []interfaces.AuditEvent
+	var flushErr error
+	for _, event := range pending {
+		value, err := json.Marshal(newAuditRecord(event))
+		if err == nil {
+			err = a.store.Put(ctx, interfaces.StateNamespaceEvents, event.ID, string(value))
+		}
+		if err != nil {
+			failed = append(failed, event)
+			flushErr = fmt.Errorf("failed to store audit event: %w", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		a.mu.Lock()
+		a.pending = append(failed, a.pending...)
+		if excess := len(a.pending) - maxPendingAuditEvents; excess > 0 {
+			a.pending = a.pending[excess:]
+			a.dropped += excess
+		}
+		a.mu.Unlock()
+	}
+
+	today := a.now().UTC().Format(interfaces.UsageDateLayout)
+	if flushErr == nil && a.lastPruned != today {
+		if err := a.prune(ctx); err != nil {
+			return err
+		}
+		a.lastPruned = today
+	}
+	return flushErr
+}
+
+// prune borra los eventos fuera de la retención (de todas las réplicas)
+func (a *AuditTrail) prune(ctx context.Context) error {
+	oldest := a.now().UTC().AddDate(0, 0, -a.retentionDays)
+
+	values, err := a.store.List(ctx, interfaces.StateNamespaceEvents)
+	if err != nil {
+		return fmt.Errorf("failed to list audit events: %w", err)
+	}
+	deleted := 0
+	for key, raw := range values {
+		var record auditRecord
+		if json.Unmarshal([]byte(raw), &record) == nil && !record.Time.Before(oldest) {
+			continue
+		}
+		if err := a.store.Delete(ctx, interfaces.StateNamespaceEvents, key); err != nil {
+			return fmt.Errorf("failed to delete audit event: %w", err)
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		logging.Info(ctx, "Pruned expired audit events", logging.Fields{
+			"deleted":        deleted,
+			"retention_days": a.retentionDays,
+		})
+	}
+	return nil
+}
+
+// load lee los eventos guardados indexados por su ID
+func (a *AuditTrail) load(ctx context.Context) (map[string]interfaces.AuditEvent, error) {
+	values, err := a.store.List(ctx, interfaces.StateNamespaceEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit events: %w", err)
+	}
+	events := make(map[string]interfaces.AuditEvent, len(values))
+	for key, raw := range values {
+		var record auditRecord
+		if err := json.Unmarshal([]byte(raw), &record); err != nil {
+			continue
+		}
+		events[key] = record.event(key)
+	}
+	return events, nil
+}
+
+// auditRecord es el formato JSON de un evento en el state repository
+type auditRecord struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Instance   string    `json:"instance"`
+	Reason     string    `json:"reason"`
+	DurationMs int64     `json:"duration_ms"`
+	Pairs      []string  `json:"pairs,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	Shard      string    `json:"shard,omitempty"`
+	Attempt    int       `json:"attempt,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+}
+
+func newAuditRecord(event interfaces.AuditEvent) auditRecord {
+	return auditRecord{
+		Type:       event.Type,
+		Time:       event.Time,
+		Instance:   event.Instance,
+		Reason:     event.Reason,
+		DurationMs: event.Duration.Milliseconds(),
+		Pairs:      event.Pairs,
+		Success:    event.Success,
+		Error:      event.Error,
+		Shard:      event.Shard,
+		Attempt:    event.Attempt,
+		RequestID:  event.RequestID,
+	}
+}
+
+func (r auditRecord) event(id string) interfaces.AuditEvent {
+	return interfaces.AuditEvent{
+		ID:        id,
+		Type:      r.Type,
+		Time:      r.Time,
+		Instance:  r.Instance,
+		Reason:    r.Reason,
+		Duration:  time.Duration(r.DurationMs) * time.Millisecond,
+		Pairs:     r.Pairs,
+		Success:   r.Success,
+		Error:     r.Error,
+		Shard:     r.Shard,
+		Attempt:   r.Attempt,
+		RequestID: r.RequestID,
+	}
+}
+
+// Start guarda los eventos periódicamente en background
+func (a *AuditTrail) Start(ctx context.Context) error {
+	a.loopMu.Lock()
+	defer a.loopMu.Unlock()
+	if a.stop != nil {
+		return nil
+	}
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+
+	logging.Info(ctx, "Starting audit trail", logging.Fields{
+		"instance_id":    a.instanceID,
+		"flush_interval": a.flushInterval.String(),
+		"retention_days": a.retentionDays,
+	})
+	go a.loop(context.WithoutCancel(ctx), a.stop, a.done)
+	return nil
+}
+
+// Stop detiene la escritura periódica y guarda los eventos pendientes
+func (a *AuditTrail) Stop(ctx context.Context) error {
+	a.loopMu.Lock()
+	stop, done := a.stop, a.done
+	a.stop, a.done = nil, nil
+	a.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return a.Flush(ctx)
+}
+
+func (a *AuditTrail) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			flushCtx, cancel := context.WithTimeout(ctx, a.flushInterval)
+			if err := a.Flush(flushCtx); err != nil {
+				logging.Warn(flushCtx, "Audit flush failed", logging.Fields{"error": err.Error()})
+			}
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingStateRepository falla en los Put mientras fail es true
+type failingStateRepository struct {
+	interfaces.StateRepository
+	fail bool
+}
+
+func (f *failingStateRepository) Put(ctx context.Context, namespace, key string, value string) error {
+	if f.fail {
+		return errors.New("state unavailable")
+	}
+	return f.StateRepository.Put(ctx, namespace, key, value)
+}
+
+func TestAuditTrail_FiltersEventsAcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	replicaA := NewAuditTrail(store, "replica-a", time.Minute, 30)
+	replicaB := NewAuditTrail(store, "replica-b", time.Minute, 30)
+
+	replicaA.RecordEvent(ctx, interfaces.AuditEvent{
+		Type: interfaces.AuditEventFallback, Time: now, Reason: "timeout",
+		Duration: 120 * time.Millisecond, Pairs: []string{"BTC/USD"}, Success: true, RequestID: "req-1",
+	})
+	replicaB.RecordEvent(ctx, interfaces.AuditEvent{
+		Type: interfaces.AuditEventReconnect, Time: now.Add(time.Minute), Reason: "read_timeout",
+		Pairs: []string{"BTC/USD", "ETH/USD"}, Error: "dial failed", Shard: "0", Attempt: 1,
+	})
+	require.NoError(t, replicaB.Flush(ctx))
+
+	events, err := replicaA.Events(ctx, interfaces.AuditEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2, "unflushed events of this replica are included")
+	assert.Equal(t, interfaces.AuditEventReconnect, events[0].Type, "newest first")
+	assert.Equal(t, "replica-b", events[0].Instance)
+	assert.Equal(t, "dial failed", events[0].Error)
+	assert.Equal(t, "req-1", events[1].RequestID)
+	assert.Equal(t, 120*time.Millisecond, events[1].Duration)
+
+	fallbacks, err := replicaB.Events(ctx, interfaces.AuditEventFilter{Type: interfaces.AuditEventFallback})
+	require.NoError(t, err)
+	assert.Empty(t, fallbacks, "other replicas only see flushed events")
+
+	require.NoError(t, replicaA.Flush(ctx))
+	for _, tt := range []struct {
+		name   string
+		filter interfaces.AuditEventFilter
+		want   int
+	}{
+		{name: "type", filter: interfaces.AuditEventFilter{Type: interfaces.AuditEventFallback}, want: 1},
+		{name: "reason", filter: interfaces.AuditEventFilter{Reason: "read_timeout"}, want: 1},
+		{name: "pair", filter: interfaces.AuditEventFilter{Pair: "ETH/USD"}, want: 1},
+		{name: "from", filter: interfaces.AuditEventFilter{From: now.Add(time.Second)}, want: 1},
+		{name: "to", filter: interfaces.AuditEventFilter{To: now}, want: 1},
+		{name: "limit", filter: interfaces.AuditEventFilter{Limit: 1}, want: 1},
+		{name: "none", filter: interfaces.AuditEventFilter{}, want: 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := replicaB.Events(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Len(t, events, tt.want)
+		})
+	}
+}
+
+func TestAuditTrail_RetriesFailedFlushAndPrunes(t *testing.T) {
+	ctx := context.Background()
+	store := &failingStateRepository{StateRepository: state.NewMemoryStateRepository(), fail: true}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	trail := NewAuditTrail(store, "replica-a", time.Hour, 2)
+	trail.now = func() time.Time { return now }
+
+	trail.RecordEvent(ctx, interfaces.AuditEvent{Type: interfaces.AuditEventFallback, Time: now.AddDate(0, 0, -3), Reason: "timeout"})
+	trail.RecordEvent(ctx, interfaces.AuditEvent{Type: interfaces.AuditEventFallback, Reason: "max_retries"})
+	assert.Error(t, trail.Flush(ctx))
+
+	store.fail = false
+	require.NoError(t, trail.Start(ctx))
+	require.NoError(t, trail.Stop(ctx), "stop flushes the events kept after the failed flush")
+
+	stored, err := store.List(ctx, interfaces.StateNamespaceEvents)
+	require.NoError(t, err)
+	require.Len(t, stored, 1, "events older than the retention are pruned")
+
+	events, err := NewAuditTrail(store, "replica-b", time.Hour, 2).Events(ctx, interfaces.AuditEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "max_retries", events[0].Reason)
+	assert.Equal(t, now, events[0].Time, "events without time are stamped when recorded")
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"time"
+)
+
+// BudgetSplit define qué fracción del tiempo restante de una request recibe cada
+// etapa. Las fracciones se normalizan, por lo que sólo importa su proporción.
+type BudgetSplit struct {
+	Cache     float64
+	WebSocket float64
+	REST      float64
+}
+
+// DefaultBudgetSplit reserva la mayor parte del tiempo para el exchange, dando
+// al fallback REST algo más que al WebSocket porque suele ser más lento
+var DefaultBudgetSplit = BudgetSplit{Cache: 0.1, WebSocket: 0.4, REST: 0.5}
+
+// Allocate divide remaining según las fracciones; un split inválido usa el default
+func (s BudgetSplit) Allocate(remaining time.Duration) entities.FetchBudget {
+	total := s.Cache + s.WebSocket + s.REST
+	if s.Cache < 0 || s.WebSocket < 0 || s.REST < 0 || total <= 0 {
+		return DefaultBudgetSplit.Allocate(remaining)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	share := func(fraction float64) time.Duration {
+		return time.Duration(float64(remaining) * fraction / total)
+	}
+	return entities.FetchBudget{
+		Cache:     share(s.Cache),
+		WebSocket: share(s.WebSocket),
+		REST:      share(s.REST),
+	}
+}
+
+// WithBudgetSplit cambia el reparto del deadline de la request entre etapas
+func WithBudgetSplit(split BudgetSplit) PriceServiceOption {
+	return func(s *priceService) {
+		s.budgetSplit = split
+	}
+}
+
+// budgetFor reparte el tiempo hasta el deadline de ctx; sin deadline no hay presupuesto
+func (s *priceService) budgetFor(ctx context.Context) (entities.FetchBudget, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return entities.FetchBudget{}, false
+	}
+	return s.budgetSplit.Allocate(time.Until(deadline)), true
+}
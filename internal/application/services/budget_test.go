@@ -0,0 +1,64 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetSplit_Allocate(t *testing.T) {
+	budget := DefaultBudgetSplit.Allocate(10 * time.Second)
+	assert.Equal(t, entities.FetchBudget{Cache: time.Second, WebSocket: 4 * time.Second, REST: 5 * time.Second}, budget)
+
+	// Sólo importa la proporción
+	budget = BudgetSplit{Cache: 1, WebSocket: 1, REST: 2}.Allocate(4 * time.Second)
+	assert.Equal(t, entities.FetchBudget{Cache: time.Second, WebSocket: time.Second, REST: 2 * time.Second}, budget)
+
+	// Splits inválidos usan el default; tiempo vencido no produce duraciones negativas
+	assert.Equal(t, DefaultBudgetSplit.Allocate(time.Second), BudgetSplit{}.Allocate(time.Second))
+	assert.Equal(t, entities.FetchBudget{}, DefaultBudgetSplit.Allocate(-time.Second))
+}
+
+func TestPriceService_RefreshPrices_PropagatesDeadlineBudget(t *testing.T) {
+	exchange := &budgetRecorder{stubPrices: stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: time.Now()},
+	}}}
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD"})
+
+	// Sin deadline no hay presupuesto
+	require.NoError(t, service.RefreshPrices(context.Background(), []string{"BTC/USD"}))
+	assert.False(t, exchange.hasBudget)
+	assert.False(t, exchange.hasDeadline)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	requestDeadline, _ := ctx.Deadline()
+	require.NoError(t, service.RefreshPrices(ctx, []string{"BTC/USD"}))
+
+	require.True(t, exchange.hasBudget)
+	assert.InDelta(t, float64(4*time.Second), float64(exchange.budget.WebSocket), float64(100*time.Millisecond))
+	assert.InDelta(t, float64(5*time.Second), float64(exchange.budget.REST), float64(100*time.Millisecond))
+	// El exchange debe terminar antes del deadline de la request, dejando la parte de caché
+	require.True(t, exchange.hasDeadline)
+	assert.WithinDuration(t, requestDeadline.Add(-time.Second), exchange.deadline, 100*time.Millisecond)
+}
+
+// budgetRecorder registra el presupuesto y deadline que recibe el exchange
+type budgetRecorder struct {
+	stubPrices
+	budget      entities.FetchBudget
+	hasBudget   bool
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (b *budgetRecorder) GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
+	b.budget, b.hasBudget = entities.FetchBudgetFrom(ctx)
+	b.deadline, b.hasDeadline = ctx.Deadline()
+	return b.stubPrices.GetTickers(ctx, pairs)
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultDeviationCheckInterval = 30 * time.Second // Frecuencia de comparación contra la referencia
+	DefaultMaxDeviationPercent    = 1.0              // Desvío tolerado respecto a la referencia
+)
+
+// Acciones ante un precio que se desvía de la referencia
+const (
+	// DeviationActionFlag sólo registra la alerta (métrica y log); el precio se sigue sirviendo
+	DeviationActionFlag = "flag"
+	// DeviationActionRefuse además deja de servir el precio hasta que vuelva a coincidir
+	DeviationActionRefuse = "refuse"
+)
+
+// DeviationGuardConfig parametriza el control de integridad
+type DeviationGuardConfig struct {
+	Interval            time.Duration
+	MaxDeviationPercent float64
+	Action              string
+}
+
+// PriceDeviation es un par cuyo precio cacheado se aparta de la referencia
+type PriceDeviation struct {
+	Pair      string
+	Cached    float64
+	Reference float64
+	Percent   float64
+	Since     time.Time
+}
+
+// DeviationGuard compara periódicamente los precios cacheados contra una fuente
+// de referencia (REST u otro exchange) y marca los pares que se desvían más de
+// MaxDeviationPercent. Con la acción refuse implementa interfaces.PriceGuard y
+// retiene esos precios hasta que un chequeo posterior los encuentre alineados.
+type DeviationGuard struct {
+	cached    func(ctx context.Context) ([]*entities.Price, error)
+	reference interfaces.Exchange
+	config    DeviationGuardConfig
+	now       func() time.Time
+
+	mu      sync.RWMutex
+	flagged map[string]PriceDeviation
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewDeviationGuard crea el control; cached devuelve los precios a verificar
+// (sin pasar por el guard) y reference provee los precios de comparación.
+// Valores no positivos usan los defaults y una acción vacía equivale a flag.
+func NewDeviationGuard(cached func(ctx context.Context) ([]*entities.Price, error), reference interfaces.Exchange, config DeviationGuardConfig) *DeviationGuard {
+	if config.Interval <= 0 {
+		config.Interval = DefaultDeviationCheckInterval
+	}
+	if config.MaxDeviationPercent <= 0 {
+		config.MaxDeviationPercent = DefaultMaxDeviationPercent
+	}
+	if config.Action == "" {
+		config.Action = DeviationActionFlag
+	}
+	return &DeviationGuard{
+		cached:    cached,
+		reference: reference,
+		config:    config,
+		now:       time.Now,
+		flagged:   make(map[string]PriceDeviation),
+	}
+}
+
+// Allow implementa interfaces.PriceGuard: con la acción refuse, un par marcado no se sirve
+func (g *DeviationGuard) Allow(pair string) error {
+	if g.config.Action != DeviationActionRefuse {
+		return nil
+	}
+	g.mu.RLock()
+	deviation, flagged := g.flagged[pair]
+	g.mu.RUnlock()
+	if !flagged {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is %.2f%% away from the reference price", interfaces.ErrPriceDeviation, pair, deviation.Percent)
+}
+
+// Deviations devuelve los pares marcados actualmente
+func (g *DeviationGuard) Deviations() []PriceDeviation {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	deviations := make([]PriceDeviation, 0, len(g.flagged))
+	for _, deviation := range g.flagged {
+		deviations = append(deviations, deviation)
+	}
+	return deviations
+}
+
+// Check compara los precios cacheados con la referencia y actualiza los pares
+// marcados. Los pares sin precio de referencia conservan su estado anterior.
+func (g *DeviationGuard) Check(ctx context.Context) error {
+	cached, err := g.cached(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read cached prices: %w", err)
+	}
+	if len(cached) == 0 {
+		return nil
+	}
+
+	pairs := make([]string, len(cached))
+	for i, price := range cached {
+		pairs[i] = price.Pair
+	}
+	reference, err := g.reference.GetTickers(ctx, pairs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch reference prices: %w", err)
+	}
+	referenceByPair := make(map[string]*entities.Price, len(reference))
+	for _, price := range reference {
+		referenceByPair[price.Pair] = price
+	}
+
+	for _, price := range cached {
+		ref, ok := referenceByPair[price.Pair]
+		if !ok || ref.Amount <= 0 {
+			continue
+		}
+		percent := math.Abs(price.Amount-ref.Amount) / ref.Amount * 100
+		g.observe(ctx, price, ref, percent)
+	}
+	return nil
+}
+
+// observe aplica el resultado de una comparación: marca el par al superar el
+// umbral y lo libera cuando vuelve a estar dentro
+func (g *DeviationGuard) observe(ctx context.Context, cached, reference *entities.Price, percent float64) {
+	deviating := percent > g.config.MaxDeviationPercent
+	metrics.UpdatePriceDeviation(cached.Pair, percent, deviating)
+
+	g.mu.Lock()
+	previous, wasFlagged := g.flagged[cached.Pair]
+	if deviating {
+		since := g.now()
+		if wasFlagged {
+			since = previous.Since
+		}
+		g.flagged[cached.Pair] = PriceDeviation{
+			Pair:      cached.Pair,
+			Cached:    cached.Amount,
+			Reference: reference.Amount,
+			Percent:   percent,
+			Since:     since,
+		}
+	} else {
+		delete(g.flagged, cached.Pair)
+	}
+	g.mu.Unlock()
+
+	fields := logging.Fields{
+		"pair":                  cached.Pair,
+		"cached_amount":         cached.Amount,
+		"cached_source":         cached.Source,
+		"reference_amount":      reference.Amount,
+		"deviation_percent":     percent,
+		"max_deviation_percent": g.config.MaxDeviationPercent,
+		"action":                g.config.Action,
+	}
+	switch {
+	case deviating && !wasFlagged:
+		metrics.RecordPriceDeviationAlert(cached.Pair)
+		logging.Warn(ctx, "Cached price deviates from reference source", fields)
+	case !deviating && wasFlagged:
+		fields["flagged_for"] = g.now().Sub(previous.Since).String()
+		logging.Info(ctx, "Cached price is back in line with reference source", fields)
+	}
+}
+
+// Start lanza la comparación periódica en background
+func (g *DeviationGuard) Start(ctx context.Context) error {
+	g.loopMu.Lock()
+	defer g.loopMu.Unlock()
+	if g.stop != nil {
+		return nil
+	}
+	g.stop = make(chan struct{})
+	g.done = make(chan struct{})
+
+	logging.Info(ctx, "Starting price deviation guard", logging.Fields{
+		"interval":              g.config.Interval.String(),
+		"max_deviation_percent": g.config.MaxDeviationPercent,
+		"action":                g.config.Action,
+	})
+	go g.loop(context.WithoutCancel(ctx), g.stop, g.done)
+	return nil
+}
+
+// Stop detiene la comparación periódica
+func (g *DeviationGuard) Stop(ctx context.Context) error {
+	g.loopMu.Lock()
+	stop, done := g.stop, g.done
+	g.stop, g.done = nil, nil
+	g.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *DeviationGuard) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(g.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, g.config.Interval)
+			if err := g.Check(checkCtx); err != nil {
+				logging.Warn(checkCtx, "Price deviation check failed", logging.Fields{"error": err.Error()})
+			}
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
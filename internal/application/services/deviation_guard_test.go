@@ -0,0 +1,85 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviationGuard_RefusesDeviatingPricesUntilTheyMatch(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer metrics.SetDefault(m)()
+
+	ctx := context.Background()
+	now := time.Now()
+	backend := cache.NewMemoryCache()
+	cached := NewPriceServiceWithTTL(&stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: now},
+		{Pair: "ETH/USD", Amount: 3000, Timestamp: now},
+	}}, backend, time.Minute, []string{"BTC/USD", "ETH/USD"})
+	require.NoError(t, cached.RefreshPrices(ctx, []string{"BTC/USD", "ETH/USD"}))
+
+	reference := &stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 52000, Timestamp: now}, // 3.8% de desvío
+		{Pair: "ETH/USD", Amount: 3001, Timestamp: now},
+	}}
+	guard := NewDeviationGuard(cached.GetCachedPrices, reference, DeviationGuardConfig{MaxDeviationPercent: 1, Action: DeviationActionRefuse})
+	guarded := NewPriceServiceWithTTL(&stubPrices{}, backend, time.Minute, []string{"BTC/USD", "ETH/USD"}, WithPriceGuard(guard))
+
+	require.NoError(t, guard.Check(ctx))
+
+	_, err = guarded.GetLastPrice(ctx, "BTC/USD")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, interfaces.ErrPriceDeviation))
+	_, err = guarded.GetLastPrice(ctx, "ETH/USD")
+	assert.NoError(t, err)
+	prices, err := guarded.GetCachedPrices(ctx)
+	require.NoError(t, err)
+	require.Len(t, prices, 1, "withheld prices are omitted from the cached list")
+	assert.Equal(t, "ETH/USD", prices[0].Pair)
+
+	require.Len(t, guard.Deviations(), 1)
+	assert.InDelta(t, 3.846, guard.Deviations()[0].Percent, 0.001)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.DeviationAlert.WithLabelValues("BTC/USD")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.Prices.DeviationAlert.WithLabelValues("ETH/USD")))
+
+	// Un segundo chequeo con el mismo desvío no repite la alerta
+	require.NoError(t, guard.Check(ctx))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.DeviationAlertsTotal.WithLabelValues("BTC/USD")))
+
+	reference.prices[0].Amount = 50100
+	require.NoError(t, guard.Check(ctx))
+	_, err = guarded.GetLastPrice(ctx, "BTC/USD")
+	assert.NoError(t, err, "the pair is served again once it matches the reference")
+	assert.Empty(t, guard.Deviations())
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.Prices.DeviationAlert.WithLabelValues("BTC/USD")))
+}
+
+func TestDeviationGuard_FlagActionKeepsServing(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer metrics.SetDefault(m)()
+
+	ctx := context.Background()
+	cached := func(context.Context) ([]*entities.Price, error) {
+		return []*entities.Price{{Pair: "BTC/USD", Amount: 50000}}, nil
+	}
+	reference := &stubPrices{prices: []*entities.Price{{Pair: "BTC/USD", Amount: 60000}}}
+	guard := NewDeviationGuard(cached, reference, DeviationGuardConfig{})
+
+	require.NoError(t, guard.Check(ctx))
+	assert.NoError(t, guard.Allow("BTC/USD"))
+	assert.Len(t, guard.Deviations(), 1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.DeviationAlertsTotal.WithLabelValues("BTC/USD")))
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultFeatureFlagsRefreshInterval es la frecuencia de relectura de overrides
+const DefaultFeatureFlagsRefreshInterval = 15 * time.Second
+
+// FeatureFlags resuelve flags declarados en configuración con overrides opcionales
+// guardados en el state repository. Cada réplica relee los overrides periódicamente,
+// así que un cambio se propaga en a lo sumo un intervalo de refresco.
+type FeatureFlags struct {
+	defaults map[string]bool
+	store    interfaces.StateRepository // nil = overrides deshabilitados
+	interval time.Duration
+
+	mu        sync.RWMutex
+	overrides map[string]bool
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewFeatureFlags crea el componente con los flags declarados en defaults. Un store
+// nil deshabilita los overrides; un intervalo no positivo usa el default.
+func NewFeatureFlags(defaults map[string]bool, store interfaces.StateRepository, refreshInterval time.Duration) *FeatureFlags {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultFeatureFlagsRefreshInterval
+	}
+	declared := make(map[string]bool, len(defaults))
+	for name, enabled := range defaults {
+		declared[name] = enabled
+	}
+	return &FeatureFlags{
+		defaults:  declared,
+		store:     store,
+		interval:  refreshInterval,
+		overrides: make(map[string]bool),
+	}
+}
+
+// Enabled indica si el flag está activo; los flags no declarados están deshabilitados
+func (f *FeatureFlags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if enabled, ok := f.overrides[name]; ok {
+		return enabled
+	}
+	return f.defaults[name]
+}
+
+// Snapshot devuelve el estado de todos los flags declarados, ordenados por nombre
+func (f *FeatureFlags) Snapshot() []interfaces.FeatureFlagState {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	states := make([]interfaces.FeatureFlagState, 0, len(f.defaults))
+	for name, def := range f.defaults {
+		state := interfaces.FeatureFlagState{Name: name, Enabled: def, Default: def}
+		if enabled, ok := f.overrides[name]; ok {
+			state.Enabled = enabled
+			state.Overridden = true
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// OverridesEnabled indica si los flags pueden sobrescribirse en runtime
+func (f *FeatureFlags) OverridesEnabled() bool {
+	return f.store != nil
+}
+
+// SetOverride persiste un override para un flag declarado y lo aplica localmente
+func (f *FeatureFlags) SetOverride(ctx context.Context, name string, enabled bool) error {
+	if err := f.checkOverridable(name); err != nil {
+		return err
+	}
+	if err := f.store.Put(ctx, interfaces.StateNamespaceFeatureFlags, name, strconv.FormatBool(enabled)); err != nil {
+		return fmt.Errorf("failed to store feature flag override: %w", err)
+	}
+
+	f.mu.Lock()
+	f.overrides[name] = enabled
+	f.mu.Unlock()
+
+	logging.Info(ctx, "Feature flag override set", logging.Fields{
+		"flag":    name,
+		"enabled": enabled,
+	})
+	return nil
+}
+
+// ClearOverride elimina el override de un flag, que vuelve a su valor por defecto
+func (f *FeatureFlags) ClearOverride(ctx context.Context, name string) error {
+	if err := f.checkOverridable(name); err != nil {
+		return err
+	}
+	if err := f.store.Delete(ctx, interfaces.StateNamespaceFeatureFlags, name); err != nil {
+		return fmt.Errorf("failed to delete feature flag override: %w", err)
+	}
+
+	f.mu.Lock()
+	delete(f.overrides, name)
+	f.mu.Unlock()
+
+	logging.Info(ctx, "Feature flag override cleared", logging.Fields{"flag": name})
+	return nil
+}
+
+func (f *FeatureFlags) checkOverridable(name string) error {
+	if f.store == nil {
+		return interfaces.ErrFeatureFlagOverridesDisabled
+	}
+	if _, ok := f.defaults[name]; !ok {
+		return fmt.Errorf("%w: %s", interfaces.ErrUnknownFeatureFlag, name)
+	}
+	return nil
+}
+
+// Sync relee los overrides del state repository. Los overrides de flags que ya no
+// están declarados o con valores no booleanos se ignoran.
+func (f *FeatureFlags) Sync(ctx context.Context) error {
+	if f.store == nil {
+		return nil
+	}
+	stored, err := f.store.List(ctx, interfaces.StateNamespaceFeatureFlags)
+	if err != nil {
+		return fmt.Errorf("failed to load feature flag overrides: %w", err)
+	}
+
+	overrides := make(map[string]bool, len(stored))
+	for name, raw := range stored {
+		if _, ok := f.defaults[name]; !ok {
+			continue
+		}
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			logging.Warn(ctx, "Ignoring invalid feature flag override", logging.Fields{
+				"flag":  name,
+				"value": raw,
+			})
+			continue
+		}
+		overrides[name] = enabled
+	}
+
+	f.mu.Lock()
+	f.overrides = overrides
+	f.mu.Unlock()
+	return nil
+}
+
+// Start carga los overrides y los relee periódicamente en background. Un fallo
+// en la carga inicial no impide arrancar: se usan los defaults.
+func (f *FeatureFlags) Start(ctx context.Context) error {
+	if f.store == nil {
+		return nil
+	}
+	if err := f.Sync(ctx); err != nil {
+		logging.Warn(ctx, "Using default feature flags", logging.Fields{"error": err.Error()})
+	}
+
+	f.loopMu.Lock()
+	defer f.loopMu.Unlock()
+	if f.stop != nil {
+		return nil
+	}
+	f.stop = make(chan struct{})
+	f.done = make(chan struct{})
+	go f.loop(context.WithoutCancel(ctx), f.stop, f.done)
+	return nil
+}
+
+// Stop detiene la relectura periódica
+func (f *FeatureFlags) Stop(ctx context.Context) error {
+	f.loopMu.Lock()
+	stop, done := f.stop, f.done
+	f.stop, f.done = nil, nil
+	f.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *FeatureFlags) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			syncCtx, cancel := context.WithTimeout(ctx, f.interval)
+			if err := f.Sync(syncCtx); err != nil {
+				logging.Warn(syncCtx, "Feature flag refresh failed", logging.Fields{"error": err.Error()})
+			}
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlags_DefaultsAndOverrides(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	flags := NewFeatureFlags(map[string]bool{"hedged_requests": false, "ws_protocol_v2": true}, store, time.Second)
+
+	assert.False(t, flags.Enabled("hedged_requests"))
+	assert.True(t, flags.Enabled("ws_protocol_v2"))
+	assert.False(t, flags.Enabled("undeclared"), "undeclared flags are disabled")
+
+	require.NoError(t, flags.SetOverride(ctx, "hedged_requests", true))
+	assert.True(t, flags.Enabled("hedged_requests"))
+	assert.Equal(t, []interfaces.FeatureFlagState{
+		{Name: "hedged_requests", Enabled: true, Default: false, Overridden: true},
+		{Name: "ws_protocol_v2", Enabled: true, Default: true},
+	}, flags.Snapshot())
+
+	require.NoError(t, flags.ClearOverride(ctx, "hedged_requests"))
+	assert.False(t, flags.Enabled("hedged_requests"))
+
+	assert.ErrorIs(t, flags.SetOverride(ctx, "undeclared", true), interfaces.ErrUnknownFeatureFlag)
+}
+
+func TestFeatureFlags_SyncPicksUpSharedOverrides(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	defaults := map[string]bool{"hedged_requests": false}
+	replicaA := NewFeatureFlags(defaults, store, time.Second)
+	replicaB := NewFeatureFlags(defaults, store, time.Second)
+
+	require.NoError(t, replicaA.SetOverride(ctx, "hedged_requests", true))
+	require.NoError(t, store.Put(ctx, interfaces.StateNamespaceFeatureFlags, "removed_flag", "true"))
+	assert.False(t, replicaB.Enabled("hedged_requests"), "other replicas see overrides after a sync")
+
+	require.NoError(t, replicaB.Sync(ctx))
+	assert.True(t, replicaB.Enabled("hedged_requests"))
+	assert.False(t, replicaB.Enabled("removed_flag"), "overrides of undeclared flags are ignored")
+}
+
+func TestFeatureFlags_OverridesDisabledWithoutStore(t *testing.T) {
+	flags := NewFeatureFlags(map[string]bool{"hedged_requests": true}, nil, 0)
+
+	assert.False(t, flags.OverridesEnabled())
+	assert.True(t, flags.Enabled("hedged_requests"))
+	assert.ErrorIs(t, flags.SetOverride(context.Background(), "hedged_requests", false), interfaces.ErrFeatureFlagOverridesDisabled)
+	require.NoError(t, flags.Start(context.Background()))
+	require.NoError(t, flags.Stop(context.Background()))
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// MaintenanceWindow es una ventana de mantenimiento del exchange; con Repeat > 0
+// se repite cada Repeat a partir de Start
+type MaintenanceWindow struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+	Repeat   time.Duration // 0 = única vez
+}
+
+// MaintenancePeriod es una ocurrencia concreta de una ventana
+type MaintenancePeriod struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// MaintenanceCalendar responde si hay una ventana de mantenimiento activa. Los
+// componentes de fondo lo consultan para no alertar ni refrescar de más mientras
+// el exchange está en mantenimiento.
+type MaintenanceCalendar struct {
+	windows []MaintenanceWindow
+	now     func() time.Time
+}
+
+// NewMaintenanceCalendar crea el calendario; las ventanas sin duración se ignoran
+func NewMaintenanceCalendar(windows []MaintenanceWindow) *MaintenanceCalendar {
+	calendar := &MaintenanceCalendar{now: time.Now}
+	for _, window := range windows {
+		if window.Duration > 0 {
+			calendar.windows = append(calendar.windows, window)
+		}
+	}
+	return calendar
+}
+
+// Active devuelve la ventana en curso en t; si se solapan varias, la que termina más tarde
+func (c *MaintenanceCalendar) Active(t time.Time) (MaintenancePeriod, bool) {
+	var active MaintenancePeriod
+	found := false
+	for _, window := range c.windows {
+		period, ok := window.occurrence(t)
+		if !ok || t.Before(period.Start) {
+			continue
+		}
+		if !found || period.End.After(active.End) {
+			active, found = period, true
+		}
+	}
+	return active, found
+}
+
+// Next devuelve la próxima ventana que empieza después de t
+func (c *MaintenanceCalendar) Next(t time.Time) (MaintenancePeriod, bool) {
+	var next MaintenancePeriod
+	found := false
+	for _, window := range c.windows {
+		period, ok := window.occurrence(t)
+		if ok && !t.Before(period.Start) && window.Repeat > 0 {
+			// En curso: la siguiente ocurrencia es la próxima repetición
+			period = MaintenancePeriod{Name: window.Name, Start: period.Start.Add(window.Repeat), End: period.End.Add(window.Repeat)}
+		} else if !ok || !t.Before(period.Start) {
+			continue
+		}
+		if !found || period.Start.Before(next.Start) {
+			next, found = period, true
+		}
+	}
+	return next, found
+}
+
+// InMaintenance reporta si hay una ventana activa ahora
+func (c *MaintenanceCalendar) InMaintenance() bool {
+	_, active := c.Active(c.now())
+	return active
+}
+
+// Describe resume el estado del calendario para /ready; nunca falla el chequeo
+func (c *MaintenanceCalendar) Describe(ctx context.Context) string {
+	now := c.now()
+	if period, ok := c.Active(now); ok {
+		return "active: " + period.Name + " until " + period.End.UTC().Format(time.RFC3339)
+	}
+	if period, ok := c.Next(now); ok {
+		return "none, next: " + period.Name + " at " + period.Start.UTC().Format(time.RFC3339)
+	}
+	return "none"
+}
+
+// occurrence devuelve la ocurrencia de la ventana que contiene t o, si no hay,
+// la siguiente; false si es una ventana única que ya terminó
+func (w MaintenanceWindow) occurrence(t time.Time) (MaintenancePeriod, bool) {
+	start := w.Start
+	if w.Repeat > 0 && t.After(start) {
+		start = start.Add(t.Sub(start) / w.Repeat * w.Repeat)
+		if !t.Before(start.Add(w.Duration)) {
+			start = start.Add(w.Repeat)
+		}
+	}
+	end := start.Add(w.Duration)
+	if !t.Before(end) {
+		return MaintenancePeriod{}, false
+	}
+	return MaintenancePeriod{Name: w.Name, Start: start, End: end}, true
+}
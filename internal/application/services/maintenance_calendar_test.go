@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceCalendar_WeeklyWindow(t *testing.T) {
+	start := time.Date(2026, 10, 6, 6, 0, 0, 0, time.UTC) // martes
+	calendar := NewMaintenanceCalendar([]MaintenanceWindow{
+		{Name: "weekly", Start: start, Duration: time.Hour, Repeat: 7 * 24 * time.Hour},
+	})
+
+	_, active := calendar.Active(start.Add(-time.Minute))
+	assert.False(t, active, "not active before the first occurrence")
+
+	insideLater := start.Add(14*24*time.Hour + 30*time.Minute)
+	period, active := calendar.Active(insideLater)
+	require.True(t, active)
+	assert.Equal(t, start.Add(14*24*time.Hour), period.Start)
+	assert.Equal(t, start.Add(14*24*time.Hour+time.Hour), period.End)
+
+	next, ok := calendar.Next(insideLater)
+	require.True(t, ok)
+	assert.Equal(t, start.Add(21*24*time.Hour), next.Start, "the next occurrence is the following week")
+
+	_, active = calendar.Active(start.Add(14*24*time.Hour + time.Hour))
+	assert.False(t, active, "the window end is exclusive")
+}
+
+func TestMaintenanceCalendar_OneOffWindowAndDescribe(t *testing.T) {
+	start := time.Date(2026, 10, 20, 6, 0, 0, 0, time.UTC)
+	calendar := NewMaintenanceCalendar([]MaintenanceWindow{
+		{Name: "upgrade", Start: start, Duration: 2 * time.Hour},
+		{Name: "invalid", Start: start},
+	})
+
+	now := start.Add(-time.Hour)
+	calendar.now = func() time.Time { return now }
+	assert.False(t, calendar.InMaintenance())
+	assert.Equal(t, "none, next: upgrade at 2026-10-20T06:00:00Z", calendar.Describe(context.Background()))
+
+	now = start.Add(time.Hour)
+	assert.True(t, calendar.InMaintenance())
+	assert.Equal(t, "active: upgrade until 2026-10-20T08:00:00Z", calendar.Describe(context.Background()))
+
+	now = start.Add(3 * time.Hour)
+	assert.False(t, calendar.InMaintenance())
+	assert.Equal(t, "none", calendar.Describe(context.Background()), "past one-off windows are not scheduled again")
+}
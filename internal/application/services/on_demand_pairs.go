@@ -0,0 +1,127 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const DefaultMaxOnDemandPairs = 50 // Pares bajo demanda admitidos a la vez
+
+// ErrOnDemandPairsFull indica que se alcanzó el máximo de pares bajo demanda
+var ErrOnDemandPairsFull = errors.New("on-demand pair limit reached")
+
+// OnDemandPairs amplía los pares soportados con pares pedidos por primera vez
+// que el exchange confirma operar. Los pares admitidos se suscriben al pedirse y
+// entran en el refresco periódico hasta que se quitan (p. ej. al podarse).
+type OnDemandPairs struct {
+	resolver  interfaces.PairResolver
+	maxPairs  int
+	supported []string
+	known     map[string]bool // Pares soportados por configuración
+
+	mu    sync.RWMutex
+	added []string // Pares admitidos, en orden de admisión
+}
+
+// NewOnDemandPairs crea el registro sobre supported; maxPairs no positivo usa
+// DefaultMaxOnDemandPairs
+func NewOnDemandPairs(supported []string, resolver interfaces.PairResolver, maxPairs int) *OnDemandPairs {
+	if maxPairs <= 0 {
+		maxPairs = DefaultMaxOnDemandPairs
+	}
+	known := make(map[string]bool, len(supported))
+	for _, pair := range supported {
+		known[entities.CanonicalPair(pair)] = true
+	}
+	return &OnDemandPairs{
+		resolver:  resolver,
+		maxPairs:  maxPairs,
+		supported: supported,
+		known:     known,
+	}
+}
+
+// Admit resuelve los pares de requested que no están soportados y retorna los
+// soportados más todos los admitidos. Los que el exchange no opera, o no caben
+// en el máximo, quedan fuera y la validación de la request los rechaza.
+func (p *OnDemandPairs) Admit(ctx context.Context, requested []string) []string {
+	for _, pair := range requested {
+		canonical, err := entities.NormalizePair(pair)
+		if err != nil || p.known[canonical] || p.Contains(canonical) {
+			continue
+		}
+		if err := p.admit(ctx, canonical); err != nil {
+			// Un par no listado es un error del cliente; el máximo alcanzado, del operador
+			log := logging.Debug
+			if errors.Is(err, ErrOnDemandPairsFull) {
+				log = logging.Warn
+			}
+			log(ctx, "On-demand pair rejected", logging.Fields{
+				"pair":  canonical,
+				"error": err.Error(),
+			})
+		}
+	}
+	return p.Pairs()
+}
+
+func (p *OnDemandPairs) admit(ctx context.Context, pair string) error {
+	resolved, err := p.resolver.ResolvePair(ctx, pair)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if slices.Contains(p.added, resolved) {
+		return nil // admitido por una request concurrente
+	}
+	if len(p.added) >= p.maxPairs {
+		return fmt.Errorf("%w: max %d", ErrOnDemandPairsFull, p.maxPairs)
+	}
+	p.added = append(p.added, resolved)
+	logging.Info(ctx, "On-demand pair admitted", logging.Fields{
+		"pair":            resolved,
+		"on_demand_count": len(p.added),
+	})
+	return nil
+}
+
+// Contains indica si pair fue admitido bajo demanda
+func (p *OnDemandPairs) Contains(pair string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Contains(p.added, entities.CanonicalPair(pair))
+}
+
+// Pairs retorna los pares soportados seguidos de los admitidos
+func (p *OnDemandPairs) Pairs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.added) == 0 {
+		return p.supported
+	}
+	return append(slices.Clip(p.supported), p.added...)
+}
+
+// Added retorna los pares admitidos bajo demanda
+func (p *OnDemandPairs) Added() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.added)
+}
+
+// Remove quita pares admitidos; los soportados por configuración se ignoran
+func (p *OnDemandPairs) Remove(pairs ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.added = slices.DeleteFunc(p.added, func(pair string) bool {
+		return slices.Contains(pairs, pair)
+	})
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listedPairs resuelve sólo los pares de la lista
+type listedPairs []string
+
+func (l listedPairs) ResolvePair(_ context.Context, pair string) (string, error) {
+	for _, listed := range l {
+		if listed == pair {
+			return pair, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", interfaces.ErrPairNotListed, pair)
+}
+
+func TestOnDemandPairs_Admit(t *testing.T) {
+	ctx := context.Background()
+	pairs := NewOnDemandPairs([]string{"BTC/USD"}, listedPairs{"ETH/USD", "SOL/USD", "DOT/USD"}, 2)
+
+	supported := pairs.Admit(ctx, []string{"btc/usd", "eth-usd", "FOO/BAR", "not a pair"})
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, supported)
+	assert.True(t, pairs.Contains("eth/usd"))
+	assert.False(t, pairs.Contains("BTC/USD"), "configured pairs are not on-demand pairs")
+
+	// Al alcanzar el máximo los pares nuevos quedan fuera
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD", "SOL/USD"}, pairs.Admit(ctx, []string{"SOL/USD", "DOT/USD"}))
+	assert.Equal(t, []string{"ETH/USD", "SOL/USD"}, pairs.Added())
+
+	pairs.Remove("ETH/USD", "BTC/USD")
+	assert.Equal(t, []string{"BTC/USD", "SOL/USD"}, pairs.Pairs())
+	assert.Equal(t, []string{"BTC/USD", "SOL/USD", "DOT/USD"}, pairs.Admit(ctx, []string{"DOT/USD"}))
+}
+
+func TestPriceService_FetchesOnDemandPairs(t *testing.T) {
+	ctx := context.Background()
+	exchange := &stubPrices{prices: []*entities.Price{{Pair: "ETH/USD", Amount: 3000, Timestamp: time.Now()}}}
+	pairs := NewOnDemandPairs([]string{"BTC/USD"}, listedPairs{"ETH/USD"}, 0)
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD"}, WithOnDemandPairs(pairs))
+
+	_, err := service.GetLastPrice(ctx, "ETH/USD")
+	assert.Error(t, err, "pairs not admitted stay cache-only")
+
+	pairs.Admit(ctx, []string{"ETH/USD"})
+	price, err := service.GetLastPrice(ctx, "ETH/USD")
+	require.NoError(t, err)
+	assert.Equal(t, 3000.0, price.Amount)
+}
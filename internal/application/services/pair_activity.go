@@ -0,0 +1,150 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultPairIdleAfter = 10 * time.Minute // Inactividad tras la que se poda un par
+
+// PairActivity registra cuándo se pidió cada par por última vez y decide qué
+// pares dejan de suscribirse y refrescarse por inactividad. Los pares que nunca
+// se pidieron cuentan desde el arranque, así que nada se poda antes de idleAfter.
+// La actividad es local a la réplica.
+type PairActivity struct {
+	idleAfter time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	since    time.Time            // Arranque o fin de la última retención de todos los pares
+	lastSeen map[string]time.Time // Última request por par
+	holds    map[string]int       // Retenciones de streams por par
+	holdAll  int                  // Retenciones de streams sobre todos los pares
+	pruned   map[string]bool
+}
+
+// NewPairActivity crea el registro de actividad; idleAfter no positivo usa
+// DefaultPairIdleAfter
+func NewPairActivity(idleAfter time.Duration) *PairActivity {
+	if idleAfter <= 0 {
+		idleAfter = DefaultPairIdleAfter
+	}
+	a := &PairActivity{
+		idleAfter: idleAfter,
+		now:       time.Now,
+		lastSeen:  make(map[string]time.Time),
+		holds:     make(map[string]int),
+		pruned:    make(map[string]bool),
+	}
+	a.since = a.now()
+	return a
+}
+
+// Touch registra una request de pair y lo reactiva; retorna true si estaba podado
+func (a *PairActivity) Touch(pair string) (revived bool) {
+	pair = entities.CanonicalPair(pair)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastSeen[pair] = a.now()
+	return a.unprune(pair)
+}
+
+// Hold mantiene activos pairs (todos si es vacío) mientras un stream los consume
+func (a *PairActivity) Hold(pairs []string) (release func()) {
+	canonical := make([]string, len(pairs))
+	for i, pair := range pairs {
+		canonical[i] = entities.CanonicalPair(pair)
+	}
+	pairs = canonical
+
+	a.mu.Lock()
+	if len(pairs) == 0 {
+		a.holdAll++
+		for pair := range a.pruned {
+			a.unprune(pair)
+		}
+	} else {
+		for _, pair := range pairs {
+			a.holds[pair]++
+			a.unprune(pair)
+		}
+	}
+	a.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { a.release(pairs) })
+	}
+}
+
+// release termina una retención; los pares cuentan como pedidos en ese momento
+func (a *PairActivity) release(pairs []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if len(pairs) == 0 {
+		a.holdAll--
+		a.since = now
+		return
+	}
+	for _, pair := range pairs {
+		if a.holds[pair]--; a.holds[pair] <= 0 {
+			delete(a.holds, pair)
+		}
+		a.lastSeen[pair] = now
+	}
+}
+
+// Prune separa pairs en los que siguen activos y los inactivos por más de
+// idleAfter, marcando estos como podados. newlyPruned son los que no estaban
+// podados en la llamada anterior.
+func (a *PairActivity) Prune(pairs []string) (active, newlyPruned []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	for _, pair := range pairs {
+		if a.holdAll > 0 || a.holds[pair] > 0 {
+			active = append(active, pair)
+			continue
+		}
+		seen := a.since
+		if last, ok := a.lastSeen[pair]; ok && last.After(seen) {
+			seen = last
+		}
+		if now.Sub(seen) < a.idleAfter {
+			active = append(active, pair)
+			continue
+		}
+		if !a.pruned[pair] {
+			a.pruned[pair] = true
+			newlyPruned = append(newlyPruned, pair)
+		}
+	}
+	metrics.UpdatePrunedPairs(len(a.pruned))
+	return active, newlyPruned
+}
+
+// Pruned retorna los pares podados, ordenados
+func (a *PairActivity) Pruned() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pairs := make([]string, 0, len(a.pruned))
+	for pair := range a.pruned {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// unprune quita pair de los podados; requiere a.mu
+func (a *PairActivity) unprune(pair string) bool {
+	if !a.pruned[pair] {
+		return false
+	}
+	delete(a.pruned, pair)
+	metrics.UpdatePrunedPairs(len(a.pruned))
+	return true
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPairActivity(idleAfter time.Duration) (*PairActivity, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	activity := NewPairActivity(idleAfter)
+	activity.now = func() time.Time { return now }
+	activity.since = now
+	return activity, &now
+}
+
+func TestPairActivity_PrunesIdlePairs(t *testing.T) {
+	activity, now := newTestPairActivity(10 * time.Minute)
+	pairs := []string{"BTC/USD", "ETH/USD", "LTC/USD"}
+
+	// Nada se poda antes de idleAfter desde el arranque
+	active, pruned := activity.Prune(pairs)
+	assert.Equal(t, pairs, active)
+	assert.Empty(t, pruned)
+
+	*now = now.Add(6 * time.Minute)
+	activity.Touch("btc/usd")
+	*now = now.Add(5 * time.Minute)
+
+	active, pruned = activity.Prune(pairs)
+	assert.Equal(t, []string{"BTC/USD"}, active)
+	assert.Equal(t, []string{"ETH/USD", "LTC/USD"}, pruned)
+	assert.Equal(t, []string{"ETH/USD", "LTC/USD"}, activity.Pruned())
+
+	// Los ya podados no se informan de nuevo
+	_, pruned = activity.Prune(pairs)
+	assert.Empty(t, pruned)
+
+	assert.True(t, activity.Touch("ETH/USD"), "touching a pruned pair revives it")
+	assert.False(t, activity.Touch("ETH/USD"))
+	assert.Equal(t, []string{"LTC/USD"}, activity.Pruned())
+}
+
+func TestPairActivity_HoldKeepsPairsActive(t *testing.T) {
+	activity, now := newTestPairActivity(time.Minute)
+	pairs := []string{"BTC/USD", "ETH/USD"}
+
+	*now = now.Add(2 * time.Minute)
+	_, pruned := activity.Prune(pairs)
+	require.Equal(t, pairs, pruned)
+
+	release := activity.Hold([]string{"eth/usd"})
+	assert.Equal(t, []string{"BTC/USD"}, activity.Pruned(), "holding a pruned pair revives it")
+	*now = now.Add(5 * time.Minute)
+	active, _ := activity.Prune(pairs)
+	assert.Equal(t, []string{"ETH/USD"}, active)
+
+	// Al liberar, el par cuenta como pedido en ese momento
+	release()
+	release()
+	*now = now.Add(30 * time.Second)
+	active, _ = activity.Prune(pairs)
+	assert.Equal(t, []string{"ETH/USD"}, active)
+	*now = now.Add(time.Minute)
+	active, _ = activity.Prune(pairs)
+	assert.Empty(t, active)
+
+	releaseAll := activity.Hold(nil)
+	assert.Empty(t, activity.Pruned())
+	active, _ = activity.Prune(pairs)
+	assert.Equal(t, pairs, active)
+	releaseAll()
+}
+
+func TestPriceService_FetchesPrunedPairsOnDemand(t *testing.T) {
+	ctx := context.Background()
+	exchange := &stubPrices{prices: []*entities.Price{{Pair: "BTC/USD", Amount: 50000, Timestamp: time.Now()}}}
+	activity, _ := newTestPairActivity(time.Minute)
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD"}, WithPairActivity(activity))
+
+	price, err := service.GetLastPrice(ctx, "BTC/USD")
+	require.NoError(t, err, "a cache miss is fetched on demand")
+	assert.Equal(t, 50000.0, price.Amount)
+
+	exchange.prices = nil
+	_, err = service.GetLastPrice(ctx, "ETH/USD")
+	assert.Error(t, err)
+}
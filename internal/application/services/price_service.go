@@ -5,10 +5,14 @@ import (
 	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/logging"
 	"btc-ltp-service/internal/infrastructure/metrics"
+	cachepkg "btc-ltp-service/internal/infrastructure/repositories/cache"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,27 +26,91 @@ type priceService struct {
 	exchange       interfaces.Exchange
 	cache          interfaces.Cache
 	cacheTTL       time.Duration
-	supportedPairs []string // Pares soportados para GetCachedPrices
+	supportedPairs []string                  // Pares soportados para GetCachedPrices
+	validator      interfaces.PriceValidator // Opcional: descarta ticks sospechosos antes de cachear
+	guard          interfaces.PriceGuard     // Opcional: retiene precios que se desvían de la referencia
+	budgetSplit    BudgetSplit               // Reparto del deadline de la request entre etapas
+	codec          interfaces.PriceCodec     // Opcional: serialización de los precios cacheados (JSON si es nil)
+	activity       *PairActivity             // Opcional: registra las requests por par para podar los inactivos
+	onDemand       *OnDemandPairs            // Opcional: pares admitidos fuera de supportedPairs
+
+	// prices escribe en cache con el compare-and-set de PriceCacheAdapter.Set
+	prices *cachepkg.PriceCacheAdapter
+
+	fetchMu  sync.Mutex
+	fetching map[string]chan struct{} // Obtenciones bajo demanda en curso, por par
+}
+
+// PriceServiceOption configura dependencias opcionales del servicio
+type PriceServiceOption func(*priceService)
+
+// WithPriceValidator valida cada precio contra el valor cacheado antes de almacenarlo
+func WithPriceValidator(validator interfaces.PriceValidator) PriceServiceOption {
+	return func(s *priceService) {
+		s.validator = validator
+	}
+}
+
+// WithPriceGuard deja de servir los precios cacheados que guard retiene
+func WithPriceGuard(guard interfaces.PriceGuard) PriceServiceOption {
+	return func(s *priceService) {
+		s.guard = guard
+	}
+}
+
+// WithPriceCodec serializa los precios cacheados con codec en lugar de JSON
+func WithPriceCodec(codec interfaces.PriceCodec) PriceServiceOption {
+	return func(s *priceService) {
+		s.codec = codec
+	}
+}
+
+// WithPairActivity registra cada GetLastPrice en activity; un cache miss obtiene
+// el precio bajo demanda porque el par puede estar podado
+func WithPairActivity(activity *PairActivity) PriceServiceOption {
+	return func(s *priceService) {
+		s.activity = activity
+	}
+}
+
+// WithOnDemandPairs obtiene bajo demanda el precio de los pares admitidos por
+// pairs mientras aún no están en caché (primera request)
+func WithOnDemandPairs(pairs *OnDemandPairs) PriceServiceOption {
+	return func(s *priceService) {
+		s.onDemand = pairs
+	}
 }
 
 // NewPriceService creates a new instance of the price service
 func NewPriceService(exchange interfaces.Exchange, cache interfaces.Cache, supportedPairs []string) interfaces.PriceService {
-	return &priceService{
+	s := &priceService{
 		exchange:       exchange,
 		cache:          cache,
 		cacheTTL:       DefaultCacheTTL,
 		supportedPairs: supportedPairs,
+		budgetSplit:    DefaultBudgetSplit,
 	}
+	s.prices = cachepkg.NewPriceCache(cache, s.cacheTTL)
+	return s
 }
 
 // NewPriceServiceWithTTL creates an instance with custom TTL
-func NewPriceServiceWithTTL(exchange interfaces.Exchange, cache interfaces.Cache, ttl time.Duration, supportedPairs []string) interfaces.PriceService {
-	return &priceService{
+func NewPriceServiceWithTTL(exchange interfaces.Exchange, cache interfaces.Cache, ttl time.Duration, supportedPairs []string, opts ...PriceServiceOption) interfaces.PriceService {
+	s := &priceService{
 		exchange:       exchange,
 		cache:          cache,
 		cacheTTL:       ttl,
 		supportedPairs: supportedPairs,
+		budgetSplit:    DefaultBudgetSplit,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.prices = cachepkg.NewPriceCache(cache, ttl)
+	if s.codec != nil {
+		s.prices.WithCodec(s.codec)
+	}
+	return s
 }
 
 // GetLastPrice implements CACHE-ONLY strategy - NO fallback to exchange
@@ -54,8 +122,19 @@ func (s *priceService) GetLastPrice(ctx context.Context, pair string) (*entities
 		"source":    "cache_only",
 	})
 
+	if s.activity != nil && s.activity.Touch(pair) {
+		logging.Info(ctx, "Pruned pair requested again, resuming refresh", logging.Fields{
+			"pair": pair,
+		})
+	}
+
 	// ONLY try to get from cache - NO fallback to exchange
 	cachedPrice, err := s.getPriceFromCache(ctx, pair)
+	var fetchErr error
+	if err != nil && s.fetchesOnMiss(pair) {
+		fetchErr = s.fetchOnDemand(ctx, pair)
+		cachedPrice, err = s.getPriceFromCache(ctx, pair)
+	}
 	if err != nil {
 		// Cache miss - return error, NO fallback to exchange
 		metrics.RecordCacheOperation("get", "miss")
@@ -67,7 +146,16 @@ func (s *priceService) GetLastPrice(ctx context.Context, pair string) (*entities
 			"policy": "cache_only_no_fallback",
 		})
 
-		return nil, fmt.Errorf("price not available in cache for %s (cache-only mode): %w", pair, err)
+		return nil, fmt.Errorf("price not available in cache for %s (cache-only mode): %w", pair, unavailableError(err, fetchErr))
+	}
+
+	if err := s.allow(pair); err != nil {
+		logging.Warn(ctx, "Withholding cached price", logging.Fields{
+			"pair":   pair,
+			"amount": cachedPrice.Amount,
+			"error":  err.Error(),
+		})
+		return nil, fmt.Errorf("price withheld for %s: %w", pair, err)
 	}
 
 	// Cache hit - return immediately
@@ -88,8 +176,33 @@ func (s *priceService) GetLastPrice(ctx context.Context, pair string) (*entities
 
 // RefreshPrices updates cache with fresh prices for multiple pairs
 func (s *priceService) RefreshPrices(ctx context.Context, pairs []string) error {
+	_, err := s.RefreshPricesReport(ctx, pairs)
+	return err
+}
+
+// RefreshPricesReport implementa interfaces.RefreshReporter: refresca pairs como
+// RefreshPrices e informa qué pares se escribieron en caché y cuáles no
+func (s *priceService) RefreshPricesReport(ctx context.Context, pairs []string) (interfaces.RefreshReport, error) {
+	return s.refresh(ctx, pairs, s.exchange.GetTickers)
+}
+
+// RefreshPricesFromSource implementa interfaces.SourceRefresher: con un exchange
+// que consulta REST sin pasar por su caché (interfaces.WarmupExchange) refresca
+// desde ahí; si no, equivale a RefreshPrices
+func (s *priceService) RefreshPricesFromSource(ctx context.Context, pairs []string) error {
+	source, ok := s.exchange.(interfaces.WarmupExchange)
+	if !ok {
+		return s.RefreshPrices(ctx, pairs)
+	}
+	_, err := s.refresh(ctx, pairs, source.WarmupTickers)
+	return err
+}
+
+// refresh obtiene pairs con fetch y los escribe en caché
+func (s *priceService) refresh(ctx context.Context, pairs []string, fetch func(context.Context, []string) ([]*entities.Price, error)) (interfaces.RefreshReport, error) {
+	var report interfaces.RefreshReport
 	if len(pairs) == 0 {
-		return nil
+		return report, nil
 	}
 
 	logging.Info(ctx, "Starting price refresh operation", logging.Fields{
@@ -97,19 +210,34 @@ func (s *priceService) RefreshPrices(ctx context.Context, pairs []string) error
 		"pairs":       pairs,
 	})
 
+	// Con deadline, el exchange recibe las partes WebSocket y REST del presupuesto;
+	// la parte de caché queda reservada para las escrituras posteriores
+	fetchCtx := ctx
+	if budget, ok := s.budgetFor(ctx); ok {
+		logging.Debug(ctx, "Refresh deadline budget", logging.Fields{
+			"cache_ms":     budget.Cache.Milliseconds(),
+			"websocket_ms": budget.WebSocket.Milliseconds(),
+			"rest_ms":      budget.REST.Milliseconds(),
+		})
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(entities.WithFetchBudget(ctx, budget), budget.WebSocket+budget.REST)
+		defer cancel()
+	}
+
 	// Get prices from exchange (batch operation)
 	exchangeStart := time.Now()
-	prices, err := s.exchange.GetTickers(ctx, pairs)
+	prices, err := fetch(fetchCtx, pairs)
 	exchangeDuration := time.Since(exchangeStart)
 
 	if err != nil {
-		metrics.PriceRefreshesTotal.WithLabelValues("error").Inc()
+		metrics.RecordPriceRefresh("error")
 		logging.ErrorWithError(ctx, "Failed to refresh prices from exchange", err, logging.Fields{
 			"pairs_count":          len(pairs),
 			"pairs":                pairs,
 			"exchange_duration_ms": float64(exchangeDuration.Nanoseconds()) / 1e6,
 		})
-		return fmt.Errorf("failed to refresh prices from exchange: %w", err)
+		report.Failed = slices.Clone(pairs)
+		return report, fmt.Errorf("failed to refresh prices from exchange: %w", err)
 	}
 
 	logging.Info(ctx, "Successfully retrieved prices from exchange", logging.Fields{
@@ -119,20 +247,36 @@ func (s *priceService) RefreshPrices(ctx context.Context, pairs []string) error
 	})
 
 	// Cache all prices
-	var errors []string
+	var cacheErrors []string
 	successCount := 0
 	for _, price := range prices {
-		if err := s.cachePrice(ctx, price); err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", price.Pair, err))
+		if s.validator != nil {
+			previous, _ := s.getPriceFromCache(ctx, price.Pair)
+			if err := s.validator.Validate(ctx, price, previous); err != nil {
+				continue // en cuarentena: se conserva el valor cacheado previo
+			}
+		}
+
+		if err := s.cachePrice(ctx, price); errors.Is(err, cachepkg.ErrStaleWrite) {
+			// Otro refresco ya cacheó un precio más reciente: el par está actualizado
+			report.Refreshed = append(report.Refreshed, price.Pair)
+			logging.Debug(ctx, "Skipped caching an older price", logging.Fields{
+				"pair":  price.Pair,
+				"error": err.Error(),
+			})
+		} else if err != nil {
+			cacheErrors = append(cacheErrors, fmt.Sprintf("%s: %v", price.Pair, err))
 			logging.Warn(ctx, "Failed to cache individual price", logging.Fields{
 				"pair":  price.Pair,
 				"error": err.Error(),
 			})
 		} else {
 			successCount++
+			report.Refreshed = append(report.Refreshed, price.Pair)
 			metrics.RecordCacheOperation("set", "success")
 			metrics.UpdateCurrentPrice(price.Pair, price.Amount)
 			metrics.UpdatePriceAge(price.Pair, 0) // Fresh price
+			metrics.RecordPricePipelineLatency(price.Pair, price.Source, metrics.PipelineStageCache, price.ReceivedTime)
 
 			logging.CacheOperation(ctx, "set", s.cacheKey(price.Pair), true, logging.Fields{
 				"pair":   price.Pair,
@@ -141,23 +285,31 @@ func (s *priceService) RefreshPrices(ctx context.Context, pairs []string) error
 		}
 	}
 
-	if len(errors) > 0 {
-		metrics.PriceRefreshesTotal.WithLabelValues("error").Inc()
+	for _, pair := range pairs {
+		if !slices.ContainsFunc(report.Refreshed, func(refreshed string) bool {
+			return entities.CanonicalPair(refreshed) == entities.CanonicalPair(pair)
+		}) {
+			report.Failed = append(report.Failed, pair)
+		}
+	}
+
+	if len(cacheErrors) > 0 {
+		metrics.RecordPriceRefresh("error")
 		logging.Error(ctx, "Failed to cache some prices during refresh", logging.Fields{
-			"failed_count":  len(errors),
+			"failed_count":  len(cacheErrors),
 			"success_count": successCount,
-			"errors":        errors,
+			"errors":        cacheErrors,
 		})
-		return fmt.Errorf("failed to cache some prices: %s", strings.Join(errors, ", "))
+		return report, fmt.Errorf("failed to cache some prices: %s", strings.Join(cacheErrors, ", "))
 	}
 
-	metrics.PriceRefreshesTotal.WithLabelValues("success").Inc()
+	metrics.RecordPriceRefresh("success")
 	logging.Info(ctx, "Successfully completed price refresh operation", logging.Fields{
 		"pairs_count":   len(pairs),
 		"cached_count":  len(prices),
 		"success_count": successCount,
 	})
-	return nil
+	return report, nil
 }
 
 // GetCachedPrices returns all prices currently in cache for supported pairs
@@ -176,6 +328,9 @@ func (s *priceService) GetCachedPrices(ctx context.Context) ([]*entities.Price,
 
 	for _, pair := range s.supportedPairs {
 		if price, err := s.getPriceFromCache(ctx, pair); err == nil {
+			if s.allow(pair) != nil {
+				continue // retenido por el guard: se omite como un par sin precio
+			}
 			cachedPrices = append(cachedPrices, price)
 			logging.Debug(ctx, "Found cached price for pair", logging.Fields{
 				"pair":   pair,
@@ -198,39 +353,213 @@ func (s *priceService) GetCachedPrices(ctx context.Context) ([]*entities.Price,
 	return cachedPrices, nil
 }
 
+// GetPriceWithMetadata returns the cached price for a pair with its source and age (cache-only)
+func (s *priceService) GetPriceWithMetadata(ctx context.Context, pair string) (*entities.PriceMetadata, error) {
+	price, err := s.GetLastPrice(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	source := price.Source
+	if source == "" {
+		source = entities.PriceSourceUnknown
+	}
+
+	return &entities.PriceMetadata{
+		Price:    price,
+		Source:   source,
+		Age:      price.Age,
+		CacheKey: s.cacheKey(pair),
+	}, nil
+}
+
+// DeletePrice removes the cached price for a pair; deleting a missing pair is not an error
+func (s *priceService) DeletePrice(ctx context.Context, pair string) error {
+	key := s.cacheKey(pair)
+
+	if err := s.cache.Delete(ctx, key); err != nil {
+		metrics.RecordCacheOperation("delete", "error")
+		logging.CacheOperation(ctx, "delete", key, false, logging.Fields{
+			"pair":  pair,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to delete cached price for %s: %w", pair, err)
+	}
+
+	metrics.RecordCacheOperation("delete", "success")
+	logging.CacheOperation(ctx, "delete", key, true, logging.Fields{
+		"pair": pair,
+	})
+	return nil
+}
+
+// InvalidatePrices removes the cached prices for pairs from the service cache and,
+// when the exchange keeps its own cache, from the exchange too
+func (s *priceService) InvalidatePrices(ctx context.Context, pairs []string) error {
+	keys := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		keys = append(keys, s.cacheKey(pair))
+	}
+
+	if err := s.cache.DeleteMany(ctx, keys); err != nil {
+		metrics.RecordCacheOperation("delete", "error")
+		logging.Warn(ctx, "Failed to invalidate cached prices", logging.Fields{
+			"pairs": pairs,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to invalidate cached prices: %w", err)
+	}
+	metrics.RecordCacheOperation("delete", "success")
+
+	if invalidator, ok := s.exchange.(interfaces.PriceCacheInvalidator); ok {
+		if err := invalidator.InvalidatePrices(ctx, pairs); err != nil {
+			return fmt.Errorf("failed to invalidate exchange prices: %w", err)
+		}
+	}
+
+	logging.Info(ctx, "Invalidated cached prices", logging.Fields{
+		"pairs": pairs,
+	})
+	return nil
+}
+
+// FlushPrices removes every cached price (keys under CacheKeyPrefix) from the
+// service cache and the exchange cache; other keys in the backend are kept
+func (s *priceService) FlushPrices(ctx context.Context) (int, error) {
+	removed, err := s.cache.Flush(ctx, CacheKeyPrefix)
+	if err != nil {
+		metrics.RecordCacheOperation("flush", "error")
+		logging.Warn(ctx, "Failed to flush cached prices", logging.Fields{
+			"error": err.Error(),
+		})
+		return removed, fmt.Errorf("failed to flush cached prices: %w", err)
+	}
+	metrics.RecordCacheOperation("flush", "success")
+
+	if invalidator, ok := s.exchange.(interfaces.PriceCacheInvalidator); ok {
+		if _, err := invalidator.FlushPrices(ctx); err != nil {
+			return removed, fmt.Errorf("failed to flush exchange prices: %w", err)
+		}
+	}
+
+	logging.Info(ctx, "Flushed cached prices", logging.Fields{
+		"removed": removed,
+	})
+	return removed, nil
+}
+
+// fetchesOnMiss indica si un cache miss de pair se obtiene del exchange, como
+// excepción a cache-only: con poda el par puede estar podado (aquí o en la réplica
+// líder, que es la que refresca) y un par bajo demanda aún no se refrescó
+func (s *priceService) fetchesOnMiss(pair string) bool {
+	return s.activity != nil || (s.onDemand != nil && s.onDemand.Contains(pair))
+}
+
+// fetchOnDemand obtiene del exchange el precio de un par que no se está
+// refrescando y retorna el error del refresco. Las requests concurrentes del mismo
+// par esperan a la obtención en curso en lugar de repetirla (y retornan nil).
+func (s *priceService) fetchOnDemand(ctx context.Context, pair string) error {
+	key := entities.CanonicalPair(pair)
+	s.fetchMu.Lock()
+	if done, inFlight := s.fetching[key]; inFlight {
+		s.fetchMu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+	if s.fetching == nil {
+		s.fetching = make(map[string]chan struct{})
+	}
+	done := make(chan struct{})
+	s.fetching[key] = done
+	s.fetchMu.Unlock()
+
+	defer func() {
+		s.fetchMu.Lock()
+		delete(s.fetching, key)
+		s.fetchMu.Unlock()
+		close(done)
+	}()
+	err := s.RefreshPrices(ctx, []string{key})
+	if err != nil {
+		logging.Warn(ctx, "Failed to fetch price on demand", logging.Fields{
+			"pair":  key,
+			"error": err.Error(),
+		})
+	}
+	return err
+}
+
+// unavailableError clasifica la falta de precio en caché con los errores de
+// PriceService. Si hubo una obtención bajo demanda, su error explica mejor la
+// falta; un error de caché que no es un miss (p. ej. Redis caído) se retorna tal cual.
+func unavailableError(cacheErr, fetchErr error) error {
+	switch {
+	case errors.Is(fetchErr, interfaces.ErrRateLimited):
+		return fetchErr
+	case errors.Is(fetchErr, interfaces.ErrPairNotListed):
+		return fmt.Errorf("%w: %w", interfaces.ErrPairUnsupported, fetchErr)
+	case fetchErr != nil:
+		return fmt.Errorf("%w: %w", interfaces.ErrUpstreamUnavailable, fetchErr)
+	case errors.Is(cacheErr, interfaces.ErrCacheExpired):
+		return fmt.Errorf("%w: %w", interfaces.ErrStaleData, cacheErr)
+	case errors.Is(cacheErr, interfaces.ErrCacheMiss):
+		return fmt.Errorf("%w: %w", interfaces.ErrUpstreamUnavailable, cacheErr)
+	}
+	return cacheErr
+}
+
 // getPriceFromCache retrieves and deserializes a price from cache
 func (s *priceService) getPriceFromCache(ctx context.Context, pair string) (*entities.Price, error) {
+	defer entities.RequestTimingsFrom(ctx).Observe(entities.TimingCache, time.Now())
 	key := s.cacheKey(pair)
 
-	priceJSON, err := s.cache.Get(ctx, key)
+	data, err := s.cache.Get(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 
-	var price entities.Price
-	if err := json.Unmarshal([]byte(priceJSON), &price); err != nil {
+	price, err := s.unmarshalPrice([]byte(data))
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached price for %s: %w", pair, err)
 	}
 
-	// Update price age
-	price.Age = time.Since(price.Timestamp)
+	// Update price age (desde la hora del exchange si está disponible)
+	price.Age = price.AgeAt(time.Now())
 
-	return &price, nil
+	return price, nil
 }
 
-// cachePrice serializes and stores a price in cache
-func (s *priceService) cachePrice(ctx context.Context, price *entities.Price) error {
-	key := s.cacheKey(price.Pair)
+func (s *priceService) unmarshalPrice(data []byte) (*entities.Price, error) {
+	if s.codec != nil {
+		return s.codec.Unmarshal(data)
+	}
+	var price entities.Price
+	if err := json.Unmarshal(data, &price); err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
 
-	priceJSON, err := json.Marshal(price)
-	if err != nil {
-		return fmt.Errorf("failed to marshal price for %s: %w", price.Pair, err)
+// allow consulta el guard, si hay uno configurado
+func (s *priceService) allow(pair string) error {
+	if s.guard == nil {
+		return nil
 	}
+	return s.guard.Allow(pair)
+}
 
-	return s.cache.Set(ctx, key, string(priceJSON), s.cacheTTL)
+// cachePrice serializes and stores a price in cache. PriceCacheAdapter.Set no
+// reemplaza un precio cacheado más reciente (refrescos concurrentes del
+// scheduler, POST /ltp/refresh o max_age) y retorna cache.ErrStaleWrite.
+func (s *priceService) cachePrice(ctx context.Context, price *entities.Price) error {
+	defer entities.RequestTimingsFrom(ctx).Observe(entities.TimingCache, time.Now())
+	return s.prices.Set(ctx, price)
 }
 
 // cacheKey generates the cache key for a pair
 func (s *priceService) cacheKey(pair string) string {
-	return CacheKeyPrefix + strings.ToUpper(pair)
+	return CacheKeyPrefix + entities.CanonicalPair(pair)
 }
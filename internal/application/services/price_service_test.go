@@ -0,0 +1,199 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceService_GetPriceWithMetadataAndDeletePrice(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	exchange := &stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: now.Add(-2 * time.Second), Source: entities.PriceSourceREST},
+		{Pair: "ETH/USD", Amount: 3000, Timestamp: now},
+	}}
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD", "ETH/USD"})
+	require.NoError(t, service.RefreshPrices(ctx, []string{"BTC/USD", "ETH/USD"}))
+
+	meta, err := service.GetPriceWithMetadata(ctx, "btc/usd")
+	require.NoError(t, err)
+	assert.Equal(t, 50000.0, meta.Price.Amount)
+	assert.Equal(t, entities.PriceSourceREST, meta.Source)
+	assert.Equal(t, "price:BTC/USD", meta.CacheKey)
+	assert.GreaterOrEqual(t, meta.Age, 2*time.Second)
+
+	meta, err = service.GetPriceWithMetadata(ctx, "ETH/USD")
+	require.NoError(t, err)
+	assert.Equal(t, entities.PriceSourceUnknown, meta.Source)
+
+	require.NoError(t, service.DeletePrice(ctx, "BTC/USD"))
+	_, err = service.GetLastPrice(ctx, "BTC/USD")
+	assert.Error(t, err)
+	_, err = service.GetPriceWithMetadata(ctx, "BTC/USD")
+	assert.Error(t, err)
+
+	assert.NoError(t, service.DeletePrice(ctx, "BTC/USD"), "deleting a missing pair is not an error")
+	_, err = service.GetLastPrice(ctx, "ETH/USD")
+	assert.NoError(t, err)
+}
+
+func TestPriceService_InvalidateAndFlushPrices(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	exchange := &invalidatingStub{stubPrices: stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: now},
+		{Pair: "ETH/USD", Amount: 3000, Timestamp: now},
+	}}}
+	backend := cache.NewMemoryCache()
+	require.NoError(t, backend.Set(ctx, "leader:lock", "instance-1", time.Minute))
+	service := NewPriceServiceWithTTL(exchange, backend, time.Minute, []string{"BTC/USD", "ETH/USD"})
+	require.NoError(t, service.RefreshPrices(ctx, []string{"BTC/USD", "ETH/USD"}))
+
+	require.NoError(t, service.InvalidatePrices(ctx, []string{"btc/usd"}))
+	_, err := service.GetLastPrice(ctx, "BTC/USD")
+	assert.Error(t, err)
+	_, err = service.GetLastPrice(ctx, "ETH/USD")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"btc/usd"}, exchange.invalidated, "exchange cache must be purged too")
+
+	removed, err := service.FlushPrices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.True(t, exchange.flushed)
+	_, err = service.GetLastPrice(ctx, "ETH/USD")
+	assert.Error(t, err)
+
+	_, err = backend.Get(ctx, "leader:lock")
+	assert.NoError(t, err, "flush must only remove price keys")
+}
+
+// invalidatingStub registra las invalidaciones propagadas al exchange
+type invalidatingStub struct {
+	stubPrices
+	invalidated []string
+	flushed     bool
+}
+
+func (s *invalidatingStub) InvalidatePrices(_ context.Context, pairs []string) error {
+	s.invalidated = append(s.invalidated, pairs...)
+	return nil
+}
+
+func (s *invalidatingStub) FlushPrices(context.Context) (int, error) {
+	s.flushed = true
+	return 0, nil
+}
+
+// stubPrices implementa interfaces.Exchange con precios fijos
+type stubPrices struct {
+	prices []*entities.Price
+}
+
+func (s *stubPrices) GetTickers(context.Context, []string) ([]*entities.Price, error) {
+	return s.prices, nil
+}
+
+func (s *stubPrices) GetTicker(_ context.Context, pair string) (*entities.Price, error) {
+	for _, p := range s.prices {
+		if p.Pair == pair {
+			return p, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func TestPriceService_RefreshPricesReport(t *testing.T) {
+	ctx := context.Background()
+	exchange := &stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: time.Now()},
+	}}
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD", "ETH/USD"})
+	reporter, ok := service.(interfaces.RefreshReporter)
+	require.True(t, ok)
+
+	report, err := reporter.RefreshPricesReport(ctx, []string{"BTC/USD", "ETH/USD"})
+	require.NoError(t, err, "a pair missing from the exchange response does not fail the refresh")
+	assert.Equal(t, []string{"BTC/USD"}, report.Refreshed)
+	assert.Equal(t, []string{"ETH/USD"}, report.Failed)
+}
+
+func TestPriceService_RefreshKeepsNewerCachedPrice(t *testing.T) {
+	ctx := context.Background()
+	second := time.Now().Truncate(time.Second)
+	tick := &entities.Price{Pair: "BTC/USD", Amount: 50100, Timestamp: second.Add(700 * time.Millisecond),
+		ExchangeTime: second.Add(700 * time.Millisecond), ReceivedTime: second.Add(720 * time.Millisecond)}
+	exchange := &stubPrices{prices: []*entities.Price{tick}}
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD"})
+	reporter := service.(interfaces.RefreshReporter)
+	require.NoError(t, service.RefreshPrices(ctx, []string{"BTC/USD"}))
+
+	// Un refresco concurrente que obtuvo el precio antes que el tick cacheado
+	exchange.prices = []*entities.Price{{Pair: "BTC/USD", Amount: 49900, Timestamp: second.Add(-time.Second),
+		ExchangeTime: second.Add(-time.Second), ReceivedTime: second.Add(-500 * time.Millisecond)}}
+	report, err := reporter.RefreshPricesReport(ctx, []string{"BTC/USD"})
+	require.NoError(t, err, "an older price is skipped, not a cache failure")
+	assert.Equal(t, []string{"BTC/USD"}, report.Refreshed)
+	price, err := service.GetLastPrice(ctx, "BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, 50100.0, price.Amount, "the older price must not overwrite the newer one")
+
+	// REST en el mismo segundo que el tick, recibido después: el header Date lo
+	// trunca al segundo pero no es más viejo
+	exchange.prices = []*entities.Price{{Pair: "BTC/USD", Amount: 50200, Timestamp: second,
+		ExchangeTime: second, ReceivedTime: second.Add(900 * time.Millisecond), Source: entities.PriceSourceREST}}
+	require.NoError(t, service.RefreshPrices(ctx, []string{"BTC/USD"}))
+	price, err = service.GetLastPrice(ctx, "BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, 50200.0, price.Amount)
+}
+
+func TestPriceService_GetLastPriceTypedErrors(t *testing.T) {
+	ctx := context.Background()
+	exchange := &stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: time.Now()},
+	}}
+	service := NewPriceServiceWithTTL(exchange, cache.NewMemoryCache(), 20*time.Millisecond, []string{"BTC/USD", "ETH/USD"})
+
+	_, err := service.GetLastPrice(ctx, "ETH/USD")
+	assert.ErrorIs(t, err, interfaces.ErrUpstreamUnavailable, "a pair never refreshed is a miss")
+
+	require.NoError(t, service.RefreshPrices(ctx, []string{"BTC/USD"}))
+	time.Sleep(40 * time.Millisecond)
+	_, err = service.GetLastPrice(ctx, "BTC/USD")
+	assert.ErrorIs(t, err, interfaces.ErrStaleData, "an expired entry is stale data")
+	assert.False(t, errors.Is(err, interfaces.ErrUpstreamUnavailable))
+}
+
+func TestUnavailableError(t *testing.T) {
+	rateLimited := fmt.Errorf("HTTP 429: %w", interfaces.ErrRateLimited)
+	notListed := fmt.Errorf("%w: DOGE/MOON", interfaces.ErrPairNotListed)
+	redisDown := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		cacheErr error
+		fetchErr error
+		want     error
+	}{
+		{"miss", cache.ErrKeyNotFound, nil, interfaces.ErrUpstreamUnavailable},
+		{"expired", cache.ErrKeyExpired, nil, interfaces.ErrStaleData},
+		{"fetch rate limited", cache.ErrKeyNotFound, rateLimited, interfaces.ErrRateLimited},
+		{"fetch not listed", cache.ErrKeyNotFound, notListed, interfaces.ErrPairUnsupported},
+		{"fetch failed", cache.ErrKeyExpired, assert.AnError, interfaces.ErrUpstreamUnavailable},
+		{"cache failure", redisDown, nil, redisDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, unavailableError(tt.cacheErr, tt.fetchErr), tt.want)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxJumpPercent = 20.0            // Variación máxima aceptada respecto al precio previo
+	DefaultMaxFutureSkew  = 5 * time.Second // Tolerancia para timestamps adelantados
+	DefaultQuarantineSize = 50              // Ticks sospechosos retenidos por par
+)
+
+// QuarantinedPrice es un tick rechazado junto con el motivo
+type QuarantinedPrice struct {
+	Price      *entities.Price `json:"price"`
+	Reason     string          `json:"reason"`
+	Error      string          `json:"error"`
+	Quarantine time.Time       `json:"quarantined_at"`
+}
+
+// PriceValidatorConfig parametriza los límites de sanidad
+type PriceValidatorConfig struct {
+	MaxJumpPercent float64
+	MaxFutureSkew  time.Duration
+	QuarantineSize int
+}
+
+// PriceSanityValidator implementa interfaces.PriceValidator con cuarentena en memoria
+type PriceSanityValidator struct {
+	config PriceValidatorConfig
+	now    func() time.Time
+
+	mu         sync.RWMutex
+	quarantine map[string][]QuarantinedPrice
+}
+
+// NewPriceSanityValidator crea un validador; valores no positivos usan los defaults
+func NewPriceSanityValidator(config PriceValidatorConfig) *PriceSanityValidator {
+	if config.MaxJumpPercent <= 0 {
+		config.MaxJumpPercent = DefaultMaxJumpPercent
+	}
+	if config.MaxFutureSkew <= 0 {
+		config.MaxFutureSkew = DefaultMaxFutureSkew
+	}
+	if config.QuarantineSize <= 0 {
+		config.QuarantineSize = DefaultQuarantineSize
+	}
+	return &PriceSanityValidator{
+		config:     config,
+		now:        time.Now,
+		quarantine: make(map[string][]QuarantinedPrice),
+	}
+}
+
+// Validate rechaza precios no positivos, timestamps futuros y saltos mayores a MaxJumpPercent
+func (v *PriceSanityValidator) Validate(ctx context.Context, candidate, previous *entities.Price) error {
+	if candidate == nil {
+		return fmt.Errorf("%w: nil price", entities.ErrNonPositivePrice)
+	}
+
+	err := candidate.Validate(v.now(), v.config.MaxFutureSkew)
+	if err == nil && previous != nil && previous.Amount > 0 {
+		change := math.Abs(candidate.Amount-previous.Amount) / previous.Amount * 100
+		if change > v.config.MaxJumpPercent {
+			err = fmt.Errorf("%w: %s moved %.2f%% (%v -> %v), max %.2f%%",
+				entities.ErrPriceJump, candidate.Pair, change, previous.Amount, candidate.Amount, v.config.MaxJumpPercent)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	reason := rejectionReason(err)
+	v.store(candidate, reason, err)
+	metrics.RecordPriceQuarantined(candidate.Pair, reason)
+	logging.Warn(ctx, "Price tick quarantined", logging.Fields{
+		"pair":   candidate.Pair,
+		"amount": candidate.Amount,
+		"reason": reason,
+		"error":  err.Error(),
+	})
+	return err
+}
+
+// Quarantined retorna los ticks en cuarentena de un par (más recientes al final)
+func (v *PriceSanityValidator) Quarantined(pair string) []QuarantinedPrice {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	items := v.quarantine[strings.ToUpper(pair)]
+	out := make([]QuarantinedPrice, len(items))
+	copy(out, items)
+	return out
+}
+
+func (v *PriceSanityValidator) store(price *entities.Price, reason string, err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := strings.ToUpper(price.Pair)
+	items := append(v.quarantine[key], QuarantinedPrice{
+		Price:      price,
+		Reason:     reason,
+		Error:      err.Error(),
+		Quarantine: v.now(),
+	})
+	if len(items) > v.config.QuarantineSize {
+		items = items[len(items)-v.config.QuarantineSize:]
+	}
+	v.quarantine[key] = items
+}
+
+// rejectionReason traduce el error en una etiqueta de baja cardinalidad
+func rejectionReason(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrNonPositivePrice):
+		return "non_positive"
+	case errors.Is(err, entities.ErrFutureTimestamp):
+		return "future_timestamp"
+	case errors.Is(err, entities.ErrPriceJump):
+		return "jump"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(size int) (*PriceSanityValidator, time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	v := NewPriceSanityValidator(PriceValidatorConfig{MaxJumpPercent: 10, MaxFutureSkew: time.Second, QuarantineSize: size})
+	v.now = func() time.Time { return now }
+	return v, now
+}
+
+func TestPriceSanityValidator_Rejections(t *testing.T) {
+	v, now := newTestValidator(10)
+	ctx := context.Background()
+	previous := &entities.Price{Pair: "BTC/USD", Amount: 100, Timestamp: now}
+
+	tests := []struct {
+		name      string
+		candidate *entities.Price
+		previous  *entities.Price
+		expected  error
+	}{
+		{name: "precio válido", candidate: &entities.Price{Pair: "BTC/USD", Amount: 105, Timestamp: now}, previous: previous},
+		{name: "sin precio previo", candidate: &entities.Price{Pair: "BTC/USD", Amount: 500, Timestamp: now}},
+		{name: "precio cero", candidate: &entities.Price{Pair: "BTC/USD", Amount: 0, Timestamp: now}, expected: entities.ErrNonPositivePrice},
+		{name: "precio negativo", candidate: &entities.Price{Pair: "BTC/USD", Amount: -1, Timestamp: now}, expected: entities.ErrNonPositivePrice},
+		{name: "timestamp futuro", candidate: &entities.Price{Pair: "BTC/USD", Amount: 100, Timestamp: now.Add(time.Minute)}, expected: entities.ErrFutureTimestamp},
+		{name: "salto excesivo", candidate: &entities.Price{Pair: "BTC/USD", Amount: 120, Timestamp: now}, previous: previous, expected: entities.ErrPriceJump},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(ctx, tt.candidate, tt.previous)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.expected), "expected %v, got %v", tt.expected, err)
+		})
+	}
+
+	quarantined := v.Quarantined("btc/usd")
+	require.Len(t, quarantined, 4)
+	assert.Equal(t, "non_positive", quarantined[0].Reason)
+	assert.Equal(t, "future_timestamp", quarantined[2].Reason)
+	assert.Equal(t, "jump", quarantined[3].Reason)
+}
+
+func TestPriceSanityValidator_QuarantineBounded(t *testing.T) {
+	v, now := newTestValidator(3)
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		_ = v.Validate(ctx, &entities.Price{Pair: "ETH/USD", Amount: -float64(i), Timestamp: now}, nil)
+	}
+
+	quarantined := v.Quarantined("ETH/USD")
+	require.Len(t, quarantined, 3)
+	assert.Equal(t, -3.0, quarantined[0].Price.Amount)
+	assert.Equal(t, -5.0, quarantined[2].Price.Amount)
+	assert.Empty(t, v.Quarantined("BTC/USD"))
+}
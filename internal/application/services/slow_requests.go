@@ -0,0 +1,97 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"time"
+)
+
+// slowRequestLogger decora un PriceService: mide cada request de precio por etapa
+// y loguea las que superan threshold con el desglose de dónde se fue el tiempo
+type slowRequestLogger struct {
+	interfaces.PriceService
+	threshold time.Duration
+}
+
+// NewSlowRequestLogger envuelve inner para loguear y contar las requests de precio
+// más lentas que threshold; con threshold <= 0 retorna inner sin envolver
+func NewSlowRequestLogger(inner interfaces.PriceService, threshold time.Duration) interfaces.PriceService {
+	if threshold <= 0 {
+		return inner
+	}
+	return &slowRequestLogger{PriceService: inner, threshold: threshold}
+}
+
+// GetLastPrice delega en el servicio midiendo la request
+func (s *slowRequestLogger) GetLastPrice(ctx context.Context, pair string) (*entities.Price, error) {
+	ctx, done := s.measure(ctx, pair)
+	price, err := s.PriceService.GetLastPrice(ctx, pair)
+	done(err)
+	return price, err
+}
+
+// GetPriceWithMetadata delega en el servicio midiendo la request
+func (s *slowRequestLogger) GetPriceWithMetadata(ctx context.Context, pair string) (*entities.PriceMetadata, error) {
+	ctx, done := s.measure(ctx, pair)
+	metadata, err := s.PriceService.GetPriceWithMetadata(ctx, pair)
+	done(err)
+	return metadata, err
+}
+
+// RefreshPricesReport conserva el detalle por par del servicio envuelto, que el
+// refresher obtiene por type assertion
+func (s *slowRequestLogger) RefreshPricesReport(ctx context.Context, pairs []string) (interfaces.RefreshReport, error) {
+	if reporter, ok := s.PriceService.(interfaces.RefreshReporter); ok {
+		return reporter.RefreshPricesReport(ctx, pairs)
+	}
+	if err := s.PriceService.RefreshPrices(ctx, pairs); err != nil {
+		return interfaces.RefreshReport{Failed: pairs}, err
+	}
+	return interfaces.RefreshReport{Refreshed: pairs}, nil
+}
+
+// RefreshPricesFromSource delega en el servicio envuelto si saltea la caché del
+// exchange; si no, refresca como RefreshPrices
+func (s *slowRequestLogger) RefreshPricesFromSource(ctx context.Context, pairs []string) error {
+	if source, ok := s.PriceService.(interfaces.SourceRefresher); ok {
+		return source.RefreshPricesFromSource(ctx, pairs)
+	}
+	return s.PriceService.RefreshPrices(ctx, pairs)
+}
+
+// measure adjunta un acumulador de tiempos a ctx; done cierra la medición con el
+// resultado de la request y la reporta si fue lenta
+func (s *slowRequestLogger) measure(ctx context.Context, pair string) (context.Context, func(error)) {
+	timings := entities.NewRequestTimings()
+	start := time.Now()
+	return entities.WithRequestTimings(ctx, timings), func(err error) {
+		elapsed := time.Since(start)
+		if elapsed < s.threshold {
+			return
+		}
+
+		metrics.RecordSlowPriceRequest(pair)
+		durations := timings.Durations()
+		fields := logging.Fields{
+			"pair":         pair,
+			"duration_ms":  elapsed.Milliseconds(),
+			"threshold_ms": s.threshold.Milliseconds(),
+			"cache_ms":     durations[entities.TimingCache].Milliseconds(),
+			"ws_ms":        durations[entities.TimingWebSocket].Milliseconds(),
+			"rest_ms":      durations[entities.TimingREST].Milliseconds(),
+			"retries":      timings.Retries(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		logging.Warn(ctx, "Slow price request", fields)
+	}
+}
+
+var (
+	_ interfaces.RefreshReporter = (*slowRequestLogger)(nil)
+	_ interfaces.SourceRefresher = (*slowRequestLogger)(nil)
+)
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowPrices simula un servicio cuya request pasa delay en REST con un reintento
+type slowPrices struct {
+	interfaces.PriceService
+	delay   time.Duration
+	timings *entities.RequestTimings
+}
+
+func (s *slowPrices) GetLastPrice(ctx context.Context, pair string) (*entities.Price, error) {
+	s.timings = entities.RequestTimingsFrom(ctx)
+	start := time.Now()
+	time.Sleep(s.delay)
+	s.timings.Retry()
+	s.timings.Observe(entities.TimingREST, start)
+	return &entities.Price{Pair: pair, Amount: 50000}, nil
+}
+
+func TestSlowRequestLogger_CountsRequestsOverThreshold(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	inner := &slowPrices{delay: 20 * time.Millisecond}
+	service := NewSlowRequestLogger(inner, 10*time.Millisecond)
+
+	price, err := service.GetLastPrice(context.Background(), "BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, 50000.0, price.Amount)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.SlowRequestsTotal.WithLabelValues("BTC/USD")))
+	require.NotNil(t, inner.timings, "the decorator attaches timings to the context")
+	assert.GreaterOrEqual(t, inner.timings.Durations()[entities.TimingREST], 20*time.Millisecond)
+	assert.Equal(t, 1, inner.timings.Retries())
+
+	inner.delay = 0
+	_, err = service.GetLastPrice(context.Background(), "BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Prices.SlowRequestsTotal.WithLabelValues("BTC/USD")), "fast requests are not counted")
+}
+
+func TestSlowRequestLogger_DisabledAndReporter(t *testing.T) {
+	inner := NewPriceServiceWithTTL(&stubPrices{prices: []*entities.Price{
+		{Pair: "BTC/USD", Amount: 50000, Timestamp: time.Now()},
+	}}, cache.NewMemoryCache(), time.Minute, []string{"BTC/USD", "ETH/USD"})
+	assert.Same(t, inner, NewSlowRequestLogger(inner, 0))
+
+	reporter, ok := NewSlowRequestLogger(inner, time.Second).(interfaces.RefreshReporter)
+	require.True(t, ok, "the refresher keeps its per-pair report")
+	report, err := reporter.RefreshPricesReport(context.Background(), []string{"BTC/USD", "ETH/USD"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USD"}, report.Refreshed)
+	assert.Equal(t, []string{"ETH/USD"}, report.Failed)
+}
+
+func TestRequestTimings_NilIsNoop(t *testing.T) {
+	timings := entities.RequestTimingsFrom(context.Background())
+	assert.Nil(t, timings)
+	timings.Observe(entities.TimingCache, time.Now())
+	timings.Retry()
+	assert.Zero(t, timings.Retries())
+	assert.Nil(t, timings.Durations())
+}
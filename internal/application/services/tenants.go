@@ -0,0 +1,334 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTenantsRefreshInterval es la frecuencia de relectura de los tenants gestionados en runtime
+const DefaultTenantsRefreshInterval = 15 * time.Second
+
+// storedTenant es la forma persistida de un tenant creado vía admin; sólo se
+// guardan hashes de las API keys
+type storedTenant struct {
+	APIKeyHashes []string `json:"api_key_hashes"`
+	Pairs        []string `json:"pairs,omitempty"`
+	Capacity     int      `json:"rate_limit_capacity,omitempty"`
+	RefillRate   int      `json:"rate_limit_refill_rate,omitempty"`
+}
+
+// registeredTenant es un tenant indexado por los hashes de sus API keys
+type registeredTenant struct {
+	tenant *entities.Tenant // sin API keys
+	hashes []string
+	source string
+}
+
+// TenantRegistry resuelve tenants declarados en configuración más, si hay store,
+// los creados vía API de administración. Cada réplica relee los del state
+// repository periódicamente, igual que FeatureFlags.
+type TenantRegistry struct {
+	configured   map[string]registeredTenant
+	operatorHash string
+	store        interfaces.StateRepository // nil = gestión en runtime deshabilitada
+	interval     time.Duration
+
+	mu     sync.RWMutex
+	stored map[string]registeredTenant
+	byHash map[string]*entities.Tenant
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewTenantRegistry crea el registro con los tenants de la configuración (ya
+// validados). operatorKey queda reservada para el operador; un store nil
+// deshabilita la gestión en runtime.
+func NewTenantRegistry(tenants []entities.Tenant, operatorKey string, store interfaces.StateRepository, refreshInterval time.Duration) *TenantRegistry {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultTenantsRefreshInterval
+	}
+	registry := &TenantRegistry{
+		configured: make(map[string]registeredTenant, len(tenants)),
+		store:      store,
+		interval:   refreshInterval,
+		stored:     make(map[string]registeredTenant),
+	}
+	if operatorKey != "" {
+		registry.operatorHash = hashAPIKey(operatorKey)
+	}
+	for _, tenant := range tenants {
+		registry.configured[tenant.Name] = newRegisteredTenant(tenant, interfaces.TenantSourceConfig)
+	}
+	registry.reindex()
+	return registry
+}
+
+func newRegisteredTenant(tenant entities.Tenant, source string) registeredTenant {
+	hashes := make([]string, len(tenant.APIKeys))
+	for i, key := range tenant.APIKeys {
+		hashes[i] = hashAPIKey(key)
+	}
+	tenant.APIKeys = nil
+	tenant.Pairs = canonicalPairs(tenant.Pairs)
+	return registeredTenant{tenant: &tenant, hashes: hashes, source: source}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalPairs(pairs []string) []string {
+	if len(pairs) == 0 {
+		return nil
+	}
+	canonical := make([]string, len(pairs))
+	for i, pair := range pairs {
+		canonical[i] = entities.CanonicalPair(pair)
+	}
+	return canonical
+}
+
+// TenantByAPIKey implementa interfaces.TenantDirectory
+func (r *TenantRegistry) TenantByAPIKey(apiKey string) (*entities.Tenant, bool) {
+	if apiKey == "" {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tenant, ok := r.byHash[hashAPIKey(apiKey)]
+	return tenant, ok
+}
+
+// Tenants devuelve todos los tenants ordenados por nombre
+func (r *TenantRegistry) Tenants() []interfaces.TenantState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	states := make([]interfaces.TenantState, 0, len(r.configured)+len(r.stored))
+	for _, registered := range r.all() {
+		states = append(states, interfaces.TenantState{
+			Tenant:   *registered.tenant,
+			Source:   registered.source,
+			KeyCount: len(registered.hashes),
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Tenant.Name < states[j].Tenant.Name })
+	return states
+}
+
+// ManagementEnabled indica si los tenants pueden gestionarse en runtime
+func (r *TenantRegistry) ManagementEnabled() bool {
+	return r.store != nil
+}
+
+// PutTenant crea o reemplaza un tenant gestionado en runtime. Sus API keys no
+// pueden estar en uso por otro tenant ni ser la del operador.
+func (r *TenantRegistry) PutTenant(ctx context.Context, tenant entities.Tenant) error {
+	if r.store == nil {
+		return interfaces.ErrTenantManagementDisabled
+	}
+	if _, ok := r.configured[tenant.Name]; ok {
+		return fmt.Errorf("%w: %s", interfaces.ErrTenantReadOnly, tenant.Name)
+	}
+
+	registered := newRegisteredTenant(tenant, interfaces.TenantSourceAdmin)
+	r.mu.RLock()
+	for _, hash := range registered.hashes {
+		if owner, taken := r.byHash[hash]; (taken && owner.Name != tenant.Name) || hash == r.operatorHash {
+			r.mu.RUnlock()
+			return fmt.Errorf("%w: tenant %s", interfaces.ErrTenantConflict, tenant.Name)
+		}
+	}
+	r.mu.RUnlock()
+
+	value, err := json.Marshal(storedTenant{
+		APIKeyHashes: registered.hashes,
+		Pairs:        registered.tenant.Pairs,
+		Capacity:     registered.tenant.RateLimitCapacity,
+		RefillRate:   registered.tenant.RateLimitRefillRate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode tenant: %w", err)
+	}
+	if err := r.store.Put(ctx, interfaces.StateNamespaceTenants, tenant.Name, string(value)); err != nil {
+		return fmt.Errorf("failed to store tenant: %w", err)
+	}
+
+	r.mu.Lock()
+	r.stored[tenant.Name] = registered
+	r.reindex()
+	r.mu.Unlock()
+
+	logging.Info(ctx, "Tenant stored", logging.Fields{
+		"tenant":    tenant.Name,
+		"pairs":     registered.tenant.Pairs,
+		"key_count": len(registered.hashes),
+	})
+	return nil
+}
+
+// DeleteTenant elimina un tenant gestionado en runtime; sus API keys dejan de ser válidas
+func (r *TenantRegistry) DeleteTenant(ctx context.Context, name string) error {
+	if r.store == nil {
+		return interfaces.ErrTenantManagementDisabled
+	}
+	if _, ok := r.configured[name]; ok {
+		return fmt.Errorf("%w: %s", interfaces.ErrTenantReadOnly, name)
+	}
+	r.mu.RLock()
+	_, exists := r.stored[name]
+	r.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("%w: %s", interfaces.ErrUnknownTenant, name)
+	}
+	if err := r.store.Delete(ctx, interfaces.StateNamespaceTenants, name); err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+
+	r.mu.Lock()
+	delete(r.stored, name)
+	r.reindex()
+	r.mu.Unlock()
+
+	logging.Info(ctx, "Tenant deleted", logging.Fields{"tenant": name})
+	return nil
+}
+
+// Sync relee los tenants del state repository. Se ignoran los que repiten el
+// nombre de un tenant de configuración o tienen valores inválidos.
+func (r *TenantRegistry) Sync(ctx context.Context) error {
+	if r.store == nil {
+		return nil
+	}
+	values, err := r.store.List(ctx, interfaces.StateNamespaceTenants)
+	if err != nil {
+		return fmt.Errorf("failed to load tenants: %w", err)
+	}
+
+	stored := make(map[string]registeredTenant, len(values))
+	for name, raw := range values {
+		var value storedTenant
+		if _, configured := r.configured[name]; configured || json.Unmarshal([]byte(raw), &value) != nil {
+			logging.Warn(ctx, "Ignoring invalid stored tenant", logging.Fields{"tenant": name})
+			continue
+		}
+		stored[name] = registeredTenant{
+			tenant: &entities.Tenant{
+				Name:                name,
+				Pairs:               canonicalPairs(value.Pairs),
+				RateLimitCapacity:   value.Capacity,
+				RateLimitRefillRate: value.RefillRate,
+			},
+			hashes: value.APIKeyHashes,
+			source: interfaces.TenantSourceAdmin,
+		}
+	}
+
+	r.mu.Lock()
+	r.stored = stored
+	r.reindex()
+	r.mu.Unlock()
+	return nil
+}
+
+// all devuelve los tenants de configuración y los gestionados (requiere lock)
+func (r *TenantRegistry) all() []registeredTenant {
+	all := make([]registeredTenant, 0, len(r.configured)+len(r.stored))
+	for _, registered := range r.configured {
+		all = append(all, registered)
+	}
+	for _, registered := range r.stored {
+		all = append(all, registered)
+	}
+	return all
+}
+
+// reindex reconstruye el índice por hash de API key (requiere lock de escritura).
+// Los tenants de configuración ganan ante una key repetida y la del operador
+// nunca identifica a un tenant.
+func (r *TenantRegistry) reindex() {
+	byHash := make(map[string]*entities.Tenant)
+	for _, registered := range r.stored {
+		for _, hash := range registered.hashes {
+			byHash[hash] = registered.tenant
+		}
+	}
+	for _, registered := range r.configured {
+		for _, hash := range registered.hashes {
+			byHash[hash] = registered.tenant
+		}
+	}
+	delete(byHash, r.operatorHash)
+	r.byHash = byHash
+}
+
+// Start carga los tenants gestionados y los relee periódicamente en background.
+// Un fallo en la carga inicial no impide arrancar: se usan los de configuración.
+func (r *TenantRegistry) Start(ctx context.Context) error {
+	if r.store == nil {
+		return nil
+	}
+	if err := r.Sync(ctx); err != nil {
+		logging.Warn(ctx, "Using configured tenants only", logging.Fields{"error": err.Error()})
+	}
+
+	r.loopMu.Lock()
+	defer r.loopMu.Unlock()
+	if r.stop != nil {
+		return nil
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.loop(context.WithoutCancel(ctx), r.stop, r.done)
+	return nil
+}
+
+// Stop detiene la relectura periódica
+func (r *TenantRegistry) Stop(ctx context.Context) error {
+	r.loopMu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *TenantRegistry) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			syncCtx, cancel := context.WithTimeout(ctx, r.interval)
+			if err := r.Sync(syncCtx); err != nil {
+				logging.Warn(syncCtx, "Tenant refresh failed", logging.Fields{"error": err.Error()})
+			}
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantRegistry_ResolvesKeysAndProtectsConfiguredTenants(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	registry := NewTenantRegistry([]entities.Tenant{
+		{Name: "team-a", APIKeys: []string{"key-a"}, Pairs: []string{"btc/usd"}},
+	}, "operator-key", store, time.Second)
+
+	tenant, ok := registry.TenantByAPIKey("key-a")
+	require.True(t, ok)
+	assert.Equal(t, "team-a", tenant.Name)
+	assert.Equal(t, []string{"BTC/USD"}, tenant.Pairs, "pairs are stored in canonical form")
+	assert.Empty(t, tenant.APIKeys, "resolved tenants do not carry their keys")
+	_, ok = registry.TenantByAPIKey("operator-key")
+	assert.False(t, ok, "the operator key never identifies a tenant")
+
+	assert.ErrorIs(t, registry.PutTenant(ctx, entities.Tenant{Name: "team-a", APIKeys: []string{"other"}}), interfaces.ErrTenantReadOnly)
+	assert.ErrorIs(t, registry.DeleteTenant(ctx, "team-a"), interfaces.ErrTenantReadOnly)
+	assert.ErrorIs(t, registry.PutTenant(ctx, entities.Tenant{Name: "team-b", APIKeys: []string{"key-a"}}), interfaces.ErrTenantConflict)
+	assert.ErrorIs(t, registry.PutTenant(ctx, entities.Tenant{Name: "team-b", APIKeys: []string{"operator-key"}}), interfaces.ErrTenantConflict)
+	assert.ErrorIs(t, registry.DeleteTenant(ctx, "team-b"), interfaces.ErrUnknownTenant)
+}
+
+func TestTenantRegistry_ManagedTenantsAreSharedHashed(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	replicaA := NewTenantRegistry(nil, "operator-key", store, time.Second)
+	replicaB := NewTenantRegistry(nil, "operator-key", store, time.Second)
+
+	require.NoError(t, replicaA.PutTenant(ctx, entities.Tenant{
+		Name: "team-b", APIKeys: []string{"key-b1", "key-b2"}, Pairs: []string{"ETH/USD"}, RateLimitCapacity: 5, RateLimitRefillRate: 1,
+	}))
+	stored, err := store.Get(ctx, interfaces.StateNamespaceTenants, "team-b")
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(stored, "key-b1"), "API keys are stored hashed")
+
+	_, ok := replicaB.TenantByAPIKey("key-b2")
+	assert.False(t, ok, "other replicas see new tenants after a sync")
+	require.NoError(t, replicaB.Sync(ctx))
+	tenant, ok := replicaB.TenantByAPIKey("key-b2")
+	require.True(t, ok)
+	assert.Equal(t, 5, tenant.RateLimitCapacity)
+	assert.Equal(t, []interfaces.TenantState{{
+		Tenant:   entities.Tenant{Name: "team-b", Pairs: []string{"ETH/USD"}, RateLimitCapacity: 5, RateLimitRefillRate: 1},
+		Source:   interfaces.TenantSourceAdmin,
+		KeyCount: 2,
+	}}, replicaB.Tenants())
+
+	// Reemplazar el tenant revoca las keys anteriores
+	require.NoError(t, replicaA.PutTenant(ctx, entities.Tenant{Name: "team-b", APIKeys: []string{"key-b3"}}))
+	_, ok = replicaA.TenantByAPIKey("key-b1")
+	assert.False(t, ok)
+
+	require.NoError(t, replicaA.DeleteTenant(ctx, "team-b"))
+	require.NoError(t, replicaB.Sync(ctx))
+	_, ok = replicaB.TenantByAPIKey("key-b2")
+	assert.False(t, ok)
+	assert.Empty(t, replicaB.Tenants())
+}
+
+func TestTenantRegistry_ManagementDisabledWithoutStore(t *testing.T) {
+	registry := NewTenantRegistry([]entities.Tenant{{Name: "team-a", APIKeys: []string{"key-a"}}}, "", nil, 0)
+
+	assert.False(t, registry.ManagementEnabled())
+	assert.ErrorIs(t, registry.PutTenant(context.Background(), entities.Tenant{Name: "team-b", APIKeys: []string{"key-b"}}), interfaces.ErrTenantManagementDisabled)
+	assert.Equal(t, []interfaces.TenantState{{Tenant: entities.Tenant{Name: "team-a"}, Source: interfaces.TenantSourceConfig, KeyCount: 1}}, registry.Tenants())
+	require.NoError(t, registry.Start(context.Background()))
+	require.NoError(t, registry.Stop(context.Background()))
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultUsageFlushInterval = time.Minute // Frecuencia de escritura de los contadores
+	DefaultUsageRetentionDays = 90          // Días de agregados diarios conservados
+)
+
+// usageKey identifica un contador diario
+type usageKey struct {
+	date   string
+	tenant string
+	keyID  string
+}
+
+type usageCounts struct {
+	requests       int64
+	streamMessages int64
+}
+
+// storedUsage es el agregado diario de una réplica; cada réplica escribe sólo sus
+// propios registros, así que no hay escrituras concurrentes sobre la misma clave
+type storedUsage struct {
+	Date           string `json:"date"`
+	Instance       string `json:"instance"`
+	Tenant         string `json:"tenant,omitempty"`
+	KeyID          string `json:"key_id"`
+	Requests       int64  `json:"requests"`
+	StreamMessages int64  `json:"stream_messages"`
+}
+
+// UsageMeter cuenta requests y mensajes del stream por API key y día. Los
+// contadores viven en memoria y se guardan acumulados en el state repository
+// cada flushInterval; el reporte suma los registros de todas las réplicas.
+type UsageMeter struct {
+	store         interfaces.StateRepository
+	instanceID    string
+	flushInterval time.Duration
+	retentionDays int
+	now           func() time.Time
+
+	mu     sync.Mutex
+	counts map[usageKey]*usageCounts
+	dirty  map[usageKey]bool
+
+	flushMu    sync.Mutex
+	lastPruned string
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewUsageMeter crea el medidor de uso de esta réplica; valores no positivos usan los defaults
+func NewUsageMeter(store interfaces.StateRepository, instanceID string, flushInterval time.Duration, retentionDays int) *UsageMeter {
+	if flushInterval <= 0 {
+		flushInterval = DefaultUsageFlushInterval
+	}
+	if retentionDays <= 0 {
+		retentionDays = DefaultUsageRetentionDays
+	}
+	return &UsageMeter{
+		store:         store,
+		instanceID:    instanceID,
+		flushInterval: flushInterval,
+		retentionDays: retentionDays,
+		now:           time.Now,
+		counts:        make(map[usageKey]*usageCounts),
+		dirty:         make(map[usageKey]bool),
+	}
+}
+
+// RecordRequest implementa interfaces.UsageRecorder
+func (u *UsageMeter) RecordRequest(ctx context.Context) {
+	u.record(ctx, 1, 0)
+}
+
+// RecordStreamMessages implementa interfaces.UsageRecorder
+func (u *UsageMeter) RecordStreamMessages(ctx context.Context, count int) {
+	if count > 0 {
+		u.record(ctx, 0, int64(count))
+	}
+}
+
+func (u *UsageMeter) record(ctx context.Context, requests, streamMessages int64) {
+	keyID := entities.APIKeyIDFrom(ctx)
+	if keyID == "" {
+		return
+	}
+	key := usageKey{date: u.now().UTC().Format(interfaces.UsageDateLayout), keyID: keyID}
+	if tenant, ok := entities.TenantFrom(ctx); ok {
+		key.tenant = tenant.Name
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	counts, ok := u.counts[key]
+	if !ok {
+		counts = &usageCounts{}
+		u.counts[key] = counts
+	}
+	counts.requests += requests
+	counts.streamMessages += streamMessages
+	u.dirty[key] = true
+}
+
+// RetentionDays implementa interfaces.UsageReporter
+func (u *UsageMeter) RetentionDays() int {
+	return u.retentionDays
+}
+
+// Usage implementa interfaces.UsageReporter. Los contadores de esta réplica
+// todavía no guardados se incluyen en el reporte.
+func (u *UsageMeter) Usage(ctx context.Context, from, to time.Time, tenant string) ([]interfaces.UsageRecord, error) {
+	stored, err := u.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u.mu.Lock()
+	for key, counts := range u.counts {
+		record := u.storedRecord(key, counts)
+		stored[u.storeKey(key)] = record
+	}
+	u.mu.Unlock()
+
+	first, last := from.UTC().Format(interfaces.UsageDateLayout), to.UTC().Format(interfaces.UsageDateLayout)
+	totals := make(map[usageKey]*interfaces.UsageRecord)
+	for _, record := range stored {
+		if record.Date < first || record.Date > last || (tenant != "" && record.Tenant != tenant) {
+			continue
+		}
+		key := usageKey{date: record.Date, tenant: record.Tenant, keyID: record.KeyID}
+		total, ok := totals[key]
+		if !ok {
+			total = &interfaces.UsageRecord{Date: record.Date, Tenant: record.Tenant, KeyID: record.KeyID}
+			totals[key] = total
+		}
+		total.Requests += record.Requests
+		total.StreamMessages += record.StreamMessages
+	}
+
+	records := make([]interfaces.UsageRecord, 0, len(totals))
+	for _, total := range totals {
+		records = append(records, *total)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		return a.KeyID < b.KeyID
+	})
+	return records, nil
+}
+
+// Flush guarda los contadores modificados desde el último flush y descarta de
+// memoria los de días anteriores ya guardados. Una vez por día borra los
+// agregados fuera de la retención.
+func (u *UsageMeter) Flush(ctx context.Context) error {
+	u.flushMu.Lock()
+	defer u.flushMu.Unlock()
+
+	u.mu.Lock()
+	pending := make(map[usageKey]storedUsage, len(u.dirty))
+	for key := range u.dirty {
+		pending[key] = u.storedRecord(key, u.counts[key])
+	}
+	u.dirty = make(map[usageKey]bool)
+	u.mu.Unlock()
+
+	var failed []usageKey
+	var flushErr error
+	for key, record := range pending {
+		value, err := json.Marshal(record)
+		if err == nil {
+			err = u.store.Put(ctx, interfaces.StateNamespaceUsage, u.storeKey(key), string(value))
+		}
+		if err != nil {
+			failed = append(failed, key)
+			flushErr = fmt.Errorf("failed to store usage: %w", err)
+		}
+	}
+
+	today := u.now().UTC().Format(interfaces.UsageDateLayout)
+	u.mu.Lock()
+	for _, key := range failed {
+		u.dirty[key] = true
+	}
+	for key := range u.counts {
+		if key.date < today && !u.dirty[key] {
+			delete(u.counts, key)
+		}
+	}
+	u.mu.Unlock()
+
+	if flushErr == nil && u.lastPruned != today {
+		if err := u.prune(ctx, today); err != nil {
+			return err
+		}
+		u.lastPruned = today
+	}
+	return flushErr
+}
+
+// prune borra los agregados de días fuera de la retención (de todas las réplicas)
+func (u *UsageMeter) prune(ctx context.Context, today string) error {
+	day, err := time.Parse(interfaces.UsageDateLayout, today)
+	if err != nil {
+		return err
+	}
+	oldest := day.AddDate(0, 0, 1-u.retentionDays).Format(interfaces.UsageDateLayout)
+
+	values, err := u.store.List(ctx, interfaces.StateNamespaceUsage)
+	if err != nil {
+		return fmt.Errorf("failed to list usage: %w", err)
+	}
+	deleted := 0
+	for key, raw := range values {
+		var record storedUsage
+		if json.Unmarshal([]byte(raw), &record) == nil && record.Date >= oldest {
+			continue
+		}
+		if err := u.store.Delete(ctx, interfaces.StateNamespaceUsage, key); err != nil {
+			return fmt.Errorf("failed to delete usage: %w", err)
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		logging.Info(ctx, "Pruned expired usage aggregates", logging.Fields{
+			"deleted":        deleted,
+			"oldest_kept":    oldest,
+			"retention_days": u.retentionDays,
+		})
+	}
+	return nil
+}
+
+// load lee los agregados guardados indexados por su clave en el state repository
+func (u *UsageMeter) load(ctx context.Context) (map[string]storedUsage, error) {
+	values, err := u.store.List(ctx, interfaces.StateNamespaceUsage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+	stored := make(map[string]storedUsage, len(values))
+	for key, raw := range values {
+		var record storedUsage
+		if err := json.Unmarshal([]byte(raw), &record); err != nil {
+			continue
+		}
+		stored[key] = record
+	}
+	return stored, nil
+}
+
+// seed recupera los contadores de hoy de esta réplica, para que un reinicio con
+// el mismo instance_id no los sobrescriba con valores menores
+func (u *UsageMeter) seed(ctx context.Context) error {
+	stored, err := u.load(ctx)
+	if err != nil {
+		return err
+	}
+	today := u.now().UTC().Format(interfaces.UsageDateLayout)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, record := range stored {
+		if record.Instance != u.instanceID || record.Date != today {
+			continue
+		}
+		key := usageKey{date: record.Date, tenant: record.Tenant, keyID: record.KeyID}
+		counts, ok := u.counts[key]
+		if !ok {
+			counts = &usageCounts{}
+			u.counts[key] = counts
+		}
+		counts.requests += record.Requests
+		counts.streamMessages += record.StreamMessages
+		u.dirty[key] = true
+	}
+	return nil
+}
+
+func (u *UsageMeter) storedRecord(key usageKey, counts *usageCounts) storedUsage {
+	return storedUsage{
+		Date:           key.date,
+		Instance:       u.instanceID,
+		Tenant:         key.tenant,
+		KeyID:          key.keyID,
+		Requests:       counts.requests,
+		StreamMessages: counts.streamMessages,
+	}
+}
+
+func (u *UsageMeter) storeKey(key usageKey) string {
+	return key.date + "/" + u.instanceID + "/" + key.tenant + "/" + key.keyID
+}
+
+// Start recupera los contadores de hoy y los guarda periódicamente en background
+func (u *UsageMeter) Start(ctx context.Context) error {
+	if err := u.seed(ctx); err != nil {
+		logging.Warn(ctx, "Could not restore today's usage counters", logging.Fields{"error": err.Error()})
+	}
+
+	u.loopMu.Lock()
+	defer u.loopMu.Unlock()
+	if u.stop != nil {
+		return nil
+	}
+	u.stop = make(chan struct{})
+	u.done = make(chan struct{})
+
+	logging.Info(ctx, "Starting usage accounting", logging.Fields{
+		"instance_id":    u.instanceID,
+		"flush_interval": u.flushInterval.String(),
+		"retention_days": u.retentionDays,
+	})
+	go u.loop(context.WithoutCancel(ctx), u.stop, u.done)
+	return nil
+}
+
+// Stop detiene la escritura periódica y guarda los contadores pendientes
+func (u *UsageMeter) Stop(ctx context.Context) error {
+	u.loopMu.Lock()
+	stop, done := u.stop, u.done
+	u.stop, u.done = nil, nil
+	u.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return u.Flush(ctx)
+}
+
+func (u *UsageMeter) loop(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(u.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			flushCtx, cancel := context.WithTimeout(ctx, u.flushInterval)
+			if err := u.Flush(flushCtx); err != nil {
+				logging.Warn(flushCtx, "Usage flush failed", logging.Fields{"error": err.Error()})
+			}
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/repositories/state"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usageContext(apiKey string, tenant *entities.Tenant) context.Context {
+	ctx := entities.WithAPIKeyID(context.Background(), entities.APIKeyID(apiKey))
+	if tenant != nil {
+		ctx = entities.WithTenant(ctx, tenant)
+	}
+	return ctx
+}
+
+func TestUsageMeter_AggregatesReplicasPerKeyAndDay(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	now := time.Date(2026, 10, 14, 23, 59, 0, 0, time.UTC)
+	replicaA := NewUsageMeter(store, "replica-a", time.Minute, 30)
+	replicaB := NewUsageMeter(store, "replica-b", time.Minute, 30)
+	replicaA.now = func() time.Time { return now }
+	replicaB.now = func() time.Time { return now }
+
+	teamA := &entities.Tenant{Name: "team-a"}
+	replicaA.RecordRequest(usageContext("key-a", teamA))
+	replicaA.RecordStreamMessages(usageContext("key-a", teamA), 10)
+	replicaB.RecordRequest(usageContext("key-a", teamA))
+	replicaB.RecordRequest(usageContext("operator", nil))
+	replicaB.RecordRequest(context.Background()) // sin API key: no se cuenta
+	require.NoError(t, replicaB.Flush(ctx))
+
+	now = now.Add(time.Minute) // 2026-10-15
+	replicaA.RecordRequest(usageContext("key-a", teamA))
+
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	records, err := replicaA.Usage(ctx, day, day.AddDate(0, 0, 1), "")
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.UsageRecord{
+		{Date: "2026-10-14", KeyID: entities.APIKeyID("operator"), Requests: 1},
+		{Date: "2026-10-14", Tenant: "team-a", KeyID: entities.APIKeyID("key-a"), Requests: 2, StreamMessages: 10},
+		{Date: "2026-10-15", Tenant: "team-a", KeyID: entities.APIKeyID("key-a"), Requests: 1},
+	}, records, "unflushed counters of this replica are included")
+
+	records, err = replicaB.Usage(ctx, day, day, "team-a")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, int64(1), records[0].Requests, "other replicas only see flushed counters")
+
+	require.NoError(t, replicaA.Flush(ctx))
+	records, err = replicaB.Usage(ctx, day, day, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), records[0].Requests)
+}
+
+func TestUsageMeter_RestartKeepsCountersAndPrunes(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryStateRepository()
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	newMeter := func() *UsageMeter {
+		meter := NewUsageMeter(store, "replica-a", time.Hour, 2)
+		meter.now = func() time.Time { return now }
+		return meter
+	}
+
+	meter := newMeter()
+	require.NoError(t, meter.Start(ctx))
+	meter.RecordRequest(usageContext("key-a", nil))
+	require.NoError(t, meter.Stop(ctx), "stop flushes pending counters")
+
+	restarted := newMeter()
+	require.NoError(t, restarted.Start(ctx))
+	restarted.RecordRequest(usageContext("key-a", nil))
+	require.NoError(t, restarted.Stop(ctx))
+	records, err := restarted.Usage(ctx, now, now, "")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, int64(2), records[0].Requests, "a restart with the same instance id does not reset the day")
+
+	now = now.AddDate(0, 0, 2)
+	require.NoError(t, restarted.Flush(ctx))
+	values, err := store.List(ctx, interfaces.StateNamespaceUsage)
+	require.NoError(t, err)
+	assert.Empty(t, values, "aggregates outside the retention are deleted")
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultWarmupTimeout     = 30 * time.Second // Tiempo máximo total del warm-up
+	DefaultWarmupBatchSize   = 10               // Pares por request REST
+	DefaultWarmupConcurrency = 2                // Requests REST simultáneos
+)
+
+// ErrRequiredPairsNotWarmed indica que algún par requerido no pudo precargarse
+var ErrRequiredPairsNotWarmed = errors.New("required pairs not warmed")
+
+// WarmupConfig parametriza la precarga de la caché al iniciar
+type WarmupConfig struct {
+	Timeout       time.Duration
+	BatchSize     int
+	Concurrency   int
+	RequiredPairs []string // Deben precargarse antes de reportar readiness
+}
+
+// WarmupResult resume el resultado por par de un warm-up
+type WarmupResult struct {
+	Succeeded []string
+	Failed    map[string]string // par -> motivo
+	Duration  time.Duration
+}
+
+// CacheWarmer precarga la caché en lotes paralelos y controla la readiness del
+// servicio en función de los pares requeridos.
+type CacheWarmer struct {
+	service interfaces.PriceService
+	config  WarmupConfig
+	ready   atomic.Bool
+}
+
+// NewCacheWarmer crea un warmer que refresca precios a través de service;
+// valores no positivos usan los defaults
+func NewCacheWarmer(service interfaces.PriceService, config WarmupConfig) *CacheWarmer {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultWarmupTimeout
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultWarmupBatchSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultWarmupConcurrency
+	}
+	config.RequiredPairs = normalizePairs(config.RequiredPairs)
+
+	w := &CacheWarmer{service: service, config: config}
+	w.ready.Store(len(config.RequiredPairs) == 0)
+	return w
+}
+
+// Warmup precarga pairs en lotes de BatchSize con hasta Concurrency lotes en
+// paralelo. Retorna ErrRequiredPairsNotWarmed si falla algún par requerido.
+func (w *CacheWarmer) Warmup(ctx context.Context, pairs []string) (*WarmupResult, error) {
+	start := time.Now()
+	pairs = normalizePairs(pairs)
+	result := &WarmupResult{Failed: make(map[string]string)}
+	if len(pairs) == 0 {
+		return result, nil
+	}
+
+	warmCtx, cancel := context.WithTimeout(ctx, w.config.Timeout)
+	defer cancel()
+
+	logging.Info(ctx, "Starting cache warm-up", logging.Fields{
+		"pairs_count":    len(pairs),
+		"batch_size":     w.config.BatchSize,
+		"concurrency":    w.config.Concurrency,
+		"timeout":        w.config.Timeout.String(),
+		"required_pairs": w.config.RequiredPairs,
+	})
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, w.config.Concurrency)
+	)
+	for _, batch := range chunkPairs(pairs, w.config.BatchSize) {
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-warmCtx.Done():
+				mu.Lock()
+				for _, pair := range batch {
+					result.Failed[pair] = warmCtx.Err().Error()
+				}
+				mu.Unlock()
+				return
+			}
+
+			refreshErr := w.service.RefreshPrices(warmCtx, batch)
+
+			// Verificar por par: un lote puede fallar parcialmente
+			mu.Lock()
+			defer mu.Unlock()
+			for _, pair := range batch {
+				if _, err := w.service.GetLastPrice(warmCtx, pair); err == nil {
+					result.Succeeded = append(result.Succeeded, pair)
+				} else if refreshErr != nil {
+					result.Failed[pair] = refreshErr.Error()
+				} else {
+					result.Failed[pair] = err.Error()
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	sort.Strings(result.Succeeded)
+	result.Duration = time.Since(start)
+
+	missing := w.missingRequired(result.Succeeded)
+	if len(missing) == 0 {
+		w.ready.Store(true)
+	}
+
+	logging.Info(ctx, "Cache warm-up finished", logging.Fields{
+		"succeeded":        result.Succeeded,
+		"failed":           result.Failed,
+		"duration_ms":      result.Duration.Milliseconds(),
+		"missing_required": missing,
+	})
+
+	if len(missing) > 0 {
+		return result, fmt.Errorf("%w: %s", ErrRequiredPairsNotWarmed, strings.Join(missing, ","))
+	}
+	return result, nil
+}
+
+// Ready indica si los pares requeridos están disponibles. Si el warm-up no los
+// obtuvo, se consulta la caché (p. ej. tras el refresco automático); una vez listo
+// el resultado queda fijo.
+func (w *CacheWarmer) Ready(ctx context.Context) error {
+	if w.ready.Load() {
+		return nil
+	}
+
+	var missing []string
+	for _, pair := range w.config.RequiredPairs {
+		if _, err := w.service.GetLastPrice(ctx, pair); err != nil {
+			missing = append(missing, pair)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrRequiredPairsNotWarmed, strings.Join(missing, ","))
+	}
+	w.ready.Store(true)
+	return nil
+}
+
+func (w *CacheWarmer) missingRequired(succeeded []string) []string {
+	ok := make(map[string]bool, len(succeeded))
+	for _, pair := range succeeded {
+		ok[pair] = true
+	}
+	var missing []string
+	for _, pair := range w.config.RequiredPairs {
+		if !ok[pair] {
+			missing = append(missing, pair)
+		}
+	}
+	return missing
+}
+
+// chunkPairs divide pairs en lotes de tamaño size
+func chunkPairs(pairs []string, size int) [][]string {
+	var chunks [][]string
+	for start := 0; start < len(pairs); start += size {
+		end := start + size
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		chunks = append(chunks, pairs[start:end])
+	}
+	return chunks
+}
+
+// normalizePairs lleva los pares a su forma canónica y elimina duplicados conservando el orden
+func normalizePairs(pairs []string) []string {
+	seen := make(map[string]bool, len(pairs))
+	out := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		pair = entities.CanonicalPair(pair)
+		if pair == "" || seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		out = append(out, pair)
+	}
+	return out
+}
+
+// warmupSource adapta un WarmupExchange (sólo REST) a interfaces.Exchange
+type warmupSource struct {
+	warmup interfaces.WarmupExchange
+}
+
+// NewWarmupSource expone WarmupTickers como Exchange para usarlo con un PriceService
+func NewWarmupSource(warmup interfaces.WarmupExchange) interfaces.Exchange {
+	return &warmupSource{warmup: warmup}
+}
+
+func (s *warmupSource) GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
+	return s.warmup.WarmupTickers(ctx, pairs)
+}
+
+func (s *warmupSource) GetTicker(ctx context.Context, pair string) (*entities.Price, error) {
+	prices, err := s.warmup.WarmupTickers(ctx, []string{pair})
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no price returned for %s", pair)
+	}
+	return prices[0], nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRefresher implementa interfaces.PriceService en memoria; los pares en
+// failing nunca llegan a la caché
+type fakeRefresher struct {
+	mu       sync.Mutex
+	cached   map[string]*entities.Price
+	failing  map[string]bool
+	batches  [][]string
+	inFlight int
+	maxSeen  int
+}
+
+func newFakeRefresher(failing ...string) *fakeRefresher {
+	f := &fakeRefresher{cached: make(map[string]*entities.Price), failing: make(map[string]bool)}
+	for _, pair := range failing {
+		f.failing[pair] = true
+	}
+	return f
+}
+
+func (f *fakeRefresher) GetLastPrice(_ context.Context, pair string) (*entities.Price, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if price, ok := f.cached[pair]; ok {
+		return price, nil
+	}
+	return nil, errors.New("not cached")
+}
+
+func (f *fakeRefresher) RefreshPrices(_ context.Context, pairs []string) error {
+	f.mu.Lock()
+	f.batches = append(f.batches, append([]string(nil), pairs...))
+	f.inFlight++
+	if f.inFlight > f.maxSeen {
+		f.maxSeen = f.inFlight
+	}
+	f.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFlight--
+	var err error
+	for _, pair := range pairs {
+		if f.failing[pair] {
+			err = errors.New("kraken error for " + pair)
+			continue
+		}
+		f.cached[pair] = &entities.Price{Pair: pair, Amount: 1, Timestamp: time.Now()}
+	}
+	return err
+}
+
+func (f *fakeRefresher) GetCachedPrices(context.Context) ([]*entities.Price, error) {
+	return nil, nil
+}
+
+func (f *fakeRefresher) GetPriceWithMetadata(ctx context.Context, pair string) (*entities.PriceMetadata, error) {
+	price, err := f.GetLastPrice(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PriceMetadata{Price: price, Source: entities.PriceSourceUnknown}, nil
+}
+
+func (f *fakeRefresher) DeletePrice(_ context.Context, pair string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.cached, pair)
+	return nil
+}
+
+func (f *fakeRefresher) InvalidatePrices(_ context.Context, pairs []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, pair := range pairs {
+		delete(f.cached, pair)
+	}
+	return nil
+}
+
+func (f *fakeRefresher) FlushPrices(_ context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	removed := len(f.cached)
+	f.cached = make(map[string]*entities.Price)
+	return removed, nil
+}
+
+func TestCacheWarmer_BatchesAndReportsPerPair(t *testing.T) {
+	refresher := newFakeRefresher("XRP/USD")
+	warmer := NewCacheWarmer(refresher, WarmupConfig{Timeout: time.Second, BatchSize: 2, Concurrency: 2})
+
+	pairs := []string{"BTC/USD", "ETH/USD", "LTC/USD", "XRP/USD", "BTC/EUR"}
+	result, err := warmer.Warmup(context.Background(), pairs)
+	require.NoError(t, err, "no required pairs configured")
+
+	assert.Equal(t, []string{"BTC/EUR", "BTC/USD", "ETH/USD", "LTC/USD"}, result.Succeeded)
+	assert.Contains(t, result.Failed["XRP/USD"], "kraken error")
+	assert.Len(t, refresher.batches, 3)
+	assert.LessOrEqual(t, refresher.maxSeen, 2)
+	assert.NoError(t, warmer.Ready(context.Background()))
+}
+
+func TestCacheWarmer_RequiredPairsGateReadiness(t *testing.T) {
+	refresher := newFakeRefresher("ETH/USD")
+	warmer := NewCacheWarmer(refresher, WarmupConfig{Timeout: time.Second, RequiredPairs: []string{"btc/usd", "ETH/USD"}})
+
+	assert.Error(t, warmer.Ready(context.Background()), "not ready before warm-up")
+
+	_, err := warmer.Warmup(context.Background(), []string{"BTC/USD", "ETH/USD"})
+	assert.ErrorIs(t, err, ErrRequiredPairsNotWarmed)
+	assert.ErrorIs(t, warmer.Ready(context.Background()), ErrRequiredPairsNotWarmed)
+
+	// El refresco automático posterior completa el par requerido
+	delete(refresher.failing, "ETH/USD")
+	require.NoError(t, refresher.RefreshPrices(context.Background(), []string{"ETH/USD"}))
+	assert.NoError(t, warmer.Ready(context.Background()))
+}
+
+func TestChunkPairs(t *testing.T) {
+	assert.Equal(t, [][]string{{"A", "B"}, {"C"}}, chunkPairs([]string{"A", "B", "C"}, 2))
+	assert.Nil(t, chunkPairs(nil, 2))
+}
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// FetchBudget reparte el tiempo restante de una request entre las etapas de
+// obtención de precios. Una duración cero indica que la etapa no tiene límite
+// propio y sólo aplica el deadline del contexto.
+type FetchBudget struct {
+	Cache     time.Duration // Lecturas y escrituras de caché
+	WebSocket time.Duration // Espera de precios por WebSocket (todos los intentos)
+	REST      time.Duration // Fallback REST
+}
+
+type fetchBudgetKey struct{}
+
+// WithFetchBudget adjunta un presupuesto de obtención al contexto
+func WithFetchBudget(ctx context.Context, budget FetchBudget) context.Context {
+	return context.WithValue(ctx, fetchBudgetKey{}, budget)
+}
+
+// FetchBudgetFrom retorna el presupuesto adjunto al contexto, si existe
+func FetchBudgetFrom(ctx context.Context) (FetchBudget, bool) {
+	budget, ok := ctx.Value(fetchBudgetKey{}).(FetchBudget)
+	return budget, ok
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// TickerStats resume la actividad de las últimas 24h de un par según el ticker del exchange
+type TickerStats struct {
+	Pair      string    `json:"pair"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Last      float64   `json:"last"`
+	Volume    float64   `json:"volume"`
+	VWAP      float64   `json:"vwap"`
+	Trades    int64     `json:"trades"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Spread es la mejor compra y venta de un par en un instante
+type Spread struct {
+	Pair      string    `json:"pair"`
+	Bid       float64   `json:"bid"`
+	Ask       float64   `json:"ask"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Mid es el punto medio entre Bid y Ask
+func (s *Spread) Mid() float64 {
+	return (s.Bid + s.Ask) / 2
+}
+
+// Candle es una vela OHLC de un par que abre en OpenTime y dura Interval
+type Candle struct {
+	Pair     string        `json:"pair"`
+	Interval time.Duration `json:"interval"`
+	OpenTime time.Time     `json:"open_time"`
+	Open     float64       `json:"open"`
+	High     float64       `json:"high"`
+	Low      float64       `json:"low"`
+	Close    float64       `json:"close"`
+	Volume   float64       `json:"volume"`
+}
+
+// CandleID identifica la vela de pair con ese intervalo y apertura, p. ej.
+// "BTC/USD:1m0s:1700000000"
+func CandleID(pair string, interval time.Duration, openTime time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", CanonicalPair(pair), interval, openTime.Unix())
+}
+
+// ID identifica la vela; ver CandleID
+func (c *Candle) ID() string {
+	return CandleID(c.Pair, c.Interval, c.OpenTime)
+}
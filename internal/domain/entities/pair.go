@@ -1,5 +1,11 @@
 package entities
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 type Pair struct {
 	Symbol string `json:"symbol"`
 	Price  Price  `json:"price"`
@@ -12,3 +18,98 @@ func NewPair(symbol string, price Price) *Pair {
 		Price:  price,
 	}
 }
+
+// ErrInvalidPair indica que un par no puede interpretarse como BASE/QUOTE
+var ErrInvalidPair = errors.New("invalid pair format")
+
+// assetAliases mapea códigos alternativos de activos a su forma canónica
+var assetAliases = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+// knownQuotes son las monedas de cotización usadas para separar pares sin
+// separador (BTCUSD); se prueban de la más larga a la más corta
+var knownQuotes = []string{"USDT", "USDC", "USD", "EUR", "GBP", "CHF", "JPY", "CAD", "AUD", "XBT", "BTC", "ETH"}
+
+// NormalizePair convierte un par en su forma canónica BASE/QUOTE. Acepta
+// separadores "/", "-", "_" y ":" (BTC-USD, btc_usd), pares concatenados (XBTUSD),
+// el formato legado de Kraken (XXBTZUSD) y alias de activos (XBT → BTC).
+func NormalizePair(raw string) (string, error) {
+	pair := strings.ToUpper(strings.TrimSpace(raw))
+	if pair == "" {
+		return "", fmt.Errorf("%w: empty pair", ErrInvalidPair)
+	}
+
+	var base, quote string
+	if i := strings.IndexAny(pair, "/-_:"); i >= 0 {
+		base, quote = pair[:i], pair[i+1:]
+		if strings.ContainsAny(quote, "/-_:") {
+			return "", fmt.Errorf("%w: %s (expected BASE/QUOTE)", ErrInvalidPair, raw)
+		}
+	} else {
+		var ok bool
+		if base, quote, ok = splitConcatenatedPair(pair); !ok {
+			return "", fmt.Errorf("%w: %s (expected BASE/QUOTE)", ErrInvalidPair, raw)
+		}
+	}
+
+	base, quote = strings.TrimSpace(base), strings.TrimSpace(quote)
+	if !isAssetCode(base) || !isAssetCode(quote) {
+		return "", fmt.Errorf("%w: %s (expected BASE/QUOTE)", ErrInvalidPair, raw)
+	}
+	return canonicalAsset(base) + "/" + canonicalAsset(quote), nil
+}
+
+// CanonicalPair normaliza pair o, si no es reconocible, lo retorna en mayúsculas;
+// útil para claves de caché donde no corresponde fallar
+func CanonicalPair(pair string) string {
+	if normalized, err := NormalizePair(pair); err == nil {
+		return normalized
+	}
+	return strings.ToUpper(strings.TrimSpace(pair))
+}
+
+// SplitPair separa un par en activo base y de cotización canónicos; false si
+// pair no es reconocible
+func SplitPair(pair string) (base, quote string, ok bool) {
+	normalized, err := NormalizePair(pair)
+	if err != nil {
+		return "", "", false
+	}
+	base, quote, _ = strings.Cut(normalized, "/")
+	return base, quote, true
+}
+
+// splitConcatenatedPair separa XXBTZUSD (legado Kraken) o BTCUSD por la cotización conocida
+func splitConcatenatedPair(pair string) (string, string, bool) {
+	if len(pair) == 8 && pair[0] == 'X' && (pair[4] == 'Z' || pair[4] == 'X') {
+		return pair[1:4], pair[5:], true
+	}
+	for _, quote := range knownQuotes {
+		if len(pair) > len(quote)+1 && strings.HasSuffix(pair, quote) {
+			return strings.TrimSuffix(pair, quote), quote, true
+		}
+	}
+	return "", "", false
+}
+
+// isAssetCode acepta códigos de activo no vacíos formados por letras y dígitos ASCII
+func isAssetCode(asset string) bool {
+	if asset == "" {
+		return false
+	}
+	for _, r := range asset {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func canonicalAsset(asset string) string {
+	if canonical, ok := assetAliases[asset]; ok {
+		return canonical
+	}
+	return asset
+}
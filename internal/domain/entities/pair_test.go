@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePair(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"BTC/USD", "BTC/USD"},
+		{" btc/usd ", "BTC/USD"},
+		{"BTC-USD", "BTC/USD"},
+		{"btc_usd", "BTC/USD"},
+		{"BTC:EUR", "BTC/EUR"},
+		{"XBTUSD", "BTC/USD"},
+		{"XBT/USD", "BTC/USD"},
+		{"XXBTZUSD", "BTC/USD"},
+		{"XETHXXBT", "ETH/BTC"},
+		{"ETHBTC", "ETH/BTC"},
+		{"BTCUSDT", "BTC/USDT"},
+		{"LINKEUR", "LINK/EUR"},
+		{"XDG/USD", "DOGE/USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizePair(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizePair_Invalid(t *testing.T) {
+	for _, input := range []string{"", "BITCOIN", "BTC", "BTC//USD", "/USD", "BTC/", "BTC/USD/EUR", "BTC-USD@2024"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := NormalizePair(input)
+			assert.True(t, errors.Is(err, ErrInvalidPair), "got %v", err)
+		})
+	}
+}
+
+func TestCanonicalPair(t *testing.T) {
+	assert.Equal(t, "BTC/USD", CanonicalPair("xbt-usd"))
+	assert.Equal(t, "BITCOIN", CanonicalPair(" bitcoin "), "unrecognized input is only upper-cased")
+}
+
+func TestSplitPair(t *testing.T) {
+	base, quote, ok := SplitPair("xbt-usdt")
+	assert.True(t, ok)
+	assert.Equal(t, "BTC", base)
+	assert.Equal(t, "USDT", quote)
+
+	_, _, ok = SplitPair("bitcoin")
+	assert.False(t, ok)
+}
@@ -1,19 +1,150 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
 
 type Price struct {
 	Pair      string        `json:"pair"`
 	Amount    float64       `json:"amount"`
 	Timestamp time.Time     `json:"timestamp"`
 	Age       time.Duration `json:"age"`
+	// ExchangeTime es el instante informado por el exchange (cero si no lo provee)
+	ExchangeTime time.Time `json:"exchange_time,omitzero"`
+	// ReceivedTime es el instante en que el servicio recibió el precio
+	ReceivedTime time.Time `json:"received_time,omitzero"`
+	// Source identifica el origen del precio (websocket, rest, mock)
+	Source string `json:"source,omitempty"`
+	// Exchange es el exchange que informó el precio y Channel el canal del
+	// WebSocket o el endpoint REST del que vino
+	Exchange string `json:"exchange,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	// Bid y Ask son la mejor compra y venta del ticker (0 si no se conocen)
+	Bid float64 `json:"bid,omitempty"`
+	Ask float64 `json:"ask,omitempty"`
 }
 
+// Orígenes de precios
+const (
+	PriceSourceWebSocket = "websocket"
+	PriceSourceREST      = "rest"
+	PriceSourceMock      = "mock"
+	PriceSourceUnknown   = "unknown"
+)
+
+// Exchanges de origen de los precios
+const (
+	ExchangeKraken = "kraken"
+	ExchangeMock   = "mock"
+)
+
+// PriceMetadata agrupa un precio con información de origen y frescura
+type PriceMetadata struct {
+	Price    *Price        `json:"price"`
+	Source   string        `json:"source"`
+	Age      time.Duration `json:"age"`
+	CacheKey string        `json:"cache_key"`
+}
+
+// NewPrice crea un precio observado en timestamp; un timestamp cero usa time.Now()
 func NewPrice(pair string, amount float64, timestamp time.Time, age time.Duration) *Price {
+	now := time.Now()
+	if timestamp.IsZero() {
+		timestamp = now
+	}
 	return &Price{
-		Pair:      pair,
-		Amount:    amount,
-		Timestamp: time.Now(),
-		Age:       age,
+		Pair:         pair,
+		Amount:       amount,
+		Timestamp:    timestamp,
+		Age:          age,
+		ReceivedTime: now,
+	}
+}
+
+// NewPriceWithExchangeTime crea un precio con el timestamp del exchange cuando está
+// disponible. Timestamp y Age se derivan de exchangeTime; si es cero se usa receivedTime.
+func NewPriceWithExchangeTime(pair string, amount float64, exchangeTime, receivedTime time.Time) *Price {
+	if receivedTime.IsZero() {
+		receivedTime = time.Now()
+	}
+	price := &Price{
+		Pair:         pair,
+		Amount:       amount,
+		Timestamp:    receivedTime,
+		ExchangeTime: exchangeTime,
+		ReceivedTime: receivedTime,
+	}
+	if !exchangeTime.IsZero() {
+		price.Timestamp = exchangeTime
+	}
+	price.Age = price.AgeAt(receivedTime)
+	return price
+}
+
+// AgeAt calcula la edad del precio en now a partir del timestamp del exchange (o
+// Timestamp si no existe). Un reloj del exchange adelantado produce edad 0, nunca negativa.
+// Con precisión de segundos (el header Date de REST) el precio es de algún momento
+// del segundo siguiente y no más nuevo que ReceivedTime, que acota la referencia:
+// si no, un precio REST recién obtenido aparentaría hasta 1s de edad.
+func (p *Price) AgeAt(now time.Time) time.Duration {
+	reference := p.Timestamp
+	if !p.ExchangeTime.IsZero() {
+		reference = p.ExchangeTime
+	}
+	if reference.IsZero() {
+		return 0
+	}
+	if !subSecond(reference) && p.ReceivedTime.After(reference) {
+		latest := reference.Add(time.Second - time.Nanosecond)
+		reference = p.ReceivedTime
+		if reference.After(latest) {
+			reference = latest
+		}
+	}
+	if age := now.Sub(reference); age > 0 {
+		return age
+	}
+	return 0
+}
+
+// OlderThan indica si p es anterior a other al decidir qué precio conservar en
+// caché. Compara ExchangeTime sólo si ambos lo tienen con precisión menor al
+// segundo: el header Date de REST sólo tiene segundos y un precio REST obtenido
+// después de un tick del mismo segundo no es más viejo. Si no, compara
+// ReceivedTime y, sin él, Timestamp.
+func (p *Price) OlderThan(other *Price) bool {
+	switch {
+	case subSecond(p.ExchangeTime) && subSecond(other.ExchangeTime):
+		return p.ExchangeTime.Before(other.ExchangeTime)
+	case !p.ReceivedTime.IsZero() && !other.ReceivedTime.IsZero():
+		return p.ReceivedTime.Before(other.ReceivedTime)
+	}
+	return p.Timestamp.Before(other.Timestamp)
+}
+
+// subSecond indica si t no es cero y tiene precisión menor al segundo
+func subSecond(t time.Time) bool {
+	return !t.IsZero() && t.Nanosecond() != 0
+}
+
+// Errores de validación de precios
+var (
+	ErrNonPositivePrice = errors.New("price must be positive")
+	ErrFutureTimestamp  = errors.New("price timestamp is in the future")
+	ErrPriceJump        = errors.New("price deviates too much from previous value")
+)
+
+// Validate verifica invariantes básicas del precio: monto positivo y finito, y
+// timestamp no posterior a now+maxFutureSkew
+func (p *Price) Validate(now time.Time, maxFutureSkew time.Duration) error {
+	if p.Amount <= 0 || math.IsNaN(p.Amount) || math.IsInf(p.Amount, 0) {
+		return fmt.Errorf("%w: %s=%v", ErrNonPositivePrice, p.Pair, p.Amount)
+	}
+	if p.Timestamp.After(now.Add(maxFutureSkew)) {
+		return fmt.Errorf("%w: %s at %s", ErrFutureTimestamp, p.Pair, p.Timestamp.Format(time.RFC3339Nano))
 	}
+	return nil
 }
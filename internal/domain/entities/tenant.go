@@ -0,0 +1,84 @@
+package entities
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+)
+
+// Tenant es un equipo cliente de un despliegue compartido: se autentica con sus
+// propias API keys y sólo accede a sus pares, con un rate limit propio
+type Tenant struct {
+	Name                string
+	APIKeys             []string
+	Pairs               []string // Pares permitidos en forma canónica; vacío = todos los soportados
+	RateLimitCapacity   int      // Bucket compartido por todo el tenant; 0 = sin límite propio
+	RateLimitRefillRate int
+}
+
+// AllowsPair indica si el tenant puede consultar pair
+func (t *Tenant) AllowsPair(pair string) bool {
+	return len(t.Pairs) == 0 || slices.Contains(t.Pairs, CanonicalPair(pair))
+}
+
+// FilterPairs devuelve los pares de pairs permitidos para el tenant, en el mismo orden
+func (t *Tenant) FilterPairs(pairs []string) []string {
+	if len(t.Pairs) == 0 {
+		return pairs
+	}
+	allowed := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if t.AllowsPair(pair) {
+			allowed = append(allowed, pair)
+		}
+	}
+	return allowed
+}
+
+// FilterPrices devuelve los precios de pares permitidos para el tenant
+func (t *Tenant) FilterPrices(prices []*Price) []*Price {
+	if len(t.Pairs) == 0 {
+		return prices
+	}
+	allowed := make([]*Price, 0, len(prices))
+	for _, price := range prices {
+		if t.AllowsPair(price.Pair) {
+			allowed = append(allowed, price)
+		}
+	}
+	return allowed
+}
+
+type tenantKey struct{}
+
+// WithTenant adjunta el tenant autenticado al contexto
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom retorna el tenant de la request; false para el operador o sin multi-tenancy
+func TenantFrom(ctx context.Context) (*Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(*Tenant)
+	return tenant, ok && tenant != nil
+}
+
+type apiKeyIDKey struct{}
+
+// APIKeyID es el identificador público de una API key: los primeros 12 dígitos
+// hexadecimales de su SHA-256. Permite reportar uso por key sin exponerla.
+func APIKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// WithAPIKeyID adjunta al contexto el identificador de la API key autenticada
+func WithAPIKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey{}, keyID)
+}
+
+// APIKeyIDFrom retorna el identificador de la API key de la request; "" sin autenticación
+func APIKeyIDFrom(ctx context.Context) string {
+	keyID, _ := ctx.Value(apiKeyIDKey{}).(string)
+	return keyID
+}
@@ -0,0 +1,84 @@
+package entities
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Etapas en las que se reparte el tiempo de una request de precio
+const (
+	TimingCache     = "cache"
+	TimingWebSocket = "ws"
+	TimingREST      = "rest"
+)
+
+// RequestTimings acumula por etapa el tiempo que consume una request de precio y
+// cuántos reintentos hizo. Es seguro para uso concurrente y un *RequestTimings
+// nil ignora las observaciones, así las etapas no necesitan saber si se mide.
+type RequestTimings struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+	retries   int
+}
+
+// NewRequestTimings crea un acumulador vacío
+func NewRequestTimings() *RequestTimings {
+	return &RequestTimings{durations: make(map[string]time.Duration)}
+}
+
+type requestTimingsKey struct{}
+
+// WithRequestTimings adjunta timings al contexto
+func WithRequestTimings(ctx context.Context, timings *RequestTimings) context.Context {
+	return context.WithValue(ctx, requestTimingsKey{}, timings)
+}
+
+// RequestTimingsFrom retorna el acumulador adjunto al contexto, o nil si no hay
+func RequestTimingsFrom(ctx context.Context) *RequestTimings {
+	timings, _ := ctx.Value(requestTimingsKey{}).(*RequestTimings)
+	return timings
+}
+
+// Observe suma a stage el tiempo transcurrido desde start; pensado para
+// defer t.Observe(stage, time.Now())
+func (t *RequestTimings) Observe(stage string, start time.Time) {
+	if t == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.durations[stage] += elapsed
+}
+
+// Retry cuenta un reintento (WebSocket o REST)
+func (t *RequestTimings) Retry() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.retries++
+}
+
+// Durations retorna una copia del tiempo acumulado por etapa
+func (t *RequestTimings) Durations() map[string]time.Duration {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return maps.Clone(t.durations)
+}
+
+// Retries retorna la cantidad de reintentos contados
+func (t *RequestTimings) Retries() int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.retries
+}
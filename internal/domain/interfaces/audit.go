@@ -0,0 +1,54 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// StateNamespaceEvents guarda el registro de auditoría de fallbacks y reconexiones (JSON)
+const StateNamespaceEvents = "events"
+
+// Tipos de eventos del registro de auditoría
+const (
+	AuditEventFallback  = "fallback"  // Activación del fallback REST
+	AuditEventReconnect = "reconnect" // Reconexión del WebSocket
+)
+
+// AuditEvent es una activación del fallback REST o una reconexión del WebSocket
+type AuditEvent struct {
+	ID        string // Asignado al registrarlo
+	Type      string // AuditEventFallback o AuditEventReconnect
+	Time      time.Time
+	Instance  string        // Réplica que lo registró
+	Reason    string        // Motivo (timeout, read_error, forced, ...)
+	Duration  time.Duration // Fallback: consulta REST; reconexión: desde la pérdida de la conexión
+	Pairs     []string      // Pares afectados
+	Success   bool          // Si REST devolvió los precios o la conexión se restableció
+	Error     string        // Causa del fallo, vacío si Success
+	Shard     string        // Conexión WebSocket, sólo en reconexiones
+	Attempt   int           // Intento de reconexión, sólo en reconexiones
+	RequestID string        // request_id de la activación del fallback (vacío si no se conoce)
+}
+
+// AuditEventFilter restringe los eventos consultados; los campos vacíos no filtran
+type AuditEventFilter struct {
+	Type   string
+	Reason string
+	Pair   string // Eventos que afectaron al par
+	From   time.Time
+	To     time.Time
+	Limit  int // Máximo de eventos, los más recientes
+}
+
+// AuditRecorder registra eventos del registro de auditoría; no debe bloquear
+type AuditRecorder interface {
+	RecordEvent(ctx context.Context, event AuditEvent)
+}
+
+// AuditLog registra y consulta los eventos de todas las réplicas
+type AuditLog interface {
+	AuditRecorder
+	// Events devuelve los eventos que cumplen filter, del más reciente al más antiguo
+	Events(ctx context.Context, filter AuditEventFilter) ([]AuditEvent, error)
+	RetentionDays() int
+}
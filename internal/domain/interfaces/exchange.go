@@ -3,9 +3,122 @@ package interfaces
 import (
 	"btc-ltp-service/internal/domain/entities"
 	"context"
+	"errors"
+	"time"
 )
 
 type Exchange interface {
 	GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error)
 	GetTicker(ctx context.Context, pair string) (*entities.Price, error)
 }
+
+// FallbackEvent es una activación del fallback REST de un exchange con streaming
+type FallbackEvent struct {
+	Time      time.Time
+	Pairs     []string
+	Reason    string        // Motivo del fallback (timeout, connection_error, ...)
+	Success   bool          // Si REST devolvió los precios
+	Duration  time.Duration // Duración de la consulta REST
+	RequestID string        // request_id de los logs de la activación (vacío si no se conoce)
+}
+
+// FallbackHistory expone las activaciones recientes del fallback, de la más
+// reciente a la más antigua (dashboard de administración)
+type FallbackHistory interface {
+	RecentFallbacks() []FallbackEvent
+}
+
+// PairFeedQuality resume la salud del feed de streaming de un par en la ventana
+// móvil del reporte. Los tiempos en cero indican que no hubo el evento.
+type PairFeedQuality struct {
+	Pair              string
+	TicksPerMinute    float64       // Ticks recibidos por minuto
+	DropRate          float64       // Ticks descartados sobre recibidos (0-1)
+	AvgTickInterval   time.Duration // Intervalo promedio entre ticks (0 con menos de dos)
+	LastTick          time.Time     // Último tick recibido, aunque sea fuera de la ventana
+	LastFallback      time.Time     // Última activación del fallback REST que incluyó el par
+	DivergencePercent float64       // Última divergencia medida entre WebSocket y REST
+	DivergenceAt      time.Time     // Cuándo se midió (cero si nunca hubo precios comparables)
+}
+
+// FeedQualityReport son las estadísticas de calidad del feed de los pares pedidos
+type FeedQualityReport struct {
+	Window time.Duration // Ventana móvil de ticks y descartes
+	Pairs  []PairFeedQuality
+}
+
+// FeedQualityReporter expone la calidad del feed por par (GET /admin/feed-quality)
+type FeedQualityReporter interface {
+	FeedQuality(pairs []string) FeedQualityReport
+}
+
+// Estados de la suscripción al stream WebSocket de un par
+const (
+	SubscriptionConfirmed    = "confirmed"    // Confirmada por el exchange
+	SubscriptionPending      = "pending"      // Enviada y aún sin confirmar
+	SubscriptionRetrying     = "retrying"     // Rechazada por el exchange; se reintenta con backoff
+	SubscriptionExcluded     = "excluded"     // Rechazada repetidamente; el par se sirve por REST
+	SubscriptionUnsubscribed = "unsubscribed" // Sin suscripción (todavía no se pidió o se podó)
+)
+
+// PairSubscription es el estado de la suscripción WebSocket de un par
+type PairSubscription struct {
+	Pair          string
+	Status        string // Subscription*
+	Failures      int    // Errores de suscripción desde la última confirmación
+	LastError     string
+	LastFailureAt time.Time
+	RetryAt       time.Time // Próximo reintento (sólo con SubscriptionRetrying)
+}
+
+// SubscriptionReporter expone el estado de suscripción por par (GET /admin/pairs)
+type SubscriptionReporter interface {
+	PairSubscriptions(pairs []string) []PairSubscription
+}
+
+// PairMetadata describe un par soportado según el exchange que lo provee
+type PairMetadata struct {
+	Pair          string
+	Base          string
+	Quote         string
+	Exchange      string // Exchange de origen (kraken, mock)
+	Subscribed    bool   // Suscripción al stream WebSocket confirmada
+	HasPrecision  bool   // Si el exchange informó los decimales del par
+	PriceDecimals int
+	LotDecimals   int
+	Status        string // Estado del par en el exchange (online, cancel_only, ...); vacío si se desconoce
+}
+
+// PairCatalog expone los metadatos de los pares soportados (GET /api/v1/pairs)
+type PairCatalog interface {
+	PairMetadata(pairs []string) []PairMetadata
+}
+
+// PairPruner cancela el streaming de pares que nadie consulta; el exchange los
+// vuelve a suscribir cuando se piden de nuevo
+type PairPruner interface {
+	PrunePairs(ctx context.Context, pairs []string) error
+}
+
+// ErrPairNotListed indica que el exchange no lista el par o no lo opera
+var ErrPairNotListed = errors.New("pair not listed by exchange")
+
+// PairResolver confirma que el exchange opera un par fuera de los soportados
+// (pares bajo demanda). Retorna el par en forma canónica o un error que envuelve
+// ErrPairNotListed.
+type PairResolver interface {
+	ResolvePair(ctx context.Context, pair string) (string, error)
+}
+
+// PairAdmitter amplía los pares soportados con pares bajo demanda
+type PairAdmitter interface {
+	// Admit intenta admitir los pares no soportados de requested y retorna los
+	// soportados más todos los admitidos
+	Admit(ctx context.Context, requested []string) []string
+}
+
+// SystemStatusReporter expone el estado operativo que publica el exchange (p. ej.
+// "online" o "maintenance"); vacío mientras el exchange aún no lo informó
+type SystemStatusReporter interface {
+	SystemStatus() string
+}
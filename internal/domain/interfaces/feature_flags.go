@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrUnknownFeatureFlag indica un flag no declarado en feature_flags.flags
+	ErrUnknownFeatureFlag = errors.New("unknown feature flag")
+	// ErrFeatureFlagOverridesDisabled indica que los overrides en runtime están deshabilitados
+	ErrFeatureFlagOverridesDisabled = errors.New("feature flag overrides are disabled")
+)
+
+// FeatureFlagState describe el estado efectivo de un flag
+type FeatureFlagState struct {
+	Name       string
+	Enabled    bool // Estado efectivo (override si existe, si no el default)
+	Default    bool // Estado declarado en la configuración
+	Overridden bool
+}
+
+// FeatureFlags consulta flags que habilitan funcionalidades riesgosas en runtime.
+// Un flag no declarado está siempre deshabilitado.
+type FeatureFlags interface {
+	Enabled(name string) bool
+	Snapshot() []FeatureFlagState
+}
+
+// FeatureFlagManager permite además sobrescribir flags en runtime (endpoints de admin)
+type FeatureFlagManager interface {
+	FeatureFlags
+	OverridesEnabled() bool
+	SetOverride(ctx context.Context, name string, enabled bool) error
+	ClearOverride(ctx context.Context, name string) error
+}
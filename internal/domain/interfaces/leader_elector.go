@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// LeaderElector decide qué réplica ejecuta los jobs de fondo (refresh loop,
+// staleness watcher) para no multiplicar el tráfico hacia el exchange.
+type LeaderElector interface {
+	// Start inicia la participación en la elección (no bloqueante)
+	Start(ctx context.Context) error
+	// IsLeader indica si esta instancia es actualmente la líder
+	IsLeader() bool
+	// Stop abandona la elección y libera el liderazgo si se posee
+	Stop() error
+}
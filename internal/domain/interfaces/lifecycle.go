@@ -0,0 +1,85 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Closer es un componente que libera recursos (conexiones, goroutines) al apagarse
+type Closer interface {
+	Close() error
+}
+
+// GracefulCloser es un Closer que además puede apagarse respetando el deadline de ctx
+type GracefulCloser interface {
+	Closer
+	Shutdown(ctx context.Context) error
+}
+
+// Reconnectable es un componente con una conexión persistente al upstream (ej: el
+// WebSocket de un exchange) que puede forzarse a reconectar
+type Reconnectable interface {
+	// Reconnect cierra la conexión actual y la vuelve a establecer dentro del deadline de ctx
+	Reconnect(ctx context.Context) error
+	// IsConnected indica si la conexión está establecida
+	IsConnected() bool
+}
+
+// Drainer es un servidor que puede empezar a drenar antes de recibir la señal de
+// apagado (hook preStop de Kubernetes): deja de estar listo, rechaza streams
+// nuevos y avisa a los abiertos que reconecten en otra instancia
+type Drainer interface {
+	// Drain inicia el drenaje y retorna los streams que siguen abiertos; llamarlo
+	// de nuevo sólo los vuelve a contar
+	Drain(ctx context.Context) int
+	// Draining indica si el drenaje empezó
+	Draining() bool
+}
+
+// ConnectionStatus es el estado de una de las conexiones de un ConnectionPool
+type ConnectionStatus struct {
+	ID                string
+	Pairs             int // Pares asignados a la conexión
+	Connected         bool
+	Reconnecting      bool
+	ReconnectAttempts int
+	Confirmed         int // Suscripciones confirmadas por el upstream
+	Pending           int // Suscripciones enviadas y aún sin confirmar
+}
+
+// ConnectionPool es un Reconnectable que reparte sus suscripciones entre varias
+// conexiones (ej: los shards del WebSocket de Kraken)
+type ConnectionPool interface {
+	Connections() []ConnectionStatus
+}
+
+// StartupStep es un paso del arranque: la creación de un componente ("init") o su
+// inicio en el ciclo de vida ("start")
+type StartupStep struct {
+	Name     string
+	Phase    string
+	Duration time.Duration
+	Err      string // Vacío si el paso terminó bien
+}
+
+// StartupWarmup es el resultado del warm-up de la caché durante el arranque
+type StartupWarmup struct {
+	Succeeded []string
+	Failed    map[string]string // par -> motivo
+	Duration  time.Duration
+	Err       string // Vacío si se precargaron los pares requeridos
+}
+
+// StartupReport describe cómo arrancó el servicio, para diagnosticar arranques lentos
+type StartupReport struct {
+	StartedAt      time.Time
+	ReadyAt        time.Time // Cero mientras el arranque no terminó
+	Steps          []StartupStep
+	Warmup         *StartupWarmup // nil si no hubo warm-up
+	ConfigWarnings []string       // Configuraciones válidas pero probablemente no deseadas
+}
+
+// StartupReporter expone el informe de arranque del servicio
+type StartupReporter interface {
+	StartupReport() StartupReport
+}
@@ -1,12 +1,37 @@
 package interfaces
 
 import (
+	"btc-ltp-service/internal/domain/entities"
 	"context"
+	"errors"
 	"time"
 )
 
+// Errores de Cache.Get
+var (
+	// ErrCacheMiss indica que la clave no está en caché
+	ErrCacheMiss = errors.New("key not found")
+	// ErrCacheExpired indica que la clave venció; sólo lo distinguen los backends
+	// que conservan las claves vencidas hasta leerlas (los demás retornan ErrCacheMiss)
+	ErrCacheExpired = errors.New("key expired")
+)
+
 type Cache interface {
+	// Get retorna el valor de key, o un error que envuelve ErrCacheMiss o ErrCacheExpired
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value string, ttl time.Duration) error
 	Delete(ctx context.Context, key string) error
+	// DeleteMany elimina varias claves; las inexistentes se ignoran
+	DeleteMany(ctx context.Context, keys []string) error
+	// Flush elimina las claves que empiezan con prefix (todas si prefix es "")
+	// y retorna cuántas se eliminaron
+	Flush(ctx context.Context, prefix string) (int, error)
+}
+
+// PriceCodec serializa los precios que se guardan en Cache
+type PriceCodec interface {
+	// Name identifica el codec en la configuración (json, binary)
+	Name() string
+	Marshal(price *entities.Price) ([]byte, error)
+	Unmarshal(data []byte) (*entities.Price, error)
 }
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"btc-ltp-service/internal/domain/entities"
 	"context"
+	"errors"
 )
 
 // PriceService define los casos de uso relacionados con precios de criptomonedas
@@ -17,4 +18,82 @@ type PriceService interface {
 
 	// GetCachedPrices retorna todos los precios que están actualmente en cache
 	GetCachedPrices(ctx context.Context) ([]*entities.Price, error)
+
+	// GetPriceWithMetadata obtiene el precio cacheado de un par junto con su origen y edad
+	GetPriceWithMetadata(ctx context.Context, pair string) (*entities.PriceMetadata, error)
+
+	// DeletePrice invalida el precio cacheado de un par (herramientas de administración y tests)
+	DeletePrice(ctx context.Context, pair string) error
+
+	PriceCacheInvalidator
+}
+
+// Errores de GetLastPrice y GetPriceWithMetadata: el error retornado envuelve uno
+// de ellos (o ErrPriceDeviation) para que la capa HTTP elija status y código sin
+// depender del mensaje
+var (
+	// ErrPairUnsupported indica que el exchange no lista el par
+	ErrPairUnsupported = errors.New("pair not supported")
+	// ErrUpstreamUnavailable indica que el exchange aún no entregó un precio del par
+	ErrUpstreamUnavailable = errors.New("price source unavailable")
+	// ErrStaleData indica que el último precio del par venció sin renovarse
+	ErrStaleData = errors.New("price data is stale")
+	// ErrRateLimited indica que el exchange rechazó las requests por rate limit
+	ErrRateLimited = errors.New("rate limited by exchange")
+)
+
+// RefreshReport detalla qué pares actualizó un refresco
+type RefreshReport struct {
+	Refreshed []string // Pares escritos en caché
+	// Failed son los pedidos que no se actualizaron: el exchange no los devolvió,
+	// quedaron en cuarentena o falló su escritura
+	Failed []string
+}
+
+// RefreshReporter es implementado por servicios que informan el resultado por par
+// de un refresco; el error es el mismo que retornaría RefreshPrices
+type RefreshReporter interface {
+	RefreshPricesReport(ctx context.Context, pairs []string) (RefreshReport, error)
+}
+
+// SourceRefresher es implementado por servicios que pueden refrescar consultando
+// al exchange sin su caché propia (p. ej. REST en lugar del último tick del
+// WebSocket), para cuando el cliente no acepta un precio tan viejo como el cacheado
+type SourceRefresher interface {
+	RefreshPricesFromSource(ctx context.Context, pairs []string) error
+}
+
+// PriceCacheInvalidator permite purgar precios cacheados sin reiniciar el servicio.
+// Los exchanges con caché propia (p. ej. el cliente WebSocket) lo implementan para
+// que una invalidación no sea repoblada con el mismo dato incorrecto.
+type PriceCacheInvalidator interface {
+	// InvalidatePrices elimina los precios cacheados de pairs
+	InvalidatePrices(ctx context.Context, pairs []string) error
+
+	// FlushPrices elimina todos los precios cacheados y retorna cuántos se eliminaron
+	FlushPrices(ctx context.Context) (int, error)
+}
+
+// PriceValidator decide si un precio entrante es confiable antes de cachearlo.
+// previous es el último precio conocido del par (nil si no hay). Un error indica
+// que el tick fue puesto en cuarentena y no debe almacenarse.
+type PriceValidator interface {
+	Validate(ctx context.Context, candidate, previous *entities.Price) error
+}
+
+// ErrPriceDeviation indica que el precio cacheado se aparta de la fuente de
+// referencia más de lo tolerado y se retiene en lugar de servirse
+var ErrPriceDeviation = errors.New("price deviates from reference source")
+
+// PriceGuard decide si el precio cacheado de un par puede servirse. Un error
+// (que envuelve ErrPriceDeviation) indica que el precio está retenido.
+type PriceGuard interface {
+	Allow(pair string) error
+}
+
+// PairActivity mantiene activos pares consumidos por conexiones de streaming, que
+// no pasan por GetLastPrice. Hold con pairs vacío retiene todos los pares; release
+// libera la retención y es idempotente.
+type PairActivity interface {
+	Hold(pairs []string) (release func())
 }
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// Namespaces conocidos para el estado mutable en runtime
+const (
+	StateNamespacePairs   = "pairs"
+	StateNamespaceAlerts  = "alerts"
+	StateNamespaceAPIKeys = "api_keys"
+	// StateNamespaceFeatureFlags guarda los overrides de feature flags ("true"/"false")
+	StateNamespaceFeatureFlags = "feature_flags"
+)
+
+// StateRepository persiste el estado mutable del servicio (pares agregados,
+// alertas, API keys) para que sobreviva reinicios y pueda compartirse entre réplicas.
+// A diferencia de Cache, los valores no expiran.
+type StateRepository interface {
+	Get(ctx context.Context, namespace, key string) (string, error)
+	Put(ctx context.Context, namespace, key string, value string) error
+	Delete(ctx context.Context, namespace, key string) error
+	List(ctx context.Context, namespace string) (map[string]string, error)
+	Close() error
+}
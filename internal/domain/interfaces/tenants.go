@@ -0,0 +1,48 @@
+package interfaces
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+// StateNamespaceTenants guarda los tenants creados vía API de administración (JSON)
+const StateNamespaceTenants = "tenants"
+
+var (
+	// ErrUnknownTenant indica un tenant inexistente
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrTenantReadOnly indica un tenant declarado en configuración, que no se modifica en runtime
+	ErrTenantReadOnly = errors.New("tenant is declared in configuration")
+	// ErrTenantConflict indica una API key ya usada por otro tenant o por el operador
+	ErrTenantConflict = errors.New("api key is already in use")
+	// ErrTenantManagementDisabled indica que la gestión de tenants en runtime está deshabilitada
+	ErrTenantManagementDisabled = errors.New("tenant management is disabled")
+)
+
+// Orígenes de un tenant
+const (
+	TenantSourceConfig = "config"
+	TenantSourceAdmin  = "admin"
+)
+
+// TenantState es un tenant con su origen; las API keys no se exponen
+type TenantState struct {
+	Tenant   entities.Tenant // APIKeys vacío
+	Source   string          // config o admin
+	KeyCount int
+}
+
+// TenantDirectory resuelve el tenant dueño de una API key (middleware de auth)
+type TenantDirectory interface {
+	TenantByAPIKey(apiKey string) (*entities.Tenant, bool)
+}
+
+// TenantManager permite además listar y gestionar tenants (endpoints de admin)
+type TenantManager interface {
+	TenantDirectory
+	Tenants() []TenantState
+	ManagementEnabled() bool
+	PutTenant(ctx context.Context, tenant entities.Tenant) error
+	DeleteTenant(ctx context.Context, name string) error
+}
@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// StateNamespaceUsage guarda los agregados diarios de uso por réplica y API key (JSON)
+const StateNamespaceUsage = "usage"
+
+// UsageDateLayout es el formato de los días de los registros de uso (UTC)
+const UsageDateLayout = "2006-01-02"
+
+// UsageRecord es el uso de una API key durante un día (UTC)
+type UsageRecord struct {
+	Date           string // UsageDateLayout
+	Tenant         string // vacío para la key del operador
+	KeyID          string // entities.APIKeyID
+	Requests       int64
+	StreamMessages int64
+}
+
+// UsageRecorder cuenta el uso de la API key autenticada en ctx; las requests sin
+// API key no se cuentan
+type UsageRecorder interface {
+	RecordRequest(ctx context.Context)
+	RecordStreamMessages(ctx context.Context, count int)
+}
+
+// UsageReporter agrega el uso de todas las réplicas
+type UsageReporter interface {
+	// Usage devuelve los registros entre from y to (días UTC, inclusive) ordenados
+	// por fecha, tenant y key; tenant != "" filtra por tenant
+	Usage(ctx context.Context, from, to time.Time, tenant string) ([]UsageRecord, error)
+	RetentionDays() int
+}
+
+// UsageTracker registra y reporta el uso por API key
+type UsageTracker interface {
+	UsageRecorder
+	UsageReporter
+}
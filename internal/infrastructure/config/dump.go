@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue reemplaza los secretos en la configuración volcada
+const redactedValue = "[REDACTED]"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Redacted devuelve una copia de la configuración con los secretos ocultos
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	// Las API keys de los tenants viven en slices compartidos con c: copiarlas antes de ocultarlas
+	redacted.Tenancy.Tenants = make([]TenantConfig, len(c.Tenancy.Tenants))
+	for i, tenant := range c.Tenancy.Tenants {
+		tenant.APIKeys = append([]string(nil), tenant.APIKeys...)
+		redacted.Tenancy.Tenants[i] = tenant
+	}
+	for _, field := range secretFields(&redacted) {
+		if *field != "" {
+			*field = redactedValue
+		}
+	}
+	return &redacted
+}
+
+// DumpYAML escribe la configuración efectiva (con secretos ocultos) como YAML,
+// en el orden de los campos y con duraciones legibles (30s, 500ms)
+func DumpYAML(w io.Writer, config *Config) error {
+	node, err := dumpNode(reflect.ValueOf(*config.Redacted()))
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return encoder.Close()
+}
+
+// dumpNode convierte v en un nodo YAML usando las etiquetas yaml de los structs
+func dumpNode(v reflect.Value) (*yaml.Node, error) {
+	if v.Type() == durationType {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: time.Duration(v.Int()).String()}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			child, err := dumpNode(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, child)
+		}
+		return node, nil
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			child, err := dumpNode(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(key)}, child)
+		}
+		return node, nil
+	case reflect.Slice, reflect.Array:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			child, err := dumpNode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
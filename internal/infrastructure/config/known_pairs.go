@@ -0,0 +1,166 @@
+package config
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Fuentes de pares conocidos para validar business.supported_pairs
+const (
+	PairSourceStatic    = "static"    // lista embebida en el validador
+	PairSourceLive      = "live"      // endpoint AssetPairs de Kraken al arrancar
+	PairSourceAllowlist = "allowlist" // archivo con un par por línea
+)
+
+// liveFetchTimeout limita la consulta de pares en vivo durante el arranque
+const liveFetchTimeout = 10 * time.Second
+
+// PairLister obtiene la lista de pares disponibles en el exchange (formato BASE/QUOTE)
+type PairLister func(ctx context.Context) ([]string, error)
+
+// WithPairLister configura la fuente de pares para el modo live
+func (v *Validator) WithPairLister(lister PairLister) *Validator {
+	v.pairLister = lister
+	return v
+}
+
+// PairSource retorna la fuente efectivamente usada en la última validación
+// (puede ser "static" si live/allowlist falló y offline_fallback está activo)
+func (v *Validator) PairSource() string {
+	return v.usedPairSource
+}
+
+// SkippedPairs retorna los pares de base_assets × quote_currencies que la última
+// validación omitió por no estar entre los pares conocidos
+func (v *Validator) SkippedPairs() []string {
+	return v.skippedPairs
+}
+
+// expandPairs agrega a SupportedPairs el producto cartesiano BaseAssets ×
+// QuoteCurrencies. Sólo se agregan los pares conocidos (con source live, los que
+// Kraken lista en AssetPairs): el producto suele incluir combinaciones que el
+// exchange no opera, que se omiten y quedan en SkippedPairs.
+func (v *Validator) expandPairs(config *BusinessConfig, knownPairs map[string]bool) error {
+	v.skippedPairs = nil
+	if len(config.BaseAssets) == 0 && len(config.QuoteCurrencies) == 0 {
+		return nil
+	}
+	if len(config.BaseAssets) == 0 || len(config.QuoteCurrencies) == 0 {
+		return fmt.Errorf("base_assets and quote_currencies must be set together")
+	}
+
+	seen := make(map[string]bool, len(config.SupportedPairs))
+	for _, pair := range config.SupportedPairs {
+		seen[entities.CanonicalPair(pair)] = true
+	}
+	for _, base := range config.BaseAssets {
+		for _, quote := range config.QuoteCurrencies {
+			pair, err := entities.NormalizePair(base + "/" + quote)
+			if err != nil {
+				return fmt.Errorf("invalid base_assets/quote_currencies entry: %w", err)
+			}
+			if b, q, _ := entities.SplitPair(pair); b == q || seen[pair] {
+				continue
+			}
+			seen[pair] = true
+			if !v.isKnownPair(pair, knownPairs) {
+				v.skippedPairs = append(v.skippedPairs, pair)
+				continue
+			}
+			config.SupportedPairs = append(config.SupportedPairs, pair)
+		}
+	}
+
+	if len(config.SupportedPairs) == 0 {
+		return fmt.Errorf("no pair of base_assets x quote_currencies is known to the %s pair source, skipped: %v", v.usedPairSource, v.skippedPairs)
+	}
+	return nil
+}
+
+// knownPairsFor resuelve el conjunto de pares conocidos según la configuración
+func (v *Validator) knownPairsFor(config PairValidationConfig) (map[string]bool, error) {
+	var (
+		known map[string]bool
+		err   error
+	)
+
+	switch config.Source {
+	case "", PairSourceStatic:
+		v.usedPairSource = PairSourceStatic
+		return v.getKnownKrakenPairs(), nil
+	case PairSourceAllowlist:
+		known, err = loadPairAllowlist(config.AllowlistFile)
+	case PairSourceLive:
+		known, err = v.fetchLivePairs()
+	default:
+		return nil, fmt.Errorf("invalid pair_validation source: %s", config.Source)
+	}
+
+	if err != nil {
+		if !config.OfflineFallback {
+			return nil, fmt.Errorf("failed to load known pairs from %s: %w", config.Source, err)
+		}
+		v.usedPairSource = PairSourceStatic
+		return v.getKnownKrakenPairs(), nil
+	}
+
+	v.usedPairSource = config.Source
+	return known, nil
+}
+
+// fetchLivePairs consulta el PairLister configurado
+func (v *Validator) fetchLivePairs() (map[string]bool, error) {
+	if v.pairLister == nil {
+		return nil, fmt.Errorf("no pair lister configured for live validation")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), liveFetchTimeout)
+	defer cancel()
+
+	pairs, err := v.pairLister(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("pair lister returned no pairs")
+	}
+
+	known := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		known[entities.CanonicalPair(pair)] = true
+	}
+	return known, nil
+}
+
+// loadPairAllowlist lee un par por línea; líneas vacías y comentarios (#) se ignoran
+func loadPairAllowlist(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pair allowlist: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	known := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		known[entities.CanonicalPair(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pair allowlist: %w", err)
+	}
+	if len(known) == 0 {
+		return nil, fmt.Errorf("pair allowlist %s is empty", path)
+	}
+	return known, nil
+}
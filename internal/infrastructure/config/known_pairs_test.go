@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func businessWith(pairs []string, validation PairValidationConfig) *BusinessConfig {
+	return &BusinessConfig{SupportedPairs: pairs, CachePrefix: "price:", PairValidation: validation}
+}
+
+func TestValidateBusiness_AllowlistSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pairs.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# allowed\nDOGE/USD\n\nbtc/usd\n"), 0o600))
+
+	validator := NewValidator()
+	validation := PairValidationConfig{Source: PairSourceAllowlist, AllowlistFile: path}
+
+	assert.NoError(t, validator.validateBusiness(businessWith([]string{"DOGE/USD", "BTC/USD"}, validation)))
+	assert.Equal(t, PairSourceAllowlist, validator.PairSource())
+
+	err := validator.validateBusiness(businessWith([]string{"ETH/USD"}, validation))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown trading pairs")
+}
+
+func TestValidateBusiness_AllowlistMissingFile(t *testing.T) {
+	validator := NewValidator()
+
+	err := validator.validateBusiness(businessWith([]string{"BTC/USD"}, PairValidationConfig{Source: PairSourceAllowlist}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "allowlist_file cannot be empty")
+
+	missing := PairValidationConfig{Source: PairSourceAllowlist, AllowlistFile: "/nonexistent/pairs.txt"}
+	err = validator.validateBusiness(businessWith([]string{"BTC/USD"}, missing))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load known pairs")
+
+	missing.OfflineFallback = true
+	assert.NoError(t, validator.validateBusiness(businessWith([]string{"BTC/USD"}, missing)))
+	assert.Equal(t, PairSourceStatic, validator.PairSource())
+}
+
+func TestValidateBusiness_LiveSource(t *testing.T) {
+	validator := NewValidator().WithPairLister(func(ctx context.Context) ([]string, error) {
+		return []string{"PEPE/USD", "BTC/USD"}, nil
+	})
+	live := PairValidationConfig{Source: PairSourceLive}
+
+	assert.NoError(t, validator.validateBusiness(businessWith([]string{"PEPE/USD"}, live)))
+	assert.Equal(t, PairSourceLive, validator.PairSource())
+	assert.Error(t, validator.validateBusiness(businessWith([]string{"ETH/USD"}, live)))
+}
+
+func TestValidateBusiness_LiveSourceOfflineFallback(t *testing.T) {
+	validator := NewValidator().WithPairLister(func(ctx context.Context) ([]string, error) {
+		return nil, errors.New("network unreachable")
+	})
+
+	strict := PairValidationConfig{Source: PairSourceLive}
+	assert.Error(t, validator.validateBusiness(businessWith([]string{"BTC/USD"}, strict)))
+
+	fallback := PairValidationConfig{Source: PairSourceLive, OfflineFallback: true}
+	assert.NoError(t, validator.validateBusiness(businessWith([]string{"BTC/USD"}, fallback)))
+	assert.Equal(t, PairSourceStatic, validator.PairSource())
+
+	// Sin lister configurado también se aplica el fallback
+	assert.NoError(t, NewValidator().validateBusiness(businessWith([]string{"BTC/USD"}, fallback)))
+}
+
+func TestValidateBusiness_InvalidSource(t *testing.T) {
+	err := NewValidator().validateBusiness(businessWith([]string{"BTC/USD"}, PairValidationConfig{Source: "etcd"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pair_validation source")
+}
+
+func TestValidateBusiness_ExpandsBaseAndQuoteAssets(t *testing.T) {
+	validator := NewValidator()
+	business := businessWith([]string{"ETH/BTC"}, PairValidationConfig{Source: PairSourceStatic})
+	business.BaseAssets = []string{"XBT", "eth", "LTC"}
+	business.QuoteCurrencies = []string{"USD", "EUR", "CHF"}
+
+	require.NoError(t, validator.validateBusiness(business))
+	assert.Equal(t, []string{"ETH/BTC", "BTC/USD", "BTC/EUR", "BTC/CHF", "ETH/USD", "ETH/EUR", "ETH/CHF", "LTC/USD", "LTC/EUR"}, business.SupportedPairs)
+	assert.Equal(t, []string{"LTC/CHF"}, validator.SkippedPairs(), "pairs the source does not know are skipped")
+}
+
+func TestValidateBusiness_ExpansionUsesLiveSource(t *testing.T) {
+	validator := NewValidator().WithPairLister(func(ctx context.Context) ([]string, error) {
+		return []string{"BTC/USD", "BTC/JPY", "PEPE/USD"}, nil
+	})
+	business := businessWith(nil, PairValidationConfig{Source: PairSourceLive})
+	business.BaseAssets = []string{"BTC", "PEPE"}
+	business.QuoteCurrencies = []string{"USD", "JPY", "BTC"}
+
+	require.NoError(t, validator.validateBusiness(business))
+	assert.Equal(t, []string{"BTC/USD", "BTC/JPY", "PEPE/USD"}, business.SupportedPairs)
+	assert.Equal(t, []string{"PEPE/JPY", "PEPE/BTC"}, validator.SkippedPairs(), "BTC/BTC is never generated")
+}
+
+func TestValidateBusiness_ExpansionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		bases  []string
+		quotes []string
+		want   string
+	}{
+		{"bases without quotes", []string{"BTC"}, nil, "must be set together"},
+		{"quotes without bases", nil, []string{"USD"}, "must be set together"},
+		{"invalid asset", []string{"BTC/USD"}, []string{"EUR"}, "invalid base_assets/quote_currencies entry"},
+		{"nothing known", []string{"DOGE"}, []string{"MOON"}, "no pair of base_assets x quote_currencies is known"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			business := businessWith(nil, PairValidationConfig{})
+			business.BaseAssets = tt.bases
+			business.QuoteCurrencies = tt.quotes
+			err := NewValidator().validateBusiness(business)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -1,37 +1,83 @@
 package config
 
 import (
+	"btc-ltp-service/internal/domain/entities"
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
 )
 
-// Loader handles configuration loading using Viper
+// defaultConfigPaths son los directorios donde se buscan las capas de configuración
+var defaultConfigPaths = []string{
+	"./configs",    // Configs directory in root
+	"../configs",   // For when running from cmd/
+	".",            // Current directory
+	"/etc/btc-ltp", // System (production)
+}
+
+const (
+	// baseConfigName es la capa común a todos los entornos
+	baseConfigName = "config"
+	// localConfigName es la capa de overrides locales (no versionada)
+	localConfigName = "config.local"
+)
+
+// Loader handles configuration loading using Viper. Precedence (lowest to highest):
+// defaults → config.yaml → config.{env}.yaml → config.local.yaml → ENV vars.
+// Secret references (file://, env://) are resolved last.
 type Loader struct {
-	v *viper.Viper
+	v       *viper.Viper
+	paths   []string
+	sources []string
+	origins map[string]ValueSource
 }
 
 // NewLoader creates a new configuration loader instance
 func NewLoader() *Loader {
 	return &Loader{
-		v: viper.New(),
+		v:     viper.New(),
+		paths: defaultConfigPaths,
 	}
 }
 
-// Load loads configuration from files and environment variables
+// WithConfigPaths reemplaza los directorios de búsqueda de las capas YAML
+func (l *Loader) WithConfigPaths(paths ...string) *Loader {
+	l.paths = paths
+	return l
+}
+
+// Sources devuelve los archivos aplicados en el último Load, en orden de precedencia
+func (l *Loader) Sources() []string {
+	return append([]string(nil), l.sources...)
+}
+
+// Load loads configuration from the base and local files and environment variables
 func (l *Loader) Load() (*Config, error) {
+	return l.LoadForEnvironment("")
+}
+
+// LoadForEnvironment loads the base configuration plus the overlay for environment
+// (config.{environment}.yaml) and local overrides, then environment variables
+func (l *Loader) LoadForEnvironment(environment string) (*Config, error) {
 	// 1. Configure Viper
-	if err := l.setupViper(); err != nil {
-		return nil, fmt.Errorf("failed to setup viper: %w", err)
+	l.setupViper()
+
+	// 2. Merge YAML layers; missing files are skipped
+	layers := []string{baseConfigName}
+	if environment != "" {
+		layers = append(layers, fmt.Sprintf("config.%s", environment))
 	}
+	layers = append(layers, localConfigName)
 
-	// 2. Read configuration
-	if err := l.v.ReadInConfig(); err != nil {
-		// If config.yaml doesn't exist, use only env vars and defaults
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+	l.sources = nil
+	l.origins = make(map[string]ValueSource)
+	for _, layer := range layers {
+		if err := l.mergeLayer(layer); err != nil {
+			return nil, err
 		}
 	}
 
@@ -44,21 +90,26 @@ func (l *Loader) Load() (*Config, error) {
 	// 4. Override with specific env vars (for compatibility)
 	l.overrideWithEnvVars(config)
 
+	// 5. Resolve secret references (file://, env://)
+	if err := resolveSecrets(config); err != nil {
+		return nil, err
+	}
+
+	// 6. Record where each value came from (GET /api/v1/admin/config)
+	l.recordEnv()
+	config.provenance = &Provenance{
+		Environment: environment,
+		Files:       l.Sources(),
+		Values:      l.origins,
+	}
+
 	return config, nil
 }
 
-// setupViper configures Viper to read files and env vars
-func (l *Loader) setupViper() error {
-	// Configure to read YAML files
-	l.v.SetConfigName("config")
+// setupViper configures Viper to read env vars
+func (l *Loader) setupViper() {
 	l.v.SetConfigType("yaml")
 
-	// Search for configuration files in:
-	l.v.AddConfigPath("./configs")    // Configs directory in root
-	l.v.AddConfigPath("../configs")   // For when running from cmd/
-	l.v.AddConfigPath(".")            // Current directory
-	l.v.AddConfigPath("/etc/btc-ltp") // System (production)
-
 	// Automatic environment variables
 	l.v.AutomaticEnv()
 	l.v.SetEnvPrefix("BTC_LTP") // Prefix for env vars: BTC_LTP_SERVER_PORT
@@ -66,37 +117,195 @@ func (l *Loader) setupViper() error {
 
 	// Explicit env vars mapping (for backward compatibility)
 	l.bindEnvVars()
+}
+
+// mergeLayer busca name.yaml en los directorios configurados, interpola variables
+// de entorno y lo fusiona sobre las capas anteriores
+func (l *Loader) mergeLayer(name string) error {
+	path, ok := l.findConfigFile(name)
+	if !ok {
+		return nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	content, err := interpolateEnv(raw)
+	if err != nil {
+		return fmt.Errorf("failed to interpolate config file %s: %w", path, err)
+	}
+	if err := l.v.MergeConfig(bytes.NewReader(content)); err != nil {
+		return fmt.Errorf("failed to merge config file %s: %w", path, err)
+	}
+	if err := l.recordFile(path, content); err != nil {
+		return err
+	}
 
+	l.sources = append(l.sources, path)
 	return nil
 }
 
+// findConfigFile devuelve el primer name.yaml (o .yml) existente en los directorios de búsqueda
+func (l *Loader) findConfigFile(name string) (string, bool) {
+	for _, dir := range l.paths {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dir, name+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// envBindings mapea claves de configuración a env vars explícitas (sin prefijo)
+var envBindings = map[string]string{
+	"server.port":                              "PORT",
+	"server.reuse_port":                        "SERVER_REUSE_PORT",
+	"server.drain_period":                      "SHUTDOWN_DRAIN_PERIOD",
+	"server.request_timeout":                   "REQUEST_TIMEOUT",
+	"server.response_cache.enabled":            "RESPONSE_CACHE_ENABLED",
+	"server.response_cache.ttl":                "RESPONSE_CACHE_TTL",
+	"server.max_bulk_pairs":                    "MAX_BULK_PAIRS",
+	"server.input_validation.enabled":          "INPUT_VALIDATION_ENABLED",
+	"server.input_validation.max_body_bytes":   "INPUT_VALIDATION_MAX_BODY_BYTES",
+	"server.input_validation.strict_query":     "INPUT_VALIDATION_STRICT_QUERY",
+	"server.problem_type_base_uri":             "PROBLEM_TYPE_BASE_URI",
+	"server.admin_ui.enabled":                  "ADMIN_UI_ENABLED",
+	"server.streaming.buffer_size":             "STREAM_BUFFER_SIZE",
+	"server.streaming.write_timeout":           "STREAM_WRITE_TIMEOUT",
+	"server.streaming.slow_client_policy":      "STREAM_SLOW_CLIENT_POLICY",
+	"server.streaming.max_updates_per_second":  "STREAM_MAX_UPDATES_PER_SECOND",
+	"server.streaming.max_message_bytes":       "STREAM_MAX_MESSAGE_BYTES",
+	"cache.backend":                            "CACHE_BACKEND",
+	"cache.ttl":                                "CACHE_TTL",
+	"cache.codec":                              "CACHE_CODEC",
+	"cache.refresh_failure_threshold":          "CACHE_REFRESH_FAILURE_THRESHOLD",
+	"cache.refresh_max_backoff":                "CACHE_REFRESH_MAX_BACKOFF",
+	"cache.redis.addr":                         "REDIS_ADDR",
+	"cache.redis.password":                     "REDIS_PASSWORD",
+	"cache.redis.db":                           "REDIS_DB",
+	"state.backend":                            "STATE_BACKEND",
+	"state.sql.driver":                         "STATE_SQL_DRIVER",
+	"state.sql.dsn":                            "STATE_SQL_DSN",
+	"business.supported_pairs":                 "SUPPORTED_PAIRS",
+	"business.base_assets":                     "BASE_ASSETS",
+	"business.quote_currencies":                "QUOTE_CURRENCIES",
+	"exchange.proxy.url":                       "EXCHANGE_PROXY_URL",
+	"exchange.proxy.no_proxy":                  "EXCHANGE_NO_PROXY",
+	"exchange.kraken.rest_url":                 "KRAKEN_BASE_URL",
+	"exchange.kraken.rest_urls":                "KRAKEN_REST_URLS",
+	"exchange.kraken.websocket_urls":           "KRAKEN_WEBSOCKET_URLS",
+	"exchange.kraken.endpoint_cooldown":        "KRAKEN_ENDPOINT_COOLDOWN",
+	"exchange.kraken.timeout":                  "KRAKEN_TIMEOUT",
+	"exchange.kraken.fallback_timeout":         "KRAKEN_FALLBACK_TIMEOUT",
+	"exchange.kraken.price_cache_ttl":          "PRICE_CACHE_TTL",
+	"exchange.kraken.staleness_interval":       "KRAKEN_STALENESS_INTERVAL",
+	"exchange.kraken.staleness_max_age":        "KRAKEN_STALENESS_MAX_AGE",
+	"exchange.kraken.channel_capacity":         "KRAKEN_CHANNEL_CAPACITY",
+	"exchange.kraken.channel_overflow_policy":  "KRAKEN_CHANNEL_OVERFLOW_POLICY",
+	"exchange.kraken.dynamic_pairs":            "KRAKEN_DYNAMIC_PAIRS",
+	"exchange.kraken.rest_batch_size":          "KRAKEN_REST_BATCH_SIZE",
+	"exchange.kraken.max_pairs_per_connection": "KRAKEN_MAX_PAIRS_PER_CONNECTION",
+	"exchange.kraken.dedup_window":             "KRAKEN_DEDUP_WINDOW",
+	"exchange.kraken.source_switch_cooldown":   "KRAKEN_SOURCE_SWITCH_COOLDOWN",
+	"exchange.kraken.warm_standby":             "KRAKEN_WARM_STANDBY",
+	"exchange.kraken.max_frame_bytes":          "KRAKEN_MAX_FRAME_BYTES",
+	"exchange.kraken.max_connection_age":       "KRAKEN_MAX_CONNECTION_AGE",
+	"exchange.kraken.retry_budget.enabled":     "KRAKEN_RETRY_BUDGET_ENABLED",
+	"exchange.kraken.retry_budget.max_tokens":  "KRAKEN_RETRY_BUDGET_MAX_TOKENS",
+	"exchange.kraken.retry_budget.token_ratio": "KRAKEN_RETRY_BUDGET_TOKEN_RATIO",
+	"exchange.kraken.egress.allowed_cidrs":     "KRAKEN_EGRESS_ALLOWED_CIDRS",
+	"exchange.kraken.egress.pin_dns":           "KRAKEN_EGRESS_PIN_DNS",
+	"exchange.kraken.egress.resolve_interval":  "KRAKEN_EGRESS_RESOLVE_INTERVAL",
+	"exchange.kraken.tls.ca_file":              "KRAKEN_TLS_CA_FILE",
+	"exchange.kraken.tls.min_version":          "KRAKEN_TLS_MIN_VERSION",
+	"exchange.kraken.tls.pinned_spki":          "KRAKEN_TLS_PINNED_SPKI",
+	"exchange.kraken.proxy.url":                "KRAKEN_PROXY_URL",
+	"exchange.kraken.proxy.no_proxy":           "KRAKEN_NO_PROXY",
+	"logging.level":                            "LOG_LEVEL",
+	"logging.format":                           "LOG_FORMAT",
+	"logging.slow_request_threshold":           "SLOW_REQUEST_THRESHOLD",
+	"rate_limit.capacity":                      "RATE_LIMIT_CAPACITY",
+	"rate_limit.refill_rate":                   "RATE_LIMIT_REFILL_RATE",
+	"rate_limit.enabled":                       "RATE_LIMIT_ENABLED",
+	"rate_limit.client_id.strategy":            "RATE_LIMIT_CLIENT_ID_STRATEGY",
+	"rate_limit.client_id.trusted_proxies":     "RATE_LIMIT_TRUSTED_PROXIES",
+	"rate_limit.client_id.header":              "RATE_LIMIT_CLIENT_ID_HEADER",
+	"leader_election.enabled":                  "LEADER_ELECTION_ENABLED",
+	"leader_election.instance_id":              "LEADER_ELECTION_INSTANCE_ID",
+	"price_validation.enabled":                 "PRICE_VALIDATION_ENABLED",
+	"warmup.timeout":                           "WARMUP_TIMEOUT",
+	"feature_flags.overrides_enabled":          "FEATURE_FLAGS_OVERRIDES_ENABLED",
+	"feature_flags.refresh_interval":           "FEATURE_FLAGS_REFRESH_INTERVAL",
+	"metrics.disabled_groups":                  "METRICS_DISABLED_GROUPS",
+	"slo.enabled":                              "SLO_ENABLED",
+	"slo.window":                               "SLO_WINDOW",
+	"slo.evaluation_interval":                  "SLO_EVALUATION_INTERVAL",
+	"runtime.auto_max_procs":                   "RUNTIME_AUTO_MAX_PROCS",
+	"runtime.max_procs":                        "RUNTIME_MAX_PROCS",
+	"runtime.memory_limit":                     "RUNTIME_MEMORY_LIMIT",
+	"runtime.memory_limit_ratio":               "RUNTIME_MEMORY_LIMIT_RATIO",
+	// Timeouts mappings
+	"timeouts.http_read":               "HTTP_READ_TIMEOUT",
+	"timeouts.http_write":              "HTTP_WRITE_TIMEOUT",
+	"timeouts.http_idle":               "HTTP_IDLE_TIMEOUT",
+	"timeouts.cache_refresh":           "CACHE_REFRESH_TIMEOUT",
+	"timeouts.websocket.ping_interval": "KRAKEN_WS_PING_INTERVAL",
+	"timeouts.websocket.pong_wait":     "KRAKEN_WS_PONG_WAIT",
+	"timeouts.websocket.write_wait":    "KRAKEN_WS_WRITE_WAIT",
+	"timeouts.websocket.quiet_timeout": "KRAKEN_WS_QUIET_TIMEOUT",
+	// Price deviation guard mappings
+	"price_validation.deviation_guard.enabled":               "DEVIATION_GUARD_ENABLED",
+	"price_validation.deviation_guard.interval":              "DEVIATION_GUARD_INTERVAL",
+	"price_validation.deviation_guard.max_deviation_percent": "DEVIATION_GUARD_MAX_PERCENT",
+	"price_validation.deviation_guard.action":                "DEVIATION_GUARD_ACTION",
+	// Anomaly detection mappings
+	"anomaly_detection.enabled":        "ANOMALY_DETECTION_ENABLED",
+	"anomaly_detection.flatline_after": "ANOMALY_FLATLINE_AFTER",
+	"anomaly_detection.flatline_pairs": "ANOMALY_FLATLINE_PAIRS",
+	"anomaly_detection.spike_z_score":  "ANOMALY_SPIKE_Z_SCORE",
+	// Price format mappings
+	"business.price_format.enabled": "PRICE_FORMAT_ENABLED",
+	"business.price_format.mode":    "PRICE_FORMAT_MODE",
+	// Pair pruning mappings
+	"business.pair_pruning.enabled":    "PAIR_PRUNING_ENABLED",
+	"business.pair_pruning.idle_after": "PAIR_PRUNING_IDLE_AFTER",
+	// On-demand pairs mappings
+	"business.on_demand_pairs.enabled":   "ON_DEMAND_PAIRS_ENABLED",
+	"business.on_demand_pairs.max_pairs": "ON_DEMAND_PAIRS_MAX",
+	// Tenancy mappings
+	"tenancy.enabled":           "TENANCY_ENABLED",
+	"tenancy.admin_api_enabled": "TENANCY_ADMIN_API_ENABLED",
+	"tenancy.refresh_interval":  "TENANCY_REFRESH_INTERVAL",
+	// Usage accounting mappings
+	"usage.enabled":        "USAGE_ENABLED",
+	"usage.flush_interval": "USAGE_FLUSH_INTERVAL",
+	"usage.retention_days": "USAGE_RETENTION_DAYS",
+	// Audit trail mappings
+	"audit.enabled":        "AUDIT_ENABLED",
+	"audit.flush_interval": "AUDIT_FLUSH_INTERVAL",
+	"audit.retention_days": "AUDIT_RETENTION_DAYS",
+	// Maintenance window mappings
+	"maintenance.enabled":          "MAINTENANCE_ENABLED",
+	"maintenance.refresh_interval": "MAINTENANCE_REFRESH_INTERVAL",
+	// Authentication configuration mappings
+	"auth.enabled":     "AUTH_ENABLED",
+	"auth.api_key":     "AUTH_API_KEY",
+	"auth.header_name": "AUTH_HEADER_NAME",
+}
+
+// envOverrides son las env vars booleanas que aplica overrideWithEnvVars
+var envOverrides = map[string]string{
+	"development.dev_mode":   "DEV_MODE",
+	"development.mock_mode":  "MOCK_MODE",
+	"development.debug_mode": "DEBUG_MODE",
+}
+
 // bindEnvVars maps specific environment variables to configuration keys
 func (l *Loader) bindEnvVars() {
-	// Existing environment variables (backward compatibility)
-	envMappings := map[string]string{
-		"server.port":                      "PORT",
-		"cache.backend":                    "CACHE_BACKEND",
-		"cache.ttl":                        "CACHE_TTL",
-		"cache.redis.addr":                 "REDIS_ADDR",
-		"cache.redis.password":             "REDIS_PASSWORD",
-		"cache.redis.db":                   "REDIS_DB",
-		"business.supported_pairs":         "SUPPORTED_PAIRS",
-		"exchange.kraken.rest_url":         "KRAKEN_BASE_URL",
-		"exchange.kraken.timeout":          "KRAKEN_TIMEOUT",
-		"exchange.kraken.fallback_timeout": "KRAKEN_FALLBACK_TIMEOUT",
-		"exchange.kraken.price_cache_ttl":  "PRICE_CACHE_TTL",
-		"logging.level":                    "LOG_LEVEL",
-		"logging.format":                   "LOG_FORMAT",
-		"rate_limit.capacity":              "RATE_LIMIT_CAPACITY",
-		"rate_limit.refill_rate":           "RATE_LIMIT_REFILL_RATE",
-		"rate_limit.enabled":               "RATE_LIMIT_ENABLED",
-		// Authentication configuration mappings
-		"auth.enabled":     "AUTH_ENABLED",
-		"auth.api_key":     "AUTH_API_KEY",
-		"auth.header_name": "AUTH_HEADER_NAME",
-	}
-
-	for configKey, envVar := range envMappings {
+	for configKey, envVar := range envBindings {
 		_ = l.v.BindEnv(configKey, envVar)
 	}
 }
@@ -109,9 +318,8 @@ func (l *Loader) overrideWithEnvVars(config *Config) {
 		var cleanPairs []string
 
 		for _, pair := range pairs {
-			pair = strings.TrimSpace(strings.ToUpper(pair))
-			if pair != "" && strings.Contains(pair, "/") {
-				cleanPairs = append(cleanPairs, pair)
+			if normalized, err := entities.NormalizePair(pair); err == nil {
+				cleanPairs = append(cleanPairs, normalized)
 			}
 		}
 
@@ -120,6 +328,20 @@ func (l *Loader) overrideWithEnvVars(config *Config) {
 		}
 	}
 
+	// BASE_ASSETS y QUOTE_CURRENCIES como strings separados por comas
+	if bases := splitList(os.Getenv("BASE_ASSETS")); len(bases) > 0 {
+		config.Business.BaseAssets = bases
+	}
+	if quotes := splitList(os.Getenv("QUOTE_CURRENCIES")); len(quotes) > 0 {
+		config.Business.QuoteCurrencies = quotes
+	}
+
+	// Pares en forma canónica (XBTUSD, btc-usd → BTC/USD); los no reconocibles se
+	// conservan para que el validator los reporte
+	for i, pair := range config.Business.SupportedPairs {
+		config.Business.SupportedPairs[i] = entities.CanonicalPair(pair)
+	}
+
 	// Development mode env vars
 	if devMode := os.Getenv("DEV_MODE"); devMode == "true" || devMode == "1" {
 		config.Development.DevMode = true
@@ -132,36 +354,15 @@ func (l *Loader) overrideWithEnvVars(config *Config) {
 	}
 }
 
-// LoadForEnvironment loads specific configuration for an environment
-func (l *Loader) LoadForEnvironment(environment string) (*Config, error) {
-	// Load base config first
-	config, err := l.Load()
-	if err != nil {
-		return nil, err
-	}
-
-	// Try to load environment-specific override
-	if environment != "" {
-		envConfigFile := fmt.Sprintf("config.%s", environment)
-		l.v.SetConfigName(envConfigFile)
-
-		if err := l.v.MergeInConfig(); err != nil {
-			// Not a critical error if environment file doesn't exist
-			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-				return nil, fmt.Errorf("failed to merge environment config: %w", err)
-			}
-		}
-
-		// Re-unmarshal with merged configuration
-		if err := l.v.Unmarshal(config); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal merged config: %w", err)
+// splitList separa una lista separada por comas descartando elementos vacíos
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
-
-		// Re-apply env var overrides
-		l.overrideWithEnvVars(config)
 	}
-
-	return config, nil
+	return items
 }
 
 // GetEnvironment determina el entorno actual desde ENV vars
@@ -53,10 +53,16 @@ func TestTradingPairValidation_Comprehensive(t *testing.T) {
 		},
 		{
 			name:          "invalid_format_no_slash",
-			pairs:         []string{"BTCUSD", "ETH/USD"},
+			pairs:         []string{"BITCOIN", "ETH/USD"},
 			expectError:   true,
 			errorContains: "invalid pair format",
-			description:   "Pairs without slash should fail",
+			description:   "Pairs without separator or known quote should fail",
+		},
+		{
+			name:        "valid_aliases",
+			pairs:       []string{"XBTUSD", "btc-eur", "eth_usd", "XXBTZGBP"},
+			expectError: false,
+			description: "Aliases are normalized to BASE/QUOTE before validation",
 		},
 		{
 			name:          "invalid_format_multiple_slashes",
@@ -274,7 +280,9 @@ func TestIsKnownPair(t *testing.T) {
 		{"known_pair_case_insensitive", "btc/usd", true},
 		{"unknown_pair", "FAKE/COIN", false},
 		{"empty_pair", "", false},
-		{"invalid_format", "BTCUSD", false},
+		{"invalid_format", "BITCOIN", false},
+		{"alias_concatenated", "XBTUSD", true},
+		{"alias_dash", "btc-usd", true},
 		{"partial_match_base", "BTC/UNKNOWN", false},
 		{"partial_match_quote", "UNKNOWN/USD", false},
 	}
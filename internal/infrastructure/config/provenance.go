@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Orígenes de un valor de configuración
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// envPrefix es el prefijo de las env vars automáticas (BTC_LTP_SERVER_PORT)
+const envPrefix = "BTC_LTP"
+
+// ValueSource indica de dónde salió un valor: un default, un archivo (From es su
+// path) o una variable de entorno (From es su nombre)
+type ValueSource struct {
+	Source string
+	From   string
+}
+
+// Provenance describe cómo se armó una configuración: archivos aplicados y
+// origen de cada valor que no es un default
+type Provenance struct {
+	Environment string
+	Files       []string
+	Values      map[string]ValueSource
+}
+
+// Source devuelve el origen del valor de key (p. ej. "server.port")
+func (p Provenance) Source(key string) ValueSource {
+	if source, ok := p.Values[key]; ok {
+		return source
+	}
+	return ValueSource{Source: SourceDefault}
+}
+
+// Provenance devuelve el origen de los valores; vacío si la configuración no
+// se cargó con Loader (todos los valores son defaults)
+func (c *Config) Provenance() Provenance {
+	if c.provenance == nil {
+		return Provenance{}
+	}
+	return *c.provenance
+}
+
+// Keys devuelve las claves de configuración en el orden de los campos. Los mapas
+// y listas (route_timeouts, feature_flags.flags) son una sola clave.
+func Keys() []string {
+	return appendKeys(nil, "", reflect.TypeOf(Config{}))
+}
+
+func appendKeys(keys []string, prefix string, t reflect.Type) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			keys = appendKeys(keys, key+".", field.Type)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Values devuelve la configuración efectiva (con secretos ocultos) como mapas
+// anidados con las claves de YAML y duraciones legibles, lista para JSON
+func (c *Config) Values() (map[string]any, error) {
+	node, err := dumpNode(reflect.ValueOf(*c.Redacted()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	values := make(map[string]any)
+	if err := node.Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return values, nil
+}
+
+// recordFile marca como provenientes de path las claves definidas en content
+func (l *Loader) recordFile(path string, content []byte) error {
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	leaves := make(map[string]bool)
+	for _, key := range Keys() {
+		leaves[key] = true
+	}
+	recordKeys(doc, "", leaves, func(key string) {
+		l.origins[key] = ValueSource{Source: SourceFile, From: path}
+	})
+	return nil
+}
+
+// recordKeys recorre el documento YAML hasta las claves de configuración; las
+// claves desconocidas se ignoran (viper también las ignora)
+func recordKeys(doc map[string]any, prefix string, leaves map[string]bool, record func(string)) {
+	for name, value := range doc {
+		key := prefix + strings.ToLower(name)
+		if leaves[key] {
+			record(key)
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			recordKeys(nested, key+".", leaves, record)
+		}
+	}
+}
+
+// recordEnv marca las claves sobrescritas por env vars. Las automáticas
+// (BTC_LTP_*) solo aplican a claves que viper conoce: definidas en algún
+// archivo o con env var explícita.
+func (l *Loader) recordEnv() {
+	for _, key := range Keys() {
+		_, inFile := l.origins[key]
+		explicit, bound := envBindings[key]
+		if automatic := envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")); (inFile || bound) && os.Getenv(automatic) != "" {
+			l.origins[key] = ValueSource{Source: SourceEnv, From: automatic}
+		} else if bound && os.Getenv(explicit) != "" {
+			l.origins[key] = ValueSource{Source: SourceEnv, From: explicit}
+		}
+	}
+	for key, envVar := range envOverrides {
+		if value := os.Getenv(envVar); value == "true" || value == "1" {
+			l.origins[key] = ValueSource{Source: SourceEnv, From: envVar}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// fileSecretPrefix referencia un archivo cuyo contenido es el secreto (p. ej. Docker/K8s secrets)
+	fileSecretPrefix = "file://"
+	// envSecretPrefix referencia una variable de entorno que contiene el secreto
+	envSecretPrefix = "env://"
+)
+
+// envPlaceholder reconoce ${VAR} y ${VAR:-default} en los archivos YAML
+var envPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolateEnv reemplaza ${VAR} y ${VAR:-default} por el valor de la variable.
+// Una variable sin definir y sin default es un error (fail-fast); ${VAR:-} la deja vacía.
+func interpolateEnv(content []byte) ([]byte, error) {
+	var missing []string
+	result := envPlaceholder.ReplaceAllFunc(content, func(match []byte) []byte {
+		groups := envPlaceholder.FindSubmatch(match)
+		name := string(groups[1])
+		value, ok := os.LookupEnv(name)
+		if ok && value != "" {
+			return []byte(value)
+		}
+		if groups[2] != nil {
+			return groups[3]
+		}
+		if ok {
+			return nil
+		}
+		missing = append(missing, name)
+		return match
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// secretFields son los valores que aceptan referencias a secretos, por clave de configuración
+func secretFields(config *Config) map[string]*string {
+	fields := map[string]*string{
+		"cache.redis.password": &config.Cache.Redis.Password,
+		"auth.api_key":         &config.Auth.APIKey,
+		"state.sql.dsn":        &config.State.SQL.DSN,
+		// Las URLs de proxy pueden llevar usuario y contraseña
+		"exchange.proxy.url":        &config.Exchange.Proxy.URL,
+		"exchange.kraken.proxy.url": &config.Exchange.Kraken.Proxy.URL,
+	}
+	for i := range config.Tenancy.Tenants {
+		tenant := &config.Tenancy.Tenants[i]
+		for j := range tenant.APIKeys {
+			fields[fmt.Sprintf("tenancy.tenants.%s.api_keys[%d]", tenant.Name, j)] = &tenant.APIKeys[j]
+		}
+	}
+	return fields
+}
+
+// resolveSecrets reemplaza las referencias file:// y env:// por el secreto que apuntan.
+// Se aplica después de las env vars, así que REDIS_PASSWORD=file://... también funciona.
+func resolveSecrets(config *Config) error {
+	for key, field := range secretFields(config) {
+		value, err := resolveSecret(*field)
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret %s: %w", key, err)
+		}
+		*field = value
+	}
+	return nil
+}
+
+// resolveSecret devuelve el secreto referenciado por ref, o ref si no es una referencia
+func resolveSecret(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, fileSecretPrefix):
+		path := strings.TrimPrefix(ref, fileSecretPrefix)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		// Los archivos de secretos suelen terminar en salto de línea
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(ref, envSecretPrefix):
+		name := strings.TrimPrefix(ref, envSecretPrefix)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	default:
+		return ref, nil
+	}
+}
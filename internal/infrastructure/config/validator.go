@@ -1,14 +1,27 @@
 package config
 
 import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"net/url"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Validator valida la configuración cargada
-type Validator struct{}
+type Validator struct {
+	pairLister     PairLister // Opcional: fuente de pares para pair_validation.source=live
+	usedPairSource string
+	skippedPairs   []string // Pares de base_assets × quote_currencies que el exchange no lista
+}
 
 // NewValidator crea una nueva instancia del validador
 func NewValidator() *Validator {
@@ -30,11 +43,15 @@ func (v *Validator) Validate(config *Config) error {
 		return fmt.Errorf("cache config validation failed: %w", err)
 	}
 
+	if err := v.validateState(config.State, config.Cache.Redis); err != nil {
+		return fmt.Errorf("state config validation failed: %w", err)
+	}
+
 	if err := v.validateExchange(config.Exchange); err != nil {
 		return fmt.Errorf("exchange config validation failed: %w", err)
 	}
 
-	if err := v.validateRateLimit(config.RateLimit); err != nil {
+	if err := v.validateRateLimit(config.RateLimit, config.Auth); err != nil {
 		return fmt.Errorf("rate limit config validation failed: %w", err)
 	}
 
@@ -42,10 +59,62 @@ func (v *Validator) Validate(config *Config) error {
 		return fmt.Errorf("logging config validation failed: %w", err)
 	}
 
-	if err := v.validateBusiness(config.Business); err != nil {
+	if err := v.validateBusiness(&config.Business); err != nil {
 		return fmt.Errorf("business config validation failed: %w", err)
 	}
 
+	if err := v.validateLeader(config.Leader, config.Cache.Redis); err != nil {
+		return fmt.Errorf("leader election config validation failed: %w", err)
+	}
+
+	if err := v.validatePriceValidation(config.Validation); err != nil {
+		return fmt.Errorf("price validation config validation failed: %w", err)
+	}
+
+	if err := v.validateAnomalyDetection(config.Anomalies, config.Business.SupportedPairs); err != nil {
+		return fmt.Errorf("anomaly detection config validation failed: %w", err)
+	}
+
+	if err := v.validateTenancy(config.Tenancy, config.Auth, config.Business.SupportedPairs); err != nil {
+		return fmt.Errorf("tenancy config validation failed: %w", err)
+	}
+
+	if err := v.validateUsage(config.Usage, config.Auth); err != nil {
+		return fmt.Errorf("usage config validation failed: %w", err)
+	}
+
+	if err := v.validateAudit(config.Audit); err != nil {
+		return fmt.Errorf("audit config validation failed: %w", err)
+	}
+
+	if err := v.validateMaintenance(config.Maintenance); err != nil {
+		return fmt.Errorf("maintenance config validation failed: %w", err)
+	}
+
+	if err := v.validateWarmup(config.Warmup, config.Business.SupportedPairs); err != nil {
+		return fmt.Errorf("warmup config validation failed: %w", err)
+	}
+
+	if err := v.validateFeatureFlags(config.Features); err != nil {
+		return fmt.Errorf("feature flags config validation failed: %w", err)
+	}
+
+	if err := v.validateMetrics(config.Metrics); err != nil {
+		return fmt.Errorf("metrics config validation failed: %w", err)
+	}
+
+	if err := v.validateRuntime(config.Runtime); err != nil {
+		return fmt.Errorf("runtime config validation failed: %w", err)
+	}
+
+	if err := v.validateSLO(config.SLO); err != nil {
+		return fmt.Errorf("slo config validation failed: %w", err)
+	}
+
+	if err := v.validateTimeouts(config); err != nil {
+		return fmt.Errorf("timeouts config validation failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -55,6 +124,10 @@ func (v *Validator) validateServer(config ServerConfig) error {
 		return fmt.Errorf("invalid port: %d, must be between 1-65535", config.Port)
 	}
 
+	if err := v.validateListeners(config.Listeners); err != nil {
+		return err
+	}
+
 	if config.ShutdownTimeout <= 0 {
 		return fmt.Errorf("shutdown_timeout must be positive, got: %v", config.ShutdownTimeout)
 	}
@@ -63,6 +136,103 @@ func (v *Validator) validateServer(config ServerConfig) error {
 		return fmt.Errorf("shutdown_timeout too long: %v, max 5 minutes", config.ShutdownTimeout)
 	}
 
+	if config.DrainPeriod < 0 || config.DrainPeriod >= config.ShutdownTimeout {
+		return fmt.Errorf("drain_period must be between 0 and shutdown_timeout (%v), got: %v", config.ShutdownTimeout, config.DrainPeriod)
+	}
+
+	if config.RequestTimeout < 0 || config.RequestTimeout > 5*time.Minute {
+		return fmt.Errorf("request_timeout must be between 0 and 5 minutes, got: %v", config.RequestTimeout)
+	}
+
+	for route, timeout := range config.RouteTimeouts {
+		if !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("route_timeouts key must be an absolute path, got: %q", route)
+		}
+		if timeout <= 0 || timeout > 5*time.Minute {
+			return fmt.Errorf("route_timeouts[%s] must be between 0 and 5 minutes, got: %v", route, timeout)
+		}
+	}
+
+	if config.MaxBulkPairs < 0 || config.MaxBulkPairs > 1000 {
+		return fmt.Errorf("max_bulk_pairs must be between 0 and 1000, got: %d", config.MaxBulkPairs)
+	}
+
+	if config.InputValidation.Enabled {
+		// 0 deshabilita el límite; los headers nunca superan http.DefaultMaxHeaderBytes
+		if config.InputValidation.MaxBodyBytes < 0 || config.InputValidation.MaxBodyBytes > 64<<20 {
+			return fmt.Errorf("input_validation.max_body_bytes must be between 0 and 64MiB, got: %d", config.InputValidation.MaxBodyBytes)
+		}
+		if config.InputValidation.MaxHeaderBytes < 0 || config.InputValidation.MaxHeaderBytes > 1<<20 {
+			return fmt.Errorf("input_validation.max_header_bytes must be between 0 and 1MiB, got: %d", config.InputValidation.MaxHeaderBytes)
+		}
+	}
+
+	if config.ProblemTypeBaseURI != "" {
+		if _, err := url.Parse(config.ProblemTypeBaseURI); err != nil {
+			return fmt.Errorf("problem_type_base_uri must be a valid URI reference: %w", err)
+		}
+	}
+
+	if config.ResponseCache.Enabled {
+		if config.ResponseCache.TTL <= 0 || config.ResponseCache.TTL > 10*time.Second {
+			return fmt.Errorf("response_cache.ttl must be between 0 and 10s, got: %v", config.ResponseCache.TTL)
+		}
+		if config.ResponseCache.MaxEntries <= 0 {
+			return fmt.Errorf("response_cache.max_entries must be positive, got: %d", config.ResponseCache.MaxEntries)
+		}
+	}
+
+	if config.Streaming.BufferSize < 1 || config.Streaming.BufferSize > 100000 {
+		return fmt.Errorf("streaming.buffer_size must be between 1 and 100000, got: %d", config.Streaming.BufferSize)
+	}
+	if config.Streaming.WriteTimeout < time.Second || config.Streaming.WriteTimeout > 5*time.Minute {
+		return fmt.Errorf("streaming.write_timeout must be between 1s and 5 minutes, got: %v", config.Streaming.WriteTimeout)
+	}
+	validPolicies := []string{"coalesce", "disconnect"}
+	if !contains(validPolicies, config.Streaming.SlowClientPolicy) {
+		return fmt.Errorf("invalid streaming.slow_client_policy: %s, must be one of: %v", config.Streaming.SlowClientPolicy, validPolicies)
+	}
+	if config.Streaming.MaxUpdatesPerSecond < 0 || config.Streaming.MaxUpdatesPerSecond > 1000 {
+		return fmt.Errorf("streaming.max_updates_per_second must be between 0 and 1000, got: %v", config.Streaming.MaxUpdatesPerSecond)
+	}
+	if config.Streaming.MaxMessageBytes != 0 && (config.Streaming.MaxMessageBytes < 128 || config.Streaming.MaxMessageBytes > 1<<20) {
+		return fmt.Errorf("streaming.max_message_bytes must be 0 (default) or between 128 and 1048576, got: %d", config.Streaming.MaxMessageBytes)
+	}
+
+	return nil
+}
+
+// validateListeners valida la red y la dirección de cada listener, sin repetidos
+func (v *Validator) validateListeners(listeners []ListenerConfig) error {
+	seen := make(map[string]bool, len(listeners))
+	for i, listener := range listeners {
+		switch listener.Network {
+		case "", "tcp", "tcp4", "tcp6":
+			if listener.Mode != "" {
+				return fmt.Errorf("listeners[%d] mode only applies to unix sockets", i)
+			}
+			_, port, err := net.SplitHostPort(listener.Address)
+			if err != nil {
+				return fmt.Errorf("listeners[%d] address must be host:port, got: %q", i, listener.Address)
+			}
+			if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+				return fmt.Errorf("listeners[%d] port must be between 0-65535, got: %q", i, port)
+			}
+		case "unix":
+			if listener.Address == "" {
+				return fmt.Errorf("listeners[%d] address must be a socket path", i)
+			}
+			if _, err := listener.FileMode(); err != nil {
+				return fmt.Errorf("listeners[%d] %w", i, err)
+			}
+		default:
+			return fmt.Errorf("invalid listeners[%d] network: %s, must be one of: tcp, tcp4, tcp6, unix", i, listener.Network)
+		}
+		if seen[listener.Address] {
+			return fmt.Errorf("duplicate listener address: %s", listener.Address)
+		}
+		seen[listener.Address] = true
+	}
 	return nil
 }
 
@@ -73,11 +243,25 @@ func (v *Validator) validateCache(config CacheConfig) error {
 		return fmt.Errorf("invalid cache backend: %s, must be one of: %v", config.Backend, validBackends)
 	}
 
+	// Vacío equivale a json
+	validCodecs := []string{"json", "binary"}
+	if config.Codec != "" && !contains(validCodecs, config.Codec) {
+		return fmt.Errorf("invalid cache codec: %s, must be one of: %v", config.Codec, validCodecs)
+	}
+
 	// Validación mejorada de TTL para detectar casos edge
 	if err := v.validateTTL(config.TTL); err != nil {
 		return fmt.Errorf("cache TTL validation failed: %w", err)
 	}
 
+	// 0 usa el default
+	if config.RefreshFailureThreshold < 0 || config.RefreshFailureThreshold > 100 {
+		return fmt.Errorf("cache refresh_failure_threshold must be between 0 and 100, got: %d", config.RefreshFailureThreshold)
+	}
+	if config.RefreshMaxBackoff < 0 || config.RefreshMaxBackoff > time.Hour {
+		return fmt.Errorf("cache refresh_max_backoff must be between 0 and 1h, got: %v", config.RefreshMaxBackoff)
+	}
+
 	// Validar Redis config si se usa Redis
 	if config.Backend == "redis" {
 		if err := v.validateRedis(config.Redis); err != nil {
@@ -106,8 +290,39 @@ func (v *Validator) validateRedis(config RedisConfig) error {
 	return nil
 }
 
+// validateState valida la configuración del repositorio de estado
+func (v *Validator) validateState(config StateConfig, redisConfig RedisConfig) error {
+	// Backend vacío equivale a memory (compatibilidad con configs previas)
+	if config.Backend == "" {
+		return nil
+	}
+
+	validBackends := []string{"memory", "redis", "sql"}
+	if !contains(validBackends, config.Backend) {
+		return fmt.Errorf("invalid state backend: %s, must be one of: %v", config.Backend, validBackends)
+	}
+
+	switch strings.ToLower(config.Backend) {
+	case "redis":
+		return v.validateRedis(redisConfig)
+	case "sql":
+		validDrivers := []string{"sqlite", "sqlite3", "postgres", "pgx"}
+		if !contains(validDrivers, config.SQL.Driver) {
+			return fmt.Errorf("invalid state sql driver: %s, must be one of: %v", config.SQL.Driver, validDrivers)
+		}
+		if config.SQL.DSN == "" {
+			return fmt.Errorf("state sql dsn cannot be empty")
+		}
+	}
+
+	return nil
+}
+
 // validateExchange valida la configuración de exchanges
 func (v *Validator) validateExchange(config ExchangeConfig) error {
+	if err := v.validateProxy("exchange.proxy", config.Proxy); err != nil {
+		return err
+	}
 	return v.validateKraken(config.Kraken)
 }
 
@@ -122,6 +337,10 @@ func (v *Validator) validateKraken(config KrakenConfig) error {
 		return err
 	}
 
+	if err := v.validateEndpoints(config); err != nil {
+		return err
+	}
+
 	// Validar timeouts
 	if config.Timeout <= 0 {
 		return fmt.Errorf("kraken timeout must be positive, got: %v", config.Timeout)
@@ -144,11 +363,203 @@ func (v *Validator) validateKraken(config KrakenConfig) error {
 		return fmt.Errorf("kraken max_retries must be between 1-10, got: %d", config.MaxRetries)
 	}
 
+	// Validar staleness watcher (0 usa defaults)
+	if config.StalenessInterval < 0 || config.StalenessMaxAge < 0 {
+		return fmt.Errorf("kraken staleness_interval and staleness_max_age cannot be negative")
+	}
+
+	if config.StalenessInterval > 0 && config.StalenessMaxAge > 0 && config.StalenessInterval > config.StalenessMaxAge {
+		return fmt.Errorf("kraken staleness_interval (%v) should not exceed staleness_max_age (%v)", config.StalenessInterval, config.StalenessMaxAge)
+	}
+
+	// Validar canales de precios (0/vacío usa defaults)
+	if config.ChannelCapacity < 0 || config.ChannelCapacity > 10000 {
+		return fmt.Errorf("kraken channel_capacity must be between 0-10000, got: %d", config.ChannelCapacity)
+	}
+
+	validPolicies := []string{"", "drop_oldest", "drop_newest", "block"}
+	if !contains(validPolicies, config.ChannelOverflowPolicy) {
+		return fmt.Errorf("invalid kraken channel_overflow_policy: %s, must be one of: %v", config.ChannelOverflowPolicy, validPolicies[1:])
+	}
+
+	if config.ChannelBlockTimeout < 0 || config.ChannelDropWarnThreshold < 0 {
+		return fmt.Errorf("kraken channel_block_timeout and channel_drop_warn_threshold cannot be negative")
+	}
+
+	if config.DynamicPairs && config.PairsRefreshInterval > 0 && config.PairsRefreshInterval < time.Minute {
+		return fmt.Errorf("kraken pairs_refresh_interval too short: %v, minimum 1m", config.PairsRefreshInterval)
+	}
+
+	if config.DivergenceWindow < 0 || config.DivergenceWarnPercent < 0 {
+		return fmt.Errorf("kraken divergence settings cannot be negative (window: %v, warn_percent: %v)", config.DivergenceWindow, config.DivergenceWarnPercent)
+	}
+
+	if config.DivergenceWarnPercent > 100 {
+		return fmt.Errorf("kraken divergence_warn_percent must be between 0-100, got: %v", config.DivergenceWarnPercent)
+	}
+
+	if config.RestBatchSize < 0 || config.RestBatchSize > 100 {
+		return fmt.Errorf("kraken rest_batch_size must be between 0-100, got: %d", config.RestBatchSize)
+	}
+
+	if config.RestBatchConcurrency < 0 || config.RestBatchConcurrency > 10 {
+		return fmt.Errorf("kraken rest_batch_concurrency must be between 0-10, got: %d", config.RestBatchConcurrency)
+	}
+
+	if config.MaxPairsPerConnection < 0 || config.MaxPairsPerConnection > 1000 {
+		return fmt.Errorf("kraken max_pairs_per_connection must be between 0-1000, got: %d", config.MaxPairsPerConnection)
+	}
+
+	// Validar deduplicación: la ventana debe renovar el precio antes de que expire o se considere vencido
+	if config.DedupWindow < 0 {
+		return fmt.Errorf("kraken dedup_window cannot be negative, got: %v", config.DedupWindow)
+	}
+
+	if config.DedupWindow > 0 && config.PriceCacheTTL > 0 && config.DedupWindow >= config.PriceCacheTTL {
+		return fmt.Errorf("kraken dedup_window (%v) should be less than price_cache_ttl (%v)", config.DedupWindow, config.PriceCacheTTL)
+	}
+
+	if config.DedupWindow > 0 && config.StalenessMaxAge > 0 && config.DedupWindow >= config.StalenessMaxAge {
+		return fmt.Errorf("kraken dedup_window (%v) should be less than staleness_max_age (%v)", config.DedupWindow, config.StalenessMaxAge)
+	}
+
+	if config.MaxFrameBytes != 0 && (config.MaxFrameBytes < 4<<10 || config.MaxFrameBytes > 64<<20) {
+		return fmt.Errorf("kraken max_frame_bytes must be 0 (default) or between 4096 and 67108864, got: %d", config.MaxFrameBytes)
+	}
+
+	if config.RetryBudget.MaxTokens < 0 || config.RetryBudget.MaxTokens > 1000 {
+		return fmt.Errorf("kraken retry_budget.max_tokens must be between 0 (default) and 1000, got: %v", config.RetryBudget.MaxTokens)
+	}
+
+	if config.RetryBudget.TokenRatio < 0 || config.RetryBudget.TokenRatio > 1 {
+		return fmt.Errorf("kraken retry_budget.token_ratio must be between 0 (default) and 1, got: %v", config.RetryBudget.TokenRatio)
+	}
+
+	if config.MaxConnectionAge != 0 && (config.MaxConnectionAge < time.Minute || config.MaxConnectionAge > 7*24*time.Hour) {
+		return fmt.Errorf("kraken max_connection_age must be 0 (disabled) or between 1m and 168h, got: %v", config.MaxConnectionAge)
+	}
+
+	if config.SourceSwitchCooldown < 0 || config.SourceSwitchCooldown > time.Hour {
+		return fmt.Errorf("kraken source_switch_cooldown must be between 0 and 1h, got: %v", config.SourceSwitchCooldown)
+	}
+
+	if err := v.validateEgress(config.Egress); err != nil {
+		return err
+	}
+
+	if err := v.validateKrakenTLS(config.TLS); err != nil {
+		return err
+	}
+
+	return v.validateProxy("exchange.kraken.proxy", config.Proxy)
+}
+
+// validateProxy valida la URL del proxy de key
+func (v *Validator) validateProxy(key string, config ProxyConfig) error {
+	if config.URL == "" {
+		return nil
+	}
+	proxyURL, err := url.Parse(config.URL)
+	if err != nil {
+		// El error de url.Parse incluye la URL, que puede tener la contraseña
+		return fmt.Errorf("invalid %s url: must be scheme://[user:password@]host:port", key)
+	}
+	if proxyURL.Scheme != "http" && proxyURL.Scheme != "socks5" {
+		return fmt.Errorf("invalid %s url scheme %q: must be http or socks5", key, proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return fmt.Errorf("%s url must include the proxy host", key)
+	}
 	return nil
 }
 
+// validateKrakenTLS valida el CA bundle, la versión mínima y los pines SPKI
+func (v *Validator) validateKrakenTLS(config KrakenTLSConfig) error {
+	if config.CAFile != "" {
+		bundle, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read kraken tls ca_file: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("kraken tls ca_file %s contains no PEM certificates", config.CAFile)
+		}
+	}
+
+	validVersions := []string{"", "1.2", "1.3"}
+	if !contains(validVersions, config.MinVersion) {
+		return fmt.Errorf("invalid kraken tls min_version: %s, must be one of: %v", config.MinVersion, validVersions[1:])
+	}
+
+	for _, pin := range config.PinnedSPKI {
+		hash, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(hash) != sha256.Size {
+			return fmt.Errorf("invalid kraken tls pinned_spki %q: must be a base64 SHA-256 hash", pin)
+		}
+	}
+
+	return nil
+}
+
+// validateEgress valida la allowlist y los pines de las conexiones salientes
+func (v *Validator) validateEgress(config EgressConfig) error {
+	allowed := make([]*net.IPNet, 0, len(config.AllowedCIDRs))
+	for _, entry := range config.AllowedCIDRs {
+		if ip := net.ParseIP(entry); ip != nil {
+			allowed = append(allowed, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(entry)
+		if err != nil {
+			return fmt.Errorf("invalid kraken egress allowed_cidrs entry %q: must be an IP or CIDR", entry)
+		}
+		allowed = append(allowed, cidr)
+	}
+
+	hosts := make(map[string]bool, len(config.Pins))
+	for _, pin := range config.Pins {
+		host := strings.ToLower(pin.Host)
+		if host == "" || net.ParseIP(host) != nil {
+			return fmt.Errorf("kraken egress pin host must be a hostname, got: %q", pin.Host)
+		}
+		if hosts[host] {
+			return fmt.Errorf("duplicate kraken egress pin for host %s", pin.Host)
+		}
+		hosts[host] = true
+		if len(pin.IPs) == 0 {
+			return fmt.Errorf("kraken egress pin for %s must list at least one IP", pin.Host)
+		}
+		for _, entry := range pin.IPs {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return fmt.Errorf("invalid kraken egress pin IP %q for %s", entry, pin.Host)
+			}
+			if len(allowed) > 0 && !containsIP(allowed, ip) {
+				return fmt.Errorf("kraken egress pin IP %s for %s is outside allowed_cidrs", entry, pin.Host)
+			}
+		}
+	}
+
+	if config.ResolveInterval < 0 {
+		return fmt.Errorf("kraken egress resolve_interval cannot be negative, got: %v", config.ResolveInterval)
+	}
+	if config.PinDNS && config.ResolveInterval > 0 && config.ResolveInterval < 10*time.Second {
+		return fmt.Errorf("kraken egress resolve_interval too short: %v, minimum 10s", config.ResolveInterval)
+	}
+
+	return nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // validateRateLimit valida la configuración de rate limiting
-func (v *Validator) validateRateLimit(config RateLimitConfig) error {
+func (v *Validator) validateRateLimit(config RateLimitConfig, auth AuthConfig) error {
 	if config.Enabled {
 		if config.Capacity <= 0 {
 			return fmt.Errorf("rate_limit capacity must be positive when enabled, got: %d", config.Capacity)
@@ -165,6 +576,41 @@ func (v *Validator) validateRateLimit(config RateLimitConfig) error {
 		if config.RefillRate > 1000 {
 			return fmt.Errorf("rate_limit refill_rate too high: %d, max 1000", config.RefillRate)
 		}
+
+		if err := v.validateClientID(config.ClientID); err != nil {
+			return err
+		}
+
+		// api_key sólo puede verificar keys que auth lee de su header
+		if config.ClientID.Strategy == "api_key" && auth.Enabled && config.ClientID.Header != "" &&
+			!strings.EqualFold(config.ClientID.Header, auth.HeaderName) {
+			return fmt.Errorf("rate_limit client_id header %q must match auth.header_name %q with the api_key strategy",
+				config.ClientID.Header, auth.HeaderName)
+		}
+	}
+
+	return nil
+}
+
+// validateClientID valida la estrategia de identificación de clientes y los proxies confiables
+func (v *Validator) validateClientID(config ClientIDConfig) error {
+	switch config.Strategy {
+	case "remote_addr", "forwarded_for", "api_key":
+	case "header":
+		if config.Header == "" {
+			return fmt.Errorf("rate_limit client_id header is required with the header strategy")
+		}
+	default:
+		return fmt.Errorf("invalid rate_limit client_id strategy: %s (valid: remote_addr, forwarded_for, api_key, header)", config.Strategy)
+	}
+
+	for _, proxy := range config.TrustedProxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return fmt.Errorf("invalid rate_limit trusted proxy %q: must be an IP or CIDR", proxy)
+		}
 	}
 
 	return nil
@@ -182,17 +628,33 @@ func (v *Validator) validateLogging(config LoggingConfig) error {
 		return fmt.Errorf("invalid log format: %s, must be one of: %v", config.Format, validFormats)
 	}
 
+	if config.SlowRequestThreshold < 0 || config.SlowRequestThreshold > time.Minute {
+		return fmt.Errorf("logging slow_request_threshold must be between 0 and 1m, got %v", config.SlowRequestThreshold)
+	}
+
 	return nil
 }
 
-// validateBusiness valida la configuración de negocio
-func (v *Validator) validateBusiness(config BusinessConfig) error {
-	if len(config.SupportedPairs) == 0 {
+// validateBusiness valida la configuración de negocio y agrega a SupportedPairs
+// los pares de base_assets × quote_currencies
+func (v *Validator) validateBusiness(config *BusinessConfig) error {
+	if len(config.SupportedPairs) == 0 && len(config.BaseAssets) == 0 && len(config.QuoteCurrencies) == 0 {
 		return fmt.Errorf("supported_pairs cannot be empty")
 	}
 
-	// Validación robusta de pares con lista de pares conocidos
-	if err := v.validateTradingPairs(config.SupportedPairs); err != nil {
+	if config.PairValidation.Source == PairSourceAllowlist && config.PairValidation.AllowlistFile == "" {
+		return fmt.Errorf("pair_validation allowlist_file cannot be empty when source is allowlist")
+	}
+
+	// Validación robusta de pares con lista de pares conocidos (estática, allowlist o live)
+	knownPairs, err := v.knownPairsFor(config.PairValidation)
+	if err != nil {
+		return fmt.Errorf("trading pairs validation failed: %w", err)
+	}
+	if err := v.expandPairs(config, knownPairs); err != nil {
+		return err
+	}
+	if err := v.validateTradingPairsAgainst(config.SupportedPairs, knownPairs); err != nil {
 		return fmt.Errorf("trading pairs validation failed: %w", err)
 	}
 
@@ -200,9 +662,503 @@ func (v *Validator) validateBusiness(config BusinessConfig) error {
 		return fmt.Errorf("cache_prefix cannot be empty")
 	}
 
+	if err := v.validatePriceFormat(config.PriceFormat); err != nil {
+		return err
+	}
+
+	if err := v.validatePairPruning(config.PairPruning); err != nil {
+		return err
+	}
+
+	return v.validateOnDemandPairs(config.OnDemandPairs)
+}
+
+// validateOnDemandPairs valida el máximo de pares admitidos bajo demanda
+func (v *Validator) validateOnDemandPairs(config OnDemandPairsConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	// Cada par admitido es una suscripción más y entra en el refresco periódico
+	if config.MaxPairs < 1 || config.MaxPairs > 1000 {
+		return fmt.Errorf("on_demand_pairs max_pairs must be between 1-1000, got: %d", config.MaxPairs)
+	}
+
+	return nil
+}
+
+// validatePairPruning valida la ventana de inactividad de la poda de pares
+func (v *Validator) validatePairPruning(config PairPruningConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	// Ventanas cortas desuscriben y resuscriben pares con tráfico esporádico
+	if config.IdleAfter < time.Minute {
+		return fmt.Errorf("pair_pruning idle_after must be at least 1m when enabled, got: %v", config.IdleAfter)
+	}
+
+	return nil
+}
+
+// validatePriceFormat valida las reglas de decimales por moneda cotizada
+func (v *Validator) validatePriceFormat(config PriceFormatConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	switch config.Mode {
+	case "round", "truncate":
+	default:
+		return fmt.Errorf("price_format mode must be round or truncate, got: %q", config.Mode)
+	}
+
+	for quote, decimals := range config.QuoteDecimals {
+		// Las claves deben ser el código canónico (BTC, no XBT): se comparan con la cotización normalizada del par
+		canonical, err := entities.NormalizePair("X/" + quote)
+		if err != nil || !strings.EqualFold(canonical, "X/"+quote) {
+			return fmt.Errorf("price_format quote_decimals key must be a canonical currency code, got: %q", quote)
+		}
+		if decimals < 0 || decimals > 12 {
+			return fmt.Errorf("price_format quote_decimals[%s] must be between 0-12, got: %d", quote, decimals)
+		}
+	}
+
+	return nil
+}
+
+// validatePriceValidation valida los límites de sanidad de precios (0 usa defaults)
+func (v *Validator) validatePriceValidation(config PriceValidationConfig) error {
+	if err := v.validateDeviationGuard(config.DeviationGuard); err != nil {
+		return err
+	}
+
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.MaxJumpPercent < 0 || config.MaxJumpPercent > 1000 {
+		return fmt.Errorf("max_jump_percent must be between 0-1000, got: %v", config.MaxJumpPercent)
+	}
+
+	if config.MaxFutureSkew < 0 {
+		return fmt.Errorf("max_future_skew cannot be negative, got: %v", config.MaxFutureSkew)
+	}
+
+	if config.QuarantineSize < 0 {
+		return fmt.Errorf("quarantine_size cannot be negative, got: %d", config.QuarantineSize)
+	}
+
+	return nil
+}
+
+// validateDeviationGuard valida el control de desvío contra la fuente de referencia
+func (v *Validator) validateDeviationGuard(config DeviationGuardConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.Interval < time.Second || config.Interval > time.Hour {
+		return fmt.Errorf("deviation_guard interval must be between 1s-1h, got: %v", config.Interval)
+	}
+
+	if config.MaxDeviationPercent <= 0 || config.MaxDeviationPercent > 100 {
+		return fmt.Errorf("deviation_guard max_deviation_percent must be between 0-100 (exclusive of 0), got: %v", config.MaxDeviationPercent)
+	}
+
+	switch config.Action {
+	case "flag", "refuse":
+	default:
+		return fmt.Errorf("deviation_guard action must be flag or refuse, got: %q", config.Action)
+	}
+
+	return nil
+}
+
+// validateAnomalyDetection valida los umbrales de flatline y spike
+func (v *Validator) validateAnomalyDetection(config AnomalyDetectionConfig, supportedPairs []string) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.CheckInterval < time.Second || config.CheckInterval > time.Hour {
+		return fmt.Errorf("check_interval must be between 1s-1h, got: %v", config.CheckInterval)
+	}
+
+	if config.FlatlineAfter < config.CheckInterval || config.FlatlineAfter > 24*time.Hour {
+		return fmt.Errorf("flatline_after must be between check_interval (%v) and 24h, got: %v", config.CheckInterval, config.FlatlineAfter)
+	}
+
+	if config.SpikeZScore < 2 || config.SpikeZScore > 100 {
+		return fmt.Errorf("spike_z_score must be between 2-100, got: %v", config.SpikeZScore)
+	}
+
+	if config.SpikeWindow < 10 || config.SpikeWindow > 10000 {
+		return fmt.Errorf("spike_window must be between 10-10000, got: %d", config.SpikeWindow)
+	}
+
+	if config.SpikeMinSamples < 2 || config.SpikeMinSamples > config.SpikeWindow {
+		return fmt.Errorf("spike_min_samples must be between 2 and spike_window (%d), got: %d", config.SpikeWindow, config.SpikeMinSamples)
+	}
+
+	supported := make(map[string]bool, len(supportedPairs))
+	for _, pair := range supportedPairs {
+		supported[strings.ToUpper(pair)] = true
+	}
+	for _, pair := range config.FlatlinePairs {
+		if !supported[strings.ToUpper(strings.TrimSpace(pair))] {
+			return fmt.Errorf("flatline pair %s is not in supported_pairs", pair)
+		}
+	}
+
+	return nil
+}
+
+// validateTenancy valida los tenants: nombres y API keys únicos, pares
+// soportados y rate limits completos
+func (v *Validator) validateTenancy(config TenancyConfig, auth AuthConfig, supportedPairs []string) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if !auth.Enabled {
+		return fmt.Errorf("tenancy requires auth.enabled: tenants are identified by their API keys")
+	}
+
+	if config.AdminAPIEnabled && (config.RefreshInterval < time.Second || config.RefreshInterval > 10*time.Minute) {
+		return fmt.Errorf("tenancy refresh_interval must be between 1s-10m, got: %v", config.RefreshInterval)
+	}
+
+	supported := make(map[string]bool, len(supportedPairs))
+	for _, pair := range supportedPairs {
+		supported[strings.ToUpper(pair)] = true
+	}
+	names := make(map[string]bool, len(config.Tenants))
+	keys := map[string]string{auth.APIKey: "the operator key (auth.api_key)"}
+	for i, tenant := range config.Tenants {
+		if strings.TrimSpace(tenant.Name) == "" {
+			return fmt.Errorf("tenant %d has no name", i)
+		}
+		if names[tenant.Name] {
+			return fmt.Errorf("tenant %s is declared twice", tenant.Name)
+		}
+		names[tenant.Name] = true
+
+		if len(tenant.APIKeys) == 0 {
+			return fmt.Errorf("tenant %s has no api_keys", tenant.Name)
+		}
+		for _, key := range tenant.APIKeys {
+			if key == "" {
+				return fmt.Errorf("tenant %s has an empty api key", tenant.Name)
+			}
+			if owner, taken := keys[key]; taken {
+				return fmt.Errorf("tenant %s reuses an api key of %s", tenant.Name, owner)
+			}
+			keys[key] = "tenant " + tenant.Name
+		}
+
+		for _, pair := range tenant.Pairs {
+			if !supported[strings.ToUpper(strings.TrimSpace(pair))] {
+				return fmt.Errorf("tenant %s pair %s is not in supported_pairs", tenant.Name, pair)
+			}
+		}
+
+		limit := tenant.RateLimit
+		if limit.Capacity < 0 || limit.RefillRate < 0 || (limit.Capacity > 0) != (limit.RefillRate > 0) {
+			return fmt.Errorf("tenant %s rate_limit capacity and refill_rate must both be positive or both 0, got: %d/%d", tenant.Name, limit.Capacity, limit.RefillRate)
+		}
+	}
+
+	return nil
+}
+
+// validateUsage valida la contabilidad de uso por API key
+func (v *Validator) validateUsage(config UsageConfig, auth AuthConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if !auth.Enabled {
+		return fmt.Errorf("usage accounting requires auth.enabled: usage is counted per API key")
+	}
+
+	if config.FlushInterval < time.Second || config.FlushInterval > time.Hour {
+		return fmt.Errorf("usage flush_interval must be between 1s-1h, got: %v", config.FlushInterval)
+	}
+
+	if config.RetentionDays < 1 || config.RetentionDays > 3660 {
+		return fmt.Errorf("usage retention_days must be between 1-3660, got: %d", config.RetentionDays)
+	}
+
+	return nil
+}
+
+// validateAudit valida el registro persistente de fallbacks y reconexiones
+func (v *Validator) validateAudit(config AuditConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.FlushInterval < time.Second || config.FlushInterval > time.Hour {
+		return fmt.Errorf("audit flush_interval must be between 1s-1h, got: %v", config.FlushInterval)
+	}
+
+	if config.RetentionDays < 1 || config.RetentionDays > 3660 {
+		return fmt.Errorf("audit retention_days must be between 1-3660, got: %d", config.RetentionDays)
+	}
+
+	return nil
+}
+
+// validateMaintenance valida el calendario de ventanas de mantenimiento
+func (v *Validator) validateMaintenance(config MaintenanceConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.RefreshInterval < time.Second || config.RefreshInterval > time.Hour {
+		return fmt.Errorf("maintenance refresh_interval must be between 1s-1h, got: %v", config.RefreshInterval)
+	}
+
+	names := make(map[string]bool, len(config.Windows))
+	for i, window := range config.Windows {
+		if strings.TrimSpace(window.Name) == "" {
+			return fmt.Errorf("maintenance window %d has no name", i)
+		}
+		if names[window.Name] {
+			return fmt.Errorf("maintenance window %s is declared twice", window.Name)
+		}
+		names[window.Name] = true
+
+		if _, err := time.Parse(time.RFC3339, window.Start); err != nil {
+			return fmt.Errorf("maintenance window %s start must be RFC3339, got: %q", window.Name, window.Start)
+		}
+
+		maxDuration := 7 * 24 * time.Hour
+		switch window.Repeat {
+		case "":
+		case "daily":
+			maxDuration = 24 * time.Hour
+		case "weekly":
+		default:
+			return fmt.Errorf("maintenance window %s repeat must be empty, daily or weekly, got: %q", window.Name, window.Repeat)
+		}
+		if window.Duration <= 0 || window.Duration >= maxDuration {
+			return fmt.Errorf("maintenance window %s duration must be between 0-%v (exclusive), got: %v", window.Name, maxDuration, window.Duration)
+		}
+	}
+
+	return nil
+}
+
+// validateWarmup valida la configuración del warm-up de caché
+func (v *Validator) validateWarmup(config WarmupConfig, supportedPairs []string) error {
+	if config.Timeout < time.Second || config.Timeout > 5*time.Minute {
+		return fmt.Errorf("warmup timeout must be between 1s-5m, got: %v", config.Timeout)
+	}
+
+	if config.BatchSize < 1 || config.BatchSize > 50 {
+		return fmt.Errorf("warmup batch_size must be between 1-50, got: %d", config.BatchSize)
+	}
+
+	if config.Concurrency < 1 || config.Concurrency > 10 {
+		return fmt.Errorf("warmup concurrency must be between 1-10, got: %d", config.Concurrency)
+	}
+
+	supported := make(map[string]bool, len(supportedPairs))
+	for _, pair := range supportedPairs {
+		supported[strings.ToUpper(pair)] = true
+	}
+	for _, pair := range config.RequiredPairs {
+		if !supported[strings.ToUpper(strings.TrimSpace(pair))] {
+			return fmt.Errorf("warmup required pair %s is not in supported_pairs", pair)
+		}
+	}
+
+	return nil
+}
+
+// featureFlagName restringe los nombres de flags (viper normaliza las claves a minúsculas)
+var featureFlagName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateFeatureFlags valida los nombres de flags y el intervalo de sincronización
+func (v *Validator) validateFeatureFlags(config FeatureFlagsConfig) error {
+	for name := range config.Flags {
+		if !featureFlagName.MatchString(name) {
+			return fmt.Errorf("invalid feature flag name %q: use lowercase letters, digits and underscores", name)
+		}
+	}
+
+	if config.OverridesEnabled && (config.RefreshInterval < time.Second || config.RefreshInterval > 10*time.Minute) {
+		return fmt.Errorf("feature flags refresh_interval must be between 1s-10m, got: %v", config.RefreshInterval)
+	}
+
+	return nil
+}
+
+// validateMetrics valida que los grupos deshabilitados existan
+func (v *Validator) validateMetrics(config MetricsConfig) error {
+	for _, group := range config.DisabledGroups {
+		if _, err := metrics.ParseGroup(group); err != nil {
+			return fmt.Errorf("invalid disabled_groups entry: %w (valid: %v)", err, metrics.Groups())
+		}
+	}
 	return nil
 }
 
+// validateRuntime valida los límites de GOMAXPROCS y GOMEMLIMIT
+func (v *Validator) validateRuntime(config RuntimeConfig) error {
+	if config.MaxProcs < 0 {
+		return fmt.Errorf("max_procs cannot be negative: %d", config.MaxProcs)
+	}
+	if config.MemoryLimit < 0 {
+		return fmt.Errorf("memory_limit cannot be negative: %d", config.MemoryLimit)
+	}
+	if config.MemoryLimit > 0 && config.MemoryLimit < 16<<20 {
+		return fmt.Errorf("memory_limit too low: %d bytes (minimum 16MiB)", config.MemoryLimit)
+	}
+	if config.MemoryLimitRatio < 0 || config.MemoryLimitRatio > 1 {
+		return fmt.Errorf("memory_limit_ratio must be between 0 and 1, got %v", config.MemoryLimitRatio)
+	}
+	return nil
+}
+
+// validateSLO valida la ventana de evaluación y cada objetivo
+func (v *Validator) validateSLO(config SLOConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.EvaluationInterval < time.Second || config.EvaluationInterval > 10*time.Minute {
+		return fmt.Errorf("slo evaluation_interval must be between 1s-10m, got: %v", config.EvaluationInterval)
+	}
+	if config.Window < config.EvaluationInterval || config.Window > 30*24*time.Hour {
+		return fmt.Errorf("slo window must be between evaluation_interval (%v) and 30 days, got: %v", config.EvaluationInterval, config.Window)
+	}
+
+	names := make(map[string]bool, len(config.Objectives))
+	for _, objective := range config.Objectives {
+		if !featureFlagName.MatchString(objective.Name) {
+			return fmt.Errorf("invalid slo name %q: use lowercase letters, digits and underscores", objective.Name)
+		}
+		if names[objective.Name] {
+			return fmt.Errorf("duplicate slo name: %s", objective.Name)
+		}
+		names[objective.Name] = true
+
+		if objective.Target <= 0 || objective.Target >= 1 {
+			return fmt.Errorf("slo %s target must be between 0 and 1 (exclusive), got: %v", objective.Name, objective.Target)
+		}
+		if objective.Latency < 0 || objective.MaxAge < 0 {
+			return fmt.Errorf("slo %s latency and max_age cannot be negative", objective.Name)
+		}
+		if objective.Latency == 0 && objective.MaxAge == 0 {
+			return fmt.Errorf("slo %s must set latency, max_age or both", objective.Name)
+		}
+		if objective.Latency > 0 && !strings.HasPrefix(objective.Path, "/") {
+			return fmt.Errorf("slo %s path must start with /, got: %q", objective.Name, objective.Path)
+		}
+	}
+
+	return nil
+}
+
+// validateTimeouts valida los rangos de la sección timeouts y la coherencia de los
+// timeouts de todas las secciones entre sí: cada intento debe caber en el deadline
+// que lo contiene. Los valores 0 se comparan con su default.
+func (v *Validator) validateTimeouts(config *Config) error {
+	timeouts := config.Timeouts
+	named := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"http_read", timeouts.HTTPRead},
+		{"http_write", timeouts.HTTPWrite},
+		{"http_idle", timeouts.HTTPIdle},
+		{"cache_refresh", timeouts.CacheRefresh},
+		{"websocket.ping_interval", timeouts.WebSocket.PingInterval},
+		{"websocket.pong_wait", timeouts.WebSocket.PongWait},
+		{"websocket.write_wait", timeouts.WebSocket.WriteWait},
+		{"websocket.quiet_timeout", timeouts.WebSocket.QuietTimeout},
+	}
+	for _, timeout := range named {
+		if timeout.value < 0 || timeout.value > 10*time.Minute {
+			return fmt.Errorf("%s must be between 0 and 10 minutes, got: %v", timeout.name, timeout.value)
+		}
+	}
+
+	defaults := GetDefaultConfig().Timeouts
+	httpWrite := orDefault(timeouts.HTTPWrite, defaults.HTTPWrite)
+	pingInterval := orDefault(timeouts.WebSocket.PingInterval, defaults.WebSocket.PingInterval)
+	pongWait := orDefault(timeouts.WebSocket.PongWait, defaults.WebSocket.PongWait)
+	writeWait := orDefault(timeouts.WebSocket.WriteWait, defaults.WebSocket.WriteWait)
+	quietTimeout := orDefault(timeouts.WebSocket.QuietTimeout, defaults.WebSocket.QuietTimeout)
+
+	// Un handler con un deadline mayor que http_write termina después de que el
+	// servidor cortó la respuesta
+	if config.Server.RequestTimeout > httpWrite {
+		return fmt.Errorf("server request_timeout (%v) must not exceed http_write (%v)", config.Server.RequestTimeout, httpWrite)
+	}
+	for route, timeout := range config.Server.RouteTimeouts {
+		if timeout > httpWrite {
+			return fmt.Errorf("server route_timeouts[%s] (%v) must not exceed http_write (%v)", route, timeout, httpWrite)
+		}
+	}
+
+	kraken := config.Exchange.Kraken
+	if config.Server.RequestTimeout > 0 && kraken.RequestTimeout > config.Server.RequestTimeout {
+		return fmt.Errorf("kraken request_timeout (%v) must not exceed server request_timeout (%v)", kraken.RequestTimeout, config.Server.RequestTimeout)
+	}
+	if cacheRefresh := orDefault(timeouts.CacheRefresh, defaults.CacheRefresh); kraken.RequestTimeout >= cacheRefresh {
+		return fmt.Errorf("kraken request_timeout (%v) should be less than cache_refresh (%v)", kraken.RequestTimeout, cacheRefresh)
+	}
+
+	// Sin un ping antes de pong_wait la conexión se da por muerta aunque esté sana
+	if pingInterval >= pongWait {
+		return fmt.Errorf("websocket.ping_interval (%v) should be less than websocket.pong_wait (%v)", pingInterval, pongWait)
+	}
+	if writeWait >= pingInterval {
+		return fmt.Errorf("websocket.write_wait (%v) should be less than websocket.ping_interval (%v)", writeWait, pingInterval)
+	}
+	// Kraken envía un heartbeat por segundo: con menos margen se reconectaría una
+	// conexión sana, y por encima de pong_wait el read deadline llega antes
+	if quietTimeout < 2*time.Second || quietTimeout >= pongWait {
+		return fmt.Errorf("websocket.quiet_timeout (%v) must be at least 2s and less than websocket.pong_wait (%v)", quietTimeout, pongWait)
+	}
+
+	return nil
+}
+
+// orDefault retorna value, o def cuando value es 0
+func orDefault(value, def time.Duration) time.Duration {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// validateLeader valida la configuración de elección de líder
+func (v *Validator) validateLeader(config LeaderConfig, redisConfig RedisConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	validBackends := []string{"redis"}
+	if !contains(validBackends, config.Backend) {
+		return fmt.Errorf("invalid leader election backend: %s, must be one of: %v", config.Backend, validBackends)
+	}
+
+	if config.LeaseTTL < time.Second {
+		return fmt.Errorf("leader election lease_ttl too short: %v, minimum 1s", config.LeaseTTL)
+	}
+
+	if config.RenewInterval <= 0 || config.RenewInterval >= config.LeaseTTL {
+		return fmt.Errorf("leader election renew_interval (%v) must be positive and less than lease_ttl (%v)", config.RenewInterval, config.LeaseTTL)
+	}
+
+	return v.validateRedis(redisConfig)
+}
+
 // validateURL valida que una URL sea válida para HTTP/HTTPS
 func (v *Validator) validateURL(rawURL, fieldName string) error {
 	if rawURL == "" {
@@ -225,6 +1181,24 @@ func (v *Validator) validateURL(rawURL, fieldName string) error {
 	return nil
 }
 
+// validateEndpoints valida las URLs alternativas de Kraken y su cooldown
+func (v *Validator) validateEndpoints(config KrakenConfig) error {
+	for i, restURL := range config.RestURLs {
+		if err := v.validateURL(restURL, fmt.Sprintf("kraken rest_urls[%d]", i)); err != nil {
+			return err
+		}
+	}
+	for i, wsURL := range config.WebSocketURLs {
+		if err := v.validateWebSocketURL(wsURL, fmt.Sprintf("kraken websocket_urls[%d]", i)); err != nil {
+			return err
+		}
+	}
+	if config.EndpointCooldown < 0 || config.EndpointCooldown > 10*time.Minute {
+		return fmt.Errorf("kraken endpoint_cooldown must be between 0 and 10m, got: %v", config.EndpointCooldown)
+	}
+	return nil
+}
+
 // validateWebSocketURL valida que una URL sea válida para WebSocket
 func (v *Validator) validateWebSocketURL(rawURL, fieldName string) error {
 	if rawURL == "" {
@@ -287,27 +1261,23 @@ func (v *Validator) validateTTL(ttl time.Duration) error {
 
 // validateTradingPairs valida pares contra lista conocida de Kraken
 func (v *Validator) validateTradingPairs(pairs []string) error {
+	return v.validateTradingPairsAgainst(pairs, v.getKnownKrakenPairs())
+}
+
+// validateTradingPairsAgainst valida pares contra el conjunto knownPairs
+func (v *Validator) validateTradingPairsAgainst(pairs []string, knownPairs map[string]bool) error {
 	if len(pairs) == 0 {
 		return fmt.Errorf("trading pairs list cannot be empty")
 	}
 
-	// Lista de pares conocidos en Kraken (actualizada frecuentemente)
-	knownPairs := v.getKnownKrakenPairs()
 	unknownPairs := make([]string, 0)
 	invalidFormatPairs := make([]string, 0)
 
-	for _, pair := range pairs {
-		pair = strings.TrimSpace(strings.ToUpper(pair))
-
-		// Validar formato básico
-		if !strings.Contains(pair, "/") {
-			invalidFormatPairs = append(invalidFormatPairs, pair)
-			continue
-		}
-
-		parts := strings.Split(pair, "/")
-		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-			invalidFormatPairs = append(invalidFormatPairs, pair)
+	for _, raw := range pairs {
+		// Validar formato básico, aceptando alias (XBTUSD, BTC-USD, btc_usd)
+		pair, err := entities.NormalizePair(raw)
+		if err != nil {
+			invalidFormatPairs = append(invalidFormatPairs, strings.TrimSpace(strings.ToUpper(raw)))
 			continue
 		}
 
@@ -338,8 +1308,12 @@ func (v *Validator) getKnownKrakenPairs() map[string]bool {
 		"BTC/EUR":  true,
 		"BTC/CHF":  true,
 		"BTC/GBP":  true,
+		"BTC/JPY":  true,
 		"ETH/USD":  true,
 		"ETH/EUR":  true,
+		"ETH/CHF":  true,
+		"ETH/GBP":  true,
+		"ETH/JPY":  true,
 		"ETH/BTC":  true,
 		"LTC/USD":  true,
 		"LTC/EUR":  true,
@@ -362,7 +1336,7 @@ func (v *Validator) getKnownKrakenPairs() map[string]bool {
 
 // isKnownPair verifica si un par está en la lista conocida
 func (v *Validator) isKnownPair(pair string, knownPairs map[string]bool) bool {
-	return knownPairs[strings.ToUpper(pair)]
+	return knownPairs[entities.CanonicalPair(pair)]
 }
 
 // getSampleKnownPairs retorna muestra de pares para mensajes de error
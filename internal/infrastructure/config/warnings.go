@@ -0,0 +1,26 @@
+package config
+
+// Warnings retorna las combinaciones válidas pero probablemente no deseadas de
+// config; no impiden arrancar y se incluyen en el informe de arranque
+func Warnings(config *Config) []string {
+	var warnings []string
+	if config.Development.MockMode || config.Development.DevMode {
+		warnings = append(warnings, "development mode is on: prices come from the mock exchange, not Kraken")
+	}
+	if !config.Auth.Enabled {
+		warnings = append(warnings, "auth is disabled: /api/v1/admin endpoints accept unauthenticated requests")
+	}
+	if !config.RateLimit.Enabled {
+		warnings = append(warnings, "rate limiting is disabled")
+	}
+	if config.Server.RequestTimeout == 0 {
+		warnings = append(warnings, "server.request_timeout is 0: requests have no deadline")
+	}
+	if config.Leader.Enabled && config.Cache.Backend == "memory" {
+		warnings = append(warnings, "leader election with the memory cache: only the leader's cache is refreshed automatically")
+	}
+	if config.State.Backend == "memory" {
+		warnings = append(warnings, "state backend is memory: pairs, alerts and API keys changed at runtime are lost on restart")
+	}
+	return warnings
+}
@@ -0,0 +1,181 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// SourceWebSocket identifica precios recibidos por el stream WebSocket
+	SourceWebSocket = entities.PriceSourceWebSocket
+	// SourceREST identifica precios obtenidos vía REST
+	SourceREST = entities.PriceSourceREST
+
+	// DefaultDivergenceWindow es la ventana en la que dos observaciones se consideran comparables
+	DefaultDivergenceWindow = 5 * time.Second
+	// DefaultDivergenceWarnPercent es la divergencia a partir de la cual se registra un warning
+	DefaultDivergenceWarnPercent = 0.5
+)
+
+// sourceObservation es el último precio visto de una fuente
+type sourceObservation struct {
+	price *entities.Price
+	seen  time.Time
+}
+
+// DivergenceMonitor compara los precios de WebSocket y REST observados dentro de
+// una ventana corta y registra la divergencia entre fuentes, para detectar
+// corrupción de un feed de forma temprana.
+type DivergenceMonitor struct {
+	window      time.Duration
+	warnPercent float64
+	now         func() time.Time
+
+	mu       sync.Mutex
+	last     map[string]map[string]sourceObservation // pair -> source -> observación
+	measured map[string]divergenceSample             // pair -> última divergencia medida
+}
+
+// divergenceSample es una divergencia medida entre fuentes
+type divergenceSample struct {
+	percent float64
+	at      time.Time
+}
+
+// NewDivergenceMonitor crea un monitor; valores no positivos usan los defaults
+func NewDivergenceMonitor(window time.Duration, warnPercent float64) *DivergenceMonitor {
+	if window <= 0 {
+		window = DefaultDivergenceWindow
+	}
+	if warnPercent <= 0 {
+		warnPercent = DefaultDivergenceWarnPercent
+	}
+	return &DivergenceMonitor{
+		window:      window,
+		warnPercent: warnPercent,
+		now:         time.Now,
+		last:        make(map[string]map[string]sourceObservation),
+		measured:    make(map[string]divergenceSample),
+	}
+}
+
+// Observe registra un precio de source y, si la otra fuente produjo un valor del
+// mismo par dentro de la ventana, mide la divergencia entre ambos.
+func (d *DivergenceMonitor) Observe(ctx context.Context, source string, price *entities.Price) {
+	if d == nil || price == nil {
+		return
+	}
+
+	now := d.now()
+	d.mu.Lock()
+	bySource, ok := d.last[price.Pair]
+	if !ok {
+		bySource = make(map[string]sourceObservation, 2)
+		d.last[price.Pair] = bySource
+	}
+	bySource[source] = sourceObservation{price: price, seen: now}
+	other, hasOther := bySource[otherSource(source)]
+	d.mu.Unlock()
+
+	if !hasOther || now.Sub(other.seen) > d.window || other.price.Amount <= 0 {
+		return
+	}
+
+	absolute := math.Abs(price.Amount - other.price.Amount)
+	percent := absolute / other.price.Amount * 100
+	metrics.RecordPriceSourceDivergence(price.Pair, absolute, percent)
+	d.mu.Lock()
+	d.measured[price.Pair] = divergenceSample{percent: percent, at: now}
+	d.mu.Unlock()
+
+	if percent >= d.warnPercent {
+		metrics.RecordPriceSourceConflict(price.Pair)
+		logging.Warn(ctx, "Price sources disagree", logging.Fields{
+			"pair":               price.Pair,
+			"source":             source,
+			"amount":             price.Amount,
+			"other_source":       otherSource(source),
+			"other_amount":       other.price.Amount,
+			"divergence_abs":     absolute,
+			"divergence_percent": percent,
+			"warn_percent":       d.warnPercent,
+		})
+	}
+}
+
+// LastDivergence retorna la última divergencia porcentual medida para pair y
+// cuándo se midió; false si nunca hubo observaciones comparables
+func (d *DivergenceMonitor) LastDivergence(pair string) (percent float64, at time.Time, ok bool) {
+	if d == nil {
+		return 0, time.Time{}, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sample, ok := d.measured[pair]
+	return sample.percent, sample.at, ok
+}
+
+// Resolve elige entre candidate (obtenido de source) y la última observación de la
+// otra fuente dentro de la ventana: gana el timestamp más reciente y, en empate,
+// WebSocket. Sin observación comparable retorna candidate.
+func (d *DivergenceMonitor) Resolve(source string, candidate *entities.Price) *entities.Price {
+	if d == nil || candidate == nil {
+		return candidate
+	}
+
+	d.mu.Lock()
+	other, ok := d.last[candidate.Pair][otherSource(source)]
+	d.mu.Unlock()
+	if !ok || d.now().Sub(other.seen) > d.window {
+		return candidate
+	}
+
+	switch {
+	case other.price.Timestamp.After(candidate.Timestamp):
+		return other.price
+	case candidate.Timestamp.After(other.price.Timestamp):
+		return candidate
+	case source == SourceWebSocket:
+		return candidate
+	default:
+		return other.price
+	}
+}
+
+func otherSource(source string) string {
+	if source == SourceWebSocket {
+		return SourceREST
+	}
+	return SourceWebSocket
+}
+
+// observedExchange decora un Exchange registrando cada precio obtenido en el monitor
+type observedExchange struct {
+	interfaces.Exchange
+	source  string
+	monitor *DivergenceMonitor
+}
+
+func (o *observedExchange) GetTicker(ctx context.Context, pair string) (*entities.Price, error) {
+	price, err := o.Exchange.GetTicker(ctx, pair)
+	if err == nil {
+		o.monitor.Observe(ctx, o.source, price)
+	}
+	return price, err
+}
+
+func (o *observedExchange) GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
+	prices, err := o.Exchange.GetTickers(ctx, pairs)
+	if err == nil {
+		for _, price := range prices {
+			o.monitor.Observe(ctx, o.source, price)
+		}
+	}
+	return prices, err
+}
@@ -0,0 +1,98 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubExchange retorna siempre el mismo precio
+type stubExchange struct {
+	price *entities.Price
+}
+
+func (s *stubExchange) GetTicker(context.Context, string) (*entities.Price, error) {
+	return s.price, nil
+}
+
+func (s *stubExchange) GetTickers(context.Context, []string) ([]*entities.Price, error) {
+	return []*entities.Price{s.price}, nil
+}
+
+func newTestDivergenceMonitor(now *time.Time) *DivergenceMonitor {
+	m := NewDivergenceMonitor(5*time.Second, 1)
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func TestDivergenceMonitor_ResolvePrefersNewerThenWebSocket(t *testing.T) {
+	now := time.Now()
+	m := newTestDivergenceMonitor(&now)
+	ctx := context.Background()
+
+	ws := &entities.Price{Pair: "BTC/USD", Amount: 50000, Timestamp: now}
+	m.Observe(ctx, SourceWebSocket, ws)
+
+	olderREST := &entities.Price{Pair: "BTC/USD", Amount: 49990, Timestamp: now.Add(-time.Second)}
+	assert.Same(t, ws, m.Resolve(SourceREST, olderREST))
+
+	newerREST := &entities.Price{Pair: "BTC/USD", Amount: 50010, Timestamp: now.Add(time.Second)}
+	assert.Same(t, newerREST, m.Resolve(SourceREST, newerREST))
+
+	tiedREST := &entities.Price{Pair: "BTC/USD", Amount: 50005, Timestamp: now}
+	assert.Same(t, ws, m.Resolve(SourceREST, tiedREST), "WebSocket wins ties")
+
+	// Fuera de la ventana la observación WebSocket ya no compite
+	now = now.Add(10 * time.Second)
+	assert.Same(t, olderREST, m.Resolve(SourceREST, olderREST))
+}
+
+func TestDivergenceMonitor_ResolveWithoutOtherSource(t *testing.T) {
+	now := time.Now()
+	m := newTestDivergenceMonitor(&now)
+	rest := &entities.Price{Pair: "ETH/USD", Amount: 3000, Timestamp: now}
+
+	assert.Same(t, rest, m.Resolve(SourceREST, rest))
+
+	var nilMonitor *DivergenceMonitor
+	assert.Same(t, rest, nilMonitor.Resolve(SourceREST, rest))
+	nilMonitor.Observe(context.Background(), SourceREST, rest) // no debe hacer panic
+}
+
+func TestObservedExchange_RecordsRESTPrices(t *testing.T) {
+	now := time.Now()
+	m := newTestDivergenceMonitor(&now)
+	rest := &entities.Price{Pair: "BTC/USD", Amount: 50000, Timestamp: now.Add(time.Second)}
+	source := &observedExchange{Exchange: &stubExchange{price: rest}, source: SourceREST, monitor: m}
+
+	got, err := source.GetTicker(context.Background(), "BTC/USD")
+	assert.NoError(t, err)
+	assert.Same(t, rest, got)
+
+	ws := &entities.Price{Pair: "BTC/USD", Amount: 50001, Timestamp: now}
+	assert.Same(t, rest, m.Resolve(SourceWebSocket, ws))
+}
+
+func TestDivergenceMonitor_LastDivergence(t *testing.T) {
+	now := time.Now()
+	m := newTestDivergenceMonitor(&now)
+	ctx := context.Background()
+
+	_, _, ok := m.LastDivergence("BTC/USD")
+	assert.False(t, ok, "nothing compared yet")
+
+	m.Observe(ctx, SourceWebSocket, &entities.Price{Pair: "BTC/USD", Amount: 50000, Timestamp: now})
+	m.Observe(ctx, SourceREST, &entities.Price{Pair: "BTC/USD", Amount: 50050, Timestamp: now})
+
+	percent, at, ok := m.LastDivergence("BTC/USD")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.1, percent, 1e-9)
+	assert.Equal(t, now, at)
+
+	var nilMonitor *DivergenceMonitor
+	_, _, ok = nilMonitor.LastDivergence("BTC/USD")
+	assert.False(t, ok)
+}
@@ -0,0 +1,12 @@
+package exchange
+
+import "errors"
+
+// Errores de los intentos WebSocket del fallback; determineFallbackReason los
+// clasifica con errors.Is para etiquetar las activaciones
+var (
+	ErrWebSocketBudgetExhausted = errors.New("WebSocket budget exhausted")
+	ErrWebSocketTimeout         = errors.New("WebSocket timeout")
+	ErrWebSocketPanic           = errors.New("WebSocket panic recovered")
+	ErrWebSocketRetriesDenied   = errors.New("WebSocket retries denied by retry budget")
+)
@@ -0,0 +1,132 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"slices"
+	"time"
+)
+
+// reconnectReasonForced es el motivo de las reconexiones pedidas con Reconnect
+const reconnectReasonForced = "forced"
+
+// fallbackEventMessage es el mensaje del único log que describe cada activación
+// del fallback REST; sus campos forman un esquema estable para scripts de análisis
+const fallbackEventMessage = "fallback_event"
+
+// Resultados de una activación del fallback
+const (
+	fallbackOutcomeSuccess = "success"
+	fallbackOutcomeFailed  = "failed"
+)
+
+// fallbackActivation reúne lo ocurrido en una activación del fallback REST
+type fallbackActivation struct {
+	started     time.Time
+	pairs       []string
+	reason      string
+	attempts    int   // Intentos WebSocket realizados antes de caer a REST
+	maxAttempts int   // Intentos WebSocket permitidos (kraken.max_retries)
+	wsErr       error // Último error del WebSocket
+	restLatency time.Duration
+	restErr     error
+	prices      []*entities.Price // Precios devueltos por REST (vacío si falló)
+}
+
+// fields arma los campos del log fallback_event. price_age_ms es la edad en now
+// del precio más viejo devuelto y sólo aparece si REST respondió.
+func (a fallbackActivation) fields(now time.Time) logging.Fields {
+	fields := logging.Fields{
+		"pairs":                a.pairs,
+		"pairs_count":          len(a.pairs),
+		"reason":               a.reason,
+		"attempts":             a.attempts,
+		"max_attempts":         a.maxAttempts,
+		"ws_error":             "",
+		"rest_latency_ms":      a.restLatency.Milliseconds(),
+		"fallback_duration_ms": now.Sub(a.started).Milliseconds(),
+		"outcome":              fallbackOutcomeSuccess,
+	}
+	if a.wsErr != nil {
+		fields["ws_error"] = a.wsErr.Error()
+	}
+	if a.restErr != nil {
+		fields["outcome"] = fallbackOutcomeFailed
+		fields["rest_error"] = a.restErr.Error()
+		return fields
+	}
+
+	var oldest time.Duration
+	retrieved := 0
+	for _, price := range a.prices {
+		if price == nil {
+			continue
+		}
+		retrieved++
+		oldest = max(oldest, price.AgeAt(now))
+	}
+	fields["retrieved_count"] = retrieved
+	fields["price_age_ms"] = oldest.Milliseconds()
+	return fields
+}
+
+// recordFallback guarda la activación en el historial y en el registro de
+// auditoría y emite su log fallback_event (INFO si REST respondió, ERROR si
+// también falló)
+func (f *FallbackExchange) recordFallback(ctx context.Context, activation fallbackActivation) {
+	f.history.record(interfaces.FallbackEvent{
+		Time:      activation.started,
+		Pairs:     slices.Clone(activation.pairs),
+		Reason:    activation.reason,
+		Success:   activation.restErr == nil,
+		Duration:  activation.restLatency,
+		RequestID: logging.GetRequestID(ctx),
+	})
+	f.quality.observeFallback(activation.pairs, activation.started)
+	if f.audit != nil {
+		event := interfaces.AuditEvent{
+			Type:      interfaces.AuditEventFallback,
+			Time:      activation.started,
+			Reason:    activation.reason,
+			Duration:  activation.restLatency,
+			Pairs:     activation.pairs,
+			Success:   activation.restErr == nil,
+			RequestID: logging.GetRequestID(ctx),
+		}
+		if activation.restErr != nil {
+			event.Error = activation.restErr.Error()
+		}
+		f.audit.RecordEvent(ctx, event)
+	}
+
+	fields := activation.fields(time.Now())
+	if activation.restErr != nil {
+		logging.Error(ctx, fallbackEventMessage, fields)
+		return
+	}
+	logging.Info(ctx, fallbackEventMessage, fields)
+}
+
+// auditReconnect guarda un intento de reconexión del WebSocket en el registro de auditoría
+func (f *FallbackExchange) auditReconnect(reconnect kraken.ReconnectEvent) {
+	if f.audit == nil {
+		return
+	}
+	event := interfaces.AuditEvent{
+		Type:     interfaces.AuditEventReconnect,
+		Time:     reconnect.Started,
+		Reason:   reconnect.Reason,
+		Duration: reconnect.Duration,
+		Pairs:    reconnect.Pairs,
+		Success:  reconnect.Err == nil,
+		Shard:    reconnect.Shard,
+		Attempt:  reconnect.Attempt,
+	}
+	if reconnect.Err != nil {
+		event.Error = reconnect.Err.Error()
+	}
+	f.audit.RecordEvent(context.Background(), event)
+}
@@ -0,0 +1,144 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackActivation_Fields(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(500 * time.Millisecond)
+	activation := fallbackActivation{
+		started:     now.Add(-300 * time.Millisecond),
+		pairs:       []string{"BTC/USD", "ETH/USD"},
+		reason:      "timeout",
+		attempts:    3,
+		maxAttempts: 3,
+		wsErr:       errors.New("WebSocket timeout after 1s for operation: multiple_pairs"),
+		restLatency: 250 * time.Millisecond,
+		prices: []*entities.Price{
+			entities.NewPriceWithExchangeTime("BTC/USD", 50000, now.Add(-2*time.Second), now),
+			entities.NewPriceWithExchangeTime("ETH/USD", 3000, now.Add(-5*time.Second), now),
+			nil,
+		},
+	}
+
+	fields := activation.fields(now)
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, fields["pairs"])
+	assert.Equal(t, 2, fields["pairs_count"])
+	assert.Equal(t, "timeout", fields["reason"])
+	assert.Equal(t, 3, fields["attempts"])
+	assert.Equal(t, 3, fields["max_attempts"])
+	assert.Equal(t, "WebSocket timeout after 1s for operation: multiple_pairs", fields["ws_error"])
+	assert.Equal(t, int64(250), fields["rest_latency_ms"])
+	assert.Equal(t, int64(300), fields["fallback_duration_ms"])
+	assert.Equal(t, fallbackOutcomeSuccess, fields["outcome"])
+	assert.Equal(t, 2, fields["retrieved_count"])
+	assert.Equal(t, int64(5000), fields["price_age_ms"], "the oldest returned price is reported")
+	assert.NotContains(t, fields, "rest_error")
+}
+
+func TestFallbackActivation_FieldsWhenRESTFails(t *testing.T) {
+	now := time.Now()
+	activation := fallbackActivation{
+		started:     now,
+		pairs:       []string{"BTC/USD"},
+		reason:      "connection_closed",
+		attempts:    1,
+		maxAttempts: 3,
+		wsErr:       errors.New("connection closed"),
+		restErr:     errors.New("HTTP 503"),
+	}
+
+	fields := activation.fields(now)
+	assert.Equal(t, fallbackOutcomeFailed, fields["outcome"])
+	assert.Equal(t, "HTTP 503", fields["rest_error"])
+	assert.NotContains(t, fields, "price_age_ms")
+	assert.NotContains(t, fields, "retrieved_count")
+}
+
+func TestFallbackExchange_RecordFallbackKeepsHistory(t *testing.T) {
+	exchange := &FallbackExchange{
+		config:  config.KrakenConfig{MaxRetries: 2},
+		history: newFallbackHistory(fallbackHistorySize),
+	}
+	pairs := []string{"BTC/USD"}
+	exchange.recordFallback(context.Background(), fallbackActivation{
+		started:     time.Now(),
+		pairs:       pairs,
+		reason:      "timeout",
+		attempts:    2,
+		maxAttempts: 2,
+		wsErr:       errors.New("timeout"),
+		restLatency: 40 * time.Millisecond,
+		restErr:     errors.New("HTTP 503"),
+	})
+	pairs[0] = "ETH/USD"
+
+	events := exchange.RecentFallbacks()
+	require.Len(t, events, 1)
+	assert.Equal(t, []string{"BTC/USD"}, events[0].Pairs, "the history keeps its own copy of the pairs")
+	assert.Equal(t, "timeout", events[0].Reason)
+	assert.False(t, events[0].Success)
+	assert.Equal(t, 40*time.Millisecond, events[0].Duration)
+}
+
+// recordingAudit guarda los eventos de auditoría recibidos
+type recordingAudit struct {
+	events []interfaces.AuditEvent
+}
+
+func (r *recordingAudit) RecordEvent(_ context.Context, event interfaces.AuditEvent) {
+	r.events = append(r.events, event)
+}
+
+func TestFallbackExchange_RecordsAuditEvents(t *testing.T) {
+	audit := &recordingAudit{}
+	exchange := &FallbackExchange{history: newFallbackHistory(fallbackHistorySize)}
+	exchange.SetAuditRecorder(audit)
+	started := time.Now()
+
+	exchange.recordFallback(context.Background(), fallbackActivation{
+		started:     started,
+		pairs:       []string{"BTC/USD"},
+		reason:      "timeout",
+		restLatency: 40 * time.Millisecond,
+		restErr:     errors.New("HTTP 503"),
+	})
+	exchange.auditReconnect(kraken.ReconnectEvent{
+		Shard:    "1",
+		Started:  started,
+		Duration: time.Second,
+		Reason:   kraken.ReconnectReasonReadTimeout,
+		Attempt:  1,
+		Pairs:    []string{"ETH/USD"},
+	})
+
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, interfaces.AuditEvent{
+		Type:     interfaces.AuditEventFallback,
+		Time:     started,
+		Reason:   "timeout",
+		Duration: 40 * time.Millisecond,
+		Pairs:    []string{"BTC/USD"},
+		Error:    "HTTP 503",
+	}, audit.events[0])
+	assert.Equal(t, interfaces.AuditEvent{
+		Type:     interfaces.AuditEventReconnect,
+		Time:     started,
+		Reason:   kraken.ReconnectReasonReadTimeout,
+		Duration: time.Second,
+		Pairs:    []string{"ETH/USD"},
+		Success:  true,
+		Shard:    "1",
+		Attempt:  1,
+	}, audit.events[1])
+}
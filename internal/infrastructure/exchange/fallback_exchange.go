@@ -8,79 +8,113 @@ import (
 	"btc-ltp-service/internal/infrastructure/logging"
 	"btc-ltp-service/internal/infrastructure/metrics"
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"net"
+	"slices"
+	"strconv"
 	"time"
 )
 
 // FallbackExchange implementa la interfaz Exchange con estrategia de fallback
 // WebSocket → REST para garantizar alta disponibilidad, usando configuración inyectada
 type FallbackExchange struct {
-	primary   *kraken.WebSocketClient // Cliente WebSocket (preferido)
-	secondary interfaces.Exchange     // Cliente REST (fallback)
-	config    config.KrakenConfig     // Configuración de Kraken
+	primary    *kraken.ShardedWebSocketClient  // Cliente WebSocket (preferido), repartido en shards
+	secondary  interfaces.Exchange             // Cliente REST (fallback)
+	config     config.KrakenConfig             // Configuración de Kraken
+	leader     interfaces.LeaderElector        // Opcional: restringe jobs de fondo a la réplica líder
+	watcher    *StalenessWatcher               // Refresca vía REST precios vencidos
+	mapper     *kraken.PairMapper              // Opcional: mapeo dinámico de pares (AssetPairs)
+	divergence *DivergenceMonitor              // Compara precios WebSocket vs REST
+	history    *fallbackHistory                // Últimas activaciones del fallback REST
+	quality    *feedQuality                    // Estadísticas móviles del feed por par
+	pruned     prunedPairs                     // Pares desuscriptos por inactividad
+	health     *sourceHealth                   // Opcional: consulta REST primero mientras el WebSocket puntúa mal
+	status     interfaces.SystemStatusReporter // Opcional: estado de Kraken; en mantenimiento sólo se consulta REST
+	retries    *kraken.RetryBudget             // Opcional: reintentos WebSocket compartidos por GetTicker y GetTickers
+	audit      interfaces.AuditRecorder        // Opcional: registro persistente de fallbacks y reconexiones
 }
 
 // NewFallbackExchange crea una nueva instancia del exchange con fallback usando configuración y lista de pares a suscribir al inicio
 func NewFallbackExchange(krakenConfig config.KrakenConfig, supportedPairs []string) *FallbackExchange {
 	// Crear clientes con configuración
-	wsClient := kraken.NewWebSocketClientWithConfig(krakenConfig)
+	wsClient := kraken.NewShardedWebSocketClient(krakenConfig)
 	restClient := kraken.NewRestClientWithConfig(krakenConfig)
 
+	divergence := NewDivergenceMonitor(krakenConfig.DivergenceWindow, krakenConfig.DivergenceWarnPercent)
 	exchange := &FallbackExchange{
-		primary:   wsClient,
-		secondary: restClient,
-		config:    krakenConfig,
+		primary:    wsClient,
+		secondary:  &observedExchange{Exchange: restClient, source: SourceREST, monitor: divergence},
+		config:     krakenConfig,
+		divergence: divergence,
+		history:    newFallbackHistory(fallbackHistorySize),
+		quality:    newFeedQuality(DefaultFeedQualityWindow),
+		health:     newSourceHealth(krakenConfig.SourceSwitchCooldown, krakenConfig.FallbackTimeout),
+		status:     wsClient,
+		retries:    kraken.NewRetryBudget(SourceWebSocket, krakenConfig.RetryBudget),
+	}
+	wsClient.OnPrice(func(price *entities.Price) {
+		divergence.Observe(context.Background(), SourceWebSocket, price)
+	})
+	wsClient.OnTick(exchange.quality.observeTick)
+	wsClient.OnReconnect(exchange.auditReconnect)
+	exchange.watcher = NewStalenessWatcher(wsClient.GetPriceCache(), exchange.secondary, supportedPairs,
+		krakenConfig.StalenessInterval, krakenConfig.StalenessMaxAge)
+	exchange.watcher.SetLeaderCheck(exchange.isLeader)
+	exchange.watcher.SetPausedCheck(exchange.pruned.contains)
+
+	if krakenConfig.DynamicPairs {
+		exchange.mapper = kraken.NewPairMapper(krakenConfig.RestURL, krakenConfig.Timeout)
+		exchange.mapper.SetConnectionConfig(krakenConfig)
+		wsClient.SetPairMapper(exchange.mapper)
+		restClient.SetPairMapper(exchange.mapper)
 	}
 
 	// Intentar conectar WebSocket al inicio de forma asíncrona con contexto controlado
 	go func() {
-		// Crear contexto con timeout para evitar bloqueos indefinidos
+		// Crear contexto con timeout para evitar bloqueos indefinidos; el dial respeta su deadline
 		ctx, cancel := context.WithTimeout(context.Background(), krakenConfig.FallbackTimeout)
 		defer cancel()
 
-		// Usar canal para hacer la conexión cancelable
-		done := make(chan error, 1)
-		go func() {
-			done <- wsClient.Connect()
-		}()
+		// Cargar AssetPairs antes de suscribir para que los pares dinámicos resuelvan
+		if exchange.mapper != nil {
+			exchange.mapper.Start(ctx, krakenConfig.PairsRefreshInterval)
+		}
 
-		select {
-		case err := <-done:
-			if err != nil {
-				metrics.UpdateWebSocketConnectionStatus(false)
-				metrics.RecordWebSocketReconnectionAttempt("startup")
-				logging.Warn(ctx, "Failed to initialize WebSocket connection at startup", logging.Fields{
-					"error":            err.Error(),
-					"websocket_url":    krakenConfig.WebSocketURL,
-					"fallback_timeout": krakenConfig.FallbackTimeout,
-				})
-			} else {
-				metrics.UpdateWebSocketConnectionStatus(true)
-				logging.Info(ctx, "WebSocket connection established successfully", logging.Fields{
+		if err := wsClient.ConnectContext(ctx); err != nil {
+			metrics.UpdateWebSocketConnectionStatus(false)
+			metrics.RecordWebSocketReconnectionAttempt("startup")
+			if ctx.Err() != nil {
+				logging.Warn(ctx, "WebSocket connection startup timeout", logging.Fields{
+					"timeout":       krakenConfig.FallbackTimeout,
 					"websocket_url": krakenConfig.WebSocketURL,
 				})
-
-				// Suscribir pares soportados inmediatamente
-				if len(supportedPairs) > 0 {
-					if subErr := wsClient.SubscribeTicker(supportedPairs); subErr != nil {
-						logging.Warn(ctx, "Failed to subscribe supported pairs on startup", logging.Fields{
-							"error": subErr.Error(),
-							"pairs": supportedPairs,
-						})
-					}
-
-					// Lanzar watchdog de frescura
-					go exchange.startStalenessWatcher(supportedPairs, 60*time.Second)
-				}
+				return
 			}
-		case <-ctx.Done():
-			metrics.UpdateWebSocketConnectionStatus(false)
-			metrics.RecordWebSocketReconnectionAttempt("startup")
-			logging.Warn(ctx, "WebSocket connection startup timeout", logging.Fields{
-				"timeout":       krakenConfig.FallbackTimeout,
-				"websocket_url": krakenConfig.WebSocketURL,
+			logging.Warn(ctx, "Failed to initialize WebSocket connection at startup", logging.Fields{
+				"error":            err.Error(),
+				"websocket_url":    krakenConfig.WebSocketURL,
+				"fallback_timeout": krakenConfig.FallbackTimeout,
 			})
+			return
+		}
+
+		metrics.UpdateWebSocketConnectionStatus(true)
+		logging.Info(ctx, "WebSocket connection established successfully", logging.Fields{
+			"websocket_url": krakenConfig.WebSocketURL,
+		})
+
+		// Suscribir pares soportados inmediatamente
+		if len(supportedPairs) > 0 {
+			if subErr := wsClient.SubscribeTicker(supportedPairs); subErr != nil {
+				logging.Warn(ctx, "Failed to subscribe supported pairs on startup", logging.Fields{
+					"error": subErr.Error(),
+					"pairs": supportedPairs,
+				})
+			}
+
+			// Lanzar watchdog de frescura
+			exchange.watcher.Start()
 		}
 	}()
 
@@ -93,6 +127,7 @@ func (f *FallbackExchange) GetTicker(ctx context.Context, pair string) (*entitie
 		"pair":             pair,
 		"fallback_timeout": f.config.FallbackTimeout,
 	})
+	f.revive(ctx, pair)
 
 	// 0. Probar cache global antes de WebSocket
 	if cache := f.primary.GetPriceCache(); cache != nil {
@@ -101,8 +136,22 @@ func (f *FallbackExchange) GetTicker(ctx context.Context, pair string) (*entitie
 		}
 	}
 
+	// Durante el mantenimiento de Kraken el WebSocket no publica precios
+	if f.inMaintenance() {
+		price, err := f.restTicker(ctx, pair)
+		if err != nil {
+			return nil, fmt.Errorf("REST failed during Kraken maintenance: %w", err)
+		}
+		return f.divergence.Resolve(SourceREST, price), nil
+	}
+
+	// Con el WebSocket degradado se consulta REST primero durante el cool-down
+	if f.health.preferREST(ctx, time.Now()) {
+		return f.getTickerRESTFirst(ctx, pair)
+	}
+
 	// 1. Intentar con WebSocket primero
-	price, err := f.tryWebSocketSingle(ctx, pair, func(ctx context.Context) (*entities.Price, error) {
+	price, attempts, err := f.tryWebSocketSingle(ctx, pair, func(ctx context.Context) (*entities.Price, error) {
 		return f.primary.GetTicker(ctx, pair)
 	})
 
@@ -116,42 +165,30 @@ func (f *FallbackExchange) GetTicker(ctx context.Context, pair string) (*entitie
 	}
 
 	// 2. Fallback a REST
-	fallbackReason := f.determineFallbackReason(err)
-	metrics.RecordFallbackActivation(fallbackReason, pair)
-
-	logging.Info(ctx, "WebSocket failed, falling back to REST API", logging.Fields{
-		"pair":             pair,
-		"websocket_error":  err.Error(),
-		"fallback_reason":  fallbackReason,
-		"fallback_timeout": f.config.FallbackTimeout,
-	})
+	activation := fallbackActivation{
+		started:     time.Now(),
+		pairs:       []string{pair},
+		reason:      f.determineFallbackReason(err),
+		attempts:    attempts,
+		maxAttempts: f.config.MaxRetries,
+		wsErr:       err,
+	}
+	metrics.RecordFallbackActivation(activation.reason, pair)
 
-	fallbackStartTime := time.Now()
-	restStartTime := time.Now()
-	price, restErr := f.secondary.GetTicker(ctx, pair)
-	restDuration := time.Since(restStartTime)
-
-	if restErr != nil {
-		logging.Error(ctx, "Both WebSocket and REST failed", logging.Fields{
-			"pair":             pair,
-			"websocket_error":  err.Error(),
-			"rest_error":       restErr.Error(),
-			"rest_duration_ms": restDuration.Milliseconds(),
-		})
-		return nil, fmt.Errorf("both WebSocket and REST failed - WebSocket: %v, REST: %v", err, restErr)
+	price, activation.restErr = f.restTicker(ctx, pair)
+	activation.restLatency = time.Since(activation.started)
+	if activation.restErr != nil {
+		f.recordFallback(ctx, activation)
+		return nil, fmt.Errorf("both WebSocket and REST failed - WebSocket: %w, REST: %w", err, activation.restErr)
 	}
 
+	// Si WebSocket produjo un valor reciente del mismo par, desempatar por timestamp
+	price = f.divergence.Resolve(SourceREST, price)
+	activation.prices = []*entities.Price{price}
+	f.recordFallback(ctx, activation)
+
 	// Record successful fallback duration
-	fallbackDuration := time.Since(fallbackStartTime)
-	metrics.RecordFallbackDuration(pair, fallbackDuration.Seconds())
-
-	logging.Info(ctx, "Successfully retrieved price via REST fallback", logging.Fields{
-		"pair":                 pair,
-		"amount":               price.Amount,
-		"source":               "rest_fallback",
-		"rest_duration_ms":     restDuration.Milliseconds(),
-		"fallback_duration_ms": fallbackDuration.Milliseconds(),
-	})
+	metrics.RecordFallbackDuration(pair, time.Since(activation.started).Seconds())
 
 	return price, nil
 }
@@ -167,6 +204,7 @@ func (f *FallbackExchange) GetTickers(ctx context.Context, pairs []string) ([]*e
 		"pairs":            pairs,
 		"fallback_timeout": f.config.FallbackTimeout,
 	})
+	f.revive(ctx, pairs...)
 
 	// 0. Intentar cache global primero
 	var cached []*entities.Price
@@ -180,8 +218,23 @@ func (f *FallbackExchange) GetTickers(ctx context.Context, pairs []string) ([]*e
 		missing = pairs
 	}
 
+	if f.inMaintenance() {
+		prices, err := f.restTickers(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("REST failed during Kraken maintenance: %w", err)
+		}
+		for i, price := range prices {
+			prices[i] = f.divergence.Resolve(SourceREST, price)
+		}
+		return append(cached, prices...), nil
+	}
+
+	if f.health.preferREST(ctx, time.Now()) {
+		return f.getTickersRESTFirst(ctx, cached, missing)
+	}
+
 	// 1. Intentar con WebSocket para pares faltantes
-	pricesMissing, err := f.tryWebSocketMultiple(ctx, "multiple_pairs", func(ctx context.Context) ([]*entities.Price, error) {
+	pricesMissing, attempts, err := f.tryWebSocketMultiple(ctx, "multiple_pairs", func(ctx context.Context) ([]*entities.Price, error) {
 		return f.primary.GetTickers(ctx, missing)
 	})
 
@@ -197,65 +250,148 @@ func (f *FallbackExchange) GetTickers(ctx context.Context, pairs []string) ([]*e
 	}
 
 	// 2. Fallback a REST
-	fallbackReason := f.determineFallbackReason(err)
+	activation := fallbackActivation{
+		started:     time.Now(),
+		pairs:       pairs,
+		reason:      f.determineFallbackReason(err),
+		attempts:    attempts,
+		maxAttempts: f.config.MaxRetries,
+		wsErr:       err,
+	}
 	// Record fallback activation for each pair
 	for _, pair := range pairs {
-		metrics.RecordFallbackActivation(fallbackReason, pair)
+		metrics.RecordFallbackActivation(activation.reason, pair)
 	}
 
-	logging.Info(ctx, "WebSocket failed, falling back to REST API for multiple pairs", logging.Fields{
-		"pairs_count":      len(pairs),
-		"websocket_error":  err.Error(),
-		"fallback_reason":  fallbackReason,
-		"fallback_timeout": f.config.FallbackTimeout,
-	})
+	prices, activation.restErr = f.restTickers(ctx, pairs)
+	activation.restLatency = time.Since(activation.started)
+	if activation.restErr != nil {
+		f.recordFallback(ctx, activation)
+		return nil, fmt.Errorf("both WebSocket and REST failed for multiple pairs - WebSocket: %w, REST: %w", err, activation.restErr)
+	}
 
-	fallbackStartTime := time.Now()
-	restStartTime := time.Now()
-	prices, restErr := f.secondary.GetTickers(ctx, pairs)
-	restDuration := time.Since(restStartTime)
-
-	if restErr != nil {
-		logging.Error(ctx, "Both WebSocket and REST failed for multiple pairs", logging.Fields{
-			"pairs_count":      len(pairs),
-			"websocket_error":  err.Error(),
-			"rest_error":       restErr.Error(),
-			"rest_duration_ms": restDuration.Milliseconds(),
-		})
-		return nil, fmt.Errorf("both WebSocket and REST failed for multiple pairs - WebSocket: %v, REST: %v", err, restErr)
+	for i, price := range prices {
+		prices[i] = f.divergence.Resolve(SourceREST, price)
 	}
+	activation.prices = prices
+	f.recordFallback(ctx, activation)
 
 	// Record successful fallback duration for each pair
-	fallbackDuration := time.Since(fallbackStartTime)
+	fallbackDuration := time.Since(activation.started)
 	for _, price := range prices {
 		if price != nil {
 			metrics.RecordFallbackDuration(price.Pair, fallbackDuration.Seconds())
 		}
 	}
 
-	logging.Info(ctx, "Successfully retrieved prices via REST fallback", logging.Fields{
-		"pairs_count":          len(pairs),
-		"retrieved_count":      len(prices),
-		"source":               "rest_fallback",
-		"rest_duration_ms":     restDuration.Milliseconds(),
-		"fallback_duration_ms": fallbackDuration.Milliseconds(),
+	return prices, nil
+}
+
+// getTickerRESTFirst consulta REST antes que el WebSocket mientras éste está
+// degradado; no es una activación del fallback
+func (f *FallbackExchange) getTickerRESTFirst(ctx context.Context, pair string) (*entities.Price, error) {
+	price, restErr := f.restTicker(ctx, pair)
+	if restErr == nil {
+		logging.Debug(ctx, "Retrieved price via REST while WebSocket is unhealthy", logging.Fields{
+			"pair":   pair,
+			"source": "rest",
+		})
+		return f.divergence.Resolve(SourceREST, price), nil
+	}
+
+	price, _, err := f.tryWebSocketSingle(ctx, pair, func(ctx context.Context) (*entities.Price, error) {
+		return f.primary.GetTicker(ctx, pair)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("both REST and WebSocket failed - REST: %w, WebSocket: %w", restErr, err)
+	}
+	return price, nil
+}
+
+// getTickersRESTFirst es getTickerRESTFirst para los pares que no estaban en caché
+func (f *FallbackExchange) getTickersRESTFirst(ctx context.Context, cached []*entities.Price, missing []string) ([]*entities.Price, error) {
+	prices, restErr := f.restTickers(ctx, missing)
+	if restErr == nil {
+		logging.Debug(ctx, "Retrieved prices via REST while WebSocket is unhealthy", logging.Fields{
+			"pairs_count":     len(missing),
+			"retrieved_count": len(prices),
+			"source":          "rest",
+		})
+		for i, price := range prices {
+			prices[i] = f.divergence.Resolve(SourceREST, price)
+		}
+		return append(cached, prices...), nil
+	}
+
+	prices, _, err := f.tryWebSocketMultiple(ctx, "multiple_pairs", func(ctx context.Context) ([]*entities.Price, error) {
+		return f.primary.GetTickers(ctx, missing)
 	})
+	if err != nil {
+		return nil, fmt.Errorf("both REST and WebSocket failed for multiple pairs - REST: %w, WebSocket: %w", restErr, err)
+	}
+	return append(cached, prices...), nil
+}
 
-	return prices, nil
+// restTicker consulta REST y registra el resultado en el puntaje de la fuente
+func (f *FallbackExchange) restTicker(ctx context.Context, pair string) (*entities.Price, error) {
+	start := time.Now()
+	defer entities.RequestTimingsFrom(ctx).Observe(entities.TimingREST, start)
+	price, err := f.secondary.GetTicker(ctx, pair)
+	f.observeREST(ctx, start, err)
+	return price, err
 }
 
-// tryWebSocketSingle intenta ejecutar una operación WebSocket para un solo precio con timeout configurado
-func (f *FallbackExchange) tryWebSocketSingle(ctx context.Context, operation string, wsFunc func(context.Context) (*entities.Price, error)) (*entities.Price, error) {
+// restTickers es restTicker para varios pares
+func (f *FallbackExchange) restTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
+	start := time.Now()
+	defer entities.RequestTimingsFrom(ctx).Observe(entities.TimingREST, start)
+	prices, err := f.secondary.GetTickers(ctx, pairs)
+	f.observeREST(ctx, start, err)
+	return prices, err
+}
+
+// observeREST puntúa una consulta REST salvo que la haya cortado el contexto de la request
+func (f *FallbackExchange) observeREST(ctx context.Context, start time.Time, err error) {
+	if ctx.Err() == nil {
+		f.health.observe(SourceREST, time.Since(start), err)
+	}
+}
+
+// tryWebSocketSingle intenta ejecutar una operación WebSocket para un solo precio con timeout configurado;
+// retorna además la cantidad de intentos realizados
+func (f *FallbackExchange) tryWebSocketSingle(ctx context.Context, operation string, wsFunc func(context.Context) (*entities.Price, error)) (*entities.Price, int, error) {
+	budgetCtx, cancelBudget := f.webSocketBudget(ctx)
+	defer cancelBudget()
+	timings := entities.RequestTimingsFrom(ctx)
+	defer timings.Observe(entities.TimingWebSocket, time.Now())
+
 	var lastErr error
+	attempts := 0
 	for attempt := 1; attempt <= f.config.MaxRetries; attempt++ {
-		wsCtx, cancel := context.WithTimeout(ctx, f.config.FallbackTimeout)
+		if budgetCtx.Err() != nil {
+			if lastErr == nil {
+				lastErr = fmt.Errorf("%w for operation %s: %w", ErrWebSocketBudgetExhausted, operation, budgetCtx.Err())
+			}
+			break
+		}
+		if attempt > 1 {
+			if !f.retries.AllowRetry() {
+				f.health.tripWebSocket(ctx, time.Now())
+				lastErr = fmt.Errorf("%w: %w", ErrWebSocketRetriesDenied, lastErr)
+				break
+			}
+			timings.Retry()
+		}
+		attempts = attempt
+		attemptStart := time.Now()
+		wsCtx, cancel := context.WithTimeout(budgetCtx, f.config.FallbackTimeout)
 		resultChan := make(chan *entities.Price, 1)
 		errorChan := make(chan error, 1)
 
 		go func() {
 			defer func() {
 				if r := recover(); r != nil {
-					errorChan <- fmt.Errorf("WebSocket panic recovered: %v", r)
+					errorChan <- fmt.Errorf("%w: %v", ErrWebSocketPanic, r)
 				}
 			}()
 			res, err := wsFunc(wsCtx)
@@ -269,34 +405,69 @@ func (f *FallbackExchange) tryWebSocketSingle(ctx context.Context, operation str
 		select {
 		case res := <-resultChan:
 			cancel()
-			return res, nil
+			f.health.observe(SourceWebSocket, time.Since(attemptStart), nil)
+			f.retries.OnSuccess()
+			return res, attempts, nil
 		case err := <-errorChan:
 			lastErr = err
 		case <-wsCtx.Done():
-			lastErr = fmt.Errorf("WebSocket timeout after %v for operation: %s", f.config.FallbackTimeout, operation)
+			if budgetCtx.Err() != nil {
+				lastErr = fmt.Errorf("%w for operation %s: %w", ErrWebSocketBudgetExhausted, operation, budgetCtx.Err())
+			} else {
+				lastErr = fmt.Errorf("%w after %v for operation: %s", ErrWebSocketTimeout, f.config.FallbackTimeout, operation)
+			}
 		}
 		cancel()
-		logging.Warn(ctx, "WebSocket attempt failed", logging.Fields{
+		// Agotar el presupuesto de la request no es una falla del WebSocket
+		if budgetCtx.Err() == nil {
+			f.health.observe(SourceWebSocket, time.Since(attemptStart), lastErr)
+			f.retries.OnFailure()
+		}
+		// El resultado de la activación queda en el log fallback_event
+		logging.Debug(ctx, "WebSocket attempt failed", logging.Fields{
 			"attempt":      attempt,
 			"max_attempts": f.config.MaxRetries,
 			"error":        lastErr.Error(),
 		})
 	}
-	return nil, lastErr
+	return nil, attempts, lastErr
 }
 
-// tryWebSocketMultiple intenta ejecutar una operación WebSocket para múltiples precios con timeout configurado
-func (f *FallbackExchange) tryWebSocketMultiple(ctx context.Context, operation string, wsFunc func(context.Context) ([]*entities.Price, error)) ([]*entities.Price, error) {
+// tryWebSocketMultiple intenta ejecutar una operación WebSocket para múltiples precios con timeout configurado;
+// retorna además la cantidad de intentos realizados
+func (f *FallbackExchange) tryWebSocketMultiple(ctx context.Context, operation string, wsFunc func(context.Context) ([]*entities.Price, error)) ([]*entities.Price, int, error) {
+	budgetCtx, cancelBudget := f.webSocketBudget(ctx)
+	defer cancelBudget()
+	timings := entities.RequestTimingsFrom(ctx)
+	defer timings.Observe(entities.TimingWebSocket, time.Now())
+
 	var lastErr error
+	attempts := 0
 	for attempt := 1; attempt <= f.config.MaxRetries; attempt++ {
-		wsCtx, cancel := context.WithTimeout(ctx, f.config.FallbackTimeout)
+		if budgetCtx.Err() != nil {
+			if lastErr == nil {
+				lastErr = fmt.Errorf("%w for operation %s: %w", ErrWebSocketBudgetExhausted, operation, budgetCtx.Err())
+			}
+			break
+		}
+		if attempt > 1 {
+			if !f.retries.AllowRetry() {
+				f.health.tripWebSocket(ctx, time.Now())
+				lastErr = fmt.Errorf("%w: %w", ErrWebSocketRetriesDenied, lastErr)
+				break
+			}
+			timings.Retry()
+		}
+		attempts = attempt
+		attemptStart := time.Now()
+		wsCtx, cancel := context.WithTimeout(budgetCtx, f.config.FallbackTimeout)
 		resultChan := make(chan []*entities.Price, 1)
 		errorChan := make(chan error, 1)
 
 		go func() {
 			defer func() {
 				if r := recover(); r != nil {
-					errorChan <- fmt.Errorf("WebSocket panic recovered: %v", r)
+					errorChan <- fmt.Errorf("%w: %v", ErrWebSocketPanic, r)
 				}
 			}()
 			res, err := wsFunc(wsCtx)
@@ -310,28 +481,62 @@ func (f *FallbackExchange) tryWebSocketMultiple(ctx context.Context, operation s
 		select {
 		case res := <-resultChan:
 			cancel()
-			return res, nil
+			f.health.observe(SourceWebSocket, time.Since(attemptStart), nil)
+			f.retries.OnSuccess()
+			return res, attempts, nil
 		case err := <-errorChan:
 			lastErr = err
 		case <-wsCtx.Done():
-			lastErr = fmt.Errorf("WebSocket timeout after %v for operation: %s", f.config.FallbackTimeout, operation)
+			if budgetCtx.Err() != nil {
+				lastErr = fmt.Errorf("%w for operation %s: %w", ErrWebSocketBudgetExhausted, operation, budgetCtx.Err())
+			} else {
+				lastErr = fmt.Errorf("%w after %v for operation: %s", ErrWebSocketTimeout, f.config.FallbackTimeout, operation)
+			}
 		}
 		cancel()
-		logging.Warn(ctx, "WebSocket attempt failed", logging.Fields{
+		// Agotar el presupuesto de la request no es una falla del WebSocket
+		if budgetCtx.Err() == nil {
+			f.health.observe(SourceWebSocket, time.Since(attemptStart), lastErr)
+			f.retries.OnFailure()
+		}
+		// El resultado de la activación queda en el log fallback_event
+		logging.Debug(ctx, "WebSocket attempt failed", logging.Fields{
 			"attempt":      attempt,
 			"max_attempts": f.config.MaxRetries,
 			"error":        lastErr.Error(),
 		})
 	}
-	return nil, lastErr
+	return nil, attempts, lastErr
+}
+
+// webSocketBudget acota el conjunto de intentos WebSocket a la parte del presupuesto
+// de la request asignada al WebSocket. El fallback REST usa el tiempo restante del
+// contexto: su propia parte más lo que el WebSocket no consumió.
+func (f *FallbackExchange) webSocketBudget(ctx context.Context) (context.Context, context.CancelFunc) {
+	if budget, ok := entities.FetchBudgetFrom(ctx); ok && budget.WebSocket > 0 {
+		return context.WithTimeout(ctx, budget.WebSocket)
+	}
+	return ctx, func() {}
 }
 
 // Close cierra las conexiones de ambos clientes
 func (f *FallbackExchange) Close() error {
+	return f.Shutdown(context.Background())
+}
+
+// Shutdown cierra las conexiones respetando el deadline de ctx
+func (f *FallbackExchange) Shutdown(ctx context.Context) error {
 	var wsErr error
 
+	if f.watcher != nil {
+		f.watcher.Stop()
+	}
+	if f.mapper != nil {
+		f.mapper.Stop()
+	}
+
 	if f.primary != nil {
-		wsErr = f.primary.Close()
+		wsErr = f.primary.Shutdown(ctx)
 	}
 
 	// El cliente REST no necesita cierre explícito
@@ -340,10 +545,25 @@ func (f *FallbackExchange) Close() error {
 		return fmt.Errorf("error closing WebSocket client: %w", wsErr)
 	}
 
-	logging.Info(context.Background(), "FallbackExchange closed successfully", nil)
+	logging.Info(ctx, "FallbackExchange closed successfully", nil)
 	return nil
 }
 
+// SystemStatus implementa interfaces.SystemStatusReporter con el estado que
+// Kraken publica por WebSocket
+func (f *FallbackExchange) SystemStatus() string {
+	if f.status == nil {
+		return ""
+	}
+	return f.status.SystemStatus()
+}
+
+// inMaintenance indica si Kraken informó mantenimiento: las consultas van sólo a
+// REST en lugar de esperar el timeout de cada intento WebSocket
+func (f *FallbackExchange) inMaintenance() bool {
+	return f.SystemStatus() == kraken.SystemStatusMaintenance
+}
+
 // GetPrimaryStatus retorna el estado real de la conexión WebSocket primaria
 func (f *FallbackExchange) GetPrimaryStatus() bool {
 	if f.primary == nil {
@@ -352,29 +572,83 @@ func (f *FallbackExchange) GetPrimaryStatus() bool {
 	return f.primary.IsConnected()
 }
 
-// ForceWebSocketReconnect fuerza una reconexión del WebSocket (útil para testing/debugging)
-func (f *FallbackExchange) ForceWebSocketReconnect() error {
+// Connections implementa interfaces.ConnectionPool con el estado de cada shard del WebSocket
+func (f *FallbackExchange) Connections() []interfaces.ConnectionStatus {
+	if f.primary == nil {
+		return nil
+	}
+	shards := f.primary.Shards()
+	connections := make([]interfaces.ConnectionStatus, len(shards))
+	for i, shard := range shards {
+		connections[i] = interfaces.ConnectionStatus{
+			ID:                strconv.Itoa(shard.Index),
+			Pairs:             len(shard.Pairs),
+			Connected:         shard.Connected,
+			Reconnecting:      shard.Reconnecting,
+			ReconnectAttempts: shard.ReconnectAttempts,
+			Confirmed:         shard.Confirmed,
+			Pending:           shard.Pending,
+		}
+	}
+	return connections
+}
+
+// IsConnected implementa interfaces.Reconnectable reportando el estado del WebSocket primario
+func (f *FallbackExchange) IsConnected() bool {
+	return f.GetPrimaryStatus()
+}
+
+// Reconnect implementa interfaces.Reconnectable cerrando y reabriendo el WebSocket;
+// mientras tanto las consultas caen a REST
+func (f *FallbackExchange) Reconnect(ctx context.Context) error {
 	metrics.RecordWebSocketReconnectionAttempt("manual")
 	metrics.UpdateWebSocketConnectionStatus(false)
 
-	logging.Info(context.Background(), "Forcing WebSocket reconnection", logging.Fields{
+	logging.Info(ctx, "Forcing WebSocket reconnection", logging.Fields{
 		"websocket_url": f.config.WebSocketURL,
 	})
+	started := time.Now()
+	confirmed, pending := f.primary.GetSubscriptionStatus()
 
 	if err := f.primary.Close(); err != nil {
-		logging.Warn(context.Background(), "Error closing WebSocket during forced reconnect", logging.Fields{
+		logging.Warn(ctx, "Error closing WebSocket during forced reconnect", logging.Fields{
 			"error": err.Error(),
 		})
 	}
 
-	err := f.primary.Connect()
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		timeout := f.config.FallbackTimeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := f.primary.ConnectContext(ctx)
 	if err == nil {
 		metrics.UpdateWebSocketConnectionStatus(true)
 	}
+	f.auditReconnect(kraken.ReconnectEvent{
+		Started:  started,
+		Duration: time.Since(started),
+		Reason:   reconnectReasonForced,
+		Attempt:  1,
+		Pairs:    append(confirmed, pending...),
+		Err:      err,
+	})
 
 	return err
 }
 
+// ForceWebSocketReconnect fuerza una reconexión del WebSocket (útil para testing/debugging)
+//
+// Deprecated: usar Reconnect
+func (f *FallbackExchange) ForceWebSocketReconnect() error {
+	return f.Reconnect(context.Background())
+}
+
 // GetConfig retorna la configuración actual (útil para debugging/monitoring)
 func (f *FallbackExchange) GetConfig() config.KrakenConfig {
 	return f.config
@@ -385,66 +659,212 @@ func (f *FallbackExchange) WarmupTickers(ctx context.Context, pairs []string) ([
 	return f.secondary.GetTickers(ctx, pairs)
 }
 
+// SetLeaderElector restringe los jobs de fondo (staleness watcher) a la réplica líder
+func (f *FallbackExchange) SetLeaderElector(elector interfaces.LeaderElector) {
+	f.leader = elector
+}
+
+// SetAuditRecorder registra las activaciones del fallback y las reconexiones del
+// WebSocket en recorder
+func (f *FallbackExchange) SetAuditRecorder(recorder interfaces.AuditRecorder) {
+	f.audit = recorder
+}
+
+// isLeader retorna true si no hay elector configurado o si esta réplica es líder
+func (f *FallbackExchange) isLeader() bool {
+	return f.leader == nil || f.leader.IsLeader()
+}
+
+// SetMaintenanceCheck espacia los refrescos del staleness watcher a interval y
+// silencia sus avisos mientras inMaintenance reporte una ventana activa
+func (f *FallbackExchange) SetMaintenanceCheck(inMaintenance func() bool, interval time.Duration) {
+	f.watcher.SetMaintenanceCheck(inMaintenance, interval)
+}
+
+// SetPriceValidator valida los ticks del WebSocket antes de cachearlos
+func (f *FallbackExchange) SetPriceValidator(validator interfaces.PriceValidator) {
+	f.primary.SetPriceValidator(validator)
+}
+
+// InvalidatePrices implementa interfaces.PriceCacheInvalidator purgando la caché
+// local del WebSocket, para que el próximo refresco no reutilice el dato invalidado
+func (f *FallbackExchange) InvalidatePrices(ctx context.Context, pairs []string) error {
+	cache := f.primary.GetPriceCache()
+	if cache == nil {
+		return nil
+	}
+	return cache.Delete(ctx, pairs...)
+}
+
+// FlushPrices implementa interfaces.PriceCacheInvalidator vaciando la caché del WebSocket
+func (f *FallbackExchange) FlushPrices(ctx context.Context) (int, error) {
+	cache := f.primary.GetPriceCache()
+	if cache == nil {
+		return 0, nil
+	}
+	return cache.Flush(ctx)
+}
+
+// PrunePairs implementa interfaces.PairPruner: cancela la suscripción WebSocket de
+// los pares y los excluye del staleness watcher hasta que se vuelvan a pedir con
+// GetTicker/GetTickers, que los resuscriben
+func (f *FallbackExchange) PrunePairs(ctx context.Context, pairs []string) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	f.pruned.add(pairs...)
+	if err := f.primary.UnsubscribeTicker(pairs); err != nil {
+		return fmt.Errorf("failed to unsubscribe idle pairs: %w", err)
+	}
+	logging.Info(ctx, "Unsubscribed idle pairs", logging.Fields{
+		"pairs": pairs,
+	})
+	return nil
+}
+
+// revive vuelve a suscribir los pares podados que se piden de nuevo. Un fallo no
+// impide responder: el precio sigue llegando por caché o por REST
+func (f *FallbackExchange) revive(ctx context.Context, pairs ...string) {
+	revived := f.pruned.remove(pairs...)
+	if len(revived) == 0 {
+		return
+	}
+	if err := f.primary.SubscribeTicker(revived); err != nil {
+		logging.Warn(ctx, "Failed to resubscribe pruned pairs", logging.Fields{
+			"pairs": revived,
+			"error": err.Error(),
+		})
+		return
+	}
+	logging.Info(ctx, "Resubscribed pruned pairs", logging.Fields{
+		"pairs": revived,
+	})
+}
+
+// PairMapper expone el mapeo dinámico de pares (nil si está deshabilitado)
+func (f *FallbackExchange) PairMapper() *kraken.PairMapper {
+	return f.mapper
+}
+
+// PairMetadata implementa interfaces.PairCatalog con los metadatos de AssetPairs
+// (si el mapeo dinámico está habilitado) y el estado de suscripción del WebSocket
+func (f *FallbackExchange) PairMetadata(pairs []string) []interfaces.PairMetadata {
+	confirmed, _ := f.primary.GetSubscriptionStatus()
+	metadata := make([]interfaces.PairMetadata, len(pairs))
+	for i, pair := range pairs {
+		base, quote, _ := entities.SplitPair(pair)
+		metadata[i] = interfaces.PairMetadata{
+			Pair:       pair,
+			Base:       base,
+			Quote:      quote,
+			Exchange:   entities.ExchangeKraken,
+			Subscribed: slices.Contains(confirmed, pair),
+		}
+		if info, ok := f.mapper.Info(pair); ok {
+			metadata[i].HasPrecision = true
+			metadata[i].PriceDecimals = info.PriceDecimals
+			metadata[i].LotDecimals = info.LotDecimals
+			metadata[i].Status = info.Status
+		}
+	}
+	return metadata
+}
+
+// PairSubscriptions implementa interfaces.SubscriptionReporter con el estado de
+// las suscripciones de todos los shards
+func (f *FallbackExchange) PairSubscriptions(pairs []string) []interfaces.PairSubscription {
+	confirmed, pending := f.primary.GetSubscriptionStatus()
+	failures := make(map[string]kraken.SubscriptionFailure)
+	for _, failure := range f.primary.SubscriptionFailures() {
+		failures[failure.Pair] = failure
+	}
+
+	subscriptions := make([]interfaces.PairSubscription, len(pairs))
+	for i, pair := range pairs {
+		subscription := interfaces.PairSubscription{Pair: pair, Status: interfaces.SubscriptionUnsubscribed}
+		switch failure, failed := failures[pair]; {
+		case failed:
+			subscription.Status = interfaces.SubscriptionRetrying
+			if failure.Excluded {
+				subscription.Status = interfaces.SubscriptionExcluded
+			}
+			subscription.Failures = failure.Failures
+			subscription.LastError = failure.LastError
+			subscription.LastFailureAt = failure.LastAt
+			subscription.RetryAt = failure.RetryAt
+		case slices.Contains(confirmed, pair):
+			subscription.Status = interfaces.SubscriptionConfirmed
+		case slices.Contains(pending, pair):
+			subscription.Status = interfaces.SubscriptionPending
+		}
+		subscriptions[i] = subscription
+	}
+	return subscriptions
+}
+
+// ResolvePair implementa interfaces.PairResolver con los pares de AssetPairs; sin
+// mapeo dinámico (kraken.dynamic_pairs) ningún par se resuelve
+func (f *FallbackExchange) ResolvePair(_ context.Context, pair string) (string, error) {
+	info, ok := f.mapper.Info(entities.CanonicalPair(pair))
+	if !ok {
+		return "", fmt.Errorf("%w: %s", interfaces.ErrPairNotListed, pair)
+	}
+	if info.Status != "" && info.Status != "online" {
+		return "", fmt.Errorf("%w: %s is %s", interfaces.ErrPairNotListed, info.Pair, info.Status)
+	}
+	return info.Pair, nil
+}
+
+// OnPrice registra un callback invocado con cada tick recibido por el WebSocket
+func (f *FallbackExchange) OnPrice(handler kraken.PriceHandler) (unregister func()) {
+	return f.primary.OnPrice(handler)
+}
+
 // Secondary expone el cliente REST secundario (solo lectura)
 func (f *FallbackExchange) Secondary() interfaces.Exchange {
 	return f.secondary
 }
 
-// startStalenessWatcher verifica cada 20s que la edad del precio no supere maxAge;
-// si sucede, actualiza vía REST y escribe en caché.
-func (f *FallbackExchange) startStalenessWatcher(pairs []string, maxAge time.Duration) {
-	ticker := time.NewTicker(20 * time.Second)
-	for {
-		select {
-		case <-ticker.C:
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			for _, pair := range pairs {
-				price, ok := f.primary.GetPriceCache().Get(ctx, pair)
-				if ok && time.Since(price.Timestamp) <= maxAge {
-					continue // todavía fresco
-				}
-				// fetch via REST
-				p, err := f.secondary.GetTicker(ctx, pair)
-				if err != nil {
-					logging.Warn(ctx, "Staleness watcher REST fetch failed", logging.Fields{"pair": pair, "error": err.Error()})
-					continue
-				}
-				_ = f.primary.GetPriceCache().Set(ctx, p)
-				logging.Debug(ctx, "Staleness watcher refreshed price", logging.Fields{"pair": pair})
-			}
-			cancel()
-		default:
-			// Add small delay to prevent busy waiting
-			time.Sleep(100 * time.Millisecond)
-		}
+// RecentFallbacks devuelve las últimas activaciones del fallback REST, de la más
+// reciente a la más antigua
+func (f *FallbackExchange) RecentFallbacks() []interfaces.FallbackEvent {
+	return f.history.recent()
+}
+
+// FeedQuality resume por par la calidad del feed WebSocket en la ventana móvil:
+// ticks por minuto, tasa de descarte, intervalo medio, último fallback y
+// divergencia contra REST
+func (f *FallbackExchange) FeedQuality(pairs []string) interfaces.FeedQualityReport {
+	if f.quality == nil {
+		return interfaces.FeedQualityReport{Window: DefaultFeedQualityWindow}
 	}
+	return f.quality.report(pairs, f.divergence)
 }
 
-// determineFallbackReason determines the reason for fallback based on error analysis
+// determineFallbackReason clasifica el error del WebSocket en la etiqueta reason de
+// las activaciones con errors.Is/As sobre los errores tipados, no sobre el mensaje.
+// Importa el orden: los errores envuelven a otros (el presupuesto agotado envuelve
+// context.DeadlineExceeded) y gana la primera causa que coincide.
 func (f *FallbackExchange) determineFallbackReason(err error) string {
 	if err == nil {
 		return "unknown"
 	}
 
-	errStr := strings.ToLower(err.Error())
-
-	// Analyze error message to determine reason
-	// Note: Order matters - more specific conditions should come first
-	if strings.Contains(errStr, "timeout") {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrWebSocketBudgetExhausted):
+		return "budget_exhausted"
+	case errors.Is(err, ErrWebSocketRetriesDenied):
+		return "max_retries"
+	case errors.Is(err, ErrWebSocketTimeout), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
 		return "timeout"
-	}
-	if strings.Contains(errStr, "closed") || strings.Contains(errStr, "disconnected") {
+	case errors.Is(err, kraken.ErrWebSocketClosed), errors.Is(err, net.ErrClosed):
 		return "connection_closed"
-	}
-	if strings.Contains(errStr, "connection") {
+	case errors.Is(err, kraken.ErrConnectionFailed), errors.As(err, &netErr):
 		return "connection_error"
-	}
-	if strings.Contains(errStr, "max retries") || strings.Contains(errStr, "retries") {
-		return "max_retries"
-	}
-	if strings.Contains(errStr, "panic") {
+	case errors.Is(err, ErrWebSocketPanic):
 		return "panic"
 	}
-
 	return "unknown_error"
 }
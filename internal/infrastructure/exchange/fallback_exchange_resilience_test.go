@@ -2,8 +2,11 @@ package exchange
 
 import (
 	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"strings"
 	"testing"
 	"time"
@@ -240,31 +243,51 @@ func TestFallbackExchange_DetermineFallbackReason(t *testing.T) {
 		err            error
 		expectedReason string
 	}{
+		{
+			name:           "Budget Exhausted",
+			err:            fmt.Errorf("%w for operation GetTicker: %w", ErrWebSocketBudgetExhausted, context.DeadlineExceeded),
+			expectedReason: "budget_exhausted",
+		},
 		{
 			name:           "Timeout Error",
-			err:            errors.New("WebSocket timeout after 5s"),
+			err:            fmt.Errorf("%w after 5s for operation: GetTicker", ErrWebSocketTimeout),
+			expectedReason: "timeout",
+		},
+		{
+			name:           "Context Deadline",
+			err:            fmt.Errorf("waiting for price update: %w", context.DeadlineExceeded),
 			expectedReason: "timeout",
 		},
 		{
 			name:           "Connection Error",
-			err:            errors.New("connection refused"),
+			err:            fmt.Errorf("%w: dial tcp: connection refused", kraken.ErrConnectionFailed),
 			expectedReason: "connection_error",
 		},
 		{
-			name:           "Max Retries Error",
-			err:            errors.New("max retries exceeded"),
+			name:           "Network Error",
+			err:            &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			expectedReason: "connection_error",
+		},
+		{
+			name:           "Retries Denied",
+			err:            fmt.Errorf("%w: %w", ErrWebSocketRetriesDenied, kraken.ErrConnectionFailed),
 			expectedReason: "max_retries",
 		},
 		{
 			name:           "Panic Recovery",
-			err:            errors.New("WebSocket panic recovered"),
+			err:            fmt.Errorf("%w: nil map", ErrWebSocketPanic),
 			expectedReason: "panic",
 		},
 		{
 			name:           "Connection Closed",
-			err:            errors.New("connection closed"),
+			err:            fmt.Errorf("%w: %w for pair BTC/USD", kraken.ErrConnectionFailed, kraken.ErrWebSocketClosed),
 			expectedReason: "connection_closed",
 		},
+		{
+			name:           "Message Text Is Ignored",
+			err:            errors.New("connection closed: timeout after max retries"),
+			expectedReason: "unknown_error",
+		},
 		{
 			name:           "Unknown Error",
 			err:            errors.New("some random error"),
@@ -1,8 +1,13 @@
 package exchange
 
 import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -141,3 +146,73 @@ func TestFallbackExchange_ConfigurationValues(t *testing.T) {
 		})
 	}
 }
+
+// ===== DEADLINE BUDGET =====
+
+func TestFallbackExchange_WebSocketRespectsFetchBudget(t *testing.T) {
+	exchange := &FallbackExchange{
+		config: config.KrakenConfig{FallbackTimeout: 10 * time.Second, MaxRetries: 3},
+	}
+	ctx := entities.WithFetchBudget(context.Background(), entities.FetchBudget{WebSocket: 100 * time.Millisecond})
+
+	start := time.Now()
+	_, _, err := exchange.tryWebSocketSingle(ctx, "BTC/USD", func(ctx context.Context) (*entities.Price, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second, "all attempts must fit in the WebSocket budget")
+}
+
+func TestFallbackExchange_PairMetadata(t *testing.T) {
+	assetPairs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","base":"XXBT","quote":"ZUSD","pair_decimals":1,"lot_decimals":8,"status":"online"}}}`))
+	}))
+	defer assetPairs.Close()
+	mapper := kraken.NewPairMapper(assetPairs.URL, time.Second)
+	require.NoError(t, mapper.Load(context.Background()))
+
+	exchange := &FallbackExchange{primary: kraken.NewShardedWebSocketClient(config.KrakenConfig{}), mapper: mapper}
+	metadata := exchange.PairMetadata([]string{"BTC/USD", "ETH/EUR"})
+
+	require.Len(t, metadata, 2)
+	assert.Equal(t, interfaces.PairMetadata{
+		Pair: "BTC/USD", Base: "BTC", Quote: "USD", Exchange: "kraken",
+		HasPrecision: true, PriceDecimals: 1, LotDecimals: 8, Status: "online",
+	}, metadata[0])
+	assert.Equal(t, interfaces.PairMetadata{Pair: "ETH/EUR", Base: "ETH", Quote: "EUR", Exchange: "kraken"}, metadata[1],
+		"pairs missing from AssetPairs have no precision")
+}
+
+// ===== ESTADO DEL EXCHANGE =====
+
+type fixedSystemStatus string
+
+func (s fixedSystemStatus) SystemStatus() string { return string(s) }
+
+func TestFallbackExchange_MaintenanceServesFromRESTOnly(t *testing.T) {
+	rest := &stubExchange{price: &entities.Price{Pair: "BTC/USD", Amount: 50000, Source: entities.PriceSourceREST}}
+	exchange := &FallbackExchange{
+		primary:    kraken.NewShardedWebSocketClient(config.KrakenConfig{}),
+		secondary:  rest,
+		config:     config.KrakenConfig{FallbackTimeout: 10 * time.Second, MaxRetries: 3},
+		divergence: NewDivergenceMonitor(5*time.Second, 1),
+		history:    newFallbackHistory(fallbackHistorySize),
+		status:     fixedSystemStatus(kraken.SystemStatusMaintenance),
+	}
+
+	start := time.Now()
+	price, err := exchange.GetTicker(context.Background(), "BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, 50000.0, price.Amount)
+	prices, err := exchange.GetTickers(context.Background(), []string{"BTC/USD"})
+	require.NoError(t, err)
+	assert.Len(t, prices, 1)
+	assert.Less(t, time.Since(start), time.Second, "no WebSocket attempts during maintenance")
+	assert.Empty(t, exchange.RecentFallbacks(), "REST-only is not a fallback activation")
+	assert.Equal(t, kraken.SystemStatusMaintenance, exchange.SystemStatus())
+
+	var reporter interfaces.SystemStatusReporter = &FallbackExchange{}
+	assert.Empty(t, reporter.SystemStatus(), "no status until Kraken reports one")
+}
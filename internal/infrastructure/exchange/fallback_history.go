@@ -0,0 +1,42 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"sync"
+)
+
+// fallbackHistorySize es la cantidad de activaciones del fallback que se conservan
+const fallbackHistorySize = 50
+
+// fallbackHistory guarda las últimas activaciones del fallback en un buffer circular
+type fallbackHistory struct {
+	mu     sync.Mutex
+	events []interfaces.FallbackEvent
+	next   int
+}
+
+func newFallbackHistory(size int) *fallbackHistory {
+	return &fallbackHistory{events: make([]interfaces.FallbackEvent, 0, size)}
+}
+
+func (h *fallbackHistory) record(event interfaces.FallbackEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.events) < cap(h.events) {
+		h.events = append(h.events, event)
+		return
+	}
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+}
+
+// recent devuelve los eventos de más reciente a más antiguo
+func (h *fallbackHistory) recent() []interfaces.FallbackEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	events := make([]interfaces.FallbackEvent, 0, len(h.events))
+	for i := len(h.events) - 1; i >= 0; i-- {
+		events = append(events, h.events[(h.next+i)%len(h.events)])
+	}
+	return events
+}
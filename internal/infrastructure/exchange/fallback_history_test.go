@@ -0,0 +1,23 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallbackHistory_KeepsNewestFirst(t *testing.T) {
+	history := newFallbackHistory(3)
+	assert.Empty(t, history.recent())
+
+	for _, reason := range []string{"a", "b", "c", "d", "e"} {
+		history.record(interfaces.FallbackEvent{Reason: reason})
+	}
+
+	var reasons []string
+	for _, event := range history.recent() {
+		reasons = append(reasons, event.Reason)
+	}
+	assert.Equal(t, []string{"e", "d", "c"}, reasons)
+}
@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/application/services"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
+	cachepkg "btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingREST cuenta las consultas REST y responde con un precio recién obtenido
+type countingREST struct {
+	calls atomic.Int32
+}
+
+func (s *countingREST) GetTickers(_ context.Context, pairs []string) ([]*entities.Price, error) {
+	s.calls.Add(1)
+	now := time.Now()
+	var prices []*entities.Price
+	for _, pair := range pairs {
+		// Como el header Date: el timestamp del exchange sólo tiene segundos
+		price := entities.NewPriceWithExchangeTime(pair, 50000, now.Truncate(time.Second), now)
+		price.Source = entities.PriceSourceREST
+		prices = append(prices, price)
+	}
+	return prices, nil
+}
+
+func (s *countingREST) GetTicker(ctx context.Context, pair string) (*entities.Price, error) {
+	prices, err := s.GetTickers(ctx, []string{pair})
+	if err != nil {
+		return nil, err
+	}
+	return prices[0], nil
+}
+
+func TestFallbackExchange_RefreshFromSourceSkipsWebSocketCache(t *testing.T) {
+	// max_age llega al servicio por type assertion: el decorador de requests
+	// lentas no debe ocultarlo
+	wrappers := map[string]func(interfaces.PriceService) interfaces.PriceService{
+		"price service": func(service interfaces.PriceService) interfaces.PriceService { return service },
+		"slow request logger": func(service interfaces.PriceService) interfaces.PriceService {
+			return services.NewSlowRequestLogger(service, time.Hour)
+		},
+	}
+	for name, wrap := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			rest := &countingREST{}
+			exchange := &FallbackExchange{
+				primary:    kraken.NewShardedWebSocketClient(config.KrakenConfig{}),
+				secondary:  rest,
+				config:     config.KrakenConfig{FallbackTimeout: 10 * time.Second, MaxRetries: 3},
+				divergence: NewDivergenceMonitor(5*time.Second, 1),
+				history:    newFallbackHistory(fallbackHistorySize),
+			}
+			// El último tick del WebSocket quedó en su caché hace 10s
+			tickTime := time.Now().Add(-10 * time.Second)
+			require.NoError(t, exchange.primary.GetPriceCache().Set(ctx, entities.NewPriceWithExchangeTime("BTC/USD", 49000, tickTime, tickTime)))
+
+			pairs := []string{"BTC/USD"}
+			service := wrap(services.NewPriceServiceWithTTL(exchange, cachepkg.NewMemoryCache(), time.Minute, pairs))
+			require.NoError(t, service.RefreshPrices(ctx, pairs))
+			price, err := service.GetLastPrice(ctx, "BTC/USD")
+			require.NoError(t, err)
+			assert.Equal(t, 49000.0, price.Amount, "a regular refresh is served by the WebSocket cache")
+			assert.Zero(t, rest.calls.Load())
+
+			source, ok := service.(interfaces.SourceRefresher)
+			require.True(t, ok)
+			require.NoError(t, source.RefreshPricesFromSource(ctx, pairs))
+			assert.Equal(t, int32(1), rest.calls.Load(), "the stale WebSocket cache is skipped")
+
+			price, err = service.GetLastPrice(ctx, "BTC/USD")
+			require.NoError(t, err)
+			assert.Equal(t, 50000.0, price.Amount)
+			assert.Less(t, price.Age, 500*time.Millisecond, "a fresh REST price is not aged by the second precision of Date")
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"sync"
+	"time"
+)
+
+// DefaultFeedQualityWindow es la ventana móvil de ticks y descartes del reporte de calidad
+const DefaultFeedQualityWindow = 5 * time.Minute
+
+// feedQuality acumula en memoria, por par, los ticks recibidos y descartados por
+// el WebSocket dentro de una ventana móvil y la última activación del fallback,
+// para GET /admin/feed-quality
+type feedQuality struct {
+	window  time.Duration
+	now     func() time.Time
+	started time.Time
+
+	mu    sync.Mutex
+	pairs map[string]*pairFeed
+}
+
+// pairFeed son los eventos de un par; ticks y drops están ordenados y se podan
+// al salir de la ventana
+type pairFeed struct {
+	ticks        []time.Time
+	drops        []time.Time
+	lastTick     time.Time
+	lastFallback time.Time
+}
+
+func newFeedQuality(window time.Duration) *feedQuality {
+	if window <= 0 {
+		window = DefaultFeedQualityWindow
+	}
+	return &feedQuality{
+		window:  window,
+		now:     time.Now,
+		started: time.Now(),
+		pairs:   make(map[string]*pairFeed),
+	}
+}
+
+// observeTick registra un tick de pair recibido o descartado; es un TickHandler
+func (q *feedQuality) observeTick(pair string, dropped bool) {
+	if q == nil {
+		return
+	}
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	feed := q.feed(pair)
+	if dropped {
+		feed.drops = append(prune(feed.drops, now.Add(-q.window)), now)
+		return
+	}
+	feed.ticks = append(prune(feed.ticks, now.Add(-q.window)), now)
+	feed.lastTick = now
+}
+
+// observeFallback registra una activación del fallback REST para pairs
+func (q *feedQuality) observeFallback(pairs []string, at time.Time) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, pair := range pairs {
+		if feed := q.feed(pair); at.After(feed.lastFallback) {
+			feed.lastFallback = at
+		}
+	}
+}
+
+// feed retorna los eventos de pair, creándolos si hace falta (requiere lock)
+func (q *feedQuality) feed(pair string) *pairFeed {
+	feed, ok := q.pairs[pair]
+	if !ok {
+		feed = &pairFeed{}
+		q.pairs[pair] = feed
+	}
+	return feed
+}
+
+// report calcula las estadísticas de pairs; divergence, si no es nil, aporta la
+// última divergencia entre fuentes
+func (q *feedQuality) report(pairs []string, divergence *DivergenceMonitor) interfaces.FeedQualityReport {
+	now := q.now()
+	cutoff := now.Add(-q.window)
+	// Recién arrancado el servicio la ventana todavía no está completa
+	span := min(q.window, now.Sub(q.started))
+
+	report := interfaces.FeedQualityReport{Window: q.window, Pairs: make([]interfaces.PairFeedQuality, 0, len(pairs))}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, pair := range pairs {
+		quality := interfaces.PairFeedQuality{Pair: pair}
+		if feed, ok := q.pairs[pair]; ok {
+			feed.ticks = prune(feed.ticks, cutoff)
+			feed.drops = prune(feed.drops, cutoff)
+			ticks, drops := len(feed.ticks), len(feed.drops)
+			if span > 0 {
+				quality.TicksPerMinute = float64(ticks) / span.Minutes()
+			}
+			if ticks > 0 {
+				quality.DropRate = min(1, float64(drops)/float64(ticks))
+			}
+			if ticks > 1 {
+				quality.AvgTickInterval = feed.ticks[ticks-1].Sub(feed.ticks[0]) / time.Duration(ticks-1)
+			}
+			quality.LastTick = feed.lastTick
+			quality.LastFallback = feed.lastFallback
+		}
+		quality.DivergencePercent, quality.DivergenceAt, _ = divergence.LastDivergence(pair)
+		report.Pairs = append(report.Pairs, quality)
+	}
+	return report
+}
+
+// prune descarta los eventos anteriores a cutoff
+func prune(events []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(events) && events[i].Before(cutoff) {
+		i++
+	}
+	return events[i:]
+}
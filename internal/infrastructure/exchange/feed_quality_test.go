@@ -0,0 +1,92 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFeedQuality(now *time.Time) *feedQuality {
+	q := newFeedQuality(time.Minute)
+	q.now = func() time.Time { return *now }
+	q.started = *now
+	return q
+}
+
+func TestFeedQuality_ReportsRollingStats(t *testing.T) {
+	now := time.Now()
+	q := newTestFeedQuality(&now)
+	start := now
+
+	// 4 ticks cada 10s y un descarte
+	for i := 0; i < 4; i++ {
+		q.observeTick("BTC/USD", false)
+		now = now.Add(10 * time.Second)
+	}
+	q.observeTick("BTC/USD", true)
+	q.observeFallback([]string{"BTC/USD"}, start.Add(5*time.Second))
+	now = start.Add(time.Minute)
+
+	report := q.report([]string{"BTC/USD", "ETH/USD"}, nil)
+	assert.Equal(t, time.Minute, report.Window)
+	require.Len(t, report.Pairs, 2)
+
+	btc := report.Pairs[0]
+	assert.Equal(t, "BTC/USD", btc.Pair)
+	assert.InDelta(t, 4, btc.TicksPerMinute, 1e-9)
+	assert.InDelta(t, 0.25, btc.DropRate, 1e-9)
+	assert.Equal(t, 10*time.Second, btc.AvgTickInterval)
+	assert.Equal(t, start.Add(30*time.Second), btc.LastTick)
+	assert.Equal(t, start.Add(5*time.Second), btc.LastFallback)
+
+	eth := report.Pairs[1]
+	assert.Equal(t, "ETH/USD", eth.Pair)
+	assert.Zero(t, eth.TicksPerMinute)
+	assert.Zero(t, eth.DropRate)
+	assert.True(t, eth.LastTick.IsZero())
+}
+
+func TestFeedQuality_PrunesOutsideWindow(t *testing.T) {
+	now := time.Now()
+	q := newTestFeedQuality(&now)
+	start := now
+
+	q.observeTick("BTC/USD", false)
+	q.observeTick("BTC/USD", true)
+	now = now.Add(90 * time.Second)
+	q.observeTick("BTC/USD", false)
+
+	btc := q.report([]string{"BTC/USD"}, nil).Pairs[0]
+	assert.InDelta(t, 1, btc.TicksPerMinute, 1e-9, "only the tick inside the window counts")
+	assert.Zero(t, btc.DropRate)
+	assert.Zero(t, btc.AvgTickInterval)
+	assert.Equal(t, start.Add(90*time.Second), btc.LastTick, "the last tick survives pruning")
+}
+
+func TestFeedQuality_PartialWindowAndDivergence(t *testing.T) {
+	now := time.Now()
+	q := newTestFeedQuality(&now)
+	divergence := newTestDivergenceMonitor(&now)
+
+	q.observeTick("BTC/USD", false)
+	q.observeTick("BTC/USD", false)
+	now = now.Add(30 * time.Second)
+	divergence.measured["BTC/USD"] = divergenceSample{percent: 0.5, at: now}
+
+	btc := q.report([]string{"BTC/USD"}, divergence).Pairs[0]
+	assert.InDelta(t, 4, btc.TicksPerMinute, 1e-9, "a service up for 30s reports over 30s")
+	assert.InDelta(t, 0.5, btc.DivergencePercent, 1e-9)
+	assert.Equal(t, now, btc.DivergenceAt)
+}
+
+func TestFallbackExchange_FeedQualityWithoutTracker(t *testing.T) {
+	var q *feedQuality
+	q.observeTick("BTC/USD", false) // no debe hacer panic
+	q.observeFallback([]string{"BTC/USD"}, time.Now())
+
+	report := (&FallbackExchange{}).FeedQuality([]string{"BTC/USD"})
+	assert.Equal(t, DefaultFeedQualityWindow, report.Window)
+	assert.Empty(t, report.Pairs)
+}
@@ -155,6 +155,35 @@ func BenchmarkMessageProcessing_TickerUpdate(b *testing.B) {
 	}
 }
 
+func BenchmarkMessageProcessing_TickerFrame(b *testing.B) {
+	client := &WebSocketClient{
+		subscriptions: map[string]bool{"BTC/USD": true},
+		priceChannels: map[string]chan *entities.Price{
+			"BTC/USD": make(chan *entities.Price, 1000),
+		},
+		cache: cachepkg.NewPriceCache(cachepkg.NewMemoryCache(), time.Minute),
+	}
+
+	// Frame completo del ticker v1 tal como llega por el socket
+	frame := []byte(`[340,{"a":["50001.00000",1,"1.000"],"b":["49999.00000",2,"2.000"],` +
+		`"c":["50000.00000","0.01"],"v":["100.1","200.2"],"p":["50000.1","50000.2"],` +
+		`"t":[10,20],"l":["49000.0","49000.0"],"h":["51000.0","51000.0"],"o":["49500.0","49500.0"]},"ticker","XBT/USD"]`)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := client.handleMessage(frame); err != nil {
+			b.Fatal(err)
+		}
+
+		select {
+		case <-client.priceChannels["BTC/USD"]:
+		default:
+		}
+	}
+}
+
 func BenchmarkJSONParsing_KrakenResponse(b *testing.B) {
 	responseJSON := `{
 		"error": [],
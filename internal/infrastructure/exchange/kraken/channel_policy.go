@@ -0,0 +1,113 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy define qué hacer cuando el canal de precios de un par está lleno
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest descarta el precio más antiguo del canal y encola el nuevo
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDropNewest descarta la actualización entrante
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowBlock espera hasta blockTimeout a que haya espacio y luego descarta
+	OverflowBlock OverflowPolicy = "block"
+
+	DefaultChannelCapacity      = 100
+	DefaultChannelBlockTimeout  = 50 * time.Millisecond
+	DefaultChannelDropWarnEvery = 100
+)
+
+// channelOptions configura capacidad y política de desborde de los canales por par.
+// El valor cero equivale a los defaults.
+type channelOptions struct {
+	capacity     int
+	policy       OverflowPolicy
+	blockTimeout time.Duration
+	warnEvery    int64
+}
+
+func (o channelOptions) withDefaults() channelOptions {
+	if o.capacity <= 0 {
+		o.capacity = DefaultChannelCapacity
+	}
+	if o.policy == "" {
+		o.policy = OverflowDropOldest
+	}
+	if o.blockTimeout <= 0 {
+		o.blockTimeout = DefaultChannelBlockTimeout
+	}
+	if o.warnEvery <= 0 {
+		o.warnEvery = DefaultChannelDropWarnEvery
+	}
+	return o
+}
+
+// dropCounters cuenta descartes por par para emitir warnings por umbral
+type dropCounters struct {
+	counts sync.Map // pair -> *atomic.Int64
+}
+
+func (d *dropCounters) inc(pair string) int64 {
+	v, _ := d.counts.LoadOrStore(pair, new(atomic.Int64))
+	return v.(*atomic.Int64).Add(1)
+}
+
+// deliver envía el precio al canal aplicando la política de desborde configurada
+func (k *WebSocketClient) deliver(priceChan chan *entities.Price, price *entities.Price) {
+	opts := k.chanOpts.withDefaults()
+
+	select {
+	case priceChan <- price:
+		return
+	default:
+	}
+
+	switch opts.policy {
+	case OverflowDropNewest:
+		// Se descarta la actualización entrante
+	case OverflowBlock:
+		timer := time.NewTimer(opts.blockTimeout)
+		defer timer.Stop()
+		select {
+		case priceChan <- price:
+			return
+		case <-timer.C:
+		case <-k.ctx.Done():
+			return
+		}
+	default: // OverflowDropOldest
+		select {
+		case <-priceChan:
+		default:
+		}
+		select {
+		case priceChan <- price:
+		default:
+		}
+	}
+
+	k.recordDrop(price.Pair, opts)
+}
+
+// recordDrop registra la métrica de descarte y avisa cada warnEvery descartes
+func (k *WebSocketClient) recordDrop(pair string, opts channelOptions) {
+	metrics.RecordWebSocketChannelDrop(pair, string(opts.policy))
+	k.notifyTick(pair, true)
+
+	if n := k.drops.inc(pair); n%opts.warnEvery == 0 {
+		logging.Warn(k.logContext(), "WebSocket price channel overflowing", logging.Fields{
+			"pair":        pair,
+			"policy":      string(opts.policy),
+			"capacity":    opts.capacity,
+			"total_drops": n,
+		})
+	}
+}
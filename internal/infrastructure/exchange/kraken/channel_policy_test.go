@@ -0,0 +1,65 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPrice(value float64) *entities.Price {
+	return entities.NewPrice("BTC/USD", value, time.Now(), 0)
+}
+
+func TestChannelOptions_Defaults(t *testing.T) {
+	opts := channelOptions{}.withDefaults()
+
+	assert.Equal(t, DefaultChannelCapacity, opts.capacity)
+	assert.Equal(t, OverflowDropOldest, opts.policy)
+	assert.Equal(t, DefaultChannelBlockTimeout, opts.blockTimeout)
+	assert.Equal(t, int64(DefaultChannelDropWarnEvery), opts.warnEvery)
+}
+
+func TestWebSocketClient_Deliver_OverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		expected float64 // precio que queda en el canal de capacidad 1
+	}{
+		{name: "drop oldest keeps newest", policy: OverflowDropOldest, expected: 2},
+		{name: "drop newest keeps oldest", policy: OverflowDropNewest, expected: 1},
+		{name: "block times out and keeps oldest", policy: OverflowBlock, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := createTestWebSocketClient("ws://localhost:9999")
+			client.chanOpts = channelOptions{capacity: 1, policy: tt.policy, blockTimeout: 5 * time.Millisecond}
+			ch := make(chan *entities.Price, 1)
+
+			client.deliver(ch, newTestPrice(1))
+			client.deliver(ch, newTestPrice(2))
+
+			assert.Len(t, ch, 1)
+			assert.Equal(t, tt.expected, (<-ch).Amount)
+			assert.Equal(t, int64(2), client.drops.inc("BTC/USD"), "one drop should have been recorded")
+		})
+	}
+}
+
+func TestWebSocketClient_Deliver_BlockSucceedsWhenDrained(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.chanOpts = channelOptions{capacity: 1, policy: OverflowBlock, blockTimeout: time.Second}
+	ch := make(chan *entities.Price, 1)
+	client.deliver(ch, newTestPrice(1))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-ch
+	}()
+	client.deliver(ch, newTestPrice(2))
+
+	assert.Equal(t, 2.0, (<-ch).Amount)
+	assert.Equal(t, int64(1), client.drops.inc("BTC/USD"), "no drops expected")
+}
@@ -0,0 +1,225 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEgressResolveInterval es cada cuánto se vuelven a resolver los hosts con pin_dns
+const DefaultEgressResolveInterval = 5 * time.Minute
+
+// ErrEgressBlocked indica que ninguna dirección del host está en la allowlist de salida
+var ErrEgressBlocked = errors.New("outbound connection blocked by egress allowlist")
+
+// egressDialer abre las conexiones salientes a Kraken sólo hacia direcciones
+// permitidas. Los hosts con pin se conectan a sus IPs sin consultar DNS; con
+// pinDNS los demás se resuelven una vez y se reutilizan hasta interval.
+type egressDialer struct {
+	dialer   net.Dialer
+	allowed  []netip.Prefix
+	pinned   map[string][]netip.Addr
+	pinDNS   bool
+	interval time.Duration
+	lookup   func(ctx context.Context, host string) ([]netip.Addr, error)
+	now      func() time.Time
+
+	mu       sync.Mutex
+	resolved map[string]resolvedHost
+}
+
+// resolvedHost son las direcciones de un host con pinDNS y cuándo se resolvieron
+type resolvedHost struct {
+	addrs []netip.Addr
+	at    time.Time
+}
+
+// newEgressDialer crea el dialer de cfg; sin restricciones configuradas retorna
+// nil y las conexiones usan el dialer por defecto. cfg ya fue validada.
+func newEgressDialer(cfg config.EgressConfig) *egressDialer {
+	if !cfg.Enabled() {
+		return nil
+	}
+	interval := cfg.ResolveInterval
+	if interval <= 0 {
+		interval = DefaultEgressResolveInterval
+	}
+	d := &egressDialer{
+		dialer:   net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+		pinned:   make(map[string][]netip.Addr, len(cfg.Pins)),
+		pinDNS:   cfg.PinDNS,
+		interval: interval,
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+		now:      time.Now,
+		resolved: make(map[string]resolvedHost),
+	}
+	for _, entry := range cfg.AllowedCIDRs {
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			d.allowed = append(d.allowed, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			d.allowed = append(d.allowed, prefix.Masked())
+		}
+	}
+	for _, pin := range cfg.Pins {
+		host := strings.ToLower(pin.Host)
+		for _, ip := range pin.IPs {
+			if addr, err := netip.ParseAddr(ip); err == nil {
+				d.pinned[host] = append(d.pinned[host], addr.Unmap())
+			}
+		}
+	}
+	return d
+}
+
+// DialContext conecta a address probando en orden sus direcciones permitidas
+func (d *egressDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := d.addresses(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]netip.Addr, 0, len(addrs))
+	for _, addr := range addrs {
+		if d.isAllowed(addr) {
+			allowed = append(allowed, addr)
+		}
+	}
+	if len(allowed) == 0 {
+		metrics.RecordKrakenEgressBlocked(host)
+		logging.Warn(ctx, "Blocked outbound connection outside the egress allowlist", logging.Fields{
+			"host":      host,
+			"addresses": addrStrings(addrs),
+		})
+		return nil, fmt.Errorf("%w: %s resolves to %v", ErrEgressBlocked, host, addrStrings(addrs))
+	}
+
+	var dialErr error
+	for _, addr := range allowed {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, dialErr
+}
+
+// addresses resuelve host según los pines; una IP literal se usa tal cual
+func (d *egressDialer) addresses(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr.Unmap()}, nil
+	}
+	host = strings.ToLower(host)
+	if pinned, ok := d.pinned[host]; ok {
+		return pinned, nil
+	}
+	if !d.pinDNS {
+		return d.resolve(ctx, host)
+	}
+
+	d.mu.Lock()
+	previous, ok := d.resolved[host]
+	d.mu.Unlock()
+	if ok && d.now().Sub(previous.at) < d.interval {
+		return previous.addrs, nil
+	}
+
+	addrs, err := d.resolve(ctx, host)
+	if err != nil {
+		if !ok {
+			return nil, err
+		}
+		// Se conservan las direcciones anteriores hasta el próximo intento
+		logging.Warn(ctx, "Failed to re-resolve pinned Kraken host, keeping previous addresses", logging.Fields{
+			"host":      host,
+			"addresses": addrStrings(previous.addrs),
+			"error":     err.Error(),
+		})
+		addrs = previous.addrs
+	} else if ok && !sameAddrs(previous.addrs, addrs) {
+		logging.Info(ctx, "Pinned Kraken host resolved to new addresses", logging.Fields{
+			"host":     host,
+			"previous": addrStrings(previous.addrs),
+			"current":  addrStrings(addrs),
+		})
+	}
+
+	d.mu.Lock()
+	d.resolved[host] = resolvedHost{addrs: addrs, at: d.now()}
+	d.mu.Unlock()
+	return addrs, nil
+}
+
+// expire vence las resoluciones de pin_dns para que la próxima conexión a cada
+// host lo resuelva de nuevo; si esa resolución falla se conservan las anteriores
+func (d *egressDialer) expire() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for host, previous := range d.resolved {
+		previous.at = time.Time{}
+		d.resolved[host] = previous
+	}
+}
+
+func (d *egressDialer) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for i, addr := range addrs {
+		addrs[i] = addr.Unmap()
+	}
+	return addrs, nil
+}
+
+// isAllowed indica si addr está en la allowlist; sin allowlist todo está permitido
+func (d *egressDialer) isAllowed(addr netip.Addr) bool {
+	if len(d.allowed) == 0 {
+		return true
+	}
+	for _, prefix := range d.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameAddrs compara dos resoluciones sin importar el orden, que el DNS rota
+func sameAddrs(a, b []netip.Addr) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.SortFunc(a, netip.Addr.Compare)
+	slices.SortFunc(b, netip.Addr.Compare)
+	return slices.Equal(a, b)
+}
+
+func addrStrings(addrs []netip.Addr) []string {
+	out := make([]string, len(addrs))
+	for i, addr := range addrs {
+		out[i] = addr.String()
+	}
+	return out
+}
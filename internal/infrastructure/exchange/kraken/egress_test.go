@@ -0,0 +1,141 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEgressDialer_DisabledWithoutRestrictions(t *testing.T) {
+	assert.Nil(t, newEgressDialer(config.EgressConfig{ResolveInterval: time.Minute}))
+	assert.Nil(t, newTransport(config.KrakenConfig{}).websocketDialer().NetDialContext, "default dialer without restrictions")
+}
+
+func TestEgressDialer_PinnedHostUsesPinnedIPs(t *testing.T) {
+	server := createMockServer(http.StatusOK, createMockKrakenResponse("XXBTZUSD", "50000.0"))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client := NewRestClientWithConfig(config.KrakenConfig{
+		RestURL:        "http://api.kraken.test:" + serverURL.Port(),
+		Timeout:        5 * time.Second,
+		RequestTimeout: 2 * time.Second,
+		Egress: config.EgressConfig{
+			AllowedCIDRs: []string{"127.0.0.0/8"},
+			Pins:         []config.EgressPin{{Host: "API.kraken.test", IPs: []string{"127.0.0.1"}}},
+		},
+	})
+
+	price, err := client.GetTicker(context.Background(), "BTC/USD")
+	require.NoError(t, err, "api.kraken.test only resolves through the pin")
+	assert.Equal(t, "BTC/USD", price.Pair)
+}
+
+func TestEgressDialer_BlocksAddressesOutsideAllowlist(t *testing.T) {
+	d := newEgressDialer(config.EgressConfig{AllowedCIDRs: []string{"10.0.0.0/8", "192.0.2.7"}})
+	d.lookup = func(context.Context, string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("203.0.113.9")}, nil
+	}
+
+	_, err := d.DialContext(context.Background(), "tcp", "api.kraken.com:443")
+	assert.ErrorIs(t, err, ErrEgressBlocked)
+	_, err = d.DialContext(context.Background(), "tcp", "127.0.0.1:443")
+	assert.ErrorIs(t, err, ErrEgressBlocked, "IP literals are checked too")
+
+	assert.True(t, d.isAllowed(netip.MustParseAddr("10.1.2.3")))
+	assert.True(t, d.isAllowed(netip.MustParseAddr("192.0.2.7")))
+	assert.False(t, d.isAllowed(netip.MustParseAddr("192.0.2.8")))
+}
+
+func TestEgressDialer_SkipsBlockedAddresses(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	d := newEgressDialer(config.EgressConfig{AllowedCIDRs: []string{"127.0.0.0/8"}})
+	d.lookup = func(context.Context, string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("203.0.113.9"), netip.MustParseAddr("127.0.0.1")}, nil
+	}
+
+	conn, err := d.DialContext(context.Background(), "tcp", "ws.kraken.com:"+port)
+	require.NoError(t, err)
+	_ = conn.Close()
+}
+
+func TestEgressDialer_PinDNSReresolvesAfterInterval(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	answers := [][]netip.Addr{{netip.MustParseAddr("192.0.2.1")}, nil, {netip.MustParseAddr("192.0.2.2")}}
+	lookups := 0
+
+	d := newEgressDialer(config.EgressConfig{PinDNS: true, ResolveInterval: time.Minute})
+	d.now = func() time.Time { return now }
+	d.lookup = func(context.Context, string) ([]netip.Addr, error) {
+		answer := answers[lookups]
+		lookups++
+		if answer == nil {
+			return nil, errors.New("dns unavailable")
+		}
+		return answer, nil
+	}
+	resolve := func() []netip.Addr {
+		addrs, err := d.addresses(context.Background(), "api.kraken.com")
+		require.NoError(t, err)
+		return addrs
+	}
+
+	assert.Equal(t, answers[0], resolve())
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, answers[0], resolve(), "pinned until resolve_interval")
+	assert.Equal(t, 1, lookups)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, answers[0], resolve(), "a failed re-resolution keeps the previous addresses")
+	assert.Equal(t, answers[0], resolve(), "and waits another interval to retry")
+	assert.Equal(t, 2, lookups)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, answers[2], resolve())
+	assert.Equal(t, 3, lookups)
+}
+
+func TestEgressDialer_ExpireReresolvesBeforeInterval(t *testing.T) {
+	answers := [][]netip.Addr{{netip.MustParseAddr("192.0.2.1")}, nil, {netip.MustParseAddr("192.0.2.2")}}
+	lookups := 0
+
+	d := newEgressDialer(config.EgressConfig{PinDNS: true, ResolveInterval: time.Hour})
+	d.lookup = func(context.Context, string) ([]netip.Addr, error) {
+		answer := answers[lookups]
+		lookups++
+		if answer == nil {
+			return nil, errors.New("dns unavailable")
+		}
+		return answer, nil
+	}
+	resolve := func() []netip.Addr {
+		addrs, err := d.addresses(context.Background(), "ws.kraken.com")
+		require.NoError(t, err)
+		return addrs
+	}
+
+	assert.Equal(t, answers[0], resolve())
+	d.expire()
+	assert.Equal(t, answers[0], resolve(), "a failed re-resolution keeps the previous addresses")
+	d.expire()
+	assert.Equal(t, answers[2], resolve())
+	assert.Equal(t, answers[2], resolve(), "pinned again until resolve_interval")
+	assert.Equal(t, 3, lookups)
+
+	var disabled *egressDialer
+	disabled.expire()
+}
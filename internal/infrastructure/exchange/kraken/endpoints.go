@@ -0,0 +1,170 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultEndpointCooldown es el tiempo que una URL que falló queda excluida de la selección
+	DefaultEndpointCooldown = 30 * time.Second
+
+	// endpointLatencyWeight es el peso de cada medición en la latencia promedio de una URL
+	endpointLatencyWeight = 0.3
+)
+
+// endpointSet elige, entre las URLs configuradas de REST o del WebSocket, la de
+// menor latencia entre las sanas. La latencia es un promedio móvil exponencial
+// de los requests (REST) o de los dials (WebSocket); las URLs todavía sin medir
+// se prefieren para obtener su medición. Una falla excluye la URL durante
+// cooldown; si todas están excluidas se usa la que antes vuelva a estar
+// disponible. Un endpointSet nil usa siempre la URL por defecto del cliente.
+type endpointSet struct {
+	kind     string // "rest" o "websocket", para los logs
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	endpoints []*endpointState
+	selected  string // Última URL elegida, para loguear los cambios
+}
+
+// endpointState es la latencia medida y el estado de una URL
+type endpointState struct {
+	url       string
+	latency   time.Duration // 0 = sin medir
+	downUntil time.Time
+}
+
+// newEndpointSet crea la selección sobre primary y las URLs alternativas; con una
+// única URL distinta retorna nil y el cliente la usa directamente
+func newEndpointSet(kind, primary string, alternatives []string, cooldown time.Duration) *endpointSet {
+	if cooldown <= 0 {
+		cooldown = DefaultEndpointCooldown
+	}
+	e := &endpointSet{kind: kind, cooldown: cooldown, now: time.Now}
+	seen := make(map[string]bool)
+	for _, url := range append([]string{primary}, alternatives...) {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		e.endpoints = append(e.endpoints, &endpointState{url: url})
+	}
+	if len(e.endpoints) < 2 {
+		return nil
+	}
+	return e
+}
+
+// candidates retorna las URLs en orden de preferencia: las sanas sin medir, las
+// sanas de menor a mayor latencia y las excluidas según cuándo vuelven. Sin
+// endpointSet retorna sólo fallback.
+func (e *endpointSet) candidates(fallback string) []string {
+	if e == nil {
+		return []string{fallback}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	ordered := append([]*endpointState(nil), e.endpoints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		aDown, bDown := now.Before(a.downUntil), now.Before(b.downUntil)
+		switch {
+		case aDown != bDown:
+			return !aDown
+		case aDown:
+			return a.downUntil.Before(b.downUntil)
+		}
+		return a.latency < b.latency
+	})
+
+	urls := make([]string, len(ordered))
+	for i, endpoint := range ordered {
+		urls[i] = endpoint.url
+	}
+	return urls
+}
+
+// pick retorna la URL preferida y loguea cuando cambia respecto de la anterior
+func (e *endpointSet) pick(ctx context.Context, fallback string) string {
+	url := e.candidates(fallback)[0]
+	if e == nil {
+		return url
+	}
+
+	e.mu.Lock()
+	previous := e.selected
+	e.selected = url
+	e.mu.Unlock()
+	if previous != "" && previous != url {
+		logging.Info(ctx, "Kraken endpoint switched", logging.Fields{
+			"kind":     e.kind,
+			"url":      url,
+			"previous": previous,
+		})
+	}
+	return url
+}
+
+// observe registra un request o dial exitoso contra url y la vuelve a habilitar
+func (e *endpointSet) observe(url string, latency time.Duration) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	endpoint := e.find(url)
+	if endpoint == nil {
+		return
+	}
+	if latency <= 0 {
+		latency = time.Microsecond
+	}
+	if endpoint.latency == 0 {
+		endpoint.latency = latency
+	} else {
+		endpoint.latency = time.Duration(endpointLatencyWeight*float64(latency) + (1-endpointLatencyWeight)*float64(endpoint.latency))
+	}
+	endpoint.downUntil = time.Time{}
+}
+
+// fail excluye url de la selección durante el cooldown
+func (e *endpointSet) fail(ctx context.Context, url string, err error) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	endpoint := e.find(url)
+	if endpoint == nil {
+		e.mu.Unlock()
+		return
+	}
+	alreadyDown := e.now().Before(endpoint.downUntil)
+	endpoint.downUntil = e.now().Add(e.cooldown)
+	e.mu.Unlock()
+
+	if !alreadyDown {
+		logging.Warn(ctx, "Kraken endpoint failed, excluded from selection", logging.Fields{
+			"kind":     e.kind,
+			"url":      url,
+			"cooldown": e.cooldown.String(),
+			"error":    err.Error(),
+		})
+	}
+}
+
+// find retorna el estado de url (requiere lock)
+func (e *endpointSet) find(url string) *endpointState {
+	for _, endpoint := range e.endpoints {
+		if endpoint.url == url {
+			return endpoint
+		}
+	}
+	return nil
+}
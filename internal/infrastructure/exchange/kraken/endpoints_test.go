@@ -0,0 +1,79 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointSet_PrefersFastestHealthyEndpoint(t *testing.T) {
+	assert.Nil(t, newEndpointSet("rest", "https://a", []string{"https://a", ""}, 0), "a single distinct URL needs no selection")
+	var none *endpointSet
+	assert.Equal(t, []string{"https://a"}, none.candidates("https://a"))
+
+	now := time.Now()
+	set := newEndpointSet("rest", "https://a", []string{"https://b", "https://c"}, time.Minute)
+	set.now = func() time.Time { return now }
+	assert.Equal(t, []string{"https://a", "https://b", "https://c"}, set.candidates(""), "unmeasured endpoints keep the configured order")
+
+	set.observe("https://a", 80*time.Millisecond)
+	set.observe("https://b", 20*time.Millisecond)
+	assert.Equal(t, []string{"https://c", "https://b", "https://a"}, set.candidates(""), "unmeasured endpoints are tried before measured ones")
+
+	set.observe("https://c", 50*time.Millisecond)
+	assert.Equal(t, "https://b", set.pick(context.Background(), ""))
+
+	set.fail(context.Background(), "https://b", errors.New("connection refused"))
+	now = now.Add(time.Second)
+	set.fail(context.Background(), "https://c", errors.New("connection refused"))
+	assert.Equal(t, []string{"https://a", "https://b", "https://c"}, set.candidates(""), "failed endpoints go last, the first to recover leading")
+
+	set.fail(context.Background(), "https://a", errors.New("connection refused"))
+	assert.Equal(t, "https://b", set.pick(context.Background(), ""), "with every endpoint down the first to recover is used")
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, "https://b", set.pick(context.Background(), ""), "the cooldown ends and the fastest is used again")
+
+	set.observe("https://b", 200*time.Millisecond)
+	set.observe("https://b", 200*time.Millisecond)
+	assert.Equal(t, "https://c", set.pick(context.Background(), ""), "a slower average moves the selection")
+}
+
+func TestRestClient_FailsOverToHealthyEndpoint(t *testing.T) {
+	broken := createMockServer(http.StatusBadGateway, nil)
+	defer broken.Close()
+	mirror := createMockServer(http.StatusOK, createMockKrakenResponse("XXBTZUSD", "50000.0"))
+	defer mirror.Close()
+
+	client := NewRestClientWithConfig(config.KrakenConfig{
+		RestURL:  broken.URL,
+		RestURLs: []string{mirror.URL},
+		Timeout:  5 * time.Second,
+	})
+
+	price, err := client.GetTicker(context.Background(), "BTC/USD")
+	require.NoError(t, err, "the retry goes to the mirror")
+	assert.Equal(t, 50000.0, price.Amount)
+	assert.Equal(t, []string{mirror.URL, broken.URL}, client.endpoints.candidates(""))
+}
+
+func TestWebSocketClient_DialsNextEndpointOnFailure(t *testing.T) {
+	server := newMockWebSocketServer()
+	defer server.close()
+
+	client := newWebSocketClient(config.KrakenConfig{
+		WebSocketURL:  "ws://127.0.0.1:1",
+		WebSocketURLs: []string{server.getURL()},
+	}, newClientPriceCache(config.KrakenConfig{}))
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.ConnectContext(context.Background()))
+	assert.Equal(t, server.getURL(), client.currentURL())
+	assert.Equal(t, []string{server.getURL(), "ws://127.0.0.1:1"}, client.endpoints.candidates(""))
+}
@@ -10,4 +10,6 @@ var (
 	ErrWebSocketClosed   = errors.New("websocket connection closed")
 	ErrRetryableRequest  = errors.New("retryable kraken API request failed")
 	ErrNonRetryable      = errors.New("non-retryable kraken API error")
+	// ErrSubscriptionExcluded indica un par excluido del WebSocket por errores de suscripción persistentes
+	ErrSubscriptionExcluded = errors.New("pair excluded from websocket after repeated subscription errors")
 )
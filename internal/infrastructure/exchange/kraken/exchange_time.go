@@ -0,0 +1,50 @@
+package kraken
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// exchangeTimeFromHeader obtiene la hora del servidor de Kraken desde el header
+// Date de la respuesta REST (precisión de segundos). Retorna cero si no existe.
+func exchangeTimeFromHeader(header http.Header) time.Time {
+	value := header.Get("Date")
+	if value == "" {
+		return time.Time{}
+	}
+	parsed, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// parseExchangeTimestamp interpreta un timestamp de un mensaje WebSocket: RFC3339
+// (API v2) o segundos Unix con decimales, como string o número (API v1).
+func parseExchangeTimestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return parsed, true
+		}
+		seconds, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return unixSecondsToTime(seconds)
+	case float64:
+		return unixSecondsToTime(v)
+	default:
+		return time.Time{}, false
+	}
+}
+
+func unixSecondsToTime(seconds float64) (time.Time, bool) {
+	if seconds <= 0 {
+		return time.Time{}, false
+	}
+	sec := int64(seconds)
+	nsec := int64((seconds - float64(sec)) * float64(time.Second))
+	return time.Unix(sec, nsec), true
+}
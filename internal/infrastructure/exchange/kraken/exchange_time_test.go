@@ -0,0 +1,79 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExchangeTimestamp(t *testing.T) {
+	expected := time.Date(2026, 1, 2, 3, 4, 5, 500000000, time.UTC)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		ok    bool
+	}{
+		{name: "RFC3339", value: "2026-01-02T03:04:05.5Z", ok: true},
+		{name: "unix string", value: "1767323045.5", ok: true},
+		{name: "unix number", value: float64(1767323045.5), ok: true},
+		{name: "ausente", value: nil},
+		{name: "inválido", value: "yesterday"},
+		{name: "cero", value: float64(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, ok := parseExchangeTimestamp(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.WithinDuration(t, expected, parsed, time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestWebSocketClient_handleTickerUpdate_UsesExchangeTimestamp(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+
+	var received *entities.Price
+	client.OnPrice(func(p *entities.Price) { received = p })
+
+	exchangeTime := time.Now().Add(-2 * time.Second).UTC()
+	update := []interface{}{float64(1), map[string]interface{}{
+		"c":         []interface{}{"50000.5", "1"},
+		"timestamp": exchangeTime.Format(time.RFC3339Nano),
+	}, "ticker", "XBT/USD"}
+	require.NoError(t, client.handleTickerUpdate(update))
+
+	require.NotNil(t, received)
+	assert.True(t, received.ExchangeTime.Equal(exchangeTime))
+	assert.True(t, received.Timestamp.Equal(exchangeTime))
+	assert.False(t, received.ReceivedTime.Before(exchangeTime))
+	assert.GreaterOrEqual(t, received.Age, 2*time.Second)
+}
+
+func TestRestClient_GetTicker_UsesDateHeader(t *testing.T) {
+	serverTime := time.Now().Add(-3 * time.Second).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"c":["50000.1","1"]}}}`))
+	}))
+	defer server.Close()
+
+	client := NewRestClient()
+	client.baseURL = server.URL
+	price, err := client.GetTicker(t.Context(), "BTC/USD")
+	require.NoError(t, err)
+
+	assert.True(t, price.ExchangeTime.Equal(serverTime))
+	assert.False(t, price.ReceivedTime.IsZero())
+	// Date sólo tiene segundos: el precio es de algún momento de ese segundo
+	assert.GreaterOrEqual(t, price.Age, 2*time.Second)
+	assert.Less(t, price.Age, 3*time.Second)
+}
@@ -2,6 +2,7 @@ package kraken
 
 import (
 	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/config"
 	"btc-ltp-service/internal/infrastructure/logging"
 	"btc-ltp-service/internal/infrastructure/metrics"
@@ -10,7 +11,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/avast/retry-go/v4"
@@ -23,12 +27,25 @@ const (
 	MaxRetries       = 3               // Maximum retry attempts
 	BaseBackoff      = 100 * time.Millisecond
 	MaxBackoff       = 2 * time.Second
+
+	DefaultTickerBatchSize        = 20 // Pares por request /Ticker
+	DefaultTickerBatchConcurrency = 2  // Requests /Ticker simultáneos por GetTickers
+
+	// restTickerChannel es el Channel de los precios obtenidos por REST
+	restTickerChannel = "/Ticker"
 )
 
 // RestClient implementa la interfaz Exchange usando la API REST de Kraken
 type RestClient struct {
 	baseURL    string
+	endpoints  *endpointSet // Opcional: baseURL y las URLs alternativas, elegidas por latencia
 	httpClient *http.Client
+	mapper     atomic.Pointer[PairMapper] // Opcional: mapeo dinámico desde AssetPairs
+
+	batchSize        int           // Máximo de pares por request; 0 usa DefaultTickerBatchSize
+	batchConcurrency int           // Lotes en paralelo; 0 usa DefaultTickerBatchConcurrency
+	requestTimeout   time.Duration // Timeout de cada intento; 0 usa RequestTimeout
+	retryBudget      *RetryBudget  // Opcional: reintentos compartidos por GetTicker y GetTickers
 }
 
 // NewRestClient crea una nueva instancia del cliente REST de Kraken
@@ -44,26 +61,45 @@ func NewRestClient() *RestClient {
 // NewRestClientWithConfig crea una nueva instancia del cliente REST de Kraken con configuración
 func NewRestClientWithConfig(cfg config.KrakenConfig) *RestClient {
 	return &RestClient{
-		baseURL: cfg.RestURL,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+		baseURL:          cfg.RestURL,
+		endpoints:        newEndpointSet("rest", cfg.RestURL, cfg.RestURLs, cfg.EndpointCooldown),
+		httpClient:       newTransport(cfg).httpClient(cfg.Timeout),
+		batchSize:        cfg.RestBatchSize,
+		batchConcurrency: cfg.RestBatchConcurrency,
+		requestTimeout:   cfg.RequestTimeout,
+		retryBudget:      NewRetryBudget(entities.PriceSourceREST, cfg.RetryBudget),
 	}
 }
 
+// attemptTimeout es el timeout de cada intento de un request, dentro del timeout
+// total del cliente HTTP
+func (k *RestClient) attemptTimeout() time.Duration {
+	if k.requestTimeout <= 0 {
+		return RequestTimeout
+	}
+	return k.requestTimeout
+}
+
+// SetPairMapper habilita el mapeo dinámico de pares; nil vuelve a los mapas estáticos
+func (k *RestClient) SetPairMapper(mapper *PairMapper) {
+	k.mapper.Store(mapper)
+}
+
 // GetTicker obtiene el precio de un par específico con context y retry
 func (k *RestClient) GetTicker(ctx context.Context, pair string) (*entities.Price, error) {
-	krakenPair, err := toKrakenPair(pair)
+	krakenPair, err := k.mapper.Load().ToRest(pair)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert pair to kraken pair: %w", err)
 	}
 
 	var price *entities.Price
+	attempts := 0
 
 	retryErr := retry.Do(
 		func() error {
+			attempts++
 			// Create request context with timeout
-			reqCtx, cancel := context.WithTimeout(ctx, RequestTimeout)
+			reqCtx, cancel := context.WithTimeout(ctx, k.attemptTimeout())
 			defer cancel()
 
 			reqPrice, reqErr := k.doTickerRequest(reqCtx, krakenPair, pair)
@@ -78,13 +114,14 @@ func (k *RestClient) GetTicker(ctx context.Context, pair string) (*entities.Pric
 		retry.Delay(BaseBackoff),
 		retry.MaxDelay(MaxBackoff),
 		retry.DelayType(retry.BackOffDelay),
-		retry.RetryIf(k.isRetryableError),
+		retry.RetryIf(func(err error) bool { return k.retryAllowed(err, attempts) }),
 		retry.Context(ctx),
 		retry.OnRetry(func(n uint, err error) {
 			metrics.RecordExternalAPIRetry("kraken", "/Ticker", int(n+1))
+			entities.RequestTimingsFrom(ctx).Retry()
 
 			// Record specific metrics for 429 rate limiting
-			if strings.Contains(err.Error(), "HTTP 429") {
+			if errors.Is(err, interfaces.ErrRateLimited) {
 				metrics.RecordKrakenRateLimitDrop("/Ticker")
 				// Calculate backoff duration for this attempt
 				backoffDuration := time.Duration(n+1) * BaseBackoff
@@ -101,7 +138,7 @@ func (k *RestClient) GetTicker(ctx context.Context, pair string) (*entities.Pric
 				"max_attempts": MaxRetries,
 				"pair":         pair,
 				"error":        err.Error(),
-				"is_429":       strings.Contains(err.Error(), "HTTP 429"),
+				"is_429":       errors.Is(err, interfaces.ErrRateLimited),
 			})
 		}),
 	)
@@ -109,13 +146,30 @@ func (k *RestClient) GetTicker(ctx context.Context, pair string) (*entities.Pric
 	if retryErr != nil {
 		return nil, fmt.Errorf("failed to get ticker after retries for %s: %w", pair, retryErr)
 	}
+	k.retryBudget.OnSuccess()
 
 	return price, nil
 }
 
+// observeEndpoint registra el resultado de un request contra baseURL: los
+// errores de red, los timeouts y los 5xx la excluyen de la selección; cualquier
+// otra respuesta actualiza su latencia
+func (k *RestClient) observeEndpoint(ctx context.Context, baseURL string, resp *http.Response, err error, latency time.Duration) {
+	switch {
+	case errors.Is(err, context.Canceled):
+	case err != nil:
+		k.endpoints.fail(ctx, baseURL, err)
+	case resp.StatusCode >= 500:
+		k.endpoints.fail(ctx, baseURL, fmt.Errorf("HTTP %d", resp.StatusCode))
+	default:
+		k.endpoints.observe(baseURL, latency)
+	}
+}
+
 // doTickerRequest performs the actual HTTP request for a single ticker
 func (k *RestClient) doTickerRequest(ctx context.Context, krakenPair, originalPair string) (*entities.Price, error) {
-	url := fmt.Sprintf("%s/Ticker?pair=%s", k.baseURL, krakenPair)
+	baseURL := k.endpoints.pick(ctx, k.baseURL)
+	url := fmt.Sprintf("%s/Ticker?pair=%s", baseURL, krakenPair)
 
 	logging.Debug(ctx, "Making request to Kraken API", logging.Fields{
 		"url":         url,
@@ -131,6 +185,7 @@ func (k *RestClient) doTickerRequest(ctx context.Context, krakenPair, originalPa
 	requestStart := time.Now()
 	resp, err := k.httpClient.Do(req)
 	requestDuration := time.Since(requestStart)
+	k.observeEndpoint(ctx, baseURL, resp, err, requestDuration)
 
 	if err != nil {
 		logging.ErrorWithError(ctx, "Kraken API request failed", err, logging.Fields{
@@ -157,7 +212,7 @@ func (k *RestClient) doTickerRequest(ctx context.Context, krakenPair, originalPa
 	// CRITICAL FIX: Handle HTTP 429 (Too Many Requests) as retryable
 	if resp.StatusCode == http.StatusTooManyRequests {
 		metrics.RecordExternalAPICall("kraken", "/Ticker", resp.StatusCode, float64(requestDuration.Nanoseconds())/1e6)
-		return nil, fmt.Errorf("%w: HTTP %d (rate limited by kraken)", ErrRetryableRequest, resp.StatusCode)
+		return nil, fmt.Errorf("%w: %w: HTTP %d", ErrRetryableRequest, interfaces.ErrRateLimited, resp.StatusCode)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -174,19 +229,26 @@ func (k *RestClient) doTickerRequest(ctx context.Context, krakenPair, originalPa
 		return nil, fmt.Errorf("%w: %s", ErrNonRetryable, strings.Join(tickerResp.Error, ", "))
 	}
 
-	// Kraken puede devolver el par con un formato diferente, tomamos el primero
+	// Kraken puede devolver el par con un formato diferente; verificar que corresponde al solicitado
+	if _, err := k.resolveReturnedPairs(tickerResp.Result, []string{krakenPair}, []string{originalPair}); err != nil {
+		return nil, err
+	}
 	for _, tickerData := range tickerResp.Result {
 		price, err := tickerData.GetLastTradedPrice()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get last traded price: %w", err)
 		}
 
-		priceEntity := entities.NewPrice(
+		priceEntity := entities.NewPriceWithExchangeTime(
 			originalPair,
 			price,
-			tickerData.GetTimestamp(),
-			tickerData.GetAge(),
+			exchangeTimeFromHeader(resp.Header),
+			requestStart.Add(requestDuration),
 		)
+		priceEntity.Source = entities.PriceSourceREST
+		priceEntity.Exchange = entities.ExchangeKraken
+		priceEntity.Channel = restTickerChannel
+		priceEntity.Bid, priceEntity.Ask = tickerData.GetBidAsk()
 
 		// Record metrics and logging for successful external API call
 		metrics.RecordExternalAPICall("kraken", "/Ticker", resp.StatusCode, float64(requestDuration.Nanoseconds())/1e6)
@@ -207,6 +269,20 @@ func (k *RestClient) doTickerRequest(ctx context.Context, krakenPair, originalPa
 	return nil, fmt.Errorf("%w: no ticker data found for pair %s", ErrNonRetryable, originalPair)
 }
 
+// retryAllowed decide si reintentar tras el intento número attempt: el error debe
+// ser reintentable y el presupuesto compartido debe alcanzar. Cada intento fallido
+// con un error reintentable consume presupuesto, incluido el último.
+func (k *RestClient) retryAllowed(err error, attempt int) bool {
+	if !k.isRetryableError(err) {
+		return false
+	}
+	k.retryBudget.OnFailure()
+	if attempt >= MaxRetries {
+		return true // retry-go termina igual; no es un reintento suprimido
+	}
+	return k.retryBudget.AllowRetry()
+}
+
 // isRetryableError determines if an error should trigger a retry
 func (k *RestClient) isRetryableError(err error) bool {
 	return errors.Is(err, ErrRetryableRequest) ||
@@ -214,27 +290,92 @@ func (k *RestClient) isRetryableError(err error) bool {
 		errors.Is(err, context.Canceled)
 }
 
-// GetTickers obtiene los precios de múltiples pares con context y retry
+// GetTickers obtiene los precios de múltiples pares con context y retry. Los
+// conjuntos mayores que el tamaño de lote se dividen en varios requests /Ticker
+// ejecutados en paralelo (con límite de concurrencia) y se combinan en orden.
 func (k *RestClient) GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
 	if len(pairs) == 0 {
 		return []*entities.Price{}, nil
 	}
+	pairs = uniquePairs(pairs)
 
 	krakenPairs := make([]string, len(pairs))
 	for i, pair := range pairs {
-		krakenPair, err := toKrakenPair(pair)
+		krakenPair, err := k.mapper.Load().ToRest(pair)
 		if err != nil {
 			return nil, fmt.Errorf("%w: failed to convert pair to kraken pair: %w", ErrInvalidPair, err)
 		}
 		krakenPairs[i] = krakenPair
 	}
 
+	batchSize := k.batchSize
+	if batchSize <= 0 {
+		batchSize = DefaultTickerBatchSize
+	}
+	if len(pairs) <= batchSize {
+		return k.getTickersBatch(ctx, krakenPairs, pairs)
+	}
+
+	concurrency := k.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultTickerBatchConcurrency
+	}
+
+	batchCount := (len(pairs) + batchSize - 1) / batchSize
+	results := make([][]*entities.Price, batchCount)
+	errs := make([]error, batchCount)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for b := 0; b < batchCount; b++ {
+		start := b * batchSize
+		end := start + batchSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+
+		wg.Add(1)
+		go func(b int, krakenBatch, batch []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[b] = ctx.Err()
+				return
+			}
+			results[b], errs[b] = k.getTickersBatch(ctx, krakenBatch, batch)
+		}(b, krakenPairs[start:end], pairs[start:end])
+	}
+	wg.Wait()
+
+	prices := make([]*entities.Price, 0, len(pairs))
+	for b := range results {
+		if errs[b] != nil {
+			return nil, fmt.Errorf("ticker batch %d/%d failed: %w", b+1, batchCount, errs[b])
+		}
+		prices = append(prices, results[b]...)
+	}
+
+	logging.Debug(ctx, "Kraken tickers fetched in batches", logging.Fields{
+		"pairs_count": len(pairs),
+		"batch_count": batchCount,
+		"batch_size":  batchSize,
+		"concurrency": concurrency,
+	})
+	return prices, nil
+}
+
+// getTickersBatch obtiene un lote de pares con un único request /Ticker y retry
+func (k *RestClient) getTickersBatch(ctx context.Context, krakenPairs, pairs []string) ([]*entities.Price, error) {
 	var prices []*entities.Price
+	attempts := 0
 
 	retryErr := retry.Do(
 		func() error {
+			attempts++
 			// Create request context with timeout
-			reqCtx, cancel := context.WithTimeout(ctx, RequestTimeout)
+			reqCtx, cancel := context.WithTimeout(ctx, k.attemptTimeout())
 			defer cancel()
 
 			reqPrices, reqErr := k.doTickersRequest(reqCtx, krakenPairs, pairs)
@@ -249,13 +390,14 @@ func (k *RestClient) GetTickers(ctx context.Context, pairs []string) ([]*entitie
 		retry.Delay(BaseBackoff),
 		retry.MaxDelay(MaxBackoff),
 		retry.DelayType(retry.BackOffDelay),
-		retry.RetryIf(k.isRetryableError),
+		retry.RetryIf(func(err error) bool { return k.retryAllowed(err, attempts) }),
 		retry.Context(ctx),
 		retry.OnRetry(func(n uint, err error) {
 			metrics.RecordExternalAPIRetry("kraken", "/Ticker", int(n+1))
+			entities.RequestTimingsFrom(ctx).Retry()
 
 			// Record specific metrics for 429 rate limiting
-			if strings.Contains(err.Error(), "HTTP 429") {
+			if errors.Is(err, interfaces.ErrRateLimited) {
 				metrics.RecordKrakenRateLimitDrop("/Ticker")
 				// Calculate backoff duration for this attempt
 				backoffDuration := time.Duration(n+1) * BaseBackoff
@@ -272,7 +414,7 @@ func (k *RestClient) GetTickers(ctx context.Context, pairs []string) ([]*entitie
 				"max_attempts": MaxRetries,
 				"pairs_count":  len(pairs),
 				"error":        err.Error(),
-				"is_429":       strings.Contains(err.Error(), "HTTP 429"),
+				"is_429":       errors.Is(err, interfaces.ErrRateLimited),
 			})
 		}),
 	)
@@ -280,13 +422,15 @@ func (k *RestClient) GetTickers(ctx context.Context, pairs []string) ([]*entitie
 	if retryErr != nil {
 		return nil, fmt.Errorf("failed to get tickers after retries for %d pairs: %w", len(pairs), retryErr)
 	}
+	k.retryBudget.OnSuccess()
 
 	return prices, nil
 }
 
 // doTickersRequest performs the actual HTTP request for multiple tickers
 func (k *RestClient) doTickersRequest(ctx context.Context, krakenPairs, originalPairs []string) ([]*entities.Price, error) {
-	url := fmt.Sprintf("%s/Ticker?pair=%s", k.baseURL, strings.Join(krakenPairs, ","))
+	baseURL := k.endpoints.pick(ctx, k.baseURL)
+	url := fmt.Sprintf("%s/Ticker?pair=%s", baseURL, strings.Join(krakenPairs, ","))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -296,6 +440,7 @@ func (k *RestClient) doTickersRequest(ctx context.Context, krakenPairs, original
 	requestStart := time.Now()
 	resp, err := k.httpClient.Do(req)
 	requestDuration := time.Since(requestStart)
+	k.observeEndpoint(ctx, baseURL, resp, err, requestDuration)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 			return nil, fmt.Errorf("%w: context timeout/canceled", ErrRetryableRequest)
@@ -315,7 +460,7 @@ func (k *RestClient) doTickersRequest(ctx context.Context, krakenPairs, original
 	// CRITICAL FIX: Handle HTTP 429 (Too Many Requests) as retryable
 	if resp.StatusCode == http.StatusTooManyRequests {
 		metrics.RecordExternalAPICall("kraken", "/Ticker", resp.StatusCode, float64(requestDuration.Nanoseconds())/1e6)
-		return nil, fmt.Errorf("%w: HTTP %d (rate limited by kraken)", ErrRetryableRequest, resp.StatusCode)
+		return nil, fmt.Errorf("%w: %w: HTTP %d", ErrRetryableRequest, interfaces.ErrRateLimited, resp.StatusCode)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -332,41 +477,34 @@ func (k *RestClient) doTickersRequest(ctx context.Context, krakenPairs, original
 		return nil, fmt.Errorf("%w: %s", ErrNonRetryable, strings.Join(tickerResp.Error, ", "))
 	}
 
-	prices := make([]*entities.Price, 0, len(originalPairs))
-
-	// Crear mapeo de pares Kraken a pares originales
-	krakenToOriginal := make(map[string]string)
-	for i, originalPair := range originalPairs {
-		if i < len(krakenPairs) {
-			krakenToOriginal[krakenPairs[i]] = originalPair
-		}
+	resolved, err := k.resolveReturnedPairs(tickerResp.Result, krakenPairs, originalPairs)
+	if err != nil {
+		metrics.RecordExternalAPICall("kraken", "/Ticker", resp.StatusCode, float64(requestDuration.Nanoseconds())/1e6)
+		return nil, err
 	}
 
+	exchangeTime := exchangeTimeFromHeader(resp.Header)
+	receivedTime := requestStart.Add(requestDuration)
+	prices := make([]*entities.Price, 0, len(originalPairs))
 	for returnedPair, tickerData := range tickerResp.Result {
-		// Buscar el par original correspondiente
-		originalPair := ""
-		for krakenPair, origPair := range krakenToOriginal {
-			if krakenPair == returnedPair || strings.Contains(returnedPair, krakenPair) {
-				originalPair = origPair
-				break
-			}
-		}
-
-		if originalPair == "" {
-			continue // Skip pairs we didn't request
-		}
+		originalPair := resolved[returnedPair]
 
 		price, err := tickerData.GetLastTradedPrice()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get last traded price for %s: %w", originalPair, err)
 		}
 
-		prices = append(prices, entities.NewPrice(
+		priceEntity := entities.NewPriceWithExchangeTime(
 			originalPair,
 			price,
-			tickerData.GetTimestamp(),
-			tickerData.GetAge(),
-		))
+			exchangeTime,
+			receivedTime,
+		)
+		priceEntity.Source = entities.PriceSourceREST
+		priceEntity.Exchange = entities.ExchangeKraken
+		priceEntity.Channel = restTickerChannel
+		priceEntity.Bid, priceEntity.Ask = tickerData.GetBidAsk()
+		prices = append(prices, priceEntity)
 	}
 
 	// Record successful external API call metrics
@@ -377,6 +515,46 @@ func (k *RestClient) doTickersRequest(ctx context.Context, krakenPairs, original
 	return prices, nil
 }
 
+// resolveReturnedPairs asocia cada par devuelto por Kraken con el par solicitado
+// usando coincidencia exacta (nombre enviado o mapeo inverso del PairMapper).
+// Un par devuelto que no corresponde a ninguno solicitado es un error.
+func (k *RestClient) resolveReturnedPairs(result map[string]KrakenTickerData, krakenPairs, originalPairs []string) (map[string]string, error) {
+	requested := make(map[string]string, len(krakenPairs))
+	wanted := make(map[string]bool, len(originalPairs))
+	for i, krakenPair := range krakenPairs {
+		if i < len(originalPairs) {
+			requested[strings.ToUpper(krakenPair)] = originalPairs[i]
+			wanted[strings.ToUpper(originalPairs[i])] = true
+		}
+	}
+
+	resolved := make(map[string]string, len(result))
+	var unknown []string
+	for returnedPair := range result {
+		if originalPair, ok := requested[strings.ToUpper(returnedPair)]; ok {
+			resolved[returnedPair] = originalPair
+			continue
+		}
+		if friendly, err := k.mapper.Load().FromRest(returnedPair); err == nil && wanted[strings.ToUpper(friendly)] {
+			resolved[returnedPair] = friendly
+			continue
+		}
+		// Con un único par solicitado y una única respuesta no hay ambigüedad posible
+		if len(originalPairs) == 1 && len(result) == 1 {
+			resolved[returnedPair] = originalPairs[0]
+			continue
+		}
+		unknown = append(unknown, returnedPair)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: unexpected pairs in Kraken response: %v", ErrNonRetryable, unknown)
+	}
+	return resolved, nil
+}
+
+// assetMap traduce activos a su código REST de Kraken. Los activos legados usan
+// 4 letras con prefijo X (cripto) o Z (fiat); CHF, listado más tarde, no lo lleva.
 var assetMap = map[string]string{
 	"BTC": "XXBT", // Kraken usa XXBT para Bitcoin
 	"ETH": "XETH", // Kraken usa XETH para Ethereum
@@ -384,20 +562,44 @@ var assetMap = map[string]string{
 	"XRP": "XXRP", // Kraken usa XXRP para Ripple
 	"USD": "ZUSD", // Kraken usa ZUSD para USD
 	"EUR": "ZEUR", // Kraken usa ZEUR para EUR
+	"GBP": "ZGBP",
+	"JPY": "ZJPY",
+	"CAD": "ZCAD",
 	"CHF": "CHF",
-	"JPY": "JPY",
-	"GBP": "GBP",
-	"CAD": "CAD",
 }
-var krakenToFriendly map[string]string
 
-func init() {
-	krakenToFriendly = make(map[string]string)
-	for k, v := range assetMap {
-		krakenToFriendly[v] = k
+// restAssetCodes resuelve a su nombre amistoso tanto los códigos de assetMap
+// (XXBT, ZGBP) como los de los altnames (XBT, GBP), y restAssetPrefixes los
+// ordena de más largo a más corto para que XXBT se pruebe antes que XBT
+var restAssetCodes, restAssetPrefixes = func() (map[string]string, []string) {
+	codes := make(map[string]string, len(assetMap)+len(wsAssetMap))
+	for friendly, code := range assetMap {
+		codes[code] = friendly
+	}
+	for friendly, code := range wsAssetMap {
+		codes[code] = friendly
+	}
+	prefixes := make([]string, 0, len(codes))
+	for code := range codes {
+		prefixes = append(prefixes, code)
 	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i]) != len(prefixes[j]) {
+			return len(prefixes[i]) > len(prefixes[j])
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	return codes, prefixes
+}()
+
+// isLegacyAsset indica si code usa el formato legado de 4 letras (XXBT, ZUSD)
+func isLegacyAsset(code string) bool {
+	return len(code) == 4 && (code[0] == 'X' || code[0] == 'Z')
 }
 
+// toKrakenPair convierte BTC/USD al nombre REST de Kraken. Sólo los pares entre
+// activos legados concatenan sus códigos (XXBTZUSD); el resto se consulta por
+// altname (BTC/CHF → XBTCHF), que es además la clave con la que Kraken responde.
 func toKrakenPair(symbol string) (string, error) {
 	s := strings.ToUpper(symbol)
 	parts := strings.Split(s, "/")
@@ -414,22 +616,33 @@ func toKrakenPair(symbol string) (string, error) {
 		return "", fmt.Errorf("unsupported quote asset: %s", parts[1])
 	}
 
+	if !isLegacyAsset(base) || !isLegacyAsset(quote) {
+		return wsAssetMap[parts[0]] + wsAssetMap[parts[1]], nil
+	}
 	return base + quote, nil
 }
 
+// FromKrakenPair convierte un nombre REST de Kraken (XXBTZUSD, XBTCHF) a formato
+// amistoso, probando los códigos de activo conocidos como prefijo
 func FromKrakenPair(pair string) (string, error) {
 	s := strings.ToUpper(pair)
 
-	for krBase, friendlyBase := range krakenToFriendly {
-		if strings.HasPrefix(s, krBase) {
-			krQuote := strings.TrimPrefix(s, krBase)
-			friendlyQuote, ok := krakenToFriendly[krQuote]
-			if !ok {
-				return "", fmt.Errorf("activo quote no soportado: %s", krQuote)
-			}
-			return friendlyBase + "/" + friendlyQuote, nil
+	matchedBase := ""
+	for _, code := range restAssetPrefixes {
+		krQuote, ok := strings.CutPrefix(s, code)
+		if !ok {
+			continue
+		}
+		if friendlyQuote, ok := restAssetCodes[krQuote]; ok {
+			return restAssetCodes[code] + "/" + friendlyQuote, nil
+		}
+		if matchedBase == "" {
+			matchedBase = code
 		}
 	}
 
+	if matchedBase != "" {
+		return "", fmt.Errorf("activo quote no soportado: %s", strings.TrimPrefix(s, matchedBase))
+	}
 	return "", fmt.Errorf("base asset not supported in Kraken pair: %s", pair)
 }
@@ -2,6 +2,7 @@ package kraken
 
 import (
 	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/config"
 	"context"
 	"encoding/json"
@@ -628,6 +629,7 @@ func TestRestClient_GetTicker_HTTP429_ExceedsMaxRetries(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get ticker after retries")
 	assert.Contains(t, err.Error(), "HTTP 429")
+	assert.ErrorIs(t, err, interfaces.ErrRateLimited)
 	assert.Equal(t, MaxRetries, callCount)            // Validate max retries were attempted
 	assert.Greater(t, duration, 300*time.Millisecond) // Validate multiple backoffs occurred
 }
@@ -751,6 +753,11 @@ func TestToKrakenPair_ValidPairs(t *testing.T) {
 		{"ETH/USD", "XETHZUSD"},
 		{"BTC/EUR", "XXBTZEUR"},
 		{"ETH/EUR", "XETHZEUR"},
+		{"BTC/GBP", "XXBTZGBP"},
+		{"BTC/JPY", "XXBTZJPY"},
+		{"BTC/CAD", "XXBTZCAD"},
+		{"BTC/CHF", "XBTCHF"},
+		{"ETH/CHF", "ETHCHF"},
 	}
 
 	for _, tc := range testCases {
@@ -789,6 +796,12 @@ func TestFromKrakenPair_ValidPairs(t *testing.T) {
 		{"XETHZUSD", "ETH/USD"},
 		{"XXBTZEUR", "BTC/EUR"},
 		{"XETHZEUR", "ETH/EUR"},
+		{"XXBTZGBP", "BTC/GBP"},
+		{"XXBTZJPY", "BTC/JPY"},
+		{"XBTCHF", "BTC/CHF"},
+		{"XBTGBP", "BTC/GBP"},
+		{"XBTUSD", "BTC/USD"},
+		{"XETHXXBT", "ETH/BTC"},
 	}
 
 	for _, tc := range testCases {
@@ -806,6 +819,7 @@ func TestFromKrakenPair_InvalidPairs(t *testing.T) {
 		"",
 		"XBT",
 		"ZUSD",
+		"XXBTZMOON",
 	}
 
 	for _, tc := range testCases {
@@ -815,3 +829,21 @@ func TestFromKrakenPair_InvalidPairs(t *testing.T) {
 		})
 	}
 }
+
+func TestKrakenPair_FiatRoundTrips(t *testing.T) {
+	for _, pair := range []string{"BTC/CHF", "BTC/GBP", "BTC/JPY", "BTC/CAD", "ETH/CHF", "ETH/GBP", "ETH/JPY"} {
+		t.Run(pair, func(t *testing.T) {
+			rest, err := toKrakenPair(pair)
+			require.NoError(t, err)
+			back, err := FromKrakenPair(rest)
+			require.NoError(t, err)
+			assert.Equal(t, pair, back, "REST name %s", rest)
+
+			ws, err := toWebSocketPair(pair)
+			require.NoError(t, err)
+			back, err = fromWebSocketPair(ws)
+			require.NoError(t, err)
+			assert.Equal(t, pair, back, "WebSocket name %s", ws)
+		})
+	}
+}
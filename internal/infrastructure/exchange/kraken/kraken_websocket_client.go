@@ -2,17 +2,22 @@ package kraken
 
 import (
 	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/config"
 	"btc-ltp-service/internal/infrastructure/logging"
 	"btc-ltp-service/internal/infrastructure/metrics"
 	cachepkg "btc-ltp-service/internal/infrastructure/repositories/cache"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net/url"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -23,10 +28,36 @@ const (
 	PingInterval       = 30 * time.Second
 	WriteWait          = 10 * time.Second
 	PongWait           = 60 * time.Second
+	QuietTimeout       = 10 * time.Second
 	ReadBufferSize     = 1024
 	WriteBufferSize    = 1024
 )
 
+// keepaliveOptions son los tiempos del keepalive de la conexión; 0 usa
+// PingInterval, PongWait, WriteWait y QuietTimeout
+type keepaliveOptions struct {
+	pingInterval time.Duration
+	pongWait     time.Duration
+	writeWait    time.Duration
+	quietTimeout time.Duration // máximo sin frames (datos ni heartbeats) antes de reconectar
+}
+
+func (o keepaliveOptions) withDefaults() keepaliveOptions {
+	if o.pingInterval <= 0 {
+		o.pingInterval = PingInterval
+	}
+	if o.pongWait <= 0 {
+		o.pongWait = PongWait
+	}
+	if o.writeWait <= 0 {
+		o.writeWait = WriteWait
+	}
+	if o.quietTimeout <= 0 {
+		o.quietTimeout = QuietTimeout
+	}
+	return o
+}
+
 // WebSocketClient implementa la interfaz Exchange usando WebSocket de Kraken
 type WebSocketClient struct {
 	conn           *websocket.Conn
@@ -38,22 +69,63 @@ type WebSocketClient struct {
 	cache          *cachepkg.PriceCacheAdapter
 	ctx            context.Context
 	cancel         context.CancelFunc
+	logCtx         atomic.Value                // context.Context sin cancelación usado para logging estructurado
+	subs           subscriptionTracker         // estado confirmado/pendiente de suscripciones
+	chanOpts       channelOptions              // capacidad y política de desborde de priceChannels
+	keepalive      keepaliveOptions            // ping, espera de pong y timeout de escritura
+	drops          dropCounters                // descartes por par para warnings por umbral
+	handlers       priceHandlers               // callbacks registrados con OnPrice
+	onTick         atomic.Pointer[TickHandler] // Opcional: callback de ticks recibidos y descartados
+	mapper         atomic.Pointer[PairMapper]  // Opcional: mapeo dinámico desde AssetPairs
+	validator      interfaces.PriceValidator   // Opcional: descarta ticks sospechosos (protegido por mu)
+	dedupWindow    time.Duration               // Ventana en la que un tick con el precio cacheado no se reescribe (0 = sin deduplicar)
+	maxFrameBytes  int64                       // Tamaño máximo de un frame recibido (0 = DefaultMaxFrameBytes)
+	maxConnAge     time.Duration               // Edad a la que se recicla la conexión (0 = sin límite)
+	systemStatus   atomic.Value                // string: último estado del evento systemStatus
+	lastFrame      atomic.Int64                // UnixNano del último frame recibido (datos o heartbeat)
+	transport      *transport                  // Allowlist de salida y validación TLS (nil usa los defaults)
+	endpoints      *endpointSet                // Opcional: url y las URLs alternativas, elegidas por latencia
+	activeURL      atomic.Value                // string: URL de la conexión actual (vacía antes de conectar)
 	reconnectTimer *time.Timer
 	isReconnecting bool
 	reconnectCount int
-	wg             sync.WaitGroup // espera a que goroutines terminen al cerrar
+	onReconnect    atomic.Pointer[ReconnectHandler] // Opcional: callback de los intentos de reconexión
+	lostAt         time.Time                        // Cuándo se perdió la conexión que se está recuperando (protegido por mu)
+	lostReason     string                           // Por qué se perdió (protegido por mu)
+	shard          string                           // Etiqueta del shard en métricas por conexión (vacía fuera de ShardedWebSocketClient)
+	onDisconnect   func()                           // Opcional: se invoca en otra goroutine al perder la conexión (protegido por mu)
+	wg             sync.WaitGroup                   // espera a que goroutines terminen al cerrar
 }
 
 // WebSocketMessage representa un mensaje general de WebSocket de Kraken
 type WebSocketMessage struct {
 	Event        string      `json:"event,omitempty"`
-	Pair         []string    `json:"pair,omitempty"`
+	Pair         PairList    `json:"pair,omitempty"`
 	Subscription interface{} `json:"subscription,omitempty"`
 	ReqID        int         `json:"reqid,omitempty"`
 	Status       string      `json:"status,omitempty"`
 	ErrorMessage string      `json:"errorMessage,omitempty"`
 }
 
+// PairList acepta tanto un array de pares (subscribe) como un par único
+// (subscriptionStatus de Kraken envía "pair" como string)
+type PairList []string
+
+// UnmarshalJSON implementa json.Unmarshaler
+func (p *PairList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*p = PairList{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*p = many
+	return nil
+}
+
 // TickerSubscription representa la suscripción al canal de ticker
 type TickerSubscription struct {
 	Name string `json:"name"`
@@ -82,20 +154,68 @@ func NewWebSocketClient() *WebSocketClient {
 
 // NewWebSocketClientWithConfig crea una nueva instancia del cliente WebSocket de Kraken con configuración
 func NewWebSocketClientWithConfig(cfg config.KrakenConfig) *WebSocketClient {
-	ctx, cancel := context.WithCancel(context.Background())
+	return newWebSocketClient(cfg, newClientPriceCache(cfg))
+}
+
+// newClientPriceCache crea la caché local de precios del cliente WebSocket
+func newClientPriceCache(cfg config.KrakenConfig) *cachepkg.PriceCacheAdapter {
 	ttl := cfg.PriceCacheTTL
 	if ttl == 0 {
 		ttl = 30 * time.Second
 	}
-	backend := cachepkg.NewMemoryCache()
+	return cachepkg.NewPriceCache(cachepkg.NewMemoryCache(), ttl)
+}
+
+// newWebSocketClient crea el cliente sobre cache, que los shards de un
+// ShardedWebSocketClient comparten
+func newWebSocketClient(cfg config.KrakenConfig, cache *cachepkg.PriceCacheAdapter) *WebSocketClient {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &WebSocketClient{
 		url:           cfg.WebSocketURL,
 		subscriptions: make(map[string]bool),
 		priceChannels: make(map[string]chan *entities.Price),
-		cache:         cachepkg.NewPriceCache(backend, ttl),
+		cache:         cache,
 		ctx:           ctx,
 		cancel:        cancel,
+		chanOpts: channelOptions{
+			capacity:     cfg.ChannelCapacity,
+			policy:       OverflowPolicy(cfg.ChannelOverflowPolicy),
+			blockTimeout: cfg.ChannelBlockTimeout,
+			warnEvery:    int64(cfg.ChannelDropWarnThreshold),
+		},
+		keepalive: keepaliveOptions{
+			pingInterval: cfg.WebSocketTimeouts.PingInterval,
+			pongWait:     cfg.WebSocketTimeouts.PongWait,
+			writeWait:    cfg.WebSocketTimeouts.WriteWait,
+			quietTimeout: cfg.WebSocketTimeouts.QuietTimeout,
+		},
+		dedupWindow:   cfg.DedupWindow,
+		maxFrameBytes: cfg.MaxFrameBytes,
+		maxConnAge:    cfg.MaxConnectionAge,
+		transport:     newTransport(cfg),
+		endpoints:     newWebSocketEndpoints(cfg),
+	}
+}
+
+// newWebSocketEndpoints crea la selección entre websocket_url y las URLs alternativas
+func newWebSocketEndpoints(cfg config.KrakenConfig) *endpointSet {
+	return newEndpointSet("websocket", cfg.WebSocketURL, cfg.WebSocketURLs, cfg.EndpointCooldown)
+}
+
+// currentURL es la URL de la conexión actual o, antes de conectar, la configurada
+func (k *WebSocketClient) currentURL() string {
+	if active, ok := k.activeURL.Load().(string); ok {
+		return active
 	}
+	return k.url
+}
+
+// frameLimit es el tamaño máximo de un frame recibido; 0 usa DefaultMaxFrameBytes
+func (k *WebSocketClient) frameLimit() int64 {
+	if k.maxFrameBytes <= 0 {
+		return DefaultMaxFrameBytes
+	}
+	return k.maxFrameBytes
 }
 
 // --- Helpers de mapeo específicos para WebSocket ---
@@ -130,75 +250,148 @@ func toWebSocketPair(symbol string) (string, error) {
 	return base + "/" + quote, nil
 }
 
-func fromWebSocketPair(wsPair string) (string, error) {
-	s := strings.ToUpper(wsPair)
-	parts := strings.Split(s, "/")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid WS pair format: %s", wsPair)
-	}
-	// Construir mapa inverso
+// wsAssetInverse es wsAssetMap invertido, precalculado porque fromWebSocketPair
+// se llama por cada tick
+var wsAssetInverse = func() map[string]string {
 	inverse := make(map[string]string, len(wsAssetMap))
 	for friendly, wsName := range wsAssetMap {
 		inverse[wsName] = friendly
 	}
-	base, ok := inverse[parts[0]]
+	return inverse
+}()
+
+func fromWebSocketPair(wsPair string) (string, error) {
+	s := strings.ToUpper(wsPair)
+	wsBase, wsQuote, ok := strings.Cut(s, "/")
+	if !ok || strings.Contains(wsQuote, "/") {
+		return "", fmt.Errorf("invalid WS pair format: %s", wsPair)
+	}
+	base, ok := wsAssetInverse[wsBase]
 	if !ok {
-		return "", fmt.Errorf("unsupported WS base asset: %s", parts[0])
+		return "", fmt.Errorf("unsupported WS base asset: %s", wsBase)
 	}
-	quote, ok := inverse[parts[1]]
+	quote, ok := wsAssetInverse[wsQuote]
 	if !ok {
-		return "", fmt.Errorf("unsupported WS quote asset: %s", parts[1])
+		return "", fmt.Errorf("unsupported WS quote asset: %s", wsQuote)
 	}
 	return base + "/" + quote, nil
 }
 
 // Connect establece la conexión WebSocket con Kraken
 func (k *WebSocketClient) Connect() error {
-	k.mu.Lock()
-	defer k.mu.Unlock()
+	return k.ConnectContext(context.Background())
+}
 
-	if k.isConnected {
+// ConnectContext establece la conexión respetando el deadline/cancelación del caller.
+// Los valores del contexto (request_id, trace, etc.) se conservan para el logging
+// de las goroutines de lectura y reconexión.
+func (k *WebSocketClient) ConnectContext(ctx context.Context) error {
+	k.logCtx.Store(context.WithoutCancel(ctx))
+	return k.connect(ctx)
+}
+
+// connect realiza el dial y arranca las goroutines de lectura y ping. El dial
+// recorre todas las URLs y corre sin el lock, para no frenar a los lectores del
+// estado; si otra goroutine conectó mientras tanto se cierra la conexión sobrante.
+func (k *WebSocketClient) connect(ctx context.Context) error {
+	k.mu.RLock()
+	connected := k.isConnected
+	k.mu.RUnlock()
+	if connected {
 		return nil
 	}
 
-	u, err := url.Parse(k.url)
+	conn, endpoint, err := k.dial(ctx)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+		return err
 	}
 
-	dialer := websocket.Dialer{
-		ReadBufferSize:  ReadBufferSize,
-		WriteBufferSize: WriteBufferSize,
-	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
 
-	conn, _, err := dialer.Dial(u.String(), nil)
-	if err != nil {
-		return fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+	if k.isConnected || k.ctx.Err() != nil {
+		// Otra goroutine ganó la carrera o el cliente se cerró durante el dial
+		_ = conn.Close()
+		if k.isConnected {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrConnectionFailed, k.ctx.Err())
 	}
 
+	conn.SetReadLimit(k.frameLimit())
 	k.conn = conn
 	k.isConnected = true
+	k.publishConnectionStatus()
 
 	// Configurar timeouts
-	_ = k.conn.SetReadDeadline(time.Now().Add(PongWait))
+	pongWait := k.keepalive.withDefaults().pongWait
+	_ = k.conn.SetReadDeadline(time.Now().Add(pongWait))
 	k.conn.SetPongHandler(func(string) error {
-		_ = k.conn.SetReadDeadline(time.Now().Add(PongWait))
+		_ = k.conn.SetReadDeadline(time.Now().Add(pongWait))
 		return nil
 	})
 
+	k.lastFrame.Store(time.Now().UnixNano())
+
 	// Iniciar goroutines para manejo de mensajes
-	k.wg.Add(2)
+	k.wg.Add(4)
 	go k.readMessages()
 	go k.pingHandler()
+	go k.reconcileSubscriptions()
+	go k.watchQuietChannel(conn)
 
-	// Usar logging estructurado en lugar de log.Println
-	// Note: Necesitaríamos el contexto aquí, pero para mantener la interfaz existente,
-	// usaremos context.Background() temporalmente
+	logging.Debug(ctx, "WebSocket connection established", logging.Fields{
+		"websocket_url": endpoint,
+	})
 	return nil
 }
 
+// dial abre la conexión contra las URLs en orden de preferencia hasta que una
+// responde; cada dial mide la latencia de la URL o la excluye si falla
+func (k *WebSocketClient) dial(ctx context.Context) (*websocket.Conn, string, error) {
+	dialer := k.transport.websocketDialer()
+	var dialErr error
+	for _, endpoint := range k.endpoints.candidates(k.url) {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			dialErr = fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+			continue
+		}
+
+		start := time.Now()
+		conn, _, err := dialer.DialContext(ctx, u.String(), nil)
+		if err != nil {
+			dialErr = fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+			if ctx.Err() != nil {
+				break
+			}
+			k.endpoints.fail(ctx, endpoint, err)
+			continue
+		}
+		k.endpoints.observe(endpoint, time.Since(start))
+		k.activeURL.Store(endpoint)
+		return conn, endpoint, nil
+	}
+	return nil, "", dialErr
+}
+
+// logContext retorna el contexto de logging capturado en ConnectContext
+func (k *WebSocketClient) logContext() context.Context {
+	if ctx, ok := k.logCtx.Load().(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
 // Close cierra la conexión WebSocket
 func (k *WebSocketClient) Close() error {
+	return k.Shutdown(context.Background())
+}
+
+// Shutdown cierra la conexión WebSocket esperando a que las goroutines terminen
+// como máximo hasta el deadline de ctx. Si el deadline vence, la limpieza de
+// canales continúa en segundo plano y se retorna el error del contexto.
+func (k *WebSocketClient) Shutdown(ctx context.Context) error {
 	k.mu.Lock()
 
 	if !k.isConnected && !k.isReconnecting {
@@ -211,6 +404,7 @@ func (k *WebSocketClient) Close() error {
 	k.isConnected = false
 	k.isReconnecting = false
 	k.reconnectCount = 0
+	k.publishConnectionStatus()
 
 	if k.reconnectTimer != nil {
 		k.reconnectTimer.Stop()
@@ -226,21 +420,39 @@ func (k *WebSocketClient) Close() error {
 	var err error
 	if conn != nil {
 		_ = conn.SetReadDeadline(time.Now())
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetWriteDeadline(deadline)
+		}
 		err = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 		_ = conn.Close()
 	}
 
 	// Esperar a que goroutines terminen antes de cerrar canales
-	k.wg.Wait()
+	done := make(chan struct{})
+	go func() {
+		k.wg.Wait()
 
-	// Ahora es seguro cerrar canales y limpiar conexión compartida
-	k.mu.Lock()
-	k.conn = nil
-	for _, ch := range k.priceChannels {
-		close(ch)
+		// Ahora es seguro cerrar canales y limpiar conexión compartida
+		k.mu.Lock()
+		k.conn = nil
+		for _, ch := range k.priceChannels {
+			close(ch)
+		}
+		k.priceChannels = make(map[string]chan *entities.Price)
+		k.mu.Unlock()
+		k.subs.reset()
+		k.subs.clearFailures()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logging.Warn(ctx, "WebSocket shutdown deadline exceeded, cleanup continues in background", logging.Fields{
+			"websocket_url": k.currentURL(),
+		})
+		return fmt.Errorf("websocket shutdown: %w", ctx.Err())
 	}
-	k.priceChannels = make(map[string]chan *entities.Price)
-	k.mu.Unlock()
 
 	return err
 }
@@ -257,7 +469,7 @@ func (k *WebSocketClient) SubscribeTicker(pairs []string) error {
 	// Proteger acceso al mapa con mutex
 	k.mu.Lock()
 	for i, pair := range pairs {
-		krakenPair, err := toWebSocketPair(pair)
+		krakenPair, err := k.mapper.Load().ToWebSocket(pair)
 		if err != nil {
 			k.mu.Unlock()
 			return fmt.Errorf("failed to convert pair to Kraken WS format: %w", err)
@@ -268,7 +480,7 @@ func (k *WebSocketClient) SubscribeTicker(pairs []string) error {
 		// Si el canal ya existe, reutilizarlo para evitar cerrar un canal que
 		// podría estar siendo usado por otra goroutine en ese momento.
 		if _, exists := k.priceChannels[pair]; !exists {
-			k.priceChannels[pair] = make(chan *entities.Price, 100)
+			k.priceChannels[pair] = make(chan *entities.Price, k.chanOpts.withDefaults().capacity)
 		}
 	}
 	k.mu.Unlock()
@@ -285,8 +497,47 @@ func (k *WebSocketClient) SubscribeTicker(pairs []string) error {
 	k.mu.Lock()
 	defer k.mu.Unlock()
 
-	_ = k.conn.SetWriteDeadline(time.Now().Add(WriteWait))
-	return k.conn.WriteJSON(subscribeMsg)
+	_ = k.conn.SetWriteDeadline(time.Now().Add(k.keepalive.withDefaults().writeWait))
+	if err := k.conn.WriteJSON(subscribeMsg); err != nil {
+		return err
+	}
+	k.subs.markPending(pairs, time.Now())
+	return nil
+}
+
+// UnsubscribeTicker cancela la suscripción de ticker de los pares suscritos;
+// GetTicker y SubscribeTicker vuelven a suscribirlos. Sin conexión sólo se
+// olvidan, para que no se resuscriban al reconectar.
+func (k *WebSocketClient) UnsubscribeTicker(pairs []string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	krakenPairs := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if !k.subscriptions[pair] {
+			continue
+		}
+		delete(k.subscriptions, pair)
+		if krakenPair, err := k.mapper.Load().ToWebSocket(pair); err == nil {
+			krakenPairs = append(krakenPairs, krakenPair)
+		}
+	}
+	k.subs.forget(pairs)
+
+	if len(krakenPairs) == 0 || !k.isConnected || k.conn == nil {
+		return nil
+	}
+
+	unsubscribeMsg := WebSocketMessage{
+		Event: "unsubscribe",
+		Pair:  krakenPairs,
+		Subscription: TickerSubscription{
+			Name: "ticker",
+		},
+		ReqID: int(time.Now().Unix()),
+	}
+	_ = k.conn.SetWriteDeadline(time.Now().Add(k.keepalive.withDefaults().writeWait))
+	return k.conn.WriteJSON(unsubscribeMsg)
 }
 
 // GetTicker obtiene el último precio usando WebSocket (implementa la interfaz Exchange)
@@ -298,9 +549,13 @@ func (k *WebSocketClient) GetTicker(ctx context.Context, pair string) (*entities
 		}
 	}
 
+	if k.subs.excluded(pair) {
+		return nil, fmt.Errorf("%w: %s", ErrSubscriptionExcluded, pair)
+	}
+
 	// 2. Si no hay cache, proceder con conexión WS como antes
 	if !k.isConnected {
-		if err := k.Connect(); err != nil {
+		if err := k.connect(ctx); err != nil {
 			return nil, ErrConnectionFailed
 		}
 	}
@@ -321,33 +576,91 @@ func (k *WebSocketClient) GetTicker(ctx context.Context, pair string) (*entities
 		return nil, fmt.Errorf("price channel not found for pair %s", pair)
 	}
 
-	select {
-	case price := <-priceChan:
-		return price, nil
-	case <-ctx.Done():
-		return nil, fmt.Errorf("context canceled/timeout waiting for price update for pair %s: %w", pair, ctx.Err())
+	start := time.Now()
+	result := k.awaitPair(ctx, pair, priceChan)
+	if result.Err != nil {
+		logging.Warn(ctx, "WebSocket price wait failed", logging.Fields{
+			"pair":    pair,
+			"wait_ms": time.Since(start).Milliseconds(),
+			"error":   result.Err.Error(),
+		})
 	}
+	return result.Price, result.Err
 }
 
-// GetTickers obtiene precios múltiples usando WebSocket
+// TickerResult es el resultado de un par dentro de GetTickersByPair
+type TickerResult struct {
+	Pair  string
+	Price *entities.Price
+	Err   error
+}
+
+// GetTickers obtiene precios múltiples usando WebSocket, en el orden de pairs.
+// Si algún par no llega antes de ctx, retorna los obtenidos junto con el error.
 func (k *WebSocketClient) GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
+	results, err := k.GetTickersByPair(ctx, pairs)
+	if err != nil {
+		return nil, err
+	}
+	return tickersInOrder(pairs, results)
+}
+
+// tickersInOrder ordena los resultados de GetTickersByPair según pairs y
+// resume los pares faltantes en un único error
+func tickersInOrder(pairs []string, results map[string]TickerResult) ([]*entities.Price, error) {
+	prices := make([]*entities.Price, 0, len(results))
+	var missing []string
+	var firstErr error
+	for _, pair := range uniquePairs(pairs) {
+		result := results[pair]
+		if result.Err != nil || result.Price == nil {
+			missing = append(missing, pair)
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+			continue
+		}
+		prices = append(prices, result.Price)
+	}
+
+	if len(missing) > 0 {
+		return prices, fmt.Errorf("failed waiting for price updates, got %d out of %d (missing %v): %w", len(prices), len(prices)+len(missing), missing, firstErr)
+	}
+	return prices, nil
+}
+
+// GetTickersByPair obtiene precios múltiples correlacionando cada precio con su par.
+// Cada par se espera en su propio canal y se verifica que el precio recibido
+// corresponda al par solicitado. El error retornado sólo cubre fallos globales
+// (conexión/suscripción); los fallos por par se informan en TickerResult.Err.
+func (k *WebSocketClient) GetTickersByPair(ctx context.Context, pairs []string) (map[string]TickerResult, error) {
 	if !k.isConnected {
 		// Intentar conexión perezosa
-		if err := k.Connect(); err != nil {
+		if err := k.connect(ctx); err != nil {
 			return nil, ErrConnectionFailed
 		}
 	}
 
-	// 1. Intentar cache primero
-	var cached []*entities.Price
+	pairs = uniquePairs(pairs)
+	results := make(map[string]TickerResult, len(pairs))
+
+	// 1. Intentar cache primero; los pares excluidos no se esperan
 	var missing []string
-	if k.cache != nil {
-		cached, missing = k.cache.GetMany(ctx, pairs)
-		if len(missing) == 0 {
-			return cached, nil
+	for _, pair := range pairs {
+		if k.cache != nil {
+			if price, ok := k.cache.Get(ctx, pair); ok {
+				results[pair] = TickerResult{Pair: pair, Price: price}
+				continue
+			}
 		}
-	} else {
-		missing = pairs
+		if k.subs.excluded(pair) {
+			results[pair] = TickerResult{Pair: pair, Err: fmt.Errorf("%w: %s", ErrSubscriptionExcluded, pair)}
+			continue
+		}
+		missing = append(missing, pair)
+	}
+	if len(missing) == 0 {
+		return results, nil
 	}
 
 	// 2. Suscribirse a pares faltantes
@@ -355,32 +668,93 @@ func (k *WebSocketClient) GetTickers(ctx context.Context, pairs []string) ([]*en
 		return nil, err
 	}
 
-	// Construir canales sólo para pares faltantes
+	// 3. Esperar cada par en su canal de forma concurrente
 	k.mu.RLock()
-	priceChannels := make([]chan *entities.Price, 0, len(missing))
+	channels := make(map[string]chan *entities.Price, len(missing))
 	for _, pair := range missing {
 		if ch, ok := k.priceChannels[pair]; ok {
-			priceChannels = append(priceChannels, ch)
+			channels[pair] = ch
 		}
 	}
 	k.mu.RUnlock()
 
-	prices := make([]*entities.Price, 0, len(pairs))
-	prices = append(prices, cached...)
+	resultCh := make(chan TickerResult, len(missing))
+	for _, pair := range missing {
+		ch, ok := channels[pair]
+		if !ok {
+			resultCh <- TickerResult{Pair: pair, Err: fmt.Errorf("price channel not found for pair %s", pair)}
+			continue
+		}
+		go func(pair string, ch chan *entities.Price) {
+			resultCh <- k.awaitPair(ctx, pair, ch)
+		}(pair, ch)
+	}
 
-	for i := 0; i < len(priceChannels); i++ {
+	start := time.Now()
+	var failed []string
+	for range missing {
+		result := <-resultCh
+		if result.Price != nil && k.cache != nil {
+			_ = k.cache.Set(ctx, result.Price)
+		}
+		if result.Err != nil {
+			failed = append(failed, result.Pair)
+		}
+		results[result.Pair] = result
+	}
+	// Un único log por espera, con los pares que no llegaron
+	if len(failed) > 0 {
+		logging.Warn(ctx, "WebSocket price wait failed", logging.Fields{
+			"failed_pairs":  failed,
+			"awaited_count": len(missing),
+			"wait_ms":       time.Since(start).Milliseconds(),
+		})
+	}
+
+	return results, nil
+}
+
+// awaitPair espera un precio del par en su canal, descartando precios de otros pares
+// Los logs usan ctx para que la espera quede asociada a la request que la originó.
+func (k *WebSocketClient) awaitPair(ctx context.Context, pair string, ch chan *entities.Price) TickerResult {
+	start := time.Now()
+	for {
 		select {
-		case price := <-priceChannels[i]:
-			prices = append(prices, price)
-			if k.cache != nil {
-				_ = k.cache.Set(ctx, price)
+		case price, ok := <-ch:
+			if !ok {
+				return TickerResult{Pair: pair, Err: fmt.Errorf("%w: %w for pair %s", ErrConnectionFailed, ErrWebSocketClosed, pair)}
+			}
+			if price == nil || !strings.EqualFold(price.Pair, pair) {
+				continue // nunca asignar un precio a un par distinto
 			}
+			logging.Debug(ctx, "WebSocket price update received", logging.Fields{
+				"pair":    pair,
+				"wait_ms": time.Since(start).Milliseconds(),
+			})
+			return TickerResult{Pair: pair, Price: price}
 		case <-ctx.Done():
-			return prices, fmt.Errorf("context canceled/timeout waiting for price updates, got %d out of %d: %w", len(prices), len(pairs), ctx.Err())
+			logging.Debug(ctx, "Timed out waiting for WebSocket price update", logging.Fields{
+				"pair":    pair,
+				"wait_ms": time.Since(start).Milliseconds(),
+				"error":   ctx.Err().Error(),
+			})
+			return TickerResult{Pair: pair, Err: fmt.Errorf("context canceled/timeout waiting for price update for pair %s: %w", pair, ctx.Err())}
 		}
 	}
+}
 
-	return prices, nil
+// uniquePairs elimina pares duplicados preservando el orden
+func uniquePairs(pairs []string) []string {
+	seen := make(map[string]bool, len(pairs))
+	out := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		out = append(out, pair)
+	}
+	return out
 }
 
 // readMessages lee mensajes del WebSocket en un bucle
@@ -392,40 +766,96 @@ func (k *WebSocketClient) readMessages() {
 		case <-k.ctx.Done():
 			return
 		default:
-			_, messageBytes, err := k.conn.ReadMessage()
+			frame, err := k.nextFrame()
+			receivedAt := time.Now()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-					logging.Error(context.Background(), "WebSocket unexpected close error", logging.Fields{
+					logging.Error(k.logContext(), "WebSocket unexpected close error", logging.Fields{
 						"error": err.Error(),
-						"url":   k.url,
+						"url":   k.currentURL(),
+					})
+				} else if errors.Is(err, websocket.ErrReadLimit) {
+					metrics.RecordWebSocketOversizedFrame()
+					logging.Warn(k.logContext(), "WebSocket frame exceeds maximum size, reconnecting", logging.Fields{
+						"max_bytes": k.frameLimit(),
+						"url":       k.currentURL(),
 					})
 				}
-				k.scheduleReconnect()
+				k.scheduleReconnect(disconnectReason(err))
 				return
 			}
 
-			if err := k.handleMessage(messageBytes); err != nil {
-				logging.Warn(context.Background(), "Error handling WebSocket message", logging.Fields{
+			err = k.safeHandleMessage(frame.Bytes(), receivedAt)
+			releaseFrame(frame)
+			if err != nil {
+				logging.Warn(k.logContext(), "Error handling WebSocket message", logging.Fields{
 					"error": err.Error(),
-					"url":   k.url,
+					"url":   k.currentURL(),
 				})
 			}
 		}
 	}
 }
 
+// nextFrame lee el próximo mensaje en un buffer reutilizable (ver readFrame)
+func (k *WebSocketClient) nextFrame() (*bytes.Buffer, error) {
+	_, reader, err := k.conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	return readFrame(reader)
+}
+
+// safeHandleMessage es handleMessageAt para readMessages: un frame que provoca un
+// panic se reporta como error en lugar de terminar la goroutine de lectura
+func (k *WebSocketClient) safeHandleMessage(messageBytes []byte, receivedAt time.Time) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic handling WebSocket message: %v", r)
+		}
+	}()
+	return k.handleMessageAt(messageBytes, receivedAt)
+}
+
 // handleMessage procesa los mensajes recibidos del WebSocket
 func (k *WebSocketClient) handleMessage(messageBytes []byte) error {
-	// Intentar parsear como array (actualizaciones de ticker)
-	var tickerArray []interface{}
-	if err := json.Unmarshal(messageBytes, &tickerArray); err == nil && len(tickerArray) >= 4 {
-		return k.handleTickerUpdate(tickerArray)
-	}
+	return k.handleMessageAt(messageBytes, time.Now())
+}
 
-	// Intentar parsear como mensaje de evento
-	var msg WebSocketMessage
-	if err := json.Unmarshal(messageBytes, &msg); err == nil {
-		return k.handleEventMessage(msg)
+// handleMessageAt procesa un mensaje recibido en receivedAt; el instante de
+// recepción se propaga a los precios para medir la latencia del pipeline
+func (k *WebSocketClient) handleMessageAt(messageBytes []byte, receivedAt time.Time) error {
+	k.lastFrame.Store(receivedAt.UnixNano())
+	if !withinDepth(messageBytes, maxFrameDepth) {
+		return errFrameTooDeep
+	}
+	switch firstByte(messageBytes) {
+	case '[':
+		// Actualizaciones de ticker: el frame se separa con buffers del pool
+		frame := getTickerFrame()
+		defer frame.release()
+		if !frame.split(messageBytes) || len(frame.parts) < 4 {
+			return nil
+		}
+		ticker, err := decodeTicker(frame.parts[1])
+		if errors.Is(err, errInvalidTickerData) {
+			return err
+		}
+		pair, ok := frame.partString(3)
+		if !ok {
+			return fmt.Errorf("invalid pair format")
+		}
+		if err != nil {
+			return err
+		}
+		channel, _ := frame.partString(2)
+		return k.handleTickerAt(ticker, channel, pair, receivedAt)
+	case '{':
+		// Mensajes de evento
+		var msg WebSocketMessage
+		if err := json.Unmarshal(messageBytes, &msg); err == nil {
+			return k.handleEventMessage(msg)
+		}
 	}
 
 	return nil
@@ -433,6 +863,26 @@ func (k *WebSocketClient) handleMessage(messageBytes []byte) error {
 
 // handleTickerUpdate procesa actualizaciones de ticker
 func (k *WebSocketClient) handleTickerUpdate(data []interface{}) error {
+	return k.handleTickerUpdateAt(data, time.Now())
+}
+
+// firstTickerValue parsea el precio de un campo del ticker del WebSocket con
+// formato [<price>, ...] (p. ej. "a" y "b"); 0 si falta o no es válido
+func firstTickerValue(field interface{}) float64 {
+	values, ok := field.([]interface{})
+	if !ok || len(values) == 0 {
+		return 0
+	}
+	text, ok := values[0].(string)
+	if !ok {
+		return 0
+	}
+	value, _ := strconv.ParseFloat(text, 64)
+	return value
+}
+
+// handleTickerUpdateAt procesa una actualización de ticker recibida en receivedAt
+func (k *WebSocketClient) handleTickerUpdateAt(data []interface{}, receivedAt time.Time) error {
 	if len(data) < 4 {
 		return fmt.Errorf("invalid ticker update format")
 	}
@@ -448,30 +898,21 @@ func (k *WebSocketClient) handleTickerUpdate(data []interface{}) error {
 		return fmt.Errorf("invalid pair format")
 	}
 
-	// Extraer el precio de la última transacción
-	lastTradeInterface, ok := tickerDataInterface["c"]
-	if !ok {
-		return fmt.Errorf("no last trade data found")
-	}
-
-	lastTradeArray, ok := lastTradeInterface.([]interface{})
-	if !ok || len(lastTradeArray) == 0 {
-		return fmt.Errorf("invalid last trade format")
-	}
-
-	priceStr, ok := lastTradeArray[0].(string)
-	if !ok {
-		return fmt.Errorf("invalid price format")
-	}
-
-	price, err := strconv.ParseFloat(priceStr, 64)
+	ticker, err := tickerFromMap(tickerDataInterface)
 	if err != nil {
-		return fmt.Errorf("failed to parse price: %w", err)
+		return err
 	}
 
+	channel, _ := data[2].(string)
+	return k.handleTickerAt(ticker, channel, pairInterface, receivedAt)
+}
+
+// handleTickerAt procesa el ticker de pairInterface (formato WS, p. ej. XBT/USD)
+// recibido por channel en receivedAt
+func (k *WebSocketClient) handleTickerAt(ticker wsTicker, channel, pairInterface string, receivedAt time.Time) error {
 	// Encontrar el par original
 	// Intentar primero con formato WS (XBT/USD)
-	originalPair, wsErr := fromWebSocketPair(pairInterface)
+	originalPair, wsErr := k.mapper.Load().FromWebSocket(pairInterface)
 	if wsErr != nil {
 		// Fallback a lógica previa basada en códigos internos
 		originalPair = k.findOriginalPairFromKraken(pairInterface)
@@ -480,24 +921,55 @@ func (k *WebSocketClient) handleTickerUpdate(data []interface{}) error {
 		return fmt.Errorf("unknown pair: %s", pairInterface)
 	}
 
-	// Crear entidad Price y enviar al canal
-	priceEntity := entities.NewPrice(
+	// Crear entidad Price con la hora del exchange si el mensaje la incluye
+	priceEntity := entities.NewPriceWithExchangeTime(
 		originalPair,
-		price,
-		time.Now(),
-		0,
+		ticker.Last,
+		ticker.ExchangeTime,
+		receivedAt,
 	)
+	priceEntity.Source = entities.PriceSourceWebSocket
+	priceEntity.Exchange = entities.ExchangeKraken
+	priceEntity.Channel = channel
+	priceEntity.Bid = ticker.Bid
+	priceEntity.Ask = ticker.Ask
+
+	validator := k.priceValidator()
+	var previous *entities.Price
+	if k.cache != nil && (validator != nil || k.dedupWindow > 0) {
+		previous, _ = k.cache.Get(context.Background(), originalPair)
+	}
 
-	// Actualizar cache global
-	if k.cache != nil {
-		_ = k.cache.Set(context.Background(), priceEntity)
+	// Descartar ticks sospechosos antes de cachear/entregar
+	k.notifyTick(originalPair, false)
+	if validator != nil {
+		if err := validator.Validate(k.logContext(), priceEntity, previous); err != nil {
+			k.notifyTick(originalPair, true)
+			return nil
+		}
+	}
+
+	// Un tick que repite el precio cacheado no se reescribe ni se notifica, pero
+	// sí se entrega a quien espera un precio del par
+	if isRepeatedTick(priceEntity, previous, k.dedupWindow) {
+		metrics.RecordPriceUpdateSuppressed(originalPair)
+	} else {
+		// Actualizar cache global
+		if k.cache != nil {
+			if err := k.cache.Set(context.Background(), priceEntity); err == nil {
+				metrics.RecordPricePipelineLatency(originalPair, priceEntity.Source, metrics.PipelineStageWSCache, receivedAt)
+			}
+		}
+
+		// Notificar consumidores basados en callbacks
+		k.notifyHandlers(priceEntity)
 	}
 
 	// Usar defer recover para manejar el caso de canal cerrado
 	defer func() {
 		if r := recover(); r != nil {
 			// Canal cerrado, ignorar silenciosamente
-			logging.Debug(context.Background(), "Channel closed during send", logging.Fields{
+			logging.Debug(k.logContext(), "Channel closed during send", logging.Fields{
 				"pair": originalPair,
 			})
 		}
@@ -513,26 +985,7 @@ func (k *WebSocketClient) handleTickerUpdate(data []interface{}) error {
 		return nil
 	}
 
-	select {
-	case priceChan <- priceEntity:
-		// Enviado exitosamente
-	default:
-		// Canal lleno, descartar precio más antiguo y registrar métrica
-		metrics.RecordWebSocketChannelDrop(originalPair)
-		select {
-		case <-priceChan:
-			// Descartado precio antiguo
-		default:
-			// Canal aún lleno, pero continuamos
-		}
-		// Intentar enviar el nuevo precio
-		select {
-		case priceChan <- priceEntity:
-			// Enviado después de limpiar
-		default:
-			// Canal sigue lleno, descartamos la actualización
-		}
-	}
+	k.deliver(priceChan, priceEntity)
 
 	return nil
 }
@@ -543,26 +996,141 @@ func (k *WebSocketClient) handleEventMessage(msg WebSocketMessage) error {
 	case "subscriptionStatus":
 		switch msg.Status {
 		case "subscribed":
-			logging.Info(context.Background(), "Successfully subscribed to ticker for pairs", logging.Fields{
+			for _, wsPair := range msg.Pair {
+				if pair, err := k.mapper.Load().FromWebSocket(wsPair); err == nil {
+					k.subs.confirm(pair)
+				}
+			}
+			logging.Info(k.logContext(), "Successfully subscribed to ticker for pairs", logging.Fields{
+				"pairs": msg.Pair,
+				"url":   k.currentURL(),
+			})
+		case "unsubscribed":
+			logging.Info(k.logContext(), "Unsubscribed from ticker for pairs", logging.Fields{
 				"pairs": msg.Pair,
-				"url":   k.url,
+				"url":   k.currentURL(),
 			})
 		case "error":
-			return fmt.Errorf("subscription error: %s", msg.ErrorMessage)
+			return k.handleSubscriptionError(msg)
 		}
 	case "systemStatus":
-		logging.Info(context.Background(), "Kraken WebSocket system status", logging.Fields{
-			"status": msg.Status,
-			"url":    k.url,
-		})
+		k.setSystemStatus(msg.Status)
+	case "heartbeat":
+		// Sólo señala que el canal sigue vivo: handleMessageAt ya registró el frame
 	}
 	return nil
 }
 
+// handleSubscriptionError registra el error de cada par del subscriptionStatus:
+// reconcileSubscriptions lo reintenta con backoff y, tras MaxSubscriptionFailures
+// errores, el par se excluye del WebSocket y se sirve por REST
+func (k *WebSocketClient) handleSubscriptionError(msg WebSocketMessage) error {
+	now := time.Now()
+	var unknown []string
+	for _, wsPair := range msg.Pair {
+		pair, err := k.mapper.Load().FromWebSocket(wsPair)
+		if err != nil {
+			unknown = append(unknown, wsPair)
+			continue
+		}
+
+		failure := k.subs.fail(pair, msg.ErrorMessage, now)
+		fields := logging.Fields{
+			"pair":     pair,
+			"error":    msg.ErrorMessage,
+			"failures": failure.Failures,
+			"url":      k.currentURL(),
+		}
+		if failure.Excluded {
+			logging.Error(k.logContext(), "WebSocket subscription keeps failing, pair excluded and served from REST", fields)
+			continue
+		}
+		fields["retry_in"] = failure.RetryAt.Sub(now).String()
+		logging.Warn(k.logContext(), "WebSocket subscription failed, retrying with backoff", fields)
+	}
+
+	if len(msg.Pair) == 0 || len(unknown) > 0 {
+		return fmt.Errorf("subscription error for %v: %s", unknown, msg.ErrorMessage)
+	}
+	return nil
+}
+
+// watchQuietChannel reconecta conn cuando no llega ningún frame en quietTimeout.
+// Los pongs mantienen el read deadline aunque Kraken deje de enviar datos, pero
+// sin otro tráfico Kraken envía un heartbeat por segundo: un canal en silencio
+// indica una conexión colgada del lado del exchange. Con maxConnAge también
+// recicla conn al cumplir esa edad (ver connectionAge).
+func (k *WebSocketClient) watchQuietChannel(conn *websocket.Conn) {
+	defer k.wg.Done()
+	quietTimeout := k.keepalive.withDefaults().quietTimeout
+	ticker := time.NewTicker(quietTimeout / 4)
+	defer ticker.Stop()
+	maxAge := k.connectionAge()
+	connectedAt := time.Now()
+
+	for {
+		select {
+		case <-k.ctx.Done():
+			return
+		case now := <-ticker.C:
+			k.mu.RLock()
+			current := k.conn == conn && k.isConnected
+			k.mu.RUnlock()
+			if !current {
+				return // la conexión ya se reemplazó; la nueva tiene su propio watcher
+			}
+
+			if maxAge > 0 && now.Sub(connectedAt) >= maxAge {
+				k.recycleConnection(conn, maxAge)
+				return
+			}
+
+			quiet := now.Sub(time.Unix(0, k.lastFrame.Load()))
+			if quiet < quietTimeout {
+				continue
+			}
+
+			metrics.RecordWebSocketQuietReconnect()
+			logging.Warn(k.logContext(), "WebSocket channel quiet, reconnecting", logging.Fields{
+				"quiet_ms":      quiet.Milliseconds(),
+				"quiet_timeout": quietTimeout.String(),
+				"url":           k.currentURL(),
+			})
+			// Vencer el read deadline interrumpe readMessages, que programa la reconexión
+			_ = conn.SetReadDeadline(time.Now())
+			return
+		}
+	}
+}
+
+// connectionAge es la edad a la que se recicla la próxima conexión: maxConnAge
+// más hasta un 10% al azar, para que los shards abiertos juntos no se reconecten
+// a la vez. 0 si las conexiones no tienen edad máxima.
+func (k *WebSocketClient) connectionAge() time.Duration {
+	if k.maxConnAge <= 0 {
+		return 0
+	}
+	return k.maxConnAge + time.Duration(rand.Int64N(int64(k.maxConnAge)/10+1))
+}
+
+// recycleConnection cierra conn, que cumplió su edad máxima, y programa la
+// reconexión, que vuelve a resolver el DNS: una conexión de larga duración no
+// queda atada a una IP que Kraken dejó de anunciar
+func (k *WebSocketClient) recycleConnection(conn *websocket.Conn, age time.Duration) {
+	logging.Info(k.logContext(), "WebSocket connection reached max age, reconnecting", logging.Fields{
+		"max_connection_age": age.String(),
+		"url":                k.currentURL(),
+	})
+	k.scheduleReconnect(ReconnectReasonMaxAge)
+	deadline := time.Now().Add(k.keepalive.withDefaults().writeWait)
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "max connection age"), deadline)
+	_ = conn.Close()
+}
+
 // pingHandler envía pings periódicos para mantener la conexión activa
 func (k *WebSocketClient) pingHandler() {
 	defer k.wg.Done()
-	ticker := time.NewTicker(PingInterval)
+	ticker := time.NewTicker(k.keepalive.withDefaults().pingInterval)
 	defer ticker.Stop()
 
 	for {
@@ -577,15 +1145,106 @@ func (k *WebSocketClient) pingHandler() {
 				return
 			}
 			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				k.scheduleReconnect()
+				k.scheduleReconnect(ReconnectReasonPingFailed)
 				return
 			}
 		}
 	}
 }
 
-// scheduleReconnect programa un intento de reconexión con gestión de estado mejorada
-func (k *WebSocketClient) scheduleReconnect() {
+// reconcileSubscriptions reenvía las suscripciones que no recibieron
+// subscriptionStatus dentro de SubscriptionConfirmTimeout
+func (k *WebSocketClient) reconcileSubscriptions() {
+	defer k.wg.Done()
+	ticker := time.NewTicker(SubscriptionConfirmTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k.ctx.Done():
+			return
+		case now := <-ticker.C:
+			k.retryFailedSubscriptions(now)
+
+			expired := k.subs.expired(now, SubscriptionConfirmTimeout)
+			if len(expired) == 0 {
+				continue
+			}
+
+			logging.Warn(k.logContext(), "WebSocket subscriptions not confirmed in time, retrying", logging.Fields{
+				"pairs":   expired,
+				"timeout": SubscriptionConfirmTimeout.String(),
+				"url":     k.currentURL(),
+			})
+			for _, pair := range expired {
+				metrics.RecordWebSocketSubscriptionRetry(pair)
+			}
+			if err := k.SubscribeTicker(expired); err != nil {
+				logging.Warn(k.logContext(), "Failed to re-send unconfirmed subscriptions", logging.Fields{
+					"pairs": expired,
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// retryFailedSubscriptions reenvía las suscripciones rechazadas por Kraken cuyo
+// backoff venció
+func (k *WebSocketClient) retryFailedSubscriptions(now time.Time) {
+	failed := k.subs.retryable(now)
+	if len(failed) == 0 {
+		return
+	}
+
+	logging.Info(k.logContext(), "Retrying failed WebSocket subscriptions", logging.Fields{
+		"pairs": failed,
+		"url":   k.currentURL(),
+	})
+	for _, pair := range failed {
+		metrics.RecordWebSocketSubscriptionRetry(pair)
+	}
+	if err := k.SubscribeTicker(failed); err != nil {
+		logging.Warn(k.logContext(), "Failed to retry failed WebSocket subscriptions", logging.Fields{
+			"pairs": failed,
+			"error": err.Error(),
+		})
+	}
+}
+
+// SubscriptionFailures retorna los errores de suscripción informados por Kraken
+// desde la última confirmación de cada par, incluidos los pares excluidos
+func (k *WebSocketClient) SubscriptionFailures() []SubscriptionFailure {
+	return k.subs.failures()
+}
+
+// SetPriceValidator configura la validación de ticks previa al cacheo
+func (k *WebSocketClient) SetPriceValidator(validator interfaces.PriceValidator) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.validator = validator
+}
+
+func (k *WebSocketClient) priceValidator() interfaces.PriceValidator {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.validator
+}
+
+// SetPairMapper habilita el mapeo dinámico de pares; nil vuelve a los mapas estáticos
+func (k *WebSocketClient) SetPairMapper(mapper *PairMapper) {
+	k.mapper.Store(mapper)
+}
+
+// GetSubscriptionStatus retorna los pares confirmados por Kraken y los pendientes de confirmación
+func (k *WebSocketClient) GetSubscriptionStatus() (confirmed, pending []string) {
+	return k.subs.snapshot()
+}
+
+// scheduleReconnect programa un intento de reconexión con gestión de estado
+// mejorada; reason es el motivo de la pérdida de la conexión, que los reintentos
+// conservan
+func (k *WebSocketClient) scheduleReconnect(reason string) {
 	k.mu.Lock()
 	defer k.mu.Unlock()
 
@@ -597,6 +1256,15 @@ func (k *WebSocketClient) scheduleReconnect() {
 	k.isConnected = false
 	k.isReconnecting = true
 	k.reconnectCount++
+	if k.reconnectCount == 1 {
+		k.lostAt = time.Now()
+		k.lostReason = reason
+	}
+	k.publishConnectionStatus()
+	k.subs.reset() // las confirmaciones previas no valen para la nueva conexión
+	if k.onDisconnect != nil {
+		go k.onDisconnect()
+	}
 
 	// Implementar backoff exponencial con máximo de 60 segundos
 	delay := time.Duration(k.reconnectCount) * time.Second
@@ -606,18 +1274,18 @@ func (k *WebSocketClient) scheduleReconnect() {
 
 	// Límite máximo de reintentos para evitar reconexión infinita
 	if k.reconnectCount > 10 {
-		logging.Error(context.Background(), "Maximum WebSocket reconnection attempts reached", logging.Fields{
+		logging.Error(k.logContext(), "Maximum WebSocket reconnection attempts reached", logging.Fields{
 			"max_attempts": k.reconnectCount,
-			"url":          k.url,
+			"url":          k.currentURL(),
 		})
 		k.isReconnecting = false
 		return
 	}
 
-	logging.Info(context.Background(), "Scheduling WebSocket reconnection", logging.Fields{
+	logging.Info(k.logContext(), "Scheduling WebSocket reconnection", logging.Fields{
 		"delay_seconds": delay.Seconds(),
 		"attempt":       k.reconnectCount,
-		"url":           k.url,
+		"url":           k.currentURL(),
 	})
 
 	k.reconnectTimer = time.AfterFunc(delay, func() {
@@ -636,25 +1304,31 @@ func (k *WebSocketClient) performReconnect() {
 		return
 	}
 
-	logging.Info(context.Background(), "Attempting WebSocket reconnection", logging.Fields{
+	logging.Info(k.logContext(), "Attempting WebSocket reconnection", logging.Fields{
 		"attempt": k.reconnectCount,
-		"url":     k.url,
+		"url":     k.currentURL(),
 	})
 
-	if err := k.Connect(); err != nil {
-		logging.Warn(context.Background(), "WebSocket reconnection attempt failed", logging.Fields{
+	// Las IPs de la conexión anterior pueden ya no ser válidas tras un failover de DNS
+	k.transport.refreshDNS()
+
+	if err := k.connect(k.ctx); err != nil {
+		logging.Warn(k.logContext(), "WebSocket reconnection attempt failed", logging.Fields{
 			"attempt": k.reconnectCount,
 			"error":   err.Error(),
-			"url":     k.url,
+			"url":     k.currentURL(),
 		})
+		k.notifyReconnect(err)
 		// Programar siguiente intento
-		k.scheduleReconnect()
+		k.scheduleReconnect("")
 	} else {
-		logging.Info(context.Background(), "WebSocket reconnected successfully", logging.Fields{
+		logging.Info(k.logContext(), "WebSocket reconnected successfully", logging.Fields{
 			"attempts_taken": k.reconnectCount,
-			"url":            k.url,
+			"url":            k.currentURL(),
 		})
 
+		k.notifyReconnect(nil)
+
 		// Reset del contador de reconexión
 		k.mu.Lock()
 		k.isReconnecting = false
@@ -665,7 +1339,9 @@ func (k *WebSocketClient) performReconnect() {
 		k.mu.RLock()
 		var pairs []string
 		for pair := range k.subscriptions {
-			pairs = append(pairs, pair)
+			if !k.subs.excluded(pair) {
+				pairs = append(pairs, pair)
+			}
 		}
 		k.mu.RUnlock()
 
@@ -676,31 +1352,31 @@ func (k *WebSocketClient) performReconnect() {
 				for _, p := range pairs {
 					if subErr := k.SubscribeTicker([]string{p}); subErr != nil {
 						failed = append(failed, p)
-						logging.Warn(context.Background(), "Failed to re-subscribe individual pair after reconnect", logging.Fields{
+						logging.Warn(k.logContext(), "Failed to re-subscribe individual pair after reconnect", logging.Fields{
 							"pair":  p,
 							"error": subErr.Error(),
-							"url":   k.url,
+							"url":   k.currentURL(),
 						})
 					}
 				}
 
 				if len(failed) > 0 {
-					logging.Error(context.Background(), "Re-subscription completed with failures", logging.Fields{
+					logging.Error(k.logContext(), "Re-subscription completed with failures", logging.Fields{
 						"failed_pairs": failed,
 						"failed_count": len(failed),
-						"url":          k.url,
+						"url":          k.currentURL(),
 					})
 				} else {
-					logging.Info(context.Background(), "Successfully re-subscribed all pairs after granular retry", logging.Fields{
+					logging.Info(k.logContext(), "Successfully re-subscribed all pairs after granular retry", logging.Fields{
 						"pairs_count": len(pairs),
-						"url":         k.url,
+						"url":         k.currentURL(),
 					})
 				}
 			} else {
-				logging.Info(context.Background(), "Successfully re-subscribed to pairs after reconnect", logging.Fields{
+				logging.Info(k.logContext(), "Successfully re-subscribed to pairs after reconnect", logging.Fields{
 					"pairs_count": len(pairs),
 					"pairs":       pairs,
-					"url":         k.url,
+					"url":         k.currentURL(),
 				})
 			}
 		}
@@ -713,13 +1389,13 @@ func (k *WebSocketClient) findOriginalPairFromKraken(krakenPair string) string {
 	defer k.mu.RUnlock()
 
 	for originalPair := range k.subscriptions {
-		if krakenConverted, err := toKrakenPair(originalPair); err == nil && krakenConverted == krakenPair {
+		if krakenConverted, err := k.mapper.Load().ToRest(originalPair); err == nil && krakenConverted == krakenPair {
 			return originalPair
 		}
 	}
 
 	// Intentar conversión inversa usando FromKrakenPair
-	if friendlyPair, err := FromKrakenPair(krakenPair); err == nil {
+	if friendlyPair, err := k.mapper.Load().FromRest(krakenPair); err == nil {
 		return friendlyPair
 	}
 
@@ -733,6 +1409,23 @@ func (k *WebSocketClient) IsConnected() bool {
 	return k.isConnected
 }
 
+// setRole cambia la etiqueta de métricas de la conexión y el callback de
+// desconexión; lo usa ShardedWebSocketClient al promover la conexión standby
+func (k *WebSocketClient) setRole(shard string, onDisconnect func()) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.shard = shard
+	k.onDisconnect = onDisconnect
+	k.publishConnectionStatus()
+}
+
+// publishConnectionStatus actualiza el estado del shard en métricas (requiere lock)
+func (k *WebSocketClient) publishConnectionStatus() {
+	if k.shard != "" {
+		metrics.UpdateWebSocketShardStatus(k.shard, k.isConnected)
+	}
+}
+
 // GetReconnectionStatus retorna información sobre el estado de reconexión
 func (k *WebSocketClient) GetReconnectionStatus() (isReconnecting bool, attemptCount int) {
 	k.mu.RLock()
@@ -3,17 +3,23 @@ package kraken
 import (
 	"btc-ltp-service/internal/domain/entities"
 	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
 	cachepkg "btc-ltp-service/internal/infrastructure/repositories/cache"
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -173,6 +179,84 @@ func TestNewWebSocketClientWithConfig_CustomConfiguration(t *testing.T) {
 	assert.NotNil(t, client.cache)
 }
 
+func TestNewWebSocketClientWithConfig_Keepalive(t *testing.T) {
+	client := NewWebSocketClientWithConfig(config.KrakenConfig{
+		WebSocketTimeouts: config.WebSocketTimeoutsConfig{PingInterval: 5 * time.Second, PongWait: 12 * time.Second},
+	})
+
+	keepalive := client.keepalive.withDefaults()
+	assert.Equal(t, 5*time.Second, keepalive.pingInterval)
+	assert.Equal(t, 12*time.Second, keepalive.pongWait)
+	assert.Equal(t, WriteWait, keepalive.writeWait, "zero uses the package default")
+	assert.Equal(t, QuietTimeout, keepalive.quietTimeout)
+}
+
+func TestWebSocketClient_ClosesConnectionOnOversizedFrame(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := createTestWebSocketClient(mockServer.getURL())
+	client.maxFrameBytes = 4 << 10
+	require.NoError(t, client.Connect())
+	defer func() { _ = client.Close() }()
+
+	var conn *safeWebSocketConn
+	require.Eventually(t, func() bool {
+		mockServer.mu.Lock()
+		defer mockServer.mu.Unlock()
+		if len(mockServer.clients) > 0 {
+			conn = mockServer.clients[0]
+		}
+		return conn != nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, conn.WriteJSON([]string{strings.Repeat("x", 8<<10)}))
+
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.WebSocket.OversizedFrames) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(DefaultMaxFrameBytes), NewWebSocketClient().frameLimit(), "0 uses the default")
+}
+
+func TestWebSocketClient_ReconnectsQuietChannel(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := createTestWebSocketClient(mockServer.getURL())
+	client.keepalive.quietTimeout = 200 * time.Millisecond
+	require.NoError(t, client.Connect())
+	defer func() { _ = client.Close() }()
+
+	var conn *safeWebSocketConn
+	require.Eventually(t, func() bool {
+		mockServer.mu.Lock()
+		defer mockServer.mu.Unlock()
+		if len(mockServer.clients) > 0 {
+			conn = mockServer.clients[0]
+		}
+		return conn != nil
+	}, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 8; i++ {
+		require.NoError(t, conn.WriteJSON(map[string]string{"event": "heartbeat"}))
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.True(t, client.IsConnected(), "heartbeats keep the channel alive")
+	assert.Zero(t, testutil.ToFloat64(m.WebSocket.QuietReconnects))
+
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.WebSocket.QuietReconnects) == 1
+	}, 2*time.Second, 10*time.Millisecond, "no heartbeats nor data within quiet_timeout")
+	assert.Eventually(t, func() bool { return !client.IsConnected() }, time.Second, 10*time.Millisecond)
+}
+
 func TestWebSocketClient_Connect_Success(t *testing.T) {
 	mockServer := newMockWebSocketServer()
 	defer mockServer.close()
@@ -200,6 +284,36 @@ func TestWebSocketClient_Close_Success(t *testing.T) {
 	assert.False(t, client.IsConnected())
 }
 
+func TestWebSocketClient_ConnectContext_Shutdown(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := createTestWebSocketClient(mockServer.getURL())
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.ConnectContext(ctx))
+	assert.True(t, client.IsConnected())
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer shutdownCancel()
+	assert.NoError(t, client.Shutdown(shutdownCtx))
+	assert.False(t, client.IsConnected())
+}
+
+func TestWebSocketClient_ConnectContext_Canceled(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := createTestWebSocketClient(mockServer.getURL())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.ConnectContext(ctx)
+	assert.ErrorIs(t, err, ErrConnectionFailed)
+	assert.False(t, client.IsConnected())
+}
+
 func TestWebSocketClient_SubscribeTicker_Success(t *testing.T) {
 	mockServer := newMockWebSocketServer()
 	defer mockServer.close()
@@ -314,6 +428,65 @@ func TestWebSocketClient_Connect_AlreadyConnected(t *testing.T) {
 	assert.NoError(t, err) // No debería dar error, simplemente no hace nada
 }
 
+func TestWebSocketClient_Connect_DialsWithoutLock(t *testing.T) {
+	release := make(chan struct{})
+	handshakes := make(chan struct{}, 2)
+	closed := make(chan struct{}, 2)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handshakes <- struct{}{}
+		<-release
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				closed <- struct{}{}
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := createTestWebSocketClient("ws" + strings.TrimPrefix(server.URL, "http"))
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- client.Connect() }()
+	}
+
+	// Ambos dials esperan el handshake a la vez y el estado del cliente sigue legible
+	for i := 0; i < 2; i++ {
+		select {
+		case <-handshakes:
+		case <-time.After(time.Second):
+			close(release)
+			require.FailNow(t, "dials are serialized by the client lock")
+		}
+	}
+	state := make(chan bool)
+	go func() { state <- client.IsConnected() }()
+	select {
+	case connected := <-state:
+		assert.False(t, connected)
+	case <-time.After(time.Second):
+		require.FailNow(t, "IsConnected blocked while dialing")
+	}
+
+	close(release)
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+	assert.True(t, client.IsConnected())
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "the connection that lost the race was not closed")
+	}
+
+	_ = client.Close()
+}
+
 func TestWebSocketClient_SubscribeTicker_NotConnected(t *testing.T) {
 	client := createTestWebSocketClient("ws://localhost:9999")
 
@@ -737,6 +910,30 @@ func TestWebSocketClient_handleTickerUpdate_ValidData(t *testing.T) {
 	}
 }
 
+func TestWebSocketClient_handleTickerUpdate_ExchangeMetadata(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.subscriptions["BTC/USD"] = true
+	client.priceChannels["BTC/USD"] = make(chan *entities.Price, 1)
+
+	err := client.handleTickerUpdate([]interface{}{
+		1,
+		map[string]interface{}{
+			"a": []interface{}{"50001.5", 1, "1.000"},
+			"b": []interface{}{"49999.5", 2, "2.000"},
+			"c": []interface{}{"50000.0", "1.0"},
+		},
+		"ticker",
+		"XBT/USD",
+	})
+	require.NoError(t, err)
+
+	price := <-client.priceChannels["BTC/USD"]
+	assert.Equal(t, entities.ExchangeKraken, price.Exchange)
+	assert.Equal(t, "ticker", price.Channel)
+	assert.Equal(t, 49999.5, price.Bid)
+	assert.Equal(t, 50001.5, price.Ask)
+}
+
 func TestWebSocketClient_handleTickerUpdate_InvalidFormat(t *testing.T) {
 	client := createTestWebSocketClient("ws://localhost:9999")
 
@@ -944,7 +1141,7 @@ func TestWebSocketClient_scheduleReconnect_StateManagement(t *testing.T) {
 			}
 
 			// Llamar scheduleReconnect
-			client.scheduleReconnect()
+			client.scheduleReconnect(ReconnectReasonReadError)
 
 			// Verificar estado final
 			client.mu.RLock()
@@ -972,3 +1169,60 @@ func TestWebSocketClient_scheduleReconnect_StateManagement(t *testing.T) {
 		})
 	}
 }
+
+func TestDisconnectReason(t *testing.T) {
+	assert.Equal(t, ReconnectReasonOversizeFrame, disconnectReason(websocket.ErrReadLimit))
+	assert.Equal(t, ReconnectReasonReadTimeout, disconnectReason(&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}))
+	assert.Equal(t, ReconnectReasonClosed, disconnectReason(&websocket.CloseError{Code: websocket.CloseAbnormalClosure}))
+	assert.Equal(t, ReconnectReasonClosed, disconnectReason(fmt.Errorf("read: %w", net.ErrClosed)))
+	assert.Equal(t, ReconnectReasonReadError, disconnectReason(errors.New("boom")))
+}
+
+func TestWebSocketClient_NotifyReconnectReportsTheLostConnection(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.subscriptions = map[string]bool{"ETH/USD": true, "BTC/USD": true}
+	client.isConnected = true
+	client.scheduleReconnect(ReconnectReasonPingFailed)
+	client.mu.Lock()
+	if client.reconnectTimer != nil {
+		client.reconnectTimer.Stop()
+	}
+	lostAt := client.lostAt
+	client.mu.Unlock()
+
+	var events []ReconnectEvent
+	client.OnReconnect(func(event ReconnectEvent) { events = append(events, event) })
+	client.notifyReconnect(errors.New("dial failed"))
+
+	require.Len(t, events, 1)
+	assert.Equal(t, lostAt, events[0].Started)
+	assert.Equal(t, ReconnectReasonPingFailed, events[0].Reason)
+	assert.Equal(t, 1, events[0].Attempt)
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, events[0].Pairs)
+	assert.EqualError(t, events[0].Err, "dial failed")
+}
+
+func TestWebSocketClient_RecyclesConnectionAtMaxAge(t *testing.T) {
+	server := newMockWebSocketServer()
+	defer server.close()
+
+	client := createTestWebSocketClient(server.getURL())
+	client.maxConnAge = 20 * time.Millisecond
+	client.keepalive.quietTimeout = 200 * time.Millisecond
+	events := make(chan ReconnectEvent, 1)
+	client.OnReconnect(func(event ReconnectEvent) { events <- event })
+	defer func() { _ = client.Close() }()
+
+	assert.GreaterOrEqual(t, client.connectionAge(), client.maxConnAge)
+	assert.LessOrEqual(t, client.connectionAge(), client.maxConnAge+client.maxConnAge/10)
+
+	require.NoError(t, client.Connect())
+	select {
+	case event := <-events:
+		assert.Equal(t, ReconnectReasonMaxAge, event.Reason)
+		assert.NoError(t, event.Err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection was not recycled")
+	}
+	assert.True(t, client.IsConnected())
+}
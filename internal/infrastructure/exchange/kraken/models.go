@@ -33,6 +33,17 @@ func (t *KrakenTickerData) GetLastTradedPrice() (float64, error) {
 	return strconv.ParseFloat(t.LastTradeClosed[0], 64)
 }
 
+// GetBidAsk extrae la mejor compra y venta del ticker; 0 si no vienen o no se pueden parsear
+func (t *KrakenTickerData) GetBidAsk() (bid, ask float64) {
+	if len(t.Bid) > 0 {
+		bid, _ = strconv.ParseFloat(t.Bid[0], 64)
+	}
+	if len(t.Ask) > 0 {
+		ask, _ = strconv.ParseFloat(t.Ask[0], 64)
+	}
+	return bid, ask
+}
+
 // GetTimestamp retorna el timestamp actual ya que Kraken no proporciona timestamp en el ticker
 func (t *KrakenTickerData) GetTimestamp() time.Time {
 	return time.Now()
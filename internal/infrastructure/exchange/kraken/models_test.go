@@ -22,6 +22,21 @@ func TestKrakenTickerData_GetLastTradedPrice_Success(t *testing.T) {
 	assert.Equal(t, 50000.5, price)
 }
 
+func TestKrakenTickerData_GetBidAsk(t *testing.T) {
+	tickerData := KrakenTickerData{
+		Ask: []string{"50001.0", "1", "1"},
+		Bid: []string{"49999.0", "1", "1"},
+	}
+
+	bid, ask := tickerData.GetBidAsk()
+	assert.Equal(t, 49999.0, bid)
+	assert.Equal(t, 50001.0, ask)
+
+	bid, ask = (&KrakenTickerData{Bid: []string{"invalid"}}).GetBidAsk()
+	assert.Zero(t, bid, "unparseable bid is unknown")
+	assert.Zero(t, ask, "missing ask is unknown")
+}
+
 func TestKrakenTickerData_GetTimestamp_ReturnsCurrentTime(t *testing.T) {
 	tickerData := KrakenTickerData{}
 
@@ -0,0 +1,297 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPairsRefreshInterval es la frecuencia por defecto de recarga de AssetPairs
+const DefaultPairsRefreshInterval = time.Hour
+
+// wsAssetAliases normaliza los nombres de activos que Kraken expone en wsname
+var wsAssetAliases = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+// KrakenAssetPairsResponse representa la respuesta del endpoint AssetPairs
+type KrakenAssetPairsResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]KrakenAssetPair `json:"result"`
+}
+
+// KrakenAssetPair contiene los metadatos relevantes de un par de Kraken
+type KrakenAssetPair struct {
+	AltName      string `json:"altname"`
+	WSName       string `json:"wsname"`
+	Base         string `json:"base"`
+	Quote        string `json:"quote"`
+	Status       string `json:"status,omitempty"`
+	PairDecimals int    `json:"pair_decimals"`
+	LotDecimals  int    `json:"lot_decimals"`
+}
+
+// PairInfo describe los nombres de un par en cada API de Kraken
+type PairInfo struct {
+	Pair          string // formato amistoso BASE/QUOTE (BTC/USD)
+	RestName      string // clave devuelta por AssetPairs/Ticker (XXBTZUSD)
+	AltName       string // nombre alternativo REST (XBTUSD)
+	WSName        string // nombre usado en WebSocket (XBT/USD)
+	PriceDecimals int    // decimales del precio (pair_decimals)
+	LotDecimals   int    // decimales del volumen (lot_decimals)
+	Status        string // online, cancel_only, ...
+}
+
+// PairMapper mantiene el mapeo de pares cargado desde AssetPairs de Kraken.
+// Los pares desconocidos recurren a los mapas estáticos, de modo que el servicio
+// sigue funcionando sin conectividad al iniciar.
+type PairMapper struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu         sync.RWMutex
+	byPair     map[string]PairInfo
+	byRestName map[string]string
+	byWSName   map[string]string
+	loadedAt   time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewPairMapper crea un mapper vacío que consulta AssetPairs en baseURL
+func NewPairMapper(baseURL string, timeout time.Duration) *PairMapper {
+	if baseURL == "" {
+		baseURL = KrakenAPIBaseURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &PairMapper{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+		byPair:     make(map[string]PairInfo),
+		byRestName: make(map[string]string),
+		byWSName:   make(map[string]string),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// SetConnectionConfig aplica a AssetPairs la allowlist de salida y la validación
+// TLS de cfg; llamar antes de Start
+func (m *PairMapper) SetConnectionConfig(cfg config.KrakenConfig) {
+	m.httpClient = newTransport(cfg).httpClient(m.httpClient.Timeout)
+}
+
+// Start carga AssetPairs con ctx y lanza el refresco periódico hasta Stop.
+// Un fallo de la carga inicial sólo se registra: se usan los mapas estáticos.
+func (m *PairMapper) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPairsRefreshInterval
+	}
+
+	if err := m.Load(ctx); err != nil {
+		logging.Warn(ctx, "Failed to load Kraken AssetPairs, using static pair mapping", logging.Fields{
+			"error": err.Error(),
+		})
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				refreshCtx, cancel := context.WithTimeout(context.Background(), m.httpClient.Timeout)
+				if err := m.Load(refreshCtx); err != nil {
+					logging.Warn(refreshCtx, "Failed to refresh Kraken AssetPairs", logging.Fields{
+						"error": err.Error(),
+					})
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop detiene el refresco periódico (idempotente)
+func (m *PairMapper) Stop() {
+	m.stopOnce.Do(func() { close(m.stopCh) })
+}
+
+// Load descarga AssetPairs y reemplaza el mapeo actual
+func (m *PairMapper) Load(ctx context.Context) error {
+	url := fmt.Sprintf("%s/AssetPairs", m.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRetryableRequest, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: HTTP %d", ErrRetryableRequest, resp.StatusCode)
+	}
+
+	var parsed KrakenAssetPairsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return fmt.Errorf("failed to decode AssetPairs response: %w", err)
+	}
+	if len(parsed.Error) > 0 {
+		return fmt.Errorf("%w: %s", ErrNonRetryable, strings.Join(parsed.Error, ", "))
+	}
+
+	return m.load(parsed.Result)
+}
+
+// load reemplaza el mapeo con las entradas de AssetPairs
+func (m *PairMapper) load(result map[string]KrakenAssetPair) error {
+	byPair := make(map[string]PairInfo, len(result))
+	byRestName := make(map[string]string, len(result)*2)
+	byWSName := make(map[string]string, len(result))
+
+	for restName, ap := range result {
+		pair, err := friendlyFromWSName(ap.WSName)
+		if err != nil {
+			continue // pares dark pool (.d) y otros sin wsname
+		}
+		byPair[pair] = PairInfo{
+			Pair:          pair,
+			RestName:      restName,
+			AltName:       ap.AltName,
+			WSName:        ap.WSName,
+			PriceDecimals: ap.PairDecimals,
+			LotDecimals:   ap.LotDecimals,
+			Status:        ap.Status,
+		}
+		byRestName[strings.ToUpper(restName)] = pair
+		if ap.AltName != "" {
+			byRestName[strings.ToUpper(ap.AltName)] = pair
+		}
+		byWSName[strings.ToUpper(ap.WSName)] = pair
+	}
+
+	if len(byPair) == 0 {
+		return errors.New("AssetPairs response contained no usable pairs")
+	}
+
+	m.mu.Lock()
+	m.byPair = byPair
+	m.byRestName = byRestName
+	m.byWSName = byWSName
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+
+	logging.Info(context.Background(), "Kraken AssetPairs loaded", logging.Fields{
+		"pairs_count": len(byPair),
+	})
+	return nil
+}
+
+// friendlyFromWSName convierte XBT/USD en BTC/USD
+func friendlyFromWSName(wsName string) (string, error) {
+	parts := strings.Split(strings.ToUpper(wsName), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid WS pair format: %s", wsName)
+	}
+	for i, asset := range parts {
+		if alias, ok := wsAssetAliases[asset]; ok {
+			parts[i] = alias
+		}
+	}
+	return parts[0] + "/" + parts[1], nil
+}
+
+// lookup retorna la información de un par en formato amistoso
+func (m *PairMapper) lookup(pair string) (PairInfo, bool) {
+	if m == nil {
+		return PairInfo{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	info, ok := m.byPair[strings.ToUpper(pair)]
+	return info, ok
+}
+
+// Info retorna los metadatos de AssetPairs de un par; false si no se cargaron
+func (m *PairMapper) Info(pair string) (PairInfo, bool) {
+	return m.lookup(pair)
+}
+
+// ToRest convierte BTC/USD al nombre REST de Kraken
+func (m *PairMapper) ToRest(pair string) (string, error) {
+	if info, ok := m.lookup(pair); ok {
+		return info.RestName, nil
+	}
+	return toKrakenPair(pair)
+}
+
+// ToWebSocket convierte BTC/USD al nombre WebSocket de Kraken
+func (m *PairMapper) ToWebSocket(pair string) (string, error) {
+	if info, ok := m.lookup(pair); ok {
+		return info.WSName, nil
+	}
+	return toWebSocketPair(pair)
+}
+
+// FromRest convierte un nombre REST (clave o altname) a formato amistoso
+func (m *PairMapper) FromRest(restName string) (string, error) {
+	if m != nil {
+		m.mu.RLock()
+		pair, ok := m.byRestName[strings.ToUpper(restName)]
+		m.mu.RUnlock()
+		if ok {
+			return pair, nil
+		}
+	}
+	return FromKrakenPair(restName)
+}
+
+// FromWebSocket convierte un nombre WebSocket a formato amistoso
+func (m *PairMapper) FromWebSocket(wsName string) (string, error) {
+	if m != nil {
+		m.mu.RLock()
+		pair, ok := m.byWSName[strings.ToUpper(wsName)]
+		m.mu.RUnlock()
+		if ok {
+			return pair, nil
+		}
+	}
+	return fromWebSocketPair(wsName)
+}
+
+// Pairs retorna los pares cargados desde AssetPairs, ordenados
+func (m *PairMapper) Pairs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pairs := make([]string, 0, len(m.byPair))
+	for pair := range m.byPair {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Loaded indica si AssetPairs se cargó al menos una vez
+func (m *PairMapper) Loaded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.loadedAt.IsZero()
+}
@@ -0,0 +1,168 @@
+package kraken
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assetPairsFixture = `{
+	"error": [],
+	"result": {
+		"XXBTZUSD": {"altname": "XBTUSD", "wsname": "XBT/USD", "base": "XXBT", "quote": "ZUSD", "pair_decimals": 1, "lot_decimals": 8, "status": "online"},
+		"ADAUSD": {"altname": "ADAUSD", "wsname": "ADA/USD", "base": "ADA", "quote": "ZUSD"},
+		"SOLEUR": {"altname": "SOLEUR", "wsname": "SOL/EUR", "base": "SOL", "quote": "ZEUR"},
+		"XDGUSD": {"altname": "XDGUSD", "wsname": "XDG/USD", "base": "XXDG", "quote": "ZUSD"},
+		"XXBTZUSD.d": {"altname": "XBTUSD.d", "base": "XXBT", "quote": "ZUSD"}
+	}
+}`
+
+func newAssetPairsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/AssetPairs", r.URL.Path)
+		_, _ = w.Write([]byte(assetPairsFixture))
+	}))
+}
+
+func TestPairMapper_Load(t *testing.T) {
+	server := newAssetPairsServer(t)
+	defer server.Close()
+
+	mapper := NewPairMapper(server.URL, time.Second)
+	require.NoError(t, mapper.Load(context.Background()))
+
+	assert.True(t, mapper.Loaded())
+	assert.Equal(t, []string{"ADA/USD", "BTC/USD", "DOGE/USD", "SOL/EUR"}, mapper.Pairs())
+
+	rest, err := mapper.ToRest("ada/usd")
+	require.NoError(t, err)
+	assert.Equal(t, "ADAUSD", rest)
+
+	ws, err := mapper.ToWebSocket("SOL/EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "SOL/EUR", ws)
+
+	ws, err = mapper.ToWebSocket("DOGE/USD")
+	require.NoError(t, err)
+	assert.Equal(t, "XDG/USD", ws)
+
+	pair, err := mapper.FromRest("XBTUSD")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USD", pair)
+
+	pair, err = mapper.FromWebSocket("XBT/USD")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USD", pair)
+
+	info, ok := mapper.Info("btc/usd")
+	require.True(t, ok)
+	assert.Equal(t, 1, info.PriceDecimals)
+	assert.Equal(t, 8, info.LotDecimals)
+	assert.Equal(t, "online", info.Status)
+}
+
+func TestPairMapper_FallsBackToStaticMaps(t *testing.T) {
+	var mapper *PairMapper // sin mapper configurado
+
+	rest, err := mapper.ToRest("ETH/EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "XETHZEUR", rest)
+
+	pair, err := mapper.FromWebSocket("XBT/EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/EUR", pair)
+
+	_, err = NewPairMapper("", 0).ToRest("ADA/USD")
+	assert.Error(t, err, "unknown pairs fail until AssetPairs is loaded")
+}
+
+func TestPairMapper_FiatQuotes(t *testing.T) {
+	mapper := NewPairMapper("", time.Second)
+	require.NoError(t, mapper.load(map[string]KrakenAssetPair{
+		"XBTCHF":   {AltName: "XBTCHF", WSName: "XBT/CHF", Base: "XXBT", Quote: "CHF"},
+		"XXBTZGBP": {AltName: "XBTGBP", WSName: "XBT/GBP", Base: "XXBT", Quote: "ZGBP"},
+		"XXBTZJPY": {AltName: "XBTJPY", WSName: "XBT/JPY", Base: "XXBT", Quote: "ZJPY"},
+	}))
+
+	tests := []struct {
+		pair, rest, altName, ws string
+	}{
+		{"BTC/CHF", "XBTCHF", "XBTCHF", "XBT/CHF"},
+		{"BTC/GBP", "XXBTZGBP", "XBTGBP", "XBT/GBP"},
+		{"BTC/JPY", "XXBTZJPY", "XBTJPY", "XBT/JPY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pair, func(t *testing.T) {
+			rest, err := mapper.ToRest(tt.pair)
+			require.NoError(t, err)
+			assert.Equal(t, tt.rest, rest)
+			ws, err := mapper.ToWebSocket(tt.pair)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ws, ws)
+
+			for _, name := range []string{tt.rest, tt.altName} {
+				pair, err := mapper.FromRest(name)
+				require.NoError(t, err)
+				assert.Equal(t, tt.pair, pair)
+			}
+			pair, err := mapper.FromWebSocket(tt.ws)
+			require.NoError(t, err)
+			assert.Equal(t, tt.pair, pair)
+
+			// Sin AssetPairs cargado los mapas estáticos producen los mismos nombres
+			var static *PairMapper
+			rest, err = static.ToRest(tt.pair)
+			require.NoError(t, err)
+			assert.Equal(t, tt.rest, rest)
+			pair, err = static.FromRest(tt.altName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.pair, pair)
+		})
+	}
+}
+
+func TestPairMapper_LoadErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":["EGeneral:Internal error"],"result":{}}`))
+	}))
+	defer server.Close()
+
+	mapper := NewPairMapper(server.URL, time.Second)
+	err := mapper.Load(context.Background())
+	assert.ErrorIs(t, err, ErrNonRetryable)
+	assert.False(t, mapper.Loaded())
+
+	// Start no falla aunque la carga inicial falle
+	mapper.Start(context.Background(), time.Hour)
+	mapper.Stop()
+	mapper.Stop()
+}
+
+func TestRestClient_UsesPairMapper(t *testing.T) {
+	server := newAssetPairsServer(t)
+	defer server.Close()
+
+	mapper := NewPairMapper(server.URL, time.Second)
+	require.NoError(t, mapper.Load(context.Background()))
+
+	ticker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ADAUSD", r.URL.Query().Get("pair"))
+		_, _ = w.Write([]byte(`{"error":[],"result":{"ADAUSD":{"c":["0.45","10"]}}}`))
+	}))
+	defer ticker.Close()
+
+	client := NewRestClient()
+	client.baseURL = ticker.URL
+	client.SetPairMapper(mapper)
+
+	price, err := client.GetTicker(context.Background(), "ADA/USD")
+	require.NoError(t, err)
+	assert.Equal(t, "ADA/USD", price.Pair)
+	assert.Equal(t, 0.45, price.Amount)
+}
@@ -0,0 +1,91 @@
+package kraken
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestClient_ResolveReturnedPairs(t *testing.T) {
+	client := NewRestClient()
+	data := KrakenTickerData{LastTradeClosed: []string{"1.0", "1"}}
+
+	tests := []struct {
+		name        string
+		result      map[string]KrakenTickerData
+		krakenPairs []string
+		pairs       []string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:        "exact match on requested names",
+			result:      map[string]KrakenTickerData{"XXBTZUSD": data, "XETHZUSD": data},
+			krakenPairs: []string{"XXBTZUSD", "XETHZUSD"},
+			pairs:       []string{"BTC/USD", "ETH/USD"},
+			expected:    map[string]string{"XXBTZUSD": "BTC/USD", "XETHZUSD": "ETH/USD"},
+		},
+		{
+			name:        "overlapping codes are not confused",
+			result:      map[string]KrakenTickerData{"XXBTZEUR": data, "XXBTZUSD": data},
+			krakenPairs: []string{"XXBTZUSD", "XXBTZEUR"},
+			pairs:       []string{"BTC/USD", "BTC/EUR"},
+			expected:    map[string]string{"XXBTZUSD": "BTC/USD", "XXBTZEUR": "BTC/EUR"},
+		},
+		{
+			name:        "single request accepts alternative key",
+			result:      map[string]KrakenTickerData{"XBTCHF": data},
+			krakenPairs: []string{"XXBTCHF"},
+			pairs:       []string{"BTC/CHF"},
+			expected:    map[string]string{"XBTCHF": "BTC/CHF"},
+		},
+		{
+			name:        "unknown returned pair fails loudly",
+			result:      map[string]KrakenTickerData{"XXBTZUSD": data, "SOLUSD": data},
+			krakenPairs: []string{"XXBTZUSD", "XETHZUSD"},
+			pairs:       []string{"BTC/USD", "ETH/USD"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := client.resolveReturnedPairs(tt.result, tt.krakenPairs, tt.pairs)
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrNonRetryable)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolved)
+		})
+	}
+}
+
+func TestRestClient_GetTickers_UsesPairMapperReverseMap(t *testing.T) {
+	assetPairs := newAssetPairsServer(t)
+	defer assetPairs.Close()
+	mapper := NewPairMapper(assetPairs.URL, 0)
+	require.NoError(t, mapper.Load(context.Background()))
+
+	ticker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Kraken responde con la clave canónica aunque se haya pedido el altname
+		_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"c":["50000.0","1"]},"ADAUSD":{"c":["0.5","1"]}}}`))
+	}))
+	defer ticker.Close()
+
+	client := NewRestClient()
+	client.baseURL = ticker.URL
+	client.SetPairMapper(mapper)
+
+	prices, err := client.GetTickers(context.Background(), []string{"BTC/USD", "ADA/USD", "BTC/USD"})
+	require.NoError(t, err)
+	got := map[string]float64{}
+	for _, p := range prices {
+		got[p.Pair] = p.Amount
+	}
+	assert.Equal(t, map[string]float64{"BTC/USD": 50000.0, "ADA/USD": 0.5}, got)
+}
@@ -0,0 +1,20 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"time"
+)
+
+// isRepeatedTick indica si price repite el precio de previous (el cacheado) sin
+// que haya pasado window desde él. Con window 0 o sin precio previo nunca lo es.
+// Al vencer la ventana el tick repetido se vuelve a escribir, renovando el TTL de
+// la caché y la edad del precio antes de que el staleness watcher lo dé por vencido.
+func isRepeatedTick(price, previous *entities.Price, window time.Duration) bool {
+	if window <= 0 || previous == nil {
+		return false
+	}
+	if price.Amount != previous.Amount || price.Bid != previous.Bid || price.Ask != previous.Ask {
+		return false
+	}
+	return price.Timestamp.Sub(previous.Timestamp) < window
+}
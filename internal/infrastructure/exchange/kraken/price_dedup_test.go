@@ -0,0 +1,76 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRepeatedTick(t *testing.T) {
+	now := time.Now()
+	previous := entities.NewPriceWithExchangeTime("BTC/USD", 50000, time.Time{}, now)
+
+	tests := []struct {
+		name   string
+		price  *entities.Price
+		window time.Duration
+		want   bool
+	}{
+		{"same price within window", entities.NewPriceWithExchangeTime("BTC/USD", 50000, time.Time{}, now.Add(time.Second)), 5 * time.Second, true},
+		{"same price after window", entities.NewPriceWithExchangeTime("BTC/USD", 50000, time.Time{}, now.Add(5*time.Second)), 5 * time.Second, false},
+		{"price changed", entities.NewPriceWithExchangeTime("BTC/USD", 50000.1, time.Time{}, now.Add(time.Second)), 5 * time.Second, false},
+		{"disabled", entities.NewPriceWithExchangeTime("BTC/USD", 50000, time.Time{}, now.Add(time.Second)), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isRepeatedTick(tt.price, previous, tt.window))
+		})
+	}
+
+	quoted := *previous
+	quoted.Bid = 49999
+	assert.False(t, isRepeatedTick(&quoted, previous, 5*time.Second), "a bid/ask change is written")
+	assert.False(t, isRepeatedTick(previous, nil, 5*time.Second))
+}
+
+func TestWebSocketClient_SuppressesRepeatedTicks(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.dedupWindow = 5 * time.Second
+	priceChan := make(chan *entities.Price, 10)
+	client.priceChannels["BTC/USD"] = priceChan
+
+	var notified []float64
+	client.OnPrice(func(p *entities.Price) { notified = append(notified, p.Amount) })
+
+	tick := func(last string, receivedAt time.Time) {
+		update := []interface{}{float64(1), map[string]interface{}{"c": []interface{}{last, "1"}}, "ticker", "XBT/USD"}
+		require.NoError(t, client.handleTickerUpdateAt(update, receivedAt))
+	}
+	cachedAt := func() time.Time {
+		price, ok := client.cache.Get(context.Background(), "BTC/USD")
+		require.True(t, ok)
+		return price.ReceivedTime
+	}
+
+	start := time.Now()
+	tick("50000.0", start)
+	tick("50000.0", start.Add(time.Second)) // repetido: ni caché ni callbacks
+	assert.Equal(t, start, cachedAt())
+	assert.Equal(t, []float64{50000}, notified)
+
+	tick("50001.0", start.Add(2*time.Second))
+	assert.Equal(t, start.Add(2*time.Second), cachedAt())
+	assert.Equal(t, []float64{50000, 50001}, notified)
+
+	// Vencida la ventana el precio repetido se reescribe para renovar el TTL
+	tick("50001.0", start.Add(8*time.Second))
+	assert.Equal(t, start.Add(8*time.Second), cachedAt())
+	assert.Len(t, notified, 3)
+
+	// Quien espera un precio del par recibe también los ticks repetidos
+	assert.Len(t, priceChan, 4)
+}
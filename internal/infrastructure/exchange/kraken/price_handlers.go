@@ -0,0 +1,92 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"sync"
+)
+
+// PriceHandler recibe cada actualización de precio decodificada del WebSocket.
+// Se invoca en la goroutine de lectura: debe retornar rápido y no bloquear.
+type PriceHandler func(price *entities.Price)
+
+// TickHandler recibe cada tick de ticker recibido (dropped=false) y cada tick
+// descartado (dropped=true): rechazado por el validador o desplazado del canal por
+// la política de desborde. Se invoca en la goroutine de lectura y no debe bloquear.
+type TickHandler func(pair string, dropped bool)
+
+// priceHandlers es el registro de callbacks; el valor cero es utilizable
+type priceHandlers struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]PriceHandler
+}
+
+func (h *priceHandlers) add(handler PriceHandler) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[int]PriceHandler)
+	}
+	h.nextID++
+	h.handlers[h.nextID] = handler
+	return h.nextID
+}
+
+func (h *priceHandlers) remove(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.handlers, id)
+}
+
+func (h *priceHandlers) snapshot() []PriceHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]PriceHandler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		out = append(out, handler)
+	}
+	return out
+}
+
+// OnPrice registra un callback invocado con cada actualización de precio, complementando
+// la API basada en canales (GetTicker/GetTickers). Retorna una función para desregistrarlo.
+func (k *WebSocketClient) OnPrice(handler PriceHandler) (unregister func()) {
+	id := k.handlers.add(handler)
+	return func() { k.handlers.remove(id) }
+}
+
+// OnTick registra el callback de ticks recibidos y descartados, reemplazando al anterior
+func (k *WebSocketClient) OnTick(handler TickHandler) {
+	k.onTick.Store(&handler)
+}
+
+// notifyTick informa un tick de pair al callback de OnTick, si hay uno
+func (k *WebSocketClient) notifyTick(pair string, dropped bool) {
+	if handler := k.onTick.Load(); handler != nil {
+		(*handler)(pair, dropped)
+	}
+}
+
+// notifyHandlers invoca los callbacks registrados aislando panics de cada uno
+func (k *WebSocketClient) notifyHandlers(price *entities.Price) {
+	k.handlers.notify(k.logContext(), price)
+}
+
+// notify invoca los callbacks registrados aislando panics de cada uno
+func (h *priceHandlers) notify(ctx context.Context, price *entities.Price) {
+	for _, handler := range h.snapshot() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logging.Warn(ctx, "Price handler panicked", logging.Fields{
+						"pair":  price.Pair,
+						"panic": r,
+					})
+				}
+			}()
+			handler(price)
+		}()
+	}
+}
@@ -0,0 +1,46 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSocketClient_OnPrice_ReceivesTickerUpdates(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+
+	var received []*entities.Price
+	unregister := client.OnPrice(func(p *entities.Price) { received = append(received, p) })
+	client.OnPrice(func(*entities.Price) { panic("boom") }) // no debe afectar al resto
+
+	update := []interface{}{float64(1), map[string]interface{}{"c": []interface{}{"50000.5", "1"}}, "ticker", "XBT/USD"}
+	assert.NoError(t, client.handleTickerUpdate(update))
+
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "BTC/USD", received[0].Pair)
+		assert.Equal(t, 50000.5, received[0].Amount)
+	}
+
+	unregister()
+	assert.NoError(t, client.handleTickerUpdate(update))
+	assert.Len(t, received, 1)
+}
+
+func TestWebSocketClient_OnTick_ReportsAcceptedAndDroppedTicks(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.chanOpts = channelOptions{capacity: 1, policy: OverflowDropNewest}
+
+	ticks := map[bool][]string{}
+	client.OnTick(func(pair string, dropped bool) { ticks[dropped] = append(ticks[dropped], pair) })
+
+	update := []interface{}{float64(1), map[string]interface{}{"c": []interface{}{"50000.5", "1"}}, "ticker", "XBT/USD"}
+	assert.NoError(t, client.handleTickerUpdate(update))
+
+	ch := make(chan *entities.Price, 1)
+	client.deliver(ch, newTestPrice(1))
+	client.deliver(ch, newTestPrice(2))
+
+	assert.Equal(t, []string{"BTC/USD"}, ticks[false])
+	assert.Equal(t, []string{"BTC/USD"}, ticks[true], "the overflowed delivery counts as a dropped tick")
+}
@@ -0,0 +1,82 @@
+package kraken
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Motivos de pérdida de la conexión informados en ReconnectEvent
+const (
+	ReconnectReasonClosed        = "connection_closed" // Kraken o la red cerraron la conexión
+	ReconnectReasonReadTimeout   = "read_timeout"      // Sin frames ni pongs a tiempo (canal silencioso o keepalive)
+	ReconnectReasonOversizeFrame = "oversized_frame"   // Frame mayor a max_frame_bytes
+	ReconnectReasonReadError     = "read_error"        // Otro error de lectura
+	ReconnectReasonPingFailed    = "ping_failed"       // No se pudo enviar el ping de keepalive
+	ReconnectReasonStandby       = "standby_promotion" // Se promovió la conexión standby en lugar del shard caído
+	ReconnectReasonMaxAge        = "max_age"           // La conexión cumplió max_connection_age
+)
+
+// ReconnectEvent es el resultado de un intento de reconexión del WebSocket
+type ReconnectEvent struct {
+	Shard    string        // Conexión (vacío fuera de ShardedWebSocketClient)
+	Started  time.Time     // Cuándo se perdió la conexión
+	Duration time.Duration // Desde la pérdida de la conexión hasta el resultado del intento
+	Reason   string        // ReconnectReason*
+	Attempt  int
+	Pairs    []string // Pares suscriptos en la conexión
+	Err      error    // nil si la conexión se restableció
+}
+
+// ReconnectHandler recibe cada intento de reconexión; se invoca en la goroutine de
+// reconexión y no debe bloquear
+type ReconnectHandler func(event ReconnectEvent)
+
+// OnReconnect registra el callback de intentos de reconexión, reemplazando al anterior
+func (k *WebSocketClient) OnReconnect(handler ReconnectHandler) {
+	k.onReconnect.Store(&handler)
+}
+
+// notifyReconnect informa el resultado del intento de reconexión en curso al
+// callback de OnReconnect, si hay uno
+func (k *WebSocketClient) notifyReconnect(err error) {
+	handler := k.onReconnect.Load()
+	if handler == nil {
+		return
+	}
+	k.mu.RLock()
+	event := ReconnectEvent{
+		Shard:   k.shard,
+		Started: k.lostAt,
+		Reason:  k.lostReason,
+		Attempt: k.reconnectCount,
+		Pairs:   make([]string, 0, len(k.subscriptions)),
+		Err:     err,
+	}
+	for pair := range k.subscriptions {
+		event.Pairs = append(event.Pairs, pair)
+	}
+	k.mu.RUnlock()
+	sort.Strings(event.Pairs)
+	event.Duration = time.Since(event.Started)
+	(*handler)(event)
+}
+
+// disconnectReason clasifica el error de lectura que cortó la conexión
+func disconnectReason(err error) string {
+	var closeErr *websocket.CloseError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, websocket.ErrReadLimit):
+		return ReconnectReasonOversizeFrame
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ReconnectReasonReadTimeout
+	case errors.As(err, &closeErr), errors.Is(err, net.ErrClosed):
+		return ReconnectReasonClosed
+	default:
+		return ReconnectReasonReadError
+	}
+}
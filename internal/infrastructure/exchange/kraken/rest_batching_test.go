@@ -0,0 +1,114 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticMapper registra count pares ficticios C<i>/USD -> C<i>USD
+func syntheticMapper(t *testing.T, count int) (*PairMapper, []string) {
+	t.Helper()
+	mapper := NewPairMapper("http://unused", time.Second)
+	result := make(map[string]KrakenAssetPair, count)
+	pairs := make([]string, count)
+	for i := 0; i < count; i++ {
+		base := fmt.Sprintf("C%d", i)
+		result[base+"USD"] = KrakenAssetPair{AltName: base + "USD", WSName: base + "/USD"}
+		pairs[i] = base + "/USD"
+	}
+	require.NoError(t, mapper.load(result))
+	return mapper, pairs
+}
+
+func TestRestClient_GetTickers_SplitsLargeRequests(t *testing.T) {
+	mapper, pairs := syntheticMapper(t, 45)
+
+	var requests, inFlight, maxInFlight int32
+	var mu sync.Mutex
+	var batchSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		requested := strings.Split(r.URL.Query().Get("pair"), ",")
+		mu.Lock()
+		batchSizes = append(batchSizes, len(requested))
+		mu.Unlock()
+
+		entries := make([]string, len(requested))
+		for i, name := range requested {
+			entries[i] = fmt.Sprintf(`%q:{"c":["%d.5","1"]}`, name, i+1)
+		}
+		_, _ = fmt.Fprintf(w, `{"error":[],"result":{%s}}`, strings.Join(entries, ","))
+	}))
+	defer server.Close()
+
+	client := NewRestClient()
+	client.baseURL = server.URL
+	client.batchSize = 20
+	client.batchConcurrency = 2
+	client.SetPairMapper(mapper)
+
+	prices, err := client.GetTickers(context.Background(), pairs)
+	require.NoError(t, err)
+
+	assert.Len(t, prices, 45)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+	assert.ElementsMatch(t, []int{20, 20, 5}, batchSizes)
+
+	got := make(map[string]bool, len(prices))
+	for _, p := range prices {
+		got[p.Pair] = true
+	}
+	for _, pair := range pairs {
+		assert.True(t, got[pair], "missing %s", pair)
+	}
+}
+
+func TestRestClient_GetTickers_BatchFailureFailsRequest(t *testing.T) {
+	mapper, pairs := syntheticMapper(t, 5)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("pair"), "C4USD") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requested := strings.Split(r.URL.Query().Get("pair"), ",")
+		entries := make([]string, len(requested))
+		for i, name := range requested {
+			entries[i] = fmt.Sprintf(`%q:{"c":["1.0","1"]}`, name)
+		}
+		_, _ = fmt.Fprintf(w, `{"error":[],"result":{%s}}`, strings.Join(entries, ","))
+	}))
+	defer server.Close()
+
+	client := NewRestClient()
+	client.baseURL = server.URL
+	client.batchSize = 2
+	client.SetPairMapper(mapper)
+
+	prices, err := client.GetTickers(context.Background(), pairs)
+	assert.Nil(t, prices)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ticker batch 3/3")
+	assert.ErrorIs(t, err, ErrNonRetryable)
+}
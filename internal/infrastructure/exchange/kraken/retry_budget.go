@@ -0,0 +1,99 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"sync"
+)
+
+const (
+	DefaultRetryBudgetTokens = 10  // Tokens máximos del presupuesto de reintentos
+	DefaultRetryBudgetRatio  = 0.1 // Tokens que devuelve cada request exitosa
+)
+
+// RetryBudget limita los reintentos que comparten todas las requests a una fuente,
+// al estilo del retry throttling de gRPC: cada intento fallido consume un token y
+// cada request exitosa devuelve ratio tokens, hasta maxTokens. Sólo se reintenta
+// mientras quede más de la mitad del máximo, así bajo una falla sostenida cada
+// request hace un único intento en lugar de multiplicar la carga sobre Kraken, y
+// los reintentos vuelven a medida que las requests se recuperan. Es seguro para uso
+// concurrente y un *RetryBudget nil permite todos los reintentos.
+type RetryBudget struct {
+	source    string // Etiqueta de la fuente en métricas (websocket, rest)
+	maxTokens float64
+	ratio     float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewRetryBudget crea el presupuesto de reintentos de source, lleno; con el
+// presupuesto deshabilitado retorna nil
+func NewRetryBudget(source string, cfg config.RetryBudgetConfig) *RetryBudget {
+	if !cfg.Enabled {
+		return nil
+	}
+	maxTokens := cfg.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultRetryBudgetTokens
+	}
+	ratio := cfg.TokenRatio
+	if ratio <= 0 {
+		ratio = DefaultRetryBudgetRatio
+	}
+	metrics.UpdateRetryBudgetTokens(source, maxTokens)
+	return &RetryBudget{source: source, maxTokens: maxTokens, ratio: ratio, tokens: maxTokens}
+}
+
+// OnFailure consume un token por un intento fallido
+func (b *RetryBudget) OnFailure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.tokens = max(0, b.tokens-1)
+	tokens := b.tokens
+	b.mu.Unlock()
+	metrics.UpdateRetryBudgetTokens(b.source, tokens)
+}
+
+// OnSuccess devuelve ratio tokens por una request exitosa
+func (b *RetryBudget) OnSuccess() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.tokens == b.maxTokens {
+		b.mu.Unlock()
+		return
+	}
+	b.tokens = min(b.maxTokens, b.tokens+b.ratio)
+	tokens := b.tokens
+	b.mu.Unlock()
+	metrics.UpdateRetryBudgetTokens(b.source, tokens)
+}
+
+// AllowRetry indica si queda presupuesto para reintentar; un reintento denegado
+// se cuenta en btc_ltp_retries_suppressed_total
+func (b *RetryBudget) AllowRetry() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	allowed := b.tokens > b.maxTokens/2
+	b.mu.Unlock()
+	if !allowed {
+		metrics.RecordRetrySuppressed(b.source)
+	}
+	return allowed
+}
+
+// Tokens retorna los tokens disponibles
+func (b *RetryBudget) Tokens() float64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
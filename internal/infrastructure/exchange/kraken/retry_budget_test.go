@@ -0,0 +1,78 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget_SpendsOnFailureAndRefillsOnSuccess(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	budget := NewRetryBudget("rest", config.RetryBudgetConfig{Enabled: true, MaxTokens: 4, TokenRatio: 0.5})
+	assert.True(t, budget.AllowRetry())
+
+	budget.OnFailure()
+	budget.OnFailure()
+	assert.False(t, budget.AllowRetry(), "retries need more than half of the tokens")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Fallback.RetriesSuppressed.WithLabelValues("rest")))
+
+	budget.OnSuccess()
+	assert.True(t, budget.AllowRetry())
+	for i := 0; i < 10; i++ {
+		budget.OnSuccess()
+	}
+	assert.Equal(t, 4.0, budget.Tokens(), "capped at max_tokens")
+	assert.Equal(t, 4.0, testutil.ToFloat64(m.Fallback.RetryBudgetTokens.WithLabelValues("rest")))
+
+	for i := 0; i < 10; i++ {
+		budget.OnFailure()
+	}
+	assert.Zero(t, budget.Tokens())
+}
+
+func TestRetryBudget_DisabledAllowsEveryRetry(t *testing.T) {
+	budget := NewRetryBudget("rest", config.RetryBudgetConfig{MaxTokens: 4})
+	assert.Nil(t, budget)
+	budget.OnFailure()
+	assert.True(t, budget.AllowRetry())
+
+	defaults := NewRetryBudget("rest", config.RetryBudgetConfig{Enabled: true})
+	assert.Equal(t, float64(DefaultRetryBudgetTokens), defaults.Tokens())
+}
+
+func TestRestClient_RetryBudgetSharedAcrossRequests(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewRestClientWithConfig(config.KrakenConfig{
+		RestURL:        server.URL,
+		Timeout:        5 * time.Second,
+		RequestTimeout: time.Second,
+		RetryBudget:    config.RetryBudgetConfig{Enabled: true, MaxTokens: 4},
+	})
+
+	_, err := client.GetTicker(context.Background(), "BTC/USD")
+	require.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "the budget stops retrying after the second failure")
+
+	_, err = client.GetTickers(context.Background(), []string{"BTC/USD", "ETH/USD"})
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load(), "GetTickers shares the exhausted budget")
+}
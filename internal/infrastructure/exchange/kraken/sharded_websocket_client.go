@@ -0,0 +1,515 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	cachepkg "btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxPairsPerConnection es el máximo de pares suscritos por conexión
+// cuando exchange.kraken.max_pairs_per_connection es 0
+const DefaultMaxPairsPerConnection = 100
+
+// shardConnectTimeout acota el dial de un shard nuevo cuando la configuración no
+// define exchange.kraken.timeout
+const shardConnectTimeout = 10 * time.Second
+
+// ShardStatus describe el estado de una de las conexiones de un ShardedWebSocketClient
+type ShardStatus struct {
+	Index             int
+	Pairs             []string // Pares asignados a la conexión, ordenados
+	Connected         bool
+	Reconnecting      bool
+	ReconnectAttempts int
+	Confirmed         int // Suscripciones confirmadas por Kraken
+	Pending           int // Suscripciones enviadas y aún sin confirmar
+}
+
+// ShardedWebSocketClient reparte las suscripciones de ticker entre varias
+// conexiones WebSocket de a lo sumo maxPairs pares cada una. Cada shard es un
+// WebSocketClient con su propio ciclo de vida (ping, reconexión, reintentos de
+// suscripción); todos escriben en la misma caché de precios y notifican a los
+// mismos callbacks de OnPrice. Los shards se abren a medida que hacen falta y un
+// par queda asignado a su shard mientras el cliente viva. Con warm standby se
+// mantiene además una conexión sin suscripciones que reemplaza al primer shard
+// que se caiga (ver warm_standby.go).
+type ShardedWebSocketClient struct {
+	cfg       config.KrakenConfig
+	maxPairs  int
+	cache     *cachepkg.PriceCacheAdapter
+	handlers  priceHandlers
+	onTick    atomic.Pointer[TickHandler]
+	reconnect atomic.Pointer[ReconnectHandler]
+	transport *transport   // Compartido por los shards para reutilizar las IPs con pin_dns
+	endpoints *endpointSet // Compartido por los shards: todos eligen según las mismas mediciones
+
+	mu         sync.RWMutex
+	shards     []*WebSocketClient
+	assignment map[string]int // par -> índice del shard
+	counts     []int          // pares asignados por shard
+	validator  interfaces.PriceValidator
+	mapper     *PairMapper
+	logCtx     context.Context // capturado en ConnectContext; nil hasta la primera conexión
+
+	standby      *WebSocketClient // Conexión de reserva sin suscripciones (nil sin warm standby)
+	standbyRetry *time.Timer      // Reintento de apertura del standby
+	closed       bool             // Shutdown en curso: no se abren más conexiones standby
+}
+
+// shardBatch son los pares de una operación que corresponden a un shard
+type shardBatch struct {
+	shard *WebSocketClient
+	pairs []string
+}
+
+// NewShardedWebSocketClient crea el cliente con un único shard, sin conectar
+func NewShardedWebSocketClient(cfg config.KrakenConfig) *ShardedWebSocketClient {
+	maxPairs := cfg.MaxPairsPerConnection
+	if maxPairs <= 0 {
+		maxPairs = DefaultMaxPairsPerConnection
+	}
+	s := &ShardedWebSocketClient{
+		cfg:        cfg,
+		maxPairs:   maxPairs,
+		cache:      newClientPriceCache(cfg),
+		transport:  newTransport(cfg),
+		endpoints:  newWebSocketEndpoints(cfg),
+		assignment: make(map[string]int),
+	}
+	s.mu.Lock()
+	s.addShard()
+	s.mu.Unlock()
+	return s
+}
+
+// addShard crea un shard con la configuración vigente (requiere lock)
+func (s *ShardedWebSocketClient) addShard() {
+	shard := s.newConnection(strconv.Itoa(len(s.shards)))
+	if s.cfg.WarmStandby {
+		shard.onDisconnect = func() { s.failover(shard) }
+	}
+
+	s.shards = append(s.shards, shard)
+	s.counts = append(s.counts, 0)
+	metrics.UpdateWebSocketShards(len(s.shards))
+	metrics.UpdateWebSocketShardStatus(shard.shard, false)
+	metrics.UpdateWebSocketShardPairs(shard.shard, 0)
+}
+
+// newConnection crea una conexión sobre la caché compartida con los callbacks,
+// el validador y el mapper vigentes (requiere lock)
+func (s *ShardedWebSocketClient) newConnection(label string) *WebSocketClient {
+	client := newWebSocketClient(s.cfg, s.cache)
+	client.shard = label
+	client.transport = s.transport
+	client.endpoints = s.endpoints
+	client.OnPrice(func(price *entities.Price) {
+		s.handlers.notify(client.logContext(), price)
+	})
+	client.OnTick(func(pair string, dropped bool) {
+		if handler := s.onTick.Load(); handler != nil {
+			(*handler)(pair, dropped)
+		}
+	})
+	client.OnReconnect(s.notifyReconnect)
+	client.SetPriceValidator(s.validator)
+	client.SetPairMapper(s.mapper)
+	return client
+}
+
+// assign agrupa pairs por shard, asignando los pares nuevos al primer shard con
+// lugar y abriendo un shard más cuando todos están llenos. Los lotes respetan el
+// orden de los shards.
+func (s *ShardedWebSocketClient) assign(pairs []string) []shardBatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byShard := make(map[int][]string)
+	for _, pair := range uniquePairs(pairs) {
+		index, ok := s.assignment[pair]
+		if !ok {
+			index = s.freeShard()
+			s.assignment[pair] = index
+			s.counts[index]++
+			metrics.UpdateWebSocketShardPairs(s.shards[index].shard, s.counts[index])
+		}
+		byShard[index] = append(byShard[index], pair)
+	}
+	return s.batches(byShard)
+}
+
+// batches convierte los pares agrupados por índice de shard en lotes ordenados por shard (requiere lock)
+func (s *ShardedWebSocketClient) batches(byShard map[int][]string) []shardBatch {
+	batches := make([]shardBatch, 0, len(byShard))
+	for index, shard := range s.shards {
+		if shardPairs, ok := byShard[index]; ok {
+			batches = append(batches, shardBatch{shard: shard, pairs: shardPairs})
+		}
+	}
+	return batches
+}
+
+// freeShard retorna el primer shard con lugar, creando uno si todos están llenos (requiere lock)
+func (s *ShardedWebSocketClient) freeShard() int {
+	for index, count := range s.counts {
+		if count < s.maxPairs {
+			return index
+		}
+	}
+	s.addShard()
+	return len(s.shards) - 1
+}
+
+// snapshotShards retorna una copia de los shards actuales
+func (s *ShardedWebSocketClient) snapshotShards() []*WebSocketClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*WebSocketClient(nil), s.shards...)
+}
+
+// pairsOf retorna los pares asignados al shard index, ordenados
+func (s *ShardedWebSocketClient) pairsOf(index int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var pairs []string
+	for pair, assigned := range s.assignment {
+		if assigned == index {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Connect establece las conexiones de todos los shards
+func (s *ShardedWebSocketClient) Connect() error {
+	return s.ConnectContext(context.Background())
+}
+
+// ConnectContext conecta los shards que no estén conectados respetando el deadline
+// de ctx, y vuelve a suscribir los pares ya asignados a cada uno. Los valores de
+// ctx se conservan para el logging de los shards que se abran después.
+func (s *ShardedWebSocketClient) ConnectContext(ctx context.Context) error {
+	s.mu.Lock()
+	s.logCtx = context.WithoutCancel(ctx)
+	s.closed = false
+	s.mu.Unlock()
+	defer func() { go s.openStandby() }()
+
+	var errs []error
+	for index, shard := range s.snapshotShards() {
+		if shard.IsConnected() {
+			continue
+		}
+		if err := shard.ConnectContext(ctx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if pairs := s.pairsOf(index); len(pairs) > 0 {
+			if err := shard.SubscribeTicker(pairs); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// connectShard conecta un shard abierto después de ConnectContext (o cuyo dial
+// falló) con el contexto de logging capturado; ErrConnectionFailed si el cliente
+// todavía no se conectó. Los shards que están reconectando se dejan a su propio ciclo.
+func (s *ShardedWebSocketClient) connectShard(shard *WebSocketClient) error {
+	if reconnecting, _ := shard.GetReconnectionStatus(); shard.IsConnected() || reconnecting {
+		return nil
+	}
+
+	s.mu.RLock()
+	logCtx := s.logCtx
+	s.mu.RUnlock()
+	if logCtx == nil {
+		return ErrConnectionFailed
+	}
+
+	ctx, cancel := context.WithTimeout(logCtx, s.connectTimeout())
+	defer cancel()
+
+	if err := shard.ConnectContext(ctx); err != nil {
+		return err
+	}
+	logging.Info(ctx, "WebSocket shard connected", logging.Fields{
+		"shard":                    shard.shard,
+		"max_pairs_per_connection": s.maxPairs,
+		"websocket_url":            s.cfg.WebSocketURL,
+	})
+	return nil
+}
+
+// connectTimeout acota el dial de las conexiones abiertas después de ConnectContext
+func (s *ShardedWebSocketClient) connectTimeout() time.Duration {
+	if s.cfg.Timeout > 0 {
+		return s.cfg.Timeout
+	}
+	return shardConnectTimeout
+}
+
+// SubscribeTicker suscribe los pares en el shard asignado a cada uno, abriendo
+// nuevas conexiones cuando los shards existentes están llenos
+func (s *ShardedWebSocketClient) SubscribeTicker(pairs []string) error {
+	var errs []error
+	for _, batch := range s.assign(pairs) {
+		if err := s.connectShard(batch.shard); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := batch.shard.SubscribeTicker(batch.pairs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnsubscribeTicker cancela la suscripción de los pares y libera su lugar en el
+// shard, que se reutiliza para los próximos pares. Los shards que quedan sin
+// pares siguen conectados.
+func (s *ShardedWebSocketClient) UnsubscribeTicker(pairs []string) error {
+	s.mu.Lock()
+	byShard := make(map[int][]string)
+	for _, pair := range uniquePairs(pairs) {
+		index, ok := s.assignment[pair]
+		if !ok {
+			continue
+		}
+		delete(s.assignment, pair)
+		s.counts[index]--
+		metrics.UpdateWebSocketShardPairs(s.shards[index].shard, s.counts[index])
+		byShard[index] = append(byShard[index], pair)
+	}
+	batches := s.batches(byShard)
+	s.mu.Unlock()
+
+	var errs []error
+	for _, batch := range batches {
+		if err := batch.shard.UnsubscribeTicker(batch.pairs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetTicker obtiene el último precio del par desde la caché compartida o el shard asignado
+func (s *ShardedWebSocketClient) GetTicker(ctx context.Context, pair string) (*entities.Price, error) {
+	if price, ok := s.cache.Get(ctx, pair); ok {
+		return price, nil
+	}
+	batches := s.assign([]string{pair})
+	return batches[0].shard.GetTicker(ctx, pair)
+}
+
+// GetTickers obtiene precios múltiples en el orden de pairs. Si algún par no
+// llega antes de ctx, retorna los obtenidos junto con el error.
+func (s *ShardedWebSocketClient) GetTickers(ctx context.Context, pairs []string) ([]*entities.Price, error) {
+	results, err := s.GetTickersByPair(ctx, pairs)
+	if err != nil {
+		return nil, err
+	}
+	return tickersInOrder(pairs, results)
+}
+
+// GetTickersByPair consulta cada shard en paralelo con sus pares. El fallo global
+// de un shard (conexión/suscripción) se informa como error de sus pares; sólo se
+// retorna error si fallaron todos los shards consultados.
+func (s *ShardedWebSocketClient) GetTickersByPair(ctx context.Context, pairs []string) (map[string]TickerResult, error) {
+	batches := s.assign(pairs)
+
+	type batchResult struct {
+		batch   shardBatch
+		results map[string]TickerResult
+		err     error
+	}
+	resultCh := make(chan batchResult, len(batches))
+	for _, batch := range batches {
+		go func(batch shardBatch) {
+			results, err := batch.shard.GetTickersByPair(ctx, batch.pairs)
+			resultCh <- batchResult{batch: batch, results: results, err: err}
+		}(batch)
+	}
+
+	results := make(map[string]TickerResult, len(pairs))
+	var firstErr error
+	failed := 0
+	for range batches {
+		result := <-resultCh
+		if result.err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			for _, pair := range result.batch.pairs {
+				results[pair] = TickerResult{Pair: pair, Err: result.err}
+			}
+			continue
+		}
+		for pair, tickerResult := range result.results {
+			results[pair] = tickerResult
+		}
+	}
+
+	if len(batches) > 0 && failed == len(batches) {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// Close cierra todas las conexiones
+func (s *ShardedWebSocketClient) Close() error {
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown cierra todos los shards y el standby en paralelo respetando el deadline de ctx
+func (s *ShardedWebSocketClient) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	if s.standbyRetry != nil {
+		s.standbyRetry.Stop()
+		s.standbyRetry = nil
+	}
+	shards := append([]*WebSocketClient(nil), s.shards...)
+	if s.standby != nil {
+		shards = append(shards, s.standby)
+		s.standby = nil
+		metrics.UpdateWebSocketStandbyStatus(false)
+	}
+	s.mu.Unlock()
+
+	errCh := make(chan error, len(shards))
+	for _, shard := range shards {
+		go func(shard *WebSocketClient) {
+			errCh <- shard.Shutdown(ctx)
+		}(shard)
+	}
+
+	var errs []error
+	for range shards {
+		if err := <-errCh; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// IsConnected retorna true si todos los shards están conectados
+func (s *ShardedWebSocketClient) IsConnected() bool {
+	for _, shard := range s.snapshotShards() {
+		if !shard.IsConnected() {
+			return false
+		}
+	}
+	return true
+}
+
+// Shards retorna el estado de cada conexión, en orden de creación
+func (s *ShardedWebSocketClient) Shards() []ShardStatus {
+	shards := s.snapshotShards()
+	statuses := make([]ShardStatus, len(shards))
+	for index, shard := range shards {
+		reconnecting, attempts := shard.GetReconnectionStatus()
+		confirmed, pending := shard.GetSubscriptionStatus()
+		statuses[index] = ShardStatus{
+			Index:             index,
+			Pairs:             s.pairsOf(index),
+			Connected:         shard.IsConnected(),
+			Reconnecting:      reconnecting,
+			ReconnectAttempts: attempts,
+			Confirmed:         len(confirmed),
+			Pending:           len(pending),
+		}
+	}
+	return statuses
+}
+
+// GetSubscriptionStatus retorna los pares confirmados y pendientes de todos los shards
+func (s *ShardedWebSocketClient) GetSubscriptionStatus() (confirmed, pending []string) {
+	for _, shard := range s.snapshotShards() {
+		shardConfirmed, shardPending := shard.GetSubscriptionStatus()
+		confirmed = append(confirmed, shardConfirmed...)
+		pending = append(pending, shardPending...)
+	}
+	sort.Strings(confirmed)
+	sort.Strings(pending)
+	return confirmed, pending
+}
+
+// SubscriptionFailures retorna los errores de suscripción de todos los shards, ordenados por par
+func (s *ShardedWebSocketClient) SubscriptionFailures() []SubscriptionFailure {
+	var failures []SubscriptionFailure
+	for _, shard := range s.snapshotShards() {
+		failures = append(failures, shard.SubscriptionFailures()...)
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Pair < failures[j].Pair })
+	return failures
+}
+
+// SetPriceValidator configura la validación de ticks en todos los shards, incluidos los futuros
+func (s *ShardedWebSocketClient) SetPriceValidator(validator interfaces.PriceValidator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.validator = validator
+	for _, shard := range s.shards {
+		shard.SetPriceValidator(validator)
+	}
+	if s.standby != nil {
+		s.standby.SetPriceValidator(validator)
+	}
+}
+
+// SetPairMapper habilita el mapeo dinámico de pares en todos los shards; nil vuelve a los mapas estáticos
+func (s *ShardedWebSocketClient) SetPairMapper(mapper *PairMapper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mapper = mapper
+	for _, shard := range s.shards {
+		shard.SetPairMapper(mapper)
+	}
+	if s.standby != nil {
+		s.standby.SetPairMapper(mapper)
+	}
+}
+
+// OnPrice registra un callback invocado con cada tick recibido por cualquiera de los shards
+func (s *ShardedWebSocketClient) OnPrice(handler PriceHandler) (unregister func()) {
+	id := s.handlers.add(handler)
+	return func() { s.handlers.remove(id) }
+}
+
+// OnTick registra el callback de ticks recibidos y descartados por cualquiera de
+// los shards, reemplazando al anterior
+func (s *ShardedWebSocketClient) OnTick(handler TickHandler) {
+	s.onTick.Store(&handler)
+}
+
+// OnReconnect registra el callback de intentos de reconexión de cualquiera de los
+// shards y de las promociones de la conexión standby, reemplazando al anterior
+func (s *ShardedWebSocketClient) OnReconnect(handler ReconnectHandler) {
+	s.reconnect.Store(&handler)
+}
+
+// notifyReconnect informa event al callback de OnReconnect, si hay uno
+func (s *ShardedWebSocketClient) notifyReconnect(event ReconnectEvent) {
+	if handler := s.reconnect.Load(); handler != nil {
+		(*handler)(event)
+	}
+}
+
+// GetPriceCache expone la caché de precios compartida por los shards
+func (s *ShardedWebSocketClient) GetPriceCache() *cachepkg.PriceCacheAdapter {
+	return s.cache
+}
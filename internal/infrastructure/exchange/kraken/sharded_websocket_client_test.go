@@ -0,0 +1,218 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedWebSocketClient_AssignFillsShardsInOrder(t *testing.T) {
+	client := NewShardedWebSocketClient(config.KrakenConfig{MaxPairsPerConnection: 2})
+	defer client.Close()
+
+	batches := client.assign([]string{"BTC/USD", "ETH/USD", "LTC/USD", "BTC/USD", "XRP/USD", "BTC/EUR"})
+	require.Len(t, batches, 3)
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, batches[0].pairs)
+	assert.Equal(t, []string{"LTC/USD", "XRP/USD"}, batches[1].pairs)
+	assert.Equal(t, []string{"BTC/EUR"}, batches[2].pairs)
+
+	// Un par ya asignado vuelve siempre a su shard
+	batches = client.assign([]string{"XRP/USD"})
+	require.Len(t, batches, 1)
+	assert.Same(t, client.shards[1], batches[0].shard)
+
+	shards := client.Shards()
+	require.Len(t, shards, 3)
+	assert.Equal(t, []string{"BTC/EUR"}, shards[2].Pairs)
+	assert.False(t, client.IsConnected())
+}
+
+func TestShardedWebSocketClient_DefaultMaxPairs(t *testing.T) {
+	client := NewShardedWebSocketClient(config.KrakenConfig{})
+	defer client.Close()
+	assert.Equal(t, DefaultMaxPairsPerConnection, client.maxPairs)
+}
+
+func TestShardedWebSocketClient_SubscribeOpensShards(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := NewShardedWebSocketClient(config.KrakenConfig{WebSocketURL: mockServer.getURL(), MaxPairsPerConnection: 2})
+	defer client.Close()
+
+	received := make(chan *entities.Price, 10)
+	client.OnPrice(func(price *entities.Price) { received <- price })
+
+	require.NoError(t, client.ConnectContext(context.Background()))
+	require.NoError(t, client.SubscribeTicker([]string{"BTC/USD", "ETH/USD", "LTC/USD"}))
+	assert.True(t, client.IsConnected())
+
+	// Cada shard envía su propio subscribe con sus pares
+	subscribed := make(map[string]bool)
+	for len(subscribed) < 3 {
+		select {
+		case raw := <-mockServer.messages:
+			var msg WebSocketMessage
+			require.NoError(t, json.Unmarshal(raw, &msg))
+			require.LessOrEqual(t, len(msg.Pair), 2)
+			for _, pair := range msg.Pair {
+				subscribed[pair] = true
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("subscriptions not received, got %v", subscribed)
+		}
+	}
+	assert.Equal(t, map[string]bool{"XBT/USD": true, "ETH/USD": true, "LTC/USD": true}, subscribed)
+
+	shards := client.Shards()
+	require.Len(t, shards, 2)
+	for _, shard := range shards {
+		assert.True(t, shard.Connected)
+	}
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, shards[0].Pairs)
+	assert.Equal(t, []string{"LTC/USD"}, shards[1].Pairs)
+
+	// Los ticks de cualquier shard llegan a los mismos callbacks y a la caché compartida
+	mockServer.sendTickerUpdate("LTC/USD", "70.5")
+	select {
+	case price := <-received:
+		assert.Equal(t, "LTC/USD", price.Pair)
+	case <-time.After(2 * time.Second):
+		t.Fatal("price not received")
+	}
+	require.Eventually(t, func() bool {
+		price, ok := client.GetPriceCache().Get(context.Background(), "LTC/USD")
+		return ok && price.Amount == 70.5
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Close())
+	assert.False(t, client.IsConnected())
+}
+
+func TestShardedWebSocketClient_GetTickersByPair_AllShardsFail(t *testing.T) {
+	client := NewShardedWebSocketClient(config.KrakenConfig{WebSocketURL: "ws://127.0.0.1:1", MaxPairsPerConnection: 1})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.GetTickers(ctx, []string{"BTC/USD", "ETH/USD"})
+	assert.ErrorIs(t, err, ErrConnectionFailed)
+	assert.Len(t, client.Shards(), 2)
+}
+
+func TestShardedWebSocketClient_UnsubscribeTicker(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := NewShardedWebSocketClient(config.KrakenConfig{WebSocketURL: mockServer.getURL(), MaxPairsPerConnection: 2})
+	defer client.Close()
+
+	require.NoError(t, client.ConnectContext(context.Background()))
+	require.NoError(t, client.SubscribeTicker([]string{"BTC/USD", "ETH/USD", "LTC/USD"}))
+	require.NoError(t, client.UnsubscribeTicker([]string{"LTC/USD", "XRP/USD"}))
+
+	var unsubscribed PairList
+	for unsubscribed == nil {
+		select {
+		case raw := <-mockServer.messages:
+			var msg WebSocketMessage
+			require.NoError(t, json.Unmarshal(raw, &msg))
+			if msg.Event == "unsubscribe" {
+				unsubscribed = msg.Pair
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("unsubscribe not received")
+		}
+	}
+	assert.Equal(t, PairList{"LTC/USD"}, unsubscribed, "only subscribed pairs are unsubscribed")
+
+	// El shard queda sin pares pero conectado, y el par vuelve a él al resuscribirse
+	shards := client.Shards()
+	require.Len(t, shards, 2)
+	assert.Empty(t, shards[1].Pairs)
+	assert.True(t, shards[1].Connected)
+
+	require.NoError(t, client.SubscribeTicker([]string{"LTC/USD"}))
+	assert.Equal(t, []string{"LTC/USD"}, client.Shards()[1].Pairs)
+}
+
+func TestShardedWebSocketClient_WarmStandbyTakesOverFailedShard(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := NewShardedWebSocketClient(config.KrakenConfig{WebSocketURL: mockServer.getURL(), WarmStandby: true})
+	defer client.Close()
+
+	require.NoError(t, client.ConnectContext(context.Background()))
+	require.NoError(t, client.SubscribeTicker([]string{"BTC/USD"}))
+	<-mockServer.messages // subscribe del shard
+
+	standbyReady := func() *WebSocketClient {
+		client.mu.RLock()
+		defer client.mu.RUnlock()
+		if client.standby != nil && client.standby.IsConnected() {
+			return client.standby
+		}
+		return nil
+	}
+	require.Eventually(t, func() bool { return standbyReady() != nil }, 2*time.Second, 10*time.Millisecond)
+	standby := standbyReady()
+	failed := client.shards[0]
+
+	// El servidor corta la conexión del shard (la primera que se abrió)
+	mockServer.mu.Lock()
+	shardConn := mockServer.clients[0]
+	mockServer.mu.Unlock()
+	require.NoError(t, shardConn.Close())
+
+	require.Eventually(t, func() bool {
+		client.mu.RLock()
+		defer client.mu.RUnlock()
+		return client.shards[0] == standby
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// El standby promovido suscribe enseguida los pares del shard caído
+	select {
+	case raw := <-mockServer.messages:
+		var msg WebSocketMessage
+		require.NoError(t, json.Unmarshal(raw, &msg))
+		assert.Equal(t, "subscribe", msg.Event)
+		assert.Equal(t, PairList{"XBT/USD"}, msg.Pair)
+	case <-time.After(2 * time.Second):
+		t.Fatal("promoted standby did not subscribe")
+	}
+	assert.Equal(t, "0", standby.shard)
+	assert.Equal(t, []string{"BTC/USD"}, client.Shards()[0].Pairs)
+	assert.True(t, client.Shards()[0].Connected)
+	require.Eventually(t, func() bool {
+		reconnecting, _ := failed.GetReconnectionStatus()
+		return !reconnecting && !failed.IsConnected()
+	}, 2*time.Second, 10*time.Millisecond, "the failed shard stops reconnecting")
+
+	// Se abre un standby nuevo para la próxima falla
+	require.Eventually(t, func() bool {
+		next := standbyReady()
+		return next != nil && next != standby
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestShardedWebSocketClient_NoStandbyByDefault(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := NewShardedWebSocketClient(config.KrakenConfig{WebSocketURL: mockServer.getURL()})
+	defer client.Close()
+	require.NoError(t, client.ConnectContext(context.Background()))
+	time.Sleep(50 * time.Millisecond)
+
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	assert.Nil(t, client.standby)
+}
@@ -0,0 +1,239 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// SubscriptionConfirmTimeout es el tiempo máximo de espera de un subscriptionStatus
+	SubscriptionConfirmTimeout = 10 * time.Second
+	// MaxSubscriptionAttempts limita los reintentos de suscripción por par
+	MaxSubscriptionAttempts = 5
+
+	// SubscriptionRetryBackoff es la espera tras el primer error de suscripción de
+	// un par; se duplica con cada error hasta MaxSubscriptionRetryBackoff
+	SubscriptionRetryBackoff    = 5 * time.Second
+	MaxSubscriptionRetryBackoff = 2 * time.Minute
+	// MaxSubscriptionFailures son los errores consecutivos tras los que se excluye el par
+	MaxSubscriptionFailures = 5
+)
+
+// pendingSubscription registra una suscripción enviada y aún no confirmada
+type pendingSubscription struct {
+	sentAt   time.Time
+	attempts int
+}
+
+// SubscriptionFailure son los errores de suscripción de un par informados por
+// Kraken desde su última confirmación
+type SubscriptionFailure struct {
+	Pair      string
+	Failures  int
+	LastError string
+	LastAt    time.Time
+	RetryAt   time.Time // Próximo reintento; cero si el par está excluido
+	Excluded  bool      // Superó MaxSubscriptionFailures y ya no se reintenta
+}
+
+// subscriptionTracker mantiene el estado confirmado/pendiente de cada par y los
+// errores de suscripción. El valor cero es utilizable.
+type subscriptionTracker struct {
+	mu        sync.Mutex
+	pending   map[string]*pendingSubscription
+	confirmed map[string]bool
+	failed    map[string]*SubscriptionFailure // sobreviven a reset: una reconexión no rehabilita un par excluido
+	published int                             // pendientes ya sumados a unconfirmedSubscriptions
+}
+
+// unconfirmedSubscriptions suma los pendientes de todos los trackers del
+// proceso, de modo que el gauge cubra todas las conexiones de un cliente con shards
+var unconfirmedSubscriptions atomic.Int64
+
+// markPending registra los pares como enviados y pendientes de confirmación
+func (t *subscriptionTracker) markPending(pairs []string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+
+	for _, pair := range pairs {
+		delete(t.confirmed, pair)
+		if p, ok := t.pending[pair]; ok {
+			p.sentAt = now
+			p.attempts++
+			continue
+		}
+		t.pending[pair] = &pendingSubscription{sentAt: now, attempts: 1}
+	}
+	t.publish()
+}
+
+// confirm marca el par como confirmado por Kraken
+func (t *subscriptionTracker) confirm(pair string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+
+	delete(t.pending, pair)
+	delete(t.failed, pair)
+	t.confirmed[pair] = true
+	t.publish()
+}
+
+// fail registra un error de suscripción del par: lo reintenta con backoff o, al
+// llegar a MaxSubscriptionFailures, lo excluye. Retorna el estado resultante.
+func (t *subscriptionTracker) fail(pair, message string, now time.Time) SubscriptionFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+
+	delete(t.pending, pair)
+	failure, ok := t.failed[pair]
+	if !ok {
+		failure = &SubscriptionFailure{Pair: pair}
+		t.failed[pair] = failure
+	}
+	failure.Failures++
+	failure.LastError = message
+	failure.LastAt = now
+	if failure.Failures >= MaxSubscriptionFailures {
+		failure.Excluded = true
+		failure.RetryAt = time.Time{}
+	} else {
+		failure.RetryAt = now.Add(subscriptionRetryDelay(failure.Failures))
+	}
+	t.publish()
+	return *failure
+}
+
+// subscriptionRetryDelay es la espera antes de reintentar tras failures errores
+func subscriptionRetryDelay(failures int) time.Duration {
+	delay := SubscriptionRetryBackoff
+	for i := 1; i < failures && delay < MaxSubscriptionRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxSubscriptionRetryBackoff)
+}
+
+// retryable retorna los pares con error cuyo backoff venció, sin excluidos ni
+// pares con un reintento ya enviado
+func (t *subscriptionTracker) retryable(now time.Time) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var pairs []string
+	for pair, failure := range t.failed {
+		if _, sent := t.pending[pair]; !sent && !failure.Excluded && !now.Before(failure.RetryAt) {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// excluded indica si el par dejó de reintentarse por errores de suscripción
+func (t *subscriptionTracker) excluded(pair string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failure, ok := t.failed[pair]
+	return ok && failure.Excluded
+}
+
+// failures retorna copias de los errores de suscripción ordenadas por par
+func (t *subscriptionTracker) failures() []SubscriptionFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	failures := make([]SubscriptionFailure, 0, len(t.failed))
+	for _, failure := range t.failed {
+		failures = append(failures, *failure)
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Pair < failures[j].Pair })
+	return failures
+}
+
+// clearFailures descarta los errores de suscripción, rehabilitando los pares
+// excluidos (por ejemplo al cerrar el cliente)
+func (t *subscriptionTracker) clearFailures() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failed = nil
+}
+
+// forget descarta el estado de los pares (por ejemplo al desuscribirlos)
+func (t *subscriptionTracker) forget(pairs []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, pair := range pairs {
+		delete(t.pending, pair)
+		delete(t.confirmed, pair)
+		delete(t.failed, pair)
+	}
+	t.publish()
+}
+
+// reset descarta el estado de la conexión (por ejemplo al perderla); los errores
+// de suscripción se conservan
+func (t *subscriptionTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = nil
+	t.confirmed = nil
+	t.publish()
+}
+
+// expired retorna los pares pendientes cuya confirmación excedió timeout y que
+// aún tienen intentos disponibles
+func (t *subscriptionTracker) expired(now time.Time, timeout time.Duration) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var pairs []string
+	for pair, p := range t.pending {
+		if now.Sub(p.sentAt) >= timeout && p.attempts < MaxSubscriptionAttempts {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// snapshot retorna copias ordenadas de pares confirmados y pendientes
+func (t *subscriptionTracker) snapshot() (confirmed, pending []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for pair := range t.confirmed {
+		confirmed = append(confirmed, pair)
+	}
+	for pair := range t.pending {
+		pending = append(pending, pair)
+	}
+	sort.Strings(confirmed)
+	sort.Strings(pending)
+	return confirmed, pending
+}
+
+func (t *subscriptionTracker) init() {
+	if t.pending == nil {
+		t.pending = make(map[string]*pendingSubscription)
+	}
+	if t.confirmed == nil {
+		t.confirmed = make(map[string]bool)
+	}
+	if t.failed == nil {
+		t.failed = make(map[string]*SubscriptionFailure)
+	}
+}
+
+// publish actualiza el gauge de suscripciones sin confirmar (requiere lock)
+func (t *subscriptionTracker) publish() {
+	delta := len(t.pending) - t.published
+	t.published = len(t.pending)
+	metrics.UpdateWebSocketUnconfirmedSubscriptions(int(unconfirmedSubscriptions.Add(int64(delta))))
+}
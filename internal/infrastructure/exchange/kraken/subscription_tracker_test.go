@@ -0,0 +1,142 @@
+package kraken
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionTracker_PendingAndConfirm(t *testing.T) {
+	var tracker subscriptionTracker
+	now := time.Now()
+
+	tracker.markPending([]string{"BTC/USD", "ETH/USD"}, now)
+	confirmed, pending := tracker.snapshot()
+	assert.Empty(t, confirmed)
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD"}, pending)
+
+	tracker.confirm("BTC/USD")
+	confirmed, pending = tracker.snapshot()
+	assert.Equal(t, []string{"BTC/USD"}, confirmed)
+	assert.Equal(t, []string{"ETH/USD"}, pending)
+}
+
+func TestSubscriptionTracker_Expired(t *testing.T) {
+	var tracker subscriptionTracker
+	now := time.Now()
+
+	tracker.markPending([]string{"BTC/USD"}, now)
+	assert.Empty(t, tracker.expired(now.Add(time.Second), SubscriptionConfirmTimeout))
+	assert.Equal(t, []string{"BTC/USD"}, tracker.expired(now.Add(SubscriptionConfirmTimeout), SubscriptionConfirmTimeout))
+
+	// Al agotar los intentos, el par deja de reintentarse pero sigue pendiente
+	for i := 1; i < MaxSubscriptionAttempts; i++ {
+		tracker.markPending([]string{"BTC/USD"}, now)
+	}
+	assert.Empty(t, tracker.expired(now.Add(time.Hour), SubscriptionConfirmTimeout))
+	_, pending := tracker.snapshot()
+	assert.Equal(t, []string{"BTC/USD"}, pending)
+}
+
+func TestSubscriptionTracker_Reset(t *testing.T) {
+	var tracker subscriptionTracker
+	tracker.markPending([]string{"BTC/USD"}, time.Now())
+	tracker.confirm("BTC/USD")
+
+	tracker.reset()
+	confirmed, pending := tracker.snapshot()
+	assert.Empty(t, confirmed)
+	assert.Empty(t, pending)
+}
+
+func TestWebSocketClient_HandleEventMessage_ConfirmsSubscription(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.subs.markPending([]string{"BTC/USD"}, time.Now())
+
+	err := client.handleEventMessage(WebSocketMessage{Event: "subscriptionStatus", Status: "subscribed", Pair: []string{"XBT/USD"}})
+	assert.NoError(t, err)
+
+	confirmed, pending := client.GetSubscriptionStatus()
+	assert.Equal(t, []string{"BTC/USD"}, confirmed)
+	assert.Empty(t, pending)
+}
+
+func TestWebSocketClient_HandleMessage_SubscriptionStatusSinglePair(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.subs.markPending([]string{"ETH/USD"}, time.Now())
+
+	raw := []byte(`{"channelID":1,"channelName":"ticker","event":"subscriptionStatus","pair":"ETH/USD","status":"subscribed","subscription":{"name":"ticker"}}`)
+	assert.NoError(t, client.handleMessage(raw))
+
+	confirmed, _ := client.GetSubscriptionStatus()
+	assert.Equal(t, []string{"ETH/USD"}, confirmed)
+}
+
+func TestSubscriptionTracker_FailuresBackoffAndExclusion(t *testing.T) {
+	var tracker subscriptionTracker
+	now := time.Now()
+	tracker.markPending([]string{"BTC/USD", "ETH/USD"}, now)
+
+	failure := tracker.fail("ETH/USD", "Subscription depth not supported", now)
+	assert.Equal(t, 1, failure.Failures)
+	assert.Equal(t, now.Add(SubscriptionRetryBackoff), failure.RetryAt)
+	_, pending := tracker.snapshot()
+	assert.Equal(t, []string{"BTC/USD"}, pending, "a rejected pair is no longer pending")
+	assert.Empty(t, tracker.retryable(now.Add(time.Second)))
+	assert.Equal(t, []string{"ETH/USD"}, tracker.retryable(failure.RetryAt))
+
+	tracker.markPending([]string{"ETH/USD"}, failure.RetryAt)
+	assert.Empty(t, tracker.retryable(now.Add(time.Hour)), "a retry already sent is not repeated")
+
+	tracker.reset()
+	assert.Len(t, tracker.failures(), 1, "failures survive a lost connection")
+
+	assert.Equal(t, 2*SubscriptionRetryBackoff, tracker.fail("ETH/USD", "again", now).RetryAt.Sub(now))
+	assert.Equal(t, MaxSubscriptionRetryBackoff, subscriptionRetryDelay(20))
+	for i := 2; i < MaxSubscriptionFailures-1; i++ {
+		tracker.fail("ETH/USD", "again", now)
+	}
+	assert.False(t, tracker.excluded("ETH/USD"))
+	failure = tracker.fail("ETH/USD", "Currency pair not supported", now)
+	assert.True(t, failure.Excluded)
+	assert.True(t, tracker.excluded("ETH/USD"))
+	assert.Empty(t, tracker.retryable(now.Add(time.Hour)), "excluded pairs are not retried")
+	assert.Equal(t, []SubscriptionFailure{{
+		Pair:      "ETH/USD",
+		Failures:  MaxSubscriptionFailures,
+		LastError: "Currency pair not supported",
+		LastAt:    now,
+		Excluded:  true,
+	}}, tracker.failures())
+
+	tracker.clearFailures()
+	assert.False(t, tracker.excluded("ETH/USD"))
+	tracker.fail("BTC/USD", "rejected", now)
+	tracker.confirm("BTC/USD")
+	assert.Empty(t, tracker.failures(), "a confirmation clears the failures")
+}
+
+func TestWebSocketClient_SubscriptionErrorsExcludePair(t *testing.T) {
+	client := createTestWebSocketClient("ws://localhost:9999")
+	client.isConnected = true
+	client.subscriptions["ETH/USD"] = true
+	client.subs.markPending([]string{"ETH/USD"}, time.Now())
+
+	rejected := WebSocketMessage{Event: "subscriptionStatus", Status: "error", Pair: []string{"ETH/USD"}, ErrorMessage: "Currency pair not supported"}
+	for i := 0; i < MaxSubscriptionFailures; i++ {
+		assert.NoError(t, client.handleEventMessage(rejected))
+	}
+	assert.Error(t, client.handleEventMessage(WebSocketMessage{Event: "subscriptionStatus", Status: "error", ErrorMessage: "Malformed request"}), "errors without pairs are reported")
+
+	failures := client.SubscriptionFailures()
+	assert.Len(t, failures, 1)
+	assert.True(t, failures[0].Excluded)
+
+	results, err := client.GetTickersByPair(context.Background(), []string{"ETH/USD"})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results["ETH/USD"].Err, ErrSubscriptionExcluded, "excluded pairs fail at once so REST serves them")
+	_, err = client.GetTicker(context.Background(), "ETH/USD")
+	assert.ErrorIs(t, err, ErrSubscriptionExcluded)
+}
@@ -0,0 +1,67 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+)
+
+// Estados del evento systemStatus del WebSocket de Kraken
+const (
+	SystemStatusOnline      = "online"
+	SystemStatusMaintenance = "maintenance"
+	SystemStatusCancelOnly  = "cancel_only"
+	SystemStatusLimitOnly   = "limit_only"
+	SystemStatusPostOnly    = "post_only"
+)
+
+// SystemStatus retorna el último estado informado por Kraken en esta conexión;
+// vacío si aún no llegó ningún systemStatus
+func (k *WebSocketClient) SystemStatus() string {
+	status, _ := k.systemStatus.Load().(string)
+	return status
+}
+
+// setSystemStatus registra el estado recibido; Kraken lo envía al conectar y en
+// cada cambio, por lo que sólo se loguean las transiciones
+func (k *WebSocketClient) setSystemStatus(status string) {
+	previous, _ := k.systemStatus.Swap(status).(string)
+	metrics.UpdateKrakenSystemStatus(statusLabel(status))
+	if previous == status {
+		return
+	}
+
+	fields := logging.Fields{
+		"status":   status,
+		"previous": previous,
+		"url":      k.currentURL(),
+	}
+	if status == SystemStatusMaintenance {
+		logging.Warn(k.logContext(), "Kraken entered maintenance, serving prices from REST only", fields)
+		return
+	}
+	logging.Info(k.logContext(), "Kraken WebSocket system status", fields)
+}
+
+// statusLabel acota el label de la métrica a los estados conocidos
+func statusLabel(status string) string {
+	switch status {
+	case SystemStatusOnline, SystemStatusMaintenance, SystemStatusCancelOnly, SystemStatusLimitOnly, SystemStatusPostOnly:
+		return status
+	}
+	return "unknown"
+}
+
+// SystemStatus retorna el estado de Kraken: maintenance si alguna conexión lo
+// informa y, si no, el de la primera conexión que recibió uno
+func (s *ShardedWebSocketClient) SystemStatus() string {
+	status := ""
+	for _, shard := range s.snapshotShards() {
+		switch current := shard.SystemStatus(); {
+		case current == SystemStatusMaintenance:
+			return current
+		case status == "":
+			status = current
+		}
+	}
+	return status
+}
@@ -0,0 +1,53 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketClient_SystemStatusEvent(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	client := NewWebSocketClient()
+	defer client.Close()
+	assert.Empty(t, client.SystemStatus())
+
+	require.NoError(t, client.handleMessage([]byte(`{"connectionID":1,"event":"systemStatus","status":"online","version":"1.9.0"}`)))
+	assert.Equal(t, SystemStatusOnline, client.SystemStatus())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebSocket.KrakenSystemStatus.WithLabelValues("online")))
+
+	require.NoError(t, client.handleMessage([]byte(`{"event":"systemStatus","status":"maintenance"}`)))
+	assert.Equal(t, SystemStatusMaintenance, client.SystemStatus())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebSocket.KrakenSystemStatus.WithLabelValues("maintenance")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.WebSocket.KrakenSystemStatus), "only the current status is exported")
+
+	require.NoError(t, client.handleMessage([]byte(`{"event":"systemStatus","status":"something_new"}`)))
+	assert.Equal(t, "something_new", client.SystemStatus())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebSocket.KrakenSystemStatus.WithLabelValues("unknown")))
+}
+
+func TestShardedWebSocketClient_SystemStatus(t *testing.T) {
+	sharded := NewShardedWebSocketClient(config.KrakenConfig{MaxPairsPerConnection: 1})
+	defer sharded.Close()
+	sharded.mu.Lock()
+	sharded.addShard()
+	sharded.mu.Unlock()
+	shards := sharded.snapshotShards()
+	require.Len(t, shards, 2)
+	assert.Empty(t, sharded.SystemStatus())
+
+	shards[1].setSystemStatus(SystemStatusCancelOnly)
+	assert.Equal(t, SystemStatusCancelOnly, sharded.SystemStatus())
+	shards[0].setSystemStatus(SystemStatusOnline)
+	assert.Equal(t, SystemStatusOnline, sharded.SystemStatus(), "first connection with a status")
+	shards[1].setSystemStatus(SystemStatusMaintenance)
+	assert.Equal(t, SystemStatusMaintenance, sharded.SystemStatus(), "maintenance on any connection wins")
+}
@@ -0,0 +1,283 @@
+package kraken
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var errInvalidTickerData = errors.New("invalid ticker data format")
+
+// wsTicker son los campos de un ticker v1 que usa el cliente
+type wsTicker struct {
+	Last         float64
+	Bid          float64
+	Ask          float64
+	ExchangeTime time.Time
+}
+
+// tickerFromMap extrae el ticker de un objeto ya decodificado a interface{}
+func tickerFromMap(fields map[string]interface{}) (wsTicker, error) {
+	lastTradeInterface, ok := fields["c"]
+	if !ok {
+		return wsTicker{}, fmt.Errorf("no last trade data found")
+	}
+
+	lastTradeArray, ok := lastTradeInterface.([]interface{})
+	if !ok || len(lastTradeArray) == 0 {
+		return wsTicker{}, fmt.Errorf("invalid last trade format")
+	}
+
+	priceStr, ok := lastTradeArray[0].(string)
+	if !ok {
+		return wsTicker{}, fmt.Errorf("invalid price format")
+	}
+
+	price, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil {
+		return wsTicker{}, fmt.Errorf("failed to parse price: %w", err)
+	}
+
+	ticker := wsTicker{
+		Last: price,
+		Bid:  firstTickerValue(fields["b"]),
+		Ask:  firstTickerValue(fields["a"]),
+	}
+	if ts, ok := parseExchangeTimestamp(fields["timestamp"]); ok {
+		ticker.ExchangeTime = ts
+	}
+	return ticker, nil
+}
+
+// decodeTicker decodifica el objeto tickerData de un frame sin reflection ni
+// valores interface{}: recorre las claves de primer nivel y sólo interpreta "c",
+// "b", "a" y "timestamp"; el resto se saltea sin copiarse. Los errores coinciden
+// con los de tickerFromMap.
+func decodeTicker(data []byte) (wsTicker, error) {
+	var last, bid, ask, timestamp []byte
+
+	s := tickerScanner{data: data}
+	if !s.consume('{') {
+		return wsTicker{}, errInvalidTickerData
+	}
+	if !s.consume('}') {
+		for {
+			key, ok := s.rawString()
+			if !ok || !s.consume(':') {
+				return wsTicker{}, errInvalidTickerData
+			}
+			value, ok := s.value()
+			if !ok {
+				return wsTicker{}, errInvalidTickerData
+			}
+			switch string(key) {
+			case "c":
+				last = value
+			case "b":
+				bid = value
+			case "a":
+				ask = value
+			case "timestamp":
+				timestamp = value
+			}
+			if s.consume(',') {
+				continue
+			}
+			if s.consume('}') {
+				break
+			}
+			return wsTicker{}, errInvalidTickerData
+		}
+	}
+
+	if last == nil {
+		return wsTicker{}, fmt.Errorf("no last trade data found")
+	}
+	first, ok := firstElement(last)
+	if !ok {
+		return wsTicker{}, fmt.Errorf("invalid last trade format")
+	}
+	if len(first) == 0 || first[0] != '"' {
+		return wsTicker{}, fmt.Errorf("invalid price format")
+	}
+	price, err := parseQuotedFloat(first)
+	if err != nil {
+		return wsTicker{}, fmt.Errorf("failed to parse price: %w", err)
+	}
+
+	ticker := wsTicker{
+		Last: price,
+		Bid:  firstQuotedValue(bid),
+		Ask:  firstQuotedValue(ask),
+	}
+	if ts, ok := decodeExchangeTimestamp(timestamp); ok {
+		ticker.ExchangeTime = ts
+	}
+	return ticker, nil
+}
+
+// firstQuotedValue es el equivalente de firstTickerValue sobre el JSON crudo
+func firstQuotedValue(raw []byte) float64 {
+	first, ok := firstElement(raw)
+	if !ok || len(first) == 0 || first[0] != '"' {
+		return 0
+	}
+	value, _ := parseQuotedFloat(first)
+	return value
+}
+
+// decodeExchangeTimestamp interpreta el campo "timestamp" crudo como
+// parseExchangeTimestamp: string RFC3339 o segundos Unix, o número
+func decodeExchangeTimestamp(raw []byte) (time.Time, bool) {
+	if len(raw) == 0 {
+		return time.Time{}, false
+	}
+	if raw[0] == '"' {
+		text, ok := unquote(raw)
+		if !ok {
+			return time.Time{}, false
+		}
+		return parseExchangeTimestamp(text)
+	}
+	seconds, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return unixSecondsToTime(seconds)
+}
+
+// parseQuotedFloat parsea un número enviado como string JSON ("50000.1")
+func parseQuotedFloat(quoted []byte) (float64, error) {
+	if !bytes.ContainsRune(quoted, '\\') && len(quoted) >= 2 {
+		return strconv.ParseFloat(string(quoted[1:len(quoted)-1]), 64)
+	}
+	text, ok := unquote(quoted)
+	if !ok {
+		return 0, errInvalidTickerData
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// unquote decodifica un string JSON con escapes
+func unquote(quoted []byte) (string, bool) {
+	var text string
+	if err := json.Unmarshal(quoted, &text); err != nil {
+		return "", false
+	}
+	return text, true
+}
+
+// firstElement devuelve el primer elemento crudo del array raw; false si raw no
+// es un array o está vacío
+func firstElement(raw []byte) ([]byte, bool) {
+	s := tickerScanner{data: raw}
+	if !s.consume('[') || s.consume(']') {
+		return nil, false
+	}
+	return s.value()
+}
+
+// tickerScanner recorre JSON sin asignar memoria. Sólo delimita valores
+// (respetando strings y anidamiento); no valida literales ni números.
+type tickerScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *tickerScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume avanza sobre c si es el próximo byte significativo
+func (s *tickerScanner) consume(c byte) bool {
+	s.skipSpace()
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// rawString devuelve el contenido (sin comillas ni decodificar escapes) del
+// string que sigue
+func (s *tickerScanner) rawString() ([]byte, bool) {
+	s.skipSpace()
+	if s.pos >= len(s.data) || s.data[s.pos] != '"' {
+		return nil, false
+	}
+	start := s.pos
+	if !s.skipString() {
+		return nil, false
+	}
+	return s.data[start+1 : s.pos-1], true
+}
+
+// skipString avanza sobre el string que empieza en pos
+func (s *tickerScanner) skipString() bool {
+	for i := s.pos + 1; i < len(s.data); i++ {
+		switch s.data[i] {
+		case '\\':
+			i++
+		case '"':
+			s.pos = i + 1
+			return true
+		}
+	}
+	return false
+}
+
+// value devuelve el valor crudo que sigue (string, número, literal, objeto o array)
+func (s *tickerScanner) value() ([]byte, bool) {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return nil, false
+	}
+	start := s.pos
+	switch s.data[s.pos] {
+	case '"':
+		if !s.skipString() {
+			return nil, false
+		}
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case '"':
+				if !s.skipString() {
+					return nil, false
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			s.pos++
+			if depth == 0 {
+				break
+			}
+		}
+		if depth != 0 {
+			return nil, false
+		}
+	default:
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				return s.data[start:s.pos], s.pos > start
+			}
+			s.pos++
+		}
+	}
+	return s.data[start:s.pos], true
+}
@@ -0,0 +1,88 @@
+package kraken
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTicker_MatchesGenericDecoding(t *testing.T) {
+	inputs := []string{
+		`{"a":["50001.00000",1,"1.000"],"b":["49999.00000",2,"2.000"],"c":["50000.00000","0.01"],"v":["1","2"],"t":[10,20],"o":["49500.0","49500.0"]}`,
+		` { "c" : [ "50000.5" , "1" ] , "nested" : {"c":["1","1"],"s":"},]"} } `,
+		`{"c":["50000.1","1"],"timestamp":"2026-01-02T03:04:05.5Z"}`,
+		`{"c":["50000.1","1"],"timestamp":1767323045.5}`,
+		`{"c":["50000.1","1"],"timestamp":"1767323045.5"}`,
+		`{"c":["50000.1","1"],"b":[1],"a":[]}`,
+		`{}`,
+		`{"c":"50000"}`,
+		`{"c":[]}`,
+		`{"c":[50000,"1"]}`,
+		`{"c":["abc","1"]}`,
+	}
+
+	for _, input := range inputs {
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(input), &fields), input)
+		want, wantErr := tickerFromMap(fields)
+
+		got, err := decodeTicker([]byte(input))
+		if wantErr != nil {
+			require.Error(t, err, input)
+			assert.Equal(t, wantErr.Error(), err.Error(), input)
+			continue
+		}
+		require.NoError(t, err, input)
+		assert.Equal(t, want.Last, got.Last, input)
+		assert.Equal(t, want.Bid, got.Bid, input)
+		assert.Equal(t, want.Ask, got.Ask, input)
+		assert.True(t, want.ExchangeTime.Equal(got.ExchangeTime), input)
+	}
+}
+
+func TestDecodeTicker_InvalidObjects(t *testing.T) {
+	for _, input := range []string{`"not-an-object"`, `[1,2]`, `{"c":["1","1"]`, `{"c" ["1"]}`, `{"c":["1"],}`, `{"c":["1]}`} {
+		_, err := decodeTicker([]byte(input))
+		assert.ErrorIs(t, err, errInvalidTickerData, input)
+	}
+}
+
+func TestDecodeTicker_ExchangeTimestamp(t *testing.T) {
+	ticker, err := decodeTicker([]byte(`{"c":["1.5","1"],"timestamp":"2026-01-02T03:04:05.5Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 500000000, time.UTC), ticker.ExchangeTime)
+}
+
+func FuzzDecodeTicker(f *testing.F) {
+	f.Add(`{"a":["50001.00000",1,"1.000"],"b":["49999.00000",2,"2.000"],"c":["50000.00000","0.01"]}`)
+	f.Add(`{"c":["50000.1","1"],"timestamp":"2026-01-02T03:04:05.5Z"}`)
+	f.Add(`{"c":["50000.1","1"],"timestamp":1767323045.5}`)
+	f.Add(`{"c":["1]}`)
+	f.Add(`{"c":[[[[["1"]]]]]}`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		got, err := decodeTicker([]byte(input))
+
+		// Sin escapes en las claves, el decoder coincide con la decodificación genérica
+		var fields map[string]interface{}
+		if strings.ContainsRune(input, '\\') || json.Unmarshal([]byte(input), &fields) != nil || fields == nil {
+			return
+		}
+		want, wantErr := tickerFromMap(fields)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("decodeTicker(%q) error = %v, generic decoding error = %v", input, err, wantErr)
+		}
+		if err == nil && !sameFloat(want.Last, got.Last) {
+			t.Fatalf("decodeTicker(%q) last = %v, generic decoding = %v", input, got.Last, want.Last)
+		}
+	})
+}
+
+// sameFloat compara dos precios parseados considerando NaN igual a NaN
+func sameFloat(a, b float64) bool {
+	return a == b || (a != a && b != b)
+}
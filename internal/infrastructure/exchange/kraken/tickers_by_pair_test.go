@@ -0,0 +1,65 @@
+package kraken
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketClient_GetTickersByPair_CorrelatesByPair(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := createTestWebSocketClient(mockServer.getURL())
+	client.cache = nil // forzar espera por WebSocket
+	require.NoError(t, client.Connect())
+	defer func() {
+		_ = client.Close()
+	}()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		// ETH llega primero: no debe ocupar el lugar de BTC
+		mockServer.sendTickerUpdate("ETH/USD", "3000.0")
+		mockServer.sendTickerUpdate("XBT/USD", "50000.0")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	results, err := client.GetTickersByPair(ctx, []string{"BTC/USD", "ETH/USD", "BTC/USD"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 50000.0, results["BTC/USD"].Price.Amount)
+	assert.Equal(t, 3000.0, results["ETH/USD"].Price.Amount)
+}
+
+func TestWebSocketClient_GetTickers_ReportsMissingPairs(t *testing.T) {
+	mockServer := newMockWebSocketServer()
+	defer mockServer.close()
+
+	client := createTestWebSocketClient(mockServer.getURL())
+	client.cache = nil
+	require.NoError(t, client.Connect())
+	defer func() {
+		_ = client.Close()
+	}()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		mockServer.sendTickerUpdate("XBT/USD", "50000.0")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	prices, err := client.GetTickers(ctx, []string{"BTC/USD", "ETH/USD"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "ETH/USD")
+	require.Len(t, prices, 1)
+	assert.Equal(t, "BTC/USD", prices[0].Pair)
+}
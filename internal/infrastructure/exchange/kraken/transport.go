@@ -0,0 +1,164 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrCertificatePin indica que ningún certificado de la cadena coincide con los pines SPKI
+var ErrCertificatePin = errors.New("kraken certificate matches no pinned SPKI hash")
+
+// transport son las opciones de las conexiones salientes a Kraken que comparten
+// REST, WebSocket y AssetPairs: proxy, allowlist de salida y validación TLS. Un
+// transport nil usa los valores por defecto de net/http y gorilla/websocket.
+type transport struct {
+	egress *egressDialer // nil: dialer por defecto; con proxy sólo ve la conexión al proxy
+	tls    *tls.Config
+	proxy  func(*http.Request) (*url.URL, error)
+}
+
+// newTransport crea el transport de cfg, ya validada
+func newTransport(cfg config.KrakenConfig) *transport {
+	return &transport{
+		egress: newEgressDialer(cfg.Egress),
+		tls:    newTLSConfig(cfg.TLS),
+		proxy:  newProxyFunc(cfg.Proxy),
+	}
+}
+
+// newProxyFunc elige el proxy de cada request según cfg; los campos vacíos usan
+// HTTP_PROXY, HTTPS_PROXY y NO_PROXY. Los requests a localhost nunca usan proxy.
+func newProxyFunc(cfg config.ProxyConfig) func(*http.Request) (*url.URL, error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if cfg.URL != "" {
+		proxyConfig.HTTPProxy, proxyConfig.HTTPSProxy = cfg.URL, cfg.URL
+	}
+	if cfg.NoProxy != "" {
+		proxyConfig.NoProxy = cfg.NoProxy
+	}
+	proxyFor := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFor(req.URL)
+	}
+}
+
+// httpClient crea un cliente HTTP con timeout sobre el transport
+func (t *transport) httpClient(timeout time.Duration) *http.Client {
+	if t == nil {
+		return &http.Client{Timeout: timeout}
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = t.tls
+	base.Proxy = t.proxy
+	if t.egress != nil {
+		base.DialContext = t.egress.DialContext
+	}
+	return &http.Client{Timeout: timeout, Transport: base}
+}
+
+// websocketDialer crea el dialer WebSocket sobre el transport
+func (t *transport) websocketDialer() websocket.Dialer {
+	dialer := websocket.Dialer{
+		ReadBufferSize:  ReadBufferSize,
+		WriteBufferSize: WriteBufferSize,
+	}
+	if t == nil {
+		return dialer
+	}
+	dialer.TLSClientConfig = t.tls
+	dialer.Proxy = t.proxy
+	if t.egress != nil {
+		dialer.NetDialContext = t.egress.DialContext
+	}
+	return dialer
+}
+
+// refreshDNS hace que la próxima conexión vuelva a resolver los hosts con
+// pin_dns; sin pin_dns cada conexión ya consulta el DNS
+func (t *transport) refreshDNS() {
+	if t == nil {
+		return
+	}
+	t.egress.expire()
+}
+
+// newTLSConfig crea la configuración TLS de cfg. Si el CA bundle no se puede leer
+// (la validación de config ya lo comprobó) los handshakes fallan en lugar de
+// recurrir a las raíces del sistema.
+func newTLSConfig(cfg config.KrakenTLSConfig) *tls.Config {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.MinVersion == "1.3" {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+
+	var loadErr error
+	if cfg.CAFile != "" {
+		pool, err := loadCABundle(cfg.CAFile)
+		if err != nil {
+			loadErr = err
+			logging.Error(context.Background(), "Failed to load Kraken CA bundle, connections will be refused", logging.Fields{
+				"ca_file": cfg.CAFile,
+				"error":   err.Error(),
+			})
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	pins := make(map[[sha256.Size]byte]bool, len(cfg.PinnedSPKI))
+	for _, pin := range cfg.PinnedSPKI {
+		if hash, err := base64.StdEncoding.DecodeString(pin); err == nil && len(hash) == sha256.Size {
+			pins[[sha256.Size]byte(hash)] = true
+		}
+	}
+
+	if loadErr != nil || len(pins) > 0 {
+		// VerifyConnection corre después de la verificación normal de la cadena
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if loadErr != nil {
+				return loadErr
+			}
+			return verifyPins(state, pins)
+		}
+	}
+	return tlsConfig
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	bundle, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// verifyPins acepta la conexión si algún certificado de una cadena verificada
+// tiene la clave pública de un pin, de modo que fijar la CA intermedia sobrevive
+// a la renovación del certificado de Kraken
+func verifyPins(state tls.ConnectionState, pins map[[sha256.Size]byte]bool) error {
+	for _, chain := range state.VerifiedChains {
+		for _, cert := range chain {
+			if pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)] {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrCertificatePin, state.ServerName)
+}
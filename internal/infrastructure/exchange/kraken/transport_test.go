@@ -0,0 +1,134 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeServerCA guarda el certificado de server como bundle PEM
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, block, 0o600))
+	return path
+}
+
+func spkiPin(server *httptest.Server) string {
+	hash := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func tlsGet(t *testing.T, cfg config.KrakenTLSConfig, url string) error {
+	resp, err := newTransport(config.KrakenConfig{TLS: cfg}).httpClient(5 * time.Second).Get(url)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	return err
+}
+
+func TestTransport_CustomCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	assert.Error(t, tlsGet(t, config.KrakenTLSConfig{}, server.URL), "the test CA is not a system root")
+	assert.NoError(t, tlsGet(t, config.KrakenTLSConfig{CAFile: writeServerCA(t, server)}, server.URL))
+
+	err := tlsGet(t, config.KrakenTLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}, server.URL)
+	assert.Error(t, err, "an unreadable bundle refuses connections instead of using the system roots")
+}
+
+func TestTransport_PinnedSPKI(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caFile := writeServerCA(t, server)
+
+	pinned := config.KrakenTLSConfig{CAFile: caFile, PinnedSPKI: []string{spkiPin(server)}}
+	assert.NoError(t, tlsGet(t, pinned, server.URL))
+
+	other := sha256.Sum256([]byte("another key"))
+	mismatch := config.KrakenTLSConfig{CAFile: caFile, PinnedSPKI: []string{base64.StdEncoding.EncodeToString(other[:])}}
+	assert.ErrorIs(t, tlsGet(t, mismatch, server.URL), ErrCertificatePin)
+}
+
+func TestTransport_MinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writeServerCA(t, server)
+
+	assert.NoError(t, tlsGet(t, config.KrakenTLSConfig{CAFile: caFile, MinVersion: "1.2"}, server.URL))
+	assert.Error(t, tlsGet(t, config.KrakenTLSConfig{CAFile: caFile, MinVersion: "1.3"}, server.URL))
+}
+
+func TestTransport_WebSocketDialerSharesTLS(t *testing.T) {
+	dialer := newTransport(config.KrakenConfig{TLS: config.KrakenTLSConfig{MinVersion: "1.3"}}).websocketDialer()
+	require.NotNil(t, dialer.TLSClientConfig)
+	assert.Equal(t, uint16(tls.VersionTLS13), dialer.TLSClientConfig.MinVersion)
+
+	var none *transport
+	assert.Nil(t, none.websocketDialer().TLSClientConfig)
+	assert.Nil(t, none.httpClient(time.Second).Transport)
+}
+
+func TestTransport_RESTThroughHTTPProxy(t *testing.T) {
+	t.Setenv("NO_PROXY", "")
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Un proxy HTTP recibe la URL absoluta del destino
+		proxiedHost = r.URL.Host
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(createMockKrakenResponse("XXBTZUSD", "50000.0"))
+	}))
+	defer proxy.Close()
+
+	client := NewRestClientWithConfig(config.KrakenConfig{
+		RestURL:        "http://api.kraken.test/0/public",
+		Timeout:        5 * time.Second,
+		RequestTimeout: 2 * time.Second,
+		Proxy:          config.ProxyConfig{URL: proxy.URL},
+	})
+
+	price, err := client.GetTicker(context.Background(), "BTC/USD")
+	require.NoError(t, err, "api.kraken.test only resolves through the proxy")
+	assert.Equal(t, "BTC/USD", price.Pair)
+	assert.Equal(t, "api.kraken.test", proxiedHost)
+}
+
+func TestTransport_ProxySelection(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	t.Setenv("NO_PROXY", "")
+	// gorilla/websocket consulta el proxy con el esquema https de wss://
+	wsRequest := httptest.NewRequest(http.MethodGet, "https://ws.kraken.com/", nil)
+
+	proxyFor := func(cfg config.ProxyConfig) string {
+		proxyURL, err := newTransport(config.KrakenConfig{Proxy: cfg}).websocketDialer().Proxy(wsRequest)
+		require.NoError(t, err)
+		if proxyURL == nil {
+			return ""
+		}
+		return proxyURL.String()
+	}
+
+	assert.Equal(t, "http://env-proxy:3128", proxyFor(config.ProxyConfig{}), "an empty url uses HTTPS_PROXY")
+	assert.Equal(t, "socks5://socks.internal:1080", proxyFor(config.ProxyConfig{URL: "socks5://socks.internal:1080"}))
+	assert.Empty(t, proxyFor(config.ProxyConfig{URL: "socks5://socks.internal:1080", NoProxy: ".kraken.com"}))
+	assert.Empty(t, proxyFor(config.ProxyConfig{NoProxy: "*"}), "no_proxy replaces NO_PROXY")
+
+	t.Setenv("NO_PROXY", "ws.kraken.com")
+	assert.Empty(t, proxyFor(config.ProxyConfig{}), "NO_PROXY applies when no_proxy is empty")
+}
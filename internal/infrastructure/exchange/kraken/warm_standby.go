@@ -0,0 +1,124 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"slices"
+	"time"
+)
+
+// standbyRetryDelay es la espera antes de reintentar abrir la conexión standby
+const standbyRetryDelay = 30 * time.Second
+
+// openStandby abre la conexión de reserva si exchange.kraken.warm_standby está
+// habilitado y no hay una. El standby queda conectado sin suscripciones; si el
+// dial falla se reintenta cada standbyRetryDelay.
+func (s *ShardedWebSocketClient) openStandby() {
+	s.mu.Lock()
+	if !s.cfg.WarmStandby || s.closed || s.standby != nil || s.logCtx == nil {
+		s.mu.Unlock()
+		return
+	}
+	standby := s.newConnection("")
+	standby.onDisconnect = func() { s.dropStandby(standby) }
+	s.standby = standby
+	logCtx := s.logCtx
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(logCtx, s.connectTimeout())
+	defer cancel()
+	if err := standby.ConnectContext(ctx); err != nil {
+		logging.Warn(ctx, "Failed to open standby WebSocket connection", logging.Fields{
+			"error":         err.Error(),
+			"retry_in":      standbyRetryDelay.String(),
+			"websocket_url": s.cfg.WebSocketURL,
+		})
+		s.mu.Lock()
+		if s.standby == standby {
+			s.standby = nil
+		}
+		if !s.closed {
+			s.standbyRetry = time.AfterFunc(standbyRetryDelay, s.openStandby)
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	s.mu.Lock()
+	ready := s.standby == standby
+	s.mu.Unlock()
+	if !ready {
+		// Shutdown empezó durante el dial
+		_ = standby.Close()
+		return
+	}
+	metrics.UpdateWebSocketStandbyStatus(true)
+	logging.Info(ctx, "Standby WebSocket connection ready", logging.Fields{
+		"websocket_url": s.cfg.WebSocketURL,
+	})
+}
+
+// dropStandby descarta un standby que perdió la conexión y abre otro
+func (s *ShardedWebSocketClient) dropStandby(standby *WebSocketClient) {
+	s.mu.Lock()
+	if s.standby != standby {
+		s.mu.Unlock()
+		return
+	}
+	s.standby = nil
+	s.mu.Unlock()
+
+	metrics.UpdateWebSocketStandbyStatus(false)
+	logging.Warn(standby.logContext(), "Standby WebSocket connection lost, reopening", logging.Fields{
+		"websocket_url": s.cfg.WebSocketURL,
+	})
+	_ = standby.Close()
+	s.openStandby()
+}
+
+// failover reemplaza un shard que perdió la conexión por el standby y suscribe en
+// él los pares del shard, evitando esperar el backoff de reconexión. Sin standby
+// listo el shard sigue su propio ciclo de reconexión.
+func (s *ShardedWebSocketClient) failover(failed *WebSocketClient) {
+	s.mu.Lock()
+	index := slices.Index(s.shards, failed)
+	standby := s.standby
+	if index < 0 || standby == nil || !standby.IsConnected() || s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.standby = nil
+	s.shards[index] = standby
+	label := failed.shard
+	// El shard caído suelta su etiqueta para no pisar las métricas del standby al cerrarse
+	failed.setRole("", nil)
+	standby.setRole(label, func() { s.failover(standby) })
+	s.mu.Unlock()
+
+	metrics.UpdateWebSocketStandbyStatus(false)
+	metrics.RecordWebSocketStandbyPromotion()
+
+	ctx := standby.logContext()
+	pairs := s.pairsOf(index)
+	promotion := ReconnectEvent{Shard: label, Started: time.Now(), Reason: ReconnectReasonStandby, Attempt: 1, Pairs: pairs}
+	if len(pairs) > 0 {
+		if err := standby.SubscribeTicker(pairs); err != nil {
+			promotion.Err = err
+			logging.Warn(ctx, "Failed to subscribe pairs on promoted standby connection", logging.Fields{
+				"shard": label,
+				"pairs": pairs,
+				"error": err.Error(),
+			})
+		}
+	}
+	promotion.Duration = time.Since(promotion.Started)
+	s.notifyReconnect(promotion)
+	logging.Warn(ctx, "WebSocket shard connection lost, promoted standby connection", logging.Fields{
+		"shard":       label,
+		"pairs_count": len(pairs),
+	})
+
+	_ = failed.Close()
+	s.openStandby()
+}
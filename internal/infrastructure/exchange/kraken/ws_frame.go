@@ -0,0 +1,175 @@
+package kraken
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Límites defensivos para los frames que envía el servidor. Un frame más grande
+// que el máximo (DefaultMaxFrameBytes salvo max_frame_bytes) corta la lectura
+// (websocket.ErrReadLimit) y reconecta; uno más anidado que maxFrameDepth se
+// descarta sin decodificarlo. Los mensajes de ticker y de eventos de Kraken
+// ocupan unos cientos de bytes con 3 niveles.
+const (
+	DefaultMaxFrameBytes = 1 << 20
+	maxFrameDepth        = 32
+)
+
+// errFrameTooDeep indica un frame que supera maxFrameDepth
+var errFrameTooDeep = errors.New("websocket frame exceeds maximum nesting depth")
+
+// maxPooledFrameBytes evita devolver al pool buffers inflados por un frame
+// excepcional (p. ej. un snapshot grande), que quedarían retenidos en memoria
+const maxPooledFrameBytes = 64 << 10
+
+// frameBufferPool reutiliza los buffers en que readMessages lee cada frame
+var frameBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// readFrame lee el frame completo de r en un buffer del pool; el llamador debe
+// devolverlo con releaseFrame cuando termine de procesar sus bytes
+func readFrame(r io.Reader) (*bytes.Buffer, error) {
+	buf := frameBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if _, err := buf.ReadFrom(r); err != nil {
+		releaseFrame(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+func releaseFrame(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledFrameBytes {
+		frameBufferPool.Put(buf)
+	}
+}
+
+// tickerFrame separa un frame de array [channelID, tickerData, channelName, pair]
+// sin decodificarlo a []interface{}: parts son subslices del mensaje original que
+// se reutilizan entre mensajes a través de tickerFramePool.
+type tickerFrame struct {
+	parts [][]byte
+}
+
+var tickerFramePool = sync.Pool{
+	New: func() any {
+		return &tickerFrame{parts: make([][]byte, 0, 4)}
+	},
+}
+
+func getTickerFrame() *tickerFrame {
+	return tickerFramePool.Get().(*tickerFrame)
+}
+
+// release limpia el frame y lo devuelve al pool; las partes no deben seguir en
+// uso después
+func (f *tickerFrame) release() {
+	clear(f.parts)
+	f.parts = f.parts[:0]
+	tickerFramePool.Put(f)
+}
+
+// split separa los elementos de primer nivel del array JSON data sin copiarlos.
+// Sólo delimita los elementos (respetando strings y anidamiento); su contenido
+// se valida al decodificar cada parte. false si data no es un array bien cerrado.
+func (f *tickerFrame) split(data []byte) bool {
+	f.parts = f.parts[:0]
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '[' || data[len(data)-1] != ']' {
+		return false
+	}
+
+	depth, start, inString, escaped := 0, 1, false, false
+	for i := 1; i < len(data)-1; i++ {
+		c := data[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		case c == ',' && depth == 0:
+			f.parts = append(f.parts, bytes.TrimSpace(data[start:i]))
+			start = i + 1
+		}
+	}
+	if inString || depth != 0 {
+		return false
+	}
+	if last := bytes.TrimSpace(data[start : len(data)-1]); len(last) > 0 || len(f.parts) > 0 {
+		f.parts = append(f.parts, last)
+	}
+	return true
+}
+
+// partString decodifica el elemento i del array como string; los strings sin
+// escapes (los pares y nombres de canal) se copian sin pasar por encoding/json
+func (f *tickerFrame) partString(i int) (string, bool) {
+	part := f.parts[i]
+	if len(part) >= 2 && part[0] == '"' && part[len(part)-1] == '"' && bytes.IndexByte(part[1:len(part)-1], '\\') < 0 && bytes.IndexByte(part[1:len(part)-1], '"') < 0 {
+		return string(part[1 : len(part)-1]), true
+	}
+	var s string
+	if err := json.Unmarshal(f.parts[i], &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// withinDepth indica si los arrays y objetos de data no se anidan más de max
+// niveles; los corchetes dentro de strings no cuentan
+func withinDepth(data []byte, max int) bool {
+	depth, inString, escaped := 0, false, false
+	for _, c := range data {
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '[' || c == '{':
+			depth++
+			if depth > max {
+				return false
+			}
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return true
+}
+
+// firstByte devuelve el primer byte que no es espacio en blanco (0 si no hay)
+func firstByte(data []byte) byte {
+	for _, b := range data {
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+package kraken
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTickerFrame_Split(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		ok    bool
+		parts []string
+	}{
+		{
+			name:  "ticker",
+			input: ` [340, {"c":["50000.0","1.0"],"x":{"y":[1,2]}}, "ticker", "XBT/USD"] `,
+			ok:    true,
+			parts: []string{`340`, `{"c":["50000.0","1.0"],"x":{"y":[1,2]}}`, `"ticker"`, `"XBT/USD"`},
+		},
+		{
+			name:  "commas and brackets inside strings",
+			input: `["a,b", "c]\"d", "{"]`,
+			ok:    true,
+			parts: []string{`"a,b"`, `"c]\"d"`, `"{"`},
+		},
+		{name: "empty array", input: `[]`, ok: true},
+		{name: "object", input: `{"event":"heartbeat"}`, ok: false},
+		{name: "unbalanced", input: `[1, {"a": [1}]`, ok: false},
+		{name: "unterminated string", input: `[1, "abc]`, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := getTickerFrame()
+			defer frame.release()
+
+			require.Equal(t, tt.ok, frame.split([]byte(tt.input)))
+			if !tt.ok {
+				return
+			}
+			got := make([]string, 0, len(frame.parts))
+			for _, part := range frame.parts {
+				got = append(got, string(part))
+			}
+			assert.Equal(t, len(tt.parts), len(got))
+			if len(tt.parts) > 0 {
+				assert.Equal(t, tt.parts, got)
+			}
+		})
+	}
+}
+
+func TestTickerFrame_PartString(t *testing.T) {
+	frame := getTickerFrame()
+	defer frame.release()
+
+	require.True(t, frame.split([]byte(`[1, "XBT/USD", "tick\u0065r", 5]`)))
+	pair, ok := frame.partString(1)
+	assert.True(t, ok)
+	assert.Equal(t, "XBT/USD", pair)
+	channel, ok := frame.partString(2)
+	assert.True(t, ok, "escaped strings fall back to encoding/json")
+	assert.Equal(t, "ticker", channel)
+	_, ok = frame.partString(3)
+	assert.False(t, ok)
+}
+
+func TestWebSocketClient_handleMessage_PooledFramesDoNotLeak(t *testing.T) {
+	client := NewWebSocketClient()
+	defer client.Close()
+
+	require.NoError(t, client.handleMessage([]byte(`[1,{"c":["50000.0","1"],"b":["49999.0","1","1.0"]},"ticker","XBT/USD"]`)))
+	// El segundo frame reutiliza el frame del pool: "b" del anterior no debe sobrevivir
+	require.NoError(t, client.handleMessage([]byte(`[1,{"c":["51000.0","1"]},"ticker","XBT/USD"]`)))
+
+	price, ok := client.cache.Get(context.Background(), "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, 51000.0, price.Amount)
+	assert.Zero(t, price.Bid)
+
+	err := client.handleMessage([]byte(`[1,"not-an-object","ticker","XBT/USD"]`))
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "invalid ticker data format"))
+}
+
+func TestWebSocketClient_handleMessage_RejectsDeepFrames(t *testing.T) {
+	client := NewWebSocketClient()
+	defer client.Close()
+
+	deep := strings.Repeat("[", maxFrameDepth+1) + strings.Repeat("]", maxFrameDepth+1)
+	assert.ErrorIs(t, client.handleMessage([]byte(deep)), errFrameTooDeep)
+	deepEvent := `{"event":` + strings.Repeat(`{"a":`, maxFrameDepth) + `1` + strings.Repeat("}", maxFrameDepth+1)
+	assert.ErrorIs(t, client.handleMessage([]byte(deepEvent)), errFrameTooDeep)
+
+	assert.True(t, withinDepth([]byte(`[1,{"c":["50000.0","1"]},"ticker","XBT/USD"]`), 3))
+	assert.True(t, withinDepth([]byte(`["[[[[[[[["]`), 1), "brackets inside strings do not count")
+	assert.False(t, withinDepth([]byte(`[[[1]]]`), 2))
+}
+
+func TestWebSocketClient_safeHandleMessage_RecoversPanics(t *testing.T) {
+	client := NewWebSocketClient()
+	defer client.Close()
+	client.SetPriceValidator(panickingValidator{})
+
+	// La goroutine de lectura no debe caer por un panic al procesar un frame
+	err := client.safeHandleMessage([]byte(`[1,{"c":["50000.0","1"]},"ticker","XBT/USD"]`), time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "panic handling WebSocket message")
+}
+
+func FuzzWebSocketClient_handleMessage(f *testing.F) {
+	f.Add([]byte(`[340,{"a":["50001.00000",1,"1.000"],"b":["49999.00000",2,"2.000"],"c":["50000.00000","0.01"]},"ticker","XBT/USD"]`))
+	f.Add([]byte(`[1,{"c":["51000.0","1"],"timestamp":1767323045.5},"ticker","ETH/USD"]`))
+	f.Add([]byte(`{"event":"subscriptionStatus","status":"subscribed","pair":"XBT/USD","subscription":{"name":"ticker"}}`))
+	f.Add([]byte(`{"event":"heartbeat"}`))
+	f.Add([]byte(`[1,"not-an-object","ticker","XBT/USD"]`))
+	f.Add([]byte(`[[[[[[[[[[`))
+
+	client := NewWebSocketClient()
+	f.Cleanup(func() { _ = client.Close() })
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		// Cualquier frame se procesa o se rechaza con error, nunca con panic
+		_ = client.handleMessage(frame)
+	})
+}
+
+type panickingValidator struct{}
+
+func (panickingValidator) Validate(context.Context, *entities.Price, *entities.Price) error {
+	panic("unexpected tick")
+}
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/logging"
 	"context"
 	"fmt"
@@ -9,6 +10,13 @@ import (
 	"time"
 )
 
+const (
+	// mockTickerChannel es el Channel de los precios del mock
+	mockTickerChannel = "ticker"
+	// mockSpread es la diferencia relativa entre el ask y el bid simulados
+	mockSpread = 0.0002
+)
+
 // MockExchange implementa la interfaz Exchange para testing y development
 // Retorna precios falsos pero realistas para facilitar el desarrollo
 type MockExchange struct {
@@ -57,6 +65,11 @@ func (m *MockExchange) GetTicker(ctx context.Context, pair string) (*entities.Pr
 		time.Now().Add(-age), // Timestamp en el pasado para simular age
 		age,
 	)
+	price.Source = entities.PriceSourceMock
+	price.Exchange = entities.ExchangeMock
+	price.Channel = mockTickerChannel
+	price.Bid = currentPrice * (1 - mockSpread/2)
+	price.Ask = currentPrice * (1 + mockSpread/2)
 
 	logging.Debug(ctx, "MockExchange: Generated mock price", logging.Fields{
 		"pair":          pair,
@@ -126,3 +139,37 @@ func (m *MockExchange) GetSupportedPairs() []string {
 	}
 	return pairs
 }
+
+// Close implementa interfaces.Closer; el mock no mantiene conexiones
+func (m *MockExchange) Close() error {
+	return nil
+}
+
+// Reconnect implementa interfaces.Reconnectable; el mock siempre está "conectado"
+func (m *MockExchange) Reconnect(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// IsConnected implementa interfaces.Reconnectable
+func (m *MockExchange) IsConnected() bool {
+	return true
+}
+
+// PairMetadata implementa interfaces.PairCatalog; el mock no tiene stream ni decimales
+func (m *MockExchange) PairMetadata(pairs []string) []interfaces.PairMetadata {
+	metadata := make([]interfaces.PairMetadata, len(pairs))
+	for i, pair := range pairs {
+		base, quote, _ := entities.SplitPair(pair)
+		metadata[i] = interfaces.PairMetadata{Pair: pair, Base: base, Quote: quote, Exchange: entities.ExchangeMock}
+	}
+	return metadata
+}
+
+// ResolvePair implementa interfaces.PairResolver: acepta los pares con precio base
+func (m *MockExchange) ResolvePair(_ context.Context, pair string) (string, error) {
+	canonical := entities.CanonicalPair(pair)
+	if _, exists := m.basePrices[canonical]; !exists {
+		return "", fmt.Errorf("%w: %s", interfaces.ErrPairNotListed, canonical)
+	}
+	return canonical, nil
+}
@@ -0,0 +1,48 @@
+package exchange
+
+import "sync"
+
+// prunedPairs es el conjunto de pares desuscriptos por inactividad; el valor
+// cero es utilizable
+type prunedPairs struct {
+	mu    sync.RWMutex
+	pairs map[string]bool
+}
+
+func (p *prunedPairs) add(pairs ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pairs == nil {
+		p.pairs = make(map[string]bool, len(pairs))
+	}
+	for _, pair := range pairs {
+		p.pairs[pair] = true
+	}
+}
+
+// remove quita los pares del conjunto y retorna los que estaban podados
+func (p *prunedPairs) remove(pairs ...string) []string {
+	p.mu.RLock()
+	empty := len(p.pairs) == 0
+	p.mu.RUnlock()
+	if empty {
+		return nil // caso habitual: nada podado, sin tomar el lock de escritura
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var removed []string
+	for _, pair := range pairs {
+		if p.pairs[pair] {
+			delete(p.pairs, pair)
+			removed = append(removed, pair)
+		}
+	}
+	return removed
+}
+
+func (p *prunedPairs) contains(pair string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pairs[pair]
+}
@@ -0,0 +1,161 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// healthAlpha es el peso de cada observación en los promedios móviles exponenciales
+	healthAlpha = 0.2
+	// healthMinSamples son las observaciones del WebSocket necesarias antes de poder
+	// preferir REST, y las que tiene para recuperarse tras un cool-down
+	healthMinSamples = 5
+	// healthSwitchBelow es el puntaje del WebSocket por debajo del cual se prefiere REST
+	// si éste puntúa mejor
+	healthSwitchBelow = 0.5
+)
+
+// healthScore es el promedio móvil de errores y latencia de una fuente
+type healthScore struct {
+	errorRate float64 // 0 a 1
+	latency   float64 // Segundos
+	samples   int
+}
+
+// score combina errores y latencia en un puntaje de 0 (caída) a 1 (sana). Una
+// latencia igual a reference resta la mitad del puntaje.
+func (s healthScore) score(reference time.Duration) float64 {
+	latencyPenalty := 0.0
+	if reference > 0 {
+		latencyPenalty = min(1, s.latency/reference.Seconds()) / 2
+	}
+	return (1 - s.errorRate) * (1 - latencyPenalty)
+}
+
+// sourceHealth puntúa WebSocket y REST según sus errores y latencia recientes y
+// decide qué fuente consulta primero el FallbackExchange. El WebSocket es la fuente
+// preferida; cuando su puntaje cae por debajo de healthSwitchBelow y REST puntúa
+// mejor, REST pasa a consultarse primero durante cooldown. Al vencer, el WebSocket
+// vuelve a consultarse primero con el puntaje reiniciado para que pruebe su
+// recuperación.
+type sourceHealth struct {
+	cooldown  time.Duration
+	reference time.Duration // Latencia que resta la mitad del puntaje
+
+	mu        sync.Mutex
+	scores    map[string]*healthScore
+	preferred string
+	until     time.Time
+}
+
+// newSourceHealth crea el puntaje de fuentes; cooldown no positivo retorna nil, que
+// mantiene siempre al WebSocket como primera fuente
+func newSourceHealth(cooldown, reference time.Duration) *sourceHealth {
+	if cooldown <= 0 {
+		return nil
+	}
+	metrics.SetPreferredSource(SourceWebSocket, true)
+	metrics.SetPreferredSource(SourceREST, false)
+	return &sourceHealth{
+		cooldown:  cooldown,
+		reference: reference,
+		scores: map[string]*healthScore{
+			SourceWebSocket: {},
+			SourceREST:      {},
+		},
+		preferred: SourceWebSocket,
+	}
+}
+
+// observe registra el resultado de una consulta a source que tardó latency
+func (h *sourceHealth) observe(source string, latency time.Duration, err error) {
+	if h == nil {
+		return
+	}
+	failed := 0.0
+	if err != nil {
+		failed = 1
+	}
+
+	h.mu.Lock()
+	s := h.scores[source]
+	if s.samples == 0 {
+		s.errorRate, s.latency = failed, latency.Seconds()
+	} else {
+		s.errorRate += healthAlpha * (failed - s.errorRate)
+		s.latency += healthAlpha * (latency.Seconds() - s.latency)
+	}
+	s.samples++
+	score := s.score(h.reference)
+	h.mu.Unlock()
+
+	metrics.UpdateSourceHealthScore(source, score)
+}
+
+// preferREST indica si la consulta en now debe ir primero a REST
+func (h *sourceHealth) preferREST(ctx context.Context, now time.Time) bool {
+	if h == nil {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ws, rest := h.scores[SourceWebSocket], h.scores[SourceREST]
+	if h.preferred == SourceREST {
+		if now.Before(h.until) {
+			return true
+		}
+		// Vencido el cool-down el WebSocket tiene healthMinSamples consultas para recuperarse
+		*ws = healthScore{}
+		h.setPreferred(SourceWebSocket)
+		logging.Info(ctx, "Cool-down over, trying WebSocket first again", logging.Fields{
+			"rest_score": rest.score(h.reference),
+		})
+		return false
+	}
+
+	wsScore, restScore := ws.score(h.reference), rest.score(h.reference)
+	if ws.samples < healthMinSamples || rest.samples == 0 || wsScore >= healthSwitchBelow || restScore <= wsScore {
+		return false
+	}
+	h.setPreferred(SourceREST)
+	h.until = now.Add(h.cooldown)
+	logging.Warn(ctx, "WebSocket unhealthy, trying REST first", logging.Fields{
+		"websocket_score":      wsScore,
+		"websocket_error_rate": ws.errorRate,
+		"websocket_latency_ms": ws.latency * 1000,
+		"rest_score":           restScore,
+		"cooldown":             h.cooldown.String(),
+	})
+	return true
+}
+
+// tripWebSocket pasa a consultar REST primero durante cooldown sin esperar al
+// puntaje; el FallbackExchange lo usa cuando se agota el presupuesto de
+// reintentos del WebSocket, señal de una falla sostenida
+func (h *sourceHealth) tripWebSocket(ctx context.Context, now time.Time) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.preferred == SourceREST {
+		return
+	}
+	h.setPreferred(SourceREST)
+	h.until = now.Add(h.cooldown)
+	logging.Warn(ctx, "WebSocket retry budget exhausted, trying REST first", logging.Fields{
+		"websocket_score": h.scores[SourceWebSocket].score(h.reference),
+		"cooldown":        h.cooldown.String(),
+	})
+}
+
+func (h *sourceHealth) setPreferred(source string) {
+	h.preferred = source
+	metrics.SetPreferredSource(SourceWebSocket, source == SourceWebSocket)
+	metrics.SetPreferredSource(SourceREST, source == SourceREST)
+}
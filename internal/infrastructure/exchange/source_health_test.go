@@ -0,0 +1,103 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/exchange/kraken"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthScore_Score(t *testing.T) {
+	assert.Equal(t, 1.0, healthScore{}.score(time.Second))
+	assert.Equal(t, 0.0, healthScore{errorRate: 1}.score(time.Second))
+	assert.Equal(t, 0.5, healthScore{latency: 1}.score(time.Second), "a latency equal to the reference halves the score")
+	assert.Equal(t, 0.5, healthScore{latency: 10}.score(time.Second), "the latency penalty is capped")
+}
+
+func TestSourceHealth_PrefersRESTDuringCooldown(t *testing.T) {
+	ctx := context.Background()
+	health := newSourceHealth(time.Minute, time.Second)
+	now := time.Now()
+
+	health.observe(SourceREST, 100*time.Millisecond, nil)
+	for i := 0; i < healthMinSamples-1; i++ {
+		health.observe(SourceWebSocket, time.Second, errors.New("timeout"))
+	}
+	assert.False(t, health.preferREST(ctx, now), "too few WebSocket samples to switch")
+
+	health.observe(SourceWebSocket, time.Second, errors.New("timeout"))
+	assert.True(t, health.preferREST(ctx, now))
+	assert.True(t, health.preferREST(ctx, now.Add(59*time.Second)))
+
+	// Vencido el cool-down el WebSocket vuelve a ser primero con el puntaje reiniciado
+	assert.False(t, health.preferREST(ctx, now.Add(time.Minute)))
+	assert.Equal(t, 0, health.scores[SourceWebSocket].samples)
+	assert.False(t, health.preferREST(ctx, now.Add(time.Minute)))
+}
+
+func TestSourceHealth_KeepsWebSocketWhenRESTIsWorse(t *testing.T) {
+	ctx := context.Background()
+	health := newSourceHealth(time.Minute, time.Second)
+	for i := 0; i < healthMinSamples; i++ {
+		health.observe(SourceWebSocket, time.Second, errors.New("timeout"))
+		health.observe(SourceREST, time.Second, errors.New("HTTP 503"))
+	}
+	assert.False(t, health.preferREST(ctx, time.Now()))
+}
+
+func TestSourceHealth_DisabledWithoutCooldown(t *testing.T) {
+	health := newSourceHealth(0, time.Second)
+	assert.Nil(t, health)
+	health.observe(SourceWebSocket, time.Second, errors.New("timeout"))
+	assert.False(t, health.preferREST(context.Background(), time.Now()))
+}
+
+func TestFallbackExchange_GetTickerRESTFirst(t *testing.T) {
+	cfg := config.KrakenConfig{FallbackTimeout: 100 * time.Millisecond, MaxRetries: 1}
+	exchange := &FallbackExchange{
+		primary:    kraken.NewShardedWebSocketClient(cfg),
+		secondary:  NewMockExchange(),
+		config:     cfg,
+		divergence: NewDivergenceMonitor(0, 0),
+		history:    newFallbackHistory(fallbackHistorySize),
+		health:     newSourceHealth(time.Minute, cfg.FallbackTimeout),
+	}
+	exchange.health.setPreferred(SourceREST)
+	exchange.health.until = time.Now().Add(time.Minute)
+
+	price, err := exchange.GetTicker(context.Background(), "BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USD", price.Pair)
+	assert.Empty(t, exchange.RecentFallbacks(), "querying the preferred REST source is not a fallback")
+	assert.Equal(t, 1, exchange.health.scores[SourceREST].samples)
+	assert.Equal(t, 0, exchange.health.scores[SourceWebSocket].samples)
+}
+
+func TestFallbackExchange_RetryBudgetTripsToREST(t *testing.T) {
+	cfg := config.KrakenConfig{FallbackTimeout: 100 * time.Millisecond, MaxRetries: 3}
+	exchange := &FallbackExchange{
+		config:  cfg,
+		health:  newSourceHealth(time.Minute, cfg.FallbackTimeout),
+		retries: kraken.NewRetryBudget(SourceWebSocket, config.RetryBudgetConfig{Enabled: true, MaxTokens: 4}),
+	}
+	calls := 0
+	failing := func(context.Context) (*entities.Price, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	}
+
+	_, attempts, err := exchange.tryWebSocketSingle(context.Background(), "BTC/USD", failing)
+	require.Error(t, err)
+	assert.Equal(t, 2, attempts, "the second failure leaves half of the budget")
+	assert.True(t, exchange.health.preferREST(context.Background(), time.Now()), "an exhausted budget switches to REST without waiting for the score")
+
+	_, attempts, _ = exchange.tryWebSocketSingle(context.Background(), "BTC/USD", failing)
+	assert.Equal(t, 1, attempts, "no retries while the budget is exhausted")
+	assert.Equal(t, 3, calls)
+}
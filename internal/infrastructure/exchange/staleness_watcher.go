@@ -0,0 +1,189 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	cachepkg "btc-ltp-service/internal/infrastructure/repositories/cache"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStalenessInterval es la frecuencia por defecto de verificación de frescura
+	DefaultStalenessInterval = 20 * time.Second
+	// DefaultStalenessMaxAge es la edad máxima por defecto antes de refrescar vía REST
+	DefaultStalenessMaxAge = 60 * time.Second
+)
+
+// priceStore abstrae el almacenamiento de precios usado por el watcher
+type priceStore interface {
+	Get(ctx context.Context, pair string) (*entities.Price, bool)
+	Set(ctx context.Context, price *entities.Price) error
+}
+
+// StalenessWatcher verifica periódicamente la edad de los precios cacheados
+// y los refresca vía REST cuando superan maxAge.
+type StalenessWatcher struct {
+	store    priceStore
+	source   interfaces.Exchange
+	pairs    []string
+	interval time.Duration
+	maxAge   time.Duration
+	timeout  time.Duration
+	isLeader func() bool
+	isPaused func(pair string) bool // pares que no se refrescan (p. ej. podados por inactividad)
+
+	// Durante una ventana de mantenimiento los refrescos se espacian a maintenanceInterval
+	inMaintenance       func() bool
+	maintenanceInterval time.Duration
+	lastCheck           time.Time
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+// NewStalenessWatcher crea un watcher; valores no positivos usan los defaults
+func NewStalenessWatcher(store priceStore, source interfaces.Exchange, pairs []string, interval, maxAge time.Duration) *StalenessWatcher {
+	if interval <= 0 {
+		interval = DefaultStalenessInterval
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultStalenessMaxAge
+	}
+
+	return &StalenessWatcher{
+		store:    store,
+		source:   source,
+		pairs:    append([]string(nil), pairs...),
+		interval: interval,
+		maxAge:   maxAge,
+		timeout:  10 * time.Second,
+		isLeader: func() bool { return true },
+		isPaused: func(string) bool { return false },
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+
+		inMaintenance:       func() bool { return false },
+		maintenanceInterval: interval,
+	}
+}
+
+// SetLeaderCheck restringe los refrescos a la réplica líder
+func (w *StalenessWatcher) SetLeaderCheck(isLeader func() bool) {
+	if isLeader != nil {
+		w.isLeader = isLeader
+	}
+}
+
+// SetPausedCheck excluye de los refrescos los pares para los que isPaused retorna true
+func (w *StalenessWatcher) SetPausedCheck(isPaused func(pair string) bool) {
+	if isPaused != nil {
+		w.isPaused = isPaused
+	}
+}
+
+// SetMaintenanceCheck espacia los refrescos a interval y baja a debug los avisos
+// de fallos REST mientras inMaintenance reporte una ventana activa
+func (w *StalenessWatcher) SetMaintenanceCheck(inMaintenance func() bool, interval time.Duration) {
+	if inMaintenance != nil {
+		w.inMaintenance = inMaintenance
+	}
+	if interval > w.maintenanceInterval {
+		w.maintenanceInterval = interval
+	}
+}
+
+// Start lanza la goroutine del watcher (idempotente)
+func (w *StalenessWatcher) Start() {
+	w.startOnce.Do(func() {
+		go w.run()
+		logging.Info(context.Background(), "Staleness watcher started", logging.Fields{
+			"pairs":    w.pairs,
+			"interval": w.interval.String(),
+			"max_age":  w.maxAge.String(),
+		})
+	})
+}
+
+// Stop detiene el watcher y espera a que la goroutine termine (idempotente)
+func (w *StalenessWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		started := true
+		w.startOnce.Do(func() { started = false })
+		if started {
+			<-w.doneCh
+		}
+	})
+}
+
+func (w *StalenessWatcher) run() {
+	defer close(w.doneCh)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case now := <-ticker.C:
+			if !w.isLeader() {
+				continue // sólo la réplica líder refresca vía REST
+			}
+			if w.inMaintenance() && now.Sub(w.lastCheck) < w.maintenanceInterval {
+				continue // ventana de mantenimiento del exchange: refrescos espaciados
+			}
+			w.check()
+			w.lastCheck = now
+		}
+	}
+}
+
+// check refresca vía REST los pares cuyo precio cacheado está vencido o ausente
+func (w *StalenessWatcher) check() {
+	// Un request_id por chequeo asocia sus consultas REST en los logs
+	ctx, cancel := context.WithTimeout(logging.WithRequestID(context.Background(), logging.GenerateRequestID()), w.timeout)
+	defer cancel()
+
+	// En mantenimiento los fallos REST son esperables: no alertar
+	warn := logging.Warn
+	if w.inMaintenance() {
+		warn = logging.Debug
+	}
+
+	for _, pair := range w.pairs {
+		if w.isPaused(pair) {
+			continue // nadie lo consulta: se refresca cuando se vuelva a pedir
+		}
+		price, ok := w.store.Get(ctx, pair)
+		if ok && time.Since(price.Timestamp) <= w.maxAge {
+			continue // todavía fresco
+		}
+
+		p, err := w.source.GetTicker(ctx, pair)
+		if err != nil {
+			metrics.RecordStalenessRefresh(pair, "error")
+			warn(ctx, "Staleness watcher REST fetch failed", logging.Fields{"pair": pair, "error": err.Error()})
+			continue
+		}
+		if err := w.store.Set(ctx, p); err != nil {
+			if errors.Is(err, cachepkg.ErrStaleWrite) {
+				// El WebSocket entregó un precio más nuevo mientras se consultaba REST
+				metrics.RecordStalenessRefresh(pair, "superseded")
+				continue
+			}
+			metrics.RecordStalenessRefresh(pair, "error")
+			warn(ctx, "Staleness watcher failed to cache price", logging.Fields{"pair": pair, "error": err.Error()})
+			continue
+		}
+		metrics.RecordStalenessRefresh(pair, "success")
+		logging.Debug(ctx, "Staleness watcher refreshed price", logging.Fields{"pair": pair})
+	}
+}
@@ -0,0 +1,110 @@
+package exchange
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePriceStore implementa priceStore en memoria para tests
+type fakePriceStore struct {
+	mu     sync.Mutex
+	prices map[string]*entities.Price
+	sets   int
+}
+
+func newFakePriceStore() *fakePriceStore {
+	return &fakePriceStore{prices: make(map[string]*entities.Price)}
+}
+
+func (s *fakePriceStore) Get(_ context.Context, pair string) (*entities.Price, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.prices[pair]
+	return p, ok
+}
+
+func (s *fakePriceStore) Set(_ context.Context, price *entities.Price) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[price.Pair] = price
+	s.sets++
+	return nil
+}
+
+func (s *fakePriceStore) setCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sets
+}
+
+func TestNewStalenessWatcher_Defaults(t *testing.T) {
+	w := NewStalenessWatcher(newFakePriceStore(), NewMockExchange(), []string{"BTC/USD"}, 0, 0)
+
+	assert.Equal(t, DefaultStalenessInterval, w.interval)
+	assert.Equal(t, DefaultStalenessMaxAge, w.maxAge)
+}
+
+func TestStalenessWatcher_RefreshesStaleAndMissingPairs(t *testing.T) {
+	store := newFakePriceStore()
+	fresh := entities.NewPrice("ETH/USD", 3000, time.Now(), 0)
+	_ = store.Set(context.Background(), fresh)
+
+	w := NewStalenessWatcher(store, NewMockExchange(), []string{"BTC/USD", "ETH/USD"}, time.Hour, time.Minute)
+	w.check()
+
+	_, ok := store.Get(context.Background(), "BTC/USD")
+	assert.True(t, ok, "missing pair should be refreshed")
+	got, _ := store.Get(context.Background(), "ETH/USD")
+	assert.Same(t, fresh, got, "fresh pair should not be refreshed")
+}
+
+func TestStalenessWatcher_StartStop(t *testing.T) {
+	store := newFakePriceStore()
+	w := NewStalenessWatcher(store, NewMockExchange(), []string{"BTC/USD"}, 10*time.Millisecond, time.Millisecond)
+
+	w.Start()
+	w.Start() // idempotente
+	assert.Eventually(t, func() bool { return store.setCount() > 0 }, time.Second, 5*time.Millisecond)
+
+	w.Stop()
+	w.Stop() // idempotente
+	count := store.setCount()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, count, store.setCount(), "no refreshes after Stop")
+}
+
+func TestStalenessWatcher_StopWithoutStart(t *testing.T) {
+	w := NewStalenessWatcher(newFakePriceStore(), NewMockExchange(), nil, 0, 0)
+	w.Stop()
+	w.Start() // no debe arrancar después de Stop
+}
+
+func TestStalenessWatcher_SkipsWhenNotLeader(t *testing.T) {
+	store := newFakePriceStore()
+	w := NewStalenessWatcher(store, NewMockExchange(), []string{"BTC/USD"}, 10*time.Millisecond, time.Millisecond)
+	w.SetLeaderCheck(func() bool { return false })
+
+	w.Start()
+	time.Sleep(40 * time.Millisecond)
+	w.Stop()
+
+	assert.Equal(t, 0, store.setCount())
+}
+
+func TestStalenessWatcher_SpacesRefreshesDuringMaintenance(t *testing.T) {
+	store := newFakePriceStore()
+	w := NewStalenessWatcher(store, NewMockExchange(), []string{"BTC/USD"}, 10*time.Millisecond, time.Nanosecond)
+	w.SetMaintenanceCheck(func() bool { return true }, time.Hour)
+
+	w.Start()
+	assert.Eventually(t, func() bool { return store.setCount() > 0 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	w.Stop()
+
+	assert.Equal(t, 1, store.setCount(), "only one refresh per maintenance refresh interval")
+}
@@ -0,0 +1,118 @@
+package leader
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isRedisAvailable checks if Redis is available on localhost:6379
+func isRedisAvailable() bool {
+	if os.Getenv("CI") != "" {
+		return false
+	}
+
+	conn, err := net.DialTimeout("tcp", "localhost:6379", 100*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+func TestStaticElector_AlwaysLeader(t *testing.T) {
+	elector := NewStaticElector()
+	require.NoError(t, elector.Start(context.Background()))
+	assert.True(t, elector.IsLeader())
+	assert.NoError(t, elector.Stop())
+}
+
+func TestNewRedisElector_Defaults(t *testing.T) {
+	elector := NewRedisElector(redis.NewClient(&redis.Options{Addr: "localhost:0"}), "", "", 0, 0)
+
+	assert.Equal(t, DefaultLockKey, elector.key)
+	assert.Equal(t, DefaultLeaseTTL, elector.leaseTTL)
+	assert.Equal(t, DefaultLeaseTTL/3, elector.renewInterval)
+	assert.NotEmpty(t, elector.InstanceID())
+	assert.False(t, elector.IsLeader())
+}
+
+func TestRedisElector_UnreachableRedis_NeverLeader(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond})
+	elector := NewRedisElector(client, "test:leader", "a", time.Second, 100*time.Millisecond)
+
+	require.NoError(t, elector.Start(context.Background()))
+	time.Sleep(150 * time.Millisecond)
+	assert.False(t, elector.IsLeader())
+	assert.NoError(t, elector.Stop())
+	// Stop es idempotente
+	assert.NoError(t, elector.Stop())
+}
+
+func TestRedisElector_Failover(t *testing.T) {
+	if !isRedisAvailable() {
+		t.Skip("Redis not available, skipping leader election integration test")
+	}
+
+	ctx := context.Background()
+	key := "test:leader:failover"
+	clientA := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	clientB := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	_ = clientA.Del(ctx, key).Err()
+
+	a := NewRedisElector(clientA, key, "a", time.Second, 200*time.Millisecond)
+	b := NewRedisElector(clientB, key, "b", time.Second, 200*time.Millisecond)
+
+	require.NoError(t, a.Start(ctx))
+	require.NoError(t, b.Start(ctx))
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// Al liberar el lock, la otra réplica toma el liderazgo
+	require.NoError(t, a.Stop())
+	assert.Eventually(t, b.IsLeader, 2*time.Second, 50*time.Millisecond)
+	require.NoError(t, b.Stop())
+}
+
+func TestRedisElector_RenewFailsOnceThenRecovers(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	// TTL largo: la recuperación no puede depender de que el lock expire
+	elector := NewRedisElector(client, "test:leader:recover", "a", time.Minute, 20*time.Millisecond)
+
+	require.NoError(t, elector.Start(context.Background()))
+	defer elector.Stop()
+	require.True(t, elector.IsLeader())
+
+	// La renovación falla: abdica, pero el lock sigue a su nombre
+	server.SetError("LOADING redis is loading the dataset in memory")
+	assert.Eventually(t, func() bool { return !elector.IsLeader() }, time.Second, 10*time.Millisecond)
+	server.SetError("")
+
+	owner, err := server.Get("test:leader:recover")
+	require.NoError(t, err)
+	assert.Equal(t, "a", owner)
+	assert.Eventually(t, elector.IsLeader, time.Second, 10*time.Millisecond)
+	assert.Greater(t, server.TTL("test:leader:recover"), 50*time.Second)
+}
+
+func TestRedisElector_DoesNotAdoptForeignLock(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	require.NoError(t, server.Set("test:leader:foreign", "b"))
+	server.SetTTL("test:leader:foreign", time.Minute)
+
+	elector := NewRedisElector(client, "test:leader:foreign", "a", time.Minute, 20*time.Millisecond)
+	require.NoError(t, elector.Start(context.Background()))
+	defer elector.Stop()
+
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, elector.IsLeader())
+}
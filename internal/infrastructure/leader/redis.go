@@ -0,0 +1,196 @@
+package leader
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultLockKey       = "btc-ltp:leader"
+	DefaultLeaseTTL      = 15 * time.Second
+	DefaultRenewInterval = 5 * time.Second
+)
+
+// renewScript extiende el TTL sólo si el lock sigue perteneciendo a esta instancia
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript elimina el lock sólo si pertenece a esta instancia
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisElector implementa LeaderElector con un lock en Redis (SET NX PX) renovado
+// periódicamente. Si el líder muere el lock expira tras LeaseTTL y otra réplica
+// lo adquiere automáticamente (failover).
+type RedisElector struct {
+	client        *redis.Client
+	key           string
+	instanceID    string
+	leaseTTL      time.Duration
+	renewInterval time.Duration
+
+	isLeader atomic.Bool
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewRedisElector crea un elector basado en Redis
+func NewRedisElector(client *redis.Client, key, instanceID string, leaseTTL, renewInterval time.Duration) *RedisElector {
+	if key == "" {
+		key = DefaultLockKey
+	}
+	if instanceID == "" {
+		instanceID = DefaultInstanceID()
+	}
+	if leaseTTL <= 0 {
+		leaseTTL = DefaultLeaseTTL
+	}
+	if renewInterval <= 0 || renewInterval >= leaseTTL {
+		renewInterval = leaseTTL / 3
+	}
+
+	return &RedisElector{
+		client:        client,
+		key:           key,
+		instanceID:    instanceID,
+		leaseTTL:      leaseTTL,
+		renewInterval: renewInterval,
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// DefaultInstanceID genera un identificador único por proceso (hostname-pid)
+func DefaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Start intenta adquirir el liderazgo inmediatamente y luego en cada intervalo de renovación
+func (e *RedisElector) Start(ctx context.Context) error {
+	metrics.UpdateLeaderStatus(false)
+	e.tick(ctx)
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(e.renewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.tick(ctx)
+			case <-e.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	logging.Info(ctx, "Leader election started", logging.Fields{
+		"backend":        "redis",
+		"lock_key":       e.key,
+		"instance_id":    e.instanceID,
+		"lease_ttl":      e.leaseTTL.String(),
+		"renew_interval": e.renewInterval.String(),
+	})
+	return nil
+}
+
+// tick adquiere o renueva el lock según el estado actual
+func (e *RedisElector) tick(ctx context.Context) {
+	opCtx, cancel := context.WithTimeout(ctx, e.renewInterval)
+	defer cancel()
+
+	if e.isLeader.Load() {
+		res, err := renewScript.Run(opCtx, e.client, []string{e.key}, e.instanceID, e.leaseTTL.Milliseconds()).Int()
+		if err != nil || res == 0 {
+			// Sin confirmación de renovación no podemos garantizar exclusividad: abdicar
+			fields := logging.Fields{"instance_id": e.instanceID, "lock_key": e.key}
+			if err != nil {
+				fields["error"] = err.Error()
+			}
+			logging.Warn(ctx, "Lost leadership", fields)
+			e.setLeader(false)
+		}
+		return
+	}
+
+	acquired, err := e.client.SetNX(opCtx, e.key, e.instanceID, e.leaseTTL).Result()
+	if err != nil {
+		logging.Debug(ctx, "Leader lock acquisition failed", logging.Fields{
+			"instance_id": e.instanceID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	if !acquired {
+		// Una renovación fallida (error transitorio) abdica sin borrar el lock: si la
+		// clave sigue siendo nuestra la retomamos en lugar de esperar a que expire
+		res, err := renewScript.Run(opCtx, e.client, []string{e.key}, e.instanceID, e.leaseTTL.Milliseconds()).Int()
+		acquired = err == nil && res == 1
+	}
+	if acquired {
+		logging.Info(ctx, "Acquired leadership", logging.Fields{
+			"instance_id": e.instanceID,
+			"lock_key":    e.key,
+		})
+		e.setLeader(true)
+	}
+}
+
+func (e *RedisElector) setLeader(leader bool) {
+	if e.isLeader.Swap(leader) != leader {
+		metrics.RecordLeaderTransition(leader)
+	}
+	metrics.UpdateLeaderStatus(leader)
+}
+
+// IsLeader indica si esta instancia posee el lock
+func (e *RedisElector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+// Stop detiene la renovación y libera el lock para acelerar el failover
+func (e *RedisElector) Stop() error {
+	var err error
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+		e.wg.Wait()
+
+		if e.isLeader.Load() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err = releaseScript.Run(ctx, e.client, []string{e.key}, e.instanceID).Err()
+			e.setLeader(false)
+		}
+	})
+	return err
+}
+
+// InstanceID retorna el identificador de esta instancia en la elección
+func (e *RedisElector) InstanceID() string {
+	return e.instanceID
+}
+
+var _ interfaces.LeaderElector = (*RedisElector)(nil)
@@ -0,0 +1,32 @@
+package leader
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+)
+
+// StaticElector es un LeaderElector que siempre reporta liderazgo.
+// Se usa cuando la elección está deshabilitada (despliegues de una sola réplica).
+type StaticElector struct{}
+
+// NewStaticElector crea un elector que siempre es líder
+func NewStaticElector() interfaces.LeaderElector {
+	return &StaticElector{}
+}
+
+// Start marca la instancia como líder
+func (s *StaticElector) Start(ctx context.Context) error {
+	metrics.UpdateLeaderStatus(true)
+	return nil
+}
+
+// IsLeader siempre retorna true
+func (s *StaticElector) IsLeader() bool {
+	return true
+}
+
+// Stop no requiere liberar recursos
+func (s *StaticElector) Stop() error {
+	return nil
+}
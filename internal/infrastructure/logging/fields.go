@@ -25,6 +25,7 @@ const (
 	FieldLevel      = "level"
 	FieldMessage    = "message"
 	FieldRequestID  = "request_id"
+	FieldTraceID    = "trace_id"
 	FieldService    = "service"
 	FieldVersion    = "version"
 	FieldDomain     = "domain"
@@ -192,6 +193,7 @@ type contextKey string
 
 const (
 	RequestIDKey contextKey = "request_id"
+	TraceIDKey   contextKey = "trace_id"
 	StartTimeKey contextKey = "start_time"
 	UserAgentKey contextKey = "user_agent"
 	RemoteIPKey  contextKey = "remote_ip"
@@ -202,6 +204,11 @@ func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, RequestIDKey, requestID)
 }
 
+// WithTraceID asocia el trace ID W3C (traceparent) de la request a ctx
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, TraceIDKey, traceID)
+}
+
 func WithStartTime(ctx context.Context, startTime time.Time) context.Context {
 	return context.WithValue(ctx, StartTimeKey, startTime)
 }
@@ -221,6 +228,13 @@ func GetRequestID(ctx context.Context) string {
 	return ""
 }
 
+func GetTraceID(ctx context.Context) string {
+	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
+		return traceID
+	}
+	return ""
+}
+
 func GetStartTime(ctx context.Context) time.Time {
 	if startTime, ok := ctx.Value(StartTimeKey).(time.Time); ok {
 		return startTime
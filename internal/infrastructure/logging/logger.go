@@ -22,6 +22,7 @@ type LogEntry struct {
 	Level       LogLevel `json:"level"`
 	Message     string   `json:"message"`
 	RequestID   string   `json:"request_id,omitempty"`
+	TraceID     string   `json:"trace_id,omitempty"`
 	Service     string   `json:"service"`
 	Version     string   `json:"version,omitempty"`
 	Environment string   `json:"environment,omitempty"`
@@ -89,6 +90,7 @@ func (sl *StructuredLogger) createLogEntry(ctx context.Context, level LogLevel,
 		Version:     sl.config.Version,
 		Environment: sl.config.Environment,
 		RequestID:   GetRequestID(ctx),
+		TraceID:     GetTraceID(ctx),
 		Fields:      fields,
 	}
 
@@ -130,6 +132,10 @@ func (sl *StructuredLogger) formatText(entry *LogEntry) string {
 		parts = append(parts, fmt.Sprintf("req:%s", entry.RequestID))
 	}
 
+	if entry.TraceID != "" {
+		parts = append(parts, fmt.Sprintf("trace:%s", entry.TraceID))
+	}
+
 	if entry.Domain != "" {
 		parts = append(parts, fmt.Sprintf("domain:%s", entry.Domain))
 	}
@@ -4,9 +4,13 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// maxRequestIDLength acota los X-Request-ID recibidos de clientes o proxies
+const maxRequestIDLength = 128
+
 // RequestIDGenerator generates unique request IDs
 type RequestIDGenerator struct {
 	prefix string
@@ -61,3 +65,51 @@ func GenerateRequestID() string {
 func GenerateShortRequestID() string {
 	return defaultGenerator.GenerateShort()
 }
+
+// ValidRequestID indica si un X-Request-ID recibido puede reutilizarse: no vacío,
+// de hasta 128 caracteres y sólo letras, dígitos y "-_.:/", para que no pueda
+// inyectar saltos de línea ni romper el formato de los logs
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-_.:/", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTraceparent extrae el trace ID de un header W3C traceparent
+// (version-traceid-parentid-flags); false si el header es inválido
+func ParseTraceparent(header string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || strings.EqualFold(parts[0], "ff") {
+		return "", false
+	}
+	traceID, parentID := strings.ToLower(parts[1]), strings.ToLower(parts[2])
+	// La versión 00 tiene exactamente cuatro campos; versiones futuras pueden agregar más
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", false
+	}
+	if !isHex(traceID, 32) || !isHex(parentID, 16) || !isHex(parts[3], 2) {
+		return "", false
+	}
+	// Los IDs todo ceros son inválidos según la especificación
+	if strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return "", false
+	}
+	return traceID, true
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ApplicationMetrics cubre la información del proceso
+type ApplicationMetrics struct {
+	Info          *prometheus.GaugeVec
+	UptimeSeconds prometheus.Gauge
+
+	// Límites del runtime aplicados al arrancar y los detectados en el cgroup
+	GoMaxProcs        prometheus.Gauge
+	MemoryLimitBytes  prometheus.Gauge
+	CgroupCPUQuota    prometheus.Gauge
+	CgroupMemoryLimit prometheus.Gauge
+}
+
+func init() {
+	registerModule(GroupApplication, func(m *Metrics, factory promauto.Factory) {
+		m.Application = newApplicationMetrics(factory)
+	})
+}
+
+func newApplicationMetrics(factory promauto.Factory) *ApplicationMetrics {
+	return &ApplicationMetrics{
+		Info: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_application_info",
+				Help: "Application information",
+			},
+			[]string{"version", "build_time", "go_version"},
+		),
+		UptimeSeconds: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_uptime_seconds",
+				Help: "Application uptime in seconds",
+			},
+		),
+		GoMaxProcs: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_runtime_gomaxprocs",
+				Help: "GOMAXPROCS applied at startup",
+			},
+		),
+		MemoryLimitBytes: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_runtime_memory_limit_bytes",
+				Help: "GOMEMLIMIT applied at startup (0 without limit)",
+			},
+		),
+		CgroupCPUQuota: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_runtime_cgroup_cpu_quota_cores",
+				Help: "CPU quota of the container cgroup in cores (0 without quota)",
+			},
+		),
+		CgroupMemoryLimit: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_runtime_cgroup_memory_limit_bytes",
+				Help: "Memory limit of the container cgroup (0 without limit)",
+			},
+		),
+	}
+}
+
+// SetInfo sets application information
+func (a *ApplicationMetrics) SetInfo(version, buildTime, goVersion string) {
+	a.Info.WithLabelValues(version, buildTime, goVersion).Set(1)
+}
+
+// UpdateUptime updates application uptime
+func (a *ApplicationMetrics) UpdateUptime(seconds float64) {
+	a.UptimeSeconds.Set(seconds)
+}
+
+// SetRuntimeLimits records the applied GOMAXPROCS/GOMEMLIMIT and the cgroup limits
+func (a *ApplicationMetrics) SetRuntimeLimits(maxProcs int, memoryLimit int64, cpuQuota float64, cgroupMemory int64) {
+	a.GoMaxProcs.Set(float64(maxProcs))
+	a.MemoryLimitBytes.Set(float64(memoryLimit))
+	a.CgroupCPUQuota.Set(cpuQuota)
+	a.CgroupMemoryLimit.Set(float64(cgroupMemory))
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CacheMetrics cubre las operaciones sobre la caché de precios
+type CacheMetrics struct {
+	OperationsTotal     *prometheus.CounterVec
+	Keys                *prometheus.GaugeVec
+	StaleWritesRejected *prometheus.CounterVec
+}
+
+func init() {
+	registerModule(GroupCache, func(m *Metrics, factory promauto.Factory) {
+		m.Cache = newCacheMetrics(factory)
+	})
+}
+
+func newCacheMetrics(factory promauto.Factory) *CacheMetrics {
+	return &CacheMetrics{
+		OperationsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_cache_operations_total",
+				Help: "Total number of cache operations",
+			},
+			[]string{"operation", "result"}, // operation: get/set/delete, result: hit/miss/success/error
+		),
+		Keys: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_cache_keys",
+				Help: "Number of keys currently in cache",
+			},
+			[]string{"cache_type"}, // cache_type: memory/redis
+		),
+		StaleWritesRejected: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_cache_stale_writes_rejected_total",
+				Help: "Total number of cache writes rejected because a newer price was already cached",
+			},
+			[]string{"pair"},
+		),
+	}
+}
+
+// RecordOperation records cache operation metrics
+func (c *CacheMetrics) RecordOperation(operation, result string) {
+	c.OperationsTotal.WithLabelValues(operation, result).Inc()
+}
+
+// RecordStaleWriteRejected records a price write rejected as out of order
+func (c *CacheMetrics) RecordStaleWriteRejected(pair string) {
+	c.StaleWritesRejected.WithLabelValues(pair).Inc()
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ExternalAPIMetrics cubre las llamadas a APIs externas (Kraken REST)
+type ExternalAPIMetrics struct {
+	RequestsTotal         *prometheus.CounterVec
+	RequestDuration       *prometheus.HistogramVec
+	Retries               *prometheus.CounterVec
+	KrakenRateLimitDrops  *prometheus.CounterVec
+	KrakenBackoffDuration *prometheus.HistogramVec
+	KrakenEgressBlocked   *prometheus.CounterVec
+}
+
+func init() {
+	registerModule(GroupExternalAPI, func(m *Metrics, factory promauto.Factory) {
+		m.ExternalAPI = newExternalAPIMetrics(factory)
+	})
+}
+
+func newExternalAPIMetrics(factory promauto.Factory) *ExternalAPIMetrics {
+	return &ExternalAPIMetrics{
+		RequestsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_external_api_requests_total",
+				Help: "Total number of external API requests",
+			},
+			[]string{"service", "endpoint", "status_code"},
+		),
+		RequestDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_external_api_request_duration_seconds",
+				Help:    "External API request duration in seconds",
+				Buckets: []float64{0.1, 0.5, 1.0, 2.0, 5.0, 10.0}, // External APIs can be slower
+			},
+			[]string{"service", "endpoint"},
+		),
+		Retries: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_external_api_retries_total",
+				Help: "Total number of external API retry attempts",
+			},
+			[]string{"service", "endpoint", "attempt"},
+		),
+		KrakenRateLimitDrops: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_kraken_rate_limit_drops_total",
+				Help: "Number of requests dropped due to Kraken rate limiting (429 responses)",
+			},
+			[]string{"endpoint"},
+		),
+		KrakenBackoffDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_kraken_backoff_duration_seconds",
+				Help:    "Duration of backoff delays due to Kraken rate limiting",
+				Buckets: []float64{0.1, 0.2, 0.5, 1.0, 2.0, 5.0, 10.0},
+			},
+			[]string{"endpoint", "attempt"},
+		),
+		KrakenEgressBlocked: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_kraken_egress_blocked_total",
+				Help: "Outbound connections to Kraken refused because no resolved address is in the egress allowlist",
+			},
+			[]string{"host"},
+		),
+	}
+}
+
+// RecordCall records external API call metrics
+func (e *ExternalAPIMetrics) RecordCall(service, endpoint string, statusCode int, duration float64) {
+	e.RequestsTotal.WithLabelValues(service, endpoint, strconv.Itoa(statusCode)).Inc()
+	e.RequestDuration.WithLabelValues(service, endpoint).Observe(duration)
+}
+
+// RecordRetry records external API retry attempts
+func (e *ExternalAPIMetrics) RecordRetry(service, endpoint string, attempt int) {
+	e.Retries.WithLabelValues(service, endpoint, strconv.Itoa(attempt)).Inc()
+}
+
+// RecordKrakenRateLimitDrop records requests dropped due to Kraken 429 responses
+func (e *ExternalAPIMetrics) RecordKrakenRateLimitDrop(endpoint string) {
+	e.KrakenRateLimitDrops.WithLabelValues(endpoint).Inc()
+}
+
+// RecordKrakenEgressBlocked records an outbound connection refused by the egress allowlist
+func (e *ExternalAPIMetrics) RecordKrakenEgressBlocked(host string) {
+	e.KrakenEgressBlocked.WithLabelValues(host).Inc()
+}
+
+// RecordKrakenBackoffDuration records duration of backoff delays
+func (e *ExternalAPIMetrics) RecordKrakenBackoffDuration(endpoint string, attempt int, duration float64) {
+	e.KrakenBackoffDuration.WithLabelValues(endpoint, strconv.Itoa(attempt)).Observe(duration)
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// FallbackMetrics cubre el fallback de WebSocket a REST y los circuit breakers
+type FallbackMetrics struct {
+	ActivationsTotal    *prometheus.CounterVec
+	Duration            *prometheus.HistogramVec
+	CircuitBreakerState *prometheus.GaugeVec
+	SourceHealthScore   *prometheus.GaugeVec
+	PreferredSource     *prometheus.GaugeVec
+	RetryBudgetTokens   *prometheus.GaugeVec
+	RetriesSuppressed   *prometheus.CounterVec
+}
+
+func init() {
+	registerModule(GroupFallback, func(m *Metrics, factory promauto.Factory) {
+		m.Fallback = newFallbackMetrics(factory)
+	})
+}
+
+func newFallbackMetrics(factory promauto.Factory) *FallbackMetrics {
+	return &FallbackMetrics{
+		ActivationsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_fallback_activations_total",
+				Help: "Total number of fallback activations from WebSocket to REST",
+			},
+			[]string{"reason", "pair"}, // reason: budget_exhausted/timeout/connection_closed/connection_error/max_retries/panic/unknown_error
+		),
+		Duration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_fallback_duration_seconds",
+				Help:    "Duration of fallback operations from WebSocket failure to REST success",
+				Buckets: []float64{0.1, 0.25, 0.5, 1.0, 2.0, 5.0, 10.0, 15.0},
+			},
+			[]string{"pair"},
+		),
+		CircuitBreakerState: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_circuit_breaker_state",
+				Help: "Circuit breaker state (0=closed, 1=open, 2=half_open)",
+			},
+			[]string{"service", "endpoint"},
+		),
+		SourceHealthScore: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_exchange_source_health_score",
+				Help: "Rolling health score of each price source from its error rate and latency (0=down, 1=healthy)",
+			},
+			[]string{"source"}, // source: websocket/rest
+		),
+		PreferredSource: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_exchange_preferred_source",
+				Help: "Price source queried first on a cache miss (1=preferred, 0=fallback)",
+			},
+			[]string{"source"},
+		),
+		RetryBudgetTokens: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_retry_budget_tokens",
+				Help: "Tokens left in the retry budget of each price source; retries need more than half of the maximum",
+			},
+			[]string{"source"}, // source: websocket/rest
+		),
+		RetriesSuppressed: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_retries_suppressed_total",
+				Help: "Total number of retries skipped because the retry budget of the price source was exhausted",
+			},
+			[]string{"source"},
+		),
+	}
+}
+
+// RecordActivation records when fallback from WebSocket to REST is activated
+func (f *FallbackMetrics) RecordActivation(reason, pair string) {
+	f.ActivationsTotal.WithLabelValues(reason, pair).Inc()
+}
+
+// RecordDuration records the duration of a fallback operation
+func (f *FallbackMetrics) RecordDuration(pair string, duration float64) {
+	f.Duration.WithLabelValues(pair).Observe(duration)
+}
+
+// UpdateCircuitBreakerState updates circuit breaker state
+// state: 0=closed, 1=open, 2=half_open
+func (f *FallbackMetrics) UpdateCircuitBreakerState(service, endpoint string, state int) {
+	f.CircuitBreakerState.WithLabelValues(service, endpoint).Set(float64(state))
+}
+
+// UpdateSourceHealthScore updates the rolling health score of a price source
+func (f *FallbackMetrics) UpdateSourceHealthScore(source string, score float64) {
+	f.SourceHealthScore.WithLabelValues(source).Set(score)
+}
+
+// SetPreferredSource marks whether a price source is queried first
+func (f *FallbackMetrics) SetPreferredSource(source string, preferred bool) {
+	value := 0.0
+	if preferred {
+		value = 1
+	}
+	f.PreferredSource.WithLabelValues(source).Set(value)
+}
+
+// UpdateRetryBudgetTokens updates the tokens left in the retry budget of a price source
+func (f *FallbackMetrics) UpdateRetryBudgetTokens(source string, tokens float64) {
+	f.RetryBudgetTokens.WithLabelValues(source).Set(tokens)
+}
+
+// RecordRetrySuppressed records a retry skipped for lack of retry budget
+func (f *FallbackMetrics) RecordRetrySuppressed(source string) {
+	f.RetriesSuppressed.WithLabelValues(source).Inc()
+}
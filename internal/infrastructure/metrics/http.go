@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// HTTPMetrics cubre las requests servidas por la API
+type HTTPMetrics struct {
+	RequestsTotal      *prometheus.CounterVec
+	RequestDuration    *prometheus.HistogramVec
+	RequestSizeBytes   *prometheus.HistogramVec
+	ResponseSizeBytes  *prometheus.HistogramVec
+	ResponseCacheTotal *prometheus.CounterVec
+	RejectedInputs     *prometheus.CounterVec
+	// Clientes de streaming (/ltp/stream, /ws)
+	StreamDroppedUpdates *prometheus.CounterVec
+	StreamSlowClients    *prometheus.CounterVec
+	StreamConflated      *prometheus.CounterVec
+	StreamOversized      *prometheus.CounterVec
+}
+
+func init() {
+	registerModule(GroupHTTP, func(m *Metrics, factory promauto.Factory) {
+		m.HTTP = newHTTPMetrics(factory)
+	})
+}
+
+func newHTTPMetrics(factory promauto.Factory) *HTTPMetrics {
+	return &HTTPMetrics{
+		RequestsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_requests_total",
+				Help: "Total number of HTTP requests processed",
+			},
+			[]string{"method", "path", "status_code"},
+		),
+		RequestDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_http_request_duration_seconds",
+				Help:    "HTTP request duration in seconds",
+				Buckets: prometheus.DefBuckets, // Standard buckets: .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10
+			},
+			[]string{"method", "path"},
+		),
+		RequestSizeBytes: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_http_request_size_bytes",
+				Help:    "HTTP request size in bytes",
+				Buckets: []float64{100, 1000, 10000, 100000, 1000000},
+			},
+			[]string{"method", "path"},
+		),
+		ResponseSizeBytes: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_http_response_size_bytes",
+				Help:    "HTTP response size in bytes",
+				Buckets: []float64{100, 1000, 10000, 100000, 1000000},
+			},
+			[]string{"method", "path"},
+		),
+		ResponseCacheTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_response_cache_total",
+				Help: "Total number of HTTP response cache lookups by result (hit, miss, bypass)",
+			},
+			[]string{"path", "result"},
+		),
+		RejectedInputs: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_rejected_inputs_total",
+				Help: "Total number of requests rejected by input validation before reaching handlers",
+			},
+			[]string{"reason"}, // reason: body_too_large/headers_too_large/unknown_parameter/invalid_pair
+		),
+		StreamDroppedUpdates: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_stream_dropped_updates_total",
+				Help: "Total number of intermediate price updates dropped for slow streaming clients (the latest price per pair is kept)",
+			},
+			[]string{"stream"}, // stream: sse/ws
+		),
+		StreamSlowClients: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_stream_slow_clients_disconnected_total",
+				Help: "Total number of streaming clients disconnected for not keeping up",
+			},
+			[]string{"stream", "reason"}, // reason: buffer_full/write_timeout
+		),
+		StreamConflated: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_stream_conflated_updates_total",
+				Help: "Total number of price updates replaced by a newer one of the same pair while held by the per-pair update rate limit",
+			},
+			[]string{"stream"}, // stream: sse/ws
+		),
+		StreamOversized: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_http_stream_oversized_messages_total",
+				Help: "Total number of streaming connections closed for a client message larger than server.streaming.max_message_bytes",
+			},
+			[]string{"stream"}, // stream: ws
+		),
+	}
+}
+
+// RecordRequest records HTTP request metrics
+func (h *HTTPMetrics) RecordRequest(method, path string, statusCode int, duration float64, requestSize, responseSize int64) {
+	h.RequestsTotal.WithLabelValues(method, path, strconv.Itoa(statusCode)).Inc()
+	h.RequestDuration.WithLabelValues(method, path).Observe(duration)
+
+	if requestSize > 0 {
+		h.RequestSizeBytes.WithLabelValues(method, path).Observe(float64(requestSize))
+	}
+	if responseSize > 0 {
+		h.ResponseSizeBytes.WithLabelValues(method, path).Observe(float64(responseSize))
+	}
+}
+
+// RecordResponseCache records an HTTP response cache lookup result
+func (h *HTTPMetrics) RecordResponseCache(path, result string) {
+	h.ResponseCacheTotal.WithLabelValues(path, result).Inc()
+}
+
+// RecordRejectedInput records a request rejected by input validation
+func (h *HTTPMetrics) RecordRejectedInput(reason string) {
+	h.RejectedInputs.WithLabelValues(reason).Inc()
+}
+
+// RecordStreamDroppedUpdates records intermediate updates dropped for a slow streaming client
+func (h *HTTPMetrics) RecordStreamDroppedUpdates(stream string, count int) {
+	h.StreamDroppedUpdates.WithLabelValues(stream).Add(float64(count))
+}
+
+// RecordStreamSlowClient records a streaming client disconnected for being too slow
+func (h *HTTPMetrics) RecordStreamSlowClient(stream, reason string) {
+	h.StreamSlowClients.WithLabelValues(stream, reason).Inc()
+}
+
+// RecordStreamConflatedUpdates records updates replaced while held by the per-pair rate limit
+func (h *HTTPMetrics) RecordStreamConflatedUpdates(stream string, count int) {
+	h.StreamConflated.WithLabelValues(stream).Add(float64(count))
+}
+
+// RecordStreamOversizedMessage records a streaming connection closed for an oversized client message
+func (h *HTTPMetrics) RecordStreamOversizedMessage(stream string) {
+	h.StreamOversized.WithLabelValues(stream).Inc()
+}
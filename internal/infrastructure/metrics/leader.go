@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// LeaderMetrics cubre la elección de líder entre réplicas
+type LeaderMetrics struct {
+	Status           prometheus.Gauge
+	TransitionsTotal *prometheus.CounterVec
+}
+
+func init() {
+	registerModule(GroupLeader, func(m *Metrics, factory promauto.Factory) {
+		m.Leader = newLeaderMetrics(factory)
+	})
+}
+
+func newLeaderMetrics(factory promauto.Factory) *LeaderMetrics {
+	return &LeaderMetrics{
+		Status: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_leader_status",
+				Help: "Leader election status of this instance (1=leader, 0=follower)",
+			},
+		),
+		TransitionsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_leader_transitions_total",
+				Help: "Total number of leadership transitions",
+			},
+			[]string{"transition"}, // transition: acquired/lost
+		),
+	}
+}
+
+// UpdateStatus updates the leader election status gauge
+func (l *LeaderMetrics) UpdateStatus(isLeader bool) {
+	status := 0.0
+	if isLeader {
+		status = 1.0
+	}
+	l.Status.Set(status)
+}
+
+// RecordTransition records a leadership acquisition or loss
+func (l *LeaderMetrics) RecordTransition(acquired bool) {
+	transition := "lost"
+	if acquired {
+		transition = "acquired"
+	}
+	l.TransitionsTotal.WithLabelValues(transition).Inc()
+}
@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Group identifica las métricas de un módulo; cada grupo se registra en su propio
+// sub-registry y puede deshabilitarse por configuración (metrics.disabled_groups)
+type Group string
+
+const (
+	GroupHTTP        Group = "http"
+	GroupCache       Group = "cache"
+	GroupExternalAPI Group = "external_api"
+	GroupPrices      Group = "prices"
+	GroupRateLimit   Group = "rate_limit"
+	GroupWebSocket   Group = "websocket"
+	GroupFallback    Group = "fallback"
+	GroupLeader      Group = "leader"
+	GroupApplication Group = "application"
+	GroupSLO         Group = "slo"
+)
+
+// ErrUnknownGroup indica un grupo de métricas inexistente
+var ErrUnknownGroup = errors.New("unknown metrics group")
+
+// module construye los collectors de un grupo; cada archivo del paquete registra
+// el suyo en init con registerModule
+type module struct {
+	group Group
+	build func(m *Metrics, factory promauto.Factory)
+}
+
+var modules []module
+
+func registerModule(group Group, build func(m *Metrics, factory promauto.Factory)) {
+	modules = append(modules, module{group: group, build: build})
+}
+
+// Groups devuelve los grupos de métricas registrados, en orden de registro
+func Groups() []Group {
+	groups := make([]Group, len(modules))
+	for i, mod := range modules {
+		groups[i] = mod.group
+	}
+	return groups
+}
+
+// ParseGroup valida el nombre de un grupo de métricas
+func ParseGroup(name string) (Group, error) {
+	for _, mod := range modules {
+		if string(mod.group) == name {
+			return mod.group, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownGroup, name)
+}
+
+// Metrics agrupa los collectors del servicio por módulo. La instancia por defecto
+// registra sobre prometheus.DefaultRegisterer; los tests pueden crear instancias
+// con su propio registry sin chocar con registros duplicados.
+type Metrics struct {
+	HTTP        *HTTPMetrics
+	Cache       *CacheMetrics
+	ExternalAPI *ExternalAPIMetrics
+	Prices      *PriceMetrics
+	RateLimit   *RateLimitMetrics
+	WebSocket   *WebSocketMetrics
+	Fallback    *FallbackMetrics
+	Leader      *LeaderMetrics
+	Application *ApplicationMetrics
+	SLO         *SLOMetrics
+
+	gatherer prometheus.Gatherer
+	groups   map[Group]*subRegistry
+}
+
+// New crea los collectors de todos los módulos y los registra en registerer.
+// Si registerer también es un prometheus.Gatherer (p. ej. *prometheus.Registry),
+// Handler expone sus métricas.
+func New(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{groups: make(map[Group]*subRegistry, len(modules))}
+	if gatherer, ok := registerer.(prometheus.Gatherer); ok {
+		m.gatherer = gatherer
+	}
+
+	var errs []error
+	for _, mod := range modules {
+		sub := &subRegistry{parent: registerer, enabled: true}
+		mod.build(m, promauto.With(sub))
+		m.groups[mod.group] = sub
+		for _, err := range sub.errs {
+			errs = append(errs, fmt.Errorf("failed to register %s metrics: %w", mod.group, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return m, nil
+}
+
+// Disable deja de exponer los grupos indicados; los collectors siguen aceptando
+// observaciones pero no aparecen en /metrics
+func (m *Metrics) Disable(groups ...Group) error {
+	for _, group := range groups {
+		sub, ok := m.groups[group]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownGroup, group)
+		}
+		sub.disable()
+	}
+	return nil
+}
+
+// Enabled indica si el grupo se expone en /metrics
+func (m *Metrics) Enabled(group Group) bool {
+	sub, ok := m.groups[group]
+	return ok && sub.isEnabled()
+}
+
+// Handler expone las métricas del registry asociado en formato Prometheus
+func (m *Metrics) Handler() http.Handler {
+	if m.gatherer == nil {
+		return promhttp.Handler()
+	}
+	return promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{})
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultMetrics atomic.Pointer[Metrics]
+)
+
+// Default devuelve la instancia usada por las funciones Record*/Update* del paquete;
+// se crea al primer uso sobre prometheus.DefaultRegisterer
+func Default() *Metrics {
+	defaultOnce.Do(func() {
+		if defaultMetrics.Load() != nil {
+			return
+		}
+		m, err := New(prometheus.DefaultRegisterer)
+		if err != nil {
+			panic(err)
+		}
+		defaultMetrics.Store(m)
+	})
+	return defaultMetrics.Load()
+}
+
+// SetDefault reemplaza la instancia por defecto (útil en tests con un registry
+// propio) y devuelve una función que restaura la anterior
+func SetDefault(m *Metrics) (restore func()) {
+	previous := Default()
+	defaultMetrics.Store(m)
+	return func() { defaultMetrics.Store(previous) }
+}
+
+// Handler expone las métricas de la instancia por defecto
+func Handler() http.Handler {
+	return Default().Handler()
+}
+
+// subRegistry registra los collectors de un grupo sobre el registerer padre y
+// recuerda cuáles son para poder retirarlos al deshabilitar el grupo. A diferencia
+// del registerer de Prometheus, MustRegister acumula errores en lugar de entrar en pánico.
+type subRegistry struct {
+	parent prometheus.Registerer
+
+	mu         sync.Mutex
+	collectors []prometheus.Collector
+	enabled    bool
+	errs       []error
+}
+
+func (s *subRegistry) Register(c prometheus.Collector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collectors = append(s.collectors, c)
+	if !s.enabled {
+		return nil
+	}
+	return s.parent.Register(c)
+}
+
+func (s *subRegistry) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := s.Register(c); err != nil {
+			s.mu.Lock()
+			s.errs = append(s.errs, err)
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *subRegistry) Unregister(c prometheus.Collector) bool {
+	return s.parent.Unregister(c)
+}
+
+func (s *subRegistry) disable() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	for _, c := range s.collectors {
+		s.parent.Unregister(c)
+	}
+	s.enabled = false
+}
+
+func (s *subRegistry) isEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enabled
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gatheredNames(t *testing.T, gatherer prometheus.Gatherer) map[string]bool {
+	t.Helper()
+	families, err := gatherer.Gather()
+	require.NoError(t, err)
+	names := make(map[string]bool, len(families))
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	return names
+}
+
+func TestNew_IsolatedRegistries(t *testing.T) {
+	first, err := New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	second, err := New(prometheus.NewRegistry())
+	require.NoError(t, err, "separate registries do not collide")
+
+	first.Prices.RecordRefresh("success")
+	assert.Equal(t, 1.0, testutil.ToFloat64(first.Prices.RefreshesTotal.WithLabelValues("success")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(second.Prices.RefreshesTotal.WithLabelValues("success")))
+}
+
+func TestNew_DuplicateRegistrationReturnsError(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := New(registry)
+	require.NoError(t, err)
+
+	_, err = New(registry)
+	assert.Error(t, err, "registering twice is an error instead of a panic")
+}
+
+func TestMetrics_DisableGroups(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry)
+	require.NoError(t, err)
+	m.Leader.UpdateStatus(true)
+	m.Prices.UpdateCurrent("BTC/USD", 50000)
+
+	require.NoError(t, m.Disable(GroupLeader))
+	assert.False(t, m.Enabled(GroupLeader))
+	assert.True(t, m.Enabled(GroupPrices))
+
+	names := gatheredNames(t, registry)
+	assert.False(t, names["btc_ltp_leader_status"])
+	assert.True(t, names["btc_ltp_current_prices"])
+
+	assert.ErrorIs(t, m.Disable("nope"), ErrUnknownGroup)
+}
+
+func TestSetDefault_RoutesPackageHelpers(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry)
+	require.NoError(t, err)
+	restore := SetDefault(m)
+	defer restore()
+
+	RecordCacheOperation("get", "hit")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Cache.OperationsTotal.WithLabelValues("get", "hit")))
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `btc_ltp_cache_operations_total{operation="get",result="hit"} 1`)
+}
+
+func TestRecordPricePipelineLatency(t *testing.T) {
+	m, err := New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	restore := SetDefault(m)
+	defer restore()
+
+	RecordPricePipelineLatency("BTC/USD", "", PipelineStageServed, time.Time{})
+	assert.Equal(t, 0, testutil.CollectAndCount(m.Prices.PipelineLatency), "prices without receipt time are ignored")
+
+	RecordPricePipelineLatency("BTC/USD", "websocket", PipelineStageWSCache, time.Now().Add(-20*time.Millisecond))
+	RecordPricePipelineLatency("BTC/USD", "", PipelineStageCache, time.Now().Add(-50*time.Millisecond))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.Prices.PipelineLatency))
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `btc_ltp_price_pipeline_latency_seconds_count{pair="BTC/USD",source="websocket",stage="ws_cache"} 1`)
+	assert.Contains(t, rec.Body.String(), `btc_ltp_price_pipeline_latency_seconds_count{pair="BTC/USD",source="unknown",stage="cache"} 1`)
+}
+
+func TestParseGroup(t *testing.T) {
+	group, err := ParseGroup("websocket")
+	require.NoError(t, err)
+	assert.Equal(t, GroupWebSocket, group)
+	assert.Len(t, Groups(), 10)
+
+	_, err = ParseGroup("WebSocket")
+	assert.ErrorIs(t, err, ErrUnknownGroup)
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -59,6 +61,20 @@ func (rw *responseWriterMetrics) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap exposes the wrapped writer to http.ResponseController (SSE flushing)
+func (rw *responseWriterMetrics) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack passes WebSocket upgrades through; they are recorded with status 101
+func (rw *responseWriterMetrics) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
 // normalizePath normalizes URL paths to avoid high cardinality in metrics
 // This is important to prevent metrics explosion from dynamic paths
 func normalizePath(path string) string {
@@ -82,12 +98,19 @@ func normalizePath(path string) string {
 		return "/api/v1/ltp/cached"
 	case strings.HasPrefix(path, "/api/v1/ltp/refresh"):
 		return "/api/v1/ltp/refresh"
+	case strings.HasPrefix(path, "/api/v1/ltp/stream"):
+		// SSE streams last minutes: keep them out of the /ltp latency
+		return "/api/v1/ltp/stream"
+	case path == "/api/v1/ws":
+		return "/api/v1/ws"
 	case strings.HasPrefix(path, "/api/v1/ltp"):
 		return "/api/v1/ltp"
 	case strings.HasPrefix(path, "/api/v1/"):
 		return "/api/v1/*"
 	case strings.HasPrefix(path, "/api/"):
 		return "/api/*"
+	case path == "/admin" || strings.HasPrefix(path, "/admin/"):
+		return "/admin/*"
 	default:
 		// For unknown paths, use a generic label
 		return "/unknown"
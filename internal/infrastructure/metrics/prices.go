@@ -0,0 +1,336 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PriceMetrics cubre las métricas de negocio: precios servidos, refrescos,
+// divergencia entre fuentes y validación
+type PriceMetrics struct {
+	RequestsTotal           *prometheus.CounterVec
+	RefreshesTotal          *prometheus.CounterVec
+	CurrentPrices           *prometheus.GaugeVec
+	Age                     *prometheus.GaugeVec
+	SourceDivergenceAbs     *prometheus.HistogramVec
+	SourceDivergencePercent *prometheus.HistogramVec
+	SourceConflictsTotal    *prometheus.CounterVec
+	QuarantinedTotal        *prometheus.CounterVec
+	DeviationPercent        *prometheus.GaugeVec
+	DeviationAlert          *prometheus.GaugeVec
+	DeviationAlertsTotal    *prometheus.CounterVec
+	AnomaliesTotal          *prometheus.CounterVec
+	Flatline                *prometheus.GaugeVec
+	ReturnZScore            *prometheus.GaugeVec
+	StalenessRefreshesTotal *prometheus.CounterVec
+	PipelineLatency         *prometheus.HistogramVec
+	PrunedPairs             prometheus.Gauge
+	UpdatesSuppressedTotal  *prometheus.CounterVec
+	SlowRequestsTotal       *prometheus.CounterVec
+	// Loop de refresco automático de la caché
+	RefreshLoopDuration        *prometheus.HistogramVec
+	RefreshLoopPairsTotal      *prometheus.CounterVec
+	RefreshLastSuccess         *prometheus.GaugeVec
+	RefreshConsecutiveFailures *prometheus.GaugeVec
+}
+
+// Etapas del pipeline de precios medidas desde la recepción del tick
+const (
+	// PipelineStageWSCache: tick escrito en la caché interna del cliente WebSocket
+	PipelineStageWSCache = "ws_cache"
+	// PipelineStageCache: precio legible desde la caché compartida
+	PipelineStageCache = "cache"
+	// PipelineStageServed: precio enviado en una respuesta HTTP
+	PipelineStageServed = "served"
+)
+
+func init() {
+	registerModule(GroupPrices, func(m *Metrics, factory promauto.Factory) {
+		m.Prices = newPriceMetrics(factory)
+	})
+}
+
+func newPriceMetrics(factory promauto.Factory) *PriceMetrics {
+	return &PriceMetrics{
+		RequestsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_requests_total",
+				Help: "Total number of price requests by trading pair",
+			},
+			[]string{"pair", "cache_result"}, // cache_result: hit/miss
+		),
+		RefreshesTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_refreshes_total",
+				Help: "Total number of price refresh operations",
+			},
+			[]string{"result"}, // result: success/error
+		),
+		CurrentPrices: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_current_prices",
+				Help: "Current cryptocurrency prices",
+			},
+			[]string{"pair"},
+		),
+		Age: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_price_age_seconds",
+				Help: "Age of cached prices in seconds",
+			},
+			[]string{"pair"},
+		),
+		SourceDivergenceAbs: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_price_source_divergence_absolute",
+				Help:    "Absolute difference between WebSocket and REST prices observed within the comparison window",
+				Buckets: []float64{0.01, 0.1, 1, 5, 10, 50, 100, 500, 1000},
+			},
+			[]string{"pair"},
+		),
+		SourceDivergencePercent: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_price_source_divergence_percent",
+				Help:    "Percentage difference between WebSocket and REST prices observed within the comparison window",
+				Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+			},
+			[]string{"pair"},
+		),
+		SourceConflictsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_source_conflicts_total",
+				Help: "Total number of significant disagreements between WebSocket and REST prices",
+			},
+			[]string{"pair"},
+		),
+		QuarantinedTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_prices_quarantined_total",
+				Help: "Total number of price ticks rejected by sanity checks",
+			},
+			[]string{"pair", "reason"}, // reason: non_positive/future_timestamp/jump
+		),
+		DeviationPercent: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_price_deviation_percent",
+				Help: "Percentage difference between the cached price and the reference source at the last integrity check",
+			},
+			[]string{"pair"},
+		),
+		DeviationAlert: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_price_deviation_alert",
+				Help: "Whether the cached price deviates from the reference source beyond the configured threshold (1) or not (0)",
+			},
+			[]string{"pair"},
+		),
+		DeviationAlertsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_deviation_alerts_total",
+				Help: "Total number of times a cached price started deviating from the reference source",
+			},
+			[]string{"pair"},
+		),
+		AnomaliesTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_anomalies_total",
+				Help: "Total number of anomalies detected on the price tick stream",
+			},
+			[]string{"pair", "kind"}, // kind: flatline/spike
+		),
+		Flatline: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_price_flatline",
+				Help: "Whether the pair price has not changed for the configured flatline period (1) or not (0)",
+			},
+			[]string{"pair"},
+		),
+		ReturnZScore: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_price_return_zscore",
+				Help: "Z-score of the last price change relative to the recent changes of the pair",
+			},
+			[]string{"pair"},
+		),
+		StalenessRefreshesTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_staleness_refreshes_total",
+				Help: "Total number of REST refreshes triggered by the staleness watcher",
+			},
+			[]string{"pair", "result"}, // result: success/error/superseded
+		),
+		PipelineLatency: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_price_pipeline_latency_seconds",
+				Help:    "Time from price tick receipt to each pipeline stage (cache visibility, HTTP response)",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+			},
+			[]string{"pair", "source", "stage"}, // stage: ws_cache/cache/served
+		),
+		PrunedPairs: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_pruned_pairs",
+				Help: "Number of supported pairs unsubscribed and excluded from refresh because nobody requested them recently",
+			},
+		),
+		UpdatesSuppressedTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_updates_suppressed_total",
+				Help: "Total number of WebSocket ticks not written to the cache because they repeated the cached price",
+			},
+			[]string{"pair"},
+		),
+		SlowRequestsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_price_slow_requests_total",
+				Help: "Total number of price requests that exceeded the slow request threshold",
+			},
+			[]string{"pair"},
+		),
+		RefreshLoopDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "btc_ltp_refresh_loop_duration_seconds",
+				Help:    "Duration of each automatic cache refresh run",
+				Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+			},
+			[]string{"result"}, // result: success/partial/error
+		),
+		RefreshLoopPairsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_refresh_loop_pairs_total",
+				Help: "Total number of pairs processed by the automatic cache refresh",
+			},
+			[]string{"result"}, // result: refreshed/failed
+		),
+		RefreshLastSuccess: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_refresh_last_success_timestamp_seconds",
+				Help: "Unix time of the last automatic cache refresh that updated the pair",
+			},
+			[]string{"pair"},
+		),
+		RefreshConsecutiveFailures: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_refresh_consecutive_failures",
+				Help: "Automatic cache refreshes in a row that failed to update the pair",
+			},
+			[]string{"pair"},
+		),
+	}
+}
+
+// RecordRequest records price request metrics
+func (p *PriceMetrics) RecordRequest(pair string, cacheHit bool) {
+	cacheResult := "miss"
+	if cacheHit {
+		cacheResult = "hit"
+	}
+	p.RequestsTotal.WithLabelValues(pair, cacheResult).Inc()
+}
+
+// RecordRefresh records a price refresh operation (result: success/error)
+func (p *PriceMetrics) RecordRefresh(result string) {
+	p.RefreshesTotal.WithLabelValues(result).Inc()
+}
+
+// UpdateCurrent updates current price gauge
+func (p *PriceMetrics) UpdateCurrent(pair string, price float64) {
+	p.CurrentPrices.WithLabelValues(pair).Set(price)
+}
+
+// UpdateAge updates price age gauge
+func (p *PriceMetrics) UpdateAge(pair string, ageSeconds float64) {
+	p.Age.WithLabelValues(pair).Set(ageSeconds)
+}
+
+// RecordSourceDivergence records the divergence between WebSocket and REST prices
+func (p *PriceMetrics) RecordSourceDivergence(pair string, absolute, percent float64) {
+	p.SourceDivergenceAbs.WithLabelValues(pair).Observe(absolute)
+	p.SourceDivergencePercent.WithLabelValues(pair).Observe(percent)
+}
+
+// RecordSourceConflict records a divergence above the warning threshold
+func (p *PriceMetrics) RecordSourceConflict(pair string) {
+	p.SourceConflictsTotal.WithLabelValues(pair).Inc()
+}
+
+// RecordQuarantined records a price tick rejected by validation
+func (p *PriceMetrics) RecordQuarantined(pair, reason string) {
+	p.QuarantinedTotal.WithLabelValues(pair, reason).Inc()
+}
+
+// UpdateDeviation records the deviation from the reference source and whether it triggers the alert
+func (p *PriceMetrics) UpdateDeviation(pair string, percent float64, alert bool) {
+	p.DeviationPercent.WithLabelValues(pair).Set(percent)
+	value := 0.0
+	if alert {
+		value = 1
+	}
+	p.DeviationAlert.WithLabelValues(pair).Set(value)
+}
+
+// RecordDeviationAlert records a pair that started deviating from the reference source
+func (p *PriceMetrics) RecordDeviationAlert(pair string) {
+	p.DeviationAlertsTotal.WithLabelValues(pair).Inc()
+}
+
+// RecordAnomaly records an anomaly detected on the tick stream (kind: flatline/spike)
+func (p *PriceMetrics) RecordAnomaly(pair, kind string) {
+	p.AnomaliesTotal.WithLabelValues(pair, kind).Inc()
+}
+
+// UpdateFlatline records whether the pair is in a flatline
+func (p *PriceMetrics) UpdateFlatline(pair string, flatlined bool) {
+	value := 0.0
+	if flatlined {
+		value = 1
+	}
+	p.Flatline.WithLabelValues(pair).Set(value)
+}
+
+// UpdateReturnZScore records the z-score of the last price change
+func (p *PriceMetrics) UpdateReturnZScore(pair string, z float64) {
+	p.ReturnZScore.WithLabelValues(pair).Set(z)
+}
+
+// RecordStalenessRefresh records a REST refresh performed by the staleness watcher
+func (p *PriceMetrics) RecordStalenessRefresh(pair, result string) {
+	p.StalenessRefreshesTotal.WithLabelValues(pair, result).Inc()
+}
+
+// ObservePipelineLatency records the time from tick receipt to a pipeline stage
+func (p *PriceMetrics) ObservePipelineLatency(pair, source, stage string, seconds float64) {
+	p.PipelineLatency.WithLabelValues(pair, source, stage).Observe(seconds)
+}
+
+// UpdatePrunedPairs updates the number of pairs pruned for inactivity
+func (p *PriceMetrics) UpdatePrunedPairs(count int) {
+	p.PrunedPairs.Set(float64(count))
+}
+
+// RecordRefreshLoop records an automatic cache refresh run and the pairs it processed
+func (p *PriceMetrics) RecordRefreshLoop(result string, seconds float64, refreshed, failed int) {
+	p.RefreshLoopDuration.WithLabelValues(result).Observe(seconds)
+	p.RefreshLoopPairsTotal.WithLabelValues("refreshed").Add(float64(refreshed))
+	p.RefreshLoopPairsTotal.WithLabelValues("failed").Add(float64(failed))
+}
+
+// UpdateRefreshPair records the last successful refresh and the consecutive failures of a pair
+func (p *PriceMetrics) UpdateRefreshPair(pair string, lastSuccess time.Time, consecutiveFailures int) {
+	if !lastSuccess.IsZero() {
+		p.RefreshLastSuccess.WithLabelValues(pair).Set(float64(lastSuccess.Unix()))
+	}
+	p.RefreshConsecutiveFailures.WithLabelValues(pair).Set(float64(consecutiveFailures))
+}
+
+// RecordUpdateSuppressed records a tick skipped as a repeat of the cached price
+func (p *PriceMetrics) RecordUpdateSuppressed(pair string) {
+	p.UpdatesSuppressedTotal.WithLabelValues(pair).Inc()
+}
+
+// RecordSlowRequest records a price request slower than the configured threshold
+func (p *PriceMetrics) RecordSlowRequest(pair string) {
+	p.SlowRequestsTotal.WithLabelValues(pair).Inc()
+}
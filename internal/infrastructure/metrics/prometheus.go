@@ -1,346 +1,322 @@
 package metrics
 
-import (
-	"strconv"
-
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
-)
-
-// Prometheus metrics for the BTC LTP Service
-var (
-	// HTTP Metrics
-	HTTPRequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_http_requests_total",
-			Help: "Total number of HTTP requests processed",
-		},
-		[]string{"method", "path", "status_code"},
-	)
-
-	HTTPRequestDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "btc_ltp_http_request_duration_seconds",
-			Help:    "HTTP request duration in seconds",
-			Buckets: prometheus.DefBuckets, // Standard buckets: .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10
-		},
-		[]string{"method", "path"},
-	)
-
-	HTTPRequestSizeBytes = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "btc_ltp_http_request_size_bytes",
-			Help:    "HTTP request size in bytes",
-			Buckets: []float64{100, 1000, 10000, 100000, 1000000},
-		},
-		[]string{"method", "path"},
-	)
-
-	HTTPResponseSizeBytes = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "btc_ltp_http_response_size_bytes",
-			Help:    "HTTP response size in bytes",
-			Buckets: []float64{100, 1000, 10000, 100000, 1000000},
-		},
-		[]string{"method", "path"},
-	)
-
-	// Cache Metrics
-	CacheOperationsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_cache_operations_total",
-			Help: "Total number of cache operations",
-		},
-		[]string{"operation", "result"}, // operation: get/set/delete, result: hit/miss/success/error
-	)
-
-	CacheKeys = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_cache_keys",
-			Help: "Number of keys currently in cache",
-		},
-		[]string{"cache_type"}, // cache_type: memory/redis
-	)
-
-	// External API Metrics
-	ExternalAPIRequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_external_api_requests_total",
-			Help: "Total number of external API requests",
-		},
-		[]string{"service", "endpoint", "status_code"},
-	)
-
-	ExternalAPIRequestDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "btc_ltp_external_api_request_duration_seconds",
-			Help:    "External API request duration in seconds",
-			Buckets: []float64{0.1, 0.5, 1.0, 2.0, 5.0, 10.0}, // External APIs can be slower
-		},
-		[]string{"service", "endpoint"},
-	)
-
-	ExternalAPIRetries = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_external_api_retries_total",
-			Help: "Total number of external API retry attempts",
-		},
-		[]string{"service", "endpoint", "attempt"},
-	)
-
-	// Business Metrics
-	PriceRequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_price_requests_total",
-			Help: "Total number of price requests by trading pair",
-		},
-		[]string{"pair", "cache_result"}, // cache_result: hit/miss
-	)
-
-	PriceRefreshesTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_price_refreshes_total",
-			Help: "Total number of price refresh operations",
-		},
-		[]string{"result"}, // result: success/error
-	)
-
-	CurrentPrices = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_current_prices",
-			Help: "Current cryptocurrency prices",
-		},
-		[]string{"pair"},
-	)
-
-	PriceAge = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_price_age_seconds",
-			Help: "Age of cached prices in seconds",
-		},
-		[]string{"pair"},
-	)
-
-	// Rate Limiting Metrics
-	RateLimitRequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_rate_limit_requests_total",
-			Help: "Total number of requests processed by rate limiter",
-		},
-		[]string{"result"}, // result: allowed/blocked
-	)
-
-	RateLimitTokensRemaining = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_rate_limit_tokens_remaining",
-			Help: "Number of tokens remaining in rate limiter buckets",
-		},
-		[]string{"client_id"}, // client_id: IP address or identifier
-	)
-
-	// Kraken Rate Limiting Metrics
-	KrakenRateLimitDrops = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_kraken_rate_limit_drops_total",
-			Help: "Number of requests dropped due to Kraken rate limiting (429 responses)",
-		},
-		[]string{"endpoint"},
-	)
-
-	KrakenBackoffDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "btc_ltp_kraken_backoff_duration_seconds",
-			Help:    "Duration of backoff delays due to Kraken rate limiting",
-			Buckets: []float64{0.1, 0.2, 0.5, 1.0, 2.0, 5.0, 10.0},
-		},
-		[]string{"endpoint", "attempt"},
-	)
-
-	// Application Metrics
-	ApplicationInfo = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_application_info",
-			Help: "Application information",
-		},
-		[]string{"version", "build_time", "go_version"},
-	)
-
-	UptimeSeconds = promauto.NewGauge(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_uptime_seconds",
-			Help: "Application uptime in seconds",
-		},
-	)
-
-	// WebSocket Metrics
-	WebSocketChannelDrops = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_ws_channel_drops_total",
-			Help: "Total de actualizaciones de precio descartadas por canal lleno",
-		},
-		[]string{"pair"},
-	)
-
-	// Resilience and Fallback Metrics
-	FallbackActivationsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_fallback_activations_total",
-			Help: "Total number of fallback activations from WebSocket to REST",
-		},
-		[]string{"reason", "pair"}, // reason: timeout/connection_error/max_retries/panic
-	)
-
-	FallbackDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "btc_ltp_fallback_duration_seconds",
-			Help:    "Duration of fallback operations from WebSocket failure to REST success",
-			Buckets: []float64{0.1, 0.25, 0.5, 1.0, 2.0, 5.0, 10.0, 15.0},
-		},
-		[]string{"pair"},
-	)
-
-	WebSocketConnectionStatus = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_websocket_connection_status",
-			Help: "WebSocket connection status (1=connected, 0=disconnected)",
-		},
-		[]string{},
-	)
-
-	CircuitBreakerState = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "btc_ltp_circuit_breaker_state",
-			Help: "Circuit breaker state (0=closed, 1=open, 2=half_open)",
-		},
-		[]string{"service", "endpoint"},
-	)
-
-	WebSocketReconnectionAttempts = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "btc_ltp_websocket_reconnection_attempts_total",
-			Help: "Total number of WebSocket reconnection attempts",
-		},
-		[]string{"reason"}, // reason: startup/connection_lost/manual
-	)
-)
-
-// Helper functions for common metric operations
+// Helper functions for common metric operations. They record on the Default()
+// instance; see the module files (http.go, cache.go, ...) for the metric definitions.
+
+import "time"
 
 // RecordHTTPRequest records HTTP request metrics
 func RecordHTTPRequest(method, path string, statusCode int, duration float64, requestSize, responseSize int64) {
-	HTTPRequestsTotal.WithLabelValues(method, path, strconv.Itoa(statusCode)).Inc()
-	HTTPRequestDuration.WithLabelValues(method, path).Observe(duration)
+	Default().HTTP.RecordRequest(method, path, statusCode, duration, requestSize, responseSize)
+}
 
-	if requestSize > 0 {
-		HTTPRequestSizeBytes.WithLabelValues(method, path).Observe(float64(requestSize))
-	}
-	if responseSize > 0 {
-		HTTPResponseSizeBytes.WithLabelValues(method, path).Observe(float64(responseSize))
-	}
+// RecordHTTPResponseCache records an HTTP response cache lookup result
+func RecordHTTPResponseCache(path, result string) {
+	Default().HTTP.RecordResponseCache(path, result)
+}
+
+// RecordHTTPInputRejected records a request rejected by input validation
+func RecordHTTPInputRejected(reason string) {
+	Default().HTTP.RecordRejectedInput(reason)
+}
+
+// RecordStreamDroppedUpdates records intermediate updates dropped for a slow streaming client
+func RecordStreamDroppedUpdates(stream string, count int) {
+	Default().HTTP.RecordStreamDroppedUpdates(stream, count)
+}
+
+// RecordStreamSlowClient records a streaming client disconnected for being too slow
+func RecordStreamSlowClient(stream, reason string) {
+	Default().HTTP.RecordStreamSlowClient(stream, reason)
+}
+
+// RecordStreamOversizedMessage records a streaming connection closed for an oversized client message
+func RecordStreamOversizedMessage(stream string) {
+	Default().HTTP.RecordStreamOversizedMessage(stream)
+}
+
+// RecordStreamConflatedUpdates records updates replaced while held by the per-pair rate limit
+func RecordStreamConflatedUpdates(stream string, count int) {
+	Default().HTTP.RecordStreamConflatedUpdates(stream, count)
 }
 
 // RecordCacheOperation records cache operation metrics
 func RecordCacheOperation(operation, result string) {
-	CacheOperationsTotal.WithLabelValues(operation, result).Inc()
+	Default().Cache.RecordOperation(operation, result)
 }
 
 // RecordExternalAPICall records external API call metrics
 func RecordExternalAPICall(service, endpoint string, statusCode int, duration float64) {
-	ExternalAPIRequestsTotal.WithLabelValues(service, endpoint, strconv.Itoa(statusCode)).Inc()
-	ExternalAPIRequestDuration.WithLabelValues(service, endpoint).Observe(duration)
+	Default().ExternalAPI.RecordCall(service, endpoint, statusCode, duration)
 }
 
 // RecordExternalAPIRetry records external API retry attempts
 func RecordExternalAPIRetry(service, endpoint string, attempt int) {
-	ExternalAPIRetries.WithLabelValues(service, endpoint, strconv.Itoa(attempt)).Inc()
+	Default().ExternalAPI.RecordRetry(service, endpoint, attempt)
 }
 
 // RecordWebSocketChannelDrop incrementa contador de descartes por canal lleno
-func RecordWebSocketChannelDrop(pair string) {
-	WebSocketChannelDrops.WithLabelValues(pair).Inc()
+func RecordWebSocketChannelDrop(pair, policy string) {
+	Default().WebSocket.RecordChannelDrop(pair, policy)
 }
 
 // RecordPriceRequest records price request metrics
 func RecordPriceRequest(pair string, cacheHit bool) {
-	cacheResult := "miss"
-	if cacheHit {
-		cacheResult = "hit"
-	}
-	PriceRequestsTotal.WithLabelValues(pair, cacheResult).Inc()
+	Default().Prices.RecordRequest(pair, cacheHit)
+}
+
+// RecordPriceRefresh records a price refresh operation (result: success/error)
+func RecordPriceRefresh(result string) {
+	Default().Prices.RecordRefresh(result)
 }
 
 // UpdateCurrentPrice updates current price gauge
 func UpdateCurrentPrice(pair string, price float64) {
-	CurrentPrices.WithLabelValues(pair).Set(price)
+	Default().Prices.UpdateCurrent(pair, price)
 }
 
 // UpdatePriceAge updates price age gauge
 func UpdatePriceAge(pair string, ageSeconds float64) {
-	PriceAge.WithLabelValues(pair).Set(ageSeconds)
+	Default().Prices.UpdateAge(pair, ageSeconds)
 }
 
 // RecordRateLimitResult records rate limiting results
 func RecordRateLimitResult(allowed bool) {
-	result := "blocked"
-	if allowed {
-		result = "allowed"
-	}
-	RateLimitRequestsTotal.WithLabelValues(result).Inc()
+	Default().RateLimit.RecordResult(allowed)
 }
 
 // UpdateRateLimitTokens updates remaining tokens gauge
 func UpdateRateLimitTokens(clientID string, tokens float64) {
-	RateLimitTokensRemaining.WithLabelValues(clientID).Set(tokens)
+	Default().RateLimit.UpdateTokens(clientID, tokens)
+}
+
+// RecordRateLimitRejection records a request rejected by the rate limiter for a client
+func RecordRateLimitRejection(clientID string) {
+	Default().RateLimit.RecordRejected(clientID)
 }
 
 // RecordKrakenRateLimitDrop records requests dropped due to Kraken 429 responses
 func RecordKrakenRateLimitDrop(endpoint string) {
-	KrakenRateLimitDrops.WithLabelValues(endpoint).Inc()
+	Default().ExternalAPI.RecordKrakenRateLimitDrop(endpoint)
+}
+
+// RecordKrakenEgressBlocked records an outbound connection refused by the egress allowlist
+func RecordKrakenEgressBlocked(host string) {
+	Default().ExternalAPI.RecordKrakenEgressBlocked(host)
 }
 
 // RecordKrakenBackoffDuration records duration of backoff delays
 func RecordKrakenBackoffDuration(endpoint string, attempt int, duration float64) {
-	KrakenBackoffDuration.WithLabelValues(endpoint, strconv.Itoa(attempt)).Observe(duration)
+	Default().ExternalAPI.RecordKrakenBackoffDuration(endpoint, attempt, duration)
 }
 
 // SetApplicationInfo sets application information
 func SetApplicationInfo(version, buildTime, goVersion string) {
-	ApplicationInfo.WithLabelValues(version, buildTime, goVersion).Set(1)
+	Default().Application.SetInfo(version, buildTime, goVersion)
+}
+
+// SetRuntimeLimits records the runtime limits applied at startup
+func SetRuntimeLimits(maxProcs int, memoryLimit int64, cpuQuota float64, cgroupMemory int64) {
+	Default().Application.SetRuntimeLimits(maxProcs, memoryLimit, cpuQuota, cgroupMemory)
 }
 
 // UpdateUptime updates application uptime
 func UpdateUptime(seconds float64) {
-	UptimeSeconds.Set(seconds)
+	Default().Application.UpdateUptime(seconds)
 }
 
 // Resilience and Fallback Metrics Functions
 
 // RecordFallbackActivation records when fallback from WebSocket to REST is activated
 func RecordFallbackActivation(reason, pair string) {
-	FallbackActivationsTotal.WithLabelValues(reason, pair).Inc()
+	Default().Fallback.RecordActivation(reason, pair)
 }
 
 // RecordFallbackDuration records the duration of a fallback operation
 func RecordFallbackDuration(pair string, duration float64) {
-	FallbackDuration.WithLabelValues(pair).Observe(duration)
+	Default().Fallback.RecordDuration(pair, duration)
+}
+
+// UpdateSourceHealthScore updates the rolling health score of a price source
+func UpdateSourceHealthScore(source string, score float64) {
+	Default().Fallback.UpdateSourceHealthScore(source, score)
+}
+
+// SetPreferredSource marks whether a price source is queried first
+func SetPreferredSource(source string, preferred bool) {
+	Default().Fallback.SetPreferredSource(source, preferred)
+}
+
+// UpdateRetryBudgetTokens updates the tokens left in the retry budget of a price source
+func UpdateRetryBudgetTokens(source string, tokens float64) {
+	Default().Fallback.UpdateRetryBudgetTokens(source, tokens)
+}
+
+// RecordRetrySuppressed records a retry skipped for lack of retry budget
+func RecordRetrySuppressed(source string) {
+	Default().Fallback.RecordRetrySuppressed(source)
 }
 
 // UpdateWebSocketConnectionStatus updates WebSocket connection status
 func UpdateWebSocketConnectionStatus(connected bool) {
-	status := 0.0
-	if connected {
-		status = 1.0
-	}
-	WebSocketConnectionStatus.WithLabelValues().Set(status)
+	Default().WebSocket.UpdateConnectionStatus(connected)
 }
 
 // UpdateCircuitBreakerState updates circuit breaker state
 // state: 0=closed, 1=open, 2=half_open
 func UpdateCircuitBreakerState(service, endpoint string, state int) {
-	CircuitBreakerState.WithLabelValues(service, endpoint).Set(float64(state))
+	Default().Fallback.UpdateCircuitBreakerState(service, endpoint, state)
 }
 
 // RecordWebSocketReconnectionAttempt records WebSocket reconnection attempts
 func RecordWebSocketReconnectionAttempt(reason string) {
-	WebSocketReconnectionAttempts.WithLabelValues(reason).Inc()
+	Default().WebSocket.RecordReconnectionAttempt(reason)
+}
+
+// UpdateWebSocketUnconfirmedSubscriptions updates the unconfirmed subscriptions gauge
+func UpdateWebSocketUnconfirmedSubscriptions(count int) {
+	Default().WebSocket.UpdateUnconfirmedSubscriptions(count)
+}
+
+// RecordWebSocketSubscriptionRetry records a subscription re-sent after confirmation timeout
+func RecordWebSocketSubscriptionRetry(pair string) {
+	Default().WebSocket.RecordSubscriptionRetry(pair)
+}
+
+// UpdateWebSocketShards updates the number of WebSocket shards
+func UpdateWebSocketShards(count int) {
+	Default().WebSocket.UpdateShards(count)
+}
+
+// UpdateWebSocketShardStatus updates the connection status of a WebSocket shard
+func UpdateWebSocketShardStatus(shard string, connected bool) {
+	Default().WebSocket.UpdateShardStatus(shard, connected)
+}
+
+// UpdateWebSocketShardPairs updates the number of pairs assigned to a WebSocket shard
+func UpdateWebSocketShardPairs(shard string, pairs int) {
+	Default().WebSocket.UpdateShardPairs(shard, pairs)
+}
+
+// UpdateWebSocketStandbyStatus updates the warm standby WebSocket connection status
+func UpdateWebSocketStandbyStatus(connected bool) {
+	Default().WebSocket.UpdateStandbyStatus(connected)
+}
+
+// RecordWebSocketStandbyPromotion records the standby connection replacing a failed shard
+func RecordWebSocketStandbyPromotion() {
+	Default().WebSocket.RecordStandbyPromotion()
+}
+
+// UpdateKrakenSystemStatus marks status as the current Kraken system status
+func UpdateKrakenSystemStatus(status string) {
+	Default().WebSocket.UpdateKrakenSystemStatus(status)
+}
+
+// RecordWebSocketOversizedFrame records a Kraken connection closed for exceeding the frame size limit
+func RecordWebSocketOversizedFrame() {
+	Default().WebSocket.RecordOversizedFrame()
+}
+
+// RecordWebSocketQuietReconnect records a Kraken connection reconnected for receiving no frames
+func RecordWebSocketQuietReconnect() {
+	Default().WebSocket.RecordQuietReconnect()
+}
+
+// RecordCacheStaleWriteRejected records a price write rejected as out of order
+func RecordCacheStaleWriteRejected(pair string) {
+	Default().Cache.RecordStaleWriteRejected(pair)
+}
+
+// RecordPriceSourceDivergence records the divergence between WebSocket and REST prices
+func RecordPriceSourceDivergence(pair string, absolute, percent float64) {
+	Default().Prices.RecordSourceDivergence(pair, absolute, percent)
+}
+
+// RecordPriceSourceConflict records a divergence above the warning threshold
+func RecordPriceSourceConflict(pair string) {
+	Default().Prices.RecordSourceConflict(pair)
+}
+
+// RecordPriceQuarantined records a price tick rejected by validation
+func RecordPriceQuarantined(pair, reason string) {
+	Default().Prices.RecordQuarantined(pair, reason)
+}
+
+// UpdatePriceDeviation records the deviation from the reference source and whether it triggers the alert
+func UpdatePriceDeviation(pair string, percent float64, alert bool) {
+	Default().Prices.UpdateDeviation(pair, percent, alert)
+}
+
+// RecordPriceDeviationAlert records a pair that started deviating from the reference source
+func RecordPriceDeviationAlert(pair string) {
+	Default().Prices.RecordDeviationAlert(pair)
+}
+
+// RecordPriceAnomaly records an anomaly detected on the tick stream (kind: flatline/spike)
+func RecordPriceAnomaly(pair, kind string) {
+	Default().Prices.RecordAnomaly(pair, kind)
+}
+
+// UpdatePriceFlatline records whether the pair is in a flatline
+func UpdatePriceFlatline(pair string, flatlined bool) {
+	Default().Prices.UpdateFlatline(pair, flatlined)
+}
+
+// UpdatePriceReturnZScore records the z-score of the last price change
+func UpdatePriceReturnZScore(pair string, z float64) {
+	Default().Prices.UpdateReturnZScore(pair, z)
+}
+
+// UpdateLeaderStatus updates the leader election status gauge
+func UpdateLeaderStatus(isLeader bool) {
+	Default().Leader.UpdateStatus(isLeader)
+}
+
+// RecordLeaderTransition records a leadership acquisition or loss
+func RecordLeaderTransition(acquired bool) {
+	Default().Leader.RecordTransition(acquired)
+}
+
+// RecordStalenessRefresh records a REST refresh performed by the staleness watcher
+func RecordStalenessRefresh(pair, result string) {
+	Default().Prices.RecordStalenessRefresh(pair, result)
+}
+
+// RecordPricePipelineLatency records the time elapsed since receivedAt for a pipeline
+// stage; prices without a receipt time are ignored
+func RecordPricePipelineLatency(pair, source, stage string, receivedAt time.Time) {
+	if receivedAt.IsZero() {
+		return
+	}
+	if source == "" {
+		source = "unknown"
+	}
+	Default().Prices.ObservePipelineLatency(pair, source, stage, max(time.Since(receivedAt).Seconds(), 0))
+}
+
+// UpdatePrunedPairs updates the number of pairs pruned for inactivity
+func UpdatePrunedPairs(count int) {
+	Default().Prices.UpdatePrunedPairs(count)
+}
+
+// RecordRefreshLoop records an automatic cache refresh run and the pairs it processed
+// (result: success/partial/error)
+func RecordRefreshLoop(result string, seconds float64, refreshed, failed int) {
+	Default().Prices.RecordRefreshLoop(result, seconds, refreshed, failed)
+}
+
+// UpdateRefreshPair records the last successful refresh and the consecutive failures of a pair
+func UpdateRefreshPair(pair string, lastSuccess time.Time, consecutiveFailures int) {
+	Default().Prices.UpdateRefreshPair(pair, lastSuccess, consecutiveFailures)
+}
+
+// RecordPriceUpdateSuppressed records a tick skipped as a repeat of the cached price
+func RecordPriceUpdateSuppressed(pair string) {
+	Default().Prices.RecordUpdateSuppressed(pair)
+}
+
+// RecordSlowPriceRequest records a price request slower than the configured threshold
+func RecordSlowPriceRequest(pair string) {
+	Default().Prices.RecordSlowRequest(pair)
 }
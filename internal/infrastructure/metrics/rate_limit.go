@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RateLimitMetrics cubre las decisiones del rate limiter de la API
+type RateLimitMetrics struct {
+	RequestsTotal   *prometheus.CounterVec
+	TokensRemaining *prometheus.GaugeVec
+	RejectedTotal   *prometheus.CounterVec
+}
+
+func init() {
+	registerModule(GroupRateLimit, func(m *Metrics, factory promauto.Factory) {
+		m.RateLimit = newRateLimitMetrics(factory)
+	})
+}
+
+func newRateLimitMetrics(factory promauto.Factory) *RateLimitMetrics {
+	return &RateLimitMetrics{
+		RequestsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_rate_limit_requests_total",
+				Help: "Total number of requests processed by rate limiter",
+			},
+			[]string{"result"}, // result: allowed/blocked
+		),
+		TokensRemaining: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_rate_limit_tokens_remaining",
+				Help: "Number of tokens remaining in rate limiter buckets",
+			},
+			[]string{"client_id"}, // client_id: IP address or identifier
+		),
+		RejectedTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_rate_limit_rejected_total",
+				Help: "Total number of requests rejected by the rate limiter per client",
+			},
+			[]string{"client_id"},
+		),
+	}
+}
+
+// RecordResult records rate limiting results
+func (r *RateLimitMetrics) RecordResult(allowed bool) {
+	result := "blocked"
+	if allowed {
+		result = "allowed"
+	}
+	r.RequestsTotal.WithLabelValues(result).Inc()
+}
+
+// UpdateTokens updates remaining tokens gauge
+func (r *RateLimitMetrics) UpdateTokens(clientID string, tokens float64) {
+	r.TokensRemaining.WithLabelValues(clientID).Set(tokens)
+}
+
+// RecordRejected records a request rejected for the given client
+func (r *RateLimitMetrics) RecordRejected(clientID string) {
+	r.RejectedTotal.WithLabelValues(clientID).Inc()
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SLOMetrics expone el cumplimiento de los objetivos de nivel de servicio
+// calculado por el tracker de SLOs (internal/infrastructure/slo)
+type SLOMetrics struct {
+	Target               *prometheus.GaugeVec
+	Compliance           *prometheus.GaugeVec
+	BurnRate             *prometheus.GaugeVec
+	ErrorBudgetRemaining *prometheus.GaugeVec
+}
+
+func init() {
+	registerModule(GroupSLO, func(m *Metrics, factory promauto.Factory) {
+		m.SLO = newSLOMetrics(factory)
+	})
+}
+
+func newSLOMetrics(factory promauto.Factory) *SLOMetrics {
+	return &SLOMetrics{
+		Target: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_slo_target_ratio",
+				Help: "Target ratio of good events for each service level objective",
+			},
+			[]string{"slo"},
+		),
+		Compliance: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_slo_compliance_ratio",
+				Help: "Ratio of good events over the rolling SLO window",
+			},
+			[]string{"slo", "sli"}, // sli: latency/freshness
+		),
+		BurnRate: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_slo_error_budget_burn_rate",
+				Help: "Error budget burn rate over the rolling SLO window (1 = budget consumed exactly at the window end)",
+			},
+			[]string{"slo", "sli"},
+		),
+		ErrorBudgetRemaining: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_slo_error_budget_remaining_ratio",
+				Help: "Fraction of the error budget left over the rolling SLO window (negative when exhausted)",
+			},
+			[]string{"slo", "sli"},
+		),
+	}
+}
+
+// UpdateTarget sets the target ratio of an objective
+func (s *SLOMetrics) UpdateTarget(slo string, target float64) {
+	s.Target.WithLabelValues(slo).Set(target)
+}
+
+// UpdateIndicator sets compliance, burn rate and remaining error budget of an indicator
+func (s *SLOMetrics) UpdateIndicator(slo, sli string, compliance, burnRate, budgetRemaining float64) {
+	s.Compliance.WithLabelValues(slo, sli).Set(compliance)
+	s.BurnRate.WithLabelValues(slo, sli).Set(burnRate)
+	s.ErrorBudgetRemaining.WithLabelValues(slo, sli).Set(budgetRemaining)
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// WebSocketMetrics cubre la conexión WebSocket con Kraken
+type WebSocketMetrics struct {
+	ChannelDrops             *prometheus.CounterVec
+	ConnectionStatus         *prometheus.GaugeVec
+	ReconnectionAttempts     *prometheus.CounterVec
+	UnconfirmedSubscriptions prometheus.Gauge
+	SubscriptionRetries      *prometheus.CounterVec
+	Shards                   prometheus.Gauge
+	ShardConnectionStatus    *prometheus.GaugeVec
+	ShardPairs               *prometheus.GaugeVec
+	StandbyConnected         prometheus.Gauge
+	StandbyPromotions        prometheus.Counter
+	OversizedFrames          prometheus.Counter
+	QuietReconnects          prometheus.Counter
+	KrakenSystemStatus       *prometheus.GaugeVec
+}
+
+func init() {
+	registerModule(GroupWebSocket, func(m *Metrics, factory promauto.Factory) {
+		m.WebSocket = newWebSocketMetrics(factory)
+	})
+}
+
+func newWebSocketMetrics(factory promauto.Factory) *WebSocketMetrics {
+	return &WebSocketMetrics{
+		ChannelDrops: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_ws_channel_drops_total",
+				Help: "Total de actualizaciones de precio descartadas por canal lleno",
+			},
+			[]string{"pair", "policy"}, // policy: drop_oldest/drop_newest/block
+		),
+		ConnectionStatus: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_websocket_connection_status",
+				Help: "WebSocket connection status (1=connected, 0=disconnected)",
+			},
+			[]string{},
+		),
+		ReconnectionAttempts: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_websocket_reconnection_attempts_total",
+				Help: "Total number of WebSocket reconnection attempts",
+			},
+			[]string{"reason"}, // reason: startup/connection_lost/manual
+		),
+		UnconfirmedSubscriptions: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_websocket_unconfirmed_subscriptions",
+				Help: "Number of WebSocket subscriptions sent but not yet confirmed by Kraken",
+			},
+		),
+		SubscriptionRetries: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_websocket_subscription_retries_total",
+				Help: "Total number of WebSocket subscriptions re-sent after missing confirmation",
+			},
+			[]string{"pair"},
+		),
+		Shards: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_websocket_shards",
+				Help: "Number of WebSocket connections the ticker subscriptions are sharded across",
+			},
+		),
+		ShardConnectionStatus: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_websocket_shard_connection_status",
+				Help: "WebSocket connection status per shard (1=connected, 0=disconnected)",
+			},
+			[]string{"shard"},
+		),
+		ShardPairs: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_websocket_shard_pairs",
+				Help: "Number of pairs assigned to each WebSocket shard",
+			},
+			[]string{"shard"},
+		),
+		StandbyConnected: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_websocket_standby_connected",
+				Help: "Warm standby WebSocket connection status (1=connected and ready, 0=unavailable)",
+			},
+		),
+		StandbyPromotions: factory.NewCounter(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_websocket_standby_promotions_total",
+				Help: "Total number of times the warm standby WebSocket connection replaced a failed shard",
+			},
+		),
+		OversizedFrames: factory.NewCounter(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_websocket_oversized_frames_total",
+				Help: "Total number of Kraken WebSocket connections closed for a frame larger than exchange.kraken.max_frame_bytes",
+			},
+		),
+		QuietReconnects: factory.NewCounter(
+			prometheus.CounterOpts{
+				Name: "btc_ltp_websocket_quiet_reconnects_total",
+				Help: "Total number of Kraken WebSocket connections reconnected after timeouts.websocket.quiet_timeout without data or heartbeats",
+			},
+		),
+		KrakenSystemStatus: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "btc_ltp_kraken_system_status",
+				Help: "Kraken system status reported over the WebSocket (1 for the current status)",
+			},
+			[]string{"status"}, // status: online/maintenance/cancel_only/limit_only/post_only
+		),
+	}
+}
+
+// RecordChannelDrop incrementa contador de descartes por canal lleno
+func (ws *WebSocketMetrics) RecordChannelDrop(pair, policy string) {
+	ws.ChannelDrops.WithLabelValues(pair, policy).Inc()
+}
+
+// UpdateConnectionStatus updates WebSocket connection status
+func (ws *WebSocketMetrics) UpdateConnectionStatus(connected bool) {
+	status := 0.0
+	if connected {
+		status = 1.0
+	}
+	ws.ConnectionStatus.WithLabelValues().Set(status)
+}
+
+// RecordReconnectionAttempt records WebSocket reconnection attempts
+func (ws *WebSocketMetrics) RecordReconnectionAttempt(reason string) {
+	ws.ReconnectionAttempts.WithLabelValues(reason).Inc()
+}
+
+// UpdateUnconfirmedSubscriptions updates the unconfirmed subscriptions gauge
+func (ws *WebSocketMetrics) UpdateUnconfirmedSubscriptions(count int) {
+	ws.UnconfirmedSubscriptions.Set(float64(count))
+}
+
+// RecordSubscriptionRetry records a subscription re-sent after confirmation timeout
+func (ws *WebSocketMetrics) RecordSubscriptionRetry(pair string) {
+	ws.SubscriptionRetries.WithLabelValues(pair).Inc()
+}
+
+// UpdateShards updates the number of WebSocket shards
+func (ws *WebSocketMetrics) UpdateShards(count int) {
+	ws.Shards.Set(float64(count))
+}
+
+// UpdateShardStatus updates the connection status of a WebSocket shard
+func (ws *WebSocketMetrics) UpdateShardStatus(shard string, connected bool) {
+	status := 0.0
+	if connected {
+		status = 1.0
+	}
+	ws.ShardConnectionStatus.WithLabelValues(shard).Set(status)
+}
+
+// UpdateShardPairs updates the number of pairs assigned to a WebSocket shard
+func (ws *WebSocketMetrics) UpdateShardPairs(shard string, pairs int) {
+	ws.ShardPairs.WithLabelValues(shard).Set(float64(pairs))
+}
+
+// UpdateStandbyStatus updates the warm standby WebSocket connection status
+func (ws *WebSocketMetrics) UpdateStandbyStatus(connected bool) {
+	status := 0.0
+	if connected {
+		status = 1.0
+	}
+	ws.StandbyConnected.Set(status)
+}
+
+// RecordStandbyPromotion records the standby connection replacing a failed shard
+func (ws *WebSocketMetrics) RecordStandbyPromotion() {
+	ws.StandbyPromotions.Inc()
+}
+
+// RecordOversizedFrame records a Kraken connection closed for exceeding the frame size limit
+func (ws *WebSocketMetrics) RecordOversizedFrame() {
+	ws.OversizedFrames.Inc()
+}
+
+// RecordQuietReconnect records a Kraken connection reconnected for receiving no frames
+func (ws *WebSocketMetrics) RecordQuietReconnect() {
+	ws.QuietReconnects.Inc()
+}
+
+// UpdateKrakenSystemStatus marks status as the current Kraken system status
+func (ws *WebSocketMetrics) UpdateKrakenSystemStatus(status string) {
+	ws.KrakenSystemStatus.Reset()
+	ws.KrakenSystemStatus.WithLabelValues(status).Set(1)
+}
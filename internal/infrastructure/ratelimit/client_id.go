@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Estrategias de identificación de clientes (rate_limit.client_id.strategy)
+const (
+	StrategyRemoteAddr   = "remote_addr"
+	StrategyForwardedFor = "forwarded_for"
+	StrategyAPIKey       = "api_key"
+	StrategyHeader       = "header"
+)
+
+// DefaultAPIKeyHeader es el header leído por la estrategia api_key si no se configura otro
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// KeyAuthenticator verifica una API key y retorna su ID (entities.APIKeyID) si es válida
+type KeyAuthenticator func(apiKey string) (keyID string, ok bool)
+
+// ClientIdentifier decide a qué bucket se asigna cada request
+type ClientIdentifier struct {
+	strategy     string
+	header       string
+	trusted      []*net.IPNet
+	authenticate KeyAuthenticator
+}
+
+// NewClientIdentifier crea el identificador a partir de la configuración. Una
+// estrategia vacía equivale a forwarded_for.
+func NewClientIdentifier(cfg config.ClientIDConfig) (*ClientIdentifier, error) {
+	id := &ClientIdentifier{strategy: cfg.Strategy, header: cfg.Header}
+	switch id.strategy {
+	case "":
+		id.strategy = StrategyForwardedFor
+	case StrategyRemoteAddr, StrategyForwardedFor:
+	case StrategyAPIKey:
+		if id.header == "" {
+			id.header = DefaultAPIKeyHeader
+		}
+	case StrategyHeader:
+		if id.header == "" {
+			return nil, fmt.Errorf("client_id header is required with the %s strategy", StrategyHeader)
+		}
+	default:
+		return nil, fmt.Errorf("unknown client_id strategy: %s", cfg.Strategy)
+	}
+
+	for _, proxy := range cfg.TrustedProxies {
+		network, err := parseNetwork(proxy)
+		if err != nil {
+			return nil, err
+		}
+		id.trusted = append(id.trusted, network)
+	}
+	return id, nil
+}
+
+// WithKeyAuthenticator permite a la estrategia api_key asignar bucket por key
+// aunque el limiter corra antes del middleware de auth. header es el que lee auth
+// (auth.header_name): sólo ahí una key puede ser válida, así que reemplaza al de
+// client_id.header.
+func (c *ClientIdentifier) WithKeyAuthenticator(header string, authenticate KeyAuthenticator) *ClientIdentifier {
+	withAuth := *c
+	withAuth.authenticate = authenticate
+	if c.strategy == StrategyAPIKey && header != "" {
+		withAuth.header = header
+	}
+	return &withAuth
+}
+
+// parseNetwork acepta una IP suelta o un CIDR
+func parseNetwork(value string) (*net.IPNet, error) {
+	if ip := net.ParseIP(value); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+	}
+	return network, nil
+}
+
+// ClientID devuelve la clave del bucket del request
+func (c *ClientIdentifier) ClientID(r *http.Request) string {
+	switch c.strategy {
+	case StrategyRemoteAddr:
+		return remoteIP(r)
+	case StrategyAPIKey:
+		// Sólo las keys autenticadas tienen bucket propio: con keys inventadas
+		// un cliente obtendría uno nuevo por request. El client ID aparece en
+		// métricas y logs, así que nunca es la key en claro.
+		if keyID, ok := c.authenticatedKey(r); ok {
+			return "key:" + keyID
+		}
+	case StrategyHeader:
+		// El header lo fija el gateway; directo del cliente se podría rotar
+		if !c.isTrusted(remoteIP(r)) {
+			break
+		}
+		if value := strings.TrimSpace(r.Header.Get(c.header)); value != "" {
+			return "header:" + value
+		}
+	}
+	return c.forwardedIP(r)
+}
+
+// authenticatedKey retorna el ID de la API key ya verificada por el middleware de
+// auth o, si el limiter corre antes, por el KeyAuthenticator
+func (c *ClientIdentifier) authenticatedKey(r *http.Request) (string, bool) {
+	if keyID := entities.APIKeyIDFrom(r.Context()); keyID != "" {
+		return keyID, true
+	}
+	apiKey := r.Header.Get(c.header)
+	if c.authenticate == nil || apiKey == "" {
+		return "", false
+	}
+	return c.authenticate(apiKey)
+}
+
+// forwardedIP devuelve la IP del cliente según X-Forwarded-For / X-Real-IP, que
+// sólo se aceptan si el peer es un proxy confiable. X-Forwarded-For se recorre
+// de derecha a izquierda saltando proxies confiables: las entradas a la izquierda
+// del primer salto no confiable las controla el cliente.
+func (c *ClientIdentifier) forwardedIP(r *http.Request) string {
+	peer := remoteIP(r)
+	if !c.isTrusted(peer) {
+		return peer
+	}
+
+	if forwardedFor := r.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		hops := strings.Split(strings.Join(forwardedFor, ","), ",")
+		client := ""
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			client = hop
+			if !c.isTrusted(hop) {
+				break
+			}
+		}
+		if client != "" {
+			return client
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return peer
+}
+
+func (c *ClientIdentifier) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range c.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP devuelve la IP del peer TCP sin el puerto
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
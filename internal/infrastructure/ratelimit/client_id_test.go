@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(remoteAddr string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/ltp", nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+// validKeys es un KeyAuthenticator que acepta sólo las keys dadas
+func validKeys(keys ...string) KeyAuthenticator {
+	return func(apiKey string) (string, bool) {
+		for _, key := range keys {
+			if apiKey == key {
+				return entities.APIKeyID(apiKey), true
+			}
+		}
+		return "", false
+	}
+}
+
+func TestClientIdentifier_ForwardedFor(t *testing.T) {
+	id, err := NewClientIdentifier(config.ClientIDConfig{
+		Strategy:       StrategyForwardedFor,
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"untrusted peer ignores headers", "203.0.113.9:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.9"},
+		{"trusted proxy", "10.0.0.5:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "198.51.100.1"},
+		{"spoofed leftmost entry", "10.0.0.5:4000", map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1, 10.0.0.7"}, "198.51.100.1"},
+		{"single trusted ip", "192.168.1.1:4000", map[string]string{"X-Forwarded-For": "198.51.100.2"}, "198.51.100.2"},
+		{"x-real-ip", "10.0.0.5:4000", map[string]string{"X-Real-IP": "198.51.100.3"}, "198.51.100.3"},
+		{"no headers", "10.0.0.5:4000", nil, "10.0.0.5"},
+		{"ipv6 peer", "[2001:db8::1]:4000", nil, "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, id.ClientID(newRequest(tt.remoteAddr, tt.headers)))
+		})
+	}
+}
+
+func TestClientIdentifier_APIKeyReadsAuthHeader(t *testing.T) {
+	// client_id.header por defecto (X-API-Key) y auth con otro header
+	id, err := NewClientIdentifier(config.ClientIDConfig{Strategy: StrategyAPIKey})
+	require.NoError(t, err)
+	id = id.WithKeyAuthenticator("Authorization-Key", validKeys("secret-1"))
+
+	assert.Equal(t, "key:"+entities.APIKeyID("secret-1"),
+		id.ClientID(newRequest("203.0.113.9:4000", map[string]string{"Authorization-Key": "secret-1"})),
+		"the key is read from the header auth verifies")
+	assert.Equal(t, "203.0.113.9",
+		id.ClientID(newRequest("203.0.113.9:4000", map[string]string{DefaultAPIKeyHeader: "secret-1"})),
+		"auth never accepts a key sent in another header")
+
+	// Otras estrategias conservan su header
+	header, err := NewClientIdentifier(config.ClientIDConfig{Strategy: StrategyHeader, Header: "X-Tenant", TrustedProxies: []string{"10.0.0.0/8"}})
+	require.NoError(t, err)
+	header = header.WithKeyAuthenticator("Authorization-Key", validKeys("secret-1"))
+	assert.Equal(t, "header:acme", header.ClientID(newRequest("10.0.0.5:4000", map[string]string{"X-Tenant": "acme"})))
+}
+
+func TestClientIdentifier_Strategies(t *testing.T) {
+	trusted := []string{"10.0.0.0/8"}
+
+	remote, err := NewClientIdentifier(config.ClientIDConfig{Strategy: StrategyRemoteAddr, TrustedProxies: trusted})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.5", remote.ClientID(newRequest("10.0.0.5:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"})))
+
+	apiKey, err := NewClientIdentifier(config.ClientIDConfig{Strategy: StrategyAPIKey, TrustedProxies: trusted})
+	require.NoError(t, err)
+	apiKey = apiKey.WithKeyAuthenticator(DefaultAPIKeyHeader, validKeys("secret-1"))
+	first := apiKey.ClientID(newRequest("10.0.0.5:4000", map[string]string{"X-API-Key": "secret-1"}))
+	second := apiKey.ClientID(newRequest("10.0.0.6:4000", map[string]string{"X-API-Key": "secret-1"}))
+	assert.Equal(t, first, second, "the same key shares a bucket across IPs")
+	assert.Equal(t, "key:"+entities.APIKeyID("secret-1"), first, "the key is never exposed")
+	assert.Equal(t, "198.51.100.1", apiKey.ClientID(newRequest("10.0.0.5:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"})),
+		"requests without a key fall back to the client IP")
+	assert.Equal(t, "198.51.100.1", apiKey.ClientID(newRequest("10.0.0.5:4000", map[string]string{"X-API-Key": "guess", "X-Forwarded-For": "198.51.100.1"})),
+		"unknown keys fall back to the client IP")
+
+	authenticated := newRequest("203.0.113.9:4000", nil)
+	authenticated = authenticated.WithContext(entities.WithAPIKeyID(authenticated.Context(), "abc123"))
+	assert.Equal(t, "key:abc123", apiKey.ClientID(authenticated), "a key verified by the auth middleware is used as is")
+
+	header, err := NewClientIdentifier(config.ClientIDConfig{Strategy: StrategyHeader, Header: "X-Tenant", TrustedProxies: trusted})
+	require.NoError(t, err)
+	assert.Equal(t, "header:acme", header.ClientID(newRequest("10.0.0.5:4000", map[string]string{"X-Tenant": "acme"})))
+	assert.Equal(t, "203.0.113.9", header.ClientID(newRequest("203.0.113.9:4000", map[string]string{"X-Tenant": "acme"})),
+		"the header is only honored from a trusted proxy")
+
+	_, err = NewClientIdentifier(config.ClientIDConfig{Strategy: StrategyHeader})
+	assert.Error(t, err)
+	_, err = NewClientIdentifier(config.ClientIDConfig{Strategy: "cookie"})
+	assert.Error(t, err)
+	_, err = NewClientIdentifier(config.ClientIDConfig{TrustedProxies: []string{"not-an-ip"}})
+	assert.Error(t, err)
+}
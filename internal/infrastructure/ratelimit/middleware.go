@@ -1,14 +1,17 @@
 package ratelimit
 
 import (
+	"btc-ltp-service/internal/application/dto"
 	"btc-ltp-service/internal/infrastructure/config"
 	"btc-ltp-service/internal/infrastructure/logging"
 	"btc-ltp-service/internal/infrastructure/metrics"
-	"encoding/json"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"context"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
-	"strings"
+	"time"
 )
 
 // Configuration constants with sensible defaults
@@ -19,9 +22,10 @@ const (
 
 // RateLimitMiddleware provides rate limiting for HTTP requests
 type RateLimitMiddleware struct {
-	limiter   *RateLimiterCollection
-	skipPaths map[string]bool
-	enabled   bool
+	limiter    *RateLimiterCollection
+	identifier *ClientIdentifier
+	skipPaths  map[string]bool
+	enabled    bool
 }
 
 // NewRateLimitMiddleware creates a new rate limiting middleware (backward compatibility)
@@ -39,9 +43,10 @@ func NewRateLimitMiddleware() *RateLimitMiddleware {
 	}
 
 	return &RateLimitMiddleware{
-		limiter:   NewRateLimiterCollection(capacity, refillRate),
-		skipPaths: skipPaths,
-		enabled:   enabled,
+		limiter:    NewRateLimiterCollection(capacity, refillRate),
+		identifier: newClientIdentifierOrDefault(config.GetDefaultConfig().RateLimit.ClientID),
+		skipPaths:  skipPaths,
+		enabled:    enabled,
 	}
 }
 
@@ -60,12 +65,36 @@ func NewRateLimitMiddlewareWithConfig(rateLimitConfig config.RateLimitConfig) *R
 	}
 
 	return &RateLimitMiddleware{
-		limiter:   limiter,
-		skipPaths: skipPaths,
-		enabled:   rateLimitConfig.Enabled,
+		limiter:    limiter,
+		identifier: newClientIdentifierOrDefault(rateLimitConfig.ClientID),
+		skipPaths:  skipPaths,
+		enabled:    rateLimitConfig.Enabled,
 	}
 }
 
+// WithKeyAuthenticator verifica las API keys de la estrategia api_key; sin él
+// (o con una key inválida) los requests se agrupan por IP. header es el header de
+// la key en auth.
+func (rlm *RateLimitMiddleware) WithKeyAuthenticator(header string, authenticate KeyAuthenticator) *RateLimitMiddleware {
+	withAuth := *rlm
+	withAuth.identifier = rlm.identifier.WithKeyAuthenticator(header, authenticate)
+	return &withAuth
+}
+
+// newClientIdentifierOrDefault usa forwarded_for con los proxies por defecto si la
+// configuración es inválida (el validator la rechaza antes en el arranque normal)
+func newClientIdentifierOrDefault(clientIDConfig config.ClientIDConfig) *ClientIdentifier {
+	identifier, err := NewClientIdentifier(clientIDConfig)
+	if err == nil {
+		return identifier
+	}
+	logging.Warn(context.Background(), "Invalid rate limit client_id config, using defaults", logging.Fields{
+		"error": err.Error(),
+	})
+	identifier, _ = NewClientIdentifier(config.GetDefaultConfig().RateLimit.ClientID)
+	return identifier
+}
+
 // Handler returns the HTTP middleware handler
 func (rlm *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -82,7 +111,7 @@ func (rlm *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
 		}
 
 		ctx := r.Context()
-		clientID := getClientID(r)
+		clientID := rlm.identifier.ClientID(r)
 
 		// Check rate limit
 		allowed := rlm.limiter.Allow(clientID)
@@ -92,7 +121,13 @@ func (rlm *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
 		metrics.RecordRateLimitResult(allowed)
 		metrics.UpdateRateLimitTokens(clientID, float64(tokensRemaining))
 
+		// Headers para que los clientes se autorregulen
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rlm.limiter.Capacity()))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(tokensRemaining))
+
 		if !allowed {
+			metrics.RecordRateLimitRejection(clientID)
+
 			// Rate limit exceeded
 			logging.Warn(ctx, "Rate limit exceeded", logging.Fields{
 				"client_id":  clientID,
@@ -101,63 +136,31 @@ func (rlm *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
 				"user_agent": r.Header.Get("User-Agent"),
 			})
 
-			rlm.writeRateLimitError(w, r)
+			rlm.writeRateLimitError(w, r, retryAfterSeconds(rlm.limiter.RetryAfter(clientID)))
 			return
 		}
 
-		// Add rate limit headers to response
-		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(tokensRemaining))
-
 		// Continue with request
 		next.ServeHTTP(w, r)
 	})
 }
 
-// getClientID extracts a client identifier from the request
-// This is used as the key for rate limiting buckets
-func getClientID(r *http.Request) string {
-	// Try to get real IP from headers (reverse proxy/load balancer)
-	if xForwardedFor := r.Header.Get("X-Forwarded-For"); xForwardedFor != "" {
-		// X-Forwarded-For can contain multiple IPs, take the first one
-		parts := strings.Split(xForwardedFor, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
-		}
-	}
-
-	if xRealIP := r.Header.Get("X-Real-IP"); xRealIP != "" {
-		return xRealIP
-	}
-
-	// Fallback to RemoteAddr
-	remoteAddr := r.RemoteAddr
-
-	// Remove port if present (IPv4)
-	if idx := strings.LastIndex(remoteAddr, ":"); idx != -1 {
-		return remoteAddr[:idx]
-	}
-
-	return remoteAddr
+// retryAfterSeconds redondea hacia arriba la espera; Retry-After no admite fracciones
+// y 0 invitaría a reintentar de inmediato
+func retryAfterSeconds(wait time.Duration) int {
+	return max(int(math.Ceil(wait.Seconds())), 1)
 }
 
 // writeRateLimitError writes a rate limit exceeded error response
-func (rlm *RateLimitMiddleware) writeRateLimitError(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+func (rlm *RateLimitMiddleware) writeRateLimitError(w http.ResponseWriter, r *http.Request, retryAfter int) {
 	w.Header().Set("X-RateLimit-Remaining", "0")
-	w.Header().Set("Retry-After", "1") // Suggest retry after 1 second
-	w.WriteHeader(http.StatusTooManyRequests)
-
-	errorResponse := map[string]interface{}{
-		"error":   "RATE_LIMIT_EXCEEDED",
-		"message": "Rate limit exceeded. Please slow down your requests.",
-		"code":    http.StatusTooManyRequests,
-		"details": map[string]interface{}{
-			"retry_after_seconds": 1,
-			"limit_info":          "Please reduce your request rate and try again",
-		},
-	}
-
-	_ = json.NewEncoder(w).Encode(errorResponse)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	apierror.WriteWithDetails(w, r, http.StatusTooManyRequests, dto.CodeRateLimitExceeded,
+		"Rate limit exceeded. Please slow down your requests.",
+		map[string]any{
+			"retry_after_seconds": retryAfter,
+			"limit":               rlm.limiter.Capacity(),
+		})
 }
 
 // Stats returns rate limiting statistics
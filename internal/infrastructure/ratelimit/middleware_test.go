@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware_Headers(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer metrics.SetDefault(m)()
+
+	rlm := NewRateLimitMiddlewareWithConfig(config.RateLimitConfig{Enabled: true, Capacity: 2, RefillRate: 1})
+	handler := rlm.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/ltp", nil)
+		req.RemoteAddr = "10.0.0.1:5000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := serve()
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+	assert.Empty(t, first.Header().Get("Retry-After"))
+
+	serve()
+	rejected := serve()
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "2", rejected.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", rejected.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", rejected.Header().Get("Retry-After"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.RateLimit.RejectedTotal.WithLabelValues("10.0.0.1")))
+}
+
+func TestRateLimitMiddleware_RotatingInvalidKeysShareIPBucket(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer metrics.SetDefault(m)()
+
+	rlm := NewRateLimitMiddlewareWithConfig(config.RateLimitConfig{
+		Enabled: true, Capacity: 2, RefillRate: 1,
+		ClientID: config.ClientIDConfig{Strategy: StrategyAPIKey},
+	}).WithKeyAuthenticator(DefaultAPIKeyHeader, validKeys("secret-1"))
+	handler := rlm.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	serve := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/ltp", nil)
+		req.RemoteAddr = "203.0.113.9:5000"
+		req.Header.Set(DefaultAPIKeyHeader, apiKey)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusUnauthorized, serve(fmt.Sprintf("random-%d", i)))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve("random-2"), "a new invalid key does not get a new bucket")
+	assert.Equal(t, http.StatusUnauthorized, serve("secret-1"), "a valid key has its own bucket")
+}
+
+func TestTokenBucket_RetryAfter(t *testing.T) {
+	bucket := NewTokenBucket(1, 2)
+	assert.Equal(t, time.Duration(0), bucket.RetryAfter(), "a token is available")
+
+	require.True(t, bucket.Allow())
+	wait := bucket.RetryAfter()
+	assert.Greater(t, wait, time.Duration(0))
+	assert.LessOrEqual(t, wait, 500*time.Millisecond, "refill rate 2/s frees a token every 500ms")
+
+	assert.Equal(t, 1, retryAfterSeconds(wait))
+	assert.Equal(t, 3, retryAfterSeconds(2100*time.Millisecond))
+	assert.Equal(t, time.Second, NewTokenBucket(0, 0).RetryAfter())
+}
@@ -70,6 +70,27 @@ func (tb *TokenBucket) Tokens() int {
 	return tb.tokens
 }
 
+// RetryAfter returns how long until the next token is available (0 if one is
+// available now). With a non-positive refill rate the bucket never refills and
+// one second is suggested.
+func (tb *TokenBucket) RetryAfter() time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.refill()
+	if tb.tokens > 0 {
+		return 0
+	}
+	if tb.refillRate <= 0 {
+		return time.Second
+	}
+	wait := time.Second/time.Duration(tb.refillRate) - time.Since(tb.lastRefill)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
 // refill adds tokens based on elapsed time since last refill
 // Must be called with lock held
 func (tb *TokenBucket) refill() {
@@ -131,6 +152,17 @@ func (rlc *RateLimiterCollection) Tokens(clientID string) int {
 	return bucket.Tokens()
 }
 
+// RetryAfter returns how long the given client must wait for its next token
+func (rlc *RateLimiterCollection) RetryAfter(clientID string) time.Duration {
+	bucket := rlc.getBucket(clientID)
+	return bucket.RetryAfter()
+}
+
+// Capacity returns the bucket capacity (maximum burst) of every client
+func (rlc *RateLimiterCollection) Capacity() int {
+	return rlc.capacity
+}
+
 // getBucket gets or creates a token bucket for the client
 func (rlc *RateLimiterCollection) getBucket(clientID string) *TokenBucket {
 	// Try read lock first for better performance
@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Codecs de precios soportados por cache.codec
+const (
+	CodecJSON   = "json"
+	CodecBinary = "binary"
+)
+
+// Codec serializa las entidades de un EntityCache
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// jsonCodec es el Codec por defecto de EntityCache
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// NewPriceCodec devuelve el codec de precios de name (json o binary). Los dos
+// leen valores escritos por cualquiera de ellos, así que cambiar de codec con un
+// backend compartido no invalida los precios ya cacheados.
+func NewPriceCodec(name string) (interfaces.PriceCodec, error) {
+	switch name {
+	case "", CodecJSON:
+		return JSONPriceCodec{}, nil
+	case CodecBinary:
+		return BinaryPriceCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported cache codec: %s", name)
+	}
+}
+
+// JSONPriceCodec guarda los precios como JSON legible (p. ej. desde redis-cli)
+type JSONPriceCodec struct{}
+
+func (JSONPriceCodec) Name() string { return CodecJSON }
+
+func (JSONPriceCodec) Marshal(price *entities.Price) ([]byte, error) {
+	return json.Marshal(price)
+}
+
+func (JSONPriceCodec) Unmarshal(data []byte) (*entities.Price, error) {
+	return unmarshalPrice(data)
+}
+
+// BinaryPriceCodec guarda los precios en un formato binario compacto: menos bytes
+// y sin reflection ni parseo de texto por operación. Los instantes se leen en UTC.
+type BinaryPriceCodec struct{}
+
+func (BinaryPriceCodec) Name() string { return CodecBinary }
+
+func (BinaryPriceCodec) Marshal(price *entities.Price) ([]byte, error) {
+	return appendBinaryPrice(make([]byte, 0, 64+len(price.Pair)+len(price.Source)+len(price.Exchange)+len(price.Channel)), price), nil
+}
+
+func (BinaryPriceCodec) Unmarshal(data []byte) (*entities.Price, error) {
+	return unmarshalPrice(data)
+}
+
+// binaryPriceVersion abre cada valor binario; un JSON nunca empieza con ese byte
+const binaryPriceVersion byte = 0x01
+
+// Bits del byte de presencia de los instantes del formato binario
+const (
+	hasTimestamp byte = 1 << iota
+	hasExchangeTime
+	hasReceivedTime
+)
+
+var errTruncatedPrice = errors.New("truncated binary price")
+
+// unmarshalPrice decodifica un precio en cualquiera de los dos formatos
+func unmarshalPrice(data []byte) (*entities.Price, error) {
+	if len(data) > 0 && data[0] == binaryPriceVersion {
+		return decodeBinaryPrice(data[1:])
+	}
+	var price entities.Price
+	if err := json.Unmarshal(data, &price); err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func appendBinaryPrice(buf []byte, price *entities.Price) []byte {
+	var present byte
+	if !price.Timestamp.IsZero() {
+		present |= hasTimestamp
+	}
+	if !price.ExchangeTime.IsZero() {
+		present |= hasExchangeTime
+	}
+	if !price.ReceivedTime.IsZero() {
+		present |= hasReceivedTime
+	}
+
+	buf = append(buf, binaryPriceVersion, present)
+	buf = appendString(buf, price.Pair)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(price.Amount))
+	if present&hasTimestamp != 0 {
+		buf = binary.AppendVarint(buf, price.Timestamp.UnixNano())
+	}
+	if present&hasExchangeTime != 0 {
+		buf = binary.AppendVarint(buf, price.ExchangeTime.UnixNano())
+	}
+	if present&hasReceivedTime != 0 {
+		buf = binary.AppendVarint(buf, price.ReceivedTime.UnixNano())
+	}
+	buf = binary.AppendVarint(buf, int64(price.Age))
+	buf = appendString(buf, price.Source)
+	buf = appendString(buf, price.Exchange)
+	buf = appendString(buf, price.Channel)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(price.Bid))
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(price.Ask))
+	return buf
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// binaryReader lee los campos en orden; el primer error corta las lecturas siguientes
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func decodeBinaryPrice(data []byte) (*entities.Price, error) {
+	r := &binaryReader{data: data}
+	present := r.byte()
+	price := &entities.Price{Pair: r.string(), Amount: r.float()}
+	if present&hasTimestamp != 0 {
+		price.Timestamp = r.time()
+	}
+	if present&hasExchangeTime != 0 {
+		price.ExchangeTime = r.time()
+	}
+	if present&hasReceivedTime != 0 {
+		price.ReceivedTime = r.time()
+	}
+	price.Age = time.Duration(r.varint())
+	price.Source = r.string()
+	price.Exchange = r.string()
+	price.Channel = r.string()
+	price.Bid = r.float()
+	price.Ask = r.float()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return price, nil
+}
+
+func (r *binaryReader) byte() byte {
+	if r.err != nil || len(r.data) < 1 {
+		r.err = errTruncatedPrice
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errTruncatedPrice
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *binaryReader) float() float64 {
+	if r.err != nil || len(r.data) < 8 {
+		r.err = errTruncatedPrice
+		return 0
+	}
+	value := math.Float64frombits(binary.LittleEndian.Uint64(r.data))
+	r.data = r.data[8:]
+	return value
+}
+
+func (r *binaryReader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	length, n := binary.Uvarint(r.data)
+	if n <= 0 || uint64(len(r.data)-n) < length {
+		r.err = errTruncatedPrice
+		return ""
+	}
+	s := string(r.data[n : n+int(length)])
+	r.data = r.data[n+int(length):]
+	return s
+}
+
+func (r *binaryReader) time() time.Time {
+	nanos := r.varint()
+	if r.err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func codecTestPrice() *entities.Price {
+	observed := time.Date(2026, 1, 2, 3, 4, 5, 678900000, time.UTC)
+	return &entities.Price{
+		Pair:         "BTC/USD",
+		Amount:       50123.45678,
+		Timestamp:    observed,
+		Age:          1500 * time.Millisecond,
+		ExchangeTime: observed.Add(-time.Second),
+		ReceivedTime: observed.Add(time.Millisecond),
+		Source:       entities.PriceSourceWebSocket,
+		Exchange:     entities.ExchangeKraken,
+		Channel:      "ticker",
+		Bid:          50123.4,
+		Ask:          50123.5,
+	}
+}
+
+func TestPriceCodec_RoundTrip(t *testing.T) {
+	for _, name := range []string{CodecJSON, CodecBinary} {
+		t.Run(name, func(t *testing.T) {
+			codec, err := NewPriceCodec(name)
+			require.NoError(t, err)
+			assert.Equal(t, name, codec.Name())
+
+			data, err := codec.Marshal(codecTestPrice())
+			require.NoError(t, err)
+			got, err := codec.Unmarshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, codecTestPrice(), got)
+
+			// Sin instantes ni metadata opcional
+			data, err = codec.Marshal(&entities.Price{Pair: "ETH/USD", Amount: 3000})
+			require.NoError(t, err)
+			got, err = codec.Unmarshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, &entities.Price{Pair: "ETH/USD", Amount: 3000}, got)
+		})
+	}
+}
+
+func TestPriceCodec_ReadsEitherFormat(t *testing.T) {
+	jsonData, err := JSONPriceCodec{}.Marshal(codecTestPrice())
+	require.NoError(t, err)
+	binaryData, err := BinaryPriceCodec{}.Marshal(codecTestPrice())
+	require.NoError(t, err)
+	assert.Less(t, len(binaryData), len(jsonData))
+
+	got, err := BinaryPriceCodec{}.Unmarshal(jsonData)
+	require.NoError(t, err)
+	assert.Equal(t, codecTestPrice(), got)
+	got, err = JSONPriceCodec{}.Unmarshal(binaryData)
+	require.NoError(t, err)
+	assert.Equal(t, codecTestPrice(), got)
+}
+
+func TestPriceCodec_Errors(t *testing.T) {
+	_, err := NewPriceCodec("msgpack")
+	assert.Error(t, err)
+
+	data, err := BinaryPriceCodec{}.Marshal(codecTestPrice())
+	require.NoError(t, err)
+	for _, cut := range []int{1, 2, 5, 12, len(data) - 1} {
+		_, err := BinaryPriceCodec{}.Unmarshal(data[:cut])
+		assert.ErrorIs(t, err, errTruncatedPrice, "cut at %d", cut)
+	}
+}
+
+func TestPriceCacheAdapter_WithCodec(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCache()
+	adapter := NewPriceCache(backend, time.Minute).WithCodec(BinaryPriceCodec{})
+
+	require.NoError(t, adapter.Set(ctx, entities.NewPrice("BTC/USD", 50000, time.Now(), 0)))
+
+	raw, err := backend.Get(ctx, "price:BTC/USD")
+	require.NoError(t, err)
+	assert.Equal(t, binaryPriceVersion, raw[0], "stored in the binary format")
+
+	got, ok := adapter.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, 50000.0, got.Amount)
+}
+
+func BenchmarkPriceCodec_Marshal(b *testing.B) {
+	price := codecTestPrice()
+	for _, codec := range []interfaces.PriceCodec{JSONPriceCodec{}, BinaryPriceCodec{}} {
+		b.Run(codec.Name(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.Marshal(price); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPriceCodec_Unmarshal(b *testing.B) {
+	jsonData, err := json.Marshal(codecTestPrice())
+	if err != nil {
+		b.Fatal(err)
+	}
+	binaryData, err := BinaryPriceCodec{}.Marshal(codecTestPrice())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{CodecJSON: jsonData, CodecBinary: binaryData} {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := unmarshalPrice(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"sync"
+	"time"
+)
+
+// Prefijos de clave de cada tipo de entidad cacheada
+const (
+	priceKeyPrefix  = "price:"
+	statsKeyPrefix  = "stats:"
+	spreadKeyPrefix = "spread:"
+	candleKeyPrefix = "candle:"
+)
+
+// maxMemoizedKeys acota cuántas claves armadas recuerda cada EntityCache, para
+// que ids arbitrarios no hagan crecer la memoria sin límite
+const maxMemoizedKeys = 1024
+
+// typedStore lo implementan los backends que pueden guardar además el valor ya
+// decodificado (MemoryCache), evitando decodificar en cada lectura
+type typedStore interface {
+	SetTyped(ctx context.Context, key string, value string, typed any, ttl time.Duration) error
+	GetTyped(ctx context.Context, key string) (any, string, error)
+}
+
+// EntityCache almacena entidades de tipo T en cualquier interfaces.Cache (como JSON
+// salvo que se indique otro Codec) bajo la clave <prefix><id> con un TTL común. Cada tipo de entidad usa su propio
+// prefijo, así Flush de un tipo no toca las claves de los demás.
+type EntityCache[T any] struct {
+	backend   interfaces.Cache
+	prefix    string
+	ttl       time.Duration
+	id        func(T) string
+	normalize func(string) string
+	codec     Codec[T]
+
+	// typed es el backend si guarda valores decodificados; sólo se usa con clone
+	typed typedStore
+	clone func(T) T
+
+	// keys recuerda la clave armada de cada id para no normalizar ni concatenar
+	// en cada lectura
+	keysMu sync.RWMutex
+	keys   map[string]string
+}
+
+// NewEntityCache crea el cache; id devuelve el identificador con que Set guarda
+// cada entidad
+func NewEntityCache[T any](backend interfaces.Cache, prefix string, ttl time.Duration, id func(T) string) *EntityCache[T] {
+	c := &EntityCache[T]{
+		backend: backend,
+		prefix:  prefix,
+		ttl:     ttl,
+		id:      id,
+		codec:   jsonCodec[T]{},
+		keys:    make(map[string]string),
+	}
+	c.typed, _ = backend.(typedStore)
+	return c
+}
+
+// WithIDNormalizer normaliza los ids antes de armar la clave (p. ej.
+// entities.CanonicalPair, para que "btc-usd" y "BTC/USD" sean la misma entrada)
+func (c *EntityCache[T]) WithIDNormalizer(normalize func(string) string) *EntityCache[T] {
+	c.normalize = normalize
+	return c
+}
+
+// WithCodec reemplaza la serialización JSON de los valores
+func (c *EntityCache[T]) WithCodec(codec Codec[T]) *EntityCache[T] {
+	c.codec = codec
+	return c
+}
+
+// WithClone habilita, en backends que lo soportan (MemoryCache), guardar el valor
+// decodificado junto al serializado: Get devuelve clone del valor guardado sin
+// decodificar. clone debe devolver una copia que el llamador pueda modificar.
+func (c *EntityCache[T]) WithClone(clone func(T) T) *EntityCache[T] {
+	c.clone = clone
+	return c
+}
+
+// clonePointer copia superficialmente la entidad apuntada
+func clonePointer[E any](value *E) *E {
+	clone := *value
+	return &clone
+}
+
+// NewTickerStatsCache cachea TickerStats por par bajo stats:<pair>
+func NewTickerStatsCache(backend interfaces.Cache, ttl time.Duration) *EntityCache[*entities.TickerStats] {
+	return NewEntityCache(backend, statsKeyPrefix, ttl, func(stats *entities.TickerStats) string { return stats.Pair }).
+		WithIDNormalizer(entities.CanonicalPair).
+		WithClone(clonePointer)
+}
+
+// NewSpreadCache cachea el último Spread de cada par bajo spread:<pair>
+func NewSpreadCache(backend interfaces.Cache, ttl time.Duration) *EntityCache[*entities.Spread] {
+	return NewEntityCache(backend, spreadKeyPrefix, ttl, func(spread *entities.Spread) string { return spread.Pair }).
+		WithIDNormalizer(entities.CanonicalPair).
+		WithClone(clonePointer)
+}
+
+// NewCandleCache cachea velas bajo candle:<entities.CandleID>
+func NewCandleCache(backend interfaces.Cache, ttl time.Duration) *EntityCache[*entities.Candle] {
+	return NewEntityCache(backend, candleKeyPrefix, ttl, (*entities.Candle).ID).
+		WithClone(clonePointer)
+}
+
+func (c *EntityCache[T]) key(id string) string {
+	c.keysMu.RLock()
+	key, ok := c.keys[id]
+	c.keysMu.RUnlock()
+	if ok {
+		return key
+	}
+
+	key = id
+	if c.normalize != nil {
+		key = c.normalize(id)
+	}
+	key = c.prefix + key
+
+	c.keysMu.Lock()
+	if len(c.keys) < maxMemoizedKeys {
+		c.keys[id] = key
+	}
+	c.keysMu.Unlock()
+	return key
+}
+
+// Set guarda value bajo su id
+func (c *EntityCache[T]) Set(ctx context.Context, value T) error {
+	bytes, err := c.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	key := c.key(c.id(value))
+	if c.typed != nil && c.clone != nil {
+		return c.typed.SetTyped(ctx, key, string(bytes), c.clone(value), c.ttl)
+	}
+	return c.backend.Set(ctx, key, string(bytes), c.ttl)
+}
+
+// Get obtiene la entidad si existe, no expiró y se puede decodificar
+func (c *EntityCache[T]) Get(ctx context.Context, id string) (T, bool) {
+	var zero T
+	key := c.key(id)
+	if c.typed != nil && c.clone != nil {
+		typed, str, err := c.typed.GetTyped(ctx, key)
+		if err != nil {
+			return zero, false
+		}
+		if value, ok := typed.(T); ok {
+			return c.clone(value), true
+		}
+		return c.decode(str)
+	}
+
+	str, err := c.backend.Get(ctx, key)
+	if err != nil {
+		return zero, false
+	}
+	return c.decode(str)
+}
+
+func (c *EntityCache[T]) decode(str string) (T, bool) {
+	var zero T
+	value, err := c.codec.Unmarshal([]byte(str))
+	if err != nil {
+		return zero, false
+	}
+	return value, true
+}
+
+// GetMany devuelve las entidades existentes y la lista de ids faltantes
+func (c *EntityCache[T]) GetMany(ctx context.Context, ids []string) ([]T, []string) {
+	values := make([]T, 0, len(ids))
+	missing := make([]string, 0)
+	for _, id := range ids {
+		if value, ok := c.Get(ctx, id); ok {
+			values = append(values, value)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return values, missing
+}
+
+// Delete invalida las entidades de los ids indicados
+func (c *EntityCache[T]) Delete(ctx context.Context, ids ...string) error {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, c.key(id))
+	}
+	return c.backend.DeleteMany(ctx, keys)
+}
+
+// Flush invalida todas las entidades de este tipo sin tocar otras claves del backend
+func (c *EntityCache[T]) Flush(ctx context.Context) (int, error) {
+	return c.backend.Flush(ctx, c.prefix)
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityCache_SetGetByNormalizedID(t *testing.T) {
+	ctx := context.Background()
+	stats := NewTickerStatsCache(NewMemoryCache(), time.Minute)
+
+	require.NoError(t, stats.Set(ctx, &entities.TickerStats{Pair: "XBTUSD", High: 51000, Low: 49000, Trades: 42}))
+
+	got, ok := stats.Get(ctx, "btc-usd")
+	require.True(t, ok, "ids are normalized on both Set and Get")
+	assert.Equal(t, 51000.0, got.High)
+	assert.Equal(t, int64(42), got.Trades)
+
+	values, missing := stats.GetMany(ctx, []string{"BTC/USD", "ETH/USD"})
+	assert.Len(t, values, 1)
+	assert.Equal(t, []string{"ETH/USD"}, missing)
+}
+
+func TestEntityCache_TypesDoNotShareKeys(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCache()
+	prices := NewPriceCache(backend, time.Minute)
+	spreads := NewSpreadCache(backend, time.Minute)
+	candles := NewCandleCache(backend, time.Minute)
+
+	openTime := time.Unix(1700000000, 0)
+	require.NoError(t, prices.Set(ctx, entities.NewPrice("BTC/USD", 50000, time.Now(), 0)))
+	require.NoError(t, spreads.Set(ctx, &entities.Spread{Pair: "BTC/USD", Bid: 49999, Ask: 50001}))
+	require.NoError(t, candles.Set(ctx, &entities.Candle{Pair: "BTC/USD", Interval: time.Minute, OpenTime: openTime, Close: 50000}))
+
+	spread, ok := spreads.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, 50000.0, spread.Mid())
+	candle, ok := candles.Get(ctx, entities.CandleID("XBT/USD", time.Minute, openTime))
+	require.True(t, ok)
+	assert.Equal(t, 50000.0, candle.Close)
+
+	removed, err := spreads.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed, "Flush only removes its own entity type")
+	_, ok = prices.Get(ctx, "BTC/USD")
+	assert.True(t, ok)
+	_, ok = candles.Get(ctx, candle.ID())
+	assert.True(t, ok)
+}
+
+func TestEntityCache_DeleteAndUndecodableValues(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCache()
+	spreads := NewSpreadCache(backend, time.Minute)
+
+	require.NoError(t, spreads.Set(ctx, &entities.Spread{Pair: "ETH/USD", Bid: 1, Ask: 2}))
+	require.NoError(t, spreads.Delete(ctx, "eth_usd"))
+	_, ok := spreads.Get(ctx, "ETH/USD")
+	assert.False(t, ok)
+
+	require.NoError(t, backend.Set(ctx, "spread:BTC/USD", "{not json", time.Minute))
+	_, ok = spreads.Get(ctx, "BTC/USD")
+	assert.False(t, ok, "undecodable values are treated as missing")
+}
@@ -1,8 +1,12 @@
 package cache
 
-import "errors"
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"errors"
+)
 
 var (
-	ErrKeyNotFound = errors.New("key not found")
-	ErrKeyExpired  = errors.New("key expired")
+	ErrKeyNotFound = interfaces.ErrCacheMiss
+	ErrKeyExpired  = interfaces.ErrCacheExpired
+	ErrStaleWrite  = errors.New("stale write rejected")
 )
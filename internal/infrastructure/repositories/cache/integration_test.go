@@ -67,7 +67,8 @@ func TestIntegration_PriceAdapterWithMemoryCache(t *testing.T) {
 	})
 
 	t.Run("multiple prices management", func(t *testing.T) {
-		// Set multiple prices
+		// Set multiple prices (timestamp renovado: Set rechaza escrituras más antiguas)
+		btcPrice.Timestamp = time.Now()
 		err := adapter.Set(ctx, btcPrice)
 		assert.NoError(t, err)
 
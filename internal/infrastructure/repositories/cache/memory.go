@@ -3,13 +3,16 @@ package cache
 import (
 	"btc-ltp-service/internal/domain/interfaces"
 	"context"
+	"strings"
 	"sync"
 	"time"
 )
 
 // cacheItem representa un elemento en el cache con su valor y tiempo de expiración
 type cacheItem struct {
-	value     string
+	value string
+	// typed es el valor ya decodificado guardado por SetTyped (nil si vino de Set)
+	typed     any
 	expiresAt time.Time
 }
 
@@ -33,25 +36,53 @@ func NewMemoryCache() interfaces.Cache {
 
 // Get obtiene un valor del cache
 func (c *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	item, err := c.get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return item.value, nil
+}
+
+// GetTyped obtiene el valor decodificado que guardó SetTyped junto con su forma
+// serializada; typed es nil si la clave se escribió con Set
+func (c *MemoryCache) GetTyped(ctx context.Context, key string) (any, string, error) {
+	item, err := c.get(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	return item.typed, item.value, nil
+}
+
+func (c *MemoryCache) get(ctx context.Context, key string) (*cacheItem, error) {
 	c.mu.RLock()
 	item, exists := c.items[key]
 	c.mu.RUnlock()
 
 	if !exists {
-		return "", ErrKeyNotFound
+		return nil, ErrKeyNotFound
 	}
 
 	if item.isExpired() {
 		// Eliminar clave expirada para evitar fuga de memoria
 		_ = c.Delete(ctx, key)
-		return "", ErrKeyExpired
+		return nil, ErrKeyExpired
 	}
 
-	return item.value, nil
+	return item, nil
 }
 
 // Set almacena un valor en el cache con TTL y realiza una limpieza ligera de expirados
 func (c *MemoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return c.set(key, &cacheItem{value: value}, ttl)
+}
+
+// SetTyped almacena value como Set y además el valor decodificado typed, que
+// GetTyped devuelve sin volver a decodificar. typed no debe modificarse después.
+func (c *MemoryCache) SetTyped(ctx context.Context, key string, value string, typed any, ttl time.Duration) error {
+	return c.set(key, &cacheItem{value: value, typed: typed}, ttl)
+}
+
+func (c *MemoryCache) set(key string, item *cacheItem, ttl time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -63,11 +94,8 @@ func (c *MemoryCache) Set(ctx context.Context, key string, value string, ttl tim
 		}
 	}
 
-	expiresAt := now.Add(ttl)
-	c.items[key] = &cacheItem{
-		value:     value,
-		expiresAt: expiresAt,
-	}
+	item.expiresAt = now.Add(ttl)
+	c.items[key] = item
 
 	return nil
 }
@@ -81,6 +109,32 @@ func (c *MemoryCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// DeleteMany elimina varias claves del cache bajo un único lock
+func (c *MemoryCache) DeleteMany(ctx context.Context, keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+// Flush elimina las claves con el prefijo dado (todas si prefix es "")
+func (c *MemoryCache) Flush(ctx context.Context, prefix string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
 // Size retorna el número de elementos en el cache (método auxiliar para debugging)
 func (c *MemoryCache) Size() int {
 	c.mu.RLock()
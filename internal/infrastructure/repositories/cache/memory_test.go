@@ -305,6 +305,55 @@ func TestMemoryCache_Delete(t *testing.T) {
 	}
 }
 
+func TestMemoryCache_DeleteMany(t *testing.T) {
+	cache := NewMemoryCache().(*MemoryCache)
+	ctx := context.Background()
+	for _, key := range []string{"a", "b", "c"} {
+		_ = cache.Set(ctx, key, "value", 5*time.Minute)
+	}
+
+	assert.NoError(t, cache.DeleteMany(ctx, []string{"a", "c", "missing"}))
+	assert.Equal(t, 1, cache.Size())
+	_, err := cache.Get(ctx, "b")
+	assert.NoError(t, err)
+
+	assert.NoError(t, cache.DeleteMany(ctx, nil))
+	assert.Equal(t, 1, cache.Size())
+}
+
+func TestMemoryCache_Flush(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		wantRemoved int
+		wantKept    []string
+	}{
+		{name: "prefix", prefix: "price:", wantRemoved: 2, wantKept: []string{"leader:lock"}},
+		{name: "all", prefix: "", wantRemoved: 3},
+		{name: "no match", prefix: "state:", wantRemoved: 0, wantKept: []string{"price:BTC/USD", "price:ETH/USD", "leader:lock"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewMemoryCache().(*MemoryCache)
+			ctx := context.Background()
+			for _, key := range []string{"price:BTC/USD", "price:ETH/USD", "leader:lock"} {
+				_ = cache.Set(ctx, key, "value", 5*time.Minute)
+			}
+
+			removed, err := cache.Flush(ctx, tt.prefix)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRemoved, removed)
+			assert.Equal(t, len(tt.wantKept), cache.Size())
+			for _, key := range tt.wantKept {
+				_, err := cache.Get(ctx, key)
+				assert.NoError(t, err, key)
+			}
+		})
+	}
+}
+
 func TestMemoryCache_Size(t *testing.T) {
 	tests := []struct {
 		name      string
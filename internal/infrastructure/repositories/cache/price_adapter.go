@@ -3,51 +3,67 @@ package cache
 import (
 	"btc-ltp-service/internal/domain/entities"
 	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/metrics"
 	"context"
-	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 )
 
 // PriceCacheAdapter permite almacenar entidades Price en cualquier interfaces.Cache
-// utilizando la clave price:<pair> y TTL configurable.
+// utilizando la clave price:<pair> y TTL configurable. Sobre el EntityCache común
+// agrega el compare-and-set por antigüedad y el cálculo de Age al leer.
 type PriceCacheAdapter struct {
-	backend interfaces.Cache
-	ttl     time.Duration
+	cache *EntityCache[*entities.Price]
+
+	// writeMu serializa el compare-and-set de Set dentro del proceso
+	writeMu sync.Mutex
 }
 
 // NewPriceCache crea un nuevo adaptador.
 func NewPriceCache(backend interfaces.Cache, ttl time.Duration) *PriceCacheAdapter {
-	return &PriceCacheAdapter{
-		backend: backend,
-		ttl:     ttl,
-	}
+	cache := NewEntityCache(backend, priceKeyPrefix, ttl, func(price *entities.Price) string { return price.Pair }).
+		WithIDNormalizer(entities.CanonicalPair).
+		WithClone(clonePointer)
+	return &PriceCacheAdapter{cache: cache}
+}
+
+// WithCodec cambia la serialización de los precios (ver NewPriceCodec)
+func (p *PriceCacheAdapter) WithCodec(codec interfaces.PriceCodec) *PriceCacheAdapter {
+	p.cache.WithCodec(codec)
+	return p
 }
 
 func (p *PriceCacheAdapter) key(pair string) string {
-	return fmt.Sprintf("price:%s", pair)
+	return p.cache.key(pair)
 }
 
-// Set guarda el precio para un par.
+// Set guarda el precio para un par sólo si no es más antiguo que el cacheado
+// (compare-and-set según entities.Price.OlderThan). Una escritura fuera de orden,
+// p. ej. un precio REST que llega después de un tick WebSocket más reciente,
+// retorna ErrStaleWrite.
+// La comparación es atómica dentro del proceso; con un backend compartido entre
+// instancias sigue siendo posible una carrera entre lectura y escritura.
 func (p *PriceCacheAdapter) Set(ctx context.Context, price *entities.Price) error {
-	bytes, err := json.Marshal(price)
-	if err != nil {
-		return err
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if current, ok := p.Get(ctx, price.Pair); ok && price.OlderThan(current) {
+		metrics.RecordCacheStaleWriteRejected(price.Pair)
+		return fmt.Errorf("%w: %s at %s is older than cached %s", ErrStaleWrite, price.Pair,
+			price.Timestamp.Format(time.RFC3339Nano), current.Timestamp.Format(time.RFC3339Nano))
 	}
-	return p.backend.Set(ctx, p.key(price.Pair), string(bytes), p.ttl)
+	return p.cache.Set(ctx, price)
 }
 
 // Get obtiene el precio si existe y no expiró.
 func (p *PriceCacheAdapter) Get(ctx context.Context, pair string) (*entities.Price, bool) {
-	str, err := p.backend.Get(ctx, p.key(pair))
-	if err != nil {
+	price, ok := p.cache.Get(ctx, pair)
+	if !ok || price == nil {
 		return nil, false
 	}
-	var price entities.Price
-	if err := json.Unmarshal([]byte(str), &price); err != nil {
-		return nil, false
-	}
-	return &price, true
+	price.Age = price.AgeAt(time.Now())
+	return price, true
 }
 
 // GetMany devuelve los precios existentes y la lista de pares faltantes.
@@ -63,3 +79,13 @@ func (p *PriceCacheAdapter) GetMany(ctx context.Context, pairs []string) ([]*ent
 	}
 	return prices, missing
 }
+
+// Delete invalida los precios cacheados de los pares indicados
+func (p *PriceCacheAdapter) Delete(ctx context.Context, pairs ...string) error {
+	return p.cache.Delete(ctx, pairs...)
+}
+
+// Flush invalida todos los precios cacheados sin tocar otras claves del backend
+func (p *PriceCacheAdapter) Flush(ctx context.Context) (int, error) {
+	return p.cache.Flush(ctx)
+}
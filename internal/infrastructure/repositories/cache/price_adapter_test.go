@@ -33,6 +33,16 @@ func (m *MockCache) Delete(ctx context.Context, key string) error {
 	return args.Error(0)
 }
 
+func (m *MockCache) DeleteMany(ctx context.Context, keys []string) error {
+	args := m.Called(ctx, keys)
+	return args.Error(0)
+}
+
+func (m *MockCache) Flush(ctx context.Context, prefix string) (int, error) {
+	args := m.Called(ctx, prefix)
+	return args.Int(0), args.Error(1)
+}
+
 func TestNewPriceCache(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -73,8 +83,8 @@ func TestNewPriceCache(t *testing.T) {
 				assert.Nil(t, adapter)
 			} else {
 				assert.NotNil(t, adapter)
-				assert.Equal(t, tt.backend, adapter.backend)
-				assert.Equal(t, tt.ttl, adapter.ttl)
+				assert.Equal(t, tt.backend, adapter.cache.backend)
+				assert.Equal(t, tt.ttl, adapter.cache.ttl)
 			}
 		})
 	}
@@ -206,6 +216,8 @@ func TestPriceCacheAdapter_Set(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCache := &MockCache{}
+			// Set consulta el precio actual para el compare-and-set
+			mockCache.On("Get", mock.Anything, mock.Anything).Return("", ErrKeyNotFound).Maybe()
 			if tt.setupMock != nil {
 				tt.setupMock(mockCache)
 			}
@@ -551,3 +563,134 @@ func TestPriceCacheAdapter_ContextHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestPriceCacheAdapter_Set_RejectsOutOfOrderWrites(t *testing.T) {
+	adapter := NewPriceCache(NewMemoryCache(), time.Minute)
+	ctx := context.Background()
+	now := time.Now()
+
+	newer := &entities.Price{Pair: "BTC/USD", Amount: 50100, Timestamp: now}
+	older := &entities.Price{Pair: "BTC/USD", Amount: 49900, Timestamp: now.Add(-2 * time.Second)}
+	same := &entities.Price{Pair: "BTC/USD", Amount: 50200, Timestamp: now}
+
+	assert.NoError(t, adapter.Set(ctx, newer))
+
+	err := adapter.Set(ctx, older)
+	assert.ErrorIs(t, err, ErrStaleWrite)
+	cached, ok := adapter.Get(ctx, "BTC/USD")
+	assert.True(t, ok)
+	assert.Equal(t, 50100.0, cached.Amount, "older write must not overwrite newer price")
+
+	// Un timestamp igual no es más antiguo y reemplaza la entrada
+	assert.NoError(t, adapter.Set(ctx, same))
+	cached, _ = adapter.Get(ctx, "BTC/USD")
+	assert.Equal(t, 50200.0, cached.Amount)
+
+	// Otros pares no se ven afectados
+	assert.NoError(t, adapter.Set(ctx, &entities.Price{Pair: "ETH/USD", Amount: 3000, Timestamp: now.Add(-time.Hour)}))
+}
+
+func TestPriceCacheAdapter_Set_ComparesReceivedTimeWithSecondPrecision(t *testing.T) {
+	adapter := NewPriceCache(NewMemoryCache(), time.Minute)
+	ctx := context.Background()
+	second := time.Now().Truncate(time.Second)
+
+	tick := &entities.Price{Pair: "BTC/USD", Amount: 50100, Timestamp: second.Add(600 * time.Millisecond),
+		ExchangeTime: second.Add(600 * time.Millisecond), ReceivedTime: second.Add(650 * time.Millisecond)}
+	assert.NoError(t, adapter.Set(ctx, tick))
+
+	// REST del mismo segundo (header Date truncado) recibido después del tick
+	rest := &entities.Price{Pair: "BTC/USD", Amount: 50200, Timestamp: second,
+		ExchangeTime: second, ReceivedTime: second.Add(800 * time.Millisecond)}
+	assert.NoError(t, adapter.Set(ctx, rest), "a REST price received later is not stale")
+
+	late := &entities.Price{Pair: "BTC/USD", Amount: 50000, Timestamp: second,
+		ExchangeTime: second, ReceivedTime: second.Add(700 * time.Millisecond)}
+	assert.ErrorIs(t, adapter.Set(ctx, late), ErrStaleWrite, "without sub-second exchange times ReceivedTime decides")
+
+	// Con precisión menor al segundo en ambos decide la hora del exchange, aunque
+	// el tick más nuevo se haya recibido antes
+	assert.NoError(t, adapter.Set(ctx, &entities.Price{Pair: "ETH/USD", Amount: 3001,
+		ExchangeTime: second.Add(300 * time.Millisecond), ReceivedTime: second.Add(400 * time.Millisecond)}))
+	assert.ErrorIs(t, adapter.Set(ctx, &entities.Price{Pair: "ETH/USD", Amount: 3000,
+		ExchangeTime: second.Add(200 * time.Millisecond), ReceivedTime: second.Add(500 * time.Millisecond)}), ErrStaleWrite)
+	cached, ok := adapter.Get(ctx, "ETH/USD")
+	assert.True(t, ok)
+	assert.Equal(t, 3001.0, cached.Amount)
+}
+
+func TestPriceCacheAdapter_DeleteAndFlush(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCache()
+	adapter := NewPriceCache(backend, time.Minute)
+	now := time.Now()
+	for _, pair := range []string{"BTC/USD", "ETH/USD", "LTC/USD"} {
+		assert.NoError(t, adapter.Set(ctx, &entities.Price{Pair: pair, Amount: 1, Timestamp: now}))
+	}
+	assert.NoError(t, backend.Set(ctx, "state:other", "keep", time.Minute))
+
+	assert.NoError(t, adapter.Delete(ctx, "BTC/USD"))
+	_, ok := adapter.Get(ctx, "BTC/USD")
+	assert.False(t, ok)
+
+	removed, err := adapter.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	_, missing := adapter.GetMany(ctx, []string{"ETH/USD", "LTC/USD"})
+	assert.Len(t, missing, 2)
+
+	val, err := backend.Get(ctx, "state:other")
+	assert.NoError(t, err)
+	assert.Equal(t, "keep", val)
+}
+
+func TestPriceCacheAdapter_MemoryReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryCache()
+	adapter := NewPriceCache(backend, time.Minute)
+
+	price := entities.NewPrice("BTC/USD", 50000, time.Now(), 0)
+	assert.NoError(t, adapter.Set(ctx, price))
+	price.Amount = 1
+
+	first, ok := adapter.Get(ctx, "btc-usd")
+	assert.True(t, ok)
+	assert.Equal(t, 50000.0, first.Amount, "Set keeps its own copy")
+	first.Amount = 2
+	second, _ := adapter.Get(ctx, "BTC/USD")
+	assert.Equal(t, 50000.0, second.Amount, "Get returns copies")
+
+	// Lo escrito con Set plano se sigue decodificando
+	raw, _ := json.Marshal(&entities.Price{Pair: "ETH/USD", Amount: 3000, Timestamp: time.Now()})
+	assert.NoError(t, backend.Set(ctx, "price:ETH/USD", string(raw), time.Minute))
+	eth, ok := adapter.Get(ctx, "ETH/USD")
+	assert.True(t, ok)
+	assert.Equal(t, 3000.0, eth.Amount)
+}
+
+func BenchmarkPriceCacheAdapter_Get(b *testing.B) {
+	ctx := context.Background()
+	backends := map[string]interfaces.Cache{
+		"memory":       NewMemoryCache(),
+		"string_store": &stringOnlyCache{NewMemoryCache()},
+	}
+	for name, backend := range backends {
+		adapter := NewPriceCache(backend, time.Minute)
+		if err := adapter.Set(ctx, entities.NewPrice("BTC/USD", 50000, time.Now(), 0)); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, ok := adapter.Get(ctx, "BTC/USD"); !ok {
+					b.Fatal("price not cached")
+				}
+			}
+		})
+	}
+}
+
+// stringOnlyCache oculta SetTyped/GetTyped, como un backend remoto
+type stringOnlyCache struct {
+	interfaces.Cache
+}
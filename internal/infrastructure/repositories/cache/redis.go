@@ -3,11 +3,19 @@ package cache
 import (
 	"btc-ltp-service/internal/domain/interfaces"
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// flushScanCount is the SCAN batch hint used by Flush
+const flushScanCount = 500
+
+// redisGlobEscaper escapes glob metacharacters so a prefix matches literally
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
 // RedisCache implements the Cache interface using Redis
 type RedisCache struct {
 	client *redis.Client
@@ -55,6 +63,41 @@ func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// DeleteMany removes several keys from Redis in a single DEL
+func (r *RedisCache) DeleteMany(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// Flush removes the keys starting with prefix (all keys if prefix is empty).
+// It iterates with SCAN instead of FLUSHDB so unrelated keys sharing the
+// database (state, leader election) survive a scoped flush.
+func (r *RedisCache) Flush(ctx context.Context, prefix string) (int, error) {
+	pattern := redisGlobEscaper.Replace(prefix) + "*"
+
+	removed := 0
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, flushScanCount).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to scan keys with prefix %q: %w", prefix, err)
+		}
+		if len(keys) > 0 {
+			n, err := r.client.Del(ctx, keys...).Result()
+			if err != nil {
+				return removed, fmt.Errorf("failed to delete keys with prefix %q: %w", prefix, err)
+			}
+			removed += int(n)
+		}
+		cursor = next
+		if cursor == 0 {
+			return removed, nil
+		}
+	}
+}
+
 // Ping checks if Redis connection is alive
 func (r *RedisCache) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
@@ -0,0 +1,23 @@
+package state
+
+import (
+	"strings"
+
+	// Drivers de database/sql del backend sql, ambos Go puro: "sqlite" y "pgx"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// sqlDriverName traduce el driver configurado (state.sql.driver) al nombre con el
+// que se registra el driver enlazado: sqlite3 usa modernc.org/sqlite y postgres
+// usa pgx
+func sqlDriverName(driver string) string {
+	switch strings.ToLower(driver) {
+	case "sqlite", "sqlite3":
+		return "sqlite"
+	case "postgres", "pgx":
+		return "pgx"
+	default:
+		return driver
+	}
+}
@@ -0,0 +1,9 @@
+package state
+
+import "errors"
+
+var (
+	ErrKeyNotFound        = errors.New("state key not found")
+	ErrInvalidNamespace   = errors.New("state namespace cannot be empty")
+	ErrUnsupportedBackend = errors.New("unsupported state backend")
+)
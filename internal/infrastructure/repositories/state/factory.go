@@ -0,0 +1,110 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BackendType represents the type of state repository implementation
+type BackendType string
+
+const (
+	BackendMemory BackendType = "memory"
+	BackendRedis  BackendType = "redis"
+	BackendSQL    BackendType = "sql"
+)
+
+// Config holds state repository configuration options
+type Config struct {
+	Backend       BackendType
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	SQLDriver     string
+	SQLDSN        string
+}
+
+// Factory provides methods to create state repository instances
+type Factory struct{}
+
+// NewFactory creates a new state repository factory
+func NewFactory() *Factory {
+	return &Factory{}
+}
+
+// Create creates a state repository based on configuration
+func (f *Factory) Create(ctx context.Context, config Config) (interfaces.StateRepository, error) {
+	switch config.Backend {
+	case BackendMemory, "":
+		logging.Info(ctx, "Creating memory state repository", logging.Fields{
+			"type": "memory",
+		})
+		return NewMemoryStateRepository(), nil
+
+	case BackendRedis:
+		logging.Info(ctx, "Creating Redis state repository", logging.Fields{
+			"type":     "redis",
+			"addr":     config.RedisAddr,
+			"database": config.RedisDB,
+		})
+		return f.createRedis(ctx, config)
+
+	case BackendSQL:
+		logging.Info(ctx, "Creating SQL state repository", logging.Fields{
+			"type":   "sql",
+			"driver": config.SQLDriver,
+		})
+		return f.createSQL(ctx, config)
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBackend, config.Backend)
+	}
+}
+
+// createRedis creates and tests the Redis connection
+func (f *Factory) createRedis(ctx context.Context, config Config) (interfaces.StateRepository, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     config.RedisAddr,
+		Password: config.RedisPassword,
+		DB:       config.RedisDB,
+	})
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := rdb.Ping(pingCtx).Err(); err != nil {
+		_ = rdb.Close()
+		return nil, fmt.Errorf("failed to connect to Redis state backend at %s: %w", config.RedisAddr, err)
+	}
+
+	return NewRedisStateRepositoryWithClient(rdb), nil
+}
+
+// createSQL opens the database and runs the schema migration
+func (f *Factory) createSQL(ctx context.Context, config Config) (interfaces.StateRepository, error) {
+	db, err := sql.Open(sqlDriverName(config.SQLDriver), config.SQLDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s state database: %w", config.SQLDriver, err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(pingCtx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to %s state database: %w", config.SQLDriver, err)
+	}
+
+	repo, err := NewSQLStateRepository(ctx, db, config.SQLDriver)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"sync"
+)
+
+// MemoryStateRepository implementa StateRepository en memoria local.
+// No sobrevive reinicios; útil para desarrollo, tests y despliegues de una sola réplica.
+type MemoryStateRepository struct {
+	namespaces map[string]map[string]string
+	mu         sync.RWMutex
+}
+
+// NewMemoryStateRepository crea un nuevo repositorio de estado en memoria
+func NewMemoryStateRepository() interfaces.StateRepository {
+	return &MemoryStateRepository{
+		namespaces: make(map[string]map[string]string),
+	}
+}
+
+// Get obtiene un valor del namespace indicado
+func (m *MemoryStateRepository) Get(ctx context.Context, namespace, key string) (string, error) {
+	if namespace == "" {
+		return "", ErrInvalidNamespace
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, ok := m.namespaces[namespace][key]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// Put guarda (o reemplaza) un valor en el namespace indicado
+func (m *MemoryStateRepository) Put(ctx context.Context, namespace, key string, value string) error {
+	if namespace == "" {
+		return ErrInvalidNamespace
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ns, ok := m.namespaces[namespace]
+	if !ok {
+		ns = make(map[string]string)
+		m.namespaces[namespace] = ns
+	}
+	ns[key] = value
+	return nil
+}
+
+// Delete elimina un valor del namespace indicado
+func (m *MemoryStateRepository) Delete(ctx context.Context, namespace, key string) error {
+	if namespace == "" {
+		return ErrInvalidNamespace
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.namespaces[namespace], key)
+	return nil
+}
+
+// List retorna una copia de todos los valores del namespace
+func (m *MemoryStateRepository) List(ctx context.Context, namespace string) (map[string]string, error) {
+	if namespace == "" {
+		return nil, ErrInvalidNamespace
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string]string, len(m.namespaces[namespace]))
+	for k, v := range m.namespaces[namespace] {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// Close no requiere liberar recursos en memoria
+func (m *MemoryStateRepository) Close() error {
+	return nil
+}
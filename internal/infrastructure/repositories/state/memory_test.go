@@ -0,0 +1,116 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStateRepository_PutGet(t *testing.T) {
+	repo := NewMemoryStateRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "ADA/USD", `{"enabled":true}`))
+
+	value, err := repo.Get(ctx, interfaces.StateNamespacePairs, "ADA/USD")
+	require.NoError(t, err)
+	assert.Equal(t, `{"enabled":true}`, value)
+
+	// Namespaces aislados
+	_, err = repo.Get(ctx, interfaces.StateNamespaceAlerts, "ADA/USD")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestMemoryStateRepository_Overwrite(t *testing.T) {
+	repo := NewMemoryStateRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.Put(ctx, "ns", "k", "v1"))
+	require.NoError(t, repo.Put(ctx, "ns", "k", "v2"))
+
+	value, err := repo.Get(ctx, "ns", "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", value)
+}
+
+func TestMemoryStateRepository_DeleteAndList(t *testing.T) {
+	repo := NewMemoryStateRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.Put(ctx, "ns", "a", "1"))
+	require.NoError(t, repo.Put(ctx, "ns", "b", "2"))
+	require.NoError(t, repo.Delete(ctx, "ns", "a"))
+	// Eliminar clave inexistente no es error
+	require.NoError(t, repo.Delete(ctx, "other", "missing"))
+
+	items, err := repo.List(ctx, "ns")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"b": "2"}, items)
+
+	// La copia retornada no debe modificar el estado interno
+	items["c"] = "3"
+	again, err := repo.List(ctx, "ns")
+	require.NoError(t, err)
+	assert.Len(t, again, 1)
+}
+
+func TestMemoryStateRepository_EmptyNamespace(t *testing.T) {
+	repo := NewMemoryStateRepository()
+	ctx := context.Background()
+
+	assert.ErrorIs(t, repo.Put(ctx, "", "k", "v"), ErrInvalidNamespace)
+	_, err := repo.Get(ctx, "", "k")
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+	assert.ErrorIs(t, repo.Delete(ctx, "", "k"), ErrInvalidNamespace)
+	_, err = repo.List(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+}
+
+func TestMemoryStateRepository_ConcurrentAccess(t *testing.T) {
+	repo := NewMemoryStateRepository()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a' + i%26))
+			_ = repo.Put(ctx, "ns", key, "v")
+			_, _ = repo.Get(ctx, "ns", key)
+			_, _ = repo.List(ctx, "ns")
+		}(i)
+	}
+	wg.Wait()
+
+	items, err := repo.List(ctx, "ns")
+	require.NoError(t, err)
+	assert.Len(t, items, 26)
+}
+
+func TestFactory_Create(t *testing.T) {
+	factory := NewFactory()
+	ctx := context.Background()
+
+	repo, err := factory.Create(ctx, Config{Backend: BackendMemory})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStateRepository{}, repo)
+
+	_, err = factory.Create(ctx, Config{Backend: "unknown"})
+	assert.ErrorIs(t, err, ErrUnsupportedBackend)
+
+	_, err = factory.Create(ctx, Config{Backend: BackendSQL, SQLDriver: "not-registered", SQLDSN: "x"})
+	assert.Error(t, err)
+}
+
+func TestSQLStateRepository_Bind(t *testing.T) {
+	pg := &SQLStateRepository{postgres: true}
+	assert.Equal(t, "SELECT a FROM t WHERE x = $1 AND y = $2", pg.bind("SELECT a FROM t WHERE x = ? AND y = ?"))
+
+	lite := &SQLStateRepository{postgres: false}
+	assert.Equal(t, "SELECT a FROM t WHERE x = ?", lite.bind("SELECT a FROM t WHERE x = ?"))
+}
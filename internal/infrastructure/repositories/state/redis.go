@@ -0,0 +1,73 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisKeyPrefix prefijo de las claves hash usadas por namespace (state:<namespace>)
+const RedisKeyPrefix = "state:"
+
+// RedisStateRepository implementa StateRepository usando un hash de Redis por namespace,
+// lo que permite compartir el estado entre réplicas.
+type RedisStateRepository struct {
+	client *redis.Client
+}
+
+// NewRedisStateRepositoryWithClient crea un repositorio de estado con un cliente Redis existente
+func NewRedisStateRepositoryWithClient(client *redis.Client) interfaces.StateRepository {
+	return &RedisStateRepository{
+		client: client,
+	}
+}
+
+func (r *RedisStateRepository) hashKey(namespace string) string {
+	return RedisKeyPrefix + namespace
+}
+
+// Get obtiene un valor del namespace indicado
+func (r *RedisStateRepository) Get(ctx context.Context, namespace, key string) (string, error) {
+	if namespace == "" {
+		return "", ErrInvalidNamespace
+	}
+
+	val, err := r.client.HGet(ctx, r.hashKey(namespace), key).Result()
+	if err == redis.Nil {
+		return "", ErrKeyNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+// Put guarda (o reemplaza) un valor en el namespace indicado
+func (r *RedisStateRepository) Put(ctx context.Context, namespace, key string, value string) error {
+	if namespace == "" {
+		return ErrInvalidNamespace
+	}
+	return r.client.HSet(ctx, r.hashKey(namespace), key, value).Err()
+}
+
+// Delete elimina un valor del namespace indicado
+func (r *RedisStateRepository) Delete(ctx context.Context, namespace, key string) error {
+	if namespace == "" {
+		return ErrInvalidNamespace
+	}
+	return r.client.HDel(ctx, r.hashKey(namespace), key).Err()
+}
+
+// List retorna todos los valores del namespace
+func (r *RedisStateRepository) List(ctx context.Context, namespace string) (map[string]string, error) {
+	if namespace == "" {
+		return nil, ErrInvalidNamespace
+	}
+	return r.client.HGetAll(ctx, r.hashKey(namespace)).Result()
+}
+
+// Close cierra la conexión con Redis
+func (r *RedisStateRepository) Close() error {
+	return r.client.Close()
+}
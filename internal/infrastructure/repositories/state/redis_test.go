@@ -0,0 +1,58 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMiniredisStateRepository(t *testing.T) (interfaces.StateRepository, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	repo := NewRedisStateRepositoryWithClient(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo, server
+}
+
+func TestRedisStateRepository_PutGetDeleteList(t *testing.T) {
+	repo, server := newMiniredisStateRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "ADA/USD", `{"enabled":true}`))
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "ADA/USD", `{"enabled":false}`))
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "DOT/USD", `{"enabled":true}`))
+	assert.Equal(t, `{"enabled":false}`, server.HGet(RedisKeyPrefix+interfaces.StateNamespacePairs, "ADA/USD"),
+		"each namespace is a hash under state:<namespace>")
+
+	value, err := repo.Get(ctx, interfaces.StateNamespacePairs, "ADA/USD")
+	require.NoError(t, err)
+	assert.Equal(t, `{"enabled":false}`, value)
+	_, err = repo.Get(ctx, interfaces.StateNamespaceAlerts, "ADA/USD")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	require.NoError(t, repo.Delete(ctx, interfaces.StateNamespacePairs, "DOT/USD"))
+	items, err := repo.List(ctx, interfaces.StateNamespacePairs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ADA/USD": `{"enabled":false}`}, items)
+
+	items, err = repo.List(ctx, interfaces.StateNamespaceAlerts)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestRedisStateRepository_SharedAcrossReplicas(t *testing.T) {
+	first, server := newMiniredisStateRepository(t)
+	second := NewRedisStateRepositoryWithClient(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	defer second.Close()
+	ctx := context.Background()
+
+	require.NoError(t, first.Put(ctx, interfaces.StateNamespaceAlerts, "rule-1", "on"))
+	value, err := second.Get(ctx, interfaces.StateNamespaceAlerts, "rule-1")
+	require.NoError(t, err)
+	assert.Equal(t, "on", value)
+}
@@ -0,0 +1,149 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLTableName tabla donde se persiste el estado
+const SQLTableName = "service_state"
+
+// SQLStateRepository implementa StateRepository sobre database/sql.
+// Soporta SQLite y Postgres; el Factory abre la base con los drivers que enlaza
+// este paquete (ver drivers.go).
+type SQLStateRepository struct {
+	db       *sql.DB
+	postgres bool
+}
+
+// NewSQLStateRepository crea el repositorio y asegura que la tabla exista
+func NewSQLStateRepository(ctx context.Context, db *sql.DB, driver string) (interfaces.StateRepository, error) {
+	repo := &SQLStateRepository{
+		db:       db,
+		postgres: isPostgresDriver(driver),
+	}
+
+	if err := repo.migrate(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func isPostgresDriver(driver string) bool {
+	switch strings.ToLower(driver) {
+	case "postgres", "pgx":
+		return true
+	default:
+		return false
+	}
+}
+
+// migrate crea la tabla si no existe (sintaxis compatible con SQLite y Postgres)
+func (s *SQLStateRepository) migrate(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS ` + SQLTableName + ` (
+		namespace  VARCHAR(64)  NOT NULL,
+		state_key  VARCHAR(255) NOT NULL,
+		value      TEXT         NOT NULL,
+		updated_at TIMESTAMP    NOT NULL,
+		PRIMARY KEY (namespace, state_key)
+	)`
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create state table: %w", err)
+	}
+	return nil
+}
+
+// bind reescribe los placeholders '?' al formato $n cuando el driver es Postgres
+func (s *SQLStateRepository) bind(query string) string {
+	if !s.postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Get obtiene un valor del namespace indicado
+func (s *SQLStateRepository) Get(ctx context.Context, namespace, key string) (string, error) {
+	if namespace == "" {
+		return "", ErrInvalidNamespace
+	}
+
+	var value string
+	query := s.bind(`SELECT value FROM ` + SQLTableName + ` WHERE namespace = ? AND state_key = ?`)
+	err := s.db.QueryRowContext(ctx, query, namespace, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrKeyNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Put guarda (o reemplaza) un valor en el namespace indicado
+func (s *SQLStateRepository) Put(ctx context.Context, namespace, key string, value string) error {
+	if namespace == "" {
+		return ErrInvalidNamespace
+	}
+
+	query := s.bind(`INSERT INTO ` + SQLTableName + ` (namespace, state_key, value, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (namespace, state_key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`)
+	_, err := s.db.ExecContext(ctx, query, namespace, key, value, time.Now().UTC())
+	return err
+}
+
+// Delete elimina un valor del namespace indicado
+func (s *SQLStateRepository) Delete(ctx context.Context, namespace, key string) error {
+	if namespace == "" {
+		return ErrInvalidNamespace
+	}
+
+	query := s.bind(`DELETE FROM ` + SQLTableName + ` WHERE namespace = ? AND state_key = ?`)
+	_, err := s.db.ExecContext(ctx, query, namespace, key)
+	return err
+}
+
+// List retorna todos los valores del namespace
+func (s *SQLStateRepository) List(ctx context.Context, namespace string) (map[string]string, error) {
+	if namespace == "" {
+		return nil, ErrInvalidNamespace
+	}
+
+	query := s.bind(`SELECT state_key, value FROM ` + SQLTableName + ` WHERE namespace = ?`)
+	rows, err := s.db.QueryContext(ctx, query, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, rows.Err()
+}
+
+// Close cierra el pool de conexiones
+func (s *SQLStateRepository) Close() error {
+	return s.db.Close()
+}
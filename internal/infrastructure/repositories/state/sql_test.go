@@ -0,0 +1,72 @@
+package state
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLStateRepository_BindPerDriver(t *testing.T) {
+	query := `SELECT value FROM t WHERE namespace = ? AND state_key = ?`
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{"sqlite", query},
+		{"sqlite3", query},
+		{"postgres", `SELECT value FROM t WHERE namespace = $1 AND state_key = $2`},
+		{"pgx", `SELECT value FROM t WHERE namespace = $1 AND state_key = $2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			repo := &SQLStateRepository{postgres: isPostgresDriver(tt.driver)}
+			assert.Equal(t, tt.want, repo.bind(query))
+		})
+	}
+}
+
+func TestFactory_CreateSQLite(t *testing.T) {
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "state.db")
+	config := Config{Backend: BackendSQL, SQLDriver: "sqlite3", SQLDSN: dsn}
+
+	repo, err := NewFactory().Create(ctx, config)
+	require.NoError(t, err, "the sqlite driver is linked into the binary")
+
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "ADA/USD", `{"enabled":true}`))
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "ADA/USD", `{"enabled":false}`), "put upserts")
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespacePairs, "DOT/USD", `{"enabled":true}`))
+	require.NoError(t, repo.Put(ctx, interfaces.StateNamespaceAlerts, "ADA/USD", "alert"))
+
+	value, err := repo.Get(ctx, interfaces.StateNamespacePairs, "ADA/USD")
+	require.NoError(t, err)
+	assert.Equal(t, `{"enabled":false}`, value)
+
+	require.NoError(t, repo.Delete(ctx, interfaces.StateNamespacePairs, "DOT/USD"))
+	_, err = repo.Get(ctx, interfaces.StateNamespacePairs, "DOT/USD")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = repo.Get(ctx, "", "ADA/USD")
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+	require.NoError(t, repo.Close())
+
+	// El estado se carga de la misma base al reabrirla
+	reopened, err := NewFactory().Create(ctx, config)
+	require.NoError(t, err)
+	defer reopened.Close()
+	items, err := reopened.List(ctx, interfaces.StateNamespacePairs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ADA/USD": `{"enabled":false}`}, items)
+}
+
+func TestFactory_CreatePostgresUsesLinkedDriver(t *testing.T) {
+	// Sin servidor el ping falla, pero el driver está registrado
+	_, err := NewFactory().Create(context.Background(), Config{
+		Backend: BackendSQL, SQLDriver: "postgres", SQLDSN: "postgres://user@127.0.0.1:1/db?connect_timeout=1",
+	})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "unknown driver")
+}
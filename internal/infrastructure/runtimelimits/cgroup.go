@@ -0,0 +1,150 @@
+package runtimelimits
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultCgroupRoot es donde los runtimes de contenedores (Docker, containerd en
+// Kubernetes) montan el cgroup propio del contenedor
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// cgroupV1UnlimitedMemory es el umbral a partir del cual memory.limit_in_bytes de
+// cgroup v1 significa "sin límite" (el kernel informa un valor cercano a MaxInt64
+// redondeado a página)
+const cgroupV1UnlimitedMemory = math.MaxInt64 / 2
+
+// errNoLimit indica que el archivo existe pero no fija un límite ("max" o -1)
+var errNoLimit = errors.New("no limit")
+
+// Quota son los límites de recursos del cgroup del proceso; cero si no hay
+type Quota struct {
+	// CPU son los cores disponibles según la cuota CFS (p. ej. 1.5)
+	CPU float64
+	// Memory es el límite de memoria en bytes
+	Memory int64
+}
+
+// ReadQuota lee los límites de CPU y memoria del cgroup montado en root,
+// probando primero cgroup v2 y luego v1. Los archivos ausentes o sin límite
+// dejan el campo en cero.
+func ReadQuota(root string) Quota {
+	var quota Quota
+	if cpu, err := readCPUv2(root); err == nil {
+		quota.CPU = cpu
+	} else if cpu, err := readCPUv1(root); err == nil {
+		quota.CPU = cpu
+	}
+	if memory, err := readMemoryV2(root); err == nil {
+		quota.Memory = memory
+	} else if memory, err := readMemoryV1(root); err == nil {
+		quota.Memory = memory
+	}
+	return quota
+}
+
+// readCPUv2 interpreta cpu.max: "<quota> <period>" o "max <period>"
+func readCPUv2(root string) (float64, error) {
+	fields, err := readFields(filepath.Join(root, "cpu.max"))
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) != 2 {
+		return 0, errors.New("malformed cpu.max")
+	}
+	if fields[0] == "max" {
+		return 0, errNoLimit
+	}
+	return cpuRatio(fields[0], fields[1])
+}
+
+// readCPUv1 interpreta cpu.cfs_quota_us (-1 sin límite) y cpu.cfs_period_us
+func readCPUv1(root string) (float64, error) {
+	quota, err := readFields(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, err
+	}
+	period, err := readFields(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, err
+	}
+	if len(quota) != 1 || len(period) != 1 {
+		return 0, errors.New("malformed cfs quota")
+	}
+	if quota[0] == "-1" {
+		return 0, errNoLimit
+	}
+	return cpuRatio(quota[0], period[0])
+}
+
+func cpuRatio(quotaText, periodText string) (float64, error) {
+	quota, err := strconv.ParseInt(quotaText, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	period, err := strconv.ParseInt(periodText, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if quota <= 0 || period <= 0 {
+		return 0, errNoLimit
+	}
+	return float64(quota) / float64(period), nil
+}
+
+// readMemoryV2 interpreta memory.max: bytes o "max"
+func readMemoryV2(root string) (int64, error) {
+	fields, err := readFields(filepath.Join(root, "memory.max"))
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) != 1 {
+		return 0, errors.New("malformed memory.max")
+	}
+	if fields[0] == "max" {
+		return 0, errNoLimit
+	}
+	return parseBytes(fields[0])
+}
+
+// readMemoryV1 interpreta memory/memory.limit_in_bytes
+func readMemoryV1(root string) (int64, error) {
+	fields, err := readFields(filepath.Join(root, "memory", "memory.limit_in_bytes"))
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) != 1 {
+		return 0, errors.New("malformed memory.limit_in_bytes")
+	}
+	limit, err := parseBytes(fields[0])
+	if err != nil {
+		return 0, err
+	}
+	if limit >= cgroupV1UnlimitedMemory {
+		return 0, errNoLimit
+	}
+	return limit, nil
+}
+
+func parseBytes(text string) (int64, error) {
+	limit, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if limit <= 0 {
+		return 0, errNoLimit
+	}
+	return limit, nil
+}
+
+func readFields(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
@@ -0,0 +1,96 @@
+// Package runtimelimits ajusta GOMAXPROCS y GOMEMLIMIT a los límites de CPU y
+// memoria del contenedor (cgroup), para que el scheduler no use más threads que
+// la cuota de CPU y el GC se active antes de que el kernel mate el proceso por OOM.
+package runtimelimits
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// Orígenes de cada valor aplicado
+const (
+	SourceEnv     = "env"     // GOMAXPROCS / GOMEMLIMIT del entorno, que siempre se respetan
+	SourceConfig  = "config"  // valor explícito de la configuración
+	SourceCgroup  = "cgroup"  // derivado de la cuota del cgroup
+	SourceDefault = "default" // valor por defecto del runtime de Go
+)
+
+// Options configura Apply
+type Options struct {
+	// AutoMaxProcs deriva GOMAXPROCS de la cuota de CPU del cgroup
+	AutoMaxProcs bool
+	// MaxProcs fija GOMAXPROCS (0 = automático o valor del runtime)
+	MaxProcs int
+	// MemoryLimit fija GOMEMLIMIT en bytes (0 = derivar del cgroup con MemoryLimitRatio)
+	MemoryLimit int64
+	// MemoryLimitRatio es la fracción del límite de memoria del cgroup usada como
+	// GOMEMLIMIT (0 = no derivar)
+	MemoryLimitRatio float64
+	// CgroupRoot es donde está montado el cgroup (DefaultCgroupRoot si es vacío)
+	CgroupRoot string
+}
+
+// Applied describe los valores vigentes después de Apply
+type Applied struct {
+	MaxProcs          int
+	MaxProcsSource    string
+	MemoryLimit       int64 // 0 si no hay límite
+	MemoryLimitSource string
+	Quota             Quota // límites detectados en el cgroup
+}
+
+// Apply fija GOMAXPROCS y GOMEMLIMIT según opts y los límites del cgroup. Las
+// variables de entorno GOMAXPROCS y GOMEMLIMIT tienen prioridad sobre todo lo demás.
+func Apply(opts Options) Applied {
+	root := opts.CgroupRoot
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+	applied := Applied{Quota: ReadQuota(root)}
+
+	applied.MaxProcs, applied.MaxProcsSource = maxProcs(opts, applied.Quota)
+	if applied.MaxProcsSource != SourceEnv && applied.MaxProcsSource != SourceDefault {
+		runtime.GOMAXPROCS(applied.MaxProcs)
+	}
+
+	limit, source := memoryLimit(opts, applied.Quota)
+	if source == SourceConfig || source == SourceCgroup {
+		debug.SetMemoryLimit(limit)
+	}
+	applied.MemoryLimitSource = source
+	// SetMemoryLimit con un valor negativo sólo consulta el límite vigente
+	if current := debug.SetMemoryLimit(-1); current != math.MaxInt64 {
+		applied.MemoryLimit = current
+	}
+	return applied
+}
+
+func maxProcs(opts Options, quota Quota) (int, string) {
+	if _, ok := os.LookupEnv("GOMAXPROCS"); ok {
+		return runtime.GOMAXPROCS(0), SourceEnv
+	}
+	if opts.MaxProcs > 0 {
+		return opts.MaxProcs, SourceConfig
+	}
+	if opts.AutoMaxProcs && quota.CPU > 0 {
+		// Como automaxprocs: cuota truncada hacia abajo, al menos 1
+		return max(1, int(math.Floor(quota.CPU))), SourceCgroup
+	}
+	return runtime.GOMAXPROCS(0), SourceDefault
+}
+
+func memoryLimit(opts Options, quota Quota) (int64, string) {
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); ok {
+		return 0, SourceEnv
+	}
+	if opts.MemoryLimit > 0 {
+		return opts.MemoryLimit, SourceConfig
+	}
+	if opts.MemoryLimitRatio > 0 && quota.Memory > 0 {
+		return int64(float64(quota.Memory) * opts.MemoryLimitRatio), SourceCgroup
+	}
+	return 0, SourceDefault
+}
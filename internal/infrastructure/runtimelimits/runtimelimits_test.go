@@ -0,0 +1,100 @@
+package runtimelimits
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCgroupFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0o644))
+}
+
+// restoreRuntime deshace los cambios de Apply al terminar el test
+func restoreRuntime(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+	limit := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() {
+		runtime.GOMAXPROCS(procs)
+		debug.SetMemoryLimit(limit)
+	})
+}
+
+func TestReadQuota(t *testing.T) {
+	v2 := t.TempDir()
+	writeCgroupFile(t, v2, "cpu.max", "150000 100000")
+	writeCgroupFile(t, v2, "memory.max", "536870912")
+	assert.Equal(t, Quota{CPU: 1.5, Memory: 512 << 20}, ReadQuota(v2))
+
+	unlimited := t.TempDir()
+	writeCgroupFile(t, unlimited, "cpu.max", "max 100000")
+	writeCgroupFile(t, unlimited, "memory.max", "max")
+	assert.Equal(t, Quota{}, ReadQuota(unlimited))
+
+	v1 := t.TempDir()
+	writeCgroupFile(t, v1, "cpu/cpu.cfs_quota_us", "200000")
+	writeCgroupFile(t, v1, "cpu/cpu.cfs_period_us", "100000")
+	writeCgroupFile(t, v1, "memory/memory.limit_in_bytes", "9223372036854771712")
+	assert.Equal(t, Quota{CPU: 2}, ReadQuota(v1), "v1 near-MaxInt64 memory means unlimited")
+
+	assert.Equal(t, Quota{}, ReadQuota(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestApply_FromCgroup(t *testing.T) {
+	restoreRuntime(t)
+	root := t.TempDir()
+	writeCgroupFile(t, root, "cpu.max", "250000 100000")
+	writeCgroupFile(t, root, "memory.max", "1073741824")
+
+	applied := Apply(Options{AutoMaxProcs: true, MemoryLimitRatio: 0.5, CgroupRoot: root})
+
+	assert.Equal(t, 2, applied.MaxProcs, "quota is truncated")
+	assert.Equal(t, SourceCgroup, applied.MaxProcsSource)
+	assert.Equal(t, 2, runtime.GOMAXPROCS(0))
+	assert.Equal(t, int64(512<<20), applied.MemoryLimit)
+	assert.Equal(t, SourceCgroup, applied.MemoryLimitSource)
+	assert.Equal(t, int64(512<<20), debug.SetMemoryLimit(-1))
+}
+
+func TestApply_PrecedenceAndMinimum(t *testing.T) {
+	restoreRuntime(t)
+	root := t.TempDir()
+	writeCgroupFile(t, root, "cpu.max", "50000 100000")
+	writeCgroupFile(t, root, "memory.max", "1073741824")
+
+	applied := Apply(Options{AutoMaxProcs: true, CgroupRoot: root})
+	assert.Equal(t, 1, applied.MaxProcs, "fractional quotas keep at least one P")
+	assert.Equal(t, SourceDefault, applied.MemoryLimitSource, "ratio 0 leaves GOMEMLIMIT alone")
+
+	applied = Apply(Options{AutoMaxProcs: true, MaxProcs: 3, MemoryLimit: 256 << 20, MemoryLimitRatio: 0.9, CgroupRoot: root})
+	assert.Equal(t, 3, applied.MaxProcs)
+	assert.Equal(t, SourceConfig, applied.MaxProcsSource)
+	assert.Equal(t, int64(256<<20), applied.MemoryLimit)
+	assert.Equal(t, SourceConfig, applied.MemoryLimitSource)
+
+	t.Setenv("GOMAXPROCS", "4")
+	t.Setenv("GOMEMLIMIT", "1GiB")
+	applied = Apply(Options{MaxProcs: 8, MemoryLimit: 1 << 20, CgroupRoot: root})
+	assert.Equal(t, SourceEnv, applied.MaxProcsSource)
+	assert.Equal(t, SourceEnv, applied.MemoryLimitSource)
+	assert.Equal(t, 3, runtime.GOMAXPROCS(0), "environment values are not overridden")
+}
+
+func TestApply_NoLimits(t *testing.T) {
+	restoreRuntime(t)
+	debug.SetMemoryLimit(math.MaxInt64)
+
+	applied := Apply(Options{AutoMaxProcs: true, MemoryLimitRatio: 0.9, CgroupRoot: t.TempDir()})
+	assert.Equal(t, SourceDefault, applied.MaxProcsSource)
+	assert.Equal(t, runtime.GOMAXPROCS(0), applied.MaxProcs)
+	assert.Zero(t, applied.MemoryLimit)
+}
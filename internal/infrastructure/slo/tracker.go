@@ -0,0 +1,297 @@
+// Package slo evalúa objetivos de nivel de servicio a partir de los histogramas
+// que el servicio ya registra y publica cumplimiento y consumo del error budget
+// como gauges (grupo de métricas slo).
+package slo
+
+import (
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Valores usados cuando la ventana o el intervalo no son positivos
+const (
+	DefaultWindow             = time.Hour
+	DefaultEvaluationInterval = 30 * time.Second
+)
+
+// Indicadores evaluados por objetivo
+const (
+	// IndicatorLatency: requests a Objective.Path respondidas en menos de Objective.Latency
+	IndicatorLatency = "latency"
+	// IndicatorFreshness: precios servidos por HTTP recibidos hace menos de Objective.MaxAge
+	IndicatorFreshness = "freshness"
+)
+
+// Objective es un objetivo de nivel de servicio. Latency y MaxAge se evalúan como
+// indicadores independientes; un valor cero deshabilita el indicador.
+type Objective struct {
+	Name    string
+	Path    string
+	Target  float64
+	Latency time.Duration
+	MaxAge  time.Duration
+}
+
+// Status es el cumplimiento de un indicador sobre la ventana móvil
+type Status struct {
+	Objective string
+	Indicator string
+	Target    float64
+	// Good y Total son los eventos observados dentro de la ventana
+	Good  float64
+	Total float64
+	// Compliance es Good/Total (1 sin tráfico)
+	Compliance float64
+	// BurnRate es la tasa de consumo del error budget: 1 lo agota justo al final de la ventana
+	BurnRate float64
+	// BudgetRemaining es la fracción del error budget sin consumir (negativa si se agotó)
+	BudgetRemaining float64
+}
+
+type indicatorKey struct {
+	objective string
+	indicator string
+}
+
+type counts struct {
+	good  float64
+	total float64
+}
+
+type sample struct {
+	at     time.Time
+	counts map[indicatorKey]counts
+}
+
+// Tracker toma periódicamente los contadores acumulados de los histogramas y
+// calcula el cumplimiento como la diferencia entre la muestra actual y la más
+// reciente anterior al inicio de la ventana
+type Tracker struct {
+	objectives []Objective
+	window     time.Duration
+	interval   time.Duration
+	metrics    *metrics.Metrics
+	now        func() time.Time
+
+	mu      sync.Mutex
+	samples []sample
+
+	loopMu sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewTracker crea el tracker sobre la instancia de métricas por defecto
+func NewTracker(objectives []Objective, window, evaluationInterval time.Duration) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	if evaluationInterval <= 0 {
+		evaluationInterval = DefaultEvaluationInterval
+	}
+	return &Tracker{
+		objectives: append([]Objective(nil), objectives...),
+		window:     window,
+		interval:   evaluationInterval,
+		metrics:    metrics.Default(),
+		now:        time.Now,
+	}
+}
+
+// WithMetrics lee y publica sobre m en lugar de la instancia por defecto
+func (t *Tracker) WithMetrics(m *metrics.Metrics) *Tracker {
+	t.metrics = m
+	return t
+}
+
+// Evaluate toma una muestra, descarta las que quedaron fuera de la ventana,
+// actualiza los gauges y devuelve el estado de cada indicador
+func (t *Tracker) Evaluate() []Status {
+	now := t.now()
+	current := sample{at: now, counts: t.collect()}
+
+	t.mu.Lock()
+	t.samples = append(t.samples, current)
+	// Conservar como base la muestra más reciente anterior al inicio de la ventana
+	cutoff := now.Add(-t.window)
+	first := 0
+	for i, s := range t.samples {
+		if s.at.After(cutoff) {
+			break
+		}
+		first = i
+	}
+	t.samples = t.samples[first:]
+	baseline := t.samples[0]
+	t.mu.Unlock()
+
+	statuses := make([]Status, 0, len(t.objectives)*2)
+	for _, objective := range t.objectives {
+		t.metrics.SLO.UpdateTarget(objective.Name, objective.Target)
+		for _, indicator := range indicators(objective) {
+			key := indicatorKey{objective: objective.Name, indicator: indicator}
+			status := newStatus(objective, indicator, current.counts[key], baseline.counts[key])
+			t.metrics.SLO.UpdateIndicator(objective.Name, indicator, status.Compliance, status.BurnRate, status.BudgetRemaining)
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func newStatus(objective Objective, indicator string, current, baseline counts) Status {
+	status := Status{
+		Objective:  objective.Name,
+		Indicator:  indicator,
+		Target:     objective.Target,
+		Good:       current.good - baseline.good,
+		Total:      current.total - baseline.total,
+		Compliance: 1,
+	}
+	if status.Total > 0 {
+		status.Compliance = status.Good / status.Total
+	}
+	status.BurnRate = (1 - status.Compliance) / (1 - objective.Target)
+	status.BudgetRemaining = 1 - status.BurnRate
+	return status
+}
+
+func indicators(objective Objective) []string {
+	var names []string
+	if objective.Latency > 0 {
+		names = append(names, IndicatorLatency)
+	}
+	if objective.MaxAge > 0 {
+		names = append(names, IndicatorFreshness)
+	}
+	return names
+}
+
+// collect lee los contadores acumulados de cada indicador. Los collectors siguen
+// observando aunque su grupo esté deshabilitado en /metrics.
+func (t *Tracker) collect() map[indicatorKey]counts {
+	result := make(map[indicatorKey]counts, len(t.objectives)*2)
+	for _, objective := range t.objectives {
+		if objective.Latency > 0 {
+			result[indicatorKey{objective.Name, IndicatorLatency}] = countBelow(
+				t.metrics.HTTP.RequestDuration, map[string]string{"path": objective.Path}, objective.Latency.Seconds())
+		}
+		if objective.MaxAge > 0 {
+			result[indicatorKey{objective.Name, IndicatorFreshness}] = countBelow(
+				t.metrics.Prices.PipelineLatency, map[string]string{"stage": metrics.PipelineStageServed}, objective.MaxAge.Seconds())
+		}
+	}
+	return result
+}
+
+// countBelow suma, sobre las series de collector cuyas labels coinciden con match,
+// las observaciones totales y las menores que threshold. Si threshold no coincide
+// con un bucket se interpola linealmente dentro del bucket que lo contiene.
+func countBelow(collector prometheus.Collector, match map[string]string, threshold float64) counts {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+
+	var result counts
+	for metric := range ch {
+		var out dto.Metric
+		if err := metric.Write(&out); err != nil || out.GetHistogram() == nil || !labelsMatch(out.GetLabel(), match) {
+			continue
+		}
+		histogram := out.GetHistogram()
+		result.total += float64(histogram.GetSampleCount())
+		result.good += bucketCountAt(histogram.GetBucket(), threshold)
+	}
+	return result
+}
+
+func bucketCountAt(buckets []*dto.Bucket, threshold float64) float64 {
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, bucket := range buckets {
+		upperBound, count := bucket.GetUpperBound(), float64(bucket.GetCumulativeCount())
+		if threshold <= upperBound {
+			if upperBound == lowerBound {
+				return count
+			}
+			return lowerCount + (count-lowerCount)*(threshold-lowerBound)/(upperBound-lowerBound)
+		}
+		lowerBound, lowerCount = upperBound, count
+	}
+	// Por encima del último bucket finito no se sabe cuántas observaciones cumplen
+	return lowerCount
+}
+
+func labelsMatch(labels []*dto.LabelPair, match map[string]string) bool {
+	matched := 0
+	for _, label := range labels {
+		if want, ok := match[label.GetName()]; ok {
+			if label.GetValue() != want {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(match)
+}
+
+// Start toma la muestra inicial y evalúa los objetivos periódicamente en background
+func (t *Tracker) Start(ctx context.Context) error {
+	t.loopMu.Lock()
+	defer t.loopMu.Unlock()
+	if t.stop != nil {
+		return nil
+	}
+
+	t.Evaluate()
+	logging.Info(ctx, "SLO tracking started", logging.Fields{
+		"objectives":          len(t.objectives),
+		"window":              t.window.String(),
+		"evaluation_interval": t.interval.String(),
+	})
+
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+	go t.loop(t.stop, t.done)
+	return nil
+}
+
+// Stop detiene la evaluación periódica
+func (t *Tracker) Stop(ctx context.Context) error {
+	t.loopMu.Lock()
+	stop, done := t.stop, t.done
+	t.stop, t.done = nil, nil
+	t.loopMu.Unlock()
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *Tracker) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.Evaluate()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package slo
+
+import (
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(t *testing.T, window time.Duration) (*Tracker, *metrics.Metrics, *time.Time) {
+	t.Helper()
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker([]Objective{{
+		Name:    "ltp",
+		Path:    "/api/v1/ltp",
+		Target:  0.9,
+		Latency: 250 * time.Millisecond,
+		MaxAge:  5 * time.Second,
+	}}, window, time.Minute).WithMetrics(m)
+	tracker.now = func() time.Time { return now }
+	return tracker, m, &now
+}
+
+func statusOf(t *testing.T, statuses []Status, indicator string) Status {
+	t.Helper()
+	for _, status := range statuses {
+		if status.Indicator == indicator {
+			return status
+		}
+	}
+	t.Fatalf("indicator %s not evaluated", indicator)
+	return Status{}
+}
+
+func TestTracker_ComplianceAndBurnRate(t *testing.T) {
+	tracker, m, now := newTestTracker(t, time.Hour)
+	tracker.Evaluate()
+
+	for i := 0; i < 8; i++ {
+		m.HTTP.RecordRequest("GET", "/api/v1/ltp", 200, 0.05, 0, 0)
+	}
+	m.HTTP.RecordRequest("GET", "/api/v1/ltp", 200, 0.8, 0, 0)
+	m.HTTP.RecordRequest("POST", "/api/v1/ltp", 200, 1.5, 0, 0)
+	m.HTTP.RecordRequest("GET", "/health", 200, 3, 0, 0) // otra ruta, no cuenta
+	m.Prices.ObservePipelineLatency("BTC/USD", "websocket", metrics.PipelineStageServed, 1)
+	m.Prices.ObservePipelineLatency("BTC/USD", "websocket", metrics.PipelineStageCache, 30) // otra etapa
+
+	*now = now.Add(time.Minute)
+	statuses := tracker.Evaluate()
+
+	latency := statusOf(t, statuses, IndicatorLatency)
+	assert.Equal(t, 10.0, latency.Total)
+	assert.InDelta(t, 8.0, latency.Good, 1e-9)
+	assert.InDelta(t, 0.8, latency.Compliance, 1e-9)
+	assert.InDelta(t, 2.0, latency.BurnRate, 1e-9, "20% bad events burn a 10% budget twice as fast")
+	assert.InDelta(t, -1.0, latency.BudgetRemaining, 1e-9)
+
+	freshness := statusOf(t, statuses, IndicatorFreshness)
+	assert.Equal(t, 1.0, freshness.Total)
+	assert.Equal(t, 1.0, freshness.Compliance)
+
+	assert.InDelta(t, 0.8, testutil.ToFloat64(m.SLO.Compliance.WithLabelValues("ltp", IndicatorLatency)), 1e-9)
+	assert.InDelta(t, 2.0, testutil.ToFloat64(m.SLO.BurnRate.WithLabelValues("ltp", IndicatorLatency)), 1e-9)
+	assert.Equal(t, 0.9, testutil.ToFloat64(m.SLO.Target.WithLabelValues("ltp")))
+}
+
+func TestTracker_RollingWindowForgetsOldEvents(t *testing.T) {
+	tracker, m, now := newTestTracker(t, 10*time.Minute)
+	tracker.Evaluate()
+
+	m.HTTP.RecordRequest("GET", "/api/v1/ltp", 200, 2, 0, 0)
+	*now = now.Add(5 * time.Minute)
+	assert.Equal(t, 0.0, statusOf(t, tracker.Evaluate(), IndicatorLatency).Compliance)
+
+	*now = now.Add(6 * time.Minute)
+	m.HTTP.RecordRequest("GET", "/api/v1/ltp", 200, 0.01, 0, 0)
+	*now = now.Add(5 * time.Minute)
+	status := statusOf(t, tracker.Evaluate(), IndicatorLatency)
+	assert.Equal(t, 1.0, status.Total, "the slow request fell out of the window")
+	assert.Equal(t, 1.0, status.Compliance)
+	assert.Equal(t, 0.0, status.BurnRate)
+}
+
+func TestTracker_NoTrafficIsCompliant(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, time.Hour)
+	status := statusOf(t, tracker.Evaluate(), IndicatorLatency)
+	assert.Equal(t, 1.0, status.Compliance)
+	assert.Equal(t, 1.0, status.BudgetRemaining)
+}
+
+func TestTracker_StartStop(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, time.Hour)
+	require.NoError(t, tracker.Start(context.Background()))
+	require.NoError(t, tracker.Start(context.Background()), "start is idempotent")
+	assert.NoError(t, tracker.Stop(context.Background()))
+	assert.NoError(t, tracker.Stop(context.Background()))
+}
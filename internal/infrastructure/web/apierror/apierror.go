@@ -0,0 +1,112 @@
+// Package apierror escribe las respuestas de error de la API con el esquema
+// estándar dto.ErrorResponse, compartido por handlers y middlewares. Los clientes
+// que envían "Accept: application/problem+json" reciben el mismo error en formato
+// RFC 7807, y el mensaje se traduce según Accept-Language (ver package i18n).
+package apierror
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/web/i18n"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/text/language"
+)
+
+// Write responde con status y un error de la API con code y message
+func Write(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	WriteResponse(w, r, status, dto.NewErrorResponse(code, message))
+}
+
+// WriteWithDetails responde con un error que incluye contexto adicional
+func WriteWithDetails(w http.ResponseWriter, r *http.Request, status int, code, message string, details map[string]any) {
+	WriteResponse(w, r, status, dto.NewErrorResponse(code, message).WithDetails(details))
+}
+
+// WriteResponse escribe resp completando el request ID desde el contexto del
+// request (o el header X-Request-ID ya fijado por el tracing)
+func WriteResponse(w http.ResponseWriter, r *http.Request, status int, resp *dto.ErrorResponse) {
+	if resp.RequestID == "" {
+		resp.RequestID = requestID(w, r)
+	}
+	lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+	localize(resp, lang)
+
+	var body any = resp
+	contentType := "application/json"
+	if wantsProblem(r) {
+		body = NewProblem(r, status, resp)
+		contentType = ContentTypeProblemJSON
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Language", lang.String())
+	w.Header().Add("Vary", "Accept, Accept-Language")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logging.ErrorWithError(r.Context(), "Failed to encode error response", err, logging.Fields{
+			"status_code": status,
+			"error_code":  resp.Code,
+		})
+	}
+}
+
+// NotFoundHandler responde 404 NOT_FOUND para rutas inexistentes
+func NotFoundHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, http.StatusNotFound, dto.CodeNotFound, "no route matches "+requestPath(r))
+	})
+}
+
+// MethodNotAllowedHandler responde 405 METHOD_NOT_ALLOWED para rutas existentes
+// con otro método
+func MethodNotAllowedHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, http.StatusMethodNotAllowed, dto.CodeMethodNotAllowed, "method "+r.Method+" is not allowed on "+requestPath(r))
+	})
+}
+
+// localize reemplaza el mensaje por el del catálogo en el idioma negociado. Los
+// handlers arman los mensajes en inglés y a veces con datos del error ("unsupported
+// pair: DOGE/MOON"); ese texto se conserva en details.cause al traducirlo.
+func localize(resp *dto.ErrorResponse, lang language.Tag) {
+	if lang == i18n.English {
+		if resp.Message == "" {
+			resp.Message, _ = i18n.Message(lang, resp.Code)
+		}
+		return
+	}
+
+	message, ok := i18n.Message(lang, resp.Code)
+	if !ok {
+		return
+	}
+	if generic, _ := i18n.Message(i18n.English, resp.Code); resp.Message != "" && resp.Message != generic {
+		details := make(map[string]any, len(resp.Details)+1)
+		maps.Copy(details, resp.Details)
+		details["cause"] = resp.Message
+		resp.Details = details
+	}
+	resp.Message = message
+}
+
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	if id := logging.GetRequestID(r.Context()); id != "" {
+		return id
+	}
+	return w.Header().Get("X-Request-ID")
+}
+
+// requestPath devuelve el path pedido por el cliente: los subrouters montados con
+// http.StripPrefix reciben r.URL.Path sin el prefijo (/api/v1), RequestURI no
+func requestPath(r *http.Request) string {
+	if r.RequestURI != "" {
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil && u.Path != "" {
+			return u.Path
+		}
+	}
+	return r.URL.Path
+}
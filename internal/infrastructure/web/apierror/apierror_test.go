@@ -0,0 +1,140 @@
+package apierror
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteWithDetails_IncludesRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ltp", nil)
+	req = req.WithContext(logging.WithRequestID(req.Context(), "req_123"))
+	rec := httptest.NewRecorder()
+
+	WriteWithDetails(rec, req, http.StatusBadRequest, dto.CodeInvalidParameter, "bad limit", map[string]any{"parameter": "limit"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, dto.CodeInvalidParameter, body.Code)
+	assert.Equal(t, dto.CodeInvalidParameter, body.Error, "the deprecated error field mirrors code")
+	assert.Equal(t, "bad limit", body.Message)
+	assert.Equal(t, "req_123", body.RequestID)
+	assert.Equal(t, "limit", body.Details["parameter"])
+}
+
+func TestWrite_FallsBackToResponseHeaderRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "req_from_header")
+
+	NotFoundHandler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, dto.CodeNotFound, body["code"])
+	assert.Equal(t, "req_from_header", body["request_id"])
+	assert.NotContains(t, body, "details")
+}
+
+func TestWrite_ProblemJSONWhenRequested(t *testing.T) {
+	SetProblemTypeBase("https://errors.example.com/")
+	defer SetProblemTypeBase("")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ltp", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	req = req.WithContext(logging.WithRequestID(req.Context(), "req_123"))
+	rec := httptest.NewRecorder()
+
+	WriteWithDetails(rec, req, http.StatusTooManyRequests, dto.CodeRateLimitExceeded, "slow down", map[string]any{"limit": 10})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, ContentTypeProblemJSON, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept, Accept-Language", rec.Header().Get("Vary"))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, Problem{
+		Type:      "https://errors.example.com/rate-limit-exceeded",
+		Title:     "Too Many Requests",
+		Status:    http.StatusTooManyRequests,
+		Detail:    "slow down",
+		Instance:  "/api/v1/ltp",
+		Code:      dto.CodeRateLimitExceeded,
+		RequestID: "req_123",
+		Details:   map[string]any{"limit": 10.0},
+	}, problem)
+}
+
+func TestNotFoundHandler_ReportsPathBeforeStripPrefix(t *testing.T) {
+	handler := http.StripPrefix("/api/v1", NotFoundHandler())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/nope?x=1", nil)
+	req.Header.Set("Accept", ContentTypeProblemJSON)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "/api/v1/nope", problem.Instance)
+	assert.Equal(t, "no route matches /api/v1/nope", problem.Detail)
+}
+
+func TestWantsProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/json, application/problem+json", true},
+		{"application/json, application/problem+json;q=0.5", false},
+		{"application/problem+json;q=0.9, application/json;q=0.1", true},
+		{"application/problem+json;q=0", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		assert.Equal(t, tt.want, wantsProblem(req), "Accept: %q", tt.accept)
+	}
+	assert.Equal(t, "about:blank", ProblemType(dto.CodeNotFound), "no base configured")
+}
+
+func TestWrite_LocalizesMessage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ltp", nil)
+	req.Header.Set("Accept-Language", "es-AR,es;q=0.9")
+
+	rec := httptest.NewRecorder()
+	Write(rec, req, http.StatusUnauthorized, dto.CodeAPIKeyMissing, "API key missing")
+	assert.Equal(t, "es", rec.Header().Get("Content-Language"))
+	var body dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Falta la API key", body.Message)
+	assert.Empty(t, body.Details, "the catalog message already says everything")
+
+	rec = httptest.NewRecorder()
+	WriteWithDetails(rec, req, http.StatusBadRequest, dto.CodeUnsupportedPair, "unsupported pair: DOGE/MOON", map[string]any{"pair": "DOGE/MOON"})
+	body = dto.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Par de trading no soportado", body.Message)
+	assert.Equal(t, map[string]any{"pair": "DOGE/MOON", "cause": "unsupported pair: DOGE/MOON"}, body.Details)
+
+	req.Header.Set("Accept-Language", "en")
+	rec = httptest.NewRecorder()
+	Write(rec, req, http.StatusBadRequest, dto.CodeUnsupportedPair, "unsupported pair: DOGE/MOON")
+	body = dto.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "unsupported pair: DOGE/MOON", body.Message, "English keeps the handler message")
+	assert.Equal(t, "en", rec.Header().Get("Content-Language"))
+}
@@ -0,0 +1,90 @@
+package apierror
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ContentTypeProblemJSON es el media type de los errores RFC 7807
+const ContentTypeProblemJSON = "application/problem+json"
+
+// defaultProblemType es el "type" de RFC 7807 cuando no hay base configurada
+const defaultProblemType = "about:blank"
+
+var problemTypeBase atomic.Value // string
+
+// SetProblemTypeBase fija el prefijo de los type URIs de application/problem+json
+// (server.problem_type_base_uri); vacío usa "about:blank" para todos los códigos
+func SetProblemTypeBase(base string) {
+	problemTypeBase.Store(base)
+}
+
+// Problem es un error de la API en formato RFC 7807. Los miembros de extensión
+// code, request_id y details conservan la información de dto.ErrorResponse.
+// @Description RFC 7807 problem document, returned instead of dto.ErrorResponse when the client sends Accept: application/problem+json
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// NewProblem convierte un error de la API en un problem document
+func NewProblem(r *http.Request, status int, resp *dto.ErrorResponse) *Problem {
+	return &Problem{
+		Type:      ProblemType(resp.Code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    resp.Message,
+		Instance:  requestPath(r),
+		Code:      resp.Code,
+		RequestID: resp.RequestID,
+		Details:   resp.Details,
+	}
+}
+
+// ProblemType devuelve el type URI de un código de error, p. ej.
+// RATE_LIMIT_EXCEEDED -> /problems/rate-limit-exceeded
+func ProblemType(code string) string {
+	base, _ := problemTypeBase.Load().(string)
+	if base == "" || code == "" {
+		return defaultProblemType
+	}
+	return base + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// wantsProblem indica si el Accept del request prefiere application/problem+json
+// sobre application/json. Los comodines no cuentan: sin preferencia explícita se
+// mantiene el esquema JSON estándar.
+func wantsProblem(r *http.Request) bool {
+	problemQ, jsonQ := -1.0, -1.0
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if raw, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(raw, 64); err != nil {
+					continue
+				}
+			}
+			switch mediaType {
+			case ContentTypeProblemJSON:
+				problemQ = max(problemQ, q)
+			case "application/json":
+				jsonQ = max(jsonQ, q)
+			}
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
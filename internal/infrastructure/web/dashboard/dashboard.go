@@ -0,0 +1,29 @@
+// Package dashboard sirve el dashboard de operación embebido en el binario
+// (/admin/): precios en vivo vía SSE, estado de la conexión con el exchange,
+// caché y fallbacks recientes. Es una página estática; los datos llegan de los
+// endpoints de estado y stream montados junto a ella.
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler sirve los archivos del dashboard; se monta con el prefijo ya removido
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.FileServerFS(files)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,132 @@
+// Dashboard de operación: precios vía SSE (events) y estado vía JSON (status),
+// ambos relativos a /admin/ para reutilizar las credenciales de la página.
+"use strict";
+
+const STATUS_INTERVAL_MS = 5000;
+
+const prices = new Map(); // pair -> PriceData
+
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined) node.textContent = text;
+  if (className) node.className = className;
+  return node;
+}
+
+function setBadge(id, text, state) {
+  const badge = document.getElementById(id);
+  badge.textContent = text;
+  badge.className = "badge" + (state ? " " + state : "");
+}
+
+function formatAge(seconds) {
+  if (seconds === null || seconds === undefined) return "–";
+  if (seconds < 60) return seconds.toFixed(1) + "s";
+  return Math.floor(seconds / 60) + "m " + Math.round(seconds % 60) + "s";
+}
+
+function priceTime(price) {
+  return price.exchange_time || price.received_time || null;
+}
+
+function renderPrices(flashPair) {
+  const body = document.getElementById("prices");
+  body.replaceChildren();
+  if (prices.size === 0) {
+    const row = el("tr");
+    const cell = el("td", "Waiting for prices…", "empty");
+    cell.colSpan = 4;
+    row.append(cell);
+    body.append(row);
+    return;
+  }
+  const now = Date.now();
+  for (const pair of [...prices.keys()].sort()) {
+    const price = prices.get(pair);
+    const time = priceTime(price);
+    const row = el("tr", undefined, pair === flashPair ? "flash" : "");
+    row.append(
+      el("td", pair),
+      el("td", price.amount.toLocaleString(undefined, { maximumFractionDigits: 8 }), "num"),
+      el("td", time ? new Date(time).toLocaleTimeString() : "–"),
+      el("td", time ? formatAge((now - Date.parse(time)) / 1000) : "–", "num"),
+    );
+    body.append(row);
+  }
+}
+
+function renderFallbacks(events) {
+  const body = document.getElementById("fallbacks");
+  body.replaceChildren();
+  if (events.length === 0) {
+    const row = el("tr");
+    const cell = el("td", "No fallbacks", "empty");
+    cell.colSpan = 5;
+    row.append(cell);
+    body.append(row);
+    return;
+  }
+  for (const event of events) {
+    const row = el("tr");
+    row.append(
+      el("td", new Date(event.time).toLocaleTimeString()),
+      el("td", event.pairs.join(", ")),
+      el("td", event.reason),
+      el("td", event.success ? "ok" : "failed", event.success ? "" : "failed"),
+      el("td", event.duration_ms + " ms", "num"),
+    );
+    body.append(row);
+  }
+}
+
+function renderStatus(status) {
+  const connections = status.exchange.connections || [];
+  const shards = connections.length > 1
+    ? " (" + connections.filter((c) => c.connected).length + "/" + connections.length + " shards)"
+    : "";
+  if (!status.exchange.streaming) {
+    setBadge("exchange", "exchange: polling", "");
+  } else if (status.exchange.connected) {
+    setBadge("exchange", "exchange: connected" + shards, "ok");
+  } else {
+    setBadge("exchange", "exchange: disconnected" + shards, "error");
+  }
+
+  const cache = status.cache;
+  document.getElementById("cache-entries").textContent = cache.entries + " / " + cache.supported_pairs;
+  document.getElementById("cache-missing").textContent = cache.missing_pairs.length ? cache.missing_pairs.join(", ") : "none";
+  document.getElementById("cache-oldest").textContent = cache.entries ? formatAge(cache.oldest_age_seconds) : "–";
+  document.getElementById("cache-newest").textContent = cache.entries ? formatAge(cache.newest_age_seconds) : "–";
+
+  renderFallbacks(status.recent_fallbacks);
+  document.getElementById("updated").textContent = new Date(status.timestamp).toLocaleTimeString();
+}
+
+async function refreshStatus() {
+  try {
+    const response = await fetch("status", { headers: { Accept: "application/json" } });
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({}));
+      throw new Error(error.message || response.statusText);
+    }
+    renderStatus(await response.json());
+  } catch (err) {
+    setBadge("exchange", "status: " + err.message, "error");
+  }
+}
+
+function openStream() {
+  const source = new EventSource("events");
+  source.onopen = () => setBadge("stream", "stream: live", "ok");
+  source.onerror = () => setBadge("stream", "stream: reconnecting", "error");
+  source.addEventListener("price", (message) => {
+    const price = JSON.parse(message.data);
+    prices.set(price.pair, price);
+    renderPrices(price.pair);
+  });
+}
+
+openStream();
+refreshStatus();
+setInterval(refreshStatus, STATUS_INTERVAL_MS);
+setInterval(() => renderPrices(null), 1000);
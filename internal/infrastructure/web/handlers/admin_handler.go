@@ -0,0 +1,764 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// maxAuditEventsLimit es el máximo de eventos que devuelve GET /admin/events
+const maxAuditEventsLimit = 1000
+
+// AdminHandler expone operaciones de administración (invalidación de caché,
+// reconexión, feature flags, tenants, estado del servicio, configuración efectiva)
+type AdminHandler struct {
+	invalidator    interfaces.PriceCacheInvalidator
+	reconnector    interfaces.Reconnectable
+	flags          interfaces.FeatureFlagManager
+	tenants        interfaces.TenantManager
+	usage          interfaces.UsageReporter
+	audit          interfaces.AuditLog
+	prices         interfaces.PriceService
+	fallbacks      interfaces.FallbackHistory
+	feedQuality    interfaces.FeedQualityReporter
+	subscriptions  interfaces.SubscriptionReporter
+	config         *config.Config
+	startup        interfaces.StartupReporter
+	drainer        interfaces.Drainer
+	supportedPairs []string
+}
+
+// NewAdminHandler crea un handler de administración
+func NewAdminHandler(invalidator interfaces.PriceCacheInvalidator, supportedPairs []string) *AdminHandler {
+	return &AdminHandler{
+		invalidator:    invalidator,
+		supportedPairs: supportedPairs,
+	}
+}
+
+// WithReconnector habilita la reconexión forzada del exchange; nil la deshabilita
+func (h *AdminHandler) WithReconnector(reconnector interfaces.Reconnectable) *AdminHandler {
+	h.reconnector = reconnector
+	return h
+}
+
+// WithFeatureFlags habilita los endpoints /admin/flags; nil los deshabilita
+func (h *AdminHandler) WithFeatureFlags(flags interfaces.FeatureFlagManager) *AdminHandler {
+	h.flags = flags
+	return h
+}
+
+// WithTenants habilita los endpoints /admin/tenants; nil los deshabilita
+func (h *AdminHandler) WithTenants(tenants interfaces.TenantManager) *AdminHandler {
+	h.tenants = tenants
+	return h
+}
+
+// WithUsage habilita GET /admin/usage; nil lo deshabilita
+func (h *AdminHandler) WithUsage(usage interfaces.UsageReporter) *AdminHandler {
+	h.usage = usage
+	return h
+}
+
+// WithAuditLog habilita GET /admin/events; nil lo deshabilita
+func (h *AdminHandler) WithAuditLog(audit interfaces.AuditLog) *AdminHandler {
+	h.audit = audit
+	return h
+}
+
+// WithPriceService habilita GET /admin/status; nil lo deshabilita
+func (h *AdminHandler) WithPriceService(prices interfaces.PriceService) *AdminHandler {
+	h.prices = prices
+	return h
+}
+
+// WithFallbackHistory incluye las activaciones recientes del fallback en /admin/status
+func (h *AdminHandler) WithFallbackHistory(fallbacks interfaces.FallbackHistory) *AdminHandler {
+	h.fallbacks = fallbacks
+	return h
+}
+
+// WithFeedQuality habilita GET /admin/feed-quality; nil lo deshabilita
+func (h *AdminHandler) WithFeedQuality(feedQuality interfaces.FeedQualityReporter) *AdminHandler {
+	h.feedQuality = feedQuality
+	return h
+}
+
+// WithSubscriptions habilita GET /admin/pairs; nil lo deshabilita
+func (h *AdminHandler) WithSubscriptions(subscriptions interfaces.SubscriptionReporter) *AdminHandler {
+	h.subscriptions = subscriptions
+	return h
+}
+
+// WithConfig habilita GET /admin/config con la configuración efectiva; nil lo deshabilita
+func (h *AdminHandler) WithConfig(cfg *config.Config) *AdminHandler {
+	h.config = cfg
+	return h
+}
+
+// WithStartupReport habilita GET /admin/startup-report; nil lo deshabilita
+func (h *AdminHandler) WithStartupReport(startup interfaces.StartupReporter) *AdminHandler {
+	h.startup = startup
+	return h
+}
+
+// WithDrainer habilita POST /admin/drain; nil lo deshabilita
+func (h *AdminHandler) WithDrainer(drainer interfaces.Drainer) *AdminHandler {
+	h.drainer = drainer
+	return h
+}
+
+// InvalidateCache godoc
+// @Summary Invalidate cached prices
+// @Description Purges cached prices for the given pairs, or every cached price with all=true, without restarting the service. Exactly one of pair or all must be provided.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param pair query string false "Trading pairs separated by commas to invalidate (e.g., BTC/USD,ETH/USD)"
+// @Param all query bool false "Flush every cached price"
+// @Success 200 {object} dto.CacheInvalidationResponse "Cached prices invalidated"
+// @Failure 400 {object} dto.ErrorResponse "Invalid or missing parameters"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to invalidate cache"
+// @Router /admin/cache [delete]
+func (h *AdminHandler) InvalidateCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	pairsParam := query.Get("pair")
+
+	flushAll := false
+	if allParam := query.Get("all"); allParam != "" {
+		parsed, err := strconv.ParseBool(allParam)
+		if err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "all must be a boolean")
+			return
+		}
+		flushAll = parsed
+	}
+
+	switch {
+	case flushAll && pairsParam != "":
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "use either pair or all=true, not both")
+		return
+	case flushAll:
+		removed, err := h.invalidator.FlushPrices(ctx)
+		if err != nil {
+			logging.ErrorWithError(ctx, "Failed to flush cached prices", err, nil)
+			h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to flush cached prices")
+			return
+		}
+		logging.Info(ctx, "Admin flushed price cache", logging.Fields{
+			"removed": removed,
+		})
+		h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewFlushInvalidationResponse(removed))
+	case pairsParam == "":
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeMissingParameter, "pair or all=true is required")
+		return
+	default:
+		request, err := dto.NewGetLTPRequest(pairsParam, h.supportedPairs)
+		if err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+			return
+		}
+		if err := h.invalidator.InvalidatePrices(ctx, request.Pairs); err != nil {
+			logging.ErrorWithError(ctx, "Failed to invalidate cached prices", err, logging.Fields{
+				"pairs": request.Pairs,
+			})
+			h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to invalidate cached prices")
+			return
+		}
+		logging.Info(ctx, "Admin invalidated cached prices", logging.Fields{
+			"pairs": request.Pairs,
+		})
+		h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewPairsInvalidationResponse(request.Pairs))
+	}
+}
+
+// ReconnectExchange godoc
+// @Summary Force exchange reconnection
+// @Description Closes and re-establishes the exchange streaming connection. Requests fall back to REST while reconnecting.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.ReconnectResponse "Exchange reconnected"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Exchange does not support reconnection"
+// @Failure 502 {object} dto.ReconnectResponse "Reconnection failed"
+// @Router /admin/exchange/reconnect [post]
+func (h *AdminHandler) ReconnectExchange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reconnector == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "the configured exchange does not support reconnection")
+		return
+	}
+
+	if err := h.reconnector.Reconnect(ctx); err != nil {
+		logging.ErrorWithError(ctx, "Admin exchange reconnection failed", err, nil)
+		h.writeJSONResponse(w, ctx, http.StatusBadGateway, dto.ReconnectResponse{
+			Connected: h.reconnector.IsConnected(),
+			Message:   "Reconnection failed: " + err.Error(),
+		})
+		return
+	}
+
+	logging.Info(ctx, "Admin forced exchange reconnection", nil)
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.ReconnectResponse{
+		Connected: h.reconnector.IsConnected(),
+		Message:   "Exchange reconnected",
+	})
+}
+
+// Drain godoc
+// @Summary Start draining before shutdown
+// @Description Marks the instance not ready (/ready answers 503), rejects new SSE and WebSocket subscriptions, tells the open ones to reconnect elsewhere and stops keeping connections alive. Meant to be called from a Kubernetes preStop hook before SIGTERM; regular requests are still served. Calling it again only reports the streams still open.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.DrainResponse "Instance draining"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Draining is not configured"
+// @Router /admin/drain [post]
+func (h *AdminHandler) Drain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.drainer == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "draining is not configured")
+		return
+	}
+
+	streams := h.drainer.Drain(ctx)
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.DrainResponse{
+		Draining:    true,
+		OpenStreams: streams,
+		Message:     "Instance draining, not ready for new traffic",
+	})
+}
+
+// Status godoc
+// @Summary Service status
+// @Description Exchange connection state, cached prices summary and the latest REST fallbacks. Used by the admin dashboard at /admin/.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.AdminStatusResponse "Service status"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to read cached prices"
+// @Failure 501 {object} dto.ErrorResponse "Status is not configured"
+// @Router /admin/status [get]
+func (h *AdminHandler) Status(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.prices == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "status is not configured")
+		return
+	}
+
+	prices, err := h.prices.GetCachedPrices(ctx)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to read cached prices for admin status", err, nil)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to get cached prices")
+		return
+	}
+
+	var exchange dto.ExchangeStatusData
+	if h.reconnector != nil {
+		exchange = dto.ExchangeStatusData{Streaming: true, Connected: h.reconnector.IsConnected()}
+		if pool, ok := h.reconnector.(interfaces.ConnectionPool); ok {
+			exchange.Connections = dto.NewConnectionStatusData(pool.Connections())
+		}
+	}
+	var fallbacks []interfaces.FallbackEvent
+	if h.fallbacks != nil {
+		fallbacks = h.fallbacks.RecentFallbacks()
+	}
+
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewAdminStatusResponse(time.Now(), exchange, prices, h.supportedPairs, fallbacks))
+}
+
+// GetFeedQuality godoc
+// @Summary Price feed quality
+// @Description Per supported pair, rolling in-memory statistics of the WebSocket feed: received ticks per minute, drop rate, average interval between ticks, last REST fallback and last measured divergence vs REST.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.AdminFeedQualityResponse "Feed quality per pair"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Feed quality is not available for the exchange"
+// @Router /admin/feed-quality [get]
+func (h *AdminHandler) GetFeedQuality(w http.ResponseWriter, r *http.Request) {
+	if h.feedQuality == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "feed quality is not available for the exchange")
+		return
+	}
+	report := h.feedQuality.FeedQuality(h.supportedPairs)
+	h.writeJSONResponse(w, r.Context(), http.StatusOK, dto.NewAdminFeedQualityResponse(time.Now(), report))
+}
+
+// GetPairs godoc
+// @Summary WebSocket subscription state per pair
+// @Description Per supported pair, the state of the WebSocket ticker subscription. Pairs the exchange rejects are retried with exponential backoff; after repeated errors they are excluded and served from REST until the exchange is reconnected (POST /admin/exchange/reconnect).
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.AdminPairsResponse "Subscription state per pair"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Subscription state is not available for the exchange"
+// @Router /admin/pairs [get]
+func (h *AdminHandler) GetPairs(w http.ResponseWriter, r *http.Request) {
+	if h.subscriptions == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "subscription state is not available for the exchange")
+		return
+	}
+	subscriptions := h.subscriptions.PairSubscriptions(h.supportedPairs)
+	h.writeJSONResponse(w, r.Context(), http.StatusOK, dto.NewAdminPairsResponse(time.Now(), subscriptions))
+}
+
+// GetConfig godoc
+// @Summary Effective configuration
+// @Description Effective runtime configuration with secrets redacted, plus where every value came from (default, config file or environment variable) to debug environment drift.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.AdminConfigResponse "Effective configuration"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to encode configuration"
+// @Failure 501 {object} dto.ErrorResponse "Configuration viewer is not configured"
+// @Router /admin/config [get]
+func (h *AdminHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.config == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "configuration viewer is not configured")
+		return
+	}
+
+	values, err := h.config.Values()
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to encode effective configuration", err, nil)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeEncodingError, "Failed to encode configuration")
+		return
+	}
+
+	provenance := h.config.Provenance()
+	sources := make(map[string]dto.ConfigSourceData)
+	for _, key := range config.Keys() {
+		source := provenance.Source(key)
+		sources[key] = dto.ConfigSourceData{Source: source.Source, From: source.From}
+	}
+
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.AdminConfigResponse{
+		Environment: provenance.Environment,
+		Files:       append([]string{}, provenance.Files...),
+		Config:      values,
+		Sources:     sources,
+	})
+}
+
+// GetStartupReport godoc
+// @Summary Startup report
+// @Description How the service started: duration of each component's initialization and start, cache warm-up results and configuration warnings, to diagnose slow starts. ready_at is omitted while the service is still starting.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.AdminStartupReportResponse "Startup report"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Startup report is not configured"
+// @Router /admin/startup-report [get]
+func (h *AdminHandler) GetStartupReport(w http.ResponseWriter, r *http.Request) {
+	if h.startup == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "startup report is not configured")
+		return
+	}
+	h.writeJSONResponse(w, r.Context(), http.StatusOK, dto.NewAdminStartupReportResponse(h.startup.StartupReport()))
+}
+
+// ListFeatureFlags godoc
+// @Summary List feature flags
+// @Description Lists the declared feature flags with their default and effective state.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.FeatureFlagsResponse "Declared feature flags"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Feature flags are not configured"
+// @Router /admin/flags [get]
+func (h *AdminHandler) ListFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.flags == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "feature flags are not configured")
+		return
+	}
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewFeatureFlagsResponse(h.flags.Snapshot(), h.flags.OverridesEnabled()))
+}
+
+// SetFeatureFlag godoc
+// @Summary Override a feature flag
+// @Description Overrides a declared feature flag at runtime. The override is stored in the state repository and picked up by every replica within feature_flags.refresh_interval.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Flag name"
+// @Param request body dto.FeatureFlagOverrideRequest true "New flag state"
+// @Success 200 {object} dto.FeatureFlagsResponse "Updated feature flags"
+// @Failure 400 {object} dto.ErrorResponse "Invalid body"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 404 {object} dto.ErrorResponse "Unknown feature flag"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Runtime overrides are disabled"
+// @Router /admin/flags/{name} [put]
+func (h *AdminHandler) SetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	var body dto.FeatureFlagOverrideRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidBody, "invalid JSON body: "+err.Error())
+		return
+	}
+	if body.Enabled == nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidBody, "enabled is required")
+		return
+	}
+
+	h.updateFeatureFlag(w, r, func(ctx context.Context, name string) error {
+		return h.flags.SetOverride(ctx, name, *body.Enabled)
+	})
+}
+
+// ClearFeatureFlag godoc
+// @Summary Clear a feature flag override
+// @Description Removes the runtime override of a feature flag, restoring its configured default.
+// @Tags admin
+// @Produce json
+// @Param name path string true "Flag name"
+// @Success 200 {object} dto.FeatureFlagsResponse "Updated feature flags"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 404 {object} dto.ErrorResponse "Unknown feature flag"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Runtime overrides are disabled"
+// @Router /admin/flags/{name} [delete]
+func (h *AdminHandler) ClearFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	h.updateFeatureFlag(w, r, func(ctx context.Context, name string) error {
+		return h.flags.ClearOverride(ctx, name)
+	})
+}
+
+// updateFeatureFlag aplica update al flag de la ruta y responde con el estado actualizado
+func (h *AdminHandler) updateFeatureFlag(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, name string) error) {
+	ctx := r.Context()
+	if h.flags == nil || !h.flags.OverridesEnabled() {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "feature flag overrides are disabled")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if err := update(ctx, name); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrUnknownFeatureFlag):
+			h.writeErrorResponse(w, r, http.StatusNotFound, dto.CodeUnknownFlag, err.Error())
+		case errors.Is(err, interfaces.ErrFeatureFlagOverridesDisabled):
+			h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, err.Error())
+		default:
+			logging.ErrorWithError(ctx, "Failed to update feature flag", err, logging.Fields{"flag": name})
+			h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeInternalError, "failed to update feature flag")
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewFeatureFlagsResponse(h.flags.Snapshot(), h.flags.OverridesEnabled()))
+}
+
+// ListTenants godoc
+// @Summary List tenants
+// @Description Lists the tenants with their allowed pairs and rate limit. API keys are never returned, only how many each tenant has.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.TenantsResponse "Tenants"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 403 {object} dto.ErrorResponse "Tenant API keys cannot use admin endpoints"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Multi-tenancy is not enabled"
+// @Router /admin/tenants [get]
+func (h *AdminHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.tenants == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "multi-tenancy is not enabled")
+		return
+	}
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewTenantsResponse(h.tenants.Tenants(), h.tenants.ManagementEnabled()))
+}
+
+// PutTenant godoc
+// @Summary Create or replace a tenant
+// @Description Creates or replaces a runtime-managed tenant. It is stored in the state repository (API keys hashed) and picked up by every replica within tenancy.refresh_interval. Tenants declared in configuration cannot be changed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Tenant name"
+// @Param request body dto.TenantRequest true "Tenant definition"
+// @Success 200 {object} dto.TenantsResponse "Updated tenants"
+// @Failure 400 {object} dto.ErrorResponse "Invalid body"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 403 {object} dto.ErrorResponse "Tenant API keys cannot use admin endpoints"
+// @Failure 409 {object} dto.ErrorResponse "Tenant is read-only or an API key is already in use"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Runtime tenant management is disabled"
+// @Router /admin/tenants/{name} [put]
+func (h *AdminHandler) PutTenant(w http.ResponseWriter, r *http.Request) {
+	var body dto.TenantRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidBody, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	tenant, err := h.tenantFromRequest(mux.Vars(r)["name"], body)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidBody, err.Error())
+		return
+	}
+
+	h.updateTenant(w, r, func(ctx context.Context, _ string) error {
+		return h.tenants.PutTenant(ctx, tenant)
+	})
+}
+
+// DeleteTenant godoc
+// @Summary Delete a tenant
+// @Description Deletes a runtime-managed tenant; its API keys stop working on every replica within tenancy.refresh_interval.
+// @Tags admin
+// @Produce json
+// @Param name path string true "Tenant name"
+// @Success 200 {object} dto.TenantsResponse "Updated tenants"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 403 {object} dto.ErrorResponse "Tenant API keys cannot use admin endpoints"
+// @Failure 404 {object} dto.ErrorResponse "Unknown tenant"
+// @Failure 409 {object} dto.ErrorResponse "Tenant is declared in configuration"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 501 {object} dto.ErrorResponse "Runtime tenant management is disabled"
+// @Router /admin/tenants/{name} [delete]
+func (h *AdminHandler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	h.updateTenant(w, r, func(ctx context.Context, name string) error {
+		return h.tenants.DeleteTenant(ctx, name)
+	})
+}
+
+// GetUsage godoc
+// @Summary API usage per key
+// @Description Reports requests and streamed price events per API key and UTC day, aggregated across replicas, for billing and chargeback. Keys are identified by the first 12 hex digits of their SHA-256. Counters of other replicas are included once they flush (usage.flush_interval).
+// @Tags admin
+// @Produce json
+// @Param from query string false "First UTC day, YYYY-MM-DD (default: to)"
+// @Param to query string false "Last UTC day, YYYY-MM-DD (default: today)"
+// @Param tenant query string false "Only report this tenant"
+// @Success 200 {object} dto.UsageResponse "Usage per key and day"
+// @Failure 400 {object} dto.ErrorResponse "Invalid date range"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 403 {object} dto.ErrorResponse "Tenant API keys cannot use admin endpoints"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to read usage"
+// @Failure 501 {object} dto.ErrorResponse "Usage accounting is not enabled"
+// @Router /admin/usage [get]
+func (h *AdminHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.usage == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "usage accounting is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(interfaces.UsageDateLayout, value)
+		if err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+		to = parsed
+	}
+	from := to
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(interfaces.UsageDateLayout, value)
+		if err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "from must not be after to")
+		return
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > h.usage.RetentionDays() {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter,
+			fmt.Sprintf("the period cannot exceed the usage retention of %d days", h.usage.RetentionDays()))
+		return
+	}
+
+	records, err := h.usage.Usage(ctx, from, to, query.Get("tenant"))
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to read usage", err, nil)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeInternalError, "failed to read usage")
+		return
+	}
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewUsageResponse(from.Format(interfaces.UsageDateLayout), to.Format(interfaces.UsageDateLayout), records))
+}
+
+// tenantFromRequest valida el cuerpo de PUT /admin/tenants/{name}
+// GetEvents godoc
+// @Summary Fallback and reconnect audit trail
+// @Description Lists the REST fallback activations and WebSocket reconnects persisted in the state repository by every replica, newest first, for postmortems that outlive log retention. Events of other replicas are included once they flush (audit.flush_interval).
+// @Tags admin
+// @Produce json
+// @Param type query string false "Only this event type" Enums(fallback, reconnect)
+// @Param reason query string false "Only this reason (e.g., timeout, connection_closed, forced)"
+// @Param pair query string false "Only events that affected this pair (e.g., BTC/USD)"
+// @Param from query string false "Events at or after this time, RFC 3339"
+// @Param to query string false "Events at or before this time, RFC 3339"
+// @Param limit query int false "Maximum events returned, newest first (1-1000, default 100)"
+// @Success 200 {object} dto.AuditEventsResponse "Matching events"
+// @Failure 400 {object} dto.ErrorResponse "Invalid filter"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to read events"
+// @Failure 501 {object} dto.ErrorResponse "Audit trail is not enabled"
+// @Router /admin/events [get]
+func (h *AdminHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.audit == nil {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "audit trail is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := interfaces.AuditEventFilter{
+		Type:   query.Get("type"),
+		Reason: query.Get("reason"),
+		Pair:   query.Get("pair"),
+	}
+	if filter.Type != "" && filter.Type != interfaces.AuditEventFallback && filter.Type != interfaces.AuditEventReconnect {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "type must be fallback or reconnect")
+		return
+	}
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "from must be an RFC 3339 time")
+			return
+		}
+		filter.From = parsed
+	}
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "to must be an RFC 3339 time")
+			return
+		}
+		filter.To = parsed
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, "from must not be after to")
+		return
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAuditEventsLimit {
+			h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter,
+				fmt.Sprintf("limit must be an integer between 1 and %d", maxAuditEventsLimit))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.audit.Events(ctx, filter)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to read audit events", err, nil)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeInternalError, "failed to read events")
+		return
+	}
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewAuditEventsResponse(h.audit.RetentionDays(), events))
+}
+
+func (h *AdminHandler) tenantFromRequest(name string, body dto.TenantRequest) (entities.Tenant, error) {
+	tenant := entities.Tenant{Name: name, APIKeys: body.APIKeys}
+	if len(body.APIKeys) == 0 {
+		return tenant, errors.New("api_keys is required")
+	}
+	for _, key := range body.APIKeys {
+		if key == "" {
+			return tenant, errors.New("api_keys cannot contain empty keys")
+		}
+	}
+	for _, pair := range body.Pairs {
+		canonical := entities.CanonicalPair(pair)
+		if !slices.Contains(h.supportedPairs, canonical) {
+			return tenant, fmt.Errorf("pair %s is not supported", pair)
+		}
+		tenant.Pairs = append(tenant.Pairs, canonical)
+	}
+	if limit := body.RateLimit; limit != nil {
+		if limit.Capacity <= 0 || limit.RefillRate <= 0 {
+			return tenant, errors.New("rate_limit capacity and refill_rate must be positive")
+		}
+		tenant.RateLimitCapacity = limit.Capacity
+		tenant.RateLimitRefillRate = limit.RefillRate
+	}
+	return tenant, nil
+}
+
+// updateTenant aplica update al tenant de la ruta y responde con los tenants actualizados
+func (h *AdminHandler) updateTenant(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, name string) error) {
+	ctx := r.Context()
+	if h.tenants == nil || !h.tenants.ManagementEnabled() {
+		h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, "runtime tenant management is disabled")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if err := update(ctx, name); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrUnknownTenant):
+			h.writeErrorResponse(w, r, http.StatusNotFound, dto.CodeUnknownTenant, err.Error())
+		case errors.Is(err, interfaces.ErrTenantReadOnly):
+			h.writeErrorResponse(w, r, http.StatusConflict, dto.CodeTenantReadOnly, err.Error())
+		case errors.Is(err, interfaces.ErrTenantConflict):
+			h.writeErrorResponse(w, r, http.StatusConflict, dto.CodeTenantConflict, err.Error())
+		case errors.Is(err, interfaces.ErrTenantManagementDisabled):
+			h.writeErrorResponse(w, r, http.StatusNotImplemented, dto.CodeNotSupported, err.Error())
+		default:
+			logging.ErrorWithError(ctx, "Failed to update tenant", err, logging.Fields{"tenant": name})
+			h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeInternalError, "failed to update tenant")
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewTenantsResponse(h.tenants.Tenants(), h.tenants.ManagementEnabled()))
+}
+
+// writeJSONResponse writes a JSON response preserving the request context for logging
+func (h *AdminHandler) writeJSONResponse(w http.ResponseWriter, ctx context.Context, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logging.ErrorWithError(ctx, "Failed to encode JSON response", err, logging.Fields{
+			"status_code": statusCode,
+		})
+	}
+}
+
+// writeErrorResponse writes a standard API error response
+func (h *AdminHandler) writeErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, errorCode, message string) {
+	apierror.Write(w, r, statusCode, errorCode, message)
+}
@@ -3,22 +3,45 @@ package handlers
 import (
 	"btc-ltp-service/internal/application/dto"
 	"btc-ltp-service/internal/domain/interfaces"
+	"context"
 	"encoding/json"
 	"net/http"
 )
 
+// ReadinessCheck retorna un error mientras el componente no esté listo
+type ReadinessCheck func(ctx context.Context) error
+
+// ReadinessDetail describe el estado de un componente informativo en /ready;
+// no afecta el resultado del chequeo
+type ReadinessDetail func(ctx context.Context) string
+
 // HealthHandler maneja los endpoints de health check
 type HealthHandler struct {
-	priceService interfaces.PriceService
+	priceService    interfaces.PriceService
+	readinessChecks map[string]ReadinessCheck
+	readinessDetail map[string]ReadinessDetail
 }
 
 // NewHealthHandler crea una nueva instancia del health handler
 func NewHealthHandler(priceService interfaces.PriceService) *HealthHandler {
 	return &HealthHandler{
-		priceService: priceService,
+		priceService:    priceService,
+		readinessChecks: make(map[string]ReadinessCheck),
+		readinessDetail: make(map[string]ReadinessDetail),
 	}
 }
 
+// AddReadinessCheck registra un chequeo adicional evaluado por /ready
+func (h *HealthHandler) AddReadinessCheck(name string, check ReadinessCheck) {
+	h.readinessChecks[name] = check
+}
+
+// AddReadinessDetail registra un detalle informativo incluido en /ready (p. ej.
+// la ventana de mantenimiento activa)
+func (h *HealthHandler) AddReadinessDetail(name string, detail ReadinessDetail) {
+	h.readinessDetail[name] = detail
+}
+
 // Health godoc
 // @Summary Basic health check
 // @Description Verifies that the service is running correctly. Responds quickly without checking external dependencies.
@@ -59,6 +82,23 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	}
 
 	services["cache"] = "ready"
+
+	// Chequeos registrados (p. ej. warm-up de pares requeridos)
+	for name, check := range h.readinessChecks {
+		if err := check(ctx); err != nil {
+			services[name] = "error: " + err.Error()
+			response := dto.NewHealthResponse("unhealthy", services)
+			h.writeJSONResponse(w, http.StatusServiceUnavailable, response)
+			return
+		}
+		services[name] = "ready"
+	}
+
+	// Detalles informativos (p. ej. ventana de mantenimiento)
+	for name, detail := range h.readinessDetail {
+		services[name] = detail(ctx)
+	}
+
 	services["service"] = "ready"
 
 	response := dto.NewHealthResponse("ready", services)
@@ -73,6 +113,6 @@ func (h *HealthHandler) writeJSONResponse(w http.ResponseWriter, statusCode int,
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		// If encoding fails, write basic error response
 		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte(`{"error":"ENCODING_ERROR","message":"Failed to encode response"}`))
+		_, _ = w.Write([]byte(`{"code":"ENCODING_ERROR","message":"Failed to encode response","error":"ENCODING_ERROR"}`))
 	}
 }
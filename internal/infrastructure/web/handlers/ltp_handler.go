@@ -5,17 +5,31 @@ import (
 	"btc-ltp-service/internal/domain/entities"
 	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// DefaultMaxBulkPairs es el máximo de pares por POST /api/v1/ltp si no se configura otro
+const DefaultMaxBulkPairs = 100
+
+// maxBulkBodyBytes acota el cuerpo de POST /api/v1/ltp
+const maxBulkBodyBytes = 1 << 20
+
 // LTPHandler handles requests related to Last Traded Prices
 type LTPHandler struct {
 	priceService   interfaces.PriceService
 	mapper         *dto.PriceMapper
 	supportedPairs []string
+	maxBulkPairs   int
+	priceFormat    *dto.PriceFormat
+	onDemand       interfaces.PairAdmitter
 }
 
 // NewLTPHandler creates a new instance of the LTP handler
@@ -24,22 +38,163 @@ func NewLTPHandler(priceService interfaces.PriceService, supportedPairs []string
 		priceService:   priceService,
 		mapper:         dto.NewPriceMapper(),
 		supportedPairs: supportedPairs,
+		maxBulkPairs:   DefaultMaxBulkPairs,
+	}
+}
+
+// WithMaxBulkPairs configura el máximo de pares aceptados por POST /api/v1/ltp (0 usa el default)
+func (h *LTPHandler) WithMaxBulkPairs(maxPairs int) *LTPHandler {
+	if maxPairs > 0 {
+		h.maxBulkPairs = maxPairs
 	}
+	return h
+}
+
+// WithPriceFormat redondea los importes según su moneda cotizada; nil los sirve sin cambios
+func (h *LTPHandler) WithPriceFormat(format *dto.PriceFormat) *LTPHandler {
+	h.priceFormat = format
+	return h
+}
+
+// WithOnDemandPairs acepta pares fuera de los soportados si onDemand los admite;
+// nil los rechaza con 400
+func (h *LTPHandler) WithOnDemandPairs(onDemand interfaces.PairAdmitter) *LTPHandler {
+	h.onDemand = onDemand
+	return h
 }
 
 // GetLTP maneja GET /api/v1/ltp?pair=BTC/USD,ETH/USD
-// Si no se proporciona el parámetro 'pair', devuelve todos los pares soportados
+// Con pair=* o sin el parámetro 'pair', devuelve los pares soportados que están en
+// caché, sin disparar consultas al exchange
 func (h *LTPHandler) GetLTP(w http.ResponseWriter, r *http.Request) {
 	// 1. Parse query parameters (optional - if empty, use default pairs)
 	pairsParam := r.URL.Query().Get("pair")
 
+	opts, err := h.parseListOptions(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+	format, err := priceFormatFor(r, h.priceFormat)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+
 	// 2. Crear y validar request DTO con pares soportados como fallback
-	request, err := dto.NewGetLTPRequest(pairsParam, h.supportedPairs)
+	supported := h.supportedPairs
+	if trimmed := strings.TrimSpace(pairsParam); trimmed != "" && trimmed != dto.WildcardPair {
+		supported = h.admit(r.Context(), strings.Split(pairsParam, ","))
+	}
+	request, err := dto.NewGetLTPRequest(pairsParam, tenantPairs(r.Context(), supported))
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+
+	if request.Wildcard {
+		h.respondWithCachedPrices(w, r, opts, format)
+		return
+	}
+
+	// Sólo se consultan los pares de la página solicitada
+	var page *dto.Pagination
+	request.Pairs, page = opts.ApplyToPairs(request.Pairs)
+
+	h.respondWithPrices(w, r, request, page, format)
+}
+
+// PostLTP maneja POST /api/v1/ltp con cuerpo {"pairs": [...]} para consultas que
+// no caben en un query string; responde con el mismo esquema que GET. El campo
+// opcional max_age fija la edad máxima aceptada por par.
+func (h *LTPHandler) PostLTP(w http.ResponseWriter, r *http.Request) {
+	format, err := priceFormatFor(r, h.priceFormat)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+
+	var body dto.PostLTPRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidBody, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	supported := h.supportedPairs
+	if len(body.Pairs) <= h.maxBulkPairs {
+		supported = h.admit(r.Context(), body.Pairs)
+	}
+	request, err := dto.NewPostLTPRequest(body, tenantPairs(r.Context(), supported), h.maxBulkPairs)
+	if errors.Is(err, dto.ErrTooManyPairs) {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeTooManyPairs, err.Error())
+		return
+	}
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_PARAMETER", err.Error())
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
 		return
 	}
 
+	h.respondWithPrices(w, r, request, nil, format)
+}
+
+// admit retorna los pares soportados más los admitidos bajo demanda de requested
+func (h *LTPHandler) admit(ctx context.Context, requested []string) []string {
+	if h.onDemand == nil {
+		return h.supportedPairs
+	}
+	return h.onDemand.Admit(ctx, requested)
+}
+
+// priceErrorClass es la traducción de un error de dominio a la respuesta por par
+type priceErrorClass struct {
+	err     error
+	code    string
+	message string
+	status  int
+}
+
+// priceErrorClasses mapea los errores tipados del PriceService a código y status;
+// se evalúan en orden con errors.Is y el primero que coincide gana
+var priceErrorClasses = []priceErrorClass{
+	{interfaces.ErrPairUnsupported, dto.CodeUnsupportedPair, "Trading pair not supported", http.StatusNotFound},
+	{interfaces.ErrRateLimited, dto.CodeUpstreamRateLimited, "Exchange is rate limiting requests", http.StatusTooManyRequests},
+	{interfaces.ErrStaleData, dto.CodeStaleData, "Cached price is stale", http.StatusServiceUnavailable},
+	{interfaces.ErrUpstreamUnavailable, dto.CodeUpstreamUnavailable, "Price source unavailable", http.StatusServiceUnavailable},
+	{interfaces.ErrPriceDeviation, dto.CodePriceDeviation, "Price withheld: it deviates from the reference source", http.StatusServiceUnavailable},
+}
+
+// classifyPriceError retorna la clase de err, o PRICE_FETCH_ERROR con 503 si no
+// es un error tipado
+func classifyPriceError(err error) priceErrorClass {
+	for _, class := range priceErrorClasses {
+		if errors.Is(err, class.err) {
+			return class
+		}
+	}
+	return priceErrorClass{code: dto.CodePriceFetchError, message: "Failed to fetch price", status: http.StatusServiceUnavailable}
+}
+
+// commonStatus retorna el status compartido por todos los pares fallidos, o 503
+// si difieren
+func commonStatus(statuses []int) int {
+	if len(statuses) == 0 {
+		return http.StatusServiceUnavailable
+	}
+	for _, status := range statuses[1:] {
+		if status != statuses[0] {
+			return http.StatusServiceUnavailable
+		}
+	}
+	return statuses[0]
+}
+
+// respondWithPrices obtiene los precios de request y escribe la respuesta completa,
+// parcial (206) o de error según los resultados (el status de los errores tipados
+// si todos los pares coinciden, si no 503); page, si no es
+// nil, se incluye en la respuesta y format ajusta los decimales de los importes
+func (h *LTPHandler) respondWithPrices(w http.ResponseWriter, r *http.Request, request *dto.GetLTPRequest, page *dto.Pagination, format *dto.PriceFormat) {
 	// 3. Get prices from service
 	ctx := r.Context()
 
@@ -56,21 +211,26 @@ func (h *LTPHandler) GetLTP(w http.ResponseWriter, r *http.Request) {
 	// Collect prices and errors separately for partial handling
 	var allPrices []*entities.Price
 	var priceErrors []dto.PriceError
+	var statuses []int
+
+	prices := make([]*entities.Price, len(request.Pairs))
+	fetchErrors := make([]error, len(request.Pairs))
+	for i, pair := range request.Pairs {
+		prices[i], fetchErrors[i] = h.priceService.GetLastPrice(ctx, pair)
+	}
+	h.refreshStale(ctx, request, prices, fetchErrors)
 
-	for _, pair := range request.Pairs {
-		price, err := h.priceService.GetLastPrice(ctx, pair)
+	for i, pair := range request.Pairs {
+		price, err := prices[i], fetchErrors[i]
 		if err != nil {
 			logging.ErrorWithError(ctx, "Failed to get price for pair", err, logging.Fields{
 				"pair": pair,
 			})
 
 			// Add specific error for this pair instead of failing the entire request
-			priceErrors = append(priceErrors, dto.NewPriceError(
-				pair,
-				"Failed to fetch price",
-				"PRICE_FETCH_ERROR",
-				err.Error(),
-			))
+			class := classifyPriceError(err)
+			priceErrors = append(priceErrors, dto.NewPriceError(pair, class.message, class.code, err.Error()))
+			statuses = append(statuses, class.status)
 			continue // Continuar con los otros pares
 		}
 
@@ -86,6 +246,8 @@ func (h *LTPHandler) GetLTP(w http.ResponseWriter, r *http.Request) {
 	if len(priceErrors) == 0 {
 		// All successful - clean response
 		response := h.mapper.ToGetLTPResponse(allPrices)
+		response.Pagination = page
+		format.Apply(response.LTP)
 		h.writeJSONResponseWithContext(w, r.Context(), http.StatusOK, response)
 	} else if len(allPrices) == 0 {
 		// All failed – indicar indisponibilidad del servicio backend
@@ -95,7 +257,8 @@ func (h *LTPHandler) GetLTP(w http.ResponseWriter, r *http.Request) {
 		})
 
 		response := dto.NewGetLTPResponseWithErrors(allPrices, priceErrors)
-		h.writeJSONResponseWithContext(w, r.Context(), http.StatusServiceUnavailable, response)
+		response.Pagination = page
+		h.writeJSONResponseWithContext(w, r.Context(), commonStatus(statuses), response)
 	} else {
 		// Partial success - response with included errors
 		logging.Warn(ctx, "Partial success in price fetching", logging.Fields{
@@ -104,22 +267,99 @@ func (h *LTPHandler) GetLTP(w http.ResponseWriter, r *http.Request) {
 			"total_requested":  len(request.Pairs),
 		})
 		response := dto.NewGetLTPResponseWithErrors(allPrices, priceErrors)
+		response.Pagination = page
+		format.Apply(response.LTP)
 		h.writeJSONResponseWithContext(w, r.Context(), http.StatusPartialContent, response)
 	}
+	recordServedLatency(allPrices)
+}
+
+// refreshStale refresca en un único batch, desde la fuente, los pares con max_age
+// cuyo precio es más viejo o falta, y vuelve a leerlos; los demás no generan consultas al
+// exchange. Un par que sigue más viejo que su max_age queda con ErrStaleData.
+func (h *LTPHandler) refreshStale(ctx context.Context, request *dto.GetLTPRequest, prices []*entities.Price, fetchErrors []error) {
+	var stale []int
+	var pairs []string
+	for i, pair := range request.Pairs {
+		maxAge := request.MaxAgeFor(pair)
+		if maxAge <= 0 {
+			continue
+		}
+		refreshable := errors.Is(fetchErrors[i], interfaces.ErrStaleData) || errors.Is(fetchErrors[i], interfaces.ErrUpstreamUnavailable)
+		if refreshable || (fetchErrors[i] == nil && prices[i].Age > maxAge) {
+			stale = append(stale, i)
+			pairs = append(pairs, pair)
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	logging.Info(ctx, "Refreshing pairs older than their max_age", logging.Fields{
+		"pairs_count": len(pairs),
+		"pairs":       pairs,
+	})
+	// El refresco común respondería desde la caché del WebSocket, que puede tener
+	// el mismo dato viejo: se consulta la fuente directamente
+	refresh := h.priceService.RefreshPrices
+	if source, ok := h.priceService.(interfaces.SourceRefresher); ok {
+		refresh = source.RefreshPricesFromSource
+	}
+	if err := refresh(ctx, pairs); err != nil {
+		logging.Warn(ctx, "Refresh for max_age failed, serving what the cache has", logging.Fields{
+			"pairs": pairs,
+			"error": err.Error(),
+		})
+	}
+
+	for _, i := range stale {
+		pair := request.Pairs[i]
+		prices[i], fetchErrors[i] = h.priceService.GetLastPrice(ctx, pair)
+		if maxAge := request.MaxAgeFor(pair); fetchErrors[i] == nil && prices[i].Age > maxAge {
+			fetchErrors[i] = fmt.Errorf("%w: %s is %s old, max_age %s", interfaces.ErrStaleData, pair, prices[i].Age.Round(time.Millisecond), maxAge)
+			prices[i] = nil
+		}
+	}
+}
+
+// respondWithCachedPrices responde con todos los precios soportados presentes en
+// caché; los pares sin precio cacheado simplemente se omiten
+func (h *LTPHandler) respondWithCachedPrices(w http.ResponseWriter, r *http.Request, opts dto.ListOptions, format *dto.PriceFormat) {
+	ctx := r.Context()
+
+	cachedPrices, err := h.priceService.GetCachedPrices(ctx)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to get cached prices for wildcard query", err, nil)
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to get cached prices")
+		return
+	}
+
+	logging.Info(ctx, "Serving wildcard query from cache", logging.Fields{
+		"supported_count": len(h.supportedPairs),
+		"cached_count":    len(cachedPrices),
+	})
+
+	prices, page := opts.ApplyToPrices(tenantPrices(ctx, cachedPrices))
+	response := h.mapper.ToGetLTPResponse(prices)
+	response.Pagination = page
+	format.Apply(response.LTP)
+	h.writeJSONResponseWithContext(w, ctx, http.StatusOK, response)
+	recordServedLatency(prices)
 }
 
 // RefreshPrices maneja POST /api/v1/ltp/refresh (para casos de administración)
 func (h *LTPHandler) RefreshPrices(w http.ResponseWriter, r *http.Request) {
+	supportedPairs := tenantPairs(r.Context(), h.supportedPairs)
 
 	pairsParam := r.URL.Query().Get("pairs")
 	if pairsParam == "" {
 		// Default to all supported pairs when not specified
-		pairsParam = strings.Join(h.supportedPairs, ",")
+		pairsParam = strings.Join(supportedPairs, ",")
 	}
 
-	request, err := dto.NewGetLTPRequest(pairsParam, h.supportedPairs)
+	request, err := dto.NewGetLTPRequest(pairsParam, supportedPairs)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_PARAMETER", err.Error())
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
 		return
 	}
 
@@ -157,12 +397,23 @@ func (h *LTPHandler) RefreshPrices(w http.ResponseWriter, r *http.Request) {
 func (h *LTPHandler) GetCachedPrices(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	opts, err := h.parseListOptions(r)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+	format, err := priceFormatFor(r, h.priceFormat)
+	if err != nil {
+		h.writeErrorResponse(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+
 	logging.Info(ctx, "Fetching cached prices", nil)
 
 	cachedPrices, err := h.priceService.GetCachedPrices(ctx)
 	if err != nil {
 		logging.ErrorWithError(ctx, "Failed to get cached prices", err, nil)
-		h.writeErrorResponse(w, http.StatusInternalServerError, "CACHE_ERROR", "Failed to get cached prices")
+		h.writeErrorResponse(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to get cached prices")
 		return
 	}
 
@@ -171,8 +422,26 @@ func (h *LTPHandler) GetCachedPrices(w http.ResponseWriter, r *http.Request) {
 	})
 
 	// Convert to response DTO
-	response := h.mapper.ToGetLTPResponse(cachedPrices)
+	prices, page := opts.ApplyToPrices(tenantPrices(ctx, cachedPrices))
+	response := h.mapper.ToGetLTPResponse(prices)
+	response.Pagination = page
+	format.Apply(response.LTP)
 	h.writeJSONResponseWithContext(w, ctx, http.StatusOK, response)
+	recordServedLatency(prices)
+}
+
+// recordServedLatency registra, por par, el tiempo desde la recepción del precio
+// hasta que se sirvió en una respuesta HTTP
+func recordServedLatency(prices []*entities.Price) {
+	for _, price := range prices {
+		metrics.RecordPricePipelineLatency(price.Pair, price.Source, metrics.PipelineStageServed, price.ReceivedTime)
+	}
+}
+
+// parseListOptions lee los query parameters limit, offset y quote
+func (h *LTPHandler) parseListOptions(r *http.Request) (dto.ListOptions, error) {
+	query := r.URL.Query()
+	return dto.NewListOptions(query.Get("limit"), query.Get("offset"), query.Get("quote"))
 }
 
 // writeJSONResponse writes a JSON response (maintain backward compatibility)
@@ -205,8 +474,7 @@ func (h *LTPHandler) writeJSONResponseWithContext(w http.ResponseWriter, ctx con
 	}
 }
 
-// writeErrorResponse writes an error response
-func (h *LTPHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, errorCode, message string) {
-	errorResp := dto.NewErrorResponseWithCode(errorCode, message, "")
-	h.writeJSONResponse(w, statusCode, errorResp)
+// writeErrorResponse writes a standard API error response
+func (h *LTPHandler) writeErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, errorCode, message string) {
+	apierror.Write(w, r, statusCode, errorCode, message)
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// PairsHandler expone los pares soportados con sus metadatos
+type PairsHandler struct {
+	priceService   interfaces.PriceService
+	catalog        interfaces.PairCatalog
+	supportedPairs []string
+}
+
+// NewPairsHandler crea el handler; con catalog nil sólo se informan base y
+// cotización de cada par
+func NewPairsHandler(priceService interfaces.PriceService, catalog interfaces.PairCatalog, supportedPairs []string) *PairsHandler {
+	return &PairsHandler{
+		priceService:   priceService,
+		catalog:        catalog,
+		supportedPairs: supportedPairs,
+	}
+}
+
+// ListPairs godoc
+// @Summary Supported pairs
+// @Description Lists the supported pairs (for a tenant API key, the pairs allowed for the tenant) with their metadata: base and quote assets, price and volume decimals (when the exchange reports them), source exchange, whether the WebSocket ticker subscription is confirmed and when the cached price was last updated.
+// @Tags pairs
+// @Produce json
+// @Success 200 {object} dto.PairsResponse "Supported pairs"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to read cached prices"
+// @Router /pairs [get]
+func (h *PairsHandler) ListPairs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	prices, err := h.priceService.GetCachedPrices(ctx)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to read cached prices for pair metadata", err, nil)
+		apierror.Write(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to get cached prices")
+		return
+	}
+
+	h.writeJSONResponse(w, ctx, http.StatusOK, dto.NewPairsResponse(h.metadata(tenantPairs(ctx, h.supportedPairs)), tenantPrices(ctx, prices)))
+}
+
+// metadata consulta el catálogo del exchange o, sin catálogo, deriva base y cotización
+func (h *PairsHandler) metadata(pairs []string) []interfaces.PairMetadata {
+	if h.catalog != nil {
+		return h.catalog.PairMetadata(pairs)
+	}
+	metadata := make([]interfaces.PairMetadata, len(pairs))
+	for i, pair := range pairs {
+		base, quote, _ := entities.SplitPair(pair)
+		metadata[i] = interfaces.PairMetadata{Pair: pair, Base: base, Quote: quote}
+	}
+	return metadata
+}
+
+// writeJSONResponse writes a JSON response
+func (h *PairsHandler) writeJSONResponse(w http.ResponseWriter, ctx context.Context, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logging.ErrorWithError(ctx, "Failed to encode JSON response", err, logging.Fields{
+			"status_code": statusCode,
+		})
+	}
+}
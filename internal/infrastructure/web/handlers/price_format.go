@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// priceFormatFor devuelve las reglas de presentación a aplicar en la respuesta;
+// con ?raw=true los importes se sirven sin redondear (nil)
+func priceFormatFor(r *http.Request, format *dto.PriceFormat) (*dto.PriceFormat, error) {
+	rawParam := r.URL.Query().Get("raw")
+	if rawParam == "" {
+		return format, nil
+	}
+	raw, err := strconv.ParseBool(rawParam)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw: %s (expected true or false)", rawParam)
+	}
+	if raw {
+		return nil, nil
+	}
+	return format, nil
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// SlowClientPolicy define qué hacer cuando el buffer de un cliente de streaming se llena
+type SlowClientPolicy string
+
+const (
+	// SlowClientCoalesce descarta las actualizaciones intermedias pendientes y
+	// conserva la última de cada par
+	SlowClientCoalesce SlowClientPolicy = "coalesce"
+	// SlowClientDisconnect desconecta al cliente
+	SlowClientDisconnect SlowClientPolicy = "disconnect"
+
+	DefaultStreamBufferSize   = 256
+	DefaultStreamWriteTimeout = 10 * time.Second
+)
+
+// Streams y motivos de desconexión de las métricas de clientes lentos
+const (
+	streamSSE = "sse"
+	streamWS  = "ws"
+
+	slowClientBufferFull   = "buffer_full"
+	slowClientWriteTimeout = "write_timeout"
+)
+
+// StreamBuffering configura el buffer por conexión de /ltp/stream y /ws. Una
+// escritura bloqueada más de WriteTimeout desconecta al cliente con cualquier
+// política. El valor cero equivale a los defaults.
+type StreamBuffering struct {
+	BufferSize   int // Precios pendientes por conexión
+	WriteTimeout time.Duration
+	Policy       SlowClientPolicy
+	// MaxUpdatesPerSecond acota los envíos de cada par por conexión; los precios
+	// que llegan antes de tiempo se retienen y sólo se envía el último (0 = sin límite)
+	MaxUpdatesPerSecond float64
+}
+
+func (b StreamBuffering) withDefaults() StreamBuffering {
+	if b.BufferSize <= 0 {
+		b.BufferSize = DefaultStreamBufferSize
+	}
+	if b.WriteTimeout <= 0 {
+		b.WriteTimeout = DefaultStreamWriteTimeout
+	}
+	if b.Policy == "" {
+		b.Policy = SlowClientCoalesce
+	}
+	return b
+}
+
+// streamBuffer es la cola de precios pendientes de una conexión. pumpPrices la
+// llena desde la caché y el loop de escritura la vacía a la velocidad del cliente,
+// así un cliente lento no frena la lectura de la caché ni acumula memoria sin límite.
+type streamBuffer struct {
+	stream      string
+	options     StreamBuffering
+	minInterval time.Duration // Separación mínima entre envíos de un par (0 = sin límite)
+
+	mu       sync.Mutex
+	pending  []*entities.Price
+	limit    int // Largo al que se compacta pending con coalesce
+	overflow bool
+	ready    chan struct{}
+	held     []*entities.Price    // Último precio retenido de cada par por el límite de frecuencia
+	sentAt   map[string]time.Time // Último envío de cada par
+	release  *time.Timer          // Señala ready cuando vence el próximo precio retenido
+}
+
+func newStreamBuffer(stream string, options StreamBuffering) *streamBuffer {
+	options = options.withDefaults()
+	b := &streamBuffer{
+		stream:  stream,
+		options: options,
+		limit:   options.BufferSize,
+		ready:   make(chan struct{}, 1),
+	}
+	if options.MaxUpdatesPerSecond > 0 {
+		b.minInterval = time.Duration(float64(time.Second) / options.MaxUpdatesPerSecond)
+		b.sentAt = make(map[string]time.Time)
+	}
+	return b
+}
+
+// push encola prices. Con el buffer lleno la política coalesce lo compacta al último
+// precio de cada par, así que nunca se desborda: si hay más pares que BufferSize el
+// límite crece hasta el doble de los pares pendientes. Con la política disconnect
+// el buffer queda desbordado y push retorna false.
+func (b *streamBuffer) push(prices []*entities.Price) bool {
+	if len(prices) == 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.overflow {
+		return false
+	}
+
+	for _, price := range prices {
+		if len(b.pending) >= b.limit {
+			if b.options.Policy != SlowClientCoalesce {
+				b.overflow = true
+				break
+			}
+			b.coalesce()
+			b.limit = max(b.options.BufferSize, 2*len(b.pending))
+		}
+		b.pending = append(b.pending, price)
+	}
+
+	b.signal()
+	return !b.overflow
+}
+
+// signal avisa al loop de escritura que hay precios para drain
+func (b *streamBuffer) signal() {
+	select {
+	case b.ready <- struct{}{}:
+	default:
+	}
+}
+
+// coalesce deja sólo el último precio pendiente de cada par, en el orden en que llegaron
+func (b *streamBuffer) coalesce() {
+	latest := make(map[string]int, len(b.pending))
+	for i, price := range b.pending {
+		latest[price.Pair] = i
+	}
+	kept := b.pending[:0]
+	for i, price := range b.pending {
+		if latest[price.Pair] == i {
+			kept = append(kept, price)
+		}
+	}
+	if dropped := len(b.pending) - len(kept); dropped > 0 {
+		metrics.RecordStreamDroppedUpdates(b.stream, dropped)
+	}
+	clear(b.pending[len(kept):])
+	b.pending = kept
+}
+
+// drain devuelve y vacía los precios pendientes; false si el buffer se desbordó.
+// Con límite de frecuencia sólo devuelve los pares cuyo último envío fue hace al
+// menos minInterval y retiene el resto, que se entregan en un drain posterior.
+func (b *streamBuffer) drain() ([]*entities.Price, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	prices := b.pending
+	b.pending = nil
+	if b.minInterval > 0 && !b.overflow {
+		prices = b.throttle(prices)
+	}
+	return prices, !b.overflow
+}
+
+// throttle combina los precios retenidos con prices dejando el último de cada par
+// y separa los que ya pueden enviarse; requiere b.mu
+func (b *streamBuffer) throttle(prices []*entities.Price) []*entities.Price {
+	candidates := append(b.held, prices...)
+	if len(candidates) == 0 {
+		return nil
+	}
+	latest := make(map[string]int, len(candidates))
+	for i, price := range candidates {
+		latest[price.Pair] = i
+	}
+	if conflated := len(candidates) - len(latest); conflated > 0 {
+		metrics.RecordStreamConflatedUpdates(b.stream, conflated)
+	}
+
+	now := time.Now()
+	var due, held []*entities.Price
+	var next time.Time // Vencimiento más próximo de los retenidos
+	for i, price := range candidates {
+		if latest[price.Pair] != i {
+			continue
+		}
+		if sent, ok := b.sentAt[price.Pair]; ok {
+			if at := sent.Add(b.minInterval); at.After(now) {
+				held = append(held, price)
+				if next.IsZero() || at.Before(next) {
+					next = at
+				}
+				continue
+			}
+		}
+		b.sentAt[price.Pair] = now
+		due = append(due, price)
+	}
+	b.held = held
+
+	if len(held) > 0 {
+		if b.release != nil {
+			b.release.Stop()
+		}
+		b.release = time.AfterFunc(next.Sub(now), b.signal)
+	}
+	return due
+}
+
+// discard vacía los precios pendientes y retenidos sin enviarlos (p. ej. antes de un snapshot)
+func (b *streamBuffer) discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = nil
+	b.held = nil
+}
+
+// disconnectSlowClient registra la desconexión de un cliente lento
+func (b *streamBuffer) disconnectSlowClient(ctx context.Context, reason string) {
+	metrics.RecordStreamSlowClient(b.stream, reason)
+	logging.Warn(ctx, "Disconnecting slow streaming client", logging.Fields{
+		"stream":      b.stream,
+		"reason":      reason,
+		"buffer_size": b.options.BufferSize,
+		"policy":      string(b.options.Policy),
+	})
+}
+
+// writeFailed registra la desconexión si err es un timeout de escritura; los
+// demás errores (el cliente cerró) no son clientes lentos
+func (b *streamBuffer) writeFailed(ctx context.Context, err error) {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		b.disconnectSlowClient(ctx, slowClientWriteTimeout)
+	}
+}
+
+// pumpPrices encola en buffer los precios de initial y luego relee la caché cada
+// interval encolando sólo los que cambiaron; scope restringe los precios a los de
+// la conexión. Termina al cerrarse stop o al desbordarse el buffer.
+func pumpPrices(ctx context.Context, priceService interfaces.PriceService, interval time.Duration, scope func([]*entities.Price) []*entities.Price, initial []*entities.Price, buffer *streamBuffer, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	read := make(map[string]priceVersion)
+
+	prices := initial
+	for {
+		var changed []*entities.Price
+		for _, price := range scope(prices) {
+			version := priceVersion{amount: price.Amount, timestamp: price.Timestamp}
+			if previous, ok := read[price.Pair]; ok && previous == version {
+				continue
+			}
+			read[price.Pair] = version
+			changed = append(changed, price)
+		}
+		if !buffer.push(changed) {
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		var err error
+		if prices, err = priceService.GetCachedPrices(ctx); err != nil {
+			logging.Warn(ctx, "Price stream failed to read cached prices", logging.Fields{"error": err.Error(), "stream": buffer.stream})
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"btc-ltp-service/internal/infrastructure/web/server"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultStreamInterval es la frecuencia con que el stream relee la caché
+	DefaultStreamInterval = time.Second
+	// streamKeepAlive es el máximo sin escribir antes de enviar un comentario, para
+	// que proxies y balanceadores no cierren la conexión por inactividad
+	streamKeepAlive = 15 * time.Second
+	// streamRetry es la espera sugerida al EventSource antes de reconectar
+	streamRetry = 3 * time.Second
+	// maxStreamDuration recicla las conexiones; el EventSource reconecta solo
+	maxStreamDuration = 30 * time.Minute
+)
+
+// StreamHandler publica los precios cacheados como Server-Sent Events. Cada
+// conexión recibe primero todos los precios en caché y luego, en cada intervalo,
+// sólo los que cambiaron.
+type StreamHandler struct {
+	priceService interfaces.PriceService
+	interval     time.Duration
+	usage        interfaces.UsageRecorder
+	priceFormat  *dto.PriceFormat
+	buffering    StreamBuffering
+	activity     interfaces.PairActivity
+}
+
+// NewStreamHandler crea el handler; un intervalo no positivo usa DefaultStreamInterval
+func NewStreamHandler(priceService interfaces.PriceService, interval time.Duration) *StreamHandler {
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	return &StreamHandler{priceService: priceService, interval: interval}
+}
+
+// WithUsageRecorder cuenta los eventos "price" enviados a cada API key; nil no los cuenta
+func (h *StreamHandler) WithUsageRecorder(usage interfaces.UsageRecorder) *StreamHandler {
+	h.usage = usage
+	return h
+}
+
+// WithPriceFormat redondea los importes de los eventos según su moneda cotizada
+func (h *StreamHandler) WithPriceFormat(format *dto.PriceFormat) *StreamHandler {
+	h.priceFormat = format
+	return h
+}
+
+// WithBuffering configura el buffer por conexión y el manejo de clientes lentos
+func (h *StreamHandler) WithBuffering(buffering StreamBuffering) *StreamHandler {
+	h.buffering = buffering
+	return h
+}
+
+// WithPairActivity mantiene activos todos los pares mientras haya streams abiertos
+func (h *StreamHandler) WithPairActivity(activity interfaces.PairActivity) *StreamHandler {
+	h.activity = activity
+	return h
+}
+
+// StreamPrices godoc
+// @Summary Stream prices (SSE)
+// @Description Server-Sent Events stream of cached prices: every cached price first, then each price that changes, as "price" events with a PriceData payload. The stream ends after 30 minutes, or on server shutdown after a randomized retry hint; EventSource clients reconnect automatically. New streams are refused with 503 while the server drains.
+// @Tags ltp
+// @Produce text/event-stream
+// @Param raw query bool false "Serve amounts without the per-quote rounding rules (business.price_format)"
+// @Success 200 {object} dto.PriceData "price events"
+// @Failure 400 {object} dto.ErrorResponse "Invalid raw parameter"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Streaming not supported or cache unavailable"
+// @Failure 503 {object} dto.ErrorResponse "Server shutting down"
+// @Router /ltp/stream [get]
+func (h *StreamHandler) StreamPrices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+
+	format, err := priceFormatFor(r, h.priceFormat)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+	release, ok := openStream(w, r)
+	if !ok {
+		return
+	}
+	defer release()
+	if h.activity != nil {
+		defer h.activity.Hold(nil)()
+	}
+	prices, err := h.priceService.GetCachedPrices(ctx)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to read cached prices for stream", err, nil)
+		apierror.Write(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to get cached prices")
+		return
+	}
+	// Sin deadline de escritura: el WriteTimeout del servidor cortaría el stream
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		apierror.Write(w, r, http.StatusInternalServerError, dto.CodeNotSupported, "streaming is not supported by this connection")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	logging.Debug(ctx, "Price stream opened", logging.Fields{"cached_prices": len(prices)})
+	defer logging.Debug(ctx, "Price stream closed", nil)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds()); err != nil || rc.Flush() != nil {
+		return
+	}
+
+	// La caché se lee en otra goroutine: un cliente lento sólo demora su propio buffer
+	buffer := newStreamBuffer(streamSSE, h.buffering)
+	stop := make(chan struct{})
+	defer close(stop)
+	scope := func(prices []*entities.Price) []*entities.Price { return tenantPrices(ctx, prices) }
+	go pumpPrices(ctx, h.priceService, h.interval, scope, prices, buffer, stop)
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	expire := time.NewTimer(maxStreamDuration)
+	defer expire.Stop()
+	lastWrite := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-server.ShutdownNotify(ctx):
+			// El EventSource reconecta solo; la espera al azar reparte las
+			// reconexiones entre las demás instancias
+			retry := streamRetry + rand.N(streamRetry)
+			_ = rc.SetWriteDeadline(time.Now().Add(buffer.options.WriteTimeout))
+			if _, err := fmt.Fprintf(w, ": server shutting down\nretry: %d\n\n", retry.Milliseconds()); err == nil {
+				_ = rc.Flush()
+			}
+			return
+		case <-expire.C:
+			return
+		case <-buffer.ready:
+			pending, ok := buffer.drain()
+			if !ok {
+				buffer.disconnectSlowClient(ctx, slowClientBufferFull)
+				return
+			}
+			if len(pending) == 0 {
+				continue // Todo retenido por el límite de frecuencia
+			}
+			written, err := writeEvents(w, rc, pending, format, buffer.options.WriteTimeout)
+			h.recordMessages(ctx, written)
+			if err != nil {
+				buffer.writeFailed(ctx, err)
+				return
+			}
+			lastWrite = time.Now()
+		case <-keepAlive.C:
+			if time.Since(lastWrite) < streamKeepAlive {
+				continue
+			}
+			if err := rc.SetWriteDeadline(time.Now().Add(buffer.options.WriteTimeout)); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil || rc.Flush() != nil {
+				buffer.writeFailed(ctx, err)
+				return
+			}
+			lastWrite = time.Now()
+		}
+	}
+}
+
+// recordMessages cuenta los eventos enviados para la contabilidad de uso
+func (h *StreamHandler) recordMessages(ctx context.Context, count int) {
+	if h.usage != nil {
+		h.usage.RecordStreamMessages(ctx, count)
+	}
+}
+
+// openStream registra el stream en el servidor para el drenaje del apagado; con
+// el servidor apagándose responde 503 para que el cliente reconecte a otra instancia
+func openStream(w http.ResponseWriter, r *http.Request) (func(), bool) {
+	done, ok := server.OpenStream(r.Context())
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(streamRetry.Seconds())))
+		apierror.Write(w, r, http.StatusServiceUnavailable, dto.CodeShuttingDown, "server is shutting down, reconnect")
+	}
+	return done, ok
+}
+
+// priceVersion identifica un precio ya leído o enviado por el stream
+type priceVersion struct {
+	amount    float64
+	timestamp time.Time
+}
+
+// writeEvents escribe un evento "price" por precio con los decimales de format y
+// retorna cuántos escribió; el lote tiene timeout para completarse
+func writeEvents(w http.ResponseWriter, rc *http.ResponseController, prices []*entities.Price, format *dto.PriceFormat, timeout time.Duration) (int, error) {
+	if err := rc.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+	for i, price := range prices {
+		priceData := format.Data(price)
+		data, err := json.Marshal(priceData)
+		if err != nil {
+			return i, err
+		}
+		if _, err := fmt.Fprintf(w, "event: price\ndata: %s\n\n", data); err != nil {
+			return i, err
+		}
+	}
+	return len(prices), rc.Flush()
+}
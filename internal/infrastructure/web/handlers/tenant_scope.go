@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"context"
+)
+
+// tenantPairs restringe pairs a los permitidos para el tenant de la request; sin
+// tenant (operador o multi-tenancy deshabilitado) devuelve pairs sin cambios
+func tenantPairs(ctx context.Context, pairs []string) []string {
+	if tenant, ok := entities.TenantFrom(ctx); ok {
+		return tenant.FilterPairs(pairs)
+	}
+	return pairs
+}
+
+// tenantPrices restringe prices a los pares permitidos para el tenant de la request
+func tenantPrices(ctx context.Context, prices []*entities.Price) []*entities.Price {
+	if tenant, ok := entities.TenantFrom(ctx); ok {
+		return tenant.FilterPrices(prices)
+	}
+	return prices
+}
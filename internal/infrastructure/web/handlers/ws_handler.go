@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"btc-ltp-service/internal/infrastructure/web/server"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsHandshakeTimeout acota la respuesta al upgrade
+	wsHandshakeTimeout = 10 * time.Second
+	// wsPongWait es el máximo sin recibir nada del cliente (los pongs cuentan)
+	wsPongWait = 2 * streamKeepAlive
+	// DefaultWSMaxMessageBytes acota los mensajes del cliente; sólo envía comandos cortos
+	DefaultWSMaxMessageBytes = 4 << 10
+)
+
+// WebSocketHandler publica los precios cacheados por WebSocket con un protocolo
+// de snapshot y diffs: al conectar se envía un "snapshot" con todos los pares
+// suscriptos y luego, en cada intervalo, un "diff" sólo con los que cambiaron.
+// Cada mensaje lleva un seq creciente; ante un hueco el cliente envía
+// {"type":"resync"} y recibe un snapshot nuevo.
+type WebSocketHandler struct {
+	priceService   interfaces.PriceService
+	supportedPairs []string
+	interval       time.Duration
+	usage          interfaces.UsageRecorder
+	priceFormat    *dto.PriceFormat
+	buffering      StreamBuffering
+	activity       interfaces.PairActivity
+	maxMessage     int64
+	upgrader       websocket.Upgrader
+}
+
+// NewWebSocketHandler crea el handler; un intervalo no positivo usa DefaultStreamInterval
+func NewWebSocketHandler(priceService interfaces.PriceService, supportedPairs []string, interval time.Duration) *WebSocketHandler {
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	return &WebSocketHandler{
+		priceService:   priceService,
+		supportedPairs: supportedPairs,
+		interval:       interval,
+		maxMessage:     DefaultWSMaxMessageBytes,
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: wsHandshakeTimeout,
+			Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+				code := dto.CodeInvalidParameter
+				if status == http.StatusForbidden {
+					code = dto.CodeForbidden // Origin no permitido
+				}
+				apierror.Write(w, r, status, code, reason.Error())
+			},
+		},
+	}
+}
+
+// WithUsageRecorder cuenta los mensajes enviados a cada API key; nil no los cuenta
+func (h *WebSocketHandler) WithUsageRecorder(usage interfaces.UsageRecorder) *WebSocketHandler {
+	h.usage = usage
+	return h
+}
+
+// WithPriceFormat redondea los importes de los mensajes según su moneda cotizada
+func (h *WebSocketHandler) WithPriceFormat(format *dto.PriceFormat) *WebSocketHandler {
+	h.priceFormat = format
+	return h
+}
+
+// WithBuffering configura el buffer por conexión y el manejo de clientes lentos
+func (h *WebSocketHandler) WithBuffering(buffering StreamBuffering) *WebSocketHandler {
+	h.buffering = buffering
+	return h
+}
+
+// WithPairActivity mantiene activos los pares de cada feed mientras está abierto
+func (h *WebSocketHandler) WithPairActivity(activity interfaces.PairActivity) *WebSocketHandler {
+	h.activity = activity
+	return h
+}
+
+// WithMaxMessageBytes cierra la conexión del cliente que envíe un mensaje mayor a
+// n bytes; n no positivo conserva DefaultWSMaxMessageBytes
+func (h *WebSocketHandler) WithMaxMessageBytes(n int64) *WebSocketHandler {
+	if n > 0 {
+		h.maxMessage = n
+	}
+	return h
+}
+
+// Subscribe godoc
+// @Summary Price feed (WebSocket)
+// @Description WebSocket feed of cached prices. The first message is a "snapshot" with every subscribed pair in cache; then, every second, a "diff" with only the pairs that changed. Snapshots and diffs carry a per-connection "seq" that grows by one; on a gap the client sends {"type":"resync"} and gets a fresh snapshot. Unknown client messages get an "error" message. The connection is closed with "going away" on server shutdown and after 30 minutes; new connections are refused with 503 while the server drains.
+// @Tags ltp
+// @Produce json
+// @Param pair query string false "Comma-separated pairs to subscribe to (default: every supported pair)"
+// @Param raw query bool false "Serve amounts without the per-quote rounding rules (business.price_format)"
+// @Success 101 {object} dto.StreamMessage "snapshot, diff and error messages"
+// @Failure 400 {object} dto.ErrorResponse "Invalid pair, raw parameter or WebSocket handshake"
+// @Failure 401 {object} dto.ErrorResponse "API key missing or invalid"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Failed to read cached prices"
+// @Failure 503 {object} dto.ErrorResponse "Server shutting down"
+// @Router /ws [get]
+func (h *WebSocketHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format, err := priceFormatFor(r, h.priceFormat)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+	request, err := dto.NewGetLTPRequest(r.URL.Query().Get("pair"), tenantPairs(ctx, h.supportedPairs))
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, dto.CodeInvalidParameter, err.Error())
+		return
+	}
+	release, ok := openStream(w, r)
+	if !ok {
+		return
+	}
+	defer release()
+	if h.activity != nil {
+		defer h.activity.Hold(request.Pairs)()
+	}
+
+	prices, err := h.priceService.GetCachedPrices(ctx)
+	if err != nil {
+		logging.ErrorWithError(ctx, "Failed to read cached prices for price feed", err, nil)
+		apierror.Write(w, r, http.StatusInternalServerError, dto.CodeCacheError, "Failed to get cached prices")
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// El upgrader ya respondió con el error
+		logging.Debug(ctx, "WebSocket upgrade failed", logging.Fields{"error": err.Error()})
+		return
+	}
+	defer conn.Close()
+
+	logging.Debug(ctx, "Price feed opened", logging.Fields{"pairs_count": len(request.Pairs)})
+	defer logging.Debug(ctx, "Price feed closed", nil)
+
+	buffer := newStreamBuffer(streamWS, h.buffering)
+	feed := &priceFeed{
+		conn:         conn,
+		format:       format,
+		writeTimeout: buffer.options.WriteTimeout,
+		pairs:        make(map[string]bool, len(request.Pairs)),
+		delivered:    make(map[string]priceVersion),
+	}
+	for _, pair := range request.Pairs {
+		feed.pairs[pair] = true
+	}
+
+	commands := make(chan []byte)
+	done := make(chan struct{})
+	defer close(done)
+	go feed.readCommands(ctx, h.maxMessage, commands, done)
+
+	if err := h.send(ctx, feed, feed.snapshot(prices)); err != nil {
+		buffer.writeFailed(ctx, err)
+		return
+	}
+	// La caché se lee en otra goroutine: un cliente lento sólo demora su propio buffer
+	go pumpPrices(ctx, h.priceService, h.interval, feed.scope, prices, buffer, done)
+
+	ping := time.NewTicker(streamKeepAlive)
+	defer ping.Stop()
+	expire := time.NewTimer(maxStreamDuration)
+	defer expire.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			feed.close(websocket.CloseGoingAway, "request cancelled")
+			return
+		case <-server.ShutdownNotify(ctx):
+			feed.close(websocket.CloseGoingAway, "server shutting down")
+			return
+		case <-expire.C:
+			feed.close(websocket.CloseGoingAway, "connection expired, reconnect")
+			return
+		case data, ok := <-commands:
+			if !ok {
+				return // El cliente cerró o dejó de responder
+			}
+			if err := h.handleCommand(ctx, feed, buffer, data); err != nil {
+				buffer.writeFailed(ctx, err)
+				return
+			}
+		case <-buffer.ready:
+			pending, ok := buffer.drain()
+			if !ok {
+				buffer.disconnectSlowClient(ctx, slowClientBufferFull)
+				feed.close(websocket.CloseTryAgainLater, "client too slow")
+				return
+			}
+			if message := feed.diff(pending); len(message.Prices) > 0 {
+				if err := h.send(ctx, feed, message); err != nil {
+					buffer.writeFailed(ctx, err)
+					return
+				}
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feed.writeTimeout)); err != nil {
+				buffer.writeFailed(ctx, err)
+				return
+			}
+		}
+	}
+}
+
+// handleCommand atiende un mensaje del cliente: resync envía un snapshot nuevo
+// (descartando los diffs pendientes); cualquier otro recibe un mensaje de error
+func (h *WebSocketHandler) handleCommand(ctx context.Context, feed *priceFeed, buffer *streamBuffer, data []byte) error {
+	var command dto.StreamCommand
+	if err := json.Unmarshal(data, &command); err != nil || command.Type != dto.StreamCommandResync {
+		message := "unknown message type: " + command.Type
+		if err != nil {
+			message = "invalid JSON message: " + err.Error()
+		}
+		return feed.write(dto.StreamMessage{Type: dto.StreamMessageError, Code: dto.CodeInvalidBody, Message: message})
+	}
+
+	buffer.discard()
+	prices, err := h.priceService.GetCachedPrices(ctx)
+	if err != nil {
+		logging.Warn(ctx, "Price feed failed to read cached prices", logging.Fields{"error": err.Error()})
+		return feed.write(dto.StreamMessage{Type: dto.StreamMessageError, Code: dto.CodeCacheError, Message: "failed to get cached prices, retry the resync"})
+	}
+	return h.send(ctx, feed, feed.snapshot(prices))
+}
+
+// send escribe un snapshot o diff y lo cuenta para la contabilidad de uso
+func (h *WebSocketHandler) send(ctx context.Context, feed *priceFeed, message dto.StreamMessage) error {
+	if err := feed.write(message); err != nil {
+		return err
+	}
+	if h.usage != nil {
+		h.usage.RecordStreamMessages(ctx, 1)
+	}
+	return nil
+}
+
+// priceFeed es el estado de una conexión de /api/v1/ws
+type priceFeed struct {
+	conn         *websocket.Conn
+	format       *dto.PriceFormat
+	writeTimeout time.Duration
+	pairs        map[string]bool
+	delivered    map[string]priceVersion // Último precio enviado de cada par
+	seq          uint64
+}
+
+// scope restringe prices a los pares suscriptos
+func (f *priceFeed) scope(prices []*entities.Price) []*entities.Price {
+	scoped := make([]*entities.Price, 0, len(f.pairs))
+	for _, price := range prices {
+		if f.pairs[price.Pair] {
+			scoped = append(scoped, price)
+		}
+	}
+	return scoped
+}
+
+// snapshot arma un snapshot con los pares suscriptos de prices y lo da por enviado
+func (f *priceFeed) snapshot(prices []*entities.Price) dto.StreamMessage {
+	clear(f.delivered)
+	message := dto.StreamMessage{Type: dto.StreamMessageSnapshot, Prices: []dto.PriceData{}}
+	for _, price := range f.scope(prices) {
+		message.Prices = append(message.Prices, f.format.Data(price))
+		f.delivered[price.Pair] = priceVersion{amount: price.Amount, timestamp: price.Timestamp}
+	}
+	f.seq++
+	message.Seq = f.seq
+	return message
+}
+
+// diff arma un diff con el último precio de cada par de pending que sea más nuevo
+// que el enviado (los encolados antes de un resync ya no lo son); sin precios nuevos
+// el diff queda vacío y no consume seq
+func (f *priceFeed) diff(pending []*entities.Price) dto.StreamMessage {
+	message := dto.StreamMessage{Type: dto.StreamMessageDiff}
+	index := make(map[string]int, len(pending))
+	for _, price := range pending {
+		version := priceVersion{amount: price.Amount, timestamp: price.Timestamp}
+		if previous, ok := f.delivered[price.Pair]; ok && (previous == version || price.Timestamp.Before(previous.timestamp)) {
+			continue
+		}
+		f.delivered[price.Pair] = version
+		if i, ok := index[price.Pair]; ok {
+			message.Prices[i] = f.format.Data(price)
+			continue
+		}
+		index[price.Pair] = len(message.Prices)
+		message.Prices = append(message.Prices, f.format.Data(price))
+	}
+	if len(message.Prices) > 0 {
+		f.seq++
+		message.Seq = f.seq
+	}
+	return message
+}
+
+func (f *priceFeed) write(message dto.StreamMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if err := f.conn.SetWriteDeadline(time.Now().Add(f.writeTimeout)); err != nil {
+		return err
+	}
+	return f.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// close envía el close frame; la conexión se cierra al volver del handler
+func (f *priceFeed) close(code int, reason string) {
+	_ = f.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(f.writeTimeout))
+}
+
+// readCommands lee los mensajes del cliente hasta que la conexión se cierra, el
+// cliente deja de responder a los pings, envía un mensaje mayor a maxMessage
+// (gorilla responde 1009 message too big) o el handler termina (done); al
+// terminar cierra commands
+func (f *priceFeed) readCommands(ctx context.Context, maxMessage int64, commands chan<- []byte, done <-chan struct{}) {
+	defer close(commands)
+	f.conn.SetReadLimit(maxMessage)
+	_ = f.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	f.conn.SetPongHandler(func(string) error {
+		return f.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := f.conn.ReadMessage()
+		if errors.Is(err, websocket.ErrReadLimit) {
+			metrics.RecordStreamOversizedMessage(streamWS)
+			logging.Warn(ctx, "Price feed client message exceeds maximum size, closing", logging.Fields{
+				"max_bytes": maxMessage,
+			})
+		}
+		if err != nil {
+			return
+		}
+		_ = f.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		select {
+		case commands <- data:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package i18n localiza los mensajes de error de la API según Accept-Language.
+// El catálogo tiene un mensaje por código de error (dto.Code*) y por idioma;
+// inglés es el idioma por defecto y el de los mensajes que arman los handlers.
+package i18n
+
+import (
+	"btc-ltp-service/internal/application/dto"
+
+	"golang.org/x/text/language"
+)
+
+// Idiomas soportados; el primero es el fallback de la negociación
+var (
+	English = language.English
+	Spanish = language.Spanish
+)
+
+var supported = []language.Tag{English, Spanish}
+
+var matcher = language.NewMatcher(supported)
+
+var catalog = map[language.Tag]map[string]string{
+	English: {
+		dto.CodeInvalidParameter:    "Invalid request parameters",
+		dto.CodeMissingParameter:    "A required parameter is missing",
+		dto.CodeUnknownParameter:    "Query parameter not accepted by this route",
+		dto.CodeUnsupportedPair:     "Trading pair not supported",
+		dto.CodeInvalidPair:         "Trading pair must have BASE/QUOTE syntax",
+		dto.CodeInvalidBody:         "Malformed request body",
+		dto.CodeTooManyPairs:        "Too many pairs in a single request",
+		dto.CodeBodyTooLarge:        "Request body too large",
+		dto.CodeHeadersTooLarge:     "Request headers too large",
+		dto.CodeAPIKeyMissing:       "API key missing",
+		dto.CodeAPIKeyInvalid:       "Invalid API key",
+		dto.CodeRateLimitExceeded:   "Rate limit exceeded. Please slow down your requests.",
+		dto.CodeForbidden:           "Access to this resource is not allowed",
+		dto.CodePairNotAllowed:      "Trading pair not allowed for this API key",
+		dto.CodeNotFound:            "Resource not found",
+		dto.CodeMethodNotAllowed:    "Method not allowed for this resource",
+		dto.CodeUnknownFlag:         "Unknown feature flag",
+		dto.CodeUnknownTenant:       "Unknown tenant",
+		dto.CodeTenantReadOnly:      "Tenant is defined in configuration and cannot be changed at runtime",
+		dto.CodeTenantConflict:      "API key already in use",
+		dto.CodePriceFetchError:     "Failed to fetch price",
+		dto.CodePriceDeviation:      "Price withheld: it deviates from the reference source",
+		dto.CodeUpstreamUnavailable: "The exchange has not delivered a price for this pair",
+		dto.CodeUpstreamRateLimited: "The exchange is rate limiting price requests",
+		dto.CodeStaleData:           "The last price expired without being refreshed",
+		dto.CodeCacheError:          "Cache operation failed",
+		dto.CodeEncodingError:       "Failed to encode response",
+		dto.CodeInternalError:       "Internal server error",
+		dto.CodeNotSupported:        "Operation not supported by this deployment",
+		dto.CodeShuttingDown:        "Server is shutting down, reconnect to another instance",
+	},
+	Spanish: {
+		dto.CodeInvalidParameter:    "Parámetros de la consulta inválidos",
+		dto.CodeMissingParameter:    "Falta un parámetro obligatorio",
+		dto.CodeUnknownParameter:    "Parámetro de consulta no aceptado por esta ruta",
+		dto.CodeUnsupportedPair:     "Par de trading no soportado",
+		dto.CodeInvalidPair:         "El par de trading debe tener formato BASE/QUOTE",
+		dto.CodeInvalidBody:         "Cuerpo de la petición mal formado",
+		dto.CodeTooManyPairs:        "Demasiados pares en una sola petición",
+		dto.CodeBodyTooLarge:        "Cuerpo de la petición demasiado grande",
+		dto.CodeHeadersTooLarge:     "Headers de la petición demasiado grandes",
+		dto.CodeAPIKeyMissing:       "Falta la API key",
+		dto.CodeAPIKeyInvalid:       "API key inválida",
+		dto.CodeRateLimitExceeded:   "Límite de peticiones excedido. Reduzca la frecuencia de sus peticiones.",
+		dto.CodeForbidden:           "No tiene permitido acceder a este recurso",
+		dto.CodePairNotAllowed:      "Par de trading no permitido para esta API key",
+		dto.CodeNotFound:            "Recurso no encontrado",
+		dto.CodeMethodNotAllowed:    "Método no permitido para este recurso",
+		dto.CodeUnknownFlag:         "Feature flag desconocido",
+		dto.CodeUnknownTenant:       "Tenant desconocido",
+		dto.CodeTenantReadOnly:      "El tenant está definido en la configuración y no puede modificarse en runtime",
+		dto.CodeTenantConflict:      "La API key ya está en uso",
+		dto.CodePriceFetchError:     "No se pudo obtener el precio",
+		dto.CodePriceDeviation:      "Precio retenido: se desvía de la fuente de referencia",
+		dto.CodeUpstreamUnavailable: "El exchange no entregó un precio para este par",
+		dto.CodeUpstreamRateLimited: "El exchange está limitando las consultas de precios",
+		dto.CodeStaleData:           "El último precio venció sin renovarse",
+		dto.CodeCacheError:          "Falló la operación de caché",
+		dto.CodeEncodingError:       "No se pudo codificar la respuesta",
+		dto.CodeInternalError:       "Error interno del servidor",
+		dto.CodeNotSupported:        "Operación no soportada en este despliegue",
+		dto.CodeShuttingDown:        "El servidor se está apagando, reconecte a otra instancia",
+	},
+}
+
+// Negotiate elige el idioma soportado que mejor coincide con un header
+// Accept-Language (p. ej. "es-AR,es;q=0.9" -> español); sin coincidencia, inglés
+func Negotiate(acceptLanguage string) language.Tag {
+	if acceptLanguage == "" {
+		return English
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return English
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return English
+	}
+	return supported[index]
+}
+
+// Message devuelve el mensaje del código en el idioma indicado
+func Message(lang language.Tag, code string) (string, bool) {
+	message, ok := catalog[lang][code]
+	return message, ok
+}
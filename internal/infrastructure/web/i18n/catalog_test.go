@@ -0,0 +1,38 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           language.Tag
+	}{
+		{"", English},
+		{"es", Spanish},
+		{"es-AR,es;q=0.9,en;q=0.8", Spanish},
+		{"en-US,en;q=0.9,es;q=0.5", English},
+		{"fr-FR, es;q=0.7", Spanish},
+		{"de", English},
+		{"not a language;;", English},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.acceptLanguage), "Accept-Language: %q", tt.acceptLanguage)
+	}
+}
+
+func TestCatalog_EveryLanguageHasTheSameCodes(t *testing.T) {
+	for code := range catalog[English] {
+		for _, lang := range supported {
+			message, ok := Message(lang, code)
+			assert.True(t, ok && message != "", "%s has no %s message", code, lang)
+		}
+	}
+	for _, lang := range supported {
+		assert.Len(t, catalog[lang], len(catalog[English]), "%s", lang)
+	}
+}
@@ -1,16 +1,22 @@
 package middleware
 
 import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/config"
 	"btc-ltp-service/internal/infrastructure/logging"
-	"encoding/json"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"fmt"
 	"net/http"
 	"strings"
 )
 
 // AuthMiddleware provides API key authentication functionality
 type AuthMiddleware struct {
-	config config.AuthConfig
+	config     config.AuthConfig
+	basicRealm string // != "": también acepta la API key como password de HTTP Basic
+	tenants    interfaces.TenantDirectory
 }
 
 // NewAuthMiddleware creates a new auth middleware instance
@@ -20,11 +26,22 @@ func NewAuthMiddleware(config config.AuthConfig) *AuthMiddleware {
 	}
 }
 
-// AuthResponse represents the authentication error response
-type AuthResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    string `json:"code"`
+// WithBasicAuth acepta además la API key como password de HTTP Basic (el usuario
+// se ignora) y pide credenciales con un challenge Basic, para que un navegador
+// pueda abrir páginas protegidas como el dashboard de /admin/
+func (am *AuthMiddleware) WithBasicAuth(realm string) *AuthMiddleware {
+	withBasic := *am
+	withBasic.basicRealm = realm
+	return &withBasic
+}
+
+// WithTenants acepta además las API keys de los tenants; la request continúa con
+// el tenant en el contexto (entities.TenantFrom). La API key global sigue siendo
+// la del operador, sin restricciones de tenant.
+func (am *AuthMiddleware) WithTenants(tenants interfaces.TenantDirectory) *AuthMiddleware {
+	withTenants := *am
+	withTenants.tenants = tenants
+	return &withTenants
 }
 
 // Handler wraps the given handler with API key authentication
@@ -59,31 +76,52 @@ func (am *AuthMiddleware) Handler(next http.Handler) http.Handler {
 		})
 
 		// Obtener la API key del header
-		apiKey := r.Header.Get(am.config.HeaderName)
+		apiKey := am.apiKeyFrom(r)
 		if apiKey == "" {
-			am.respondWithAuthError(w, r, "API key missing", "API_KEY_MISSING")
+			am.respondWithAuthError(w, r, "API key missing", dto.CodeAPIKeyMissing)
 			return
 		}
 
-		// Verificar la API key
-		if !am.isValidAPIKey(apiKey) {
-			am.respondWithAuthError(w, r, "Invalid API key", "API_KEY_INVALID")
-			return
-		}
-
-		// Log successful authentication
-		logging.Info(r.Context(), "API key authentication successful", logging.Fields{
+		// Verificar la API key: la del operador o la de un tenant
+		keyID := entities.APIKeyID(apiKey)
+		fields := logging.Fields{
+			"key_id":     keyID,
 			"path":       r.URL.Path,
 			"method":     r.Method,
 			"remote_ip":  getClientIP(r),
 			"user_agent": r.Header.Get("User-Agent"),
-		})
+		}
+		if !am.isValidAPIKey(apiKey) {
+			tenant, ok := am.tenantFor(apiKey)
+			if !ok {
+				am.respondWithAuthError(w, r, "Invalid API key", dto.CodeAPIKeyInvalid)
+				return
+			}
+			fields["tenant"] = tenant.Name
+			r = r.WithContext(entities.WithTenant(r.Context(), tenant))
+		}
+		r = r.WithContext(entities.WithAPIKeyID(r.Context(), keyID))
+
+		// Log successful authentication
+		logging.Info(r.Context(), "API key authentication successful", fields)
 
 		// Continuar con el siguiente handler
 		next.ServeHTTP(w, r)
 	})
 }
 
+// Authenticate verifica apiKey como lo hace Handler (la del operador o la de un
+// tenant) y retorna su ID, para quien necesite la identidad antes de la auth
+// (p. ej. el rate limiter)
+func (am *AuthMiddleware) Authenticate(apiKey string) (keyID string, ok bool) {
+	if !am.isValidAPIKey(apiKey) {
+		if _, ok := am.tenantFor(apiKey); !ok {
+			return "", false
+		}
+	}
+	return entities.APIKeyID(apiKey), true
+}
+
 // isUnauthenticatedPath verifica si la ruta debe estar exenta de autenticación
 func (am *AuthMiddleware) isUnauthenticatedPath(path string) bool {
 	for _, unauthPath := range am.config.UnauthPaths {
@@ -95,6 +133,18 @@ func (am *AuthMiddleware) isUnauthenticatedPath(path string) bool {
 	return false
 }
 
+// apiKeyFrom extrae la API key del header configurado o, con WithBasicAuth, del
+// password de HTTP Basic
+func (am *AuthMiddleware) apiKeyFrom(r *http.Request) string {
+	if apiKey := r.Header.Get(am.config.HeaderName); apiKey != "" || am.basicRealm == "" {
+		return apiKey
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		return password
+	}
+	return ""
+}
+
 // isValidAPIKey verifica si la API key es válida
 func (am *AuthMiddleware) isValidAPIKey(providedKey string) bool {
 	// Simple string comparison - en producción podríamos usar hashing
@@ -102,6 +152,14 @@ func (am *AuthMiddleware) isValidAPIKey(providedKey string) bool {
 	return providedKey == am.config.APIKey
 }
 
+// tenantFor resuelve el tenant dueño de la API key, si hay tenants configurados
+func (am *AuthMiddleware) tenantFor(apiKey string) (*entities.Tenant, bool) {
+	if am.tenants == nil {
+		return nil, false
+	}
+	return am.tenants.TenantByAPIKey(apiKey)
+}
+
 // respondWithAuthError envía una respuesta de error de autenticación
 func (am *AuthMiddleware) respondWithAuthError(w http.ResponseWriter, r *http.Request, message, code string) {
 	// Log del intento de acceso no autorizado
@@ -114,24 +172,12 @@ func (am *AuthMiddleware) respondWithAuthError(w http.ResponseWriter, r *http.Re
 		"reason":     message,
 	})
 
-	// Configurar headers de respuesta
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
-	w.WriteHeader(http.StatusUnauthorized)
-
-	// Crear respuesta JSON estructurada
-	response := AuthResponse{
-		Error:   "Authentication Failed",
-		Message: message,
-		Code:    code,
-	}
-
-	// Enviar respuesta JSON
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		logging.Error(r.Context(), "Error encoding auth error response", logging.Fields{
-			"error": err.Error(),
-		})
+	if am.basicRealm != "" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, am.basicRealm))
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
 	}
+	apierror.Write(w, r, http.StatusUnauthorized, code, message)
 }
 
 // getClientIP extrae la IP real del cliente considerando proxies
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware_BasicAuth(t *testing.T) {
+	auth := NewAuthMiddleware(config.AuthConfig{Enabled: true, APIKey: "secret", HeaderName: "X-API-Key"})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	serve := func(handler http.Handler, setup func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/", nil)
+		setup(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	basic := func(password string) func(*http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth("operator", password) }
+	}
+
+	rec := serve(auth.Handler(ok), basic("secret"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "Basic is only accepted with WithBasicAuth")
+	assert.Equal(t, `Bearer realm="api"`, rec.Header().Get("WWW-Authenticate"))
+
+	dashboard := auth.WithBasicAuth("admin").Handler(ok)
+	assert.Equal(t, http.StatusNoContent, serve(dashboard, basic("secret")).Code)
+	assert.Equal(t, http.StatusNoContent, serve(dashboard, func(r *http.Request) { r.Header.Set("X-API-Key", "secret") }).Code)
+
+	rec = serve(dashboard, basic("wrong"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="admin", charset="UTF-8"`, rec.Header().Get("WWW-Authenticate"))
+}
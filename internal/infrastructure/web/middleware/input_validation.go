@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Motivos de rechazo (label reason de btc_ltp_http_rejected_inputs_total)
+const (
+	RejectBodyTooLarge    = "body_too_large"
+	RejectHeadersTooLarge = "headers_too_large"
+	RejectUnknownParam    = "unknown_parameter"
+	RejectInvalidPair     = "invalid_pair"
+)
+
+// pairQueryParams son los parámetros que contienen listas de pares separadas por comas
+var pairQueryParams = []string{"pair", "pairs"}
+
+// InputValidationMiddleware rechaza requests malformadas antes de llegar a los
+// handlers: cuerpos y headers demasiado grandes, parámetros desconocidos (modo
+// estricto) y pares con sintaxis inválida
+type InputValidationMiddleware struct {
+	enabled        bool
+	maxBodyBytes   int64
+	maxHeaderBytes int
+	strictQuery    bool
+	allowedQuery   map[string]map[string]bool
+}
+
+// NewInputValidationMiddleware crea el middleware a partir de server.input_validation
+func NewInputValidationMiddleware(config config.InputValidationConfig) *InputValidationMiddleware {
+	return &InputValidationMiddleware{
+		enabled:        config.Enabled,
+		maxBodyBytes:   config.MaxBodyBytes,
+		maxHeaderBytes: config.MaxHeaderBytes,
+		strictQuery:    config.StrictQuery,
+		allowedQuery:   make(map[string]map[string]bool),
+	}
+}
+
+// AllowQuery declara los query parameters aceptados por path en modo estricto;
+// en ese modo las rutas no declaradas no aceptan parámetros
+func (m *InputValidationMiddleware) AllowQuery(path string, params ...string) *InputValidationMiddleware {
+	allowed := m.allowedQuery[path]
+	if allowed == nil {
+		allowed = make(map[string]bool, len(params))
+		m.allowedQuery[path] = allowed
+	}
+	for _, param := range params {
+		allowed[param] = true
+	}
+	return m
+}
+
+// Handler envuelve next con las validaciones de entrada
+func (m *InputValidationMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if m.maxHeaderBytes > 0 && headerSize(r.Header) > m.maxHeaderBytes {
+			m.reject(w, r, http.StatusRequestHeaderFieldsTooLarge, RejectHeadersTooLarge, dto.CodeHeadersTooLarge,
+				fmt.Sprintf("request headers exceed %d bytes", m.maxHeaderBytes))
+			return
+		}
+
+		if m.maxBodyBytes > 0 {
+			if r.ContentLength > m.maxBodyBytes {
+				m.reject(w, r, http.StatusRequestEntityTooLarge, RejectBodyTooLarge, dto.CodeBodyTooLarge,
+					fmt.Sprintf("request body exceeds %d bytes", m.maxBodyBytes))
+				return
+			}
+			// Cuerpos sin Content-Length (chunked) fallan al leer más del límite
+			r.Body = http.MaxBytesReader(w, r.Body, m.maxBodyBytes)
+		}
+
+		query := r.URL.Query()
+		if m.strictQuery {
+			if unknown := m.unknownParams(r.URL.Path, query); len(unknown) > 0 {
+				m.reject(w, r, http.StatusBadRequest, RejectUnknownParam, dto.CodeUnknownParameter,
+					"unknown query parameters: "+strings.Join(unknown, ", "))
+				return
+			}
+		}
+
+		for _, param := range pairQueryParams {
+			if err := validatePairList(query[param]); err != nil {
+				m.reject(w, r, http.StatusBadRequest, RejectInvalidPair, dto.CodeInvalidPair,
+					fmt.Sprintf("invalid %s parameter: %v", param, err))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unknownParams devuelve, ordenados, los parámetros no declarados para path
+func (m *InputValidationMiddleware) unknownParams(path string, query map[string][]string) []string {
+	allowed := m.allowedQuery[path]
+	var unknown []string
+	for param := range query {
+		if !allowed[param] {
+			unknown = append(unknown, param)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// validatePairList comprueba la sintaxis de cada par de una lista separada por
+// comas; el comodín "*" y las entradas vacías los resuelven los handlers
+func validatePairList(values []string) error {
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" || pair == "*" {
+				continue
+			}
+			if _, err := entities.NormalizePair(pair); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// headerSize aproxima el tamaño de los headers en el formato de la request
+// ("Name: value\r\n" por valor)
+func headerSize(header http.Header) int {
+	size := 0
+	for name, values := range header {
+		for _, value := range values {
+			size += len(name) + len(value) + 4
+		}
+	}
+	return size
+}
+
+func (m *InputValidationMiddleware) reject(w http.ResponseWriter, r *http.Request, status int, reason, errorCode, message string) {
+	metrics.RecordHTTPInputRejected(reason)
+	logging.Warn(r.Context(), "Request rejected by input validation", logging.Fields{
+		"path":   r.URL.Path,
+		"method": r.Method,
+		"reason": reason,
+		"error":  message,
+	})
+
+	apierror.WriteWithDetails(w, r, status, errorCode, message, map[string]any{"reason": reason})
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInputValidation(strict bool) (http.Handler, *int) {
+	calls := 0
+	iv := NewInputValidationMiddleware(config.InputValidationConfig{
+		Enabled:        true,
+		MaxBodyBytes:   32,
+		MaxHeaderBytes: 256,
+		StrictQuery:    strict,
+	}).AllowQuery("/ltp", "pair", "limit")
+	handler := iv.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return handler, &calls
+}
+
+func TestInputValidation_PairSyntax(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer metrics.SetDefault(m)()
+
+	handler, calls := newTestInputValidation(false)
+
+	assert.Equal(t, http.StatusOK, serve(handler, "/ltp?pair=BTC/USD,eth-usd", nil).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, "/ltp?pair=*", nil).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, "/ltp?pair=DOGE/MOON", nil).Code, "supported pairs are checked by the handler")
+
+	rec := serve(handler, "/ltp?pair=BTC/USD,<script>", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var body dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, dto.CodeInvalidPair, body.Code)
+	assert.Equal(t, RejectInvalidPair, body.Details["reason"])
+	assert.Contains(t, body.Message, "pair")
+
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.HTTP.RejectedInputs.WithLabelValues(RejectInvalidPair)))
+}
+
+func TestInputValidation_StrictQuery(t *testing.T) {
+	lenient, _ := newTestInputValidation(false)
+	assert.Equal(t, http.StatusOK, serve(lenient, "/ltp?pair=BTC/USD&debug=1", nil).Code)
+
+	strict, _ := newTestInputValidation(true)
+	assert.Equal(t, http.StatusOK, serve(strict, "/ltp?pair=BTC/USD&limit=1", nil).Code)
+
+	rec := serve(strict, "/ltp?pair=BTC/USD&debug=1&zzz=2", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "UNKNOWN_PARAMETER")
+	assert.Contains(t, rec.Body.String(), "debug, zzz")
+
+	assert.Equal(t, http.StatusBadRequest, serve(strict, "/ltp/cached?limit=1", nil).Code, "undeclared routes accept no parameters")
+}
+
+func TestInputValidation_SizeLimits(t *testing.T) {
+	handler, calls := newTestInputValidation(false)
+
+	req := httptest.NewRequest(http.MethodPost, "/ltp", strings.NewReader(`{"pairs":["BTC/USD","ETH/USD","LTC/USD"]}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "BODY_TOO_LARGE")
+
+	// Sin Content-Length: el límite se aplica al leer
+	req = httptest.NewRequest(http.MethodPost, "/ltp", io.NopCloser(strings.NewReader(strings.Repeat("x", 64))))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 1, *calls)
+
+	rec = serve(handler, "/ltp", map[string]string{"X-Padding": strings.Repeat("a", 300)})
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "HEADERS_TOO_LARGE")
+}
+
+func TestInputValidation_Disabled(t *testing.T) {
+	handler := NewInputValidationMiddleware(config.InputValidationConfig{Enabled: false, StrictQuery: true}).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+	assert.Equal(t, http.StatusOK, serve(handler, "/ltp?pair=<bad>&x=1", nil).Code)
+}
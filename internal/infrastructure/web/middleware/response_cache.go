@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ResponseCacheHeader indica si la respuesta salió de la caché (HIT, MISS o BYPASS)
+	ResponseCacheHeader = "X-Response-Cache"
+	// ResponseCacheBypassHeader fuerza a omitir la caché cuando vale "true" o "1"
+	ResponseCacheBypassHeader = "X-Cache-Bypass"
+)
+
+// cachedResponse es una respuesta HTTP almacenada junto con su expiración
+type cachedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// ResponseCacheMiddleware cachea por muy poco tiempo las respuestas 200 de GET,
+// indexadas por tenant, ruta y parámetros normalizados, para que ráfagas de consultas idénticas
+// no lleguen al PriceService.
+type ResponseCacheMiddleware struct {
+	config config.ResponseCacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// NewResponseCacheMiddleware crea el middleware a partir de server.response_cache
+func NewResponseCacheMiddleware(config config.ResponseCacheConfig) *ResponseCacheMiddleware {
+	return &ResponseCacheMiddleware{
+		config:  config,
+		now:     time.Now,
+		entries: make(map[string]*cachedResponse),
+	}
+}
+
+// Handler envuelve next sirviendo desde la caché las consultas GET repetidas
+func (rc *ResponseCacheMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rc.config.Enabled || r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		path := r.URL.Path
+		if isBypassRequested(r) {
+			metrics.RecordHTTPResponseCache(path, "bypass")
+			w.Header().Set(ResponseCacheHeader, "BYPASS")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := path + "?" + normalizeQuery(r.URL.Query())
+		if tenant, ok := entities.TenantFrom(r.Context()); ok {
+			// Cada tenant ve sólo sus pares: no comparte respuestas con otros
+			key = tenant.Name + "|" + key
+		}
+		if entry, ok := rc.get(key); ok {
+			metrics.RecordHTTPResponseCache(path, "hit")
+			w.Header().Set(ResponseCacheHeader, "HIT")
+			if entry.contentType != "" {
+				w.Header().Set("Content-Type", entry.contentType)
+			}
+			w.WriteHeader(entry.status)
+			_, _ = w.Write(entry.body)
+			return
+		}
+
+		metrics.RecordHTTPResponseCache(path, "miss")
+		w.Header().Set(ResponseCacheHeader, "MISS")
+		recorder := &bufferingWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Sólo se cachean respuestas completas; 206/503 reflejan fallas transitorias
+		if recorder.status == http.StatusOK {
+			rc.set(key, &cachedResponse{
+				status:      recorder.status,
+				contentType: w.Header().Get("Content-Type"),
+				body:        recorder.body.Bytes(),
+				expiresAt:   rc.now().Add(rc.config.TTL),
+			})
+		}
+	})
+}
+
+func (rc *ResponseCacheMiddleware) get(key string) (*cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !rc.now().Before(entry.expiresAt) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+// set almacena entry; al alcanzar max_entries purga expirados y, si sigue lleno, no almacena
+func (rc *ResponseCacheMiddleware) set(key string, entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if len(rc.entries) >= rc.config.MaxEntries {
+		now := rc.now()
+		for k, e := range rc.entries {
+			if !now.Before(e.expiresAt) {
+				delete(rc.entries, k)
+			}
+		}
+		if len(rc.entries) >= rc.config.MaxEntries {
+			return
+		}
+	}
+	rc.entries[key] = entry
+}
+
+// isBypassRequested detecta Cache-Control: no-cache o X-Cache-Bypass: true
+func isBypassRequested(r *http.Request) bool {
+	if strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache") {
+		return true
+	}
+	bypass := strings.ToLower(r.Header.Get(ResponseCacheBypassHeader))
+	return bypass == "true" || bypass == "1"
+}
+
+// normalizeQuery arma la clave con los parámetros que lee GET /ltp, tal como los
+// interpreta el handler: los nombres distinguen mayúsculas (?PAIR= no es ?pair=)
+// y el resto de la query no cambia la respuesta. Los pares se canonicalizan
+// conservando su orden, que define el orden de la respuesta.
+func normalizeQuery(values url.Values) string {
+	pairs := strings.TrimSpace(values.Get("pair"))
+	if pairs != "" {
+		parts := strings.Split(pairs, ",")
+		for i, part := range parts {
+			parts[i] = entities.CanonicalPair(part)
+		}
+		pairs = strings.Join(parts, ",")
+	}
+
+	return strings.Join([]string{
+		"pair=" + pairs,
+		"limit=" + strings.TrimSpace(values.Get("limit")),
+		"offset=" + strings.TrimSpace(values.Get("offset")),
+		"quote=" + strings.ToUpper(strings.TrimSpace(values.Get("quote"))),
+		"raw=" + values.Get("raw"),
+	}, "&")
+}
+
+// bufferingWriter copia el cuerpo de la respuesta mientras se escribe al cliente
+type bufferingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (bw *bufferingWriter) WriteHeader(code int) {
+	bw.status = code
+	bw.ResponseWriter.WriteHeader(code)
+}
+
+func (bw *bufferingWriter) Write(b []byte) (int, error) {
+	bw.body.Write(b)
+	return bw.ResponseWriter.Write(b)
+}
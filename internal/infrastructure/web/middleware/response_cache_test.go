@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestResponseCache(calls *int, status int) (*ResponseCacheMiddleware, http.Handler) {
+	rc := NewResponseCacheMiddleware(config.ResponseCacheConfig{Enabled: true, TTL: time.Second, MaxEntries: 10})
+	handler := rc.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ltp":[]}`))
+	}))
+	return rc, handler
+}
+
+func serve(handler http.Handler, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResponseCache_ServesIdenticalQueriesFromCache(t *testing.T) {
+	calls := 0
+	_, handler := newTestResponseCache(&calls, http.StatusOK)
+
+	first := serve(handler, "/ltp?pair=BTC/USD,ETH/USD", nil)
+	assert.Equal(t, "MISS", first.Header().Get(ResponseCacheHeader))
+
+	// Misma consulta con distinta capitalización y espacios
+	second := serve(handler, "/ltp?pair=btc/usd,%20eth/usd", nil)
+	assert.Equal(t, "HIT", second.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// El orden de los pares define el orden de la respuesta: es otra clave
+	serve(handler, "/ltp?pair=ETH/USD,BTC/USD", nil)
+	assert.Equal(t, 2, calls)
+}
+
+func TestResponseCache_KeyFollowsParsedParameters(t *testing.T) {
+	rc := NewResponseCacheMiddleware(config.ResponseCacheConfig{Enabled: true, TTL: time.Second, MaxEntries: 10})
+	handler := rc.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Como el handler de /ltp: los nombres de los parámetros distinguen mayúsculas
+		_, _ = w.Write([]byte("pair=" + r.URL.Query().Get("pair")))
+	}))
+
+	upper := serve(handler, "/ltp?PAIR=BTC/USD", nil)
+	assert.Equal(t, "pair=", upper.Body.String())
+
+	single := serve(handler, "/ltp?pair=BTC/USD", nil)
+	assert.Equal(t, "MISS", single.Header().Get(ResponseCacheHeader), "?PAIR= is not the pair parameter")
+	assert.Equal(t, "pair=BTC/USD", single.Body.String())
+
+	alias := serve(handler, "/ltp?pair=xbt/usd&unused=1", nil)
+	assert.Equal(t, "HIT", alias.Header().Get(ResponseCacheHeader), "canonical pairs share a key; ignored parameters do not split it")
+	assert.Equal(t, "pair=BTC/USD", alias.Body.String())
+
+	raw := serve(handler, "/ltp?pair=BTC/USD&raw=true", nil)
+	assert.Equal(t, "MISS", raw.Header().Get(ResponseCacheHeader))
+}
+
+func TestResponseCache_BypassAndExpiry(t *testing.T) {
+	calls := 0
+	rc, handler := newTestResponseCache(&calls, http.StatusOK)
+	now := time.Now()
+	rc.now = func() time.Time { return now }
+
+	serve(handler, "/ltp", nil)
+	rec := serve(handler, "/ltp", map[string]string{"Cache-Control": "no-cache"})
+	assert.Equal(t, "BYPASS", rec.Header().Get(ResponseCacheHeader))
+	serve(handler, "/ltp", map[string]string{ResponseCacheBypassHeader: "true"})
+	assert.Equal(t, 3, calls)
+
+	now = now.Add(time.Second)
+	rec = serve(handler, "/ltp", nil)
+	assert.Equal(t, "MISS", rec.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 4, calls)
+}
+
+func TestResponseCache_DoesNotCachePartialResponses(t *testing.T) {
+	calls := 0
+	_, handler := newTestResponseCache(&calls, http.StatusPartialContent)
+
+	serve(handler, "/ltp", nil)
+	rec := serve(handler, "/ltp", nil)
+	assert.Equal(t, "MISS", rec.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestResponseCache_Disabled(t *testing.T) {
+	calls := 0
+	handler := NewResponseCacheMiddleware(config.ResponseCacheConfig{}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+
+	serve(handler, "/ltp", nil)
+	rec := serve(handler, "/ltp", nil)
+	assert.Empty(t, rec.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 2, calls)
+}
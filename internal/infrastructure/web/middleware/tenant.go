@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/application/dto"
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/logging"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"btc-ltp-service/internal/infrastructure/ratelimit"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// tenantBucket es el token bucket de un tenant junto con los límites con los que se creó
+type tenantBucket struct {
+	bucket     *ratelimit.TokenBucket
+	capacity   int
+	refillRate int
+}
+
+// TenantMiddleware aísla a los tenants autenticados por AuthMiddleware.WithTenants:
+// no les permite llegar a /admin, rechaza los pares fuera de su allowlist en los
+// query parameters y aplica su rate limit propio. Las requests del operador (sin
+// tenant en el contexto) pasan sin cambios.
+type TenantMiddleware struct {
+	mu      sync.Mutex
+	buckets map[string]*tenantBucket
+}
+
+// NewTenantMiddleware crea el middleware; debe ir dentro de la autenticación
+func NewTenantMiddleware() *TenantMiddleware {
+	return &TenantMiddleware{buckets: make(map[string]*tenantBucket)}
+}
+
+// Handler aplica las restricciones del tenant de la request
+func (tm *TenantMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, ok := entities.TenantFrom(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/") {
+			tm.reject(w, r, tenant, http.StatusForbidden, dto.CodeForbidden, "admin endpoints are reserved for the operator")
+			return
+		}
+		if pair, allowed := allowedQueryPairs(r, tenant); !allowed {
+			tm.reject(w, r, tenant, http.StatusForbidden, dto.CodePairNotAllowed, "pair "+pair+" is not allowed for tenant "+tenant.Name)
+			return
+		}
+
+		if bucket := tm.bucketFor(tenant); bucket != nil {
+			allowed := bucket.Allow()
+			metrics.RecordRateLimitResult(allowed)
+			if !allowed {
+				clientID := "tenant:" + tenant.Name
+				metrics.RecordRateLimitRejection(clientID)
+				logging.Warn(r.Context(), "Tenant rate limit exceeded", logging.Fields{
+					"tenant": tenant.Name,
+					"path":   r.URL.Path,
+					"method": r.Method,
+				})
+
+				retryAfter := max(int(math.Ceil(bucket.RetryAfter().Seconds())), 1)
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(tenant.RateLimitCapacity))
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				apierror.WriteWithDetails(w, r, http.StatusTooManyRequests, dto.CodeRateLimitExceeded,
+					"Rate limit exceeded. Please slow down your requests.",
+					map[string]any{
+						"retry_after_seconds": retryAfter,
+						"limit":               tenant.RateLimitCapacity,
+						"tenant":              tenant.Name,
+					})
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedQueryPairs verifica los pares de los parámetros pair y pairs; devuelve el
+// primer par no permitido. El comodín "*" se resuelve en el handler.
+func allowedQueryPairs(r *http.Request, tenant *entities.Tenant) (string, bool) {
+	query := r.URL.Query()
+	for _, param := range pairQueryParams {
+		for _, value := range query[param] {
+			for _, pair := range strings.Split(value, ",") {
+				pair = strings.TrimSpace(pair)
+				if pair == "" || pair == "*" {
+					continue
+				}
+				if !tenant.AllowsPair(pair) {
+					return pair, false
+				}
+			}
+		}
+	}
+	return "", true
+}
+
+// bucketFor devuelve el bucket del tenant, recreándolo si cambiaron sus límites;
+// nil si el tenant no tiene rate limit propio
+func (tm *TenantMiddleware) bucketFor(tenant *entities.Tenant) *ratelimit.TokenBucket {
+	if tenant.RateLimitCapacity <= 0 || tenant.RateLimitRefillRate <= 0 {
+		return nil
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	current, ok := tm.buckets[tenant.Name]
+	if !ok || current.capacity != tenant.RateLimitCapacity || current.refillRate != tenant.RateLimitRefillRate {
+		current = &tenantBucket{
+			bucket:     ratelimit.NewTokenBucket(tenant.RateLimitCapacity, tenant.RateLimitRefillRate),
+			capacity:   tenant.RateLimitCapacity,
+			refillRate: tenant.RateLimitRefillRate,
+		}
+		tm.buckets[tenant.Name] = current
+	}
+	return current.bucket
+}
+
+// reject registra y responde el rechazo de una request del tenant
+func (tm *TenantMiddleware) reject(w http.ResponseWriter, r *http.Request, tenant *entities.Tenant, status int, code, message string) {
+	logging.Warn(r.Context(), "Tenant request rejected", logging.Fields{
+		"tenant":     tenant.Name,
+		"path":       r.URL.Path,
+		"method":     r.Method,
+		"error_code": code,
+	})
+	apierror.Write(w, r, status, code, message)
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/domain/entities"
+	"btc-ltp-service/internal/infrastructure/config"
+	"btc-ltp-service/internal/infrastructure/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubTenantDirectory map[string]*entities.Tenant
+
+func (d stubTenantDirectory) TenantByAPIKey(apiKey string) (*entities.Tenant, bool) {
+	tenant, ok := d[apiKey]
+	return tenant, ok
+}
+
+func TestTenantMiddleware_IsolatesTenants(t *testing.T) {
+	m, err := metrics.New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(metrics.SetDefault(m))
+
+	directory := stubTenantDirectory{
+		"key-a": {Name: "team-a", Pairs: []string{"BTC/USD"}, RateLimitCapacity: 2, RateLimitRefillRate: 1},
+	}
+	var seen *entities.Tenant
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = entities.TenantFrom(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+	auth := NewAuthMiddleware(config.AuthConfig{Enabled: true, APIKey: "operator", HeaderName: "X-API-Key"}).WithTenants(directory)
+	handler := auth.Handler(NewTenantMiddleware().Handler(ok))
+
+	request := func(target, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, request("/admin/tenants", "operator").Code)
+	assert.Nil(t, seen, "the operator key has no tenant")
+	assert.Equal(t, http.StatusUnauthorized, request("/ltp", "unknown").Code)
+
+	assert.Equal(t, http.StatusNoContent, request("/ltp?pair=btc/usd", "key-a").Code)
+	require.NotNil(t, seen)
+	assert.Equal(t, "team-a", seen.Name)
+
+	rec := request("/ltp?pair=BTC/USD,ETH/USD", "key-a")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "PAIR_NOT_ALLOWED")
+	rec = request("/admin/config", "key-a")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "FORBIDDEN")
+
+	// Los rechazos no consumen el bucket del tenant: quedan 1 de 2 tokens
+	assert.Equal(t, http.StatusNoContent, request("/ltp", "key-a").Code)
+	rec = request("/ltp", "key-a")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusNoContent, request("/ltp", "operator").Code, "the tenant quota does not affect the operator")
+}
+
+func TestResponseCache_IsScopedPerTenant(t *testing.T) {
+	calls := 0
+	_, handler := newTestResponseCache(&calls, http.StatusOK)
+	withTenant := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ltp?pair=*", nil)
+		if name != "" {
+			req = req.WithContext(entities.WithTenant(req.Context(), &entities.Tenant{Name: name}))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	withTenant("")
+	assert.Equal(t, "MISS", withTenant("team-a").Header().Get(ResponseCacheHeader))
+	assert.Equal(t, "HIT", withTenant("team-a").Header().Get(ResponseCacheHeader))
+	assert.Equal(t, "MISS", withTenant("team-b").Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"context"
+	"net/http"
+	"time"
+)
+
+// streamingPaths son las rutas de streams SSE y WebSocket, que duran lo que la conexión
+var streamingPaths = map[string]bool{
+	"/api/v1/ltp/stream": true,
+	"/api/v1/ws":         true,
+	"/admin/events":      true,
+}
+
+// TimeoutMiddleware fija un deadline en el contexto de cada request. Los servicios
+// reparten el tiempo restante entre sus etapas, de modo que el tiempo total del
+// handler queda acotado aunque el exchange no responda.
+type TimeoutMiddleware struct {
+	defaultTimeout time.Duration
+	routeTimeouts  map[string]time.Duration
+}
+
+// NewTimeoutMiddleware crea el middleware a partir de server.request_timeout y
+// server.route_timeouts (ruta exacta -> timeout); un timeout 0 no fija deadline
+func NewTimeoutMiddleware(config config.ServerConfig) *TimeoutMiddleware {
+	return &TimeoutMiddleware{
+		defaultTimeout: config.RequestTimeout,
+		routeTimeouts:  config.RouteTimeouts,
+	}
+}
+
+// Handler envuelve next aplicando el timeout correspondiente a la ruta
+func (tm *TimeoutMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := tm.timeoutFor(r)
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// timeoutFor retorna el timeout de la ruta o el default del servidor. Las rutas de
+// streaming no reciben el default, salvo que route_timeouts las declare; se
+// reconocen por la ruta y no por los headers, que un cliente sin
+// Accept: text/event-stream no envía.
+func (tm *TimeoutMiddleware) timeoutFor(r *http.Request) time.Duration {
+	if timeout, ok := tm.routeTimeouts[r.URL.Path]; ok {
+		return timeout
+	}
+	if streamingPaths[r.URL.Path] {
+		return 0
+	}
+	return tm.defaultTimeout
+}
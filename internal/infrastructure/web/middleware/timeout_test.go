@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/infrastructure/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutMiddleware_StreamingPathsHaveNoDefaultDeadline(t *testing.T) {
+	var hasDeadline bool
+	handler := NewTimeoutMiddleware(config.ServerConfig{
+		RequestTimeout: 10 * time.Second,
+		RouteTimeouts:  map[string]time.Duration{"/admin/events": time.Minute},
+	}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}))
+
+	tests := []struct {
+		path         string
+		wantDeadline bool
+	}{
+		{"/api/v1/ltp", true},
+		{"/api/v1/ltp/stream", false},
+		{"/api/v1/ws", false},
+		{"/admin/events", true}, // route_timeouts declara la ruta
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Sin Accept: text/event-stream ni Upgrade, como curl o un EventSource polyfill
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantDeadline, hasDeadline)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"btc-ltp-service/internal/infrastructure/logging"
+	"bufio"
+	"net"
 	"net/http"
 	"time"
 )
@@ -27,16 +29,38 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap exposes the wrapped writer to http.ResponseController (SSE flushing)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack supports WebSocket upgrades (gorilla/websocket asserts http.Hijacker instead of using Unwrap)
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
 // RequestTracingMiddleware adds request tracing and structured logging
 func RequestTracingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Generate unique request ID
-		requestID := logging.GenerateRequestID()
+		// Reusar el X-Request-ID de un proxy o cliente para poder seguir la request
+		// extremo a extremo; si falta o es inválido se genera uno
+		requestID := r.Header.Get("X-Request-ID")
+		if !logging.ValidRequestID(requestID) {
+			requestID = logging.GenerateRequestID()
+		}
 
 		// Create context with request ID and start time
 		startTime := time.Now()
 		ctx := logging.WithRequestID(r.Context(), requestID)
 		ctx = logging.WithStartTime(ctx, startTime)
+		// El trace ID W3C se agrega a cada log de la request, incluidos los del exchange
+		if traceID, ok := logging.ParseTraceparent(r.Header.Get("traceparent")); ok {
+			ctx = logging.WithTraceID(ctx, traceID)
+		}
 
 		// Add request ID to response headers (useful for debugging)
 		w.Header().Set("X-Request-ID", requestID)
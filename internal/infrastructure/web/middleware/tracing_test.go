@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/infrastructure/logging"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTracingMiddleware_PropagatesIncomingIDs(t *testing.T) {
+	var requestID, traceID string
+	handler := RequestTracingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = logging.GetRequestID(r.Context())
+		traceID = logging.GetTraceID(r.Context())
+	}))
+
+	tests := []struct {
+		name        string
+		requestID   string
+		traceparent string
+		wantID      string // vacío: se genera uno nuevo
+		wantTrace   string
+	}{
+		{
+			name:        "reuses request id and trace",
+			requestID:   "lb-7f3a:42",
+			traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			wantID:      "lb-7f3a:42",
+			wantTrace:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{name: "invalid request id", requestID: "bad id\n{}"},
+		{name: "oversized request id", requestID: strings.Repeat("a", 129)},
+		{name: "all-zero trace id", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "malformed traceparent", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-01"},
+		{name: "invalid version", traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/ltp", nil)
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, requestID)
+			} else {
+				assert.True(t, strings.HasPrefix(requestID, "req_"), "expected a generated id, got %q", requestID)
+			}
+			assert.Equal(t, requestID, rec.Header().Get("X-Request-ID"))
+			assert.Equal(t, tt.wantTrace, traceID)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"btc-ltp-service/internal/domain/interfaces"
+	"net/http"
+)
+
+// UsageMiddleware cuenta cada request autenticada para la contabilidad de uso por
+// API key; debe ir dentro de la autenticación
+type UsageMiddleware struct {
+	recorder interfaces.UsageRecorder
+}
+
+// NewUsageMiddleware crea el middleware
+func NewUsageMiddleware(recorder interfaces.UsageRecorder) *UsageMiddleware {
+	return &UsageMiddleware{recorder: recorder}
+}
+
+// Handler registra la request una vez atendida
+func (um *UsageMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		um.recorder.RecordRequest(r.Context())
+	})
+}
@@ -1,20 +1,23 @@
 package router
 
 import (
+	"btc-ltp-service/internal/application/dto"
 	"btc-ltp-service/internal/domain/interfaces"
 	"btc-ltp-service/internal/infrastructure/config"
 	"btc-ltp-service/internal/infrastructure/logging"
 	"btc-ltp-service/internal/infrastructure/metrics"
 	"btc-ltp-service/internal/infrastructure/ratelimit"
+	"btc-ltp-service/internal/infrastructure/web/apierror"
+	"btc-ltp-service/internal/infrastructure/web/dashboard"
 	"btc-ltp-service/internal/infrastructure/web/handlers"
 	"btc-ltp-service/internal/infrastructure/web/middleware"
 	"context"
+	"errors"
 	"net/http"
 
 	_ "btc-ltp-service/docs" // Import docs for swagger
 
 	"github.com/gorilla/mux"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -24,6 +27,24 @@ type Router struct {
 	supportedPairs  []string
 	rateLimitConfig config.RateLimitConfig
 	authConfig      config.AuthConfig
+	serverConfig    config.ServerConfig
+	readinessChecks map[string]handlers.ReadinessCheck
+	readinessDetail map[string]handlers.ReadinessDetail
+	reconnector     interfaces.Reconnectable
+	featureFlags    interfaces.FeatureFlagManager
+	tenants         interfaces.TenantManager
+	usage           interfaces.UsageTracker
+	audit           interfaces.AuditLog
+	priceFormat     *dto.PriceFormat
+	fallbacks       interfaces.FallbackHistory
+	feedQuality     interfaces.FeedQualityReporter
+	subscriptions   interfaces.SubscriptionReporter
+	pairCatalog     interfaces.PairCatalog
+	config          *config.Config
+	pairActivity    interfaces.PairActivity
+	onDemandPairs   interfaces.PairAdmitter
+	startup         interfaces.StartupReporter
+	drainer         interfaces.Drainer
 }
 
 // NewRouter creates a new router instance
@@ -33,17 +54,130 @@ func NewRouter(priceService interfaces.PriceService, supportedPairs []string, ra
 		supportedPairs:  supportedPairs,
 		rateLimitConfig: rateLimitConfig,
 		authConfig:      authConfig,
+		readinessChecks: make(map[string]handlers.ReadinessCheck),
+		readinessDetail: make(map[string]handlers.ReadinessDetail),
 	}
 }
 
+// AddReadinessCheck registra un chequeo adicional para /ready
+func (r *Router) AddReadinessCheck(name string, check handlers.ReadinessCheck) {
+	r.readinessChecks[name] = check
+}
+
+// AddReadinessDetail registra un detalle informativo para /ready
+func (r *Router) AddReadinessDetail(name string, detail handlers.ReadinessDetail) {
+	r.readinessDetail[name] = detail
+}
+
+// SetServerConfig configura los timeouts por request (server.request_timeout y route_timeouts)
+func (r *Router) SetServerConfig(serverConfig config.ServerConfig) {
+	r.serverConfig = serverConfig
+}
+
+// SetReconnector habilita POST /api/v1/admin/exchange/reconnect para el exchange dado
+func (r *Router) SetReconnector(reconnector interfaces.Reconnectable) {
+	r.reconnector = reconnector
+}
+
+// SetFallbackHistory incluye los fallbacks recientes del exchange en /api/v1/admin/status
+func (r *Router) SetFallbackHistory(history interfaces.FallbackHistory) {
+	r.fallbacks = history
+}
+
+// SetFeedQuality habilita GET /api/v1/admin/feed-quality con las estadísticas del feed del exchange
+func (r *Router) SetFeedQuality(reporter interfaces.FeedQualityReporter) {
+	r.feedQuality = reporter
+}
+
+// SetSubscriptions habilita GET /api/v1/admin/pairs con el estado de suscripción por par
+func (r *Router) SetSubscriptions(reporter interfaces.SubscriptionReporter) {
+	r.subscriptions = reporter
+}
+
+// SetPairCatalog incluye los metadatos del exchange en GET /api/v1/pairs
+func (r *Router) SetPairCatalog(catalog interfaces.PairCatalog) {
+	r.pairCatalog = catalog
+}
+
+// SetConfig habilita GET /api/v1/admin/config con la configuración efectiva
+func (r *Router) SetConfig(cfg *config.Config) {
+	r.config = cfg
+}
+
+// SetStartupReport habilita GET /api/v1/admin/startup-report
+func (r *Router) SetStartupReport(startup interfaces.StartupReporter) {
+	r.startup = startup
+}
+
+// SetDrainer habilita POST /api/v1/admin/drain; mientras drena /ready responde 503
+func (r *Router) SetDrainer(drainer interfaces.Drainer) {
+	r.drainer = drainer
+	r.AddReadinessCheck("drain", func(context.Context) error {
+		if drainer.Draining() {
+			return errors.New("instance is draining")
+		}
+		return nil
+	})
+}
+
+// SetFeatureFlags habilita los endpoints /api/v1/admin/flags
+func (r *Router) SetFeatureFlags(flags interfaces.FeatureFlagManager) {
+	r.featureFlags = flags
+}
+
+// SetTenants habilita la autenticación por tenant y los endpoints /api/v1/admin/tenants
+func (r *Router) SetTenants(tenants interfaces.TenantManager) {
+	r.tenants = tenants
+}
+
+// SetAuditLog habilita GET /api/v1/admin/events con el registro de fallbacks y reconexiones
+func (r *Router) SetAuditLog(audit interfaces.AuditLog) {
+	r.audit = audit
+}
+
+// SetUsage cuenta el uso por API key y habilita GET /api/v1/admin/usage
+func (r *Router) SetUsage(usage interfaces.UsageTracker) {
+	r.usage = usage
+}
+
+// SetPriceFormat aplica las reglas de decimales por moneda cotizada a los precios
+// servidos por /ltp, /ltp/cached, /ltp/stream y /ws (salvo con ?raw=true)
+func (r *Router) SetPriceFormat(format *dto.PriceFormat) {
+	r.priceFormat = format
+}
+
+// SetPairActivity mantiene activos los pares consumidos por /ltp/stream y /ws
+// mientras la poda de pares inactivos está habilitada
+func (r *Router) SetPairActivity(activity interfaces.PairActivity) {
+	r.pairActivity = activity
+}
+
+// SetOnDemandPairs acepta en /ltp pares fuera de los soportados que pairs admita
+func (r *Router) SetOnDemandPairs(pairs interfaces.PairAdmitter) {
+	r.onDemandPairs = pairs
+}
+
 // SetupRoutes configures all application routes
 func (r *Router) SetupRoutes() http.Handler {
 	// Create main router
+	apierror.SetProblemTypeBase(r.serverConfig.ProblemTypeBaseURI)
+
 	mainRouter := mux.NewRouter()
+	mainRouter.NotFoundHandler = apierror.NotFoundHandler()
+	mainRouter.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
 
 	// Create handlers
-	ltpHandler := handlers.NewLTPHandler(r.priceService, r.supportedPairs)
+	ltpHandler := handlers.NewLTPHandler(r.priceService, r.supportedPairs).
+		WithMaxBulkPairs(r.serverConfig.MaxBulkPairs).
+		WithPriceFormat(r.priceFormat).
+		WithOnDemandPairs(r.onDemandPairs)
 	healthHandler := handlers.NewHealthHandler(r.priceService)
+	for name, check := range r.readinessChecks {
+		healthHandler.AddReadinessCheck(name, check)
+	}
+	for name, detail := range r.readinessDetail {
+		healthHandler.AddReadinessDetail(name, detail)
+	}
 
 	// Swagger UI documentation (without rate limiting)
 	// Swagger UI at "/swagger/". Serves `doc.json` generated by swag.
@@ -57,7 +191,7 @@ func (r *Router) SetupRoutes() http.Handler {
 	}).Methods("GET")
 
 	// Prometheus metrics endpoint (without rate limiting)
-	mainRouter.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	mainRouter.Handle("/metrics", metrics.Handler()).Methods("GET")
 
 	// Health checks (without rate limiting)
 	mainRouter.HandleFunc("/health", healthHandler.Health).Methods("GET")
@@ -65,19 +199,81 @@ func (r *Router) SetupRoutes() http.Handler {
 
 	// Create a separate subrouter for API endpoints (not using PathPrefix on mainRouter)
 	apiRouter := mux.NewRouter()
+	apiRouter.NotFoundHandler = apierror.NotFoundHandler()
+	apiRouter.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
 
 	// LTP endpoints on the separate router
-	apiRouter.HandleFunc("/ltp", ltpHandler.GetLTP).Methods("GET")
+	// GET /ltp goes through a short-lived response cache for bursts of identical queries
+	responseCache := middleware.NewResponseCacheMiddleware(r.serverConfig.ResponseCache)
+	apiRouter.Handle("/ltp", responseCache.Handler(http.HandlerFunc(ltpHandler.GetLTP))).Methods("GET")
+	apiRouter.HandleFunc("/ltp", ltpHandler.PostLTP).Methods("POST")
 	apiRouter.HandleFunc("/ltp/refresh", ltpHandler.RefreshPrices).Methods("POST")
 	apiRouter.HandleFunc("/ltp/cached", ltpHandler.GetCachedPrices).Methods("GET")
+	buffering := handlers.StreamBuffering{
+		BufferSize:   r.serverConfig.Streaming.BufferSize,
+		WriteTimeout: r.serverConfig.Streaming.WriteTimeout,
+		Policy:       handlers.SlowClientPolicy(r.serverConfig.Streaming.SlowClientPolicy),
+
+		MaxUpdatesPerSecond: r.serverConfig.Streaming.MaxUpdatesPerSecond,
+	}
+	streamHandler := handlers.NewStreamHandler(r.priceService, handlers.DefaultStreamInterval).
+		WithUsageRecorder(r.usage).
+		WithPriceFormat(r.priceFormat).
+		WithBuffering(buffering).
+		WithPairActivity(r.pairActivity)
+	apiRouter.HandleFunc("/ltp/stream", streamHandler.StreamPrices).Methods("GET")
+	wsHandler := handlers.NewWebSocketHandler(r.priceService, r.supportedPairs, handlers.DefaultStreamInterval).
+		WithUsageRecorder(r.usage).
+		WithPriceFormat(r.priceFormat).
+		WithBuffering(buffering).
+		WithPairActivity(r.pairActivity).
+		WithMaxMessageBytes(r.serverConfig.Streaming.MaxMessageBytes)
+	apiRouter.HandleFunc("/ws", wsHandler.Subscribe).Methods("GET")
+	pairsHandler := handlers.NewPairsHandler(r.priceService, r.pairCatalog, r.supportedPairs)
+	apiRouter.HandleFunc("/pairs", pairsHandler.ListPairs).Methods("GET")
+
+	// Admin endpoints (same auth and rate limiting as the rest of the API)
+	adminHandler := handlers.NewAdminHandler(r.priceService, r.supportedPairs).
+		WithReconnector(r.reconnector).
+		WithFeatureFlags(r.featureFlags).
+		WithTenants(r.tenants).
+		WithUsage(r.usage).
+		WithAuditLog(r.audit).
+		WithPriceService(r.priceService).
+		WithFallbackHistory(r.fallbacks).
+		WithFeedQuality(r.feedQuality).
+		WithSubscriptions(r.subscriptions).
+		WithConfig(r.config).
+		WithStartupReport(r.startup).
+		WithDrainer(r.drainer)
+	apiRouter.HandleFunc("/admin/status", adminHandler.Status).Methods("GET")
+	apiRouter.HandleFunc("/admin/feed-quality", adminHandler.GetFeedQuality).Methods("GET")
+	apiRouter.HandleFunc("/admin/pairs", adminHandler.GetPairs).Methods("GET")
+	apiRouter.HandleFunc("/admin/config", adminHandler.GetConfig).Methods("GET")
+	apiRouter.HandleFunc("/admin/startup-report", adminHandler.GetStartupReport).Methods("GET")
+	apiRouter.HandleFunc("/admin/drain", adminHandler.Drain).Methods("POST")
+	apiRouter.HandleFunc("/admin/cache", adminHandler.InvalidateCache).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/exchange/reconnect", adminHandler.ReconnectExchange).Methods("POST")
+	apiRouter.HandleFunc("/admin/flags", adminHandler.ListFeatureFlags).Methods("GET")
+	apiRouter.HandleFunc("/admin/flags/{name}", adminHandler.SetFeatureFlag).Methods("PUT")
+	apiRouter.HandleFunc("/admin/flags/{name}", adminHandler.ClearFeatureFlag).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/tenants", adminHandler.ListTenants).Methods("GET")
+	apiRouter.HandleFunc("/admin/usage", adminHandler.GetUsage).Methods("GET")
+	apiRouter.HandleFunc("/admin/events", adminHandler.GetEvents).Methods("GET")
+	apiRouter.HandleFunc("/admin/tenants/{name}", adminHandler.PutTenant).Methods("PUT")
+	apiRouter.HandleFunc("/admin/tenants/{name}", adminHandler.DeleteTenant).Methods("DELETE")
 
 	// Apply middlewares by layer:
-	// 1. Auth middleware (if enabled) - applied to API routes before rate limiting
-	// 2. Rate limiting - applied to API routes only
-	// 3. Global middlewares - applied to everything
+	// 1. Auth middleware (if enabled) - applied to API routes before rate limiting;
+	//    with tenants, the tenant middleware enforces their allowlists and quotas;
+	//    usage accounting counts the requests that get through
+	// 2. Input validation - rejects oversized or malformed requests after rate limiting
+	// 3. Rate limiting - applied to API routes only
+	// 4. Global middlewares - applied to everything
 
 	// Prepare API router with Auth middleware (if enabled)
 	var finalAPIRouter http.Handler = apiRouter
+	rateLimitMiddleware := ratelimit.NewRateLimitMiddlewareWithConfig(r.rateLimitConfig)
 	if r.authConfig.Enabled {
 		// Debug log para verificar la configuración de auth
 		logging.Info(context.Background(), "Applying auth middleware to API routes", logging.Fields{
@@ -87,20 +283,60 @@ func (r *Router) SetupRoutes() http.Handler {
 			"unauth_paths": r.authConfig.UnauthPaths,
 		})
 		authMiddleware := middleware.NewAuthMiddleware(r.authConfig)
-		finalAPIRouter = authMiddleware.Handler(apiRouter)
+		if r.usage != nil {
+			finalAPIRouter = middleware.NewUsageMiddleware(r.usage).Handler(finalAPIRouter)
+		}
+		if r.tenants != nil {
+			authMiddleware = authMiddleware.WithTenants(r.tenants)
+			finalAPIRouter = middleware.NewTenantMiddleware().Handler(finalAPIRouter)
+		}
+		finalAPIRouter = authMiddleware.Handler(finalAPIRouter)
+		// The limiter runs before auth, so api_key buckets need the keys verified
+		rateLimitMiddleware = rateLimitMiddleware.WithKeyAuthenticator(r.authConfig.HeaderName, authMiddleware.Authenticate)
 	} else {
 		logging.Info(context.Background(), "Auth middleware disabled", nil)
 	}
 
+	// Query parameters accepted by each API route (enforced with strict_query)
+	inputValidation := middleware.NewInputValidationMiddleware(r.serverConfig.InputValidation).
+		AllowQuery("/ltp", "pair", "limit", "offset", "quote", "raw").
+		AllowQuery("/ltp/cached", "limit", "offset", "quote", "raw").
+		AllowQuery("/ltp/stream", "raw").
+		AllowQuery("/ws", "pair", "raw").
+		AllowQuery("/ltp/refresh", "pairs").
+		AllowQuery("/admin/cache", "pair", "all").
+		AllowQuery("/admin/usage", "from", "to", "tenant").
+		AllowQuery("/admin/events", "type", "reason", "pair", "from", "to", "limit")
+	finalAPIRouter = inputValidation.Handler(finalAPIRouter)
+
 	// Apply rate limiting to the (potentially auth-wrapped) API router
-	rateLimitMiddleware := ratelimit.NewRateLimitMiddlewareWithConfig(r.rateLimitConfig)
 	rateLimitedAPIRouter := rateLimitMiddleware.Handler(finalAPIRouter)
 
 	// Mount the fully wrapped API router to the main router
 	mainRouter.PathPrefix("/api/v1").Handler(http.StripPrefix("/api/v1", rateLimitedAPIRouter))
 
+	// Operator dashboard: static page plus its status and price stream, under
+	// /admin/ so the browser reuses the HTTP Basic credentials of the page
+	if r.serverConfig.AdminUI.Enabled {
+		dashboardRouter := mux.NewRouter()
+		dashboardRouter.NotFoundHandler = apierror.NotFoundHandler()
+		dashboardRouter.MethodNotAllowedHandler = apierror.MethodNotAllowedHandler()
+		dashboardRouter.HandleFunc("/admin/status", adminHandler.Status).Methods("GET")
+		dashboardRouter.HandleFunc("/admin/events", streamHandler.StreamPrices).Methods("GET")
+		dashboardRouter.PathPrefix("/admin/").Handler(http.StripPrefix("/admin/", dashboard.Handler())).Methods("GET")
+
+		var dashboardHandler http.Handler = dashboardRouter
+		if r.authConfig.Enabled {
+			dashboardHandler = middleware.NewAuthMiddleware(r.authConfig).WithBasicAuth("btc-ltp-service admin").Handler(dashboardHandler)
+		}
+		mainRouter.Handle("/admin", http.RedirectHandler("/admin/", http.StatusMovedPermanently)).Methods("GET")
+		mainRouter.PathPrefix("/admin/").Handler(rateLimitMiddleware.Handler(dashboardHandler))
+	}
+
 	// Apply global middlewares to the entire router
-	handler := middleware.RequestTracingMiddleware(mainRouter)
+	// The request deadline wraps the routes so handlers see it in their context
+	handler := middleware.NewTimeoutMiddleware(r.serverConfig).Handler(mainRouter)
+	handler = middleware.RequestTracingMiddleware(handler)
 	handler = metrics.HTTPMetricsMiddleware(handler)
 	handler = middleware.LoggingMiddleware(handler)
 	handler = middleware.CORSMiddleware(handler)
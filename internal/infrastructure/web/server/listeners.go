@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// Listener es una dirección donde el servidor acepta conexiones
+type Listener struct {
+	Network string // "tcp" (default), "tcp4", "tcp6" o "unix"
+	Address string // host:port, o la ruta del socket para "unix"
+	// Mode son los permisos del socket unix (p. ej. 0660 para que sólo el grupo del
+	// sidecar se conecte); 0 conserva los que deja el umask
+	Mode os.FileMode
+}
+
+func (l Listener) network() string {
+	if l.Network == "" {
+		return "tcp"
+	}
+	return l.Network
+}
+
+func (l Listener) String() string {
+	return l.network() + "://" + l.Address
+}
+
+// WithListeners reemplaza el listener ":port" por listeners; reusePort activa
+// SO_REUSEPORT en los TCP para que otro proceso pueda tomar la misma dirección
+// antes de que éste cierre (reinicios sin downtime detrás de un process manager)
+func (s *Server) WithListeners(listeners []Listener, reusePort bool) *Server {
+	s.listeners = append([]Listener(nil), listeners...)
+	s.reusePort = reusePort
+	return s
+}
+
+// addresses retorna los listeners a abrir; sin configurar, ":port" por TCP
+func (s *Server) addresses() []Listener {
+	if len(s.listeners) == 0 {
+		return []Listener{{Network: "tcp", Address: s.httpServer.Addr}}
+	}
+	return s.listeners
+}
+
+// listen abre todos los listeners; si alguno falla cierra los ya abiertos
+func (s *Server) listen(ctx context.Context) ([]net.Listener, error) {
+	var config net.ListenConfig
+	if s.reusePort {
+		config.Control = reusePortControl
+	}
+
+	opened := make([]net.Listener, 0, len(s.addresses()))
+	for _, listener := range s.addresses() {
+		l, err := listenOne(ctx, config, listener)
+		if err != nil {
+			for _, o := range opened {
+				_ = o.Close()
+			}
+			return nil, fmt.Errorf("failed to listen on %s: %w", listener, err)
+		}
+		opened = append(opened, l)
+	}
+	return opened, nil
+}
+
+func listenOne(ctx context.Context, config net.ListenConfig, listener Listener) (net.Listener, error) {
+	if listener.network() != "unix" {
+		return config.Listen(ctx, listener.network(), listener.Address)
+	}
+
+	if err := removeStaleSocket(listener.Address); err != nil {
+		return nil, err
+	}
+	// El listener unix borra el archivo del socket al cerrarse
+	config.Control = nil
+	l, err := config.Listen(ctx, "unix", listener.Address)
+	if err != nil {
+		return nil, err
+	}
+	if listener.Mode != 0 {
+		if err := os.Chmod(listener.Address, listener.Mode); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// removeStaleSocket borra el socket que dejó en path un proceso terminado sin
+// cerrarlo; falla si path no es un socket o si otro proceso sigue atendiéndolo
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+	return os.Remove(path)
+}
+
+// serve atiende cada listener hasta que el servidor se apague o uno falle
+func (s *Server) serve(listeners []net.Listener) error {
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) { errs <- s.httpServer.Serve(l) }(l)
+	}
+
+	// Tras Shutdown todos retornan http.ErrServerClosed. Se retorna el primer error
+	// sin esperar al resto: si un listener falla, Stop cierra los demás
+	return <-errs
+}
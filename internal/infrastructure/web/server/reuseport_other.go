@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package server
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePortControl falla: la plataforma no soporta SO_REUSEPORT
+func reusePortControl(network, address string, conn syscall.RawConn) error {
+	return errors.New("SO_REUSEPORT is not supported on this platform")
+}
@@ -4,36 +4,191 @@ import (
 	"btc-ltp-service/internal/infrastructure/logging"
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
 // Server encapsulates HTTP server configuration
 type Server struct {
-	httpServer *http.Server
-	port       int
+	httpServer  *http.Server
+	port        int
+	streams     *streamDrain
+	drainPeriod time.Duration
+	listeners   []Listener // Vacío: ":port" por TCP
+	reusePort   bool
+}
+
+type shutdownKey struct{}
+
+// streamDrain sigue los streams de larga duración (SSE, WebSocket) abiertos.
+// http.Server.Shutdown no espera a las conexiones WebSocket (quedan fuera del
+// servidor con Hijack), así que Stop usa esta cuenta para darles tiempo a cerrar.
+type streamDrain struct {
+	mu       sync.Mutex
+	draining bool
+	active   int
+	shutdown chan struct{} // Se cierra al empezar el drenaje
+	idle     chan struct{} // Se cierra cuando, drenando, no quedan streams
+}
+
+// begin marca el inicio del drenaje y devuelve los streams abiertos; los
+// llamados siguientes no hacen nada
+func (d *streamDrain) begin() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return d.active
+	}
+	d.draining = true
+	close(d.shutdown)
+	if d.active == 0 {
+		close(d.idle)
+	}
+	return d.active
+}
+
+func (d *streamDrain) open() (func(), bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return nil, false
+	}
+	d.active++
+	var once sync.Once
+	return func() { once.Do(d.close) }, true
+}
+
+func (d *streamDrain) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.active--
+	if d.draining && d.active == 0 {
+		close(d.idle)
+	}
+}
+
+func (d *streamDrain) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+func (d *streamDrain) remaining() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.active
 }
 
 // NewServer creates a new server instance
 func NewServer(handler http.Handler, port int) *Server {
+	httpServer := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      handler,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// Shutdown espera a los requests activos; los streams de larga duración se
+	// enteran del apagado por el contexto del request (ver ShutdownNotify)
+	streams := &streamDrain{shutdown: make(chan struct{}), idle: make(chan struct{})}
+	httpServer.RegisterOnShutdown(func() { streams.begin() })
+	httpServer.BaseContext = func(net.Listener) context.Context {
+		return context.WithValue(context.Background(), shutdownKey{}, streams)
+	}
+
 	return &Server{
-		httpServer: &http.Server{
-			Addr:         fmt.Sprintf(":%d", port),
-			Handler:      handler,
-			ReadTimeout:  15 * time.Second,
-			WriteTimeout: 15 * time.Second,
-			IdleTimeout:  60 * time.Second,
-		},
-		port: port,
+		httpServer: httpServer,
+		port:       port,
+		streams:    streams,
+	}
+}
+
+// WithDrainPeriod configura cuánto espera Stop a que los streams abiertos cierren
+// antes de cerrar el listener; 0 sólo les avisa del apagado
+func (s *Server) WithDrainPeriod(drainPeriod time.Duration) *Server {
+	s.drainPeriod = drainPeriod
+	return s
+}
+
+// WithTimeouts reemplaza los timeouts de lectura, escritura e inactividad de las
+// conexiones; un valor 0 conserva el default (15s, 15s y 60s)
+func (s *Server) WithTimeouts(read, write, idle time.Duration) *Server {
+	if read > 0 {
+		s.httpServer.ReadTimeout = read
+	}
+	if write > 0 {
+		s.httpServer.WriteTimeout = write
+	}
+	if idle > 0 {
+		s.httpServer.IdleTimeout = idle
+	}
+	return s
+}
+
+// ShutdownNotify devuelve un canal que se cierra cuando el servidor que atiende el
+// request inicia el apagado, para que handlers como los streams SSE terminen sin
+// demorar Shutdown. Fuera de un Server el canal es nil y nunca se cierra.
+func ShutdownNotify(ctx context.Context) <-chan struct{} {
+	streams, ok := ctx.Value(shutdownKey{}).(*streamDrain)
+	if !ok {
+		return nil
+	}
+	return streams.shutdown
+}
+
+// OpenStream registra un stream de larga duración para que Stop espere a que
+// termine; release debe llamarse al cerrarlo. Con el servidor ya apagándose devuelve
+// false y el stream debe rechazarse. Fuera de un Server siempre lo acepta.
+func OpenStream(ctx context.Context) (release func(), ok bool) {
+	streams, found := ctx.Value(shutdownKey{}).(*streamDrain)
+	if !found {
+		return func() {}, true
 	}
+	return streams.open()
+}
+
+// Drain empieza el drenaje sin cerrar los listeners, para llamarlo desde un hook
+// preStop antes de SIGTERM: los streams abiertos reciben el aviso de apagado, los
+// nuevos se rechazan y las conexiones dejan de mantenerse vivas para que los
+// clientes reconecten en otra instancia. Retorna los streams que siguen abiertos.
+func (s *Server) Drain(ctx context.Context) int {
+	first := !s.streams.isDraining()
+	s.httpServer.SetKeepAlivesEnabled(false)
+	active := s.streams.begin()
+	if first {
+		logging.Info(ctx, "HTTP server draining", logging.Fields{
+			"port":    s.port,
+			"streams": active,
+		})
+	}
+	return active
+}
+
+// Draining indica si el servidor empezó a drenar, por Drain o por Stop
+func (s *Server) Draining() bool {
+	return s.streams.isDraining()
 }
 
 // Start starts the HTTP server
 func (s *Server) Start() error {
 	ctx := context.Background()
 
+	listeners, err := s.listen(ctx)
+	if err != nil {
+		return err
+	}
+	addresses := make([]string, len(listeners))
+	for i, l := range listeners {
+		addresses[i] = l.Addr().Network() + "://" + l.Addr().String()
+	}
+
 	logging.Info(ctx, "HTTP server starting", logging.Fields{
-		"port": s.port,
+		"port":       s.port,
+		"listeners":  addresses,
+		"reuse_port": s.reusePort,
 	})
 
 	logging.Info(ctx, "Available endpoints", logging.Fields{
@@ -47,15 +202,33 @@ func (s *Server) Start() error {
 		},
 	})
 
-	return s.httpServer.ListenAndServe()
+	return s.serve(listeners)
 }
 
-// Stop stops the HTTP server gracefully
+// Stop stops the HTTP server gracefully. Open streams are told to close first
+// and get up to the drain period to do so; the listener stays open meanwhile.
 func (s *Server) Stop(ctx context.Context) error {
 	logging.Info(ctx, "Stopping HTTP server gracefully", logging.Fields{
 		"port": s.port,
 	})
 
+	if active := s.streams.begin(); active > 0 && s.drainPeriod > 0 {
+		logging.Info(ctx, "Draining streaming clients", logging.Fields{
+			"streams":      active,
+			"drain_period": s.drainPeriod.String(),
+		})
+		timer := time.NewTimer(s.drainPeriod)
+		defer timer.Stop()
+		select {
+		case <-s.streams.idle:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		if remaining := s.streams.remaining(); remaining > 0 {
+			logging.Warn(ctx, "Drain period elapsed with open streams", logging.Fields{"streams": remaining})
+		}
+	}
+
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -0,0 +1,332 @@
+// Package client es un cliente Go tipado para la API HTTP de btc-ltp-service.
+//
+//	c, err := client.New("http://btc-ltp:8080", client.WithAPIKey(key))
+//	resp, err := c.GetLTP(ctx, "BTC/USD", "ETH/USD")
+//
+// Las consultas de sólo lectura se reintentan ante errores de red, 429 y 5xx
+// transitorios con backoff exponencial, respetando Retry-After. StreamPrices
+// mantiene el feed de /api/v1/ws y publica los precios en un channel.
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPIKeyHeader es el header de autenticación por defecto del servicio
+	DefaultAPIKeyHeader = "X-API-Key"
+	// DefaultMaxRetries es la cantidad de reintentos de las consultas idempotentes
+	DefaultMaxRetries = 3
+	// DefaultBackoff es la espera antes del primer reintento; se duplica en cada intento
+	DefaultBackoff = 200 * time.Millisecond
+	// DefaultMaxBackoff acota la espera entre reintentos
+	DefaultMaxBackoff = 5 * time.Second
+	// DefaultTimeout es el timeout por intento del http.Client por defecto
+	DefaultTimeout = 10 * time.Second
+)
+
+// Client consume la API HTTP de btc-ltp-service; es seguro para uso concurrente
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	apiKey       string
+	apiKeyHeader string
+	userAgent    string
+	maxRetries   int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+}
+
+// Option configura un Client
+type Option func(*Client)
+
+// WithHTTPClient usa httpClient en lugar del cliente por defecto
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithAPIKey envía key en el header de autenticación (X-API-Key por defecto)
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithAPIKeyHeader cambia el header de autenticación (auth.header_name del servicio)
+func WithAPIKeyHeader(header string) Option {
+	return func(c *Client) {
+		if header != "" {
+			c.apiKeyHeader = header
+		}
+	}
+}
+
+// WithUserAgent fija el User-Agent de las requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetry configura los reintentos; maxRetries 0 los deshabilita
+func WithRetry(maxRetries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+		if maxBackoff > 0 {
+			c.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// New crea un cliente para el servicio en baseURL (ej: http://localhost:8080)
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      parsed,
+		httpClient:   &http.Client{Timeout: DefaultTimeout},
+		apiKeyHeader: DefaultAPIKeyHeader,
+		userAgent:    "btc-ltp-service-go-client",
+		maxRetries:   DefaultMaxRetries,
+		backoff:      DefaultBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// GetLTP consulta GET /api/v1/ltp. Sin pares retorna todos los soportados en caché.
+// Una respuesta parcial (206) no es error: ver LTPResponse.Partial. Si ningún par
+// pudo obtenerse retorna la respuesta junto con un *APIError que envuelve ErrAllPricesFailed.
+func (c *Client) GetLTP(ctx context.Context, pairs ...string) (*LTPResponse, error) {
+	return c.GetLTPWithOptions(ctx, ListOptions{}, pairs...)
+}
+
+// GetLTPWithOptions es GetLTP con filtro por moneda cotizada y paginación
+func (c *Client) GetLTPWithOptions(ctx context.Context, opts ListOptions, pairs ...string) (*LTPResponse, error) {
+	query := opts.values()
+	if len(pairs) > 0 {
+		query.Set("pair", strings.Join(pairs, ","))
+	}
+	return c.doLTP(ctx, http.MethodGet, "/api/v1/ltp", query, nil)
+}
+
+// GetLTPBulk consulta POST /api/v1/ltp con los pares en el cuerpo, para listas que
+// no caben en un query string
+func (c *Client) GetLTPBulk(ctx context.Context, pairs []string) (*LTPResponse, error) {
+	body, err := json.Marshal(map[string][]string{"pairs": pairs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	return c.doLTP(ctx, http.MethodPost, "/api/v1/ltp", nil, body)
+}
+
+// GetCachedPrices consulta GET /api/v1/ltp/cached
+func (c *Client) GetCachedPrices(ctx context.Context, opts ListOptions) (*LTPResponse, error) {
+	return c.doLTP(ctx, http.MethodGet, "/api/v1/ltp/cached", opts.values(), nil)
+}
+
+// RefreshPrices fuerza el refresco de pairs vía POST /api/v1/ltp/refresh (todos si
+// está vacío). No se reintenta porque dispara consultas al exchange.
+func (c *Client) RefreshPrices(ctx context.Context, pairs ...string) (*RefreshResponse, error) {
+	query := url.Values{}
+	if len(pairs) > 0 {
+		query.Set("pairs", strings.Join(pairs, ","))
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/api/v1/ltp/refresh", query, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeAPIError(resp)
+	}
+	var out RefreshResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode refresh response: %w", err)
+	}
+	return &out, nil
+}
+
+// doLTP ejecuta una consulta de precios de sólo lectura y decodifica la respuesta
+func (c *Client) doLTP(ctx context.Context, method, path string, query url.Values, body []byte) (*LTPResponse, error) {
+	resp, err := c.do(ctx, method, path, query, body, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent, http.StatusServiceUnavailable:
+	default:
+		return nil, decodeAPIError(resp)
+	}
+
+	var out LTPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode LTP response: %w", err)
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Code: "ALL_PRICES_FAILED"}
+		if len(out.Errors) > 0 {
+			apiErr.Message = out.Errors[0].Message
+		}
+		return &out, apiErr
+	}
+	return &out, nil
+}
+
+// do envía la request reintentando, si retry es true, ante errores de red, 429,
+// 502, 503 y 504. La última respuesta se retorna sin leer para que el caller la decodifique.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body []byte, retry bool) (*http.Response, error) {
+	endpoint := c.baseURL.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	attempts := 1
+	if retry {
+		attempts += c.maxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.retryDelay(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		if c.userAgent != "" {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
+		if c.apiKey != "" {
+			req.Header.Set(c.apiKeyHeader, c.apiKey)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("%s %s: %w", method, path, err)
+			continue
+		}
+
+		if attempt == attempts-1 || !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		// Se consume el cuerpo para reutilizar la conexión y se reintenta
+		lastErr = decodeAPIError(resp)
+		resp.Body.Close()
+	}
+	return nil, lastErr
+}
+
+// retryDelay usa Retry-After si el servicio lo sugirió; si no, backoff exponencial con jitter
+func (c *Client) retryDelay(attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, c.maxBackoff)
+	}
+
+	delay := c.backoff << (attempt - 1)
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	// Jitter de hasta 20% para no sincronizar reintentos de varios clientes
+	return delay - time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// decodeAPIError construye un *APIError desde una respuesta no exitosa
+func decodeAPIError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body errorBody
+	if err := json.Unmarshal(data, &body); err == nil && body.code() != "" {
+		apiErr.Code = body.code()
+		apiErr.Message = cmp.Or(body.Message, body.Detail)
+		apiErr.RequestID = body.RequestID
+		apiErr.Details = body.Details
+	} else {
+		apiErr.Code = http.StatusText(resp.StatusCode)
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// values codifica las opciones como query parameters
+func (o ListOptions) values() url.Values {
+	query := url.Values{}
+	if o.Quote != "" {
+		query.Set("quote", o.Quote)
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return query
+}
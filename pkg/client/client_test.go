@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithRetry(2, time.Millisecond, 5*time.Millisecond)}, opts...)
+	c, err := New(server.URL, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew_RejectsInvalidBaseURL(t *testing.T) {
+	_, err := New("localhost:8080")
+	assert.Error(t, err)
+}
+
+func TestGetLTP_QueryAndAuth(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/ltp", r.URL.Path)
+		assert.Equal(t, "BTC/USD,ETH/USD", r.URL.Query().Get("pair"))
+		assert.Equal(t, "USD", r.URL.Query().Get("quote"))
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+		assert.Equal(t, "secret", r.Header.Get("X-Service-Key"))
+
+		w.WriteHeader(http.StatusPartialContent)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"ltp":    []map[string]any{{"pair": "BTC/USD", "amount": 50000.5}},
+			"errors": []map[string]any{{"pair": "ETH/USD", "error": "Failed to fetch price", "code": "PRICE_FETCH_ERROR"}},
+		})
+	}, WithAPIKey("secret"), WithAPIKeyHeader("X-Service-Key"))
+
+	resp, err := c.GetLTPWithOptions(context.Background(), ListOptions{Quote: "USD", Limit: 10}, "BTC/USD", "ETH/USD")
+	require.NoError(t, err, "partial responses are not errors")
+	assert.True(t, resp.Partial())
+
+	price, ok := resp.Price("BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, 50000.5, price.Amount)
+}
+
+func TestGetLTPBulk_SendsBody(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"BTC/USD", "BTC/EUR"}, body["pairs"])
+		_, _ = w.Write([]byte(`{"ltp":[]}`))
+	})
+
+	resp, err := c.GetLTPBulk(context.Background(), []string{"BTC/USD", "BTC/EUR"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.LTP)
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"RATE_LIMIT_EXCEEDED","code":429}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ltp":[{"pair":"BTC/USD","amount":1}]}`))
+	})
+
+	resp, err := c.GetLTP(context.Background(), "BTC/USD")
+	require.NoError(t, err)
+	assert.Len(t, resp.LTP, 1)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_ReturnsAPIErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"UNSUPPORTED_PAIR","message":"unsupported pair: DOGE/MOON","request_id":"req_1","details":{"pair":"DOGE/MOON"},"error":"UNSUPPORTED_PAIR"}`))
+	})
+
+	_, err := c.GetLTP(context.Background(), "DOGE/MOON")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "UNSUPPORTED_PAIR", apiErr.Code)
+	assert.Contains(t, apiErr.Message, "DOGE/MOON")
+	assert.Equal(t, "req_1", apiErr.RequestID)
+	assert.Equal(t, "DOGE/MOON", apiErr.Details["pair"])
+	assert.Equal(t, int32(1), calls.Load(), "client errors are not retried")
+}
+
+func TestClient_AllPricesFailed(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"ltp":[],"errors":[{"pair":"BTC/USD","error":"Failed to fetch price","message":"kraken down"}]}`))
+	}, WithRetry(0, 0, 0))
+
+	resp, err := c.GetLTP(context.Background(), "BTC/USD")
+	assert.True(t, errors.Is(err, ErrAllPricesFailed))
+	require.NotNil(t, resp)
+	assert.Len(t, resp.Errors, 1)
+}
+
+func TestRefreshPrices_NotRetried(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := c.RefreshPrices(context.Background(), "BTC/USD")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
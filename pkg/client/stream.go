@@ -0,0 +1,340 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultStreamBuffer es la capacidad por defecto del channel de PriceStream.Updates
+	DefaultStreamBuffer = 256
+	// DefaultStreamReadTimeout es el máximo sin recibir mensajes ni pings del
+	// servicio (que los envía cada 15s) antes de reconectar
+	DefaultStreamReadTimeout = 45 * time.Second
+	// streamHandshakeTimeout acota el upgrade de cada conexión
+	streamHandshakeTimeout = 10 * time.Second
+	// streamWriteTimeout acota los comandos y pongs enviados al servicio
+	streamWriteTimeout = 5 * time.Second
+)
+
+// Backpressure decide qué hacer cuando el consumidor no alcanza a leer el channel
+type Backpressure int
+
+const (
+	// BackpressureDropOldest descarta la actualización más vieja pendiente para
+	// encolar la nueva; la lectura del WebSocket nunca se frena (ver PriceStream.Dropped)
+	BackpressureDropOldest Backpressure = iota
+	// BackpressureBlock frena la lectura hasta que haya lugar. Si el consumidor
+	// se atrasa demasiado el servicio cierra la conexión por cliente lento y el
+	// stream reconecta con un snapshot nuevo.
+	BackpressureBlock
+)
+
+// StreamOptions configura StreamPrices; el valor cero se suscribe a todos los pares
+type StreamOptions struct {
+	Pairs        []string      // Pares a suscribir; vacío = todos los soportados
+	Raw          bool          // Importes sin las reglas de redondeo por moneda (?raw=true)
+	Buffer       int           // Capacidad del channel; 0 usa DefaultStreamBuffer
+	Backpressure Backpressure  // Política con el channel lleno
+	ReadTimeout  time.Duration // 0 usa DefaultStreamReadTimeout
+	// OnError recibe, si no es nil, los errores que no terminan el stream: fallas
+	// de conexión antes de reconectar y los mensajes "error" del servicio. Se
+	// llama desde la goroutine del stream y no debe bloquear.
+	OnError func(error)
+}
+
+// ErrStreamClosed es el resultado de PriceStream.Err tras Close
+var ErrStreamClosed = errors.New("price stream closed")
+
+// PriceStream mantiene la conexión a /api/v1/ws: reconecta con backoff, se vuelve
+// a suscribir a los mismos pares, pide un resync ante un hueco en seq y publica
+// los precios en Updates. Es seguro para uso concurrente.
+type PriceStream struct {
+	client  *Client
+	opts    StreamOptions
+	updates chan PriceUpdate
+	dropped atomic.Uint64
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+	err  error
+}
+
+// StreamPrices abre el feed de /api/v1/ws y retorna cuando la primera conexión
+// se establece, o con el error si el servicio la rechaza. Después las caídas se
+// reconectan solas hasta que ctx termina, se llama a Close o el servicio rechaza
+// la reconexión con un error no reintentable (p. ej. 401); entonces Updates se
+// cierra y Err informa el motivo.
+func (c *Client) StreamPrices(ctx context.Context, opts StreamOptions) (*PriceStream, error) {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultStreamBuffer
+	}
+	if opts.ReadTimeout <= 0 {
+		opts.ReadTimeout = DefaultStreamReadTimeout
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &PriceStream{
+		client:  c,
+		opts:    opts,
+		updates: make(chan PriceUpdate, opts.Buffer),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	conn, err := s.dial(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go s.run(ctx, conn)
+	return s, nil
+}
+
+// Updates retorna el channel de precios; se cierra cuando el stream termina
+func (s *PriceStream) Updates() <-chan PriceUpdate {
+	return s.updates
+}
+
+// Dropped retorna cuántas actualizaciones se descartaron con BackpressureDropOldest
+func (s *PriceStream) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Err retorna el motivo por el que terminó el stream, o nil si sigue abierto
+func (s *PriceStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close cierra la conexión y espera a que termine el stream
+func (s *PriceStream) Close() error {
+	s.finish(ErrStreamClosed)
+	s.cancel()
+	s.mu.Lock()
+	if s.conn != nil {
+		_ = s.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(streamWriteTimeout))
+		_ = s.conn.Close()
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+// finish registra err como motivo de fin si todavía no hay uno
+func (s *PriceStream) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// run lee la conexión y reconecta hasta que el stream termina
+func (s *PriceStream) run(ctx context.Context, conn *websocket.Conn) {
+	defer close(s.done)
+	defer close(s.updates)
+
+	var lastErr error
+	for attempt := 0; ; {
+		if conn != nil {
+			err := s.read(ctx, conn)
+			if ctx.Err() != nil {
+				break
+			}
+			s.report(fmt.Errorf("price stream disconnected: %w", err))
+			attempt, lastErr = 0, nil
+		}
+
+		attempt++
+		if err := sleep(ctx, s.client.retryDelay(attempt, lastErr)); err != nil {
+			break
+		}
+		var err error
+		if conn, err = s.dial(ctx); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if !retryableStreamError(err) {
+				s.finish(err)
+				break
+			}
+			s.report(err)
+			lastErr = err
+		}
+	}
+	s.finish(ctx.Err())
+}
+
+// dial abre una conexión suscripta a los pares del stream
+func (s *PriceStream) dial(ctx context.Context) (*websocket.Conn, error) {
+	endpoint := s.client.baseURL.JoinPath("/api/v1/ws")
+	endpoint.Scheme = strings.Replace(endpoint.Scheme, "http", "ws", 1)
+	query := endpoint.Query()
+	if len(s.opts.Pairs) > 0 {
+		query.Set("pair", strings.Join(s.opts.Pairs, ","))
+	}
+	if s.opts.Raw {
+		query.Set("raw", "true")
+	}
+	endpoint.RawQuery = query.Encode()
+
+	header := http.Header{}
+	if s.client.userAgent != "" {
+		header.Set("User-Agent", s.client.userAgent)
+	}
+	if s.client.apiKey != "" {
+		header.Set(s.client.apiKeyHeader, s.client.apiKey)
+	}
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: streamHandshakeTimeout}
+	conn, resp, err := dialer.DialContext(ctx, endpoint.String(), header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			return nil, decodeAPIError(resp)
+		}
+		return nil, fmt.Errorf("GET /api/v1/ws: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		_ = conn.Close() // Close ganó la carrera con el dial
+		return nil, s.err
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// read procesa los mensajes de conn hasta que se cierra o deja de responder
+func (s *PriceStream) read(ctx context.Context, conn *websocket.Conn) error {
+	defer conn.Close()
+
+	extend := func() error { return conn.SetReadDeadline(time.Now().Add(s.opts.ReadTimeout)) }
+	_ = extend()
+	conn.SetPingHandler(func(data string) error {
+		_ = extend()
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(streamWriteTimeout))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+
+	// lastSeq es el último seq aplicado; resyncing descarta diffs hasta el snapshot pedido
+	var lastSeq uint64
+	resyncing := false
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = extend()
+
+		var message streamMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			s.report(fmt.Errorf("invalid price stream message: %w", err))
+			continue
+		}
+
+		switch message.Type {
+		case streamMessageSnapshot:
+			lastSeq, resyncing = message.Seq, false
+		case streamMessageDiff:
+			if resyncing {
+				continue
+			}
+			if message.Seq != lastSeq+1 {
+				// Se perdió un mensaje: los diffs siguientes no alcanzan para reconstruir el estado
+				resyncing = true
+				if err := s.resync(conn); err != nil {
+					return err
+				}
+				continue
+			}
+			lastSeq = message.Seq
+		case streamMessageError:
+			s.report(&StreamError{Code: message.Code, Message: message.Message})
+			continue
+		default:
+			continue
+		}
+
+		for _, price := range message.Prices {
+			update := PriceUpdate{Price: price, Seq: message.Seq, Snapshot: message.Type == streamMessageSnapshot}
+			if !s.deliver(ctx, update) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// resync pide un snapshot nuevo al servicio
+func (s *PriceStream) resync(conn *websocket.Conn) error {
+	data, err := json.Marshal(map[string]string{"type": streamCommandResync})
+	if err != nil {
+		return err
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// deliver encola update según la política de backpressure; retorna false si el
+// stream terminó mientras esperaba lugar
+func (s *PriceStream) deliver(ctx context.Context, update PriceUpdate) bool {
+	for {
+		select {
+		case s.updates <- update:
+			return true
+		default:
+		}
+
+		if s.opts.Backpressure == BackpressureBlock {
+			select {
+			case s.updates <- update:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// run es el único productor: tras descartar la más vieja hay lugar salvo
+		// que el consumidor también haya leído, y en ese caso se reintenta
+		select {
+		case <-s.updates:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// report entrega a OnError un error que no termina el stream
+func (s *PriceStream) report(err error) {
+	if s.opts.OnError != nil {
+		s.opts.OnError(err)
+	}
+}
+
+// retryableStreamError indica si vale la pena reconectar tras err: errores de red
+// y las respuestas que do() también reintenta
+func retryableStreamError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.StatusCode)
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStreamServer atiende /api/v1/ws llamando a serve con cada conexión
+func newStreamServer(t *testing.T, serve func(conn *websocket.Conn, r *http.Request, n int)) *Client {
+	t.Helper()
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(DefaultAPIKeyHeader) != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"UNAUTHORIZED","code":"UNAUTHORIZED","message":"invalid API key"}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		serve(conn, r, int(connections.Add(1)))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithAPIKey("secret"), WithRetry(0, time.Millisecond, 5*time.Millisecond))
+	require.NoError(t, err)
+	return c
+}
+
+func writeStreamMessage(t *testing.T, conn *websocket.Conn, kind string, seq uint64, prices ...float64) {
+	t.Helper()
+	message := map[string]any{"type": kind, "seq": seq}
+	var list []map[string]any
+	for _, amount := range prices {
+		list = append(list, map[string]any{"pair": "BTC/USD", "amount": amount})
+	}
+	message["prices"] = list
+	require.NoError(t, conn.WriteJSON(message))
+}
+
+func nextUpdate(t *testing.T, stream *PriceStream) PriceUpdate {
+	t.Helper()
+	select {
+	case update, ok := <-stream.Updates():
+		require.True(t, ok, "stream closed early: %v", stream.Err())
+		return update
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "no price update received")
+		return PriceUpdate{}
+	}
+}
+
+func TestStreamPrices_ResyncsOnSeqGap(t *testing.T) {
+	c := newStreamServer(t, func(conn *websocket.Conn, r *http.Request, _ int) {
+		assert.Equal(t, "BTC/USD", r.URL.Query().Get("pair"))
+		writeStreamMessage(t, conn, streamMessageSnapshot, 1, 50000)
+		writeStreamMessage(t, conn, streamMessageDiff, 3, 50100) // seq 2 se perdió
+
+		var command map[string]string
+		require.NoError(t, conn.ReadJSON(&command))
+		assert.Equal(t, streamCommandResync, command["type"])
+		writeStreamMessage(t, conn, streamMessageDiff, 4, 50150) // encolado antes del resync
+		writeStreamMessage(t, conn, streamMessageSnapshot, 5, 50200)
+		writeStreamMessage(t, conn, streamMessageDiff, 6, 50300)
+		_, _, _ = conn.ReadMessage()
+	})
+
+	stream, err := c.StreamPrices(context.Background(), StreamOptions{Pairs: []string{"BTC/USD"}})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	assert.Equal(t, PriceUpdate{Price: Price{Pair: "BTC/USD", Amount: 50000}, Snapshot: true, Seq: 1}, nextUpdate(t, stream))
+	update := nextUpdate(t, stream)
+	assert.Equal(t, 50200.0, update.Amount, "diffs after a gap are skipped until the resync snapshot")
+	assert.True(t, update.Snapshot)
+	assert.Equal(t, PriceUpdate{Price: Price{Pair: "BTC/USD", Amount: 50300}, Seq: 6}, nextUpdate(t, stream))
+}
+
+func TestStreamPrices_ResubscribesAfterReconnect(t *testing.T) {
+	c := newStreamServer(t, func(conn *websocket.Conn, r *http.Request, n int) {
+		assert.Equal(t, "BTC/USD,ETH/USD", r.URL.Query().Get("pair"), "every connection subscribes to the same pairs")
+		assert.Equal(t, "true", r.URL.Query().Get("raw"))
+		writeStreamMessage(t, conn, streamMessageSnapshot, 1, float64(n))
+		if n == 1 {
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "connection expired, reconnect"))
+			return
+		}
+		_, _, _ = conn.ReadMessage()
+	})
+
+	var disconnects atomic.Int32
+	stream, err := c.StreamPrices(context.Background(), StreamOptions{
+		Pairs:   []string{"BTC/USD", "ETH/USD"},
+		Raw:     true,
+		OnError: func(error) { disconnects.Add(1) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, nextUpdate(t, stream).Amount)
+	update := nextUpdate(t, stream)
+	assert.Equal(t, 2.0, update.Amount, "the second connection starts with its own snapshot")
+	assert.True(t, update.Snapshot)
+	assert.Equal(t, int32(1), disconnects.Load())
+
+	require.NoError(t, stream.Close())
+	_, ok := <-stream.Updates()
+	assert.False(t, ok, "Close closes the updates channel")
+	assert.ErrorIs(t, stream.Err(), ErrStreamClosed)
+}
+
+func TestStreamPrices_DropsOldestWhenConsumerIsSlow(t *testing.T) {
+	sent := make(chan struct{})
+	c := newStreamServer(t, func(conn *websocket.Conn, _ *http.Request, _ int) {
+		writeStreamMessage(t, conn, streamMessageSnapshot, 1, 1)
+		for seq := uint64(2); seq <= 5; seq++ {
+			writeStreamMessage(t, conn, streamMessageDiff, seq, float64(seq))
+		}
+		close(sent)
+		_, _, _ = conn.ReadMessage()
+	})
+
+	stream, err := c.StreamPrices(context.Background(), StreamOptions{Buffer: 2})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	<-sent
+	assert.Eventually(t, func() bool { return stream.Dropped() == 3 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, 4.0, nextUpdate(t, stream).Amount, "the newest updates are kept")
+	assert.Equal(t, 5.0, nextUpdate(t, stream).Amount)
+}
+
+func TestStreamPrices_RejectedHandshake(t *testing.T) {
+	c := newStreamServer(t, func(*websocket.Conn, *http.Request, int) {})
+	c.apiKey = "wrong"
+
+	_, err := c.StreamPrices(context.Background(), StreamOptions{})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr), "handshake errors are decoded: %v", err)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "UNAUTHORIZED", apiErr.Code)
+	assert.False(t, retryableStreamError(err))
+}
+
+func TestStreamPrices_ReportsServerErrors(t *testing.T) {
+	c := newStreamServer(t, func(conn *websocket.Conn, _ *http.Request, _ int) {
+		require.NoError(t, conn.WriteJSON(map[string]string{"type": streamMessageError, "code": "CACHE_ERROR", "message": "failed to get cached prices, retry the resync"}))
+		writeStreamMessage(t, conn, streamMessageSnapshot, 1, 50000)
+		_, _, _ = conn.ReadMessage()
+	})
+
+	errs := make(chan error, 1)
+	stream, err := c.StreamPrices(context.Background(), StreamOptions{OnError: func(err error) { errs <- err }})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	assert.Equal(t, 50000.0, nextUpdate(t, stream).Amount)
+	var streamErr *StreamError
+	require.True(t, errors.As(<-errs, &streamErr))
+	assert.Equal(t, "CACHE_ERROR", streamErr.Code)
+}
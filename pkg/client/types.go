@@ -0,0 +1,167 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Price es el último precio negociado de un par
+type Price struct {
+	Pair         string         `json:"pair"`
+	Amount       float64        `json:"amount"`
+	ExchangeTime *time.Time     `json:"exchange_time,omitempty"`
+	ReceivedTime *time.Time     `json:"received_time,omitempty"`
+	Exchange     *PriceExchange `json:"exchange,omitempty"`
+}
+
+// PriceExchange indica de qué exchange y feed vino un precio; Bid y Ask son 0 si
+// el exchange no los informó
+type PriceExchange struct {
+	Name    string  `json:"name"`
+	Source  string  `json:"source,omitempty"`
+	Channel string  `json:"channel,omitempty"`
+	Bid     float64 `json:"bid,omitempty"`
+	Ask     float64 `json:"ask,omitempty"`
+}
+
+// PriceError describe un par que no pudo obtenerse en una respuesta parcial
+type PriceError struct {
+	Pair    string `json:"pair"`
+	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Pagination describe la página devuelta cuando se usan quote, limit u offset
+type Pagination struct {
+	Total      int    `json:"total"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit,omitempty"`
+	NextOffset *int   `json:"next_offset,omitempty"`
+	Quote      string `json:"quote,omitempty"`
+}
+
+// LTPResponse es la respuesta de /api/v1/ltp y /api/v1/ltp/cached
+type LTPResponse struct {
+	LTP        []Price      `json:"ltp"`
+	Errors     []PriceError `json:"errors,omitempty"`
+	Pagination *Pagination  `json:"pagination,omitempty"`
+}
+
+// Partial indica que algunos pares fallaron (HTTP 206)
+func (r *LTPResponse) Partial() bool {
+	return len(r.Errors) > 0
+}
+
+// Price retorna el precio de pair si está en la respuesta
+func (r *LTPResponse) Price(pair string) (Price, bool) {
+	for _, price := range r.LTP {
+		if price.Pair == pair {
+			return price, true
+		}
+	}
+	return Price{}, false
+}
+
+// PriceUpdate es un precio recibido por PriceStream
+type PriceUpdate struct {
+	Price
+	// Snapshot indica que viene del snapshot enviado al conectar, reconectar o
+	// tras un resync; si no, de un diff con los pares que cambiaron
+	Snapshot bool
+	Seq      uint64 // seq del mensaje en la conexión actual
+}
+
+// StreamError es un mensaje "error" del feed de /api/v1/ws (p. ej. un resync que
+// no pudo leer la caché); el stream sigue abierto
+type StreamError struct {
+	Code    string
+	Message string
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("btc-ltp-service stream: %s: %s", e.Code, e.Message)
+}
+
+// Tipos de mensaje del protocolo de /api/v1/ws
+const (
+	streamMessageSnapshot = "snapshot"
+	streamMessageDiff     = "diff"
+	streamMessageError    = "error"
+	streamCommandResync   = "resync"
+)
+
+// streamMessage es un mensaje del servicio por /api/v1/ws
+type streamMessage struct {
+	Type    string  `json:"type"`
+	Seq     uint64  `json:"seq"`
+	Prices  []Price `json:"prices"`
+	Code    string  `json:"code"`
+	Message string  `json:"message"`
+}
+
+// RefreshResponse es la respuesta de POST /api/v1/ltp/refresh
+type RefreshResponse struct {
+	Message string   `json:"message"`
+	Pairs   []string `json:"pairs"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ListOptions filtra y pagina las consultas multi-par; el valor cero no filtra
+type ListOptions struct {
+	Quote  string
+	Limit  int
+	Offset int
+}
+
+// ErrAllPricesFailed se retorna (envuelto en *APIError) cuando el servicio
+// responde 503 porque ningún par pudo obtenerse
+var ErrAllPricesFailed = errors.New("all price requests failed")
+
+// APIError es una respuesta no exitosa del servicio
+type APIError struct {
+	StatusCode int
+	Code       string // Código de error del servicio (ej: UNSUPPORTED_PAIR)
+	Message    string
+	RequestID  string         // ID del request en el servicio, para correlacionar con sus logs
+	Details    map[string]any // Contexto adicional del error (ej: retry_after_seconds)
+	RetryAfter time.Duration  // Sugerido por el servicio en respuestas 429
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("btc-ltp-service: HTTP %d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("btc-ltp-service: HTTP %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Unwrap permite errors.Is(err, ErrAllPricesFailed) en respuestas 503 de /ltp
+func (e *APIError) Unwrap() error {
+	if e.Code == "ALL_PRICES_FAILED" {
+		return ErrAllPricesFailed
+	}
+	return nil
+}
+
+// errorBody es el cuerpo de error estándar del servicio. Versiones anteriores
+// enviaban "code" numérico en algunos endpoints, así que se decodifica sin tipo
+// y "error" queda como fallback.
+type errorBody struct {
+	Code      json.RawMessage `json:"code"`
+	Error     string          `json:"error"`
+	Message   string          `json:"message"`
+	Detail    string          `json:"detail"` // application/problem+json
+	RequestID string          `json:"request_id"`
+	Details   map[string]any  `json:"details"`
+}
+
+// code devuelve el código de error textual del cuerpo, o "" si no trae ninguno
+func (b errorBody) code() string {
+	var code string
+	if err := json.Unmarshal(b.Code, &code); err == nil && code != "" {
+		return code
+	}
+	return b.Error
+}
@@ -31,7 +31,7 @@ run_test() {
     fi
     
     # Ejecutar el servicio (solo validación)
-    if timeout 5s go run cmd/api/main.go 2>&1 | grep -q "validation failed"; then
+    if timeout 5s go run ./cmd/api 2>&1 | grep -q "validation failed"; then
         if [ "$expected_result" = "FAIL" ]; then
             echo -e "${RED}✅ ¡CORRECTO! Validación falló como se esperaba${NC}"
         else
@@ -106,7 +106,7 @@ echo "- LOG_LEVEL=$LOG_LEVEL (override debug→info)"
 echo "- CACHE_TTL=$CACHE_TTL (override 60s→45s)"
 
 # Intentar ejecutar con timeout para ver la configuración cargada
-timeout 3s go run cmd/api/main.go 2>&1 | head -20 || true
+timeout 3s go run ./cmd/api 2>&1 | head -20 || true
 
 echo ""
 echo "=" | head -c 50; echo ""
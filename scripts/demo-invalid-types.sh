@@ -27,11 +27,11 @@ test_env_case() {
     local expected="$3"
     
     echo -e "${YELLOW}🧪 Test ENV: $test_name${NC}"
-    echo "Command: $env_var go run cmd/api/main.go"
+    echo "Command: $env_var go run ./cmd/api"
     echo ""
     
     # Ejecutar y capturar resultado
-    if eval "$env_var timeout 3s go run cmd/api/main.go 2>&1" | grep -q "$expected"; then
+    if eval "$env_var timeout 3s go run ./cmd/api 2>&1" | grep -q "$expected"; then
         echo -e "${RED}✅ ¡CORRECTO! Error detectado: $expected${NC}"
     else
         echo -e "${GREEN}❌ ERROR: No se detectó el error esperado${NC}"
@@ -51,7 +51,7 @@ test_yaml_case() {
     echo ""
     
     # Ejecutar y capturar resultado
-    if timeout 3s go run cmd/api/main.go -config "$config_file" 2>&1 | grep -q "$expected"; then
+    if timeout 3s go run ./cmd/api -config "$config_file" 2>&1 | grep -q "$expected"; then
         echo -e "${RED}✅ ¡CORRECTO! Error detectado: $expected${NC}"
     else
         echo -e "${GREEN}❌ INFO: Error no detectado (usando defaults)${NC}"
@@ -124,11 +124,11 @@ echo ""
 echo -e "${BLUE}🔍 EJEMPLO PRÁCTICO:${NC}"
 echo ""
 echo "Para demostrar parsing error:"
-echo -e "${YELLOW}$ CACHE_TTL='abc' go run cmd/api/main.go${NC}"
+echo -e "${YELLOW}$ CACHE_TTL='abc' go run ./cmd/api${NC}"
 echo "Result: time: invalid duration"
 echo ""
 echo "Para demostrar detección de valores zero:"
-echo -e "${YELLOW}$ go run cmd/api/main.go -config configs/config.test-zero-values.yaml${NC}"  
+echo -e "${YELLOW}$ go run ./cmd/api -config configs/config.test-zero-values.yaml${NC}"  
 echo "Result: cache TTL parsed as 0, likely due to invalid duration format"
 echo ""
 
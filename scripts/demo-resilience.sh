@@ -156,7 +156,7 @@ run_demo() {
         echo "   export KRAKEN_WEBSOCKET_URL=wss://invalid-demo-url.com"
         echo
         echo "2. Iniciar el servicio:"
-        echo "   go run cmd/api/main.go"
+        echo "   go run ./cmd/api"
         echo
         echo "3. Ejecutar este demo en otra terminal:"
         echo "   ./scripts/demo-resilience.sh"